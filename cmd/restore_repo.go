@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/private"
+
+	archiver "github.com/mholt/archiver/v3"
+	"github.com/urfave/cli"
+)
+
+// CmdRestoreRepository represents the available restore a repository sub-command.
+var CmdRestoreRepository = cli.Command{
+	Name:        "restore-repo",
+	Usage:       "Restore the repository from disk",
+	Description: "This is a command for restoring the repository data written by dump-repo into the running gitea process.",
+	Action:      runRestoreRepository,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "repo_dir, r",
+			Value: "./data",
+			Usage: "Repository dir path or zip archive to restore from",
+		},
+		cli.StringFlag{
+			Name:  "owner_name",
+			Value: "",
+			Usage: "Restore destination owner name",
+		},
+		cli.StringFlag{
+			Name:  "repo_name",
+			Value: "",
+			Usage: "Restore destination repository name",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Value: time.Hour,
+			Usage: "Timeout for the restoring process",
+		},
+		cli.BoolFlag{
+			Name: "debug",
+		},
+	},
+}
+
+func runRestoreRepository(ctx *cli.Context) error {
+	setup("restore-repo", ctx.Bool("debug"))
+
+	if ctx.String("owner_name") == "" || ctx.String("repo_name") == "" {
+		return fmt.Errorf("owner_name and repo_name must be given")
+	}
+
+	repoDir, err := filepath.Abs(ctx.String("repo_dir"))
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(repoDir, ".zip") {
+		tmpDir, err := ioutil.TempDir(os.TempDir(), "gitea-restore-repo")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		if err := archiver.Unarchive(repoDir, tmpDir); err != nil {
+			return fmt.Errorf("Unable to extract %s: %v", repoDir, err)
+		}
+		repoDir = tmpDir
+	}
+
+	statusCode, msg := private.RestoreRepo(
+		repoDir,
+		ctx.String("owner_name"),
+		ctx.String("repo_name"),
+		ctx.Duration("timeout"),
+	)
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("Unable to restore repository: %s", msg)
+	}
+
+	fmt.Fprintln(os.Stdout, msg)
+	return nil
+}
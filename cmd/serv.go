@@ -99,19 +99,27 @@ func runServ(c *cli.Context) error {
 		return nil
 	}
 
+	// users authenticated by an SSH certificate are passed as user-<id>
+	var keyID, userID int64
 	keys := strings.Split(c.Args()[0], "-")
-	if len(keys) != 2 || keys[0] != "key" {
+	if len(keys) == 2 && keys[0] == "key" {
+		keyID = com.StrTo(keys[1]).MustInt64()
+	} else if len(keys) == 2 && keys[0] == "user" {
+		userID = com.StrTo(keys[1]).MustInt64()
+	}
+	if keyID <= 0 && userID <= 0 {
 		fail("Key ID format error", "Invalid key argument: %s", c.Args()[0])
 	}
-	keyID := com.StrTo(keys[1]).MustInt64()
 
 	cmd := os.Getenv("SSH_ORIGINAL_COMMAND")
 	if len(cmd) == 0 {
-		key, user, err := private.ServNoCommand(keyID)
+		key, user, err := private.ServNoCommand(keyID, userID)
 		if err != nil {
 			fail("Internal error", "Failed to check provided key: %v", err)
 		}
-		if key.Type == models.KeyTypeDeploy {
+		if key.ID == 0 {
+			println("Hi there, " + user.Name + "! You've successfully authenticated with an SSH certificate, but Gitea does not provide shell access.")
+		} else if key.Type == models.KeyTypeDeploy {
 			println("Hi there! You've successfully authenticated with the deploy key named " + key.Name + ", but Gitea does not provide shell access.")
 		} else {
 			println("Hi there, " + user.Name + "! You've successfully authenticated with the key named " + key.Name + ", but Gitea does not provide shell access.")
@@ -196,7 +204,7 @@ func runServ(c *cli.Context) error {
 		}
 	}
 
-	results, err := private.ServCommand(keyID, username, reponame, requestedMode, verb, lfsVerb)
+	results, err := private.ServCommand(keyID, userID, username, reponame, requestedMode, verb, lfsVerb)
 	if err != nil {
 		if private.IsErrServCommand(err) {
 			errServCommand := err.(private.ErrServCommand)
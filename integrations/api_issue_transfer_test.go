@@ -0,0 +1,89 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITransferIssue(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	// the target repository must exist
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/transfer?token="+token, &api.TransferIssueOption{
+		NewOwner: "user3",
+		NewRepo:  "doesnotexist",
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// pull requests can not be transferred
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/2/transfer?token="+token, &api.TransferIssueOption{
+		NewOwner: "user3",
+		NewRepo:  "repo3",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// user5 watches the public repository but can not read the private target repository
+	assert.NoError(t, models.WatchRepo(5, 1, true))
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/transfer?token="+token, &api.TransferIssueOption{
+		NewOwner: "user3",
+		NewRepo:  "repo3",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 1, apiIssue.ID)
+	assert.EqualValues(t, 2, apiIssue.Index)
+	assert.EqualValues(t, "repo3", apiIssue.Repo.Name)
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1, RepoID: 3, Index: 2})
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, Type: models.CommentTypeIssueTransferOut})
+	models.AssertExistsAndLoadBean(t, &models.Comment{IssueID: 1, Type: models.CommentTypeIssueTransfer})
+	models.AssertExistsAndLoadBean(t, &models.Action{RepoID: 1, OpType: models.ActionTransferIssue})
+	models.AssertExistsAndLoadBean(t, &models.Action{RepoID: 3, OpType: models.ActionTransferIssue})
+
+	// the old index redirects to the transferred issue
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/1?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Header().Get("Location"), "/api/v1/repos/user3/repo3/issues/2")
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Contains(t, resp.Header().Get("Location"), "/user3/repo3/issues/2")
+
+	req = NewRequest(t, "GET", "/user3/repo3/issues/2")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "transferred this issue to <b>user3/repo3#2</b>")
+	assert.Contains(t, resp.Body.String(), "transferred this issue from <b>user2/repo1#1</b>")
+
+	req = NewRequest(t, "GET", "/")
+	resp = loginUser(t, "user5").MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "to a private repository")
+	assert.NotContains(t, resp.Body.String(), "user3/repo3")
+
+	// users who can not read the private target repository are not redirected
+	req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/issues/1")
+	MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/user2/repo1/issues/1")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// a user who can read but not write the target repository can not move issues there
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo3/issues/2/transfer?token="+token, &api.TransferIssueOption{
+		NewOwner: "user2",
+		NewRepo:  "repo1",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func containerDigest(content []byte) string {
+	h := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+func TestPackageContainer(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	image := "test-image"
+	tag := "v1.0"
+	url := fmt.Sprintf("/v2/%s/%s", user.Name, image)
+
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	configDigest := containerDigest(config)
+	layer := []byte("layer content split into chunks")
+	layerDigest := containerDigest(layer)
+	manifest := []byte(fmt.Sprintf(`{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{"mediaType":"application/vnd.oci.image.config.v1+json","digest":"%s","size":%d},"layers":[{"mediaType":"application/vnd.oci.image.layer.v1.tar","digest":"%s","size":%d}]}`, configDigest, len(config), layerDigest, len(layer)))
+	manifestDigest := containerDigest(manifest)
+
+	var token string
+
+	t.Run("Authenticate", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/v2/")
+		resp := MakeRequest(t, req, http.StatusUnauthorized)
+		assert.Equal(t, `Bearer realm="`+setting.AppURL+`v2/token",service="container_registry",scope="*"`, resp.Header().Get("WWW-Authenticate"))
+		assert.Equal(t, "registry/2.0", resp.Header().Get("Docker-Distribution-Api-Version"))
+
+		req = NewRequest(t, "GET", "/v2/token")
+		AddBasicAuthHeader(req, user.Name)
+		resp = MakeRequest(t, req, http.StatusOK)
+		tokenResponse := struct {
+			Token string `json:"token"`
+		}{}
+		DecodeJSON(t, resp, &tokenResponse)
+		assert.NotEmpty(t, tokenResponse.Token)
+		token = "Bearer " + tokenResponse.Token
+
+		req = NewRequest(t, "GET", "/v2/")
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusOK)
+
+		// Anonymous clients get a token for public images
+		req = NewRequest(t, "GET", "/v2/token")
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &tokenResponse)
+		req = NewRequest(t, "GET", "/v2/")
+		req.Header.Set("Authorization", "Bearer "+tokenResponse.Token)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "POST", url+"/blobs/uploads")
+		req.Header.Set("Authorization", "Bearer "+tokenResponse.Token)
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequest(t, "GET", "/v2/token")
+		req.SetBasicAuth(user.Name, "wrong password")
+		MakeRequest(t, req, http.StatusUnauthorized)
+	})
+
+	t.Run("UploadBlob", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "POST", url+"/blobs/uploads?digest="+configDigest, bytes.NewReader(config))
+		AddBasicAuthHeader(req, "user4")
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithBody(t, "POST", url+"/blobs/uploads?digest="+containerDigest([]byte("other")), bytes.NewReader(config))
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "POST", url+"/blobs/uploads?digest="+configDigest, bytes.NewReader(config))
+		req.Header.Set("Authorization", token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		assert.Equal(t, url+"/blobs/"+configDigest, resp.Header().Get("Location"))
+		assert.Equal(t, configDigest, resp.Header().Get("Docker-Content-Digest"))
+
+		req = NewRequest(t, "HEAD", url+"/blobs/"+configDigest)
+		req.Header.Set("Authorization", token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, fmt.Sprintf("%d", len(config)), resp.Header().Get("Content-Length"))
+	})
+
+	t.Run("UploadBlobChunked", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "POST", url+"/blobs/uploads/", bytes.NewReader(nil))
+		req.Header.Set("Authorization", token)
+		resp := MakeRequest(t, req, http.StatusAccepted)
+		uuid := resp.Header().Get("Docker-Upload-UUID")
+		assert.NotEmpty(t, uuid)
+		uploadURL := resp.Header().Get("Location")
+		assert.Equal(t, url+"/blobs/uploads/"+uuid, uploadURL)
+
+		req = NewRequestWithBody(t, "PATCH", uploadURL, bytes.NewReader(layer[:10]))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Range", "0-9")
+		resp = MakeRequest(t, req, http.StatusAccepted)
+		assert.Equal(t, "0-9", resp.Header().Get("Range"))
+
+		// The next chunk has to continue the upload
+		req = NewRequestWithBody(t, "PATCH", uploadURL, bytes.NewReader(layer[10:]))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Range", "5-9")
+		MakeRequest(t, req, http.StatusRequestedRangeNotSatisfiable)
+
+		req = NewRequestWithBody(t, "PATCH", uploadURL, bytes.NewReader(layer[10:20]))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Range", "10-19")
+		MakeRequest(t, req, http.StatusAccepted)
+
+		req = NewRequest(t, "GET", uploadURL)
+		req.Header.Set("Authorization", token)
+		resp = MakeRequest(t, req, http.StatusNoContent)
+		assert.Equal(t, "0-19", resp.Header().Get("Range"))
+
+		req = NewRequestWithBody(t, "PUT", uploadURL+"?digest="+layerDigest, bytes.NewReader(layer[20:]))
+		req.Header.Set("Authorization", token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		assert.Equal(t, layerDigest, resp.Header().Get("Docker-Content-Digest"))
+
+		req = NewRequest(t, "GET", uploadURL)
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", url+"/blobs/"+layerDigest)
+		req.Header.Set("Authorization", token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, layer, resp.Body.Bytes())
+	})
+
+	t.Run("PushManifest", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		missing := []byte(`{"schemaVersion":2,"config":{"digest":"` + containerDigest([]byte("missing")) + `"}}`)
+		req := NewRequestWithBody(t, "PUT", url+"/manifests/"+tag, bytes.NewReader(missing))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "PUT", url+"/manifests/"+tag, bytes.NewReader(manifest))
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		resp := MakeRequest(t, req, http.StatusCreated)
+		assert.Equal(t, manifestDigest, resp.Header().Get("Docker-Content-Digest"))
+
+		pv, err := models.GetPackageVersionByName(user.ID, models.PackageContainer, image, tag)
+		assert.NoError(t, err)
+		files, err := models.GetPackageFilesByVersionID(pv.ID)
+		assert.NoError(t, err)
+		assert.Len(t, files, 3)
+	})
+
+	t.Run("PullManifest", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		for _, reference := range []string{tag, manifestDigest} {
+			req := NewRequest(t, "HEAD", url+"/manifests/"+reference)
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, manifestDigest, resp.Header().Get("Docker-Content-Digest"))
+
+			req = NewRequest(t, "GET", url+"/manifests/"+reference)
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, "application/vnd.oci.image.manifest.v1+json", resp.Header().Get("Content-Type"))
+			assert.Equal(t, manifest, resp.Body.Bytes())
+		}
+
+		req := NewRequest(t, "GET", url+"/manifests/unknown")
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("TagsList", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "PUT", url+"/manifests/latest", bytes.NewReader(manifest))
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		type tagList struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}
+
+		req = NewRequest(t, "GET", url+"/tags/list")
+		resp := MakeRequest(t, req, http.StatusOK)
+		var list tagList
+		DecodeJSON(t, resp, &list)
+		assert.Equal(t, user.LowerName+"/"+image, list.Name)
+		assert.Equal(t, []string{"latest", tag}, list.Tags)
+
+		req = NewRequest(t, "GET", url+"/tags/list?n=1")
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &list)
+		assert.Equal(t, []string{"latest"}, list.Tags)
+		assert.Equal(t, `<`+url+`/tags/list?n=1&last=latest>; rel="next"`, resp.Header().Get("Link"))
+
+		req = NewRequest(t, "GET", url+"/tags/list?n=1&last=latest")
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &list)
+		assert.Equal(t, []string{tag}, list.Tags)
+	})
+
+	t.Run("Web", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/container/%s/%s", user.Name, image, tag))
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "docker pull")
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/container/%s/%s", user.Name, image, models.ContainerUploadVersion))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", url+"/manifests/latest")
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusAccepted)
+
+		req = NewRequest(t, "GET", url+"/manifests/latest")
+		MakeRequest(t, req, http.StatusNotFound)
+		req = NewRequest(t, "GET", url+"/manifests/"+tag)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "DELETE", url+"/manifests/"+manifestDigest)
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusAccepted)
+
+		req = NewRequest(t, "GET", url+"/manifests/"+tag)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "DELETE", url+"/blobs/"+layerDigest)
+		req.Header.Set("Authorization", token)
+		MakeRequest(t, req, http.StatusAccepted)
+
+		req = NewRequest(t, "HEAD", url+"/blobs/"+layerDigest)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
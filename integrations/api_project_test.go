@@ -0,0 +1,143 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIOrgProject(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	// Create a project of the organization
+	req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/projects?token="+token, &api.CreateProjectOption{
+		Title:     "Release",
+		BoardType: "basic_kanban",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiProject api.Project
+	DecodeJSON(t, resp, &apiProject)
+	assert.Equal(t, "Release", apiProject.Title)
+	assert.Equal(t, "organization", apiProject.Type)
+	assert.Equal(t, api.StateOpen, apiProject.State)
+	if assert.NotNil(t, apiProject.Owner) {
+		assert.Equal(t, "user3", apiProject.Owner.UserName)
+	}
+	projectURL := fmt.Sprintf("/api/v1/projects/%d", apiProject.ID)
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/projects?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiProjects []*api.Project
+	DecodeJSON(t, resp, &apiProjects)
+	if assert.Len(t, apiProjects, 1) {
+		assert.Equal(t, apiProject.ID, apiProjects[0].ID)
+	}
+
+	req = NewRequest(t, "GET", projectURL+"/boards?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiBoards []*api.ProjectBoard
+	DecodeJSON(t, resp, &apiBoards)
+	assert.Len(t, apiBoards, 3)
+
+	req = NewRequestWithJSON(t, "POST", projectURL+"/boards?token="+token, &api.CreateProjectBoardOption{Title: "Blocked"})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiBoard api.ProjectBoard
+	DecodeJSON(t, resp, &apiBoard)
+	assert.Equal(t, "Blocked", apiBoard.Title)
+
+	// Put issues of two repositories on the board
+	req = NewRequestWithJSON(t, "POST", projectURL+"/cards?token="+token, &api.AddProjectCardOption{IssueID: 1, BoardID: apiBoard.ID})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiCard api.ProjectCard
+	DecodeJSON(t, resp, &apiCard)
+	assert.Equal(t, apiBoard.ID, apiCard.BoardID)
+	assert.EqualValues(t, 1, apiCard.Issue.ID)
+
+	req = NewRequestWithJSON(t, "POST", projectURL+"/cards?token="+token, &api.AddProjectCardOption{IssueID: 6})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequestWithJSON(t, "POST", projectURL+"/cards?token="+token, &api.AddProjectCardOption{IssueID: 1, BoardID: 1})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", projectURL+"/cards/6?token="+token, &api.MoveProjectCardOption{BoardID: apiBoard.ID})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiCard)
+	assert.Equal(t, apiBoard.ID, apiCard.BoardID)
+
+	req = NewRequest(t, "GET", projectURL+"/cards?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiCards []*api.ProjectCard
+	DecodeJSON(t, resp, &apiCards)
+	assert.Len(t, apiCards, 2)
+
+	// Anonymous users do not see the issue of the private repository and may not change the board
+	req = NewRequest(t, "GET", projectURL+"/cards")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiCards)
+	if assert.Len(t, apiCards, 1) {
+		assert.EqualValues(t, 1, apiCards[0].Issue.ID)
+	}
+
+	// Members of teams without the projects unit may only read the projects
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequest(t, "GET", projectURL+"?token="+token4)
+	session4.MakeRequest(t, req, http.StatusOK)
+	req = NewRequestWithJSON(t, "POST", projectURL+"/boards?token="+token4, &api.CreateProjectBoardOption{Title: "Other"})
+	session4.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/projects?token="+token4, &api.CreateProjectOption{Title: "Other"})
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", projectURL+"/cards/6?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "DELETE", projectURL+"/cards/6?token="+token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	closed := string(api.StateClosed)
+	req = NewRequestWithJSON(t, "PATCH", projectURL+"?token="+token, &api.EditProjectOption{State: &closed})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiProject)
+	assert.Equal(t, api.StateClosed, apiProject.State)
+
+	req = NewRequest(t, "DELETE", projectURL+"?token="+token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertNotExistsBean(t, &models.Project{ID: apiProject.ID})
+	models.AssertNotExistsBean(t, &models.ProjectIssue{ProjectID: apiProject.ID})
+}
+
+func TestAPIUserProject(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/user/projects?token="+token, &api.CreateProjectOption{Title: "Personal"})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiProject api.Project
+	DecodeJSON(t, resp, &apiProject)
+	assert.Equal(t, "individual", apiProject.Type)
+
+	req = NewRequest(t, "GET", "/api/v1/users/user4/projects")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var apiProjects []*api.Project
+	DecodeJSON(t, resp, &apiProjects)
+	assert.Len(t, apiProjects, 1)
+
+	// Other users may read but not change the project
+	session2 := loginUser(t, "user5")
+	token2 := getTokenForLoggedInUser(t, session2)
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/projects/%d?token=%s", apiProject.ID, token2))
+	session2.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -123,3 +123,136 @@ func TestAPIPullReview(t *testing.T) {
 	req = NewRequestf(t, http.MethodDelete, "/api/v1/repos/%s/%s/pulls/%d/reviews/%d?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, review.ID, token)
 	resp = session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPIPullReviewMultiLineComment(t *testing.T) {
+	defer prepareTestEnv(t)()
+	pullIssue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 3}).(*models.Issue)
+	assert.NoError(t, pullIssue.LoadAttributes())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: pullIssue.RepoID}).(*models.Repository)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	link := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/reviews?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token)
+
+	// the range has to end after it starts
+	req := NewRequestWithJSON(t, http.MethodPost, link, &api.CreatePullReviewOptions{
+		Body:  "a range",
+		Event: "COMMENT",
+		Comments: []api.CreatePullReviewComment{{
+			Path:            "iso-8859-1.txt",
+			Body:            "backwards",
+			NewStartLineNum: 5,
+			NewLineNum:      4,
+		}},
+	})
+	resp := session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	assert.Contains(t, resp.Body.String(), "invalid range of commented lines")
+
+	req = NewRequestWithJSON(t, http.MethodPost, link, &api.CreatePullReviewOptions{
+		Body:  "a range",
+		Event: "COMMENT",
+		Comments: []api.CreatePullReviewComment{{
+			Path:            "iso-8859-1.txt",
+			Body:            "these lines",
+			NewStartLineNum: 2,
+			NewLineNum:      4,
+		}},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var review api.PullReview
+	DecodeJSON(t, resp, &review)
+	assert.EqualValues(t, 1, review.CodeCommentsCount)
+
+	comment := models.AssertExistsAndLoadBean(t, &models.Comment{ReviewID: review.ID, Type: models.CommentTypeCode}).(*models.Comment)
+	assert.EqualValues(t, 2, comment.StartLine)
+	assert.EqualValues(t, 4, comment.Line)
+	assert.NoError(t, comment.LoadReview())
+	assert.Len(t, comment.CommentedLines(), 3)
+
+	req = NewRequestf(t, http.MethodGet, "/api/v1/repos/%s/%s/pulls/%d/reviews/%d/comments?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, review.ID, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var reviewComments []*api.PullReviewComment
+	DecodeJSON(t, resp, &reviewComments)
+	assert.Len(t, reviewComments, 1)
+	assert.EqualValues(t, 2, reviewComments[0].StartLineNum)
+	assert.EqualValues(t, 4, reviewComments[0].LineNum)
+
+	// the range is shown in the conversation
+	req = NewRequestf(t, http.MethodGet, "/%s/%s/pulls/%d", repo.OwnerName, repo.Name, pullIssue.Index)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "Lines 2 to 4")
+	assert.Contains(t, resp.Body.String(), "comment-range")
+
+	// a reply keeps the range of the thread
+	req = NewRequestWithValues(t, http.MethodPost, fmt.Sprintf("/%s/%s/pulls/%d/files/reviews/comments", repo.OwnerName, repo.Name, pullIssue.Index), map[string]string{
+		"_csrf":   GetCSRF(t, session, fmt.Sprintf("/%s/%s/pulls/%d", repo.OwnerName, repo.Name, pullIssue.Index)),
+		"content": "a reply",
+		"side":    "proposed",
+		"line":    "4",
+		"path":    "iso-8859-1.txt",
+		"reply":   fmt.Sprint(review.ID),
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	reply := models.AssertExistsAndLoadBean(t, &models.Comment{ReviewID: review.ID, Type: models.CommentTypeCode, Content: "a reply"}).(*models.Comment)
+	assert.EqualValues(t, 2, reply.StartLine)
+	assert.EqualValues(t, 4, reply.Line)
+}
+
+func TestAPIPullReviewRequest(t *testing.T) {
+	defer prepareTestEnv(t)()
+	pullIssue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 2}).(*models.Issue)
+	assert.NoError(t, pullIssue.LoadAttributes())
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: pullIssue.RepoID}).(*models.Repository)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	// Test add Review Request
+	req := NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token), &api.PullReviewRequestOptions{
+		Reviewers: []string{"user4@example.com", "user8"},
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var reviews []*api.PullReview
+	DecodeJSON(t, resp, &reviews)
+	if assert.Len(t, reviews, 2) {
+		assert.EqualValues(t, "user4", reviews[0].Reviewer.UserName)
+		assert.EqualValues(t, api.ReviewStateRequestReview, reviews[0].State)
+	}
+
+	// poster of pr can't be reviewer
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token), &api.PullReviewRequestOptions{
+		Reviewers: []string{"user1"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// a user not exist
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token), &api.PullReviewRequestOptions{
+		Reviewers: []string{"testOther"},
+	})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// teams can only review pull requests of organizations
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token), &api.PullReviewRequestOptions{
+		TeamReviewers: []string{"team1"},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, http.MethodGet, "/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var requests api.PullReviewRequests
+	DecodeJSON(t, resp, &requests)
+	assert.Len(t, requests.Users, 2)
+	assert.Len(t, requests.Teams, 0)
+
+	// Test Remove Review Request
+	req = NewRequestWithJSON(t, http.MethodDelete, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token), &api.PullReviewRequestOptions{
+		Reviewers: []string{"user4@example.com", "user8"},
+	})
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequestf(t, http.MethodGet, "/api/v1/repos/%s/%s/pulls/%d/requested_reviewers?token=%s", repo.OwnerName, repo.Name, pullIssue.Index, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	requests = api.PullReviewRequests{}
+	DecodeJSON(t, resp, &requests)
+	assert.Len(t, requests.Users, 0)
+}
@@ -7,7 +7,9 @@ package integrations
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
@@ -108,3 +110,103 @@ func TestAPIEditPull(t *testing.T) {
 	})
 	session.MakeRequest(t, req, 404)
 }
+
+func TestAPIScheduleAutoMerge(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls/%d?token=%s", owner.Name, repo.Name, pr.Index, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiPull api.PullRequest
+	DecodeJSON(t, resp, &apiPull)
+
+	// a pending commit status blocks the merge
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/statuses/%s?token=%s", owner.Name, repo.Name, apiPull.Head.Sha, token), &api.CreateStatusOption{
+		State:   api.StatusPending,
+		Context: "ci",
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	mergeURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", owner.Name, repo.Name, pr.Index, token)
+	form := &auth.MergePullRequestForm{
+		Do:                     string(models.MergeStyleSquash),
+		MergeWhenChecksSucceed: true,
+	}
+	req = NewRequestWithJSON(t, http.MethodPost, mergeURL, form)
+	session.MakeRequest(t, req, http.StatusAccepted)
+	req = NewRequestWithJSON(t, http.MethodPost, mergeURL, form)
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	scheduled := models.AssertExistsAndLoadBean(t, &models.PullAutoMerge{PullID: pr.ID}).(*models.PullAutoMerge)
+	assert.Equal(t, owner.ID, scheduled.DoerID)
+	assert.Equal(t, models.MergeStyleSquash, scheduled.MergeStyle)
+	models.AssertExistsAndLoadBean(t, &models.Comment{Type: models.CommentTypePRScheduledToAutoMerge, IssueID: pr.IssueID})
+	pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID}).(*models.PullRequest)
+	assert.False(t, pr.HasMerged)
+
+	req = NewRequestf(t, "GET", "/%s/%s/pulls/%d", owner.Name, repo.Name, pr.Index)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	NewHTMLParser(t, resp.Body).AssertElement(t, fmt.Sprintf("form[action=\"/%s/%s/pulls/%d/cancel_auto_merge\"]", owner.Name, repo.Name, pr.Index), true)
+
+	// users without write access can't cancel the merge
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequestf(t, http.MethodDelete, "/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", owner.Name, repo.Name, pr.Index, token4)
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, http.MethodDelete, mergeURL)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, http.MethodDelete, mergeURL)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	models.AssertNotExistsBean(t, &models.PullAutoMerge{PullID: pr.ID})
+	models.AssertExistsAndLoadBean(t, &models.Comment{Type: models.CommentTypePRUnScheduledToAutoMerge, IssueID: pr.IssueID})
+}
+
+func TestAPIAutoMergeWhenChecksSucceed(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+
+		session := loginUser(t, owner.Name)
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls/%d?token=%s", owner.Name, repo.Name, pr.Index, token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var apiPull api.PullRequest
+		DecodeJSON(t, resp, &apiPull)
+		statusURL := fmt.Sprintf("/api/v1/repos/%s/%s/statuses/%s?token=%s", owner.Name, repo.Name, apiPull.Head.Sha, token)
+
+		req = NewRequestWithJSON(t, http.MethodPost, statusURL, &api.CreateStatusOption{
+			State:   api.StatusPending,
+			Context: "ci",
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", owner.Name, repo.Name, pr.Index, token), &auth.MergePullRequestForm{
+			Do:                     string(models.MergeStyleMerge),
+			MergeWhenChecksSucceed: true,
+		})
+		session.MakeRequest(t, req, http.StatusAccepted)
+
+		// the scheduled merge happens once the commit status succeeds
+		req = NewRequestWithJSON(t, http.MethodPost, statusURL, &api.CreateStatusOption{
+			State:   api.StatusSuccess,
+			Context: "ci",
+		})
+		session.MakeRequest(t, req, http.StatusCreated)
+
+		assert.Eventually(t, func() bool {
+			pr = models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID}).(*models.PullRequest)
+			return pr.HasMerged
+		}, 10*time.Second, 100*time.Millisecond)
+		assert.Equal(t, owner.ID, pr.MergerID)
+		models.AssertNotExistsBean(t, &models.PullAutoMerge{PullID: pr.ID})
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPITagProtection(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := models.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+	baseURL := fmt.Sprintf("/api/v1/repos/%s/%s/tag_protections", owner.Name, repo.Name)
+
+	req := NewRequestWithJSON(t, "POST", baseURL+"?token="+token, &api.CreateTagProtectionOption{
+		NamePattern: "/[/",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// nobody is allowed to create tags matching the pattern
+	req = NewRequestWithJSON(t, "POST", baseURL+"?token="+token, &api.CreateTagProtectionOption{
+		NamePattern: "v*",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var pt api.TagProtection
+	DecodeJSON(t, resp, &pt)
+	assert.Equal(t, "v*", pt.NamePattern)
+	assert.Empty(t, pt.AllowlistUsernames)
+
+	req = NewRequest(t, "GET", baseURL+"?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var pts []*api.TagProtection
+	DecodeJSON(t, resp, &pts)
+	assert.Len(t, pts, 1)
+
+	releasesURL := fmt.Sprintf("/api/v1/repos/%s/%s/releases?token=%s", owner.Name, repo.Name, token)
+	req = NewRequestWithJSON(t, "POST", releasesURL, &api.CreateReleaseOption{
+		TagName: "v2.0.0",
+		Title:   "v2.0.0",
+		Target:  "master",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	models.AssertNotExistsBean(t, &models.Release{RepoID: repo.ID, TagName: "v2.0.0"})
+
+	createNewReleaseUsingAPI(t, session, token, owner, repo, "release-2.0.0", "master", "release-2.0.0", "")
+
+	// allow the owner
+	itemURL := fmt.Sprintf("%s/%d?token=%s", baseURL, pt.ID, token)
+	req = NewRequestWithJSON(t, "PATCH", itemURL, &api.EditTagProtectionOption{
+		AllowlistUsernames: []string{owner.Name},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &pt)
+	assert.Equal(t, "v*", pt.NamePattern)
+	assert.Equal(t, []string{owner.Name}, pt.AllowlistUsernames)
+
+	createNewReleaseUsingAPI(t, session, token, owner, repo, "v2.0.0", "master", "v2.0.0", "")
+
+	req = NewRequest(t, "DELETE", itemURL)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequest(t, "GET", itemURL)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	// only repository admins can manage tag protections
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequest(t, "GET", baseURL+"?token="+token4)
+	session4.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestRepoSettingsProtectedTags(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	link := "/user2/repo1/settings/tags"
+
+	req := NewRequest(t, "GET", link)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", link, map[string]string{
+		"_csrf":           htmlDoc.GetCSRF(),
+		"name_pattern":    "v*",
+		"allowlist_users": "2",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	pt := models.AssertExistsAndLoadBean(t, &models.ProtectedTag{RepoID: 1, NamePattern: "v*"}).(*models.ProtectedTag)
+	assert.Equal(t, []int64{2}, pt.AllowlistUserIDs)
+
+	req = NewRequest(t, "GET", fmt.Sprintf("%s/%d", link, pt.ID))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	value, _ := htmlDoc.doc.Find("input[name=name_pattern]").Attr("value")
+	assert.Equal(t, "v*", value)
+
+	req = NewRequestWithValues(t, "POST", link+"/delete", map[string]string{
+		"_csrf": htmlDoc.GetCSRF(),
+		"id":    fmt.Sprint(pt.ID),
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertNotExistsBean(t, &models.ProtectedTag{ID: pt.ID})
+}
@@ -5,11 +5,15 @@
 package integrations
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"testing"
 
 	"code.gitea.io/gitea/models"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TestAPICreateAndDeleteToken tests that token that was just created can be deleted
@@ -61,3 +65,77 @@ func TestAPIDeleteMissingToken(t *testing.T) {
 	req = AddBasicAuthHeader(req, user.Name)
 	MakeRequest(t, req, http.StatusNotFound)
 }
+
+// TestAPITokenScopes ensures that restricted tokens only grant access to their scopes and repositories
+func TestAPITokenScopes(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	createToken := func(t *testing.T, scopes, repositories []string, expectedStatus int) *api.AccessToken {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/users/user2/tokens", &api.CreateAccessTokenOption{
+			Name:         fmt.Sprintf("scoped-token-%d", len(scopes)+len(repositories)),
+			Scopes:       scopes,
+			Repositories: repositories,
+		})
+		req = AddBasicAuthHeader(req, "user2")
+		resp := MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusCreated {
+			return nil
+		}
+		var token api.AccessToken
+		DecodeJSON(t, resp, &token)
+		return &token
+	}
+
+	createToken(t, []string{"read:unknown"}, nil, http.StatusUnprocessableEntity)
+	createToken(t, nil, []string{"user2/unknown"}, http.StatusUnprocessableEntity)
+
+	token := createToken(t, []string{"read:repository", "read:user", "read:repository"}, []string{"user2/repo1"}, http.StatusCreated)
+	assert.Equal(t, []string{"read:repository", "read:user"}, token.Scopes)
+	assert.Equal(t, []string{"user2/repo1"}, token.Repositories)
+
+	cases := []struct {
+		method string
+		url    string
+		status int
+	}{
+		{"GET", "/api/v1/user", http.StatusOK},
+		{"GET", "/api/v1/repos/user2/repo1", http.StatusOK},
+		{"GET", "/api/v1/repositories/1", http.StatusOK},
+		// restricted to repo1
+		{"GET", "/api/v1/repos/user2/repo2", http.StatusNotFound},
+		{"GET", "/api/v1/repositories/2", http.StatusNotFound},
+		// missing scopes
+		{"GET", "/api/v1/repos/user2/repo1/issues", http.StatusForbidden},
+		{"GET", "/api/v1/user/orgs", http.StatusForbidden},
+		{"PATCH", "/api/v1/repos/user2/repo1", http.StatusForbidden},
+		{"POST", "/api/v1/user/repos", http.StatusForbidden},
+		{"GET", "/api/v1/users/user2/tokens", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := NewRequestWithBody(t, c.method, c.url+"?token="+token.Token, bytes.NewReader(nil))
+		if c.url == "/api/v1/users/user2/tokens" {
+			req = NewRequest(t, c.method, c.url)
+			req.SetBasicAuth(token.Token, "x-oauth-basic")
+		}
+		MakeRequest(t, req, c.status)
+	}
+
+	// Restricted tokens can't be used for the web interface
+	req := NewRequest(t, "GET", "/user/settings")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusFound)
+
+	// Git over HTTP
+	req = NewRequest(t, "GET", "/user2/repo16.git/info/refs")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusForbidden)
+
+	token = createToken(t, []string{"read:repository"}, nil, http.StatusCreated)
+	req = NewRequest(t, "GET", "/user2/repo16.git/info/refs")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequest(t, "GET", "/user2/repo16.git/info/refs?service=git-receive-pack")
+	req.SetBasicAuth(token.Token, "x-oauth-basic")
+	MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequestWithValues(t, "POST", "/user/login", map[string]string{
+		"_csrf":     GetCSRF(t, emptyTestSession(t), "/user/login"),
+		"user_name": "user4",
+		"password":  "wrong password",
+	})
+	MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.AuditEvent{Action: models.AuditUserLoginFailed, OwnerID: 4})
+
+	// sessions of loginUser are cached between tests, sign in again to record the event
+	session := loginUserWithPassword(t, "user4", userPassword)
+	getTokenForLoggedInUser(t, session)
+	models.AssertExistsAndLoadBean(t, &models.AuditEvent{Action: models.AuditUserLogin, ActorID: 4, OwnerID: 4})
+	models.AssertExistsAndLoadBean(t, &models.AuditEvent{Action: models.AuditAccessTokenCreate, ActorID: 4, OwnerID: 4})
+
+	req = NewRequest(t, "GET", "/user/settings/audit_log?action=access_token_create")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".audit-log tbody tr").Length())
+
+	// only admins may read the site-wide log
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/admin/audit_log?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	adminSession := loginUser(t, "user1")
+	adminToken := getTokenForLoggedInUser(t, adminSession)
+	req = NewRequestf(t, "GET", "/api/v1/admin/audit_log?owner=user4&token=%s", adminToken)
+	resp = adminSession.MakeRequest(t, req, http.StatusOK)
+	var events []*api.AuditEvent
+	DecodeJSON(t, resp, &events)
+	if assert.Len(t, events, 4) {
+		// newest first
+		assert.EqualValues(t, models.AuditAccessTokenCreate, events[0].Action)
+		assert.EqualValues(t, "user4", events[0].ActorName)
+		assert.EqualValues(t, "user4", events[0].OwnerName)
+		assert.EqualValues(t, models.AuditUserLoginFailed, events[3].Action)
+		assert.EqualValues(t, "invalid user name or password", events[3].Description)
+	}
+	assert.Equal(t, "4", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/audit_log?owner=user4&action=user_login&token=%s", adminToken)
+	resp = adminSession.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &events)
+	assert.Len(t, events, 1)
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/audit_log?action=unknown&token=%s", adminToken)
+	adminSession.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequest(t, "GET", "/admin/audit_log")
+	adminSession.MakeRequest(t, req, http.StatusOK)
+
+	// user2 owns the organization user3, which has a webhook event in the fixtures
+	req = NewRequest(t, "GET", "/org/user3/settings/audit_log")
+	resp = loginUser(t, "user2").MakeRequest(t, req, http.StatusOK)
+	htmlDoc = NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".audit-log tbody tr").Length())
+}
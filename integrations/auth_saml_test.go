@@ -0,0 +1,209 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	samlauth "code.gitea.io/gitea/modules/auth/saml"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSAMLIdentityProvider(t *testing.T) *saml.IdentityProvider {
+	certPEM, keyPEM, err := samlauth.GenerateKeyPair("idp.example.com")
+	assert.NoError(t, err)
+	keyPair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	assert.NoError(t, err)
+
+	metadataURL, _ := url.Parse("https://idp.example.com/metadata")
+	ssoURL, _ := url.Parse("https://idp.example.com/sso")
+	return &saml.IdentityProvider{
+		Key:         keyPair.PrivateKey,
+		Certificate: cert,
+		MetadataURL: *metadataURL,
+		SSOURL:      *ssoURL,
+	}
+}
+
+func addAuthSourceSAML(t *testing.T, idp *saml.IdentityProvider) *models.LoginSource {
+	metadata, err := xml.Marshal(idp.Metadata())
+	assert.NoError(t, err)
+
+	session := loginUser(t, "user1")
+	csrf := GetCSRF(t, session, "/admin/auths/new")
+	req := NewRequestWithValues(t, "POST", "/admin/auths/new", map[string]string{
+		"_csrf":                         csrf,
+		"type":                          "8",
+		"name":                          "saml",
+		"saml_idp_metadata":             string(metadata),
+		"saml_name_id_format":           "persistent",
+		"saml_attribute_username":       "uid",
+		"saml_attribute_email":          "eduPersonPrincipalName",
+		"saml_attribute_full_name":      "cn",
+		"saml_attribute_groups":         "eduPersonAffiliation",
+		"saml_group_team_map":           `{"developers": {"user3": ["team1"]}}`,
+		"saml_enable_auto_registration": "on",
+		"is_active":                     "on",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+
+	sources, err := models.LoginSourcesByType(models.LoginSAML)
+	assert.NoError(t, err)
+	assert.Len(t, sources, 1)
+	return sources[0]
+}
+
+func makeSAMLResponse(t *testing.T, idp *saml.IdentityProvider, source *models.LoginSource, requestID string) string {
+	sp, err := source.SAML().ServiceProvider(source.SAMLServiceProviderURL())
+	assert.NoError(t, err)
+	spMetadata := sp.Metadata()
+	req := &saml.IdpAuthnRequest{
+		IDP:                     idp,
+		HTTPRequest:             httptest.NewRequest("GET", idp.SSOURL.String(), nil),
+		Request:                 saml.AuthnRequest{ID: requestID},
+		ServiceProviderMetadata: spMetadata,
+		SPSSODescriptor:         &spMetadata.SPSSODescriptors[0],
+		ACSEndpoint:             &spMetadata.SPSSODescriptors[0].AssertionConsumerServices[0],
+		Now:                     saml.TimeNow(),
+	}
+	assert.NoError(t, saml.DefaultAssertionMaker{}.MakeAssertion(req, &saml.Session{
+		ID:             "session",
+		CreateTime:     time.Now(),
+		ExpireTime:     time.Now().Add(time.Hour),
+		NameID:         "saml-user-id",
+		UserName:       "saml-user",
+		UserEmail:      "saml-user@example.com",
+		UserCommonName: "SAML User",
+		Groups:         []string{"developers"},
+	}))
+	assert.NoError(t, req.MakeResponse())
+
+	doc := etree.NewDocument()
+	doc.SetRoot(req.ResponseEl)
+	response, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(response)
+}
+
+// startSAMLLogin starts a login at the identity provider and returns the ID of the request
+func startSAMLLogin(t *testing.T, session *TestSession) string {
+	req := NewRequest(t, "GET", "/user/saml/saml")
+	resp := session.MakeRequest(t, req, http.StatusFound)
+	assert.True(t, strings.HasPrefix(resp.Header().Get("Location"), "https://idp.example.com/sso?SAMLRequest="))
+
+	for _, cookie := range (&http.Response{Header: resp.Header()}).Cookies() {
+		if cookie.Name == "saml_request_id" {
+			return cookie.Value
+		}
+	}
+	assert.Fail(t, "no SAML request cookie set")
+	return ""
+}
+
+func postSAMLResponse(t *testing.T, session *TestSession, requestID, response string, expectedStatus int) *httptest.ResponseRecorder {
+	req := NewRequestWithValues(t, "POST", "/user/saml/saml/acs", map[string]string{
+		"SAMLResponse": response,
+	})
+	req.AddCookie(&http.Cookie{Name: "saml_request_id", Value: requestID})
+	return session.MakeRequest(t, req, expectedStatus)
+}
+
+func TestSAMLUserSignin(t *testing.T) {
+	defer prepareTestEnv(t)()
+	idp := newSAMLIdentityProvider(t)
+	source := addAuthSourceSAML(t, idp)
+	assert.NotEmpty(t, source.SAML().ServiceProviderPrivateKey)
+
+	// the service provider key pair is kept when the source is edited
+	adminSession := loginUser(t, "user1")
+	req := NewRequestf(t, "GET", "/admin/auths/%d", source.ID)
+	resp := adminSession.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), setting.AppURL+"user/saml/saml/acs")
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("/admin/auths/%d", source.ID), map[string]string{
+		"_csrf":                         htmlDoc.GetCSRF(),
+		"type":                          "8",
+		"name":                          "saml",
+		"saml_idp_metadata":             source.SAML().IdentityProviderMetadata,
+		"saml_sp_certificate":           source.SAML().ServiceProviderCertificate,
+		"saml_name_id_format":           "persistent",
+		"saml_attribute_username":       "uid",
+		"saml_attribute_email":          "eduPersonPrincipalName",
+		"saml_attribute_full_name":      "cn",
+		"saml_attribute_groups":         "eduPersonAffiliation",
+		"saml_group_team_map":           `{"developers": {"user3": ["team1"]}}`,
+		"saml_enable_auto_registration": "on",
+		"is_active":                     "on",
+	})
+	adminSession.MakeRequest(t, req, http.StatusFound)
+	edited, err := models.GetLoginSourceByID(source.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, source.SAML().ServiceProviderPrivateKey, edited.SAML().ServiceProviderPrivateKey)
+
+	req = NewRequest(t, "GET", "/user/saml/saml/metadata")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), `entityID="`+setting.AppURL+`user/saml/saml/metadata"`)
+	assert.Contains(t, resp.Body.String(), `Location="`+setting.AppURL+`user/saml/saml/acs"`)
+
+	req = NewRequest(t, "GET", "/user/login")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "/user/saml/saml")
+
+	// a response to another request is rejected
+	session := emptyTestSession(t)
+	requestID := startSAMLLogin(t, session)
+	resp = postSAMLResponse(t, session, requestID, makeSAMLResponse(t, idp, source, "id-other"), http.StatusFound)
+	assert.Equal(t, "/user/login", resp.Header().Get("Location"))
+	models.AssertNotExistsBean(t, &models.User{LowerName: "saml-user"})
+
+	// a response signed by another identity provider is rejected
+	requestID = startSAMLLogin(t, session)
+	resp = postSAMLResponse(t, session, requestID, makeSAMLResponse(t, newSAMLIdentityProvider(t), source, requestID), http.StatusFound)
+	assert.Equal(t, "/user/login", resp.Header().Get("Location"))
+	models.AssertNotExistsBean(t, &models.User{LowerName: "saml-user"})
+
+	// the account of a new user is registered automatically
+	requestID = startSAMLLogin(t, session)
+	resp = postSAMLResponse(t, session, requestID, makeSAMLResponse(t, idp, source, requestID), http.StatusFound)
+	assert.Equal(t, "/", resp.Header().Get("Location"))
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{LowerName: "saml-user"}).(*models.User)
+	assert.Equal(t, "saml-user@example.com", user.Email)
+	assert.Equal(t, "SAML User", user.FullName)
+	assert.Equal(t, models.LoginSAML, user.LoginType)
+	assert.Equal(t, source.ID, user.LoginSource)
+	assert.Equal(t, "saml-user-id", user.LoginName)
+	models.AssertExistsAndLoadBean(t, &models.ExternalLoginUser{ExternalID: "saml-user-id", LoginSourceID: source.ID})
+	models.AssertExistsAndLoadBean(t, &models.TeamUser{TeamID: 2, UID: user.ID})
+
+	req = NewRequest(t, "GET", "/user/settings")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "saml-user", NewHTMLParser(t, resp.Body).GetInputValueByName("name"))
+
+	// the registered user signs in again
+	session = emptyTestSession(t)
+	requestID = startSAMLLogin(t, session)
+	resp = postSAMLResponse(t, session, requestID, makeSAMLResponse(t, idp, source, requestID), http.StatusFound)
+	assert.Equal(t, "/", resp.Header().Get("Location"))
+	req = NewRequest(t, "GET", "/user/settings")
+	session.MakeRequest(t, req, http.StatusOK)
+}
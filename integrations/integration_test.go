@@ -340,6 +340,7 @@ func getTokenForLoggedInUser(t testing.TB, session *TestSession) string {
 	req = NewRequestWithValues(t, "POST", "/user/settings/applications", map[string]string{
 		"_csrf": doc.GetCSRF(),
 		"name":  fmt.Sprintf("api-testing-token-%d", tokenCounter),
+		"scope": string(models.AccessTokenScopeAll),
 	})
 	resp = session.MakeRequest(t, req, http.StatusFound)
 	req = NewRequest(t, "GET", "/user/settings/applications")
@@ -5,11 +5,17 @@
 package integrations
 
 import (
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
+	"math/big"
+	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -241,3 +247,133 @@ func TestRefreshTokenInvalidation(t *testing.T) {
 	MakeRequest(t, refreshReq, 200)
 	MakeRequest(t, refreshReq, 400)
 }
+
+func TestOpenIDConnect(t *testing.T) {
+	defer prepareTestEnv(t)()
+	setting.OAuth2.InvalidateRefreshTokens = true
+
+	req := NewRequest(t, "GET", "/.well-known/openid-configuration")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var discovery map[string]interface{}
+	DecodeJSON(t, resp, &discovery)
+	assert.Equal(t, setting.AppURL, discovery["issuer"])
+	assert.Equal(t, setting.AppURL+"login/oauth/keys", discovery["jwks_uri"])
+	assert.Equal(t, setting.AppURL+"login/oauth/userinfo", discovery["userinfo_endpoint"])
+
+	req = NewRequest(t, "GET", "/login/oauth/keys")
+	resp = MakeRequest(t, req, http.StatusOK)
+	var keySet struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	DecodeJSON(t, resp, &keySet)
+	assert.Len(t, keySet.Keys, 1)
+	n, err := base64.RawURLEncoding.DecodeString(keySet.Keys[0]["n"])
+	assert.NoError(t, err)
+	publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	// user1 already granted access but not to the OpenID Connect scopes
+	session := loginUser(t, "user1")
+	req = NewRequest(t, "GET", defaultAuthorize+"&scope=openid+profile+email+groups&nonce=thenonce")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 4, htmlDoc.doc.Find(".oauth2-authorize-application-box li").Length())
+	req = NewRequestWithValues(t, "POST", "/login/oauth/grant", map[string]string{
+		"_csrf":        htmlDoc.GetCSRF(),
+		"client_id":    "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"redirect_uri": "a",
+		"state":        "thestate",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	u, err := resp.Result().Location()
+	assert.NoError(t, err)
+	models.AssertExistsAndLoadBean(t, &models.OAuth2Grant{ID: 1, Scope: "openid profile email groups"})
+
+	// the granted scope is not requested again
+	req = NewRequest(t, "GET", defaultAuthorize+"&scope=openid+email")
+	session.MakeRequest(t, req, http.StatusFound)
+
+	type response struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		IDToken      string `json:"id_token"`
+	}
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+		"redirect_uri":  "a",
+		"code":          u.Query().Get("code"),
+	})
+	resp = MakeRequest(t, req, http.StatusOK)
+	parsed := new(response)
+	DecodeJSON(t, resp, parsed)
+
+	type idToken struct {
+		jwt.StandardClaims
+		Nonce             string   `json:"nonce"`
+		PreferredUsername string   `json:"preferred_username"`
+		Email             string   `json:"email"`
+		Groups            []string `json:"groups"`
+	}
+	parseIDToken := func(signed string) *idToken {
+		claims := new(idToken)
+		_, err := jwt.ParseWithClaims(signed, claims, func(token *jwt.Token) (interface{}, error) {
+			return publicKey, nil
+		})
+		assert.NoError(t, err)
+		return claims
+	}
+	claims := parseIDToken(parsed.IDToken)
+	assert.Equal(t, setting.AppURL, claims.Issuer)
+	assert.Equal(t, "da7da3ba-9a13-4167-856f-3899de0b0138", claims.Audience)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Equal(t, "thenonce", claims.Nonce)
+	assert.Equal(t, "user1", claims.PreferredUsername)
+	assert.Equal(t, "user1@example.com", claims.Email)
+	assert.Empty(t, claims.Groups)
+
+	req = NewRequest(t, "GET", "/login/oauth/userinfo")
+	MakeRequest(t, req, http.StatusUnauthorized)
+	req = NewRequest(t, "GET", "/login/oauth/userinfo")
+	req.Header.Set("Authorization", "Bearer "+parsed.RefreshToken)
+	MakeRequest(t, req, http.StatusUnauthorized)
+	req = NewRequest(t, "GET", "/login/oauth/userinfo")
+	req.Header.Set("Authorization", "Bearer "+parsed.AccessToken)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var userInfo map[string]interface{}
+	DecodeJSON(t, resp, &userInfo)
+	assert.Equal(t, "1", userInfo["sub"])
+	assert.Equal(t, "user1", userInfo["preferred_username"])
+	assert.Equal(t, "user1@example.com", userInfo["email"])
+
+	// refresh tokens are rotated
+	refresh := func(refreshToken string, expectedStatus int) *response {
+		req := NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+			"grant_type":    "refresh_token",
+			"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+			"client_secret": "4MK8Na6R55smdCY0WuCCumZ6hjRPnGY5saWVRHHjJiA=",
+			"refresh_token": refreshToken,
+		})
+		resp := MakeRequest(t, req, expectedStatus)
+		refreshed := new(response)
+		DecodeJSON(t, resp, refreshed)
+		return refreshed
+	}
+	refreshed := refresh(parsed.RefreshToken, http.StatusOK)
+	assert.NotEqual(t, parsed.RefreshToken, refreshed.RefreshToken)
+	claims = parseIDToken(refreshed.IDToken)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Empty(t, claims.Nonce)
+
+	// a reused refresh token revokes the refresh token issued for it
+	refresh(parsed.RefreshToken, http.StatusBadRequest)
+	refresh(refreshed.RefreshToken, http.StatusBadRequest)
+
+	// refreshing requires the client secret
+	req = NewRequestWithValues(t, "POST", "/login/oauth/access_token", map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     "da7da3ba-9a13-4167-856f-3899de0b0138",
+		"refresh_token": refreshed.RefreshToken,
+	})
+	MakeRequest(t, req, http.StatusBadRequest)
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrgProjectBoard(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user3/-/projects/new")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	doc := NewHTMLParser(t, resp.Body)
+
+	req = NewRequestWithValues(t, "POST", "/user3/-/projects/new", map[string]string{
+		"_csrf":      doc.GetCSRF(),
+		"title":      "Release",
+		"board_type": "1",
+	})
+	resp = session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user3/-/projects", resp.Header().Get("Location"))
+
+	p := models.AssertExistsAndLoadBean(t, &models.Project{OwnerID: 3, Title: "Release"}).(*models.Project)
+	assert.Equal(t, models.ProjectTypeOrganization, p.Type)
+	projectLink := fmt.Sprintf("/user3/-/projects/%d", p.ID)
+
+	// Put issues of two repositories on the board
+	for _, ref := range []string{"user2/repo1#1", "user3/repo3#1"} {
+		req = NewRequestWithValues(t, "POST", projectLink+"/cards", map[string]string{
+			"_csrf": doc.GetCSRF(),
+			"issue": ref,
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+	}
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{ProjectID: p.ID, IssueID: 1})
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{ProjectID: p.ID, IssueID: 6})
+
+	board := models.AssertExistsAndLoadBean(t, &models.ProjectBoard{ProjectID: p.ID, Title: "To Do"}).(*models.ProjectBoard)
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("%s/%d/6", projectLink, board.ID), map[string]string{
+		"_csrf": doc.GetCSRF(),
+	})
+	session.MakeRequest(t, req, http.StatusOK)
+	models.AssertExistsAndLoadBean(t, &models.ProjectIssue{ProjectID: p.ID, IssueID: 6, ProjectBoardID: board.ID})
+
+	req = NewRequest(t, "GET", projectLink)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	assert.True(t, strings.Contains(resp.Body.String(), "user3/repo3#1"))
+
+	// Anonymous users see the board without the issue of the private repository
+	req = NewRequest(t, "GET", projectLink)
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.True(t, strings.Contains(resp.Body.String(), "user2/repo1#1"))
+	assert.False(t, strings.Contains(resp.Body.String(), "user3/repo3#1"))
+
+	req = NewRequest(t, "GET", "/user3/-/projects/new")
+	MakeRequest(t, req, http.StatusFound)
+
+	// Team members without the projects unit may not change the board
+	session4 := loginUser(t, "user4")
+	req = NewRequestWithValues(t, "POST", projectLink+"/cards", map[string]string{
+		"_csrf": GetCSRF(t, session4, "/user/settings"),
+		"issue": "user2/repo1#2",
+	})
+	session4.MakeRequest(t, req, http.StatusNotFound)
+}
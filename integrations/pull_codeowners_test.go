@@ -0,0 +1,83 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullCodeOwners(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user1")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+
+		// Add the CODEOWNERS file to the base branch
+		req := NewRequest(t, "GET", "/user1/repo1/_new/master/")
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		doc := NewHTMLParser(t, resp.Body)
+		req = NewRequestWithValues(t, "POST", "/user1/repo1/_new/master/", map[string]string{
+			"_csrf":         doc.GetCSRF(),
+			"last_commit":   doc.GetInputValueByName("last_commit"),
+			"tree_path":     ".gitea/CODEOWNERS",
+			"content":       "# owners\n*.md @user2 @unknown\n*.go user4@example.com\n",
+			"commit_choice": "direct",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+
+		testEditFileToNewBranch(t, session, "user1", "repo1", "master", "codeowners", "README.md", "codeowners")
+		url := path.Join("user1", "repo1", "compare", "master...codeowners")
+		req = NewRequestWithValues(t, "POST", url, map[string]string{
+			"_csrf": GetCSRF(t, session, url),
+			"title": "pull request touching owned files",
+		})
+		session.MakeRequest(t, req, http.StatusFound)
+
+		repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerName: "user1", Name: "repo1"}).(*models.Repository)
+		issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1}).(*models.Issue)
+		models.AssertExistsAndLoadBean(t, &models.Review{IssueID: issue.ID, ReviewerID: 2, Type: models.ReviewTypeRequest})
+		models.AssertNotExistsBean(t, &models.Review{IssueID: issue.ID, ReviewerID: 4})
+
+		// Require the approval of the code owners
+		token := getTokenForLoggedInUser(t, session)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user1/repo1/branch_protections?token="+token, &api.CreateBranchProtectionOption{
+			BranchName:              "master",
+			BlockOnCodeOwnerReviews: true,
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		assert.True(t, protection.BlockOnCodeOwnerReviews)
+
+		pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{IssueID: issue.ID}).(*models.PullRequest)
+		assert.NoError(t, pr.LoadProtectedBranch())
+		isBlocked, err := pull_service.IsBlockedByCodeOwners(pr)
+		assert.NoError(t, err)
+		assert.True(t, isBlocked)
+
+		req = NewRequest(t, "GET", "/user1/repo1/pulls/1")
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "lacks the approval of code owners")
+
+		session2 := loginUser(t, "user2")
+		token2 := getTokenForLoggedInUser(t, session2)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user1/repo1/pulls/1/reviews?token="+token2, &api.CreatePullReviewOptions{
+			Event: api.ReviewStateApproved,
+		})
+		session2.MakeRequest(t, req, http.StatusOK)
+
+		isBlocked, err = pull_service.IsBlockedByCodeOwners(pr)
+		assert.NoError(t, err)
+		assert.False(t, isBlocked)
+	})
+}
@@ -18,3 +18,18 @@ func TestPullView_ReviewerMissed(t *testing.T) {
 	req = NewRequest(t, "GET", "/user2/repo1/pulls/3")
 	session.MakeRequest(t, req, http.StatusOK)
 }
+
+func TestPullApplySuggestions_NotAllowed(t *testing.T) {
+	defer prepareTestEnv(t)()
+	session := loginUser(t, "user2")
+
+	req := NewRequest(t, "GET", "/user2/repo1/pulls/2/files")
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// suggestions can not be committed to a merged pull request
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/pulls/2/suggestions/apply", map[string]string{
+		"_csrf":       GetCSRF(t, session, "/user2/repo1/pulls/2/files"),
+		"comment_ids": "4",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/ratelimit"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func enableRateLimit(t *testing.T) func() {
+	oldRateLimit := setting.RateLimit
+	setting.RateLimit.Enabled = true
+	setting.RateLimit.API = setting.RateLimitBudget{Enabled: true, Requests: 2, Period: time.Hour, Burst: 2}
+	setting.RateLimit.Login = setting.RateLimitBudget{Enabled: true, Requests: 2, Period: time.Hour, Burst: 2}
+	assert.NoError(t, ratelimit.Init())
+	return func() {
+		setting.RateLimit = oldRateLimit
+		assert.NoError(t, ratelimit.Init())
+	}
+}
+
+func TestAPIRateLimit(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer enableRateLimit(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	for remaining := 1; remaining >= 0; remaining-- {
+		req := NewRequestf(t, "GET", "/api/v1/user?token=%s", token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, "2", resp.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(remaining), resp.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, resp.Header().Get("X-RateLimit-Reset"))
+	}
+
+	req := NewRequestf(t, "GET", "/api/v1/user?token=%s", token)
+	resp := MakeRequest(t, req, http.StatusTooManyRequests)
+	assert.Equal(t, "0", resp.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+
+	// anonymous requests are limited per IP address
+	anonymousReq := NewRequest(t, "GET", "/api/v1/version")
+	anonymousReq.Header.Set("X-Real-IP", "192.0.2.1")
+	resp = MakeRequest(t, anonymousReq, http.StatusOK)
+	assert.Equal(t, "1", resp.Header().Get("X-RateLimit-Remaining"))
+
+	setting.RateLimit.ExemptUsers = []string{"user2"}
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Empty(t, resp.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer enableRateLimit(t)()
+
+	session := emptyTestSession(t)
+	signIn := func(expectedStatus int) {
+		req := NewRequestWithValues(t, "POST", "/user/login", map[string]string{
+			"_csrf":     GetCSRF(t, session, "/user/login"),
+			"user_name": "user2",
+			"password":  "wrong password",
+		})
+		req.Header.Set("X-Real-IP", "192.0.2.2")
+		session.MakeRequest(t, req, expectedStatus)
+	}
+	signIn(http.StatusOK)
+	signIn(http.StatusOK)
+	signIn(http.StatusTooManyRequests)
+}
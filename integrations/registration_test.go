@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/registration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signUp(t *testing.T, name, email string, expectedStatus int) {
+	req := NewRequestWithValues(t, "POST", "/user/sign_up", map[string]string{
+		"user_name": name,
+		"email":     email,
+		"password":  "examplePassword!1",
+		"retype":    "examplePassword!1",
+	})
+	MakeRequest(t, req, expectedStatus)
+}
+
+func TestSignupRegistrationChecks(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(denied []string, disposable string) {
+		setting.Service.EmailDomainBlocklist = denied
+		setting.Registration.DisposableEmailDomains = disposable
+		assert.NoError(t, registration.Init())
+	}(setting.Service.EmailDomainBlocklist, setting.Registration.DisposableEmailDomains)
+
+	setting.Service.EnableCaptcha = false
+	setting.Service.EmailDomainBlocklist = []string{"example.org"}
+	setting.Registration.DisposableEmailDomains = setting.RegistrationActionFlag
+	assert.NoError(t, registration.Init())
+
+	signUp(t, "deniedUser", "deniedUser@mail.example.org", http.StatusOK)
+	models.AssertNotExistsBean(t, &models.User{LowerName: "denieduser"})
+
+	signUp(t, "disposableUser", "disposableUser@mailinator.com", http.StatusFound)
+	user := models.AssertExistsAndLoadBean(t, &models.User{LowerName: "disposableuser"}).(*models.User)
+	models.AssertExistsAndLoadBean(t, &models.FlaggedUser{UserID: user.ID})
+
+	signUp(t, "acceptedUser", "acceptedUser@example.com", http.StatusFound)
+	user = models.AssertExistsAndLoadBean(t, &models.User{LowerName: "accepteduser"}).(*models.User)
+	models.AssertNotExistsBean(t, &models.FlaggedUser{UserID: user.ID})
+}
+
+func TestAdminReviewQueue(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(issueLimit int) {
+		setting.Registration.ProbationIssueLimit = issueLimit
+	}(setting.Registration.ProbationIssueLimit)
+	setting.Registration.ProbationIssueLimit = 0
+
+	assert.NoError(t, models.FlagUser(2, "new accounts are reviewed"))
+	assert.NoError(t, models.FlagUser(4, "new accounts are reviewed"))
+
+	// flagged accounts are in probation
+	token := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+token, &api.CreateIssueOption{
+		Title: "probation",
+	})
+	MakeRequest(t, req, http.StatusForbidden)
+
+	session := loginUser(t, "user1")
+	req = NewRequest(t, "GET", "/admin/users/review")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 2, htmlDoc.doc.Find("form[action$='/approve']").Length())
+
+	csrf := GetCSRF(t, session, "/admin/users/review")
+	req = NewRequestWithValues(t, "POST", "/admin/users/review/2/approve", map[string]string{
+		"_csrf": csrf,
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.FlaggedUser{UserID: 2})
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+token, &api.CreateIssueOption{
+		Title: "approved",
+	})
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequestWithValues(t, "POST", "/admin/users/review/4/reject", map[string]string{
+		"_csrf": csrf,
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.FlaggedUser{UserID: 4})
+	models.AssertExistsAndLoadBean(t, &models.User{ID: 4, ProhibitLogin: true})
+	models.AssertExistsAndLoadBean(t, &models.AuditEvent{Action: models.AuditAdminUserReject, TargetID: 4})
+
+	// the entry of an account which no longer exists is removed from the queue
+	assert.NoError(t, models.FlagUser(9999, "new accounts are reviewed"))
+	req = NewRequestWithValues(t, "POST", "/admin/users/review/9999/reject", map[string]string{
+		"_csrf": csrf,
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.FlaggedUser{UserID: 9999})
+}
@@ -0,0 +1,146 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserBlock(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	blockeeSession := loginUser(t, "user4")
+	blockeeToken := getTokenForLoggedInUser(t, blockeeSession)
+
+	req := NewRequestf(t, "GET", "/api/v1/user/blocks/user4?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "PUT", "/api/v1/user/blocks/user4?note=spam&token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "GET", "/api/v1/user/blocks/user4?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequestf(t, "GET", "/api/v1/user/blocks?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var blocks []*api.BlockedUser
+	DecodeJSON(t, resp, &blocks)
+	if assert.Len(t, blocks, 1) {
+		assert.EqualValues(t, "user4", blocks[0].User.UserName)
+		assert.EqualValues(t, "spam", blocks[0].Note)
+	}
+
+	// user4 followed user2 and watched user2/repo1 before the block
+	assert.False(t, models.IsFollowing(4, 2))
+	assert.False(t, models.IsWatching(4, 1))
+
+	req = NewRequestf(t, "PUT", "/api/v1/user/following/user2?token=%s", blockeeToken)
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestf(t, "PUT", "/api/v1/user/starred/user2/repo1?token=%s", blockeeToken)
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestf(t, "PUT", "/api/v1/repos/user2/repo1/subscription?token=%s", blockeeToken)
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues?token="+blockeeToken, &api.CreateIssueOption{
+		Title: "spam",
+	})
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithValues(t, "POST", "/api/v1/repos/user2/repo1/issues/1/comments?token="+blockeeToken, map[string]string{
+		"body": "spam",
+	})
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/issues/1/reactions?token="+blockeeToken, &api.EditReactionOption{
+		Reaction: "rocket",
+	})
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/collaborators/user4?token="+token, &api.AddCollaboratorOption{})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/user/blocks/user4?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "PUT", "/api/v1/user/following/user2?token=%s", blockeeToken)
+	blockeeSession.MakeRequest(t, req, http.StatusNoContent)
+}
+
+func TestAPIOrgBlock(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user2 owns the organization user3
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "PUT", "/api/v1/orgs/user3/blocks/user5?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	models.AssertExistsAndLoadBean(t, &models.BlockedUser{BlockerID: 3, BlockeeID: 5})
+
+	// user4 is a member of the organization
+	req = NewRequestf(t, "PUT", "/api/v1/orgs/user3/blocks/user4?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "PUT", "/api/v1/teams/1/members/user5?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	blockeeSession := loginUser(t, "user5")
+	blockeeToken := getTokenForLoggedInUser(t, blockeeSession)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo21/issues?token="+blockeeToken, &api.CreateIssueOption{
+		Title: "spam",
+	})
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+
+	// only owners can manage the blocked users of an organization
+	req = NewRequestf(t, "GET", "/api/v1/orgs/user3/blocks?token=%s", blockeeToken)
+	blockeeSession.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/orgs/user3/blocks/user5?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user3/repo21/issues?token="+blockeeToken, &api.CreateIssueOption{
+		Title: "not spam",
+	})
+	blockeeSession.MakeRequest(t, req, http.StatusCreated)
+}
+
+func TestUserBlockSettings(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequestWithValues(t, "POST", "/user/settings/blocked_users", map[string]string{
+		"_csrf":   GetCSRF(t, session, "/user/settings/blocked_users"),
+		"blockee": "user4",
+		"note":    "spam",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertExistsAndLoadBean(t, &models.BlockedUser{BlockerID: 2, BlockeeID: 4, Note: "spam"})
+
+	req = NewRequest(t, "GET", "/user/settings/blocked_users")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	htmlDoc := NewHTMLParser(t, resp.Body)
+	assert.EqualValues(t, 1, htmlDoc.doc.Find(".blocked-users .list .item").Length())
+
+	// the blocked user can not comment on the issues of the repositories of the blocker
+	blockeeSession := loginUser(t, "user4")
+	req = NewRequestWithValues(t, "POST", "/user2/repo1/issues/1/comments", map[string]string{
+		"_csrf":   GetCSRF(t, blockeeSession, "/user2/repo1/issues/1"),
+		"content": "spam",
+	})
+	blockeeSession.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.Comment{PosterID: 4, Content: "spam"})
+
+	req = NewRequestWithValues(t, "POST", "/user/settings/blocked_users/unblock", map[string]string{
+		"_csrf": GetCSRF(t, session, "/user/settings/blocked_users"),
+		"uid":   "4",
+	})
+	session.MakeRequest(t, req, http.StatusFound)
+	models.AssertNotExistsBean(t, &models.BlockedUser{BlockerID: 2, BlockeeID: 4})
+
+	req = NewRequest(t, "GET", "/org/user3/settings/blocked_users")
+	session.MakeRequest(t, req, http.StatusOK)
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebAuthnRegistrationOptions(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user1")
+	csrf := GetCSRF(t, session, "/user/settings")
+
+	// the name of a registered credential can not be used again
+	req := NewRequestWithValues(t, "POST", "/user/settings/security/webauthn/request_register", map[string]string{
+		"_csrf": csrf,
+		"name":  "WebAuthn credential",
+	})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	req = NewRequestWithValues(t, "POST", "/user/settings/security/webauthn/request_register", map[string]string{
+		"_csrf":        csrf,
+		"name":         "New credential",
+		"passwordless": "true",
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+
+	var creation protocol.CredentialCreation
+	DecodeJSON(t, resp, &creation)
+	assert.Equal(t, "localhost", creation.Response.RelyingParty.ID)
+	assert.Equal(t, "user1", creation.Response.User.Name)
+	assert.NotEmpty(t, creation.Response.Challenge)
+	assert.Len(t, creation.Response.CredentialExcludeList, 1)
+	assert.True(t, *creation.Response.AuthenticatorSelection.RequireResidentKey)
+	assert.Equal(t, protocol.VerificationRequired, creation.Response.AuthenticatorSelection.UserVerification)
+
+	// an invalid attestation is rejected
+	req = NewRequestWithBody(t, "POST", "/user/settings/security/webauthn/register", strings.NewReader(`{"id":"abc"}`))
+	req.Header.Set("X-Csrf-Token", csrf)
+	session.MakeRequest(t, req, http.StatusBadRequest)
+	models.AssertNotExistsBean(t, &models.WebAuthnCredential{UserID: 1, Name: "New credential"})
+}
+
+func TestWebAuthnSignIn(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user24 is enrolled in TOTP, security keys take precedence
+	models.AssertSuccessfulInsert(t, &models.WebAuthnCredential{
+		Name:         "Security key",
+		UserID:       24,
+		CredentialID: "a2V5",
+	})
+
+	session := emptyTestSession(t)
+	req := NewRequestWithValues(t, "POST", "/user/login", map[string]string{
+		"_csrf":     GetCSRF(t, session, "/user/login"),
+		"user_name": "user24",
+		"password":  "password",
+	})
+	resp := session.MakeRequest(t, req, http.StatusFound)
+	assert.Equal(t, "/user/webauthn", resp.Header().Get("Location"))
+
+	session.MakeRequest(t, NewRequest(t, "GET", "/user/webauthn"), http.StatusOK)
+
+	resp = session.MakeRequest(t, NewRequest(t, "GET", "/user/webauthn/assertion"), http.StatusOK)
+	var assertion protocol.CredentialAssertion
+	DecodeJSON(t, resp, &assertion)
+	assert.Equal(t, "localhost", assertion.Response.RelyingPartyID)
+	if assert.Len(t, assertion.Response.AllowedCredentials, 1) {
+		assert.Equal(t, []byte("key"), []byte(assertion.Response.AllowedCredentials[0].CredentialID))
+	}
+	assert.Contains(t, assertion.Response.Extensions, "appid")
+
+	// an invalid assertion does not sign the user in
+	req = NewRequestWithBody(t, "POST", "/user/webauthn/assertion", strings.NewReader(`{"id":"a2V5"}`))
+	req.Header.Set("X-Csrf-Token", GetCSRF(t, session, "/user/login"))
+	session.MakeRequest(t, req, http.StatusUnauthorized)
+	session.MakeRequest(t, NewRequest(t, "GET", "/user/settings"), http.StatusFound)
+}
+
+func TestWebAuthnPasswordlessOptions(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := emptyTestSession(t)
+	resp := session.MakeRequest(t, NewRequest(t, "GET", "/user/webauthn/passwordless/assertion"), http.StatusOK)
+	var assertion protocol.CredentialAssertion
+	DecodeJSON(t, resp, &assertion)
+	assert.NotEmpty(t, assertion.Response.Challenge)
+	assert.Empty(t, assertion.Response.AllowedCredentials)
+	assert.Equal(t, protocol.VerificationRequired, assertion.Response.UserVerification)
+
+	req := NewRequestWithBody(t, "POST", "/user/webauthn/passwordless/assertion", strings.NewReader(`{"id":"a2V5"}`))
+	req.Header.Set("X-Csrf-Token", GetCSRF(t, session, "/user/login"))
+	session.MakeRequest(t, req, http.StatusUnauthorized)
+}
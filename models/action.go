@@ -50,6 +50,7 @@ const (
 	ActionRejectPullRequest                        // 22
 	ActionCommentPull                              // 23
 	ActionPublishRelease                           // 24
+	ActionTransferIssue                            // 25
 )
 
 // Action represents user operation type and other information to
@@ -262,6 +263,12 @@ func (a *Action) GetIssueInfos() []string {
 	return strings.SplitN(a.Content, "|", 2)
 }
 
+// GetIssueRefLink returns the link to an issue reference of the form
+// owner/repo#index, as stored in the content of issue transfer actions.
+func (a *Action) GetIssueRefLink(ref string) string {
+	return setting.AppSubURL + "/" + strings.Replace(ref, "#", "/issues/", 1)
+}
+
 // GetIssueTitle returns the title of first issue associated
 // with the action.
 func (a *Action) GetIssueTitle() string {
@@ -0,0 +1,260 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// AuditAction represents the type of a security relevant event
+type AuditAction string
+
+// Possible audit actions
+const (
+	AuditUserLogin       AuditAction = "user_login"
+	AuditUserLoginFailed AuditAction = "user_login_failed"
+
+	AuditTwoFactorEnable            AuditAction = "two_factor_enable"
+	AuditTwoFactorDisable           AuditAction = "two_factor_disable"
+	AuditTwoFactorRegenerateScratch AuditAction = "two_factor_regenerate_scratch"
+	AuditWebAuthnRegister           AuditAction = "webauthn_register"
+	AuditWebAuthnRemove             AuditAction = "webauthn_remove"
+
+	AuditAccessTokenCreate AuditAction = "access_token_create"
+	AuditAccessTokenDelete AuditAction = "access_token_delete"
+
+	AuditPublicKeyAdd    AuditAction = "public_key_add"
+	AuditPublicKeyDelete AuditAction = "public_key_delete"
+	AuditGPGKeyAdd       AuditAction = "gpg_key_add"
+	AuditGPGKeyDelete    AuditAction = "gpg_key_delete"
+	AuditDeployKeyAdd    AuditAction = "deploy_key_add"
+	AuditDeployKeyDelete AuditAction = "deploy_key_delete"
+
+	AuditCollaboratorAdd          AuditAction = "collaborator_add"
+	AuditCollaboratorAccessChange AuditAction = "collaborator_access_change"
+	AuditCollaboratorRemove       AuditAction = "collaborator_remove"
+	AuditRepoTeamAdd              AuditAction = "repo_team_add"
+	AuditRepoTeamRemove           AuditAction = "repo_team_remove"
+	AuditTeamCreate               AuditAction = "team_create"
+	AuditTeamUpdate               AuditAction = "team_update"
+	AuditTeamDelete               AuditAction = "team_delete"
+	AuditTeamMemberAdd            AuditAction = "team_member_add"
+	AuditTeamMemberRemove         AuditAction = "team_member_remove"
+	AuditOrgMemberRemove          AuditAction = "org_member_remove"
+
+	AuditBranchProtectionUpdate AuditAction = "branch_protection_update"
+	AuditBranchProtectionDelete AuditAction = "branch_protection_delete"
+
+	AuditWebhookCreate AuditAction = "webhook_create"
+	AuditWebhookUpdate AuditAction = "webhook_update"
+	AuditWebhookDelete AuditAction = "webhook_delete"
+
+	AuditAdminUserCreate       AuditAction = "admin_user_create"
+	AuditAdminUserUpdate       AuditAction = "admin_user_update"
+	AuditAdminUserDelete       AuditAction = "admin_user_delete"
+	AuditAdminUserApprove      AuditAction = "admin_user_approve"
+	AuditAdminUserReject       AuditAction = "admin_user_reject"
+	AuditAdminAuthSourceCreate AuditAction = "admin_auth_source_create"
+	AuditAdminAuthSourceUpdate AuditAction = "admin_auth_source_update"
+	AuditAdminAuthSourceDelete AuditAction = "admin_auth_source_delete"
+	AuditAdminRepoDelete       AuditAction = "admin_repo_delete"
+)
+
+// AuditActions contains all audit actions in the order they are offered as filter
+var AuditActions = []AuditAction{
+	AuditUserLogin,
+	AuditUserLoginFailed,
+	AuditTwoFactorEnable,
+	AuditTwoFactorDisable,
+	AuditTwoFactorRegenerateScratch,
+	AuditWebAuthnRegister,
+	AuditWebAuthnRemove,
+	AuditAccessTokenCreate,
+	AuditAccessTokenDelete,
+	AuditPublicKeyAdd,
+	AuditPublicKeyDelete,
+	AuditGPGKeyAdd,
+	AuditGPGKeyDelete,
+	AuditDeployKeyAdd,
+	AuditDeployKeyDelete,
+	AuditCollaboratorAdd,
+	AuditCollaboratorAccessChange,
+	AuditCollaboratorRemove,
+	AuditRepoTeamAdd,
+	AuditRepoTeamRemove,
+	AuditTeamCreate,
+	AuditTeamUpdate,
+	AuditTeamDelete,
+	AuditTeamMemberAdd,
+	AuditTeamMemberRemove,
+	AuditOrgMemberRemove,
+	AuditBranchProtectionUpdate,
+	AuditBranchProtectionDelete,
+	AuditWebhookCreate,
+	AuditWebhookUpdate,
+	AuditWebhookDelete,
+	AuditAdminUserCreate,
+	AuditAdminUserUpdate,
+	AuditAdminUserDelete,
+	AuditAdminUserApprove,
+	AuditAdminUserReject,
+	AuditAdminAuthSourceCreate,
+	AuditAdminAuthSourceUpdate,
+	AuditAdminAuthSourceDelete,
+	AuditAdminRepoDelete,
+}
+
+// IsValid returns true if the action is a known audit action
+func (a AuditAction) IsValid() bool {
+	for _, action := range AuditActions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// TrStr returns the translation key of the action
+func (a AuditAction) TrStr() string {
+	return "audit.action." + string(a)
+}
+
+// AuditEvent represents a security relevant event, the actor and the target are also stored by name
+// so the event stays readable after they are deleted.
+type AuditEvent struct {
+	ID        int64       `xorm:"pk autoincr"`
+	Action    AuditAction `xorm:"VARCHAR(50) INDEX NOT NULL"`
+	ActorID   int64       `xorm:"INDEX"`
+	ActorName string
+	Actor     *User `xorm:"-"`
+	// OwnerID is the user or organization whose account, organization or repository is affected
+	OwnerID     int64       `xorm:"INDEX"`
+	Owner       *User       `xorm:"-"`
+	RepoID      int64       `xorm:"INDEX"`
+	Repo        *Repository `xorm:"-"`
+	TargetID    int64
+	TargetName  string
+	IPAddress   string             `xorm:"VARCHAR(64)"`
+	Description string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// CreateAuditEvent stores a new audit event
+func CreateAuditEvent(event *AuditEvent) error {
+	if !event.Action.IsValid() {
+		return fmt.Errorf("unknown audit action: %s", event.Action)
+	}
+	_, err := x.Insert(event)
+	return err
+}
+
+// FindAuditEventsOptions represents the options to find audit events
+type FindAuditEventsOptions struct {
+	ListOptions
+	Action  AuditAction
+	ActorID int64
+	OwnerID int64
+	RepoID  int64
+	Since   int64
+	Before  int64
+}
+
+func (opts *FindAuditEventsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": opts.Action})
+	}
+	if opts.ActorID > 0 {
+		cond = cond.And(builder.Eq{"actor_id": opts.ActorID})
+	}
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Since > 0 {
+		cond = cond.And(builder.Gte{"created_unix": opts.Since})
+	}
+	if opts.Before > 0 {
+		cond = cond.And(builder.Lte{"created_unix": opts.Before})
+	}
+	return cond
+}
+
+// FindAuditEvents returns the audit events matching the options, newest first, and their total count
+func FindAuditEvents(opts *FindAuditEventsOptions) (AuditEventList, int64, error) {
+	cond := opts.toConds()
+	count, err := x.Where(cond).Count(new(AuditEvent))
+	if err != nil {
+		return nil, 0, fmt.Errorf("Count: %v", err)
+	}
+
+	sess := x.Where(cond).Desc("id")
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	events := make(AuditEventList, 0, opts.PageSize)
+	if err := sess.Find(&events); err != nil {
+		return nil, 0, err
+	}
+	return events, count, nil
+}
+
+// AuditEventList defines a list of audit events
+type AuditEventList []*AuditEvent
+
+// LoadAttributes loads the actors, owners and repositories of the events which still exist
+func (events AuditEventList) LoadAttributes() error {
+	return events.loadAttributes(x)
+}
+
+func (events AuditEventList) loadAttributes(e Engine) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	userIDs := make(map[int64]struct{}, len(events))
+	repoIDs := make(map[int64]struct{}, len(events))
+	for _, event := range events {
+		if event.ActorID > 0 {
+			userIDs[event.ActorID] = struct{}{}
+		}
+		if event.OwnerID > 0 {
+			userIDs[event.OwnerID] = struct{}{}
+		}
+		if event.RepoID > 0 {
+			repoIDs[event.RepoID] = struct{}{}
+		}
+	}
+
+	userMaps := make(map[int64]*User, len(userIDs))
+	if err := e.In("id", keysInt64(userIDs)).Find(&userMaps); err != nil {
+		return fmt.Errorf("find user: %v", err)
+	}
+	repoMaps := make(map[int64]*Repository, len(repoIDs))
+	if err := e.In("id", keysInt64(repoIDs)).Find(&repoMaps); err != nil {
+		return fmt.Errorf("find repository: %v", err)
+	}
+
+	for _, event := range events {
+		event.Actor = userMaps[event.ActorID]
+		event.Owner = userMaps[event.OwnerID]
+		event.Repo = repoMaps[event.RepoID]
+		if event.Repo != nil {
+			event.Repo.Owner = userMaps[event.Repo.OwnerID]
+			if event.Repo.Owner == nil {
+				if err := event.Repo.getOwner(e); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
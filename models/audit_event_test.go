@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAuditEvent(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	event := &AuditEvent{
+		Action:     AuditAccessTokenCreate,
+		ActorID:    2,
+		ActorName:  "user2",
+		OwnerID:    2,
+		TargetID:   1,
+		TargetName: "Token A",
+		IPAddress:  "192.0.2.1",
+	}
+	assert.NoError(t, CreateAuditEvent(event))
+	AssertExistsAndLoadBean(t, &AuditEvent{ID: event.ID, Action: AuditAccessTokenCreate, TargetName: "Token A"})
+
+	assert.Error(t, CreateAuditEvent(&AuditEvent{Action: "unknown"}))
+}
+
+func TestFindAuditEvents(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	events, count, err := FindAuditEvents(&FindAuditEventsOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	if assert.Len(t, events, 3) {
+		// newest first
+		assert.EqualValues(t, 3, events[0].ID)
+	}
+
+	events, count, err = FindAuditEvents(&FindAuditEventsOptions{OwnerID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, events, 2)
+
+	events, count, err = FindAuditEvents(&FindAuditEventsOptions{OwnerID: 2, Action: AuditUserLoginFailed})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, 0, events[0].ActorID)
+		assert.Equal(t, "user2", events[0].ActorName)
+	}
+
+	events, _, err = FindAuditEvents(&FindAuditEventsOptions{Since: 946684805, Before: 946684815})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, 2, events[0].ID)
+	}
+
+	events, _, err = FindAuditEvents(&FindAuditEventsOptions{ListOptions: ListOptions{Page: 2, PageSize: 2}})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.EqualValues(t, 1, events[0].ID)
+	}
+}
+
+func TestAuditEventList_LoadAttributes(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	events, _, err := FindAuditEvents(&FindAuditEventsOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, events.LoadAttributes())
+
+	for _, event := range events {
+		switch event.ID {
+		case 2:
+			assert.Nil(t, event.Actor)
+			assert.EqualValues(t, 2, event.Owner.ID)
+		case 3:
+			assert.EqualValues(t, 2, event.Actor.ID)
+			assert.EqualValues(t, 3, event.Owner.ID)
+			assert.Equal(t, "user3/repo3", event.Repo.FullName())
+		}
+	}
+}
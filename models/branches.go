@@ -40,6 +40,7 @@ type ProtectedBranch struct {
 	RequiredApprovals         int64    `xorm:"NOT NULL DEFAULT 0"`
 	BlockOnRejectedReviews    bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOutdatedBranch     bool     `xorm:"NOT NULL DEFAULT false"`
+	BlockOnCodeOwnerReviews   bool     `xorm:"NOT NULL DEFAULT false"`
 	DismissStaleApprovals     bool     `xorm:"NOT NULL DEFAULT false"`
 	RequireSignedCommits      bool     `xorm:"NOT NULL DEFAULT false"`
 	ProtectedFilePatterns     string   `xorm:"TEXT"`
@@ -145,6 +146,16 @@ func (protectBranch *ProtectedBranch) isUserOfficialReviewer(e Engine, user *Use
 	return inTeam, nil
 }
 
+// isTeamOfficialReviewer check if the members of the team are official reviewers for the branch
+func (protectBranch *ProtectedBranch) isTeamOfficialReviewer(e Engine, team *Team) bool {
+	if !protectBranch.EnableApprovalsWhitelist {
+		// Teams with write access are considered official reviewers
+		return team.Authorize >= AccessModeWrite && team.unitEnabled(e, UnitTypeCode)
+	}
+
+	return base.Int64sContains(protectBranch.ApprovalsWhitelistTeamIDs, team.ID)
+}
+
 // HasEnoughApprovals returns true if pr has enough granted approvals.
 func (protectBranch *ProtectedBranch) HasEnoughApprovals(pr *PullRequest) bool {
 	if protectBranch.RequiredApprovals == 0 {
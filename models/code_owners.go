@@ -0,0 +1,178 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnersFiles are the paths a CODEOWNERS file is looked up at, in order of precedence
+var CodeOwnersFiles = []string{"CODEOWNERS", ".gitea/CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwnerRule assigns the owners to the paths matching the pattern of a line of a CODEOWNERS file
+type CodeOwnerRule struct {
+	Pattern string
+	Rule    *regexp.Regexp
+	Users   []*User
+	Teams   []*Team
+}
+
+// Match returns true if the path matches the pattern of the rule
+func (rule *CodeOwnerRule) Match(path string) bool {
+	return rule.Rule.MatchString(path)
+}
+
+// IsOwner returns true if the user is one of the owners or a member of one of the owning teams
+func (rule *CodeOwnerRule) IsOwner(userID int64) (bool, error) {
+	for _, u := range rule.Users {
+		if u.ID == userID {
+			return true, nil
+		}
+	}
+	for _, t := range rule.Teams {
+		isMember, err := IsTeamMember(t.OrgID, t.ID, userID)
+		if err != nil {
+			return false, err
+		}
+		if isMember {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HasOwners returns true if any users or teams own the matching paths
+func (rule *CodeOwnerRule) HasOwners() bool {
+	return len(rule.Users) > 0 || len(rule.Teams) > 0
+}
+
+// GetCodeOwnerRuleForPath returns the last rule matching the path or nil if no rule matches
+func GetCodeOwnerRuleForPath(rules []*CodeOwnerRule, path string) *CodeOwnerRule {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Match(path) {
+			return rules[i]
+		}
+	}
+	return nil
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file of the repository. Owners are referenced by
+// "@username", "@org/team" or the email address of the user. Teams have to belong to the owner of the
+// repository. Lines which can't be parsed and unknown owners are skipped and returned as warnings.
+func ParseCodeOwners(repo *Repository, content string) ([]*CodeOwnerRule, []string) {
+	rules := make([]*CodeOwnerRule, 0, 10)
+	var warnings []string
+
+	for i, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule, err := codeOwnerPatternToRegexp(fields[0])
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("line %d: invalid pattern %q: %v", i+1, fields[0], err))
+			continue
+		}
+
+		codeOwnerRule := &CodeOwnerRule{
+			Pattern: fields[0],
+			Rule:    rule,
+		}
+		for _, owner := range fields[1:] {
+			if err := codeOwnerRule.addOwner(repo, owner); err != nil {
+				warnings = append(warnings, fmt.Sprintf("line %d: %v", i+1, err))
+			}
+		}
+		rules = append(rules, codeOwnerRule)
+	}
+
+	return rules, warnings
+}
+
+func (rule *CodeOwnerRule) addOwner(repo *Repository, owner string) error {
+	if !strings.HasPrefix(owner, "@") {
+		u, err := GetUserByEmail(owner)
+		if err != nil {
+			return fmt.Errorf("unknown owner %q: %v", owner, err)
+		}
+		rule.Users = append(rule.Users, u)
+		return nil
+	}
+
+	parts := strings.SplitN(owner[1:], "/", 2)
+	if len(parts) == 1 {
+		u, err := GetUserByName(parts[0])
+		if err != nil {
+			return fmt.Errorf("unknown owner %q: %v", owner, err)
+		}
+		rule.Users = append(rule.Users, u)
+		return nil
+	}
+
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+	if !repo.Owner.IsOrganization() || !strings.EqualFold(repo.Owner.Name, parts[0]) {
+		return fmt.Errorf("team %q does not belong to the repository owner", owner)
+	}
+	t, err := GetTeam(repo.OwnerID, parts[1])
+	if err != nil {
+		return fmt.Errorf("unknown team %q: %v", owner, err)
+	}
+	rule.Teams = append(rule.Teams, t)
+	return nil
+}
+
+// codeOwnerPatternToRegexp converts a gitignore style pattern to a regular expression matching the paths
+// of the files it applies to. Patterns containing a slash other than a trailing one are relative to the
+// root of the repository, all others match at any depth. A pattern matching a directory matches all its contents.
+func codeOwnerPatternToRegexp(pattern string) (*regexp.Regexp, error) {
+	isDir := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	isAnchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !isAnchored {
+		sb.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					sb.WriteString("(.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if isDir {
+		sb.WriteString("/.*$")
+	} else {
+		sb.WriteString("(/.*)?$")
+	}
+
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodeOwnerPatternToRegexp(t *testing.T) {
+	kases := []struct {
+		pattern  string
+		matches  []string
+		excludes []string
+	}{
+		{"*", []string{"README.md", "a/b/c.go"}, nil},
+		{"*.go", []string{"main.go", "cmd/web.go"}, []string{"main.golang", "go/README.md"}},
+		{"/build/", []string{"build/Makefile", "build/a/b"}, []string{"build", "src/build/Makefile"}},
+		{"docs/", []string{"docs/index.md", "web/docs/index.md"}, []string{"docs"}},
+		{"docs", []string{"docs", "docs/index.md", "web/docs/index.md"}, []string{"mydocs/index.md"}},
+		{"/src/*.js", []string{"src/app.js"}, []string{"src/lib/app.js", "web/src/app.js"}},
+		{"src/**/test?.go", []string{"src/test1.go", "src/a/b/testA.go"}, []string{"src/test10.go", "web/src/test1.go"}},
+		{"**/models", []string{"models/user.go", "a/models/user.go"}, []string{"amodels/user.go"}},
+	}
+	for _, kase := range kases {
+		rule, err := codeOwnerPatternToRegexp(kase.pattern)
+		assert.NoError(t, err)
+		for _, path := range kase.matches {
+			assert.True(t, rule.MatchString(path), "%s should match %s", kase.pattern, path)
+		}
+		for _, path := range kase.excludes {
+			assert.False(t, rule.MatchString(path), "%s should not match %s", kase.pattern, path)
+		}
+	}
+
+	_, err := codeOwnerPatternToRegexp("/")
+	assert.Error(t, err)
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	rules, warnings := ParseCodeOwners(repo, `# comment
+*           @user2 @unknown
+*.go        user4@example.com @user3/team1 # go code
+/vendor/
+docs/       @user5 @org/team1 @user3/unknown
+`)
+	assert.Len(t, warnings, 3)
+	assert.Len(t, rules, 4)
+
+	rule := GetCodeOwnerRuleForPath(rules, "README.md")
+	assert.Equal(t, "*", rule.Pattern)
+	assert.Len(t, rule.Users, 1)
+	assert.EqualValues(t, 2, rule.Users[0].ID)
+
+	// The last matching rule wins
+	rule = GetCodeOwnerRuleForPath(rules, "models/user.go")
+	assert.Equal(t, "*.go", rule.Pattern)
+	assert.True(t, rule.HasOwners())
+	for userID, isOwner := range map[int64]bool{2: true, 4: true, 5: false} {
+		result, err := rule.IsOwner(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, isOwner, result)
+	}
+
+	rule = GetCodeOwnerRuleForPath(rules, "vendor/modules.txt")
+	assert.False(t, rule.HasOwners())
+	rule = GetCodeOwnerRuleForPath(rules, "docs/index.go")
+	assert.Equal(t, "docs/", rule.Pattern)
+
+	assert.Nil(t, GetCodeOwnerRuleForPath(rules[2:], "README.md"))
+}
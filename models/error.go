@@ -178,6 +178,56 @@ func (err ErrUserInactive) Error() string {
 	return fmt.Sprintf("user is inactive [uid: %d, name: %s]", err.UID, err.Name)
 }
 
+// ErrBlockedByUser represents a "BlockedByUser" kind of error.
+type ErrBlockedByUser struct {
+	BlockerID int64
+	UserID    int64
+}
+
+// IsErrBlockedByUser checks if an error is a ErrBlockedByUser.
+func IsErrBlockedByUser(err error) bool {
+	_, ok := err.(ErrBlockedByUser)
+	return ok
+}
+
+func (err ErrBlockedByUser) Error() string {
+	return fmt.Sprintf("user is blocked [blocker_id: %d, uid: %d]", err.BlockerID, err.UserID)
+}
+
+// ErrCannotBlockUser represents a "CannotBlockUser" kind of error.
+type ErrCannotBlockUser struct {
+	BlockerID int64
+	BlockeeID int64
+	Reason    string
+}
+
+// IsErrCannotBlockUser checks if an error is a ErrCannotBlockUser.
+func IsErrCannotBlockUser(err error) bool {
+	_, ok := err.(ErrCannotBlockUser)
+	return ok
+}
+
+func (err ErrCannotBlockUser) Error() string {
+	return fmt.Sprintf("user can not be blocked, %s [blocker_id: %d, blockee_id: %d]", err.Reason, err.BlockerID, err.BlockeeID)
+}
+
+// ErrProbationLimit represents a "ProbationLimit" kind of error.
+type ErrProbationLimit struct {
+	UserID int64
+	Kind   string
+	Limit  int
+}
+
+// IsErrProbationLimit checks if an error is a ErrProbationLimit.
+func IsErrProbationLimit(err error) bool {
+	_, ok := err.(ErrProbationLimit)
+	return ok
+}
+
+func (err ErrProbationLimit) Error() string {
+	return fmt.Sprintf("user in probation reached the %s creation limit [uid: %d, limit: %d]", err.Kind, err.UserID, err.Limit)
+}
+
 // ErrEmailAlreadyUsed represents a "EmailAlreadyUsed" kind of error.
 type ErrEmailAlreadyUsed struct {
 	Email string
@@ -223,6 +273,21 @@ func (err ErrUserOwnRepos) Error() string {
 	return fmt.Sprintf("user still has ownership of repositories [uid: %d]", err.UID)
 }
 
+// ErrUserOwnPackages represents a "UserOwnPackages" kind of error.
+type ErrUserOwnPackages struct {
+	UID int64
+}
+
+// IsErrUserOwnPackages checks if an error is a ErrUserOwnPackages.
+func IsErrUserOwnPackages(err error) bool {
+	_, ok := err.(ErrUserOwnPackages)
+	return ok
+}
+
+func (err ErrUserOwnPackages) Error() string {
+	return fmt.Sprintf("user still has ownership of packages [uid: %d]", err.UID)
+}
+
 // ErrUserHasOrgs represents a "UserHasOrgs" kind of error.
 type ErrUserHasOrgs struct {
 	UID int64
@@ -356,6 +421,21 @@ func (err ErrKeyNotExist) Error() string {
 	return fmt.Sprintf("public key does not exist [id: %d]", err.ID)
 }
 
+// ErrCertificateNotAuthorized represents a "CertificateNotAuthorized" kind of error.
+type ErrCertificateNotAuthorized struct {
+	Reason string
+}
+
+// IsErrCertificateNotAuthorized checks if an error is a ErrCertificateNotAuthorized.
+func IsErrCertificateNotAuthorized(err error) bool {
+	_, ok := err.(ErrCertificateNotAuthorized)
+	return ok
+}
+
+func (err ErrCertificateNotAuthorized) Error() string {
+	return fmt.Sprintf("SSH certificate is not authorized [reason: %s]", err.Reason)
+}
+
 // ErrKeyAlreadyExist represents a "KeyAlreadyExist" kind of error.
 type ErrKeyAlreadyExist struct {
 	OwnerID     int64
@@ -586,6 +666,21 @@ func (err ErrAccessTokenEmpty) Error() string {
 	return "access token is empty"
 }
 
+// ErrAccessTokenScopeInvalid represents a "AccessTokenScopeInvalid" kind of error.
+type ErrAccessTokenScopeInvalid struct {
+	Scope string
+}
+
+// IsErrAccessTokenScopeInvalid checks if an error is a ErrAccessTokenScopeInvalid.
+func IsErrAccessTokenScopeInvalid(err error) bool {
+	_, ok := err.(ErrAccessTokenScopeInvalid)
+	return ok
+}
+
+func (err ErrAccessTokenScopeInvalid) Error() string {
+	return fmt.Sprintf("access token scope is invalid [scope: %s]", err.Scope)
+}
+
 // ________                            .__                __  .__
 // \_____  \_______  _________    ____ |__|____________ _/  |_|__| ____   ____
 //  /   |   \_  __ \/ ___\__  \  /    \|  \___   /\__  \\   __\  |/  _ \ /    \
@@ -727,6 +822,22 @@ func (err ErrRepoNotExist) Error() string {
 		err.ID, err.UID, err.OwnerName, err.Name)
 }
 
+// ErrPushMirrorNotExist represents a "PushMirrorNotExist" kind of error.
+type ErrPushMirrorNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrPushMirrorNotExist checks if an error is a ErrPushMirrorNotExist.
+func IsErrPushMirrorNotExist(err error) bool {
+	_, ok := err.(ErrPushMirrorNotExist)
+	return ok
+}
+
+func (err ErrPushMirrorNotExist) Error() string {
+	return fmt.Sprintf("push mirror does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
 // ErrRepoAlreadyExist represents a "RepoAlreadyExist" kind of error.
 type ErrRepoAlreadyExist struct {
 	Uname string
@@ -855,6 +966,51 @@ func (err ErrInvalidTagName) Error() string {
 	return fmt.Sprintf("release tag name is not valid [tag_name: %s]", err.TagName)
 }
 
+// ErrProtectedTagName represents a "ProtectedTagName" kind of error.
+type ErrProtectedTagName struct {
+	TagName string
+}
+
+// IsErrProtectedTagName checks if an error is a ErrProtectedTagName.
+func IsErrProtectedTagName(err error) bool {
+	_, ok := err.(ErrProtectedTagName)
+	return ok
+}
+
+func (err ErrProtectedTagName) Error() string {
+	return fmt.Sprintf("release tag name is protected [tag_name: %s]", err.TagName)
+}
+
+// ErrProtectedTagNotExist represents a "ProtectedTagNotExist" kind of error.
+type ErrProtectedTagNotExist struct {
+	ID int64
+}
+
+// IsErrProtectedTagNotExist checks if an error is a ErrProtectedTagNotExist.
+func IsErrProtectedTagNotExist(err error) bool {
+	_, ok := err.(ErrProtectedTagNotExist)
+	return ok
+}
+
+func (err ErrProtectedTagNotExist) Error() string {
+	return fmt.Sprintf("protected tag does not exist [id: %d]", err.ID)
+}
+
+// ErrProtectedTagPatternInvalid represents a "ProtectedTagPatternInvalid" kind of error.
+type ErrProtectedTagPatternInvalid struct {
+	Pattern string
+}
+
+// IsErrProtectedTagPatternInvalid checks if an error is a ErrProtectedTagPatternInvalid.
+func IsErrProtectedTagPatternInvalid(err error) bool {
+	_, ok := err.(ErrProtectedTagPatternInvalid)
+	return ok
+}
+
+func (err ErrProtectedTagPatternInvalid) Error() string {
+	return fmt.Sprintf("protected tag name pattern is not valid [pattern: %s]", err.Pattern)
+}
+
 // ErrRepoFileAlreadyExists represents a "RepoFileAlreadyExist" kind of error.
 type ErrRepoFileAlreadyExists struct {
 	Path string
@@ -956,6 +1112,38 @@ func (err ErrFilePathProtected) Error() string {
 	return fmt.Sprintf("path is protected and can not be changed [path: %s]", err.Path)
 }
 
+// ErrSuggestionOutdated represents a "SuggestionOutdated" kind of error.
+type ErrSuggestionOutdated struct {
+	TreePath string
+	Line     int64
+}
+
+// IsErrSuggestionOutdated checks if an error is an ErrSuggestionOutdated.
+func IsErrSuggestionOutdated(err error) bool {
+	_, ok := err.(ErrSuggestionOutdated)
+	return ok
+}
+
+func (err ErrSuggestionOutdated) Error() string {
+	return fmt.Sprintf("suggested lines have changed since the suggestion was made [path: %s, line: %d]", err.TreePath, err.Line)
+}
+
+// ErrSuggestionsOverlap represents a "SuggestionsOverlap" kind of error.
+type ErrSuggestionsOverlap struct {
+	TreePath string
+	Line     int64
+}
+
+// IsErrSuggestionsOverlap checks if an error is an ErrSuggestionsOverlap.
+func IsErrSuggestionsOverlap(err error) bool {
+	_, ok := err.(ErrSuggestionsOverlap)
+	return ok
+}
+
+func (err ErrSuggestionsOverlap) Error() string {
+	return fmt.Sprintf("suggestions change overlapping lines [path: %s, line: %d]", err.TreePath, err.Line)
+}
+
 // ErrUserDoesNotHaveAccessToRepo represets an error where the user doesn't has access to a given repo.
 type ErrUserDoesNotHaveAccessToRepo struct {
 	UserID   int64
@@ -1210,6 +1398,39 @@ func (err ErrIssueIsClosed) Error() string {
 	return fmt.Sprintf("issue is closed [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
 }
 
+// ErrIssueRedirectNotExist represents a "IssueRedirectNotExist" kind of error.
+type ErrIssueRedirectNotExist struct {
+	RepoID int64
+	Index  int64
+}
+
+// IsErrIssueRedirectNotExist checks if an error is a ErrIssueRedirectNotExist.
+func IsErrIssueRedirectNotExist(err error) bool {
+	_, ok := err.(ErrIssueRedirectNotExist)
+	return ok
+}
+
+func (err ErrIssueRedirectNotExist) Error() string {
+	return fmt.Sprintf("issue redirect does not exist [repo_id: %d, index: %d]", err.RepoID, err.Index)
+}
+
+// ErrCannotTransferIssue represents a "CannotTransferIssue" kind of error.
+type ErrCannotTransferIssue struct {
+	IssueID int64
+	RepoID  int64
+	Reason  string
+}
+
+// IsErrCannotTransferIssue checks if an error is a ErrCannotTransferIssue.
+func IsErrCannotTransferIssue(err error) bool {
+	_, ok := err.(ErrCannotTransferIssue)
+	return ok
+}
+
+func (err ErrCannotTransferIssue) Error() string {
+	return fmt.Sprintf("issue cannot be transferred [issue_id: %d, repo_id: %d, reason: %s]", err.IssueID, err.RepoID, err.Reason)
+}
+
 // ErrIssueLabelTemplateLoad represents a "ErrIssueLabelTemplateLoad" kind of error.
 type ErrIssueLabelTemplateLoad struct {
 	TemplateFile  string
@@ -1465,6 +1686,36 @@ func (err ErrPullRequestHasMerged) Error() string {
 		err.ID, err.IssueID, err.HeadRepoID, err.BaseRepoID, err.HeadBranch, err.BaseBranch)
 }
 
+// ErrPullAutoMergeAlreadyScheduled represents a "PullAutoMergeAlreadyScheduled"-error
+type ErrPullAutoMergeAlreadyScheduled struct {
+	PullID int64
+}
+
+// IsErrPullAutoMergeAlreadyScheduled checks if an error is a ErrPullAutoMergeAlreadyScheduled.
+func IsErrPullAutoMergeAlreadyScheduled(err error) bool {
+	_, ok := err.(ErrPullAutoMergeAlreadyScheduled)
+	return ok
+}
+
+func (err ErrPullAutoMergeAlreadyScheduled) Error() string {
+	return fmt.Sprintf("pull request is already scheduled to be merged automatically [pull_id: %d]", err.PullID)
+}
+
+// ErrPullAutoMergeNotExist represents a "PullAutoMergeNotExist"-error
+type ErrPullAutoMergeNotExist struct {
+	PullID int64
+}
+
+// IsErrPullAutoMergeNotExist checks if an error is a ErrPullAutoMergeNotExist.
+func IsErrPullAutoMergeNotExist(err error) bool {
+	_, ok := err.(ErrPullAutoMergeNotExist)
+	return ok
+}
+
+func (err ErrPullAutoMergeNotExist) Error() string {
+	return fmt.Sprintf("pull request is not scheduled to be merged automatically [pull_id: %d]", err.PullID)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -1488,6 +1739,22 @@ func (err ErrCommentNotExist) Error() string {
 	return fmt.Sprintf("comment does not exist [id: %d, issue_id: %d]", err.ID, err.IssueID)
 }
 
+// ErrInvalidCommentRange represents a "InvalidCommentRange" kind of error.
+type ErrInvalidCommentRange struct {
+	StartLine int64
+	Line      int64
+}
+
+// IsErrInvalidCommentRange checks if an error is a ErrInvalidCommentRange.
+func IsErrInvalidCommentRange(err error) bool {
+	_, ok := err.(ErrInvalidCommentRange)
+	return ok
+}
+
+func (err ErrInvalidCommentRange) Error() string {
+	return fmt.Sprintf("invalid range of commented lines [start_line: %d, line: %d]", err.StartLine, err.Line)
+}
+
 //  _________ __                                __         .__
 //  /   _____//  |_  ____ ________  _  _______ _/  |_  ____ |  |__
 //  \_____  \\   __\/  _ \\____ \ \/ \/ /\__  \\   __\/ ___\|  |  \
@@ -1624,6 +1891,38 @@ func (err ErrProjectBoardNotExist) Error() string {
 	return fmt.Sprintf("project board does not exist [id: %d]", err.BoardID)
 }
 
+// ErrProjectIssueNotExist represents a "ProjectIssueNotExist" kind of error.
+type ErrProjectIssueNotExist struct {
+	ProjectID int64
+	IssueID   int64
+}
+
+// IsErrProjectIssueNotExist checks if an error is a ErrProjectIssueNotExist
+func IsErrProjectIssueNotExist(err error) bool {
+	_, ok := err.(ErrProjectIssueNotExist)
+	return ok
+}
+
+func (err ErrProjectIssueNotExist) Error() string {
+	return fmt.Sprintf("issue is not on the project board [project_id: %d, issue_id: %d]", err.ProjectID, err.IssueID)
+}
+
+// ErrProjectIssueNotAllowed represents a "ProjectIssueNotAllowed" kind of error.
+type ErrProjectIssueNotAllowed struct {
+	ProjectID int64
+	IssueID   int64
+}
+
+// IsErrProjectIssueNotAllowed checks if an error is a ErrProjectIssueNotAllowed
+func IsErrProjectIssueNotAllowed(err error) bool {
+	_, ok := err.(ErrProjectIssueNotAllowed)
+	return ok
+}
+
+func (err ErrProjectIssueNotAllowed) Error() string {
+	return fmt.Sprintf("issue cannot be added to the project board of another repository [project_id: %d, issue_id: %d]", err.ProjectID, err.IssueID)
+}
+
 //    _____  .__.__                   __
 //   /     \ |__|  |   ____   _______/  |_  ____   ____   ____
 //  /  \ /  \|  |  | _/ __ \ /  ___/\   __\/  _ \ /    \_/ __ \
@@ -1856,18 +2155,22 @@ func (err ErrExternalLoginUserNotExist) Error() string {
 // |______/ \_______ \___  /    |____|_  /\___  >___  /|__/____  > |__|  |__|  (____  /__| |__|\____/|___|  /
 // \/   \/            \/     \/_____/         \/                   \/                    \/
 
-// ErrU2FRegistrationNotExist represents a "ErrU2FRegistrationNotExist" kind of error.
-type ErrU2FRegistrationNotExist struct {
-	ID int64
+// ErrWebAuthnCredentialNotExist represents a "ErrWebAuthnCredentialNotExist" kind of error.
+type ErrWebAuthnCredentialNotExist struct {
+	ID           int64
+	CredentialID string
 }
 
-func (err ErrU2FRegistrationNotExist) Error() string {
-	return fmt.Sprintf("U2F registration does not exist [id: %d]", err.ID)
+func (err ErrWebAuthnCredentialNotExist) Error() string {
+	if err.CredentialID == "" {
+		return fmt.Sprintf("WebAuthn credential does not exist [id: %d]", err.ID)
+	}
+	return fmt.Sprintf("WebAuthn credential does not exist [credential_id: %s]", err.CredentialID)
 }
 
-// IsErrU2FRegistrationNotExist checks if an error is a ErrU2FRegistrationNotExist.
-func IsErrU2FRegistrationNotExist(err error) bool {
-	_, ok := err.(ErrU2FRegistrationNotExist)
+// IsErrWebAuthnCredentialNotExist checks if an error is a ErrWebAuthnCredentialNotExist.
+func IsErrWebAuthnCredentialNotExist(err error) bool {
+	_, ok := err.(ErrWebAuthnCredentialNotExist)
 	return ok
 }
 
@@ -1978,6 +2281,23 @@ func (err ErrReviewNotExist) Error() string {
 	return fmt.Sprintf("review does not exist [id: %d]", err.ID)
 }
 
+// ErrNotValidReviewRequest an not allowed review request modify
+type ErrNotValidReviewRequest struct {
+	Reason string
+	UserID int64
+	RepoID int64
+}
+
+// IsErrNotValidReviewRequest checks if an error is a ErrNotValidReviewRequest.
+func IsErrNotValidReviewRequest(err error) bool {
+	_, ok := err.(ErrNotValidReviewRequest)
+	return ok
+}
+
+func (err ErrNotValidReviewRequest) Error() string {
+	return fmt.Sprintf("%s [user_id: %d, repo_id: %d]", err.Reason, err.UserID, err.RepoID)
+}
+
 //  ________      _____          __  .__
 //  \_____  \    /  _  \  __ ___/  |_|  |__
 //   /   |   \  /  /_\  \|  |  \   __\  |  \
@@ -2016,3 +2336,109 @@ func IsErrOAuthApplicationNotFound(err error) bool {
 func (err ErrOAuthApplicationNotFound) Error() string {
 	return fmt.Sprintf("OAuth application not found [ID: %d]", err.ID)
 }
+
+// __________                __
+// \______   \_____    ____ |  | _______     ____   ____
+//  |     ___/\__  \ _/ ___\|  |/ /\__  \   / ___\_/ __ \
+//  |    |     / __ \\  \___|    <  / __ \_/ /_/  >  ___/
+//  |____|    (____  /\___  >__|_ \(____  /\___  / \___  >
+//                 \/     \/     \/     \//_____/      \/
+
+// ErrPackageNotExist represents a "PackageNotExist" kind of error.
+type ErrPackageNotExist struct {
+	ID      int64
+	OwnerID int64
+	Type    PackageType
+	Name    string
+}
+
+// IsErrPackageNotExist checks if an error is a ErrPackageNotExist.
+func IsErrPackageNotExist(err error) bool {
+	_, ok := err.(ErrPackageNotExist)
+	return ok
+}
+
+func (err ErrPackageNotExist) Error() string {
+	return fmt.Sprintf("package does not exist [id: %d, owner_id: %d, type: %s, name: %s]", err.ID, err.OwnerID, err.Type, err.Name)
+}
+
+// ErrPackageVersionNotExist represents a "PackageVersionNotExist" kind of error.
+type ErrPackageVersionNotExist struct {
+	ID        int64
+	PackageID int64
+	Version   string
+}
+
+// IsErrPackageVersionNotExist checks if an error is a ErrPackageVersionNotExist.
+func IsErrPackageVersionNotExist(err error) bool {
+	_, ok := err.(ErrPackageVersionNotExist)
+	return ok
+}
+
+func (err ErrPackageVersionNotExist) Error() string {
+	return fmt.Sprintf("package version does not exist [id: %d, package_id: %d, version: %s]", err.ID, err.PackageID, err.Version)
+}
+
+// ErrPackageFileNotExist represents a "PackageFileNotExist" kind of error.
+type ErrPackageFileNotExist struct {
+	ID        int64
+	VersionID int64
+	Name      string
+}
+
+// IsErrPackageFileNotExist checks if an error is a ErrPackageFileNotExist.
+func IsErrPackageFileNotExist(err error) bool {
+	_, ok := err.(ErrPackageFileNotExist)
+	return ok
+}
+
+func (err ErrPackageFileNotExist) Error() string {
+	return fmt.Sprintf("package file does not exist [id: %d, version_id: %d, name: %s]", err.ID, err.VersionID, err.Name)
+}
+
+// ErrPackageFileAlreadyExist represents a "PackageFileAlreadyExist" kind of error.
+type ErrPackageFileAlreadyExist struct {
+	VersionID int64
+	Name      string
+}
+
+// IsErrPackageFileAlreadyExist checks if an error is a ErrPackageFileAlreadyExist.
+func IsErrPackageFileAlreadyExist(err error) bool {
+	_, ok := err.(ErrPackageFileAlreadyExist)
+	return ok
+}
+
+func (err ErrPackageFileAlreadyExist) Error() string {
+	return fmt.Sprintf("package file already exists [version_id: %d, name: %s]", err.VersionID, err.Name)
+}
+
+// ErrPackageBlobNotExist represents a "PackageBlobNotExist" kind of error.
+type ErrPackageBlobNotExist struct {
+	ID         int64
+	HashSHA256 string
+}
+
+// IsErrPackageBlobNotExist checks if an error is a ErrPackageBlobNotExist.
+func IsErrPackageBlobNotExist(err error) bool {
+	_, ok := err.(ErrPackageBlobNotExist)
+	return ok
+}
+
+func (err ErrPackageBlobNotExist) Error() string {
+	return fmt.Sprintf("package blob does not exist [id: %d, sha256: %s]", err.ID, err.HashSHA256)
+}
+
+// ErrPackageBlobUploadNotExist represents a "PackageBlobUploadNotExist" kind of error.
+type ErrPackageBlobUploadNotExist struct {
+	ID string
+}
+
+// IsErrPackageBlobUploadNotExist checks if an error is a ErrPackageBlobUploadNotExist.
+func IsErrPackageBlobUploadNotExist(err error) bool {
+	_, ok := err.(ErrPackageBlobUploadNotExist)
+	return ok
+}
+
+func (err ErrPackageBlobUploadNotExist) Error() string {
+	return fmt.Sprintf("package blob upload does not exist [id: %s]", err.ID)
+}
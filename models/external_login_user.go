@@ -102,7 +102,7 @@ func GetUserIDByExternalUserID(provider string, userID string) (int64, error) {
 
 // UpdateExternalUser updates external user's information
 func UpdateExternalUser(user *User, gothUser goth.User) error {
-	loginSource, err := GetActiveOAuth2LoginSourceByName(gothUser.Provider)
+	loginSource, err := GetActiveExternalLoginSourceByName(gothUser.Provider)
 	if err != nil {
 		return err
 	}
@@ -383,6 +383,7 @@ type CommitVerification struct {
 	CommittingUser *User
 	SigningEmail   string
 	SigningKey     *GPGKey
+	SigningSSHKey  *PublicKey
 	TrustStatus    string
 }
 
@@ -646,6 +647,10 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 	}
 
+	if IsSSHSignature(c.Signature.Signature) {
+		return parseCommitWithSSHSignature(c, committer)
+	}
+
 	//Parsing signature
 	sig, err := extractSignature(c.Signature.Signature)
 	if err != nil { //Skipping failed to extract sign
@@ -715,7 +720,8 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 	}
 
-	if setting.Repository.Signing.SigningKey != "" && setting.Repository.Signing.SigningKey != "default" && setting.Repository.Signing.SigningKey != "none" {
+	if setting.Repository.Signing.SigningFormat != git.SigningFormatSSH && setting.Repository.Signing.SigningKey != "" &&
+		setting.Repository.Signing.SigningKey != "default" && setting.Repository.Signing.SigningKey != "none" {
 		// OK we should try the default key
 		gpgSettings := git.GPGSettings{
 			Sign:  true,
@@ -739,7 +745,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		log.Error("Error getting default public gpg key: %v", err)
 	} else if defaultGPGSettings == nil {
 		log.Warn("Unable to get defaultGPGSettings for unattached commit: %s", c.ID.String())
-	} else if defaultGPGSettings.Sign {
+	} else if defaultGPGSettings.Sign && defaultGPGSettings.Format != git.SigningFormatSSH {
 		if commitVerification := verifyWithGPGSettings(defaultGPGSettings, sig, c.Signature.Payload, committer, keyID); commitVerification != nil {
 			if commitVerification.Reason == BadSignature {
 				defaultReason = BadSignature
@@ -892,11 +898,17 @@ func CalculateTrustStatus(verification *CommitVerification, repository *Reposito
 
 	var isMember bool
 	if keyMap != nil {
+		keyID := ""
+		if verification.SigningKey != nil {
+			keyID = verification.SigningKey.KeyID
+		} else if verification.SigningSSHKey != nil {
+			keyID = verification.SigningSSHKey.Fingerprint
+		}
 		var has bool
-		isMember, has = (*keyMap)[verification.SigningKey.KeyID]
+		isMember, has = (*keyMap)[keyID]
 		if !has {
 			isMember, err = repository.IsOwnerMemberCollaborator(verification.SigningUser.ID)
-			(*keyMap)[verification.SigningKey.KeyID] = isMember
+			(*keyMap)[keyID] = isMember
 		}
 	} else {
 		isMember, err = repository.IsOwnerMemberCollaborator(verification.SigningUser.ID)
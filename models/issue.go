@@ -860,6 +860,10 @@ type NewIssueOptions struct {
 func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 	opts.Issue.Title = strings.TrimSpace(opts.Issue.Title)
 
+	if err = checkProbationLimit(e, doer, ProbationLimitIssue); err != nil {
+		return err
+	}
+
 	if opts.Issue.MilestoneID > 0 {
 		milestone, err := getMilestoneByRepoID(e, opts.Issue.RepoID, opts.Issue.MilestoneID)
 		if err != nil && !IsErrMilestoneNotExist(err) {
@@ -874,8 +878,18 @@ func newIssue(e *xorm.Session, doer *User, opts NewIssueOptions) (err error) {
 		}
 	}
 
+	// Indexes of issues which have been transferred away are not reused
+	maxRedirectIndex, err := getMaxIssueRedirectIndex(e, opts.Issue.RepoID)
+	if err != nil {
+		return err
+	}
+	indexExpr := "coalesce(MAX(`index`),0)+1"
+	if maxRedirectIndex > 0 {
+		indexExpr = fmt.Sprintf("CASE WHEN coalesce(MAX(`index`),0) > %[1]d THEN MAX(`index`) ELSE %[1]d END+1", maxRedirectIndex)
+	}
+
 	// Milestone validation should happen before insert actual object.
-	if _, err := e.SetExpr("`index`", "coalesce(MAX(`index`),0)+1").
+	if _, err := e.SetExpr("`index`", indexExpr).
 		Where("repo_id=?", opts.Issue.RepoID).
 		Insert(opts.Issue); err != nil {
 		return ErrNewIssueInsert{err}
@@ -995,7 +1009,7 @@ func newIssueAttempt(repo *Repository, issue *Issue, labelIDs []int64, uuids []s
 		LabelIDs:    labelIDs,
 		Attachments: uuids,
 	}); err != nil {
-		if IsErrUserDoesNotHaveAccessToRepo(err) || IsErrNewIssueInsert(err) {
+		if IsErrUserDoesNotHaveAccessToRepo(err) || IsErrNewIssueInsert(err) || IsErrProbationLimit(err) {
 			return err
 		}
 		return fmt.Errorf("newIssue: %v", err)
@@ -1182,7 +1196,12 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 		if opts.ProjectBoardID > 0 {
 			sess.In("issue.id", builder.Select("issue_id").From("project_issue").Where(builder.Eq{"project_board_id": opts.ProjectBoardID}))
 		} else {
-			sess.In("issue.id", builder.Select("issue_id").From("project_issue").Where(builder.Eq{"project_board_id": 0}))
+			cond := builder.Eq{"project_board_id": 0}
+			if opts.ProjectID > 0 {
+				// An issue may be on several projects, only the given one decides if it is uncategorized
+				cond["project_id"] = opts.ProjectID
+			}
+			sess.In("issue.id", builder.Select("issue_id").From("project_issue").Where(cond))
 		}
 	}
 
@@ -2002,6 +2021,11 @@ func deleteIssuesByRepoID(sess Engine, repoID int64) (attachmentPaths []string,
 		return
 	}
 
+	if _, err = sess.In("issue_id", deleteCond).
+		Delete(&IssueRedirect{}); err != nil {
+		return
+	}
+
 	var attachments []*Attachment
 	if err = sess.In("issue_id", deleteCond).
 		Find(&attachments); err != nil {
@@ -101,6 +101,14 @@ const (
 	CommentTypeProject
 	// Project board changed
 	CommentTypeProjectBoard
+	// Pull request scheduled to be merged automatically
+	CommentTypePRScheduledToAutoMerge
+	// Scheduled automatic merge of pull request cancelled
+	CommentTypePRUnScheduledToAutoMerge
+	// Issue transferred from another repository
+	CommentTypeIssueTransfer
+	// Issue transferred to another repository
+	CommentTypeIssueTransferOut
 )
 
 // CommentTag defines comment tag type
@@ -137,6 +145,8 @@ type Comment struct {
 	AssigneeID       int64
 	RemovedAssignee  bool
 	Assignee         *User `xorm:"-"`
+	AssigneeTeamID   int64 `xorm:"NOT NULL DEFAULT 0"`
+	AssigneeTeam     *Team `xorm:"-"`
 	ResolveDoerID    int64
 	ResolveDoer      *User `xorm:"-"`
 	OldTitle         string
@@ -148,6 +158,7 @@ type Comment struct {
 
 	CommitID        int64
 	Line            int64 // - previous line / + proposed line
+	StartLine       int64 `xorm:"NOT NULL DEFAULT 0"` // first line of a multi-line code comment, same sign as Line; 0 for a single line
 	TreePath        string
 	Content         string `xorm:"TEXT"`
 	RenderedContent string `xorm:"-"`
@@ -487,11 +498,11 @@ func (c *Comment) UpdateAttachments(uuids []string) error {
 	return sess.Commit()
 }
 
-// LoadAssigneeUser if comment.Type is CommentTypeAssignees, then load assignees
-func (c *Comment) LoadAssigneeUser() error {
+// LoadAssigneeUserAndTeam if comment.Type is CommentTypeAssignees or CommentTypeReviewRequest, then load assignees
+func (c *Comment) LoadAssigneeUserAndTeam() error {
 	var err error
 
-	if c.AssigneeID > 0 {
+	if c.AssigneeID > 0 && c.Assignee == nil {
 		c.Assignee, err = getUserByID(x, c.AssigneeID)
 		if err != nil {
 			if !IsErrUserNotExist(err) {
@@ -499,6 +510,14 @@ func (c *Comment) LoadAssigneeUser() error {
 			}
 			c.Assignee = NewGhostUser()
 		}
+	} else if c.AssigneeTeamID > 0 && c.AssigneeTeam == nil {
+		c.AssigneeTeam, err = GetTeamByID(c.AssigneeTeamID)
+		if err != nil {
+			if !IsErrTeamNotExist(err) {
+				return err
+			}
+			c.AssigneeTeam = &Team{Name: "Ghost Team"}
+		}
 	}
 	return nil
 }
@@ -611,6 +630,63 @@ func (c *Comment) UnsignedLine() uint64 {
 	return uint64(c.Line)
 }
 
+// IsMultiLine returns true if the code comment refers to a range of lines
+func (c *Comment) IsMultiLine() bool {
+	return c.StartLine != 0 && c.StartLine != c.Line
+}
+
+// UnsignedStartLine returns the first LOC of the code comment without + or -
+func (c *Comment) UnsignedStartLine() uint64 {
+	if !c.IsMultiLine() {
+		return c.UnsignedLine()
+	}
+	if c.StartLine < 0 {
+		return uint64(c.StartLine * -1)
+	}
+	return uint64(c.StartLine)
+}
+
+// CommentedLines returns the lines of the new version of the file a code comment refers to, as of the
+// commit it was made on. Comments on removed lines have no commented lines.
+func (c *Comment) CommentedLines() []string {
+	if c.Type != CommentTypeCode || c.Line <= 0 || len(c.Patch) == 0 {
+		return nil
+	}
+
+	// the patch of a code comment ends with the last commented line, walk it
+	// backwards collecting the lines of the new version
+	count := int(c.UnsignedLine()-c.UnsignedStartLine()) + 1
+	patchLines := strings.Split(strings.TrimRight(c.Patch, "\n"), "\n")
+	if last := patchLines[len(patchLines)-1]; len(last) == 0 || last[0] == '-' {
+		return nil
+	}
+	lines := make([]string, count)
+	for i := len(patchLines) - 1; i >= 0 && count > 0; i-- {
+		line := patchLines[i]
+		if len(line) == 0 || line[0] == '-' || line[0] == '\\' {
+			continue
+		}
+		if line[0] != '+' && line[0] != ' ' {
+			break
+		}
+		count--
+		lines[count] = line[1:]
+	}
+	if count > 0 {
+		return nil
+	}
+	return lines
+}
+
+// HasSuggestion returns true if the code comment suggests a change of the commented lines
+func (c *Comment) HasSuggestion() bool {
+	if len(c.CommentedLines()) == 0 {
+		return false
+	}
+	_, has := markdown.GetSuggestion([]byte(c.Content))
+	return has
+}
+
 // CodeCommentURL returns the url to a comment in code
 func (c *Comment) CodeCommentURL() string {
 	err := c.LoadIssue()
@@ -685,9 +761,11 @@ func createComment(e *xorm.Session, opts *CreateCommentOptions) (_ *Comment, err
 		ProjectID:        opts.ProjectID,
 		RemovedAssignee:  opts.RemovedAssignee,
 		AssigneeID:       opts.AssigneeID,
+		AssigneeTeamID:   opts.AssigneeTeamID,
 		CommitID:         opts.CommitID,
 		CommitSHA:        opts.CommitSHA,
 		Line:             opts.LineNum,
+		StartLine:        opts.StartLineNum,
 		Content:          opts.Content,
 		OldTitle:         opts.OldTitle,
 		NewTitle:         opts.NewTitle,
@@ -849,6 +927,7 @@ type CreateCommentOptions struct {
 	OldProjectID     int64
 	ProjectID        int64
 	AssigneeID       int64
+	AssigneeTeamID   int64
 	RemovedAssignee  bool
 	OldTitle         string
 	NewTitle         string
@@ -858,6 +937,7 @@ type CreateCommentOptions struct {
 	CommitSHA        string
 	Patch            string
 	LineNum          int64
+	StartLineNum     int64
 	TreePath         string
 	ReviewID         int64
 	Content          string
@@ -1112,8 +1192,8 @@ func fetchCodeCommentsByReview(e Engine, issue *Issue, currentUser *User, review
 			comment.Review = re
 		}
 
-		comment.RenderedContent = string(markdown.Render([]byte(comment.Content), issue.Repo.Link(),
-			issue.Repo.ComposeMetas()))
+		comment.RenderedContent = string(markdown.RenderCodeComment([]byte(comment.Content), issue.Repo.Link(),
+			issue.Repo.ComposeMetas(), comment.CommentedLines()))
 		if pathToLineToComment[comment.TreePath] == nil {
 			pathToLineToComment[comment.TreePath] = make(map[int64][]*Comment)
 		}
@@ -5,6 +5,7 @@
 package models
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -57,3 +58,30 @@ func TestFetchCodeComments(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, res, 1)
 }
+
+func TestComment_CommentedLines(t *testing.T) {
+	patch := "diff --git a/README.md b/README.md\n--- a/README.md\n+++ b/README.md\n@@ -1,2 +1,2 @@\n line one\n-old line\n+new line\n"
+	comment := &Comment{Type: CommentTypeCode, Line: 2, Patch: patch}
+	assert.Equal(t, []string{"new line"}, comment.CommentedLines())
+	assert.False(t, comment.HasSuggestion())
+
+	comment.Content = "Better:\n```suggestion\nnewer line\n```\n"
+	assert.True(t, comment.HasSuggestion())
+
+	comment.StartLine = 1
+	assert.True(t, comment.IsMultiLine())
+	assert.EqualValues(t, 1, comment.UnsignedStartLine())
+	assert.Equal(t, []string{"line one", "new line"}, comment.CommentedLines())
+
+	// the patch does not cover the whole range
+	comment.Patch = "@@ -2,1 +2,1 @@\n-old line\n+new line\n"
+	assert.Nil(t, comment.CommentedLines())
+	comment.StartLine = 0
+
+	// comments on removed lines can not carry suggestions
+	comment.Line = -2
+	assert.False(t, comment.IsMultiLine())
+	comment.Patch = strings.TrimSuffix(patch, "+new line\n")
+	assert.Nil(t, comment.CommentedLines())
+	assert.False(t, comment.HasSuggestion())
+}
@@ -148,6 +148,15 @@ func CreateReaction(opts *ReactionOptions) (*Reaction, error) {
 		return nil, err
 	}
 
+	if err := checkUserBlockedFromIssue(sess, opts.Doer.ID, opts.Issue); err != nil {
+		return nil, err
+	}
+	if opts.Comment != nil {
+		if err := checkUserBlockedBy(sess, opts.Doer.ID, opts.Comment.PosterID); err != nil {
+			return nil, err
+		}
+	}
+
 	reaction, err := createReaction(sess, opts)
 	if err != nil {
 		return reaction, err
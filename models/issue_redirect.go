@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueRedirect represents that an issue index of a repository should be redirected to an issue
+// which has been transferred to another repository
+type IssueRedirect struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OldRepoID   int64              `xorm:"UNIQUE(s)"`
+	OldIndex    int64              `xorm:"UNIQUE(s)"`
+	IssueID     int64              `xorm:"INDEX"` // issueID to redirect to
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// LookupIssueRedirect look up if an issue index of a repository has been redirected and returns the issue ID
+func LookupIssueRedirect(repoID, index int64) (int64, error) {
+	redirect := &IssueRedirect{OldRepoID: repoID, OldIndex: index}
+	if has, err := x.Get(redirect); err != nil {
+		return 0, err
+	} else if !has {
+		return 0, ErrIssueRedirectNotExist{RepoID: repoID, Index: index}
+	}
+	return redirect.IssueID, nil
+}
+
+// newIssueRedirect creates a redirect from the old index of a transferred issue
+func newIssueRedirect(e Engine, oldRepoID, oldIndex, issueID int64) error {
+	if err := deleteIssueRedirect(e, oldRepoID, oldIndex); err != nil {
+		return err
+	}
+
+	_, err := e.Insert(&IssueRedirect{
+		OldRepoID: oldRepoID,
+		OldIndex:  oldIndex,
+		IssueID:   issueID,
+	})
+	return err
+}
+
+// deleteIssueRedirect delete any redirect from the specified issue index to anything else
+func deleteIssueRedirect(e Engine, repoID, index int64) error {
+	_, err := e.Delete(&IssueRedirect{OldRepoID: repoID, OldIndex: index})
+	return err
+}
+
+// getMaxIssueRedirectIndex returns the highest index of the repository which redirects to a
+// transferred issue. New issues must get a higher index, otherwise the redirect would be
+// shadowed and old links would lead to a different issue.
+func getMaxIssueRedirectIndex(e Engine, repoID int64) (int64, error) {
+	var maxIndex int64
+	_, err := e.Table("issue_redirect").Where("old_repo_id=?", repoID).
+		Select("coalesce(MAX(old_index),0)").Get(&maxIndex)
+	return maxIndex, err
+}
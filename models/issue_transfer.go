@@ -0,0 +1,257 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// TransferIssue moves an issue with all its comments, reactions, attachments, tracked times
+// and subscriptions to another repository. The issue gets the next free index of the new
+// repository, labels and milestone are mapped by name and a redirect is left behind from
+// the old index.
+func TransferIssue(issue *Issue, doer *User, newRepo *Repository) (err error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = transferIssue(sess, issue, doer, newRepo); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+func transferIssue(e *xorm.Session, issue *Issue, doer *User, newRepo *Repository) (err error) {
+	if issue.IsPull {
+		return ErrCannotTransferIssue{IssueID: issue.ID, RepoID: newRepo.ID, Reason: "pull requests cannot be transferred"}
+	}
+	if issue.RepoID == newRepo.ID {
+		return ErrCannotTransferIssue{IssueID: issue.ID, RepoID: newRepo.ID, Reason: "issue already belongs to the repository"}
+	}
+	if err = issue.loadRepo(e); err != nil {
+		return err
+	}
+	oldRepo := issue.Repo
+	oldIndex := issue.Index
+
+	var maxIndex int64
+	if _, err = e.Table("issue").Where("repo_id=?", newRepo.ID).
+		Select("coalesce(MAX(`index`),0)").Get(&maxIndex); err != nil {
+		return err
+	}
+	// The new index must not be shadowed by an issue which was transferred away before
+	maxRedirectIndex, err := getMaxIssueRedirectIndex(e, newRepo.ID)
+	if err != nil {
+		return err
+	} else if maxRedirectIndex > maxIndex {
+		maxIndex = maxRedirectIndex
+	}
+
+	if err = transferIssueLabels(e, issue, newRepo); err != nil {
+		return fmt.Errorf("transferIssueLabels: %v", err)
+	}
+	issue.Labels = nil
+
+	oldMilestoneID := issue.MilestoneID
+	if oldMilestoneID > 0 {
+		issue.MilestoneID = 0
+		issue.Milestone = nil
+		oldMilestone, err := getMilestoneByRepoID(e, oldRepo.ID, oldMilestoneID)
+		if err != nil && !IsErrMilestoneNotExist(err) {
+			return err
+		} else if err == nil {
+			milestone := new(Milestone)
+			has, err := e.Where("repo_id=? AND name=?", newRepo.ID, oldMilestone.Name).Get(milestone)
+			if err != nil {
+				return err
+			} else if has {
+				issue.MilestoneID = milestone.ID
+				issue.Milestone = milestone
+			}
+		}
+	}
+
+	// Repository projects belong to the old repository, projects of users and organizations
+	// may hold issues of any repository and keep the issue
+	if _, err = e.Where(repoProjectIssueCond(issue.ID)).Delete(new(ProjectIssue)); err != nil {
+		return err
+	}
+
+	if err = issue.loadAssignees(e); err != nil {
+		return err
+	}
+	for _, assignee := range issue.Assignees {
+		canBeAssigned, err := canBeAssigned(e, assignee, newRepo, false)
+		if err != nil {
+			return err
+		}
+		if !canBeAssigned {
+			if _, err = e.Delete(&IssueAssignees{IssueID: issue.ID, AssigneeID: assignee.ID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = issue.loadAssignees(e); err != nil {
+		return err
+	}
+
+	oldRef := fmt.Sprintf("%s#%d", oldRepo.FullName(), oldIndex)
+	newRef := fmt.Sprintf("%s#%d", newRepo.FullName(), maxIndex+1)
+
+	// The transfer is recorded in the timeline of both repositories, first while the issue
+	// still belongs to the old one
+	if _, err = createComment(e, &CreateCommentOptions{
+		Type:   CommentTypeIssueTransferOut,
+		Doer:   doer,
+		Repo:   oldRepo,
+		Issue:  issue,
+		OldRef: oldRef,
+		NewRef: newRef,
+	}); err != nil {
+		return err
+	}
+
+	issue.RepoID = newRepo.ID
+	issue.Repo = newRepo
+	issue.Index = maxIndex + 1
+	if err = updateIssueCols(e, issue, "repo_id", "index", "milestone_id"); err != nil {
+		return err
+	}
+
+	if oldMilestoneID > 0 {
+		if err = updateMilestoneTotalNum(e, oldMilestoneID); err != nil {
+			return err
+		}
+		if err = updateMilestoneClosedNum(e, oldMilestoneID); err != nil {
+			return err
+		}
+	}
+	if issue.MilestoneID > 0 {
+		if err = updateMilestoneTotalNum(e, issue.MilestoneID); err != nil {
+			return err
+		}
+		if err = updateMilestoneClosedNum(e, issue.MilestoneID); err != nil {
+			return err
+		}
+	}
+
+	for _, repoID := range []int64{oldRepo.ID, newRepo.ID} {
+		if _, err = e.Exec("UPDATE `repository` SET num_issues=(SELECT count(*) FROM issue WHERE repo_id=? AND is_pull=?), num_closed_issues=(SELECT count(*) FROM issue WHERE repo_id=? AND is_pull=? AND is_closed=?) WHERE id=?",
+			repoID, false, repoID, false, true, repoID); err != nil {
+			return err
+		}
+	}
+
+	if _, err = e.Exec("UPDATE `notification` SET repo_id=? WHERE issue_id=?", newRepo.ID, issue.ID); err != nil {
+		return err
+	}
+	// References made from this issue point to the repository they were made in
+	if _, err = e.Exec("UPDATE `comment` SET ref_repo_id=? WHERE ref_issue_id=?", newRepo.ID, issue.ID); err != nil {
+		return err
+	}
+
+	if err = newIssueRedirect(e, oldRepo.ID, oldIndex, issue.ID); err != nil {
+		return err
+	}
+
+	if _, err = createComment(e, &CreateCommentOptions{
+		Type:   CommentTypeIssueTransfer,
+		Doer:   doer,
+		Repo:   newRepo,
+		Issue:  issue,
+		OldRef: oldRef,
+		NewRef: newRef,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CanReadIssueRef returns whether the user may read the issues of the repository of a reference
+// of the form owner/repo#index, as recorded by issue transfers. References to repositories which
+// no longer exist under that name can not be read.
+func CanReadIssueRef(ref string, user *User) (bool, error) {
+	fullName := ref
+	if i := strings.LastIndexByte(ref, '#'); i >= 0 {
+		fullName = ref[:i]
+	}
+	parts := strings.SplitN(fullName, "/", 2)
+	if len(parts) != 2 {
+		return false, nil
+	}
+
+	repo, err := GetRepositoryByOwnerAndName(parts[0], parts[1])
+	if err != nil {
+		if IsErrRepoNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	perm, err := GetUserRepoPermission(repo, user)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanRead(UnitTypeIssues), nil
+}
+
+// transferIssueLabels replaces the labels of the issue by the labels of the new repository
+// or its organization with the same names, dropping those which have no counterpart.
+func transferIssueLabels(e Engine, issue *Issue, newRepo *Repository) error {
+	labels, err := getLabelsByIssueID(e, issue.ID)
+	if err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+
+	candidates := make([]*Label, 0, 10)
+	if err = e.Where(builder.Eq{"repo_id": newRepo.ID}.Or(builder.Eq{"org_id": newRepo.OwnerID})).
+		Find(&candidates); err != nil {
+		return err
+	}
+	labelsByName := make(map[string]*Label, len(candidates))
+	for _, label := range candidates {
+		// Labels of the repository take precedence over those of the organization
+		if existing, ok := labelsByName[strings.ToLower(label.Name)]; ok && existing.RepoID > 0 {
+			continue
+		}
+		labelsByName[strings.ToLower(label.Name)] = label
+	}
+
+	changed := make([]*Label, 0, len(labels)*2)
+	for _, label := range labels {
+		if label.RepoID == newRepo.ID || (label.OrgID > 0 && label.OrgID == newRepo.OwnerID) {
+			continue
+		}
+		newLabel, ok := labelsByName[strings.ToLower(label.Name)]
+		if ok && !hasIssueLabel(e, issue.ID, newLabel.ID) {
+			if _, err = e.Where("issue_id=? AND label_id=?", issue.ID, label.ID).
+				Cols("label_id").Update(&IssueLabel{LabelID: newLabel.ID}); err != nil {
+				return err
+			}
+			changed = append(changed, newLabel)
+		} else if _, err = e.Delete(&IssueLabel{IssueID: issue.ID, LabelID: label.ID}); err != nil {
+			return err
+		}
+		changed = append(changed, label)
+	}
+
+	for _, label := range changed {
+		if err = updateLabelCols(e, label, "num_issues", "num_closed_issue"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferIssue(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	newRepo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	assert.NoError(t, ChangeMilestoneAssign(&Issue{ID: 1, RepoID: 1, MilestoneID: 2}, doer, 0))
+	issue.MilestoneID = 2
+	newLabel := &Label{RepoID: newRepo.ID, Name: "Label1", Color: "#123456"}
+	assert.NoError(t, NewLabel(newLabel))
+	newMilestone := &Milestone{RepoID: newRepo.ID, Name: "milestone2"}
+	assert.NoError(t, NewMilestone(newMilestone))
+
+	assert.NoError(t, TransferIssue(issue, doer, newRepo))
+
+	issue = AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.EqualValues(t, newRepo.ID, issue.RepoID)
+	assert.EqualValues(t, 2, issue.Index)
+	assert.EqualValues(t, newMilestone.ID, issue.MilestoneID)
+
+	AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: newLabel.ID})
+	AssertNotExistsBean(t, &IssueLabel{IssueID: issue.ID, LabelID: 1})
+	AssertExistsAndLoadBean(t, &Comment{IssueID: issue.ID, Type: CommentTypeIssueTransferOut,
+		OldRef: "user2/repo1#1", NewRef: "user3/repo3#2"})
+	AssertExistsAndLoadBean(t, &Comment{IssueID: issue.ID, Type: CommentTypeIssueTransfer,
+		OldRef: "user2/repo1#1", NewRef: "user3/repo3#2"})
+
+	issueID, err := LookupIssueRedirect(1, 1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, issue.ID, issueID)
+	_, err = LookupIssueRedirect(1, 2)
+	assert.True(t, IsErrIssueRedirectNotExist(err))
+
+	CheckConsistencyFor(t, &Repository{ID: 1}, &Repository{ID: newRepo.ID}, &Label{}, &Milestone{})
+
+	issue.Repo = nil
+	err = TransferIssue(issue, doer, newRepo)
+	assert.True(t, IsErrCannotTransferIssue(err))
+}
+
+func TestTransferIssue_LastIndex(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	newRepo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	issue := &Issue{RepoID: repo.ID, PosterID: doer.ID, Poster: doer, Title: "last issue"}
+	assert.NoError(t, NewIssue(repo, issue, nil, nil))
+	assert.EqualValues(t, 6, issue.Index)
+	assert.NoError(t, TransferIssue(issue, doer, newRepo))
+
+	// The index of the transferred issue is not given to the next issue
+	next := &Issue{RepoID: repo.ID, PosterID: doer.ID, Poster: doer, Title: "next issue"}
+	assert.NoError(t, NewIssue(repo, next, nil, nil))
+	assert.EqualValues(t, 7, next.Index)
+
+	issueID, err := LookupIssueRedirect(repo.ID, 6)
+	assert.NoError(t, err)
+	assert.EqualValues(t, issue.ID, issueID)
+
+	// Neither does an issue transferred back into the repository
+	assert.NoError(t, TransferIssue(issue, doer, repo))
+	assert.EqualValues(t, 8, issue.Index)
+}
+
+func TestCanReadIssueRef(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	test := func(ref string, user *User, expected bool) {
+		canRead, err := CanReadIssueRef(ref, user)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, canRead, ref)
+	}
+
+	test("user2/repo1#1", nil, true)
+	test("user3/repo3#1", nil, false)
+	test("user3/repo3#1", user2, true)
+	test("user2/doesnotexist#1", user2, false)
+	test("invalid", user2, false)
+}
@@ -5,6 +5,9 @@
 package models
 
 import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/structs"
 
 	"xorm.io/builder"
@@ -240,3 +243,129 @@ func UpdateReviewsMigrationsByType(tp structs.GitServiceType, originalAuthorID s
 		})
 	return err
 }
+
+// repoIssuesCond selects the issues of a repository for subqueries
+func repoIssuesCond(repoID int64) *builder.Builder {
+	return builder.Select("id").From("issue").Where(builder.Eq{"repo_id": repoID})
+}
+
+// migrationCheckpointConds returns the conditions of the data which a migration inserts into a repository
+func migrationCheckpointConds(repoID int64) map[string]builder.Cond {
+	return map[string]builder.Cond{
+		"milestone": builder.Eq{"repo_id": repoID},
+		"label":     builder.Eq{"repo_id": repoID},
+		"release":   builder.Eq{"repo_id": repoID},
+		"issue":     builder.Eq{"repo_id": repoID},
+		"comment":   builder.In("issue_id", repoIssuesCond(repoID)),
+		"review":    builder.In("issue_id", repoIssuesCond(repoID)),
+	}
+}
+
+// GetMigrationCheckpoint returns the greatest IDs of the data which has been migrated into a repository
+func GetMigrationCheckpoint(repoID int64) (map[string]int64, error) {
+	var checkpoint = make(map[string]int64)
+	for table, cond := range migrationCheckpointConds(repoID) {
+		var maxID int64
+		if _, err := x.Table(table).Where(cond).Select("COALESCE(MAX(id), 0)").Get(&maxID); err != nil {
+			return nil, err
+		}
+		checkpoint[table] = maxID
+	}
+	return checkpoint, nil
+}
+
+// DeleteMigratedDataAfterCheckpoint deletes the data which has been migrated into a repository after the
+// checkpoint returned by GetMigrationCheckpoint and recalculates the numbers of the repository
+func DeleteMigratedDataAfterCheckpoint(repoID int64, checkpoint map[string]int64) (err error) {
+	conds := migrationCheckpointConds(repoID)
+	after := func(table string) builder.Cond {
+		return conds[table].And(builder.Gt{"id": checkpoint[table]})
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	newIssues := builder.Select("id").From("issue").Where(after("issue"))
+	for _, bean := range []interface{}{&Comment{}, &Reaction{}, &IssueLabel{}, &PullRequest{}, &Review{}} {
+		if _, err = sess.In("issue_id", newIssues).Delete(bean); err != nil {
+			return err
+		}
+	}
+	if _, err = sess.Where(after("issue")).Delete(&Issue{}); err != nil {
+		return err
+	}
+
+	newComments := builder.Select("id").From("comment").Where(after("comment"))
+	if _, err = sess.In("comment_id", newComments).Delete(&Reaction{}); err != nil {
+		return err
+	}
+	if _, err = sess.Where(after("comment")).Delete(&Comment{}); err != nil {
+		return err
+	}
+	if _, err = sess.Where(after("review")).Delete(&Review{}); err != nil {
+		return err
+	}
+
+	var attachments []*Attachment
+	newReleases := builder.Select("id").From("release").Where(after("release"))
+	if err = sess.In("release_id", newReleases).Find(&attachments); err != nil {
+		return err
+	}
+	if _, err = sess.In("release_id", newReleases).Delete(&Attachment{}); err != nil {
+		return err
+	}
+	if _, err = sess.Where(after("release")).Delete(&Release{}); err != nil {
+		return err
+	}
+
+	newLabels := builder.Select("id").From("label").Where(after("label"))
+	if _, err = sess.In("label_id", newLabels).Delete(&IssueLabel{}); err != nil {
+		return err
+	}
+	if _, err = sess.Where(after("label")).Delete(&Label{}); err != nil {
+		return err
+	}
+	if _, err = sess.Where(after("milestone")).Delete(&Milestone{}); err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		"UPDATE `issue` SET num_comments=(SELECT COUNT(*) FROM `comment` WHERE comment.issue_id=issue.id AND type=0) WHERE repo_id=?",
+		"UPDATE `label` SET num_issues=(SELECT COUNT(*) FROM `issue_label` WHERE label_id=label.id) WHERE repo_id=?",
+		"UPDATE `label` SET num_closed_issues=(SELECT COUNT(*) FROM `issue_label` INNER JOIN `issue` ON issue_label.issue_id=issue.id WHERE issue_label.label_id=label.id AND issue.is_closed=?) WHERE repo_id=?",
+		"UPDATE `milestone` SET num_issues=(SELECT COUNT(*) FROM `issue` WHERE milestone_id=milestone.id) WHERE repo_id=?",
+		"UPDATE `milestone` SET num_closed_issues=(SELECT COUNT(*) FROM `issue` WHERE milestone_id=milestone.id AND is_closed=?) WHERE repo_id=?",
+		"UPDATE `milestone` SET completeness=100*num_closed_issues/(CASE WHEN num_issues > 0 THEN num_issues ELSE 1 END) WHERE repo_id=?",
+	} {
+		args := []interface{}{query}
+		if strings.Contains(query, "is_closed=?") {
+			args = append(args, true)
+		}
+		if _, err = sess.Exec(append(args, repoID)...); err != nil {
+			return err
+		}
+	}
+	if _, err = sess.Exec("UPDATE `repository` SET num_issues=(SELECT COUNT(*) FROM `issue` WHERE repo_id=? AND is_pull=?), num_closed_issues=(SELECT COUNT(*) FROM `issue` WHERE repo_id=? AND is_pull=? AND is_closed=?) WHERE id=?",
+		repoID, false, repoID, false, true, repoID); err != nil {
+		return err
+	}
+	if _, err = sess.Exec("UPDATE `repository` SET num_pulls=(SELECT COUNT(*) FROM `issue` WHERE repo_id=? AND is_pull=?), num_closed_pulls=(SELECT COUNT(*) FROM `issue` WHERE repo_id=? AND is_pull=? AND is_closed=?) WHERE id=?",
+		repoID, true, repoID, true, true, repoID); err != nil {
+		return err
+	}
+	if err = updateRepoMilestoneNum(sess, repoID); err != nil {
+		return err
+	}
+
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		RemoveStorageWithNotice(storage.Attachments, "Delete attachment", a.RelativePath())
+	}
+	return nil
+}
@@ -239,6 +239,40 @@ var migrations = []Migration{
 	NewMigration("set default password algorithm to Argon2", setDefaultPasswordToArgon2),
 	// v152 -> v153
 	NewMigration("add TrustModel field to Repository", addTrustModelToRepository),
+	// v153 -> v154
+	NewMigration("add push_mirror table", addPushMirrorTable),
+	// v154 -> v155
+	NewMigration("add package tables", addPackageTables),
+	// v155 -> v156
+	NewMigration("add container registry columns and upload table", addContainerRegistryTables),
+	// v156 -> v157
+	NewMigration("add scope and repository restriction to access tokens", addScopeToAccessToken),
+	// v157 -> v158
+	NewMigration("add table to schedule automatic merges of pull requests", addPullAutoMergeTable),
+	// v158 -> v159
+	NewMigration("add block on code owner reviews to protected branch", addBlockOnCodeOwnerReviews),
+	// v159 -> v160
+	NewMigration("add table for protected tags", addProtectedTagTable),
+	// v160 -> v161
+	NewMigration("add progress content to task", addProgressContentToTask),
+	// v161 -> v162
+	NewMigration("add scope to oauth2 grants and nonce to authorization codes", addOAuth2ScopeAndNonce),
+	// v162 -> v163
+	NewMigration("convert U2F registrations to WebAuthn credentials", convertU2FToWebAuthn),
+	// v163 -> v164
+	NewMigration("add audit event table", addAuditEventTable),
+	// v164 -> v165
+	NewMigration("add blocked user table", addBlockedUserTable),
+	// v165 -> v166
+	NewMigration("add flagged user table", addFlaggedUserTable),
+	// v166 -> v167
+	NewMigration("add team id column to review and comment", addTeamReviewRequestSupport),
+	// v167 -> v168
+	NewMigration("add start line to code comments for multi-line comments", addStartLineToComment),
+	// v168 -> v169
+	NewMigration("add issue_redirect table", addIssueRedirectTable),
+	// v169 -> v170
+	NewMigration("add owner_id to project", addOwnerIDToProject),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPushMirrorTable(x *xorm.Engine) error {
+	type PushMirror struct {
+		ID                     int64  `xorm:"pk autoincr"`
+		RepoID                 int64  `xorm:"INDEX"`
+		RemoteAddress          string `xorm:"TEXT"`
+		EncryptedRemoteAddress string `xorm:"TEXT"`
+
+		Interval       time.Duration
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		LastUpdateUnix timeutil.TimeStamp `xorm:"INDEX last_update"`
+		NextUpdateUnix timeutil.TimeStamp `xorm:"INDEX"`
+		LastError      string             `xorm:"TEXT"`
+	}
+
+	if err := x.Sync2(new(PushMirror)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
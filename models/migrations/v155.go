@@ -0,0 +1,35 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addContainerRegistryTables(x *xorm.Engine) error {
+	type PackageFile struct {
+		ContentType string
+		IsLead      bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type PackageBlobUpload struct {
+		ID            string             `xorm:"pk"`
+		OwnerID       int64              `xorm:"INDEX NOT NULL"`
+		LowerName     string             `xorm:"NOT NULL"`
+		BytesReceived int64              `xorm:"NOT NULL DEFAULT 0"`
+		NumChunks     int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	if err := x.Sync2(new(PackageFile), new(PackageBlobUpload)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
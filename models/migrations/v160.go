@@ -0,0 +1,22 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addProgressContentToTask(x *xorm.Engine) error {
+	type Task struct {
+		ProgressContent string `xorm:"TEXT"`
+	}
+
+	if err := x.Sync2(new(Task)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
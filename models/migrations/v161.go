@@ -0,0 +1,34 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+type oauth2GrantV161 struct {
+	Scope string `xorm:"TEXT"`
+}
+
+func (grant *oauth2GrantV161) TableName() string {
+	return "oauth2_grant"
+}
+
+type oauth2AuthorizationCodeV161 struct {
+	Nonce string `xorm:"TEXT"`
+}
+
+func (code *oauth2AuthorizationCodeV161) TableName() string {
+	return "oauth2_authorization_code"
+}
+
+func addOAuth2ScopeAndNonce(x *xorm.Engine) error {
+	if err := x.Sync2(new(oauth2GrantV161), new(oauth2AuthorizationCodeV161)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
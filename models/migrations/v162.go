@@ -0,0 +1,135 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/fxamacker/cbor/v2"
+	"xorm.io/xorm"
+)
+
+type u2fRegistrationV162 struct {
+	ID          int64 `xorm:"pk autoincr"`
+	Name        string
+	UserID      int64 `xorm:"INDEX"`
+	Raw         []byte
+	Counter     uint32             `xorm:"BIGINT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func (reg *u2fRegistrationV162) TableName() string {
+	return "u2f_registration"
+}
+
+type webAuthnCredentialV162 struct {
+	ID              int64 `xorm:"pk autoincr"`
+	Name            string
+	UserID          int64  `xorm:"INDEX"`
+	CredentialID    string `xorm:"INDEX VARCHAR(410)"`
+	PublicKey       []byte
+	AttestationType string
+	AAGUID          []byte
+	SignCount       uint32 `xorm:"BIGINT"`
+	CloneWarning    bool
+	CreatedUnix     timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func (cred *webAuthnCredentialV162) TableName() string {
+	return "webauthn_credential"
+}
+
+// parseU2FRegistrationV162 extracts the key handle and the public key from the raw
+// registration response message of the FIDO U2F raw message format
+func parseU2FRegistrationV162(raw []byte) (keyHandle, publicKey []byte, err error) {
+	// reserved byte, uncompressed P-256 point, key handle length, key handle
+	if len(raw) < 67 || raw[0] != 0x05 || raw[1] != 0x04 {
+		return nil, nil, fmt.Errorf("invalid registration data")
+	}
+	keyHandleLen := int(raw[66])
+	if len(raw) < 67+keyHandleLen {
+		return nil, nil, fmt.Errorf("invalid key handle length")
+	}
+	x, y := raw[2:34], raw[34:66]
+
+	// COSE_Key: kty EC2, alg ES256, crv P-256
+	encMode, err := cbor.CTAP2EncOptions().EncMode()
+	if err != nil {
+		return nil, nil, err
+	}
+	publicKey, err = encMode.Marshal(map[int]interface{}{
+		1:  2,
+		3:  -7,
+		-1: 1,
+		-2: x,
+		-3: y,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return raw[67 : 67+keyHandleLen], publicKey, nil
+}
+
+func convertU2FToWebAuthn(x *xorm.Engine) error {
+	if err := x.Sync2(new(webAuthnCredentialV162)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	exist, err := x.IsTableExist("u2f_registration")
+	if err != nil {
+		return err
+	} else if !exist {
+		return nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	const batchSize = 100
+	for start := 0; ; start += batchSize {
+		regs := make([]*u2fRegistrationV162, 0, batchSize)
+		if err := sess.OrderBy("id").Limit(batchSize, start).Find(&regs); err != nil {
+			return err
+		}
+		if len(regs) == 0 {
+			break
+		}
+
+		for _, reg := range regs {
+			keyHandle, publicKey, err := parseU2FRegistrationV162(reg.Raw)
+			if err != nil {
+				log.Warn("Unable to convert U2F registration %d of user %d: %v", reg.ID, reg.UserID, err)
+				continue
+			}
+			cred := &webAuthnCredentialV162{
+				Name:            reg.Name,
+				UserID:          reg.UserID,
+				CredentialID:    base64.RawURLEncoding.EncodeToString(keyHandle),
+				PublicKey:       publicKey,
+				AttestationType: "fido-u2f",
+				SignCount:       reg.Counter,
+				CreatedUnix:     reg.CreatedUnix,
+				UpdatedUnix:     reg.UpdatedUnix,
+			}
+			if _, err := sess.NoAutoTime().Insert(cred); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := sess.DropTable("u2f_registration"); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addBlockedUserTable(x *xorm.Engine) error {
+	type BlockedUser struct {
+		ID          int64 `xorm:"pk autoincr"`
+		BlockerID   int64 `xorm:"UNIQUE(block) NOT NULL"`
+		BlockeeID   int64 `xorm:"UNIQUE(block) INDEX NOT NULL"`
+		Note        string
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(BlockedUser)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
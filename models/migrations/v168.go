@@ -0,0 +1,28 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addIssueRedirectTable(x *xorm.Engine) error {
+	type IssueRedirect struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OldRepoID   int64              `xorm:"UNIQUE(s)"`
+		OldIndex    int64              `xorm:"UNIQUE(s)"`
+		IssueID     int64              `xorm:"INDEX"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	if err := x.Sync2(new(IssueRedirect)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -119,7 +119,7 @@ func init() {
 		new(LFSLock),
 		new(Reaction),
 		new(IssueAssignees),
-		new(U2FRegistration),
+		new(WebAuthnCredential),
 		new(TeamUnit),
 		new(Review),
 		new(OAuth2Application),
@@ -131,6 +131,18 @@ func init() {
 		new(Project),
 		new(ProjectBoard),
 		new(ProjectIssue),
+		new(PushMirror),
+		new(Package),
+		new(PackageVersion),
+		new(PackageFile),
+		new(PackageBlob),
+		new(PackageBlobUpload),
+		new(PullAutoMerge),
+		new(ProtectedTag),
+		new(AuditEvent),
+		new(BlockedUser),
+		new(FlaggedUser),
+		new(IssueRedirect),
 	)
 
 	gonicNames := []string{"SSL", "UID"}
@@ -9,8 +9,10 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
+	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/secret"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -102,15 +104,16 @@ func (app *OAuth2Application) getGrantByUserID(e Engine, userID int64) (grant *O
 	return grant, nil
 }
 
-// CreateGrant generates a grant for an user
-func (app *OAuth2Application) CreateGrant(userID int64) (*OAuth2Grant, error) {
-	return app.createGrant(x, userID)
+// CreateGrant generates a grant for an user with the space separated scope
+func (app *OAuth2Application) CreateGrant(userID int64, scope string) (*OAuth2Grant, error) {
+	return app.createGrant(x, userID, scope)
 }
 
-func (app *OAuth2Application) createGrant(e Engine, userID int64) (*OAuth2Grant, error) {
+func (app *OAuth2Application) createGrant(e Engine, userID int64, scope string) (*OAuth2Grant, error) {
 	grant := &OAuth2Grant{
 		ApplicationID: app.ID,
 		UserID:        userID,
+		Scope:         scope,
 	}
 	_, err := e.Insert(grant)
 	if err != nil {
@@ -296,6 +299,7 @@ type OAuth2AuthorizationCode struct {
 	CodeChallenge       string
 	CodeChallengeMethod string
 	RedirectURI         string
+	Nonce               string             `xorm:"TEXT"`
 	ValidUntil          timeutil.TimeStamp `xorm:"index"`
 }
 
@@ -380,6 +384,7 @@ type OAuth2Grant struct {
 	Application   *OAuth2Application `xorm:"-"`
 	ApplicationID int64              `xorm:"INDEX unique(user_application)"`
 	Counter       int64              `xorm:"NOT NULL DEFAULT 1"`
+	Scope         string             `xorm:"TEXT"`
 	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
 }
@@ -389,12 +394,13 @@ func (grant *OAuth2Grant) TableName() string {
 	return "oauth2_grant"
 }
 
-// GenerateNewAuthorizationCode generates a new authorization code for a grant and saves it to the databse
-func (grant *OAuth2Grant) GenerateNewAuthorizationCode(redirectURI, codeChallenge, codeChallengeMethod string) (*OAuth2AuthorizationCode, error) {
-	return grant.generateNewAuthorizationCode(x, redirectURI, codeChallenge, codeChallengeMethod)
+// GenerateNewAuthorizationCode generates a new authorization code for a grant and saves it to the databse.
+// The nonce of the OpenID Connect authentication request is returned in the ID token of the code.
+func (grant *OAuth2Grant) GenerateNewAuthorizationCode(redirectURI, codeChallenge, codeChallengeMethod, nonce string) (*OAuth2AuthorizationCode, error) {
+	return grant.generateNewAuthorizationCode(x, redirectURI, codeChallenge, codeChallengeMethod, nonce)
 }
 
-func (grant *OAuth2Grant) generateNewAuthorizationCode(e Engine, redirectURI, codeChallenge, codeChallengeMethod string) (code *OAuth2AuthorizationCode, err error) {
+func (grant *OAuth2Grant) generateNewAuthorizationCode(e Engine, redirectURI, codeChallenge, codeChallengeMethod, nonce string) (code *OAuth2AuthorizationCode, err error) {
 	var codeSecret string
 	if codeSecret, err = secret.New(); err != nil {
 		return &OAuth2AuthorizationCode{}, err
@@ -406,6 +412,7 @@ func (grant *OAuth2Grant) generateNewAuthorizationCode(e Engine, redirectURI, co
 		Code:                codeSecret,
 		CodeChallenge:       codeChallenge,
 		CodeChallengeMethod: codeChallengeMethod,
+		Nonce:               nonce,
 	}
 	if _, err := e.Insert(code); err != nil {
 		return nil, err
@@ -431,6 +438,86 @@ func (grant *OAuth2Grant) increaseCount(e Engine) error {
 	return nil
 }
 
+// ScopeContains returns true if the scope of the grant contains the given scope
+func (grant *OAuth2Grant) ScopeContains(scope string) bool {
+	for _, s := range strings.Fields(grant.Scope) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// SetScope updates the scope of the grant
+func (grant *OAuth2Grant) SetScope(scope string) error {
+	return grant.setScope(x, scope)
+}
+
+func (grant *OAuth2Grant) setScope(e Engine, scope string) error {
+	grant.Scope = scope
+	_, err := e.ID(grant.ID).Cols("scope").Update(grant)
+	return err
+}
+
+// GetOIDCUserClaims returns the claims about the user which are released by the scope of the grant
+func (grant *OAuth2Grant) GetOIDCUserClaims(user *User) (*OIDCUserClaims, error) {
+	return grant.getOIDCUserClaims(x, user)
+}
+
+func (grant *OAuth2Grant) getOIDCUserClaims(e Engine, user *User) (*OIDCUserClaims, error) {
+	claims := new(OIDCUserClaims)
+	if grant.ScopeContains("profile") {
+		claims.Name = user.DisplayName()
+		claims.PreferredUsername = user.Name
+		claims.Profile = user.HTMLURL()
+		claims.Picture = user.AvatarLink()
+		claims.Website = user.Website
+		claims.Locale = user.Language
+		claims.UpdatedAt = user.UpdatedUnix.AsTime().Unix()
+	}
+	if grant.ScopeContains("email") {
+		claims.Email = user.Email
+		claims.EmailVerified = user.IsActive
+	}
+	if grant.ScopeContains("groups") {
+		groups, err := getOIDCGroups(e, user)
+		if err != nil {
+			return nil, err
+		}
+		claims.Groups = groups
+	}
+	return claims, nil
+}
+
+// getOIDCGroups returns the names of the organizations of the user and its teams as "org:team"
+func getOIDCGroups(e Engine, user *User) ([]string, error) {
+	orgs := make([]*User, 0, 10)
+	if err := e.
+		Join("INNER", "`org_user`", "`org_user`.org_id=`user`.id").
+		Where("`org_user`.uid=?", user.ID).
+		Asc("`user`.name").
+		Find(&orgs); err != nil {
+		return nil, err
+	}
+	teams, err := getUserTeams(e, user.ID, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(orgs)+len(teams))
+	orgNames := make(map[int64]string, len(orgs))
+	for _, org := range orgs {
+		groups = append(groups, org.Name)
+		orgNames[org.ID] = org.Name
+	}
+	for _, team := range teams {
+		if orgName, ok := orgNames[team.OrgID]; ok {
+			groups = append(groups, orgName+":"+team.LowerName)
+		}
+	}
+	return groups, nil
+}
+
 // GetOAuth2GrantByID returns the grant with the given ID
 func GetOAuth2GrantByID(id int64) (*OAuth2Grant, error) {
 	return getOAuth2GrantByID(x, id)
@@ -533,3 +620,35 @@ func (token *OAuth2Token) SignToken() (string, error) {
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS512, token)
 	return jwtToken.SignedString(setting.OAuth2.JWTSecretBytes)
 }
+
+// OIDCUserClaims represents the standard claims (OpenID Connect Core 1.0, section 5.1) about an user
+type OIDCUserClaims struct {
+	// Scope profile
+	Name              string `json:"name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	Picture           string `json:"picture,omitempty"`
+	Website           string `json:"website,omitempty"`
+	Locale            string `json:"locale,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+
+	// Scope email
+	Email         string `json:"email,omitempty"`
+	EmailVerified bool   `json:"email_verified,omitempty"`
+
+	// Scope groups
+	Groups []string `json:"groups,omitempty"`
+}
+
+// OIDCToken represents an OpenID Connect ID token
+type OIDCToken struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+	*OIDCUserClaims
+}
+
+// SignToken signs the ID token with the signing key
+func (token *OIDCToken) SignToken(signingKey *oauth2.SigningKey) (string, error) {
+	token.IssuedAt = time.Now().Unix()
+	return signingKey.Sign(token)
+}
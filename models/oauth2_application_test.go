@@ -94,11 +94,12 @@ func TestOAuth2Application_GetGrantByUserID(t *testing.T) {
 func TestOAuth2Application_CreateGrant(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	app := AssertExistsAndLoadBean(t, &OAuth2Application{ID: 1}).(*OAuth2Application)
-	grant, err := app.CreateGrant(2)
+	grant, err := app.CreateGrant(2, "openid profile")
 	assert.NoError(t, err)
 	assert.NotNil(t, grant)
 	assert.Equal(t, int64(2), grant.UserID)
 	assert.Equal(t, int64(1), grant.ApplicationID)
+	assert.Equal(t, "openid profile", grant.Scope)
 }
 
 //////////////////// Grant
@@ -125,10 +126,44 @@ func TestOAuth2Grant_IncreaseCounter(t *testing.T) {
 func TestOAuth2Grant_GenerateNewAuthorizationCode(t *testing.T) {
 	assert.NoError(t, PrepareTestDatabase())
 	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1}).(*OAuth2Grant)
-	code, err := grant.GenerateNewAuthorizationCode("https://example2.com/callback", "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg", "S256")
+	code, err := grant.GenerateNewAuthorizationCode("https://example2.com/callback", "CjvyTLSdR47G5zYenDA-eDWW4lRrO8yvjcWwbD_deOg", "S256", "thenonce")
 	assert.NoError(t, err)
 	assert.NotNil(t, code)
 	assert.True(t, len(code.Code) > 32) // secret length > 32
+	AssertExistsAndLoadBean(t, &OAuth2AuthorizationCode{Code: code.Code, Nonce: "thenonce"})
+}
+
+func TestOAuth2Grant_ScopeContains(t *testing.T) {
+	grant := &OAuth2Grant{Scope: "openid  profile"}
+	assert.True(t, grant.ScopeContains("openid"))
+	assert.True(t, grant.ScopeContains("profile"))
+	assert.False(t, grant.ScopeContains("email"))
+	assert.False(t, grant.ScopeContains("open"))
+}
+
+func TestOAuth2Grant_SetScope(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	grant := AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1}).(*OAuth2Grant)
+	assert.NoError(t, grant.SetScope("openid email"))
+	AssertExistsAndLoadBean(t, &OAuth2Grant{ID: 1, Scope: "openid email"})
+}
+
+func TestOAuth2Grant_GetOIDCUserClaims(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	user := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	claims, err := (&OAuth2Grant{Scope: "openid"}).GetOIDCUserClaims(user)
+	assert.NoError(t, err)
+	assert.Equal(t, &OIDCUserClaims{}, claims)
+
+	claims, err = (&OAuth2Grant{Scope: "openid profile email groups"}).GetOIDCUserClaims(user)
+	assert.NoError(t, err)
+	assert.Equal(t, user.DisplayName(), claims.Name)
+	assert.Equal(t, "user2", claims.PreferredUsername)
+	assert.Equal(t, user.HTMLURL(), claims.Profile)
+	assert.Equal(t, user.Email, claims.Email)
+	assert.True(t, claims.EmailVerified)
+	assert.Equal(t, []string{"user3", "user3:owners", "user3:team1"}, claims.Groups)
 }
 
 func TestOAuth2Grant_TableName(t *testing.T) {
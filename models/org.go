@@ -268,7 +268,7 @@ func DeleteOrganization(org *User) (err error) {
 	}
 
 	if err = deleteOrg(sess, org); err != nil {
-		if IsErrUserOwnRepos(err) {
+		if IsErrUserOwnRepos(err) || IsErrUserOwnPackages(err) {
 			return err
 		} else if err != nil {
 			return fmt.Errorf("deleteOrg: %v", err)
@@ -287,15 +287,29 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		return ErrUserOwnRepos{UID: u.ID}
 	}
 
+	// Check ownership of packages.
+	count, err = countPackagesByOwnerID(e, u.ID)
+	if err != nil {
+		return fmt.Errorf("CountPackagesByOwnerID: %v", err)
+	} else if count > 0 {
+		return ErrUserOwnPackages{UID: u.ID}
+	}
+
 	if err := deleteBeans(e,
 		&Team{OrgID: u.ID},
 		&OrgUser{OrgID: u.ID},
 		&TeamUser{OrgID: u.ID},
 		&TeamUnit{OrgID: u.ID},
+		&BlockedUser{BlockerID: u.ID},
+		&BlockedUser{BlockeeID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
 
+	if err := deleteProjectsByOwnerID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteProjectsByOwnerID: %v", err)
+	}
+
 	if _, err = e.ID(u.ID).Delete(new(User)); err != nil {
 		return fmt.Errorf("Delete: %v", err)
 	}
@@ -720,6 +720,14 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// Delete pending review requests of the team.
+	if _, err := sess.
+		Where("reviewer_team_id=?", t.ID).
+		And("type=?", ReviewTypeRequest).
+		Delete(new(Review)); err != nil {
+		return err
+	}
+
 	// Delete team.
 	if _, err := sess.ID(t.ID).Delete(new(Team)); err != nil {
 		return err
@@ -838,6 +846,10 @@ func AddTeamMember(team *Team, userID int64) error {
 		return err
 	}
 
+	if err := CheckUserBlockedBy(userID, team.OrgID); err != nil {
+		return err
+	}
+
 	if err := AddOrgUser(team.OrgID, userID); err != nil {
 		return err
 	}
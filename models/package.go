@@ -0,0 +1,385 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// PackageType specifies the type of a package
+type PackageType int
+
+const (
+	// PackageGeneric is a package of arbitrary files
+	PackageGeneric PackageType = iota + 1
+	// PackageContainer is a container image following the OCI distribution spec
+	PackageContainer
+)
+
+var packageTypeNames = map[PackageType]string{
+	PackageGeneric:   "generic",
+	PackageContainer: "container",
+}
+
+// Name returns the name of the package type as used in URLs
+func (pt PackageType) Name() string {
+	return packageTypeNames[pt]
+}
+
+func (pt PackageType) String() string {
+	return pt.Name()
+}
+
+// ParsePackageType returns the package type for the given name
+func ParsePackageType(name string) (PackageType, bool) {
+	for pt, n := range packageTypeNames {
+		if n == name {
+			return pt, true
+		}
+	}
+	return 0, false
+}
+
+// Package represents a package owned by a user or an organization
+type Package struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Owner       *User              `xorm:"-"`
+	Type        PackageType        `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Name        string             `xorm:"NOT NULL"`
+	LowerName   string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+func (p *Package) loadOwner(e Engine) (err error) {
+	if p.Owner == nil {
+		p.Owner, err = getUserByID(e, p.OwnerID)
+	}
+	return err
+}
+
+// LoadOwner loads the owner of the package
+func (p *Package) LoadOwner() error {
+	return p.loadOwner(x)
+}
+
+// HTMLURL returns the absolute URL of the package page. The owner must be loaded.
+func (p *Package) HTMLURL() string {
+	return fmt.Sprintf("%s/-/packages/%s/%s", p.Owner.HTMLURL(), p.Type.Name(), url.PathEscape(p.Name))
+}
+
+// PackageVersion represents a version of a package
+type PackageVersion struct {
+	ID            int64              `xorm:"pk autoincr"`
+	PackageID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Package       *Package           `xorm:"-"`
+	CreatorID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Creator       *User              `xorm:"-"`
+	Version       string             `xorm:"NOT NULL"`
+	LowerVersion  string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	DownloadCount int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func (pv *PackageVersion) loadAttributes(e Engine) (err error) {
+	if pv.Package == nil {
+		if pv.Package, err = getPackageByID(e, pv.PackageID); err != nil {
+			return err
+		}
+	}
+	if err = pv.Package.loadOwner(e); err != nil {
+		return err
+	}
+	if pv.Creator == nil {
+		pv.Creator, err = getUserByID(e, pv.CreatorID)
+		if IsErrUserNotExist(err) {
+			pv.Creator = NewGhostUser()
+			err = nil
+		}
+	}
+	return err
+}
+
+// LoadAttributes loads the package, its owner and the creator of the version
+func (pv *PackageVersion) LoadAttributes() error {
+	return pv.loadAttributes(x)
+}
+
+// HTMLURL returns the absolute URL of the version page. The attributes must be loaded.
+func (pv *PackageVersion) HTMLURL() string {
+	return fmt.Sprintf("%s/%s", pv.Package.HTMLURL(), url.PathEscape(pv.Version))
+}
+
+// IncreaseDownloadCount increases the download counter of the version by one
+func (pv *PackageVersion) IncreaseDownloadCount() error {
+	if _, err := x.Exec("UPDATE `package_version` SET download_count=download_count+1 WHERE id=?", pv.ID); err != nil {
+		return fmt.Errorf("increase package version download count: %v", err)
+	}
+	return nil
+}
+
+// GetOrInsertPackageVersion returns the version of the package and creates the package
+// and the version if they do not exist yet
+func GetOrInsertPackageVersion(ownerID int64, packageType PackageType, name, version string, creatorID int64) (*PackageVersion, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	p := &Package{
+		OwnerID:   ownerID,
+		Type:      packageType,
+		LowerName: strings.ToLower(name),
+	}
+	has, err := sess.Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		p.Name = name
+		if _, err = sess.Insert(p); err != nil {
+			return nil, err
+		}
+	}
+
+	pv := &PackageVersion{
+		PackageID:    p.ID,
+		LowerVersion: strings.ToLower(version),
+	}
+	has, err = sess.Get(pv)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		pv.CreatorID = creatorID
+		pv.Version = version
+		if _, err = sess.Insert(pv); err != nil {
+			return nil, err
+		}
+		// Bump the updated timestamp of the package
+		if _, err = sess.ID(p.ID).Cols("updated_unix").Update(p); err != nil {
+			return nil, err
+		}
+	}
+	pv.Package = p
+
+	return pv, sess.Commit()
+}
+
+func getPackageByID(e Engine, id int64) (*Package, error) {
+	p := new(Package)
+	has, err := e.ID(id).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageNotExist{ID: id}
+	}
+	return p, nil
+}
+
+// GetPackageByID returns the package with the given ID
+func GetPackageByID(id int64) (*Package, error) {
+	return getPackageByID(x, id)
+}
+
+// GetPackageByName returns the package of the owner with the given type and name
+func GetPackageByName(ownerID int64, packageType PackageType, name string) (*Package, error) {
+	p := &Package{
+		OwnerID:   ownerID,
+		Type:      packageType,
+		LowerName: strings.ToLower(name),
+	}
+	has, err := x.Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageNotExist{OwnerID: ownerID, Type: packageType, Name: name}
+	}
+	return p, nil
+}
+
+// GetPackageVersionByID returns the package version with the given ID
+func GetPackageVersionByID(id int64) (*PackageVersion, error) {
+	pv := new(PackageVersion)
+	has, err := x.ID(id).Get(pv)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageVersionNotExist{ID: id}
+	}
+	return pv, nil
+}
+
+// GetPackageVersionByName returns the version of the package of the owner with the given type and name
+func GetPackageVersionByName(ownerID int64, packageType PackageType, name, version string) (*PackageVersion, error) {
+	p, err := GetPackageByName(ownerID, packageType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	pv := &PackageVersion{
+		PackageID:    p.ID,
+		LowerVersion: strings.ToLower(version),
+	}
+	has, err := x.Get(pv)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageVersionNotExist{PackageID: p.ID, Version: version}
+	}
+	pv.Package = p
+	return pv, nil
+}
+
+// GetPackageVersionsByPackageID returns the versions of the package, newest first
+func GetPackageVersionsByPackageID(packageID int64, listOptions ListOptions) ([]*PackageVersion, int64, error) {
+	sess := x.Where("package_id = ?", packageID)
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	versions := make([]*PackageVersion, 0, listOptions.PageSize)
+	count, err := sess.Desc("created_unix").Desc("id").FindAndCount(&versions)
+	return versions, count, err
+}
+
+// PackageSearchOptions are options for SearchPackages
+type PackageSearchOptions struct {
+	ListOptions
+	OwnerID int64
+	Type    PackageType
+	Keyword string
+}
+
+func (opts *PackageSearchOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OwnerID != 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.Type != 0 {
+		cond = cond.And(builder.Eq{"type": opts.Type})
+	}
+	if opts.Keyword != "" {
+		cond = cond.And(builder.Like{"lower_name", strings.ToLower(opts.Keyword)})
+	}
+	return cond
+}
+
+// SearchPackages returns the packages matching the options, most recently updated first
+func SearchPackages(opts *PackageSearchOptions) ([]*Package, int64, error) {
+	sess := x.Where(opts.toConds())
+	if opts.Page != 0 {
+		sess = opts.setSessionPagination(sess)
+	}
+	packages := make([]*Package, 0, opts.PageSize)
+	count, err := sess.Desc("updated_unix").Desc("id").FindAndCount(&packages)
+	return packages, count, err
+}
+
+func countPackagesByOwnerID(e Engine, ownerID int64) (int64, error) {
+	return e.Where("owner_id = ?", ownerID).Count(new(Package))
+}
+
+// DeletePackageVersion deletes the version with all its files. The package is deleted
+// too if this was its last version. Blobs which are not referenced anymore are
+// removed from the database and returned, so their content can be removed from the storage.
+func DeletePackageVersion(pv *PackageVersion) ([]*PackageBlob, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	files := make([]*PackageFile, 0, 10)
+	if err := sess.Where("version_id = ?", pv.ID).Find(&files); err != nil {
+		return nil, err
+	}
+	blobIDs := make([]int64, 0, len(files))
+	for _, pf := range files {
+		blobIDs = append(blobIDs, pf.BlobID)
+	}
+
+	if _, err := sess.Where("version_id = ?", pv.ID).Delete(new(PackageFile)); err != nil {
+		return nil, err
+	}
+	if err := deletePackageVersion(sess, pv); err != nil {
+		return nil, err
+	}
+
+	blobs, err := deleteUnreferencedPackageBlobs(sess, blobIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return blobs, sess.Commit()
+}
+
+// deletePackageVersion deletes the version row and the package if it has no versions left
+func deletePackageVersion(e Engine, pv *PackageVersion) error {
+	if _, err := e.ID(pv.ID).Delete(new(PackageVersion)); err != nil {
+		return err
+	}
+
+	count, err := e.Where("package_id = ?", pv.PackageID).Count(new(PackageVersion))
+	if err != nil {
+		return err
+	} else if count == 0 {
+		if _, err := e.ID(pv.PackageID).Delete(new(Package)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPackageAccessMode returns the access mode the doer has on the packages of the owner.
+// Access is derived from the owner: users have full access to their own packages, members
+// of an organization get the highest access mode of their teams and everyone else may read
+// the packages of owners visible to them.
+func GetPackageAccessMode(owner, doer *User) (AccessMode, error) {
+	if doer != nil && (doer.IsAdmin || doer.ID == owner.ID) {
+		return AccessModeOwner, nil
+	}
+
+	mode := AccessModeNone
+	if owner.IsOrganization() {
+		if !hasOrgVisible(x, owner, doer) {
+			return AccessModeNone, nil
+		}
+		mode = AccessModeRead
+
+		if doer != nil {
+			isOwner, err := isOrganizationOwner(x, owner.ID, doer.ID)
+			if err != nil {
+				return AccessModeNone, err
+			} else if isOwner {
+				return AccessModeOwner, nil
+			}
+
+			teams, err := owner.getUserTeams(x, doer.ID, "`team`.authorize")
+			if err != nil {
+				return AccessModeNone, err
+			}
+			for _, t := range teams {
+				if t.Authorize > mode {
+					mode = t.Authorize
+				}
+			}
+		}
+	} else if doer == nil {
+		if owner.Visibility == structs.VisibleTypePublic {
+			mode = AccessModeRead
+		}
+	} else if !doer.IsRestricted && owner.Visibility != structs.VisibleTypePrivate {
+		mode = AccessModeRead
+	}
+
+	return mode, nil
+}
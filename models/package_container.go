@@ -0,0 +1,220 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	gouuid "github.com/google/uuid"
+	"xorm.io/builder"
+)
+
+const (
+	// ContainerUploadVersion is the internal version of a container image which holds the
+	// blobs uploaded to the image independently of a manifest
+	ContainerUploadVersion = "_uploads"
+	// ContainerDigestPrefix is the prefix of the digests of container blobs and manifests.
+	// Versions named by a digest are manifests which are not tagged.
+	ContainerDigestPrefix = "sha256:"
+)
+
+// IsInternal returns true if the version is used internally and should not be listed
+func (pv *PackageVersion) IsInternal() bool {
+	return pv.Package != nil && pv.Package.Type == PackageContainer && pv.LowerVersion == ContainerUploadVersion
+}
+
+// IsUntagged returns true if the version is a container manifest without a tag
+func (pv *PackageVersion) IsUntagged() bool {
+	return pv.Package != nil && pv.Package.Type == PackageContainer && strings.HasPrefix(pv.LowerVersion, ContainerDigestPrefix)
+}
+
+// PackageBlobUpload represents a running chunked upload of a container blob
+type PackageBlobUpload struct {
+	ID            string             `xorm:"pk"`
+	OwnerID       int64              `xorm:"INDEX NOT NULL"`
+	LowerName     string             `xorm:"NOT NULL"`
+	BytesReceived int64              `xorm:"NOT NULL DEFAULT 0"`
+	NumChunks     int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// NewPackageBlobUpload creates a new upload for the image of the owner
+func NewPackageBlobUpload(ownerID int64, name string) (*PackageBlobUpload, error) {
+	pbu := &PackageBlobUpload{
+		ID:        gouuid.New().String(),
+		OwnerID:   ownerID,
+		LowerName: strings.ToLower(name),
+	}
+	if _, err := x.Insert(pbu); err != nil {
+		return nil, err
+	}
+	return pbu, nil
+}
+
+// GetPackageBlobUpload returns the upload with the given ID for the image of the owner
+func GetPackageBlobUpload(ownerID int64, name, id string) (*PackageBlobUpload, error) {
+	pbu := &PackageBlobUpload{
+		ID:        id,
+		OwnerID:   ownerID,
+		LowerName: strings.ToLower(name),
+	}
+	has, err := x.Get(pbu)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageBlobUploadNotExist{ID: id}
+	}
+	return pbu, nil
+}
+
+// UpdatePackageBlobUpload updates the received bytes and chunks of the upload
+func UpdatePackageBlobUpload(pbu *PackageBlobUpload) error {
+	_, err := x.ID(pbu.ID).Cols("bytes_received", "num_chunks", "updated_unix").Update(pbu)
+	return err
+}
+
+// DeletePackageBlobUpload deletes the upload
+func DeletePackageBlobUpload(pbu *PackageBlobUpload) error {
+	_, err := x.ID(pbu.ID).Delete(new(PackageBlobUpload))
+	return err
+}
+
+// FindPackageBlobUploadsUpdatedBefore returns the uploads which were not updated since the given time
+func FindPackageBlobUploadsUpdatedBefore(olderThan timeutil.TimeStamp) ([]*PackageBlobUpload, error) {
+	uploads := make([]*PackageBlobUpload, 0, 10)
+	return uploads, x.Where("updated_unix < ?", olderThan).Find(&uploads)
+}
+
+// packageFilesOfPackageCond returns the condition for files of all versions of the package
+func packageFilesOfPackageCond(packageID int64) builder.Cond {
+	return builder.In("version_id", builder.Select("id").From("package_version").Where(builder.Eq{"package_id": packageID}))
+}
+
+// GetContainerBlobFile returns a file with the digest in any version of the image
+func GetContainerBlobFile(packageID int64, digest string) (*PackageFile, error) {
+	pf := new(PackageFile)
+	has, err := x.
+		Where(packageFilesOfPackageCond(packageID)).
+		And("lower_name = ?", strings.ToLower(digest)).
+		Get(pf)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageFileNotExist{Name: digest}
+	}
+	return pf, pf.LoadBlob()
+}
+
+// GetContainerManifestFile returns the manifest with the digest of the image
+func GetContainerManifestFile(packageID int64, digest string) (*PackageFile, error) {
+	pf := new(PackageFile)
+	has, err := x.
+		Where(packageFilesOfPackageCond(packageID)).
+		And("lower_name = ?", strings.ToLower(digest)).
+		And("is_lead = ?", true).
+		Get(pf)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageFileNotExist{Name: digest}
+	}
+	return pf, pf.LoadBlob()
+}
+
+// GetLeadPackageFile returns the lead file of the version
+func GetLeadPackageFile(versionID int64) (*PackageFile, error) {
+	pf := &PackageFile{VersionID: versionID}
+	has, err := x.Where("is_lead = ?", true).Get(pf)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageFileNotExist{VersionID: versionID}
+	}
+	return pf, pf.LoadBlob()
+}
+
+// GetContainerVersionsByManifestDigest returns all versions of the image whose manifest has the digest
+func GetContainerVersionsByManifestDigest(packageID int64, digest string) ([]*PackageVersion, error) {
+	versions := make([]*PackageVersion, 0, 5)
+	return versions, x.
+		Where("package_id = ?", packageID).
+		And(builder.In("id", builder.Select("version_id").From("package_file").Where(builder.Eq{
+			"lower_name": strings.ToLower(digest),
+			"is_lead":    true,
+		}))).
+		Find(&versions)
+}
+
+// GetContainerTags returns up to limit tags of the image in lexical order, following the last tag.
+// A limit of zero returns all tags.
+func GetContainerTags(packageID int64, last string, limit int) ([]string, error) {
+	sess := x.Table("package_version").
+		Cols("version").
+		Where("package_id = ?", packageID).
+		And("lower_version <> ?", ContainerUploadVersion).
+		And(builder.Not{builder.Like{"lower_version", ContainerDigestPrefix + "%"}}).
+		Asc("lower_version")
+	if last != "" {
+		sess = sess.And("lower_version > ?", strings.ToLower(last))
+	}
+	if limit > 0 {
+		sess = sess.Limit(limit)
+	}
+	tags := make([]string, 0, 10)
+	return tags, sess.Find(&tags)
+}
+
+// RenamePackageVersion changes the version name of the version
+func RenamePackageVersion(pv *PackageVersion, version string) error {
+	pv.Version = version
+	pv.LowerVersion = strings.ToLower(version)
+	_, err := x.ID(pv.ID).Cols("version", "lower_version").Update(pv)
+	return err
+}
+
+// FindUnreferencedUntaggedContainerVersions returns the untagged manifests created before the given
+// time which are not referenced by a manifest list of the same image
+func FindUnreferencedUntaggedContainerVersions(olderThan timeutil.TimeStamp) ([]*PackageVersion, error) {
+	versions := make([]*PackageVersion, 0, 10)
+	if err := x.
+		Where(builder.In("package_id", builder.Select("id").From("package").Where(builder.Eq{"type": PackageContainer}))).
+		And(builder.Like{"lower_version", ContainerDigestPrefix + "%"}).
+		And("created_unix < ?", olderThan).
+		Find(&versions); err != nil {
+		return nil, err
+	}
+
+	unreferenced := make([]*PackageVersion, 0, len(versions))
+	for _, pv := range versions {
+		has, err := x.
+			Where(packageFilesOfPackageCond(pv.PackageID)).
+			And("lower_name = ?", pv.LowerVersion).
+			And("is_lead = ?", false).
+			Exist(new(PackageFile))
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			unreferenced = append(unreferenced, pv)
+		}
+	}
+	return unreferenced, nil
+}
+
+// FindContainerUploadFilesCreatedBefore returns the files of the upload versions of all images
+// created before the given time
+func FindContainerUploadFilesCreatedBefore(olderThan timeutil.TimeStamp) ([]*PackageFile, error) {
+	files := make([]*PackageFile, 0, 10)
+	return files, x.
+		Where(builder.In("version_id", builder.Select("id").From("package_version").Where(builder.And(
+			builder.Eq{"lower_version": ContainerUploadVersion},
+			builder.In("package_id", builder.Select("id").From("package").Where(builder.Eq{"type": PackageContainer})),
+		)))).
+		And("created_unix < ?", olderThan).
+		Find(&files)
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerTagsAndManifests(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	blob, _, err := GetOrInsertPackageBlob(&PackageBlob{Size: 1, HashSHA256: strings.Repeat("a", 64)})
+	assert.NoError(t, err)
+	manifest, _, err := GetOrInsertPackageBlob(&PackageBlob{Size: 2, HashSHA256: strings.Repeat("b", 64)})
+	assert.NoError(t, err)
+	blobDigest := ContainerDigestPrefix + blob.HashSHA256
+	manifestDigest := ContainerDigestPrefix + manifest.HashSHA256
+
+	uploads, err := GetOrInsertPackageVersion(2, PackageContainer, "image", ContainerUploadVersion, 2)
+	assert.NoError(t, err)
+	assert.True(t, uploads.IsInternal())
+	assert.NoError(t, InsertPackageFile(&PackageFile{VersionID: uploads.ID, BlobID: blob.ID, Name: blobDigest}))
+
+	for _, version := range []string{"latest", "1.0", manifestDigest} {
+		pv, err := GetOrInsertPackageVersion(2, PackageContainer, "image", version, 2)
+		assert.NoError(t, err)
+		assert.False(t, pv.IsInternal())
+		assert.Equal(t, version == manifestDigest, pv.IsUntagged())
+		assert.NoError(t, InsertPackageFile(&PackageFile{VersionID: pv.ID, BlobID: manifest.ID, Name: manifestDigest, IsLead: true}))
+		assert.NoError(t, InsertPackageFile(&PackageFile{VersionID: pv.ID, BlobID: blob.ID, Name: blobDigest}))
+	}
+	p := uploads.Package
+
+	tags, err := GetContainerTags(p.ID, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1.0", "latest"}, tags)
+	tags, err = GetContainerTags(p.ID, "1.0", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"latest"}, tags)
+
+	pf, err := GetContainerBlobFile(p.ID, blobDigest)
+	assert.NoError(t, err)
+	assert.Equal(t, blob.ID, pf.Blob.ID)
+	_, err = GetContainerManifestFile(p.ID, blobDigest)
+	assert.True(t, IsErrPackageFileNotExist(err))
+	pf, err = GetContainerManifestFile(p.ID, manifestDigest)
+	assert.NoError(t, err)
+	assert.True(t, pf.IsLead)
+
+	versions, err := GetContainerVersionsByManifestDigest(p.ID, manifestDigest)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 3)
+
+	// Only the untagged manifest is subject to cleanup
+	future := timeutil.TimeStampNow() + 10
+	versions, err = FindUnreferencedUntaggedContainerVersions(future)
+	assert.NoError(t, err)
+	assert.Len(t, versions, 1)
+	assert.Equal(t, manifestDigest, versions[0].Version)
+	versions, err = FindUnreferencedUntaggedContainerVersions(0)
+	assert.NoError(t, err)
+	assert.Empty(t, versions)
+
+	files, err := FindContainerUploadFilesCreatedBefore(future)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, uploads.ID, files[0].VersionID)
+}
+
+func TestPackageBlobUpload(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pbu, err := NewPackageBlobUpload(2, "Image")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pbu.ID)
+
+	pbu.BytesReceived = 10
+	pbu.NumChunks = 2
+	assert.NoError(t, UpdatePackageBlobUpload(pbu))
+
+	loaded, err := GetPackageBlobUpload(2, "image", pbu.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, loaded.BytesReceived)
+	assert.Equal(t, 2, loaded.NumChunks)
+
+	_, err = GetPackageBlobUpload(3, "image", pbu.ID)
+	assert.True(t, IsErrPackageBlobUploadNotExist(err))
+
+	uploads, err := FindPackageBlobUploadsUpdatedBefore(timeutil.TimeStampNow() + 10)
+	assert.NoError(t, err)
+	assert.Len(t, uploads, 1)
+
+	assert.NoError(t, DeletePackageBlobUpload(pbu))
+	_, err = GetPackageBlobUpload(2, "image", pbu.ID)
+	assert.True(t, IsErrPackageBlobUploadNotExist(err))
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// PackageFile represents a file of a package version. The lead file is the main file
+// of a version, e.g. the manifest of a container image.
+type PackageFile struct {
+	ID          int64        `xorm:"pk autoincr"`
+	VersionID   int64        `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	BlobID      int64        `xorm:"INDEX NOT NULL"`
+	Blob        *PackageBlob `xorm:"-"`
+	Name        string       `xorm:"NOT NULL"`
+	LowerName   string       `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	ContentType string
+	IsLead      bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// LoadBlob loads the blob holding the content of the file
+func (pf *PackageFile) LoadBlob() (err error) {
+	if pf.Blob == nil {
+		pf.Blob, err = GetPackageBlobByID(pf.BlobID)
+	}
+	return err
+}
+
+// PackageBlob represents the content of package files. Blobs are identified by the
+// hash of their content, so files with identical content share a blob.
+type PackageBlob struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+	HashSHA256  string             `xorm:"hash_sha256 char(64) UNIQUE NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// RelativePath returns the path of the blob content inside the packages storage
+func (pb *PackageBlob) RelativePath() string {
+	return PackageBlobRelativePath(pb.HashSHA256)
+}
+
+// PackageBlobRelativePath returns the path of the blob content with the given hash
+func PackageBlobRelativePath(hashSHA256 string) string {
+	return path.Join(hashSHA256[0:2], hashSHA256[2:4], hashSHA256)
+}
+
+// GetOrInsertPackageBlob returns the blob with the hash of the given blob and inserts it
+// if it does not exist yet. The returned bool reports whether the blob existed before.
+func GetOrInsertPackageBlob(pb *PackageBlob) (*PackageBlob, bool, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, false, err
+	}
+
+	existing := &PackageBlob{HashSHA256: pb.HashSHA256}
+	has, err := sess.Get(existing)
+	if err != nil {
+		return nil, false, err
+	} else if has {
+		return existing, true, nil
+	}
+	if _, err = sess.Insert(pb); err != nil {
+		return nil, false, err
+	}
+	return pb, false, sess.Commit()
+}
+
+// GetPackageBlobByID returns the blob with the given ID
+func GetPackageBlobByID(id int64) (*PackageBlob, error) {
+	pb := new(PackageBlob)
+	has, err := x.ID(id).Get(pb)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageBlobNotExist{ID: id}
+	}
+	return pb, nil
+}
+
+// GetPackageBlobByHash returns the blob with the given SHA256 hash
+func GetPackageBlobByHash(hashSHA256 string) (*PackageBlob, error) {
+	pb := &PackageBlob{HashSHA256: hashSHA256}
+	has, err := x.Get(pb)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageBlobNotExist{HashSHA256: hashSHA256}
+	}
+	return pb, nil
+}
+
+// DeletePackageBlobIfUnreferenced deletes the blob if no package file references it.
+// The returned bool reports whether the blob was deleted.
+func DeletePackageBlobIfUnreferenced(pb *PackageBlob) (bool, error) {
+	blobs, err := deleteUnreferencedPackageBlobs(x, []int64{pb.ID})
+	return len(blobs) > 0, err
+}
+
+func deleteUnreferencedPackageBlobs(e Engine, blobIDs []int64) ([]*PackageBlob, error) {
+	if len(blobIDs) == 0 {
+		return nil, nil
+	}
+
+	blobs := make([]*PackageBlob, 0, len(blobIDs))
+	if err := e.
+		In("id", blobIDs).
+		And(builder.NotIn("id", builder.Select("blob_id").From("package_file"))).
+		Find(&blobs); err != nil {
+		return nil, err
+	}
+	for _, pb := range blobs {
+		if _, err := e.ID(pb.ID).Delete(new(PackageBlob)); err != nil {
+			return nil, err
+		}
+	}
+	return blobs, nil
+}
+
+// InsertPackageFile adds the file to its version
+func InsertPackageFile(pf *PackageFile) error {
+	pf.LowerName = strings.ToLower(pf.Name)
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	has, err := sess.Exist(&PackageFile{VersionID: pf.VersionID, LowerName: pf.LowerName})
+	if err != nil {
+		return err
+	} else if has {
+		return ErrPackageFileAlreadyExist{VersionID: pf.VersionID, Name: pf.Name}
+	}
+	if _, err = sess.Insert(pf); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetPackageFileByName returns the file of the version with the given name
+func GetPackageFileByName(versionID int64, name string) (*PackageFile, error) {
+	pf := &PackageFile{
+		VersionID: versionID,
+		LowerName: strings.ToLower(name),
+	}
+	has, err := x.Get(pf)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageFileNotExist{VersionID: versionID, Name: name}
+	}
+	return pf, nil
+}
+
+// GetPackageFilesByVersionID returns all files of the version with their blobs loaded
+func GetPackageFilesByVersionID(versionID int64) ([]*PackageFile, error) {
+	files := make([]*PackageFile, 0, 10)
+	if err := x.Where("version_id = ?", versionID).Asc("lower_name").Find(&files); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return files, nil
+	}
+
+	blobIDs := make([]int64, 0, len(files))
+	for _, pf := range files {
+		blobIDs = append(blobIDs, pf.BlobID)
+	}
+	blobs := make(map[int64]*PackageBlob, len(files))
+	if err := x.In("id", blobIDs).Find(&blobs); err != nil {
+		return nil, err
+	}
+	for _, pf := range files {
+		pf.Blob = blobs[pf.BlobID]
+	}
+	return files, nil
+}
+
+// DeletePackageFile deletes the file. The version (and the package) is deleted too if
+// this was its last file. Blobs which are not referenced anymore are removed from the
+// database and returned, so their content can be removed from the storage.
+func DeletePackageFile(pv *PackageVersion, pf *PackageFile) ([]*PackageBlob, error) {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	if _, err := sess.ID(pf.ID).Delete(new(PackageFile)); err != nil {
+		return nil, err
+	}
+
+	count, err := sess.Where("version_id = ?", pv.ID).Count(new(PackageFile))
+	if err != nil {
+		return nil, err
+	} else if count == 0 {
+		if err := deletePackageVersion(sess, pv); err != nil {
+			return nil, err
+		}
+	}
+
+	blobs, err := deleteUnreferencedPackageBlobs(sess, []int64{pf.BlobID})
+	if err != nil {
+		return nil, err
+	}
+
+	return blobs, sess.Commit()
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageVersionAndFiles(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	pv, err := GetOrInsertPackageVersion(2, PackageGeneric, "Test-Package", "1.0.0", 2)
+	assert.NoError(t, err)
+	assert.NotZero(t, pv.ID)
+
+	// Lookups are case insensitive and return the existing rows
+	same, err := GetOrInsertPackageVersion(2, PackageGeneric, "test-package", "1.0.0", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, pv.ID, same.ID)
+	assert.Equal(t, "Test-Package", same.Package.Name)
+
+	pb, exists, err := GetOrInsertPackageBlob(&PackageBlob{Size: 3, HashSHA256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"})
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	_, exists, err = GetOrInsertPackageBlob(&PackageBlob{Size: 3, HashSHA256: pb.HashSHA256})
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "ba/78/"+pb.HashSHA256, pb.RelativePath())
+
+	assert.NoError(t, InsertPackageFile(&PackageFile{VersionID: pv.ID, BlobID: pb.ID, Name: "a.txt"}))
+	assert.NoError(t, InsertPackageFile(&PackageFile{VersionID: pv.ID, BlobID: pb.ID, Name: "b.txt"}))
+	err = InsertPackageFile(&PackageFile{VersionID: pv.ID, BlobID: pb.ID, Name: "A.txt"})
+	assert.True(t, IsErrPackageFileAlreadyExist(err))
+
+	files, err := GetPackageFilesByVersionID(pv.ID)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, pb.ID, files[0].Blob.ID)
+
+	packages, count, err := SearchPackages(&PackageSearchOptions{OwnerID: 2, Keyword: "test"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, packages, 1)
+
+	// The blob is still referenced by the second file
+	blobs, err := DeletePackageFile(pv, files[0])
+	assert.NoError(t, err)
+	assert.Empty(t, blobs)
+
+	// Deleting the last file removes the version, the package and the blob
+	blobs, err = DeletePackageFile(pv, files[1])
+	assert.NoError(t, err)
+	assert.Len(t, blobs, 1)
+	_, err = GetPackageVersionByID(pv.ID)
+	assert.True(t, IsErrPackageVersionNotExist(err))
+	_, err = GetPackageByName(2, PackageGeneric, "test-package")
+	assert.True(t, IsErrPackageNotExist(err))
+	_, err = GetPackageBlobByID(pb.ID)
+	assert.True(t, IsErrPackageBlobNotExist(err))
+}
+
+func TestGetPackageAccessMode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	org3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	privateOrg := AssertExistsAndLoadBean(t, &User{ID: 23}).(*User)
+
+	cases := []struct {
+		owner    *User
+		doer     *User
+		expected AccessMode
+	}{
+		{user2, user2, AccessModeOwner},
+		{user2, user4, AccessModeRead},
+		{user2, nil, AccessModeRead},
+		{org3, user2, AccessModeOwner},
+		{org3, user4, AccessModeWrite},
+		{org3, user5, AccessModeRead},
+		{org3, nil, AccessModeRead},
+		{privateOrg, nil, AccessModeNone},
+		{privateOrg, user5, AccessModeNone},
+	}
+	for _, c := range cases {
+		mode, err := GetPackageAccessMode(c.owner, c.doer)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, mode, "owner: %s", c.owner.Name)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
@@ -43,12 +45,15 @@ type Project struct {
 	Title       string `xorm:"INDEX NOT NULL"`
 	Description string `xorm:"TEXT"`
 	RepoID      int64  `xorm:"INDEX"`
+	OwnerID     int64  `xorm:"INDEX"`
 	CreatorID   int64  `xorm:"NOT NULL"`
 	IsClosed    bool   `xorm:"INDEX"`
 	BoardType   ProjectBoardType
 	Type        ProjectType
 
-	RenderedContent string `xorm:"-"`
+	RenderedContent string      `xorm:"-"`
+	Repo            *Repository `xorm:"-"`
+	Owner           *User       `xorm:"-"`
 
 	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix    timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -67,7 +72,7 @@ func GetProjectsConfig() []ProjectsConfig {
 // IsProjectTypeValid checks if a project type is valid
 func IsProjectTypeValid(p ProjectType) bool {
 	switch p {
-	case ProjectTypeRepository:
+	case ProjectTypeIndividual, ProjectTypeRepository, ProjectTypeOrganization:
 		return true
 	default:
 		return false
@@ -77,22 +82,25 @@ func IsProjectTypeValid(p ProjectType) bool {
 // ProjectSearchOptions are options for GetProjects
 type ProjectSearchOptions struct {
 	RepoID   int64
+	OwnerID  int64
 	Page     int
+	PageSize int // defaults to setting.UI.IssuePagingNum
 	IsClosed util.OptionalBool
 	SortType string
 	Type     ProjectType
 }
 
 // GetProjects returns a list of all projects that have been created in the repository
+// or, if an owner is given, by the user or organization
 func GetProjects(opts ProjectSearchOptions) ([]*Project, int64, error) {
 	return getProjects(x, opts)
 }
 
-func getProjects(e Engine, opts ProjectSearchOptions) ([]*Project, int64, error) {
-
-	projects := make([]*Project, 0, setting.UI.IssuePagingNum)
-
+func (opts *ProjectSearchOptions) toCond() builder.Cond {
 	var cond builder.Cond = builder.Eq{"repo_id": opts.RepoID}
+	if opts.OwnerID > 0 {
+		cond = builder.Eq{"owner_id": opts.OwnerID}
+	}
 	switch opts.IsClosed {
 	case util.OptionalBoolTrue:
 		cond = cond.And(builder.Eq{"is_closed": true})
@@ -103,7 +111,19 @@ func getProjects(e Engine, opts ProjectSearchOptions) ([]*Project, int64, error)
 	if opts.Type > 0 {
 		cond = cond.And(builder.Eq{"type": opts.Type})
 	}
+	return cond
+}
+
+// CountProjects counts the projects matching the options, the page is ignored
+func CountProjects(opts ProjectSearchOptions) (int64, error) {
+	return x.Where(opts.toCond()).Count(new(Project))
+}
 
+func getProjects(e Engine, opts ProjectSearchOptions) ([]*Project, int64, error) {
+
+	projects := make([]*Project, 0, setting.UI.IssuePagingNum)
+
+	cond := opts.toCond()
 	count, err := e.Where(cond).Count(new(Project))
 	if err != nil {
 		return nil, 0, fmt.Errorf("Count: %v", err)
@@ -112,7 +132,11 @@ func getProjects(e Engine, opts ProjectSearchOptions) ([]*Project, int64, error)
 	e = e.Where(cond)
 
 	if opts.Page > 0 {
-		e = e.Limit(setting.UI.IssuePagingNum, (opts.Page-1)*setting.UI.IssuePagingNum)
+		pageSize := opts.PageSize
+		if pageSize <= 0 {
+			pageSize = setting.UI.IssuePagingNum
+		}
+		e = e.Limit(pageSize, (opts.Page-1)*pageSize)
 	}
 
 	switch opts.SortType {
@@ -138,6 +162,13 @@ func NewProject(p *Project) error {
 	if !IsProjectTypeValid(p.Type) {
 		return errors.New("project type is not valid")
 	}
+	if p.Type == ProjectTypeRepository {
+		if p.RepoID == 0 {
+			return errors.New("repository project has no repository")
+		}
+	} else if p.OwnerID == 0 {
+		return errors.New("project has no owner")
+	}
 
 	sess := x.NewSession()
 	defer sess.Close()
@@ -150,8 +181,10 @@ func NewProject(p *Project) error {
 		return err
 	}
 
-	if _, err := sess.Exec("UPDATE `repository` SET num_projects = num_projects + 1 WHERE id = ?", p.RepoID); err != nil {
-		return err
+	if p.Type == ProjectTypeRepository {
+		if _, err := sess.Exec("UPDATE `repository` SET num_projects = num_projects + 1 WHERE id = ?", p.RepoID); err != nil {
+			return err
+		}
 	}
 
 	if err := createBoardsForProjectsType(sess, p); err != nil {
@@ -179,6 +212,115 @@ func getProjectByID(e Engine, id int64) (*Project, error) {
 	return p, nil
 }
 
+// LoadRepoOrOwner loads the repository of a repository project or the owner of any other project
+func (p *Project) LoadRepoOrOwner() error {
+	return p.loadRepoOrOwner(x)
+}
+
+func (p *Project) loadRepoOrOwner(e Engine) (err error) {
+	if p.Type == ProjectTypeRepository {
+		if p.Repo == nil {
+			p.Repo, err = getRepositoryByID(e, p.RepoID)
+		}
+		return err
+	}
+	if p.Owner == nil {
+		p.Owner, err = getUserByID(e, p.OwnerID)
+	}
+	return err
+}
+
+// Link returns the relative URL of the project board
+func (p *Project) Link() string {
+	if err := p.loadRepoOrOwner(x); err != nil {
+		log.Error("loadRepoOrOwner[%d]: %v", p.ID, err)
+		return ""
+	}
+	if p.Type == ProjectTypeRepository {
+		return fmt.Sprintf("%s/projects/%d", p.Repo.Link(), p.ID)
+	}
+	return fmt.Sprintf("%s/-/projects/%d", p.Owner.HomeLink(), p.ID)
+}
+
+// HTMLURL returns the absolute URL of the project board
+func (p *Project) HTMLURL() string {
+	if err := p.loadRepoOrOwner(x); err != nil {
+		log.Error("loadRepoOrOwner[%d]: %v", p.ID, err)
+		return ""
+	}
+	if p.Type == ProjectTypeRepository {
+		return fmt.Sprintf("%s/projects/%d", p.Repo.HTMLURL(), p.ID)
+	}
+	return fmt.Sprintf("%s/-/projects/%d", p.Owner.HTMLURL(), p.ID)
+}
+
+// GetProjectAccessMode returns the access mode the doer has on the project. Repository projects
+// follow the projects unit of their repository and are read-only once it is archived, any other
+// project follows the projects of its owner.
+func GetProjectAccessMode(p *Project, doer *User) (AccessMode, error) {
+	if err := p.loadRepoOrOwner(x); err != nil {
+		return AccessModeNone, err
+	}
+	if p.Type != ProjectTypeRepository {
+		return GetOwnerProjectsAccessMode(p.Owner, doer)
+	}
+
+	perm, err := GetUserRepoPermission(p.Repo, doer)
+	if err != nil {
+		return AccessModeNone, err
+	}
+	mode := perm.UnitAccessMode(UnitTypeProjects)
+	if p.Repo.IsArchived && mode > AccessModeRead {
+		mode = AccessModeRead
+	}
+	return mode, nil
+}
+
+// GetOwnerProjectsAccessMode returns the access mode the doer has on the projects of the owner.
+// Users have full access to their own projects, members of an organization get the highest
+// access mode of their teams with the projects unit and everyone else may read the projects
+// of owners visible to them.
+func GetOwnerProjectsAccessMode(owner, doer *User) (AccessMode, error) {
+	if doer != nil && (doer.IsAdmin || doer.ID == owner.ID) {
+		return AccessModeOwner, nil
+	}
+
+	mode := AccessModeNone
+	if owner.IsOrganization() {
+		if !hasOrgVisible(x, owner, doer) {
+			return AccessModeNone, nil
+		}
+		mode = AccessModeRead
+
+		if doer != nil {
+			isOwner, err := isOrganizationOwner(x, owner.ID, doer.ID)
+			if err != nil {
+				return AccessModeNone, err
+			} else if isOwner {
+				return AccessModeOwner, nil
+			}
+
+			teams, err := getUserOrgTeams(x, owner.ID, doer.ID)
+			if err != nil {
+				return AccessModeNone, err
+			}
+			for _, t := range teams {
+				if t.Authorize > mode && t.unitEnabled(x, UnitTypeProjects) {
+					mode = t.Authorize
+				}
+			}
+		}
+	} else if doer == nil {
+		if owner.Visibility == structs.VisibleTypePublic {
+			mode = AccessModeRead
+		}
+	} else if !doer.IsRestricted && owner.Visibility != structs.VisibleTypePrivate {
+		mode = AccessModeRead
+	}
+
+	return mode, nil
+}
+
 // UpdateProject updates project properties
 func UpdateProject(p *Project) error {
 	return updateProject(x, p)
@@ -256,11 +398,11 @@ func ChangeProjectStatus(p *Project, isClosed bool) error {
 func changeProjectStatus(e Engine, p *Project, isClosed bool) error {
 	p.IsClosed = isClosed
 	p.ClosedDateUnix = timeutil.TimeStampNow()
-	count, err := e.ID(p.ID).Where("repo_id = ? AND is_closed = ?", p.RepoID, !isClosed).Cols("is_closed", "closed_date_unix").Update(p)
+	count, err := e.ID(p.ID).Where("is_closed = ?", !isClosed).Cols("is_closed", "closed_date_unix").Update(p)
 	if err != nil {
 		return err
 	}
-	if count < 1 {
+	if count < 1 || p.Type != ProjectTypeRepository {
 		return nil
 	}
 
@@ -303,5 +445,21 @@ func deleteProjectByID(e Engine, id int64) error {
 		return err
 	}
 
+	if p.Type != ProjectTypeRepository {
+		return nil
+	}
 	return updateRepositoryProjectCount(e, p.RepoID)
 }
+
+func deleteProjectsByOwnerID(e Engine, ownerID int64) error {
+	projects := make([]*Project, 0, 10)
+	if err := e.Where("owner_id = ?", ownerID).Find(&projects); err != nil {
+		return err
+	}
+	for _, p := range projects {
+		if err := deleteProjectByID(e, p.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -218,3 +218,21 @@ func (bs ProjectBoardList) LoadIssues() (IssueList, error) {
 	}
 	return issues, nil
 }
+
+// LoadIssuesVisibleTo load issues assigned to the boards which the user is allowed to read
+func (bs ProjectBoardList) LoadIssuesVisibleTo(doer *User) (IssueList, error) {
+	perms := make(map[int64]Permission)
+	issues := make(IssueList, 0, len(bs)*10)
+	for i := range bs {
+		il, err := bs[i].LoadIssues()
+		if err != nil {
+			return nil, err
+		}
+		if il, err = filterIssuesVisibleTo(x, il, doer, perms); err != nil {
+			return nil, err
+		}
+		bs[i].Issues = il
+		issues = append(issues, il...)
+	}
+	return issues, nil
+}
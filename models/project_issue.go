@@ -5,8 +5,7 @@
 package models
 
 import (
-	"fmt"
-
+	"xorm.io/builder"
 	"xorm.io/xorm"
 )
 
@@ -25,13 +24,21 @@ func deleteProjectIssuesByProjectID(e Engine, projectID int64) error {
 	return err
 }
 
+// repoProjectIssueCond returns the condition of the rows linking the issue to a repository
+// project. An issue is on at most one repository project but may be on any number of projects
+// of users and organizations besides.
+func repoProjectIssueCond(issueID int64) builder.Cond {
+	return builder.Eq{"issue_id": issueID}.And(builder.NotIn("project_id",
+		builder.Select("id").From("`project`").Where(builder.Neq{"type": ProjectTypeRepository})))
+}
+
 //  ___
 // |_ _|___ ___ _   _  ___
 //  | |/ __/ __| | | |/ _ \
 //  | |\__ \__ \ |_| |  __/
 // |___|___/___/\__,_|\___|
 
-// LoadProject load the project the issue was assigned to
+// LoadProject load the repository project the issue was assigned to
 func (i *Issue) LoadProject() (err error) {
 	return i.loadProject(x)
 }
@@ -41,7 +48,7 @@ func (i *Issue) loadProject(e Engine) (err error) {
 		var p Project
 		if _, err = e.Table("project").
 			Join("INNER", "project_issue", "project.id=project_issue.project_id").
-			Where("project_issue.issue_id = ?", i.ID).
+			Where("project_issue.issue_id = ? AND project.type = ?", i.ID, ProjectTypeRepository).
 			Get(&p); err != nil {
 			return err
 		}
@@ -50,28 +57,28 @@ func (i *Issue) loadProject(e Engine) (err error) {
 	return
 }
 
-// ProjectID return project id if issue was assigned to one
+// ProjectID return repository project id if issue was assigned to one
 func (i *Issue) ProjectID() int64 {
 	return i.projectID(x)
 }
 
 func (i *Issue) projectID(e Engine) int64 {
 	var ip ProjectIssue
-	has, err := e.Where("issue_id=?", i.ID).Get(&ip)
+	has, err := e.Where(repoProjectIssueCond(i.ID)).Get(&ip)
 	if err != nil || !has {
 		return 0
 	}
 	return ip.ProjectID
 }
 
-// ProjectBoardID return project board id if issue was assigned to one
+// ProjectBoardID return repository project board id if issue was assigned to one
 func (i *Issue) ProjectBoardID() int64 {
 	return i.projectBoardID(x)
 }
 
 func (i *Issue) projectBoardID(e Engine) int64 {
 	var ip ProjectIssue
-	has, err := e.Where("issue_id=?", i.ID).Get(&ip)
+	has, err := e.Where(repoProjectIssueCond(i.ID)).Get(&ip)
 	if err != nil || !has {
 		return 0
 	}
@@ -122,7 +129,7 @@ func (p *Project) NumOpenIssues() int {
 	return int(c)
 }
 
-// ChangeProjectAssign changes the project associated with an issue
+// ChangeProjectAssign changes the repository project associated with an issue
 func ChangeProjectAssign(issue *Issue, doer *User, newProjectID int64) error {
 
 	sess := x.NewSession()
@@ -142,7 +149,7 @@ func addUpdateIssueProject(e *xorm.Session, issue *Issue, doer *User, newProject
 
 	oldProjectID := issue.projectID(e)
 
-	if _, err := e.Where("project_issue.issue_id=?", issue.ID).Delete(&ProjectIssue{}); err != nil {
+	if _, err := e.Where(repoProjectIssueCond(issue.ID)).Delete(&ProjectIssue{}); err != nil {
 		return err
 	}
 
@@ -170,6 +177,133 @@ func addUpdateIssueProject(e *xorm.Session, issue *Issue, doer *User, newProject
 	return err
 }
 
+// AddIssueToProject puts an issue or pull request on a project board. Repository projects only
+// hold issues of their repository and replace the other repository project of the issue, while
+// projects of users and organizations may hold issues of any repository. It returns an
+// ErrBlockedByUser if the doer has been blocked by the owner of the repository or the poster.
+func AddIssueToProject(issue *Issue, doer *User, p *Project) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if p.Type == ProjectTypeRepository {
+		if issue.RepoID != p.RepoID {
+			return ErrProjectIssueNotAllowed{ProjectID: p.ID, IssueID: issue.ID}
+		}
+		if issue.projectID(sess) != p.ID {
+			if err := addUpdateIssueProject(sess, issue, doer, p.ID); err != nil {
+				return err
+			}
+		}
+		return sess.Commit()
+	}
+
+	has, err := sess.Exist(&ProjectIssue{IssueID: issue.ID, ProjectID: p.ID})
+	if err != nil {
+		return err
+	} else if has {
+		return nil
+	}
+
+	if err = checkUserBlockedFromIssue(sess, doer.ID, issue); err != nil {
+		return err
+	}
+
+	if _, err = sess.Insert(&ProjectIssue{IssueID: issue.ID, ProjectID: p.ID}); err != nil {
+		return err
+	}
+
+	comment, err := canCommentProjectIssue(sess, issue, doer)
+	if err != nil {
+		return err
+	} else if comment {
+		if _, err = createComment(sess, &CreateCommentOptions{
+			Type:      CommentTypeProject,
+			Doer:      doer,
+			Repo:      issue.Repo,
+			Issue:     issue,
+			ProjectID: p.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// RemoveIssueFromProject takes an issue or pull request off a project board
+func RemoveIssueFromProject(issue *Issue, doer *User, p *Project) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := sess.Delete(&ProjectIssue{IssueID: issue.ID, ProjectID: p.ID})
+	if err != nil {
+		return err
+	} else if count == 0 {
+		return ErrProjectIssueNotExist{ProjectID: p.ID, IssueID: issue.ID}
+	}
+
+	comment, err := canCommentProjectIssue(sess, issue, doer)
+	if err != nil {
+		return err
+	} else if comment {
+		if _, err = createComment(sess, &CreateCommentOptions{
+			Type:         CommentTypeProject,
+			Doer:         doer,
+			Repo:         issue.Repo,
+			Issue:        issue,
+			OldProjectID: p.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// canCommentProjectIssue returns whether putting the issue on a project of a user or an
+// organization, or taking it off, is recorded in its timeline. Only users who can write the
+// issues of the repository may add such comments, anybody else would be able to spam the
+// timeline of any issue they can read with project titles of their choice.
+func canCommentProjectIssue(e Engine, issue *Issue, doer *User) (bool, error) {
+	if err := issue.loadRepo(e); err != nil {
+		return false, err
+	}
+	perm, err := getUserRepoPermission(e, issue.Repo, doer)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanWriteIssuesOrPulls(issue.IsPull), nil
+}
+
+// filterIssuesVisibleTo returns the issues and pull requests the user is allowed to read,
+// the permissions are cached by repository across calls
+func filterIssuesVisibleTo(e Engine, issues IssueList, doer *User, perms map[int64]Permission) (IssueList, error) {
+	visible := make(IssueList, 0, len(issues))
+	for _, issue := range issues {
+		perm, ok := perms[issue.RepoID]
+		if !ok {
+			if err := issue.loadRepo(e); err != nil {
+				return nil, err
+			}
+			var err error
+			if perm, err = getUserRepoPermission(e, issue.Repo, doer); err != nil {
+				return nil, err
+			}
+			perms[issue.RepoID] = perm
+		}
+		if perm.CanReadIssuesOrPulls(issue.IsPull) {
+			visible = append(visible, issue)
+		}
+	}
+	return visible, nil
+}
+
 //  ____            _           _   ____                      _
 // |  _ \ _ __ ___ (_) ___  ___| |_| __ )  ___   __ _ _ __ __| |
 // | |_) | '__/ _ \| |/ _ \/ __| __|  _ \ / _ \ / _` | '__/ _` |
@@ -177,7 +311,7 @@ func addUpdateIssueProject(e *xorm.Session, issue *Issue, doer *User, newProject
 // |_|   |_|  \___// |\___|\___|\__|____/ \___/ \__,_|_|  \__,_|
 //               |__/
 
-// MoveIssueAcrossProjectBoards move a card from one board to another
+// MoveIssueAcrossProjectBoards move a card from one board to another of the same project
 func MoveIssueAcrossProjectBoards(issue *Issue, board *ProjectBoard) error {
 
 	sess := x.NewSession()
@@ -187,13 +321,13 @@ func MoveIssueAcrossProjectBoards(issue *Issue, board *ProjectBoard) error {
 	}
 
 	var pis ProjectIssue
-	has, err := sess.Where("issue_id=?", issue.ID).Get(&pis)
+	has, err := sess.Where("issue_id=? AND project_id=?", issue.ID, board.ProjectID).Get(&pis)
 	if err != nil {
 		return err
 	}
 
 	if !has {
-		return fmt.Errorf("issue has to be added to a project first")
+		return ErrProjectIssueNotExist{ProjectID: board.ProjectID, IssueID: issue.ID}
 	}
 
 	pis.ProjectBoardID = board.ID
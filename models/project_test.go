@@ -5,6 +5,7 @@
 package models
 
 import (
+	"fmt"
 	"testing"
 
 	"code.gitea.io/gitea/modules/timeutil"
@@ -19,9 +20,9 @@ func TestIsProjectTypeValid(t *testing.T) {
 		typ   ProjectType
 		valid bool
 	}{
-		{ProjectTypeIndividual, false},
+		{ProjectTypeIndividual, true},
 		{ProjectTypeRepository, true},
-		{ProjectTypeOrganization, false},
+		{ProjectTypeOrganization, true},
 		{UnknownType, false},
 	}
 
@@ -80,3 +81,142 @@ func TestProject(t *testing.T) {
 
 	assert.True(t, projectFromDB.IsClosed)
 }
+
+func TestOwnerProject(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	project := &Project{
+		Type:      ProjectTypeOrganization,
+		BoardType: ProjectBoardTypeBasicKanban,
+		Title:     "Organization project",
+		OwnerID:   3,
+		CreatorID: 2,
+	}
+	assert.NoError(t, NewProject(project))
+
+	projects, count, err := GetProjects(ProjectSearchOptions{OwnerID: 3})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, projects, 1) {
+		assert.EqualValues(t, project.ID, projects[0].ID)
+	}
+
+	// Projects of owners do not count as projects of a repository
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	assert.EqualValues(t, 1, repo.NumProjects)
+
+	assert.NoError(t, project.LoadRepoOrOwner())
+	assert.Equal(t, fmt.Sprintf("%s/-/projects/%d", project.Owner.HomeLink(), project.ID), project.Link())
+
+	assert.Error(t, NewProject(&Project{Type: ProjectTypeOrganization, Title: "No owner", CreatorID: 2}))
+}
+
+func TestGetOwnerProjectsAccessMode(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	org := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	admin := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	test := func(owner, doer *User, expected AccessMode) {
+		mode, err := GetOwnerProjectsAccessMode(owner, doer)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, mode)
+	}
+
+	test(org, nil, AccessModeRead)
+	test(org, user2, AccessModeOwner)
+	test(org, user4, AccessModeRead)
+	test(user4, user4, AccessModeOwner)
+	test(user4, admin, AccessModeOwner)
+	test(user2, user4, AccessModeRead)
+
+	// Members of a team with the projects unit get the access mode of the team
+	_, err := x.Insert(&TeamUnit{OrgID: 3, TeamID: 2, Type: UnitTypeProjects})
+	assert.NoError(t, err)
+	test(org, user4, AccessModeWrite)
+}
+
+func TestAddIssueToProject(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	project := &Project{
+		Type:      ProjectTypeOrganization,
+		BoardType: ProjectBoardTypeBasicKanban,
+		Title:     "Cross repository project",
+		OwnerID:   3,
+		CreatorID: 2,
+	}
+	assert.NoError(t, NewProject(project))
+	boards, err := GetProjectBoards(project.ID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, boards)
+
+	// Issues of different repositories may be on the same board
+	issue1 := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	issue6 := AssertExistsAndLoadBean(t, &Issue{ID: 6}).(*Issue)
+	assert.NoError(t, AddIssueToProject(issue1, doer, project))
+	assert.NoError(t, AddIssueToProject(issue1, doer, project))
+	assert.NoError(t, AddIssueToProject(issue6, doer, project))
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeProject, IssueID: issue6.ID, ProjectID: project.ID})
+
+	// The repository project of the issue is kept
+	assert.EqualValues(t, 1, issue1.ProjectID())
+	assert.EqualValues(t, 1, issue1.ProjectBoardID())
+
+	assert.NoError(t, MoveIssueAcrossProjectBoards(issue1, boards[0]))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectID: project.ID, ProjectBoardID: boards[0].ID})
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue1.ID, ProjectID: 1, ProjectBoardID: 1})
+
+	// Only the reader of the repository sees the issue of the private repository
+	allBoards := ProjectBoardList{{ProjectID: project.ID, Default: true}}
+	allBoards = append(allBoards, boards...)
+	issues, err := allBoards.LoadIssuesVisibleTo(doer)
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+	issues, err = allBoards.LoadIssuesVisibleTo(nil)
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, issue1.ID, issues[0].ID)
+	}
+
+	assert.NoError(t, RemoveIssueFromProject(issue6, doer, project))
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypeProject, IssueID: issue6.ID, OldProjectID: project.ID})
+	assert.True(t, IsErrProjectIssueNotExist(RemoveIssueFromProject(issue6, doer, project)))
+	assert.True(t, IsErrProjectIssueNotExist(MoveIssueAcrossProjectBoards(issue6, boards[0])))
+
+	// Repository projects only hold issues of their repository
+	repoProject := AssertExistsAndLoadBean(t, &Project{ID: 1}).(*Project)
+	assert.True(t, IsErrProjectIssueNotAllowed(AddIssueToProject(issue6, doer, repoProject)))
+}
+
+func TestAddIssueToProject_Stranger(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	stranger := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	project := &Project{
+		Type:      ProjectTypeIndividual,
+		BoardType: ProjectBoardTypeNone,
+		Title:     "Spam",
+		OwnerID:   stranger.ID,
+		CreatorID: stranger.ID,
+	}
+	assert.NoError(t, NewProject(project))
+
+	// Users who can only read the repository do not add comments to the timeline of the issue
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	assert.NoError(t, AddIssueToProject(issue, stranger, project))
+	AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: issue.ID, ProjectID: project.ID})
+	AssertNotExistsBean(t, &Comment{Type: CommentTypeProject, IssueID: issue.ID, ProjectID: project.ID})
+
+	assert.NoError(t, RemoveIssueFromProject(issue, stranger, project))
+	AssertNotExistsBean(t, &Comment{Type: CommentTypeProject, IssueID: issue.ID, OldProjectID: project.ID})
+
+	// Blocked users can not put the issues of the blocker on their projects
+	owner := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, BlockUser(owner, stranger, ""))
+	assert.True(t, IsErrBlockedByUser(AddIssueToProject(issue, stranger, project)))
+	AssertNotExistsBean(t, &ProjectIssue{IssueID: issue.ID, ProjectID: project.ID})
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// ProtectedTag restricts the creation, update and deletion of the tags matching the name pattern
+// to the users and teams of the allowlists. Patterns enclosed in slashes are regular expressions,
+// all others are globs.
+type ProtectedTag struct {
+	ID               int64 `xorm:"pk autoincr"`
+	RepoID           int64 `xorm:"INDEX"`
+	NamePattern      string
+	RegexPattern     *regexp.Regexp `xorm:"-"`
+	GlobPattern      glob.Glob      `xorm:"-"`
+	AllowlistUserIDs []int64        `xorm:"JSON TEXT"`
+	AllowlistTeamIDs []int64        `xorm:"JSON TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IsRegex returns true if the name pattern is a regular expression
+func (pt *ProtectedTag) IsRegex() bool {
+	return len(pt.NamePattern) > 2 && strings.HasPrefix(pt.NamePattern, "/") && strings.HasSuffix(pt.NamePattern, "/")
+}
+
+// EnsureCompiledPattern compiles the name pattern if it has not been compiled yet
+func (pt *ProtectedTag) EnsureCompiledPattern() error {
+	if pt.RegexPattern != nil || pt.GlobPattern != nil {
+		return nil
+	}
+
+	var err error
+	if pt.IsRegex() {
+		pt.RegexPattern, err = regexp.Compile(pt.NamePattern[1 : len(pt.NamePattern)-1])
+	} else {
+		pt.GlobPattern, err = glob.Compile(strings.TrimSpace(pt.NamePattern))
+	}
+	if err != nil {
+		return ErrProtectedTagPatternInvalid{Pattern: pt.NamePattern}
+	}
+	return nil
+}
+
+// Match returns true if the tag name matches the name pattern
+func (pt *ProtectedTag) Match(tagName string) bool {
+	if err := pt.EnsureCompiledPattern(); err != nil {
+		return false
+	}
+	if pt.RegexPattern != nil {
+		return pt.RegexPattern.MatchString(tagName)
+	}
+	return pt.GlobPattern.Match(tagName)
+}
+
+// IsUserAllowed returns true if the user is on one of the allowlists
+func (pt *ProtectedTag) IsUserAllowed(userID int64) (bool, error) {
+	if base.Int64sContains(pt.AllowlistUserIDs, userID) {
+		return true, nil
+	}
+	if len(pt.AllowlistTeamIDs) == 0 {
+		return false, nil
+	}
+	return IsUserInTeams(userID, pt.AllowlistTeamIDs)
+}
+
+// IsUserAllowedToControlTag returns true if the user is allowed to create, update or delete the tag
+// by all protected tags whose pattern matches its name
+func IsUserAllowedToControlTag(tags []*ProtectedTag, tagName string, userID int64) (bool, error) {
+	for _, pt := range tags {
+		if !pt.Match(tagName) {
+			continue
+		}
+		isAllowed, err := pt.IsUserAllowed(userID)
+		if err != nil || !isAllowed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// GetProtectedTags returns the protected tags of the repository
+func (repo *Repository) GetProtectedTags() ([]*ProtectedTag, error) {
+	tags := make([]*ProtectedTag, 0, 5)
+	return tags, x.Where("repo_id = ?", repo.ID).Asc("id").Find(&tags)
+}
+
+// GetProtectedTagByID returns the protected tag of the repository by its ID
+func (repo *Repository) GetProtectedTagByID(id int64) (*ProtectedTag, error) {
+	pt := &ProtectedTag{ID: id, RepoID: repo.ID}
+	has, err := x.Get(pt)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrProtectedTagNotExist{ID: id}
+	}
+	return pt, nil
+}
+
+// SaveProtectedTag inserts or updates the protected tag. Only users with write access
+// and teams with access to the repository are kept on the allowlists.
+func SaveProtectedTag(repo *Repository, pt *ProtectedTag, allowlistUserIDs, allowlistTeamIDs []int64) (err error) {
+	pt.NamePattern = strings.TrimSpace(pt.NamePattern)
+	pt.RegexPattern, pt.GlobPattern = nil, nil
+	if pt.NamePattern == "" {
+		return ErrProtectedTagPatternInvalid{Pattern: pt.NamePattern}
+	}
+	if err = pt.EnsureCompiledPattern(); err != nil {
+		return err
+	}
+
+	if err = repo.GetOwner(); err != nil {
+		return err
+	}
+	if pt.AllowlistUserIDs, err = updateUserWhitelist(repo, pt.AllowlistUserIDs, allowlistUserIDs); err != nil {
+		return err
+	}
+	if repo.Owner.IsOrganization() {
+		if pt.AllowlistTeamIDs, err = updateTeamWhitelist(repo, pt.AllowlistTeamIDs, allowlistTeamIDs); err != nil {
+			return err
+		}
+	} else {
+		pt.AllowlistTeamIDs = nil
+	}
+
+	pt.RepoID = repo.ID
+	if pt.ID == 0 {
+		_, err = x.Insert(pt)
+		return err
+	}
+	_, err = x.ID(pt.ID).AllCols().Update(pt)
+	return err
+}
+
+// DeleteProtectedTag deletes the protected tag of the repository
+func (repo *Repository) DeleteProtectedTag(id int64) error {
+	n, err := x.Delete(&ProtectedTag{ID: id, RepoID: repo.ID})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrProtectedTagNotExist{ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectedTagMatch(t *testing.T) {
+	kases := []struct {
+		pattern  string
+		matches  []string
+		excludes []string
+	}{
+		{"v1.0.0", []string{"v1.0.0"}, []string{"v1.0.1", "v1.0.0-rc1"}},
+		{"v*", []string{"v1.0.0", "v2"}, []string{"release-v1"}},
+		{"v1.?.*", []string{"v1.2.0", "v1.2.3"}, []string{"v1.10.0"}},
+		{"/^v[0-9]+$/", []string{"v1", "v23"}, []string{"v1.0", "release-v1"}},
+		{"/gitea/", []string{"gitea", "v1-gitea"}, []string{"gogs"}},
+	}
+	for _, kase := range kases {
+		pt := &ProtectedTag{NamePattern: kase.pattern}
+		assert.NoError(t, pt.EnsureCompiledPattern())
+		for _, name := range kase.matches {
+			assert.True(t, pt.Match(name), "%s should match %s", kase.pattern, name)
+		}
+		for _, name := range kase.excludes {
+			assert.False(t, pt.Match(name), "%s should not match %s", kase.pattern, name)
+		}
+	}
+
+	pt := &ProtectedTag{NamePattern: "/[/"}
+	assert.True(t, IsErrProtectedTagPatternInvalid(pt.EnsureCompiledPattern()))
+	assert.False(t, pt.Match("["))
+}
+
+func TestIsUserAllowedToControlTag(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	tags := []*ProtectedTag{
+		{NamePattern: "v*", AllowlistUserIDs: []int64{1, 2}},
+		{NamePattern: "v1.*", AllowlistUserIDs: []int64{2}},
+	}
+
+	isAllowed, err := IsUserAllowedToControlTag(tags, "release", 3)
+	assert.NoError(t, err)
+	assert.True(t, isAllowed)
+
+	isAllowed, err = IsUserAllowedToControlTag(tags, "v2.0.0", 1)
+	assert.NoError(t, err)
+	assert.True(t, isAllowed)
+
+	isAllowed, err = IsUserAllowedToControlTag(tags, "v1.0.0", 1)
+	assert.NoError(t, err)
+	assert.False(t, isAllowed)
+
+	isAllowed, err = IsUserAllowedToControlTag(tags, "v1.0.0", 2)
+	assert.NoError(t, err)
+	assert.True(t, isAllowed)
+
+	// user 2 is a member of team 1 of org 3
+	tags = []*ProtectedTag{{NamePattern: "*", AllowlistTeamIDs: []int64{1}}}
+	isAllowed, err = IsUserAllowedToControlTag(tags, "v1.0.0", 2)
+	assert.NoError(t, err)
+	assert.True(t, isAllowed)
+
+	isAllowed, err = IsUserAllowedToControlTag(tags, "v1.0.0", 4)
+	assert.NoError(t, err)
+	assert.False(t, isAllowed)
+}
+
+func TestSaveProtectedTag(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	pt := &ProtectedTag{NamePattern: " v* "}
+	// user 4 has no write access to the repository
+	assert.NoError(t, SaveProtectedTag(repo, pt, []int64{2, 4}, []int64{1}))
+	assert.NotZero(t, pt.ID)
+
+	pt = AssertExistsAndLoadBean(t, &ProtectedTag{ID: pt.ID, RepoID: repo.ID}).(*ProtectedTag)
+	assert.Equal(t, "v*", pt.NamePattern)
+	assert.Equal(t, []int64{2}, pt.AllowlistUserIDs)
+	assert.Empty(t, pt.AllowlistTeamIDs)
+
+	pt.NamePattern = "/[/"
+	assert.True(t, IsErrProtectedTagPatternInvalid(SaveProtectedTag(repo, pt, []int64{2}, nil)))
+
+	tags, err := repo.GetProtectedTags()
+	assert.NoError(t, err)
+	assert.Len(t, tags, 1)
+	assert.Equal(t, "v*", tags[0].NamePattern)
+
+	assert.NoError(t, repo.DeleteProtectedTag(pt.ID))
+	AssertNotExistsBean(t, &ProtectedTag{ID: pt.ID})
+	assert.True(t, IsErrProtectedTagNotExist(repo.DeleteProtectedTag(pt.ID)))
+
+	_, err = repo.GetProtectedTagByID(pt.ID)
+	assert.True(t, IsErrProtectedTagNotExist(err))
+}
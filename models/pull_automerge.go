@@ -0,0 +1,133 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+	"xorm.io/xorm"
+)
+
+// PullAutoMerge represents a pull request which is merged automatically by the doer
+// with the merge style and message once all required status checks and approvals pass
+type PullAutoMerge struct {
+	ID          int64              `xorm:"pk autoincr"`
+	PullID      int64              `xorm:"UNIQUE"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	Doer        *User              `xorm:"-"`
+	MergeStyle  MergeStyle         `xorm:"varchar(30)"`
+	Message     string             `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// LoadDoer loads the user who scheduled the merge
+func (m *PullAutoMerge) LoadDoer() (err error) {
+	if m.Doer != nil {
+		return nil
+	}
+	m.Doer, err = GetUserByID(m.DoerID)
+	return err
+}
+
+// ScheduleAutoMerge schedules the pull request to be merged automatically
+// and adds a comment to its timeline
+func ScheduleAutoMerge(doer *User, pr *PullRequest, style MergeStyle, message string) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if exist, err := sess.Exist(&PullAutoMerge{PullID: pr.ID}); err != nil {
+		return err
+	} else if exist {
+		return ErrPullAutoMergeAlreadyScheduled{PullID: pr.ID}
+	}
+
+	if _, err := sess.Insert(&PullAutoMerge{
+		PullID:     pr.ID,
+		DoerID:     doer.ID,
+		MergeStyle: style,
+		Message:    message,
+	}); err != nil {
+		return err
+	}
+
+	if err := createAutoMergeComment(sess, CommentTypePRScheduledToAutoMerge, doer, pr); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// UnscheduleAutoMerge cancels the scheduled merge of the pull request
+// and adds a comment of the doer to its timeline
+func UnscheduleAutoMerge(doer *User, pr *PullRequest) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if n, err := sess.Delete(&PullAutoMerge{PullID: pr.ID}); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrPullAutoMergeNotExist{PullID: pr.ID}
+	}
+
+	if err := createAutoMergeComment(sess, CommentTypePRUnScheduledToAutoMerge, doer, pr); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+func createAutoMergeComment(e *xorm.Session, typ CommentType, doer *User, pr *PullRequest) error {
+	if err := pr.loadIssue(e); err != nil {
+		return err
+	}
+	if err := pr.Issue.loadRepo(e); err != nil {
+		return err
+	}
+	_, err := createComment(e, &CreateCommentOptions{
+		Type:  typ,
+		Doer:  doer,
+		Repo:  pr.Issue.Repo,
+		Issue: pr.Issue,
+	})
+	return err
+}
+
+// DeleteScheduledAutoMerge removes the scheduled merge of the pull request without
+// a comment, e.g. because it has been merged
+func DeleteScheduledAutoMerge(pullID int64) error {
+	_, err := x.Delete(&PullAutoMerge{PullID: pullID})
+	return err
+}
+
+// GetScheduledAutoMergeByPullID returns the scheduled merge of the pull request
+func GetScheduledAutoMergeByPullID(pullID int64) (*PullAutoMerge, error) {
+	m := &PullAutoMerge{PullID: pullID}
+	has, err := x.Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPullAutoMergeNotExist{PullID: pullID}
+	}
+	return m, nil
+}
+
+// GetScheduledAutoMergePullIDsByRepoID returns the IDs of the unmerged pull requests with a
+// scheduled merge whose base or head repository is the repository
+func GetScheduledAutoMergePullIDsByRepoID(repoID int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, x.Table("pull_auto_merge").
+		Join("INNER", "pull_request", "pull_request.id = pull_auto_merge.pull_id").
+		Where(builder.Eq{"pull_request.has_merged": false}).
+		And(builder.Or(builder.Eq{"pull_request.base_repo_id": repoID}, builder.Eq{"pull_request.head_repo_id": repoID})).
+		Cols("pull_auto_merge.pull_id").
+		Find(&ids)
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleAutoMerge(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	pr := AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+
+	_, err := GetScheduledAutoMergeByPullID(pr.ID)
+	assert.True(t, IsErrPullAutoMergeNotExist(err))
+	assert.True(t, IsErrPullAutoMergeNotExist(UnscheduleAutoMerge(doer, pr)))
+
+	assert.NoError(t, ScheduleAutoMerge(doer, pr, MergeStyleSquash, "message"))
+	assert.True(t, IsErrPullAutoMergeAlreadyScheduled(ScheduleAutoMerge(doer, pr, MergeStyleMerge, "")))
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypePRScheduledToAutoMerge, PosterID: doer.ID, IssueID: pr.IssueID})
+
+	scheduled, err := GetScheduledAutoMergeByPullID(pr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, MergeStyleSquash, scheduled.MergeStyle)
+	assert.Equal(t, "message", scheduled.Message)
+	assert.NoError(t, scheduled.LoadDoer())
+	assert.Equal(t, doer.ID, scheduled.Doer.ID)
+
+	ids, err := GetScheduledAutoMergePullIDsByRepoID(pr.BaseRepoID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{pr.ID}, ids)
+	ids, err = GetScheduledAutoMergePullIDsByRepoID(3)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	assert.NoError(t, UnscheduleAutoMerge(doer, pr))
+	AssertExistsAndLoadBean(t, &Comment{Type: CommentTypePRUnScheduledToAutoMerge, PosterID: doer.ID, IssueID: pr.IssueID})
+	AssertNotExistsBean(t, &PullAutoMerge{PullID: pr.ID})
+
+	assert.NoError(t, ScheduleAutoMerge(doer, pr, MergeStyleMerge, ""))
+	assert.NoError(t, DeleteScheduledAutoMerge(pr.ID))
+	AssertNotExistsBean(t, &PullAutoMerge{PullID: pr.ID})
+}
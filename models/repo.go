@@ -752,6 +752,30 @@ func (repo *Repository) GetReviewers(doerID, posterID int64) (_ []*User, err err
 	return repo.getReviewers(x, doerID, posterID)
 }
 
+// GetReviewerTeams get all teams can be requested to review,
+// that are the teams of the owner organization with access to the pull requests of the repository.
+func (repo *Repository) GetReviewerTeams() ([]*Team, error) {
+	if err := repo.GetOwner(); err != nil {
+		return nil, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return nil, nil
+	}
+
+	teams, err := GetTeamsWithAccessToRepo(repo.OwnerID, repo.ID, AccessModeRead)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewerTeams := make([]*Team, 0, len(teams))
+	for _, team := range teams {
+		if team.UnitEnabled(UnitTypePullRequests) {
+			reviewerTeams = append(reviewerTeams, team)
+		}
+	}
+	return reviewerTeams, nil
+}
+
 // GetMilestoneByID returns the milestone belongs to repository by given ID.
 func (repo *Repository) GetMilestoneByID(milestoneID int64) (*Milestone, error) {
 	return GetMilestoneByRepoID(repo.ID, milestoneID)
@@ -1110,7 +1134,7 @@ func GetRepoInitFile(tp, name string) ([]byte, error) {
 }
 
 var (
-	reservedRepoNames    = []string{".", ".."}
+	reservedRepoNames    = []string{".", "..", "-"}
 	reservedRepoPatterns = []string{"*.git", "*.wiki"}
 )
 
@@ -1125,6 +1149,10 @@ func CreateRepository(ctx DBContext, doer, u *User, repo *Repository) (err error
 		return err
 	}
 
+	if err = checkProbationLimit(ctx.e, doer, ProbationLimitRepo); err != nil {
+		return err
+	}
+
 	has, err := isRepositoryExist(ctx.e, u, repo.Name)
 	if err != nil {
 		return fmt.Errorf("IsRepositoryExist: %v", err)
@@ -1644,18 +1672,26 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
+	if _, err = sess.In("pull_id", builder.Select("id").From("pull_request").Where(builder.Eq{"base_repo_id": repoID})).
+		Delete(new(PullAutoMerge)); err != nil {
+		return err
+	}
+
 	if err = deleteBeans(sess,
 		&Access{RepoID: repo.ID},
 		&Action{RepoID: repo.ID},
 		&Watch{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Mirror{RepoID: repoID},
+		&PushMirror{RepoID: repoID},
+		&ProtectedTag{RepoID: repoID},
 		&Milestone{RepoID: repoID},
 		&Release{RepoID: repoID},
 		&Collaboration{RepoID: repoID},
 		&PullRequest{BaseRepoID: repoID},
 		&RepoUnit{RepoID: repoID},
 		&RepoRedirect{RedirectRepoID: repoID},
+		&IssueRedirect{OldRepoID: repoID},
 		&Webhook{RepoID: repoID},
 		&HookTask{RepoID: repoID},
 		&Notification{RepoID: repoID},
@@ -1696,6 +1732,7 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 
 	projects, _, err := getProjects(sess, ProjectSearchOptions{
 		RepoID: repoID,
+		Type:   ProjectTypeRepository,
 	})
 	if err != nil {
 		return fmt.Errorf("get projects: %v", err)
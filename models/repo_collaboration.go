@@ -20,6 +20,10 @@ type Collaboration struct {
 }
 
 func (repo *Repository) addCollaborator(e Engine, u *User) error {
+	if err := checkUserBlockedBy(e, u.ID, repo.OwnerID); err != nil {
+		return err
+	}
+
 	collaboration := &Collaboration{
 		RepoID: repo.ID,
 		UserID: u.ID,
@@ -170,37 +174,41 @@ func (repo *Repository) ChangeCollaborationAccessMode(uid int64, mode AccessMode
 
 // DeleteCollaboration removes collaboration relation between the user and repository.
 func (repo *Repository) DeleteCollaboration(uid int64) (err error) {
-	collaboration := &Collaboration{
-		RepoID: repo.ID,
-		UserID: uid,
-	}
-
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
 		return err
 	}
 
-	if has, err := sess.Delete(collaboration); err != nil || has == 0 {
-		return err
-	} else if err = repo.recalculateAccesses(sess); err != nil {
+	if err = repo.deleteCollaboration(sess, uid); err != nil {
 		return err
 	}
 
-	if err = watchRepo(sess, uid, repo.ID, false); err != nil {
+	return sess.Commit()
+}
+
+func (repo *Repository) deleteCollaboration(e Engine, uid int64) (err error) {
+	collaboration := &Collaboration{
+		RepoID: repo.ID,
+		UserID: uid,
+	}
+
+	if has, err := e.Delete(collaboration); err != nil || has == 0 {
+		return err
+	} else if err = repo.recalculateAccesses(e); err != nil {
 		return err
 	}
 
-	if err = repo.reconsiderWatches(sess, uid); err != nil {
+	if err = watchRepo(e, uid, repo.ID, false); err != nil {
 		return err
 	}
 
-	// Unassign a user from any issue (s)he has been assigned to in the repository
-	if err := repo.reconsiderIssueAssignees(sess, uid); err != nil {
+	if err = repo.reconsiderWatches(e, uid); err != nil {
 		return err
 	}
 
-	return sess.Commit()
+	// Unassign a user from any issue (s)he has been assigned to in the repository
+	return repo.reconsiderIssueAssignees(e, uid)
 }
 
 func (repo *Repository) reconsiderIssueAssignees(e Engine, uid int64) error {
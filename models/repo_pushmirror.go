@@ -0,0 +1,131 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/xorm"
+)
+
+// PushMirror represents a remote address the repository is pushed to.
+type PushMirror struct {
+	ID     int64       `xorm:"pk autoincr"`
+	RepoID int64       `xorm:"INDEX"`
+	Repo   *Repository `xorm:"-"`
+
+	// RemoteAddress is the remote address without credentials, used for display
+	RemoteAddress string `xorm:"TEXT"`
+	// EncryptedRemoteAddress is the full remote address including credentials,
+	// encrypted with the SECRET_KEY
+	EncryptedRemoteAddress string `xorm:"TEXT"`
+
+	Interval       time.Duration
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	LastUpdateUnix timeutil.TimeStamp `xorm:"INDEX last_update"`
+	NextUpdateUnix timeutil.TimeStamp `xorm:"INDEX"`
+	LastError      string             `xorm:"TEXT"`
+}
+
+// AfterLoad is invoked from XORM after setting the values of all fields of this object.
+func (m *PushMirror) AfterLoad(session *xorm.Session) {
+	if m == nil {
+		return
+	}
+
+	var err error
+	m.Repo, err = getRepositoryByID(session, m.RepoID)
+	if err != nil {
+		log.Error("getRepositoryByID[%d]: %v", m.ID, err)
+	}
+}
+
+// SetRemoteAddress stores the given address encrypted and keeps a sanitized copy for display.
+func (m *PushMirror) SetRemoteAddress(addr string) error {
+	encrypted, err := secret.EncryptSecret(setting.SecretKey, addr)
+	if err != nil {
+		return err
+	}
+	m.EncryptedRemoteAddress = encrypted
+	m.RemoteAddress = util.SanitizeURLCredentials(addr, false)
+	return nil
+}
+
+// DecryptRemoteAddress returns the remote address including credentials.
+func (m *PushMirror) DecryptRemoteAddress() (string, error) {
+	return secret.DecryptSecret(setting.SecretKey, m.EncryptedRemoteAddress)
+}
+
+// ScheduleNextUpdate calculates and sets next update time.
+func (m *PushMirror) ScheduleNextUpdate() {
+	if m.Interval != 0 {
+		m.NextUpdateUnix = timeutil.TimeStampNow().AddDuration(m.Interval)
+	} else {
+		m.NextUpdateUnix = 0
+	}
+}
+
+// InsertPushMirror inserts a push mirror to database
+func InsertPushMirror(m *PushMirror) error {
+	m.ScheduleNextUpdate()
+	_, err := x.Insert(m)
+	return err
+}
+
+// UpdatePushMirror updates the push mirror
+func UpdatePushMirror(m *PushMirror) error {
+	_, err := x.ID(m.ID).AllCols().Update(m)
+	return err
+}
+
+// DeletePushMirrorByRepoIDAndID deletes a push mirror of a repository by its ID
+func DeletePushMirrorByRepoIDAndID(repoID, id int64) error {
+	_, err := x.ID(id).And("repo_id = ?", repoID).Delete(new(PushMirror))
+	return err
+}
+
+// GetPushMirrorByID returns a push mirror by its ID
+func GetPushMirrorByID(id int64) (*PushMirror, error) {
+	m := new(PushMirror)
+	has, err := x.ID(id).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPushMirrorNotExist{ID: id}
+	}
+	return m, nil
+}
+
+// GetPushMirrorByRepoIDAndID returns a push mirror of a repository by its ID
+func GetPushMirrorByRepoIDAndID(repoID, id int64) (*PushMirror, error) {
+	m := new(PushMirror)
+	has, err := x.ID(id).And("repo_id = ?", repoID).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPushMirrorNotExist{ID: id, RepoID: repoID}
+	}
+	return m, nil
+}
+
+// GetPushMirrorsByRepoID returns all push mirrors of a repository
+func GetPushMirrorsByRepoID(repoID int64) ([]*PushMirror, error) {
+	mirrors := make([]*PushMirror, 0, 5)
+	return mirrors, x.Where("repo_id=?", repoID).Asc("id").Find(&mirrors)
+}
+
+// PushMirrorsIterate iterates all push mirrors that are due to be synchronized.
+func PushMirrorsIterate(f func(idx int, bean interface{}) error) error {
+	return x.
+		Where("next_update_unix<=?", time.Now().Unix()).
+		And("next_update_unix!=0").
+		Iterate(new(PushMirror), f)
+}
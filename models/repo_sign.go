@@ -88,10 +88,23 @@ func SigningKey(repoPath string) (string, *git.Signature) {
 	}
 }
 
+// signingFormat returns the format of the signatures made with the signing key of the repo
+func signingFormat(repoPath string) string {
+	if setting.Repository.Signing.SigningKey == "default" || setting.Repository.Signing.SigningKey == "" {
+		format, _ := git.NewCommand("config", "--get", "gpg.format").RunInDir(repoPath)
+		if format = strings.TrimSpace(format); format != "" {
+			return format
+		}
+		return git.SigningFormatOpenPGP
+	}
+	return setting.Repository.Signing.SigningFormat
+}
+
 // PublicSigningKey gets the public signing key within a provided repository directory
 func PublicSigningKey(repoPath string) (string, error) {
 	signingKey, _ := SigningKey(repoPath)
-	if signingKey == "" {
+	if signingKey == "" || signingFormat(repoPath) == git.SigningFormatSSH {
+		// SSH signing keys have no GPG public key to export
 		return "", nil
 	}
 
@@ -122,6 +122,11 @@ func watchRepo(e Engine, userID, repoID int64, doWatch bool) (err error) {
 
 // WatchRepo watch or unwatch repository.
 func WatchRepo(userID, repoID int64, watch bool) (err error) {
+	if watch {
+		if err = checkUserBlockedFromRepo(x, userID, repoID); err != nil {
+			return err
+		}
+	}
 	return watchRepo(x, userID, repoID, watch)
 }
 
@@ -256,7 +261,7 @@ func notifyWatchers(e Engine, actions ...*Action) error {
 				if !permCode[i] {
 					continue
 				}
-			case ActionCreateIssue, ActionCommentIssue, ActionCloseIssue, ActionReopenIssue:
+			case ActionCreateIssue, ActionCommentIssue, ActionCloseIssue, ActionReopenIssue, ActionTransferIssue:
 				if !permIssue[i] {
 					continue
 				}
@@ -54,6 +54,8 @@ type Review struct {
 	Type             ReviewType
 	Reviewer         *User `xorm:"-"`
 	ReviewerID       int64 `xorm:"index"`
+	ReviewerTeamID   int64 `xorm:"NOT NULL DEFAULT 0"`
+	ReviewerTeam     *Team `xorm:"-"`
 	OriginalAuthor   string
 	OriginalAuthorID int64
 	Issue            *Issue `xorm:"-"`
@@ -110,6 +112,19 @@ func (r *Review) LoadReviewer() error {
 	return r.loadReviewer(x)
 }
 
+func (r *Review) loadReviewerTeam(e Engine) (err error) {
+	if r.ReviewerTeam != nil || r.ReviewerTeamID == 0 {
+		return nil
+	}
+	r.ReviewerTeam, err = getTeamByID(e, r.ReviewerTeamID)
+	return
+}
+
+// LoadReviewerTeam loads the team a review was requested from
+func (r *Review) LoadReviewerTeam() error {
+	return r.loadReviewerTeam(x)
+}
+
 func (r *Review) loadAttributes(e Engine) (err error) {
 	if err = r.loadIssue(e); err != nil {
 		return
@@ -120,6 +135,9 @@ func (r *Review) loadAttributes(e Engine) (err error) {
 	if err = r.loadReviewer(e); err != nil {
 		return
 	}
+	if err = r.loadReviewerTeam(e); err != nil {
+		return
+	}
 	return
 }
 
@@ -187,15 +205,16 @@ func FindReviews(opts FindReviewOptions) ([]*Review, error) {
 	return findReviews(x, opts)
 }
 
-// CreateReviewOptions represent the options to create a review. Type, Issue and Reviewer are required.
+// CreateReviewOptions represent the options to create a review. Type, Issue and Reviewer or ReviewerTeam are required.
 type CreateReviewOptions struct {
-	Content  string
-	Type     ReviewType
-	Issue    *Issue
-	Reviewer *User
-	Official bool
-	CommitID string
-	Stale    bool
+	Content      string
+	Type         ReviewType
+	Issue        *Issue
+	Reviewer     *User
+	ReviewerTeam *Team
+	Official     bool
+	CommitID     string
+	Stale        bool
 }
 
 // IsOfficialReviewer check if reviewer can make official reviews in issue (counts towards required approvals)
@@ -218,17 +237,42 @@ func isOfficialReviewer(e Engine, issue *Issue, reviewer *User) (bool, error) {
 	return pr.ProtectedBranch.isUserOfficialReviewer(e, reviewer)
 }
 
+// IsOfficialReviewerTeam check if the members of the team can make official reviews in issue (counts towards required approvals)
+func IsOfficialReviewerTeam(issue *Issue, team *Team) (bool, error) {
+	return isOfficialReviewerTeam(x, issue, team)
+}
+
+func isOfficialReviewerTeam(e Engine, issue *Issue, team *Team) (bool, error) {
+	pr, err := getPullRequestByIssueID(e, issue.ID)
+	if err != nil {
+		return false, err
+	}
+	if err = pr.loadProtectedBranch(e); err != nil {
+		return false, err
+	}
+	if pr.ProtectedBranch == nil {
+		return false, nil
+	}
+
+	return pr.ProtectedBranch.isTeamOfficialReviewer(e, team), nil
+}
+
 func createReview(e Engine, opts CreateReviewOptions) (*Review, error) {
 	review := &Review{
-		Type:       opts.Type,
-		Issue:      opts.Issue,
-		IssueID:    opts.Issue.ID,
-		Reviewer:   opts.Reviewer,
-		ReviewerID: opts.Reviewer.ID,
-		Content:    opts.Content,
-		Official:   opts.Official,
-		CommitID:   opts.CommitID,
-		Stale:      opts.Stale,
+		Type:         opts.Type,
+		Issue:        opts.Issue,
+		IssueID:      opts.Issue.ID,
+		Reviewer:     opts.Reviewer,
+		ReviewerTeam: opts.ReviewerTeam,
+		Content:      opts.Content,
+		Official:     opts.Official,
+		CommitID:     opts.CommitID,
+		Stale:        opts.Stale,
+	}
+	if opts.Reviewer != nil {
+		review.ReviewerID = opts.Reviewer.ID
+	} else if opts.ReviewerTeam != nil {
+		review.ReviewerTeamID = opts.ReviewerTeam.ID
 	}
 	if _, err := e.Insert(review); err != nil {
 		return nil, err
@@ -263,8 +307,18 @@ func getCurrentReview(e Engine, reviewer *User, issue *Issue) (*Review, error) {
 }
 
 // ReviewExists returns whether a review exists for a particular line of code in the PR
-func ReviewExists(issue *Issue, treePath string, line int64) (bool, error) {
-	return x.Cols("id").Exist(&Comment{IssueID: issue.ID, TreePath: treePath, Line: line, Type: CommentTypeCode})
+func ReviewExists(issue *Issue, treePath string, line, startLine int64) (bool, error) {
+	// start_line is compared explicitly as the zero value of a single line would be ignored
+	return x.Where("start_line = ?", startLine).Cols("id").
+		Exist(&Comment{IssueID: issue.ID, TreePath: treePath, Line: line, Type: CommentTypeCode})
+}
+
+// GetReviewThreadStartLine returns the first line of the thread of code comments of the review
+// which ends on the line of the file, and false if the review has no such thread.
+func GetReviewThreadStartLine(reviewID int64, treePath string, line int64) (int64, bool, error) {
+	comment := &Comment{ReviewID: reviewID, TreePath: treePath, Line: line, Type: CommentTypeCode}
+	has, err := x.Asc("id").Cols("start_line").Get(comment)
+	return comment.StartLine, has, err
 }
 
 // GetCurrentReview returns the current pending review of reviewer for given issue
@@ -361,6 +415,12 @@ func SubmitReview(doer *User, issue *Issue, reviewType ReviewType, content, comm
 		}
 	}
 
+	if reviewType == ReviewTypeApprove || reviewType == ReviewTypeReject {
+		if err := removeTeamReviewRequestsOfMember(sess, issue, doer); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	comm, err := createComment(sess, &CreateCommentOptions{
 		Type:     CommentTypeReview,
 		Doer:     doer,
@@ -388,7 +448,7 @@ func GetReviewersByIssueID(issueID int64) (reviews []*Review, err error) {
 	}
 
 	// Get latest review of each reviwer, sorted in order they were made
-	if err := sess.SQL("SELECT * FROM review WHERE id IN (SELECT max(id) as id FROM review WHERE issue_id = ? AND type in (?, ?, ?) GROUP BY issue_id, reviewer_id) ORDER BY review.updated_unix ASC",
+	if err := sess.SQL("SELECT * FROM review WHERE id IN (SELECT max(id) as id FROM review WHERE issue_id = ? AND reviewer_team_id = 0 AND type in (?, ?, ?) GROUP BY issue_id, reviewer_id) ORDER BY review.updated_unix ASC",
 		issueID, ReviewTypeApprove, ReviewTypeReject, ReviewTypeRequest).
 		Find(&reviewsUnfiltered); err != nil {
 		return nil, err
@@ -408,6 +468,30 @@ func GetReviewersByIssueID(issueID int64) (reviews []*Review, err error) {
 	return reviews, nil
 }
 
+// GetReviewerTeamsByIssueID gets the pending review requests of teams for a pull request
+func GetReviewerTeamsByIssueID(issueID int64) (reviews []*Review, err error) {
+	reviewsUnfiltered := []*Review{}
+
+	if err := x.Where("issue_id = ? AND reviewer_team_id > 0 AND type = ?", issueID, ReviewTypeRequest).
+		Asc("updated_unix").
+		Find(&reviewsUnfiltered); err != nil {
+		return nil, err
+	}
+
+	// Load team and skip if team is deleted
+	for _, review := range reviewsUnfiltered {
+		if err = review.loadReviewerTeam(x); err != nil {
+			if !IsErrTeamNotExist(err) {
+				return nil, err
+			}
+		} else {
+			reviews = append(reviews, review)
+		}
+	}
+
+	return reviews, nil
+}
+
 // GetReviewerByIssueIDAndUserID get the latest review of reviewer for a pull request
 func GetReviewerByIssueIDAndUserID(issueID, userID int64) (review *Review, err error) {
 	return getReviewerByIssueIDAndUserID(x, issueID, userID)
@@ -425,6 +509,25 @@ func getReviewerByIssueIDAndUserID(e Engine, issueID, userID int64) (review *Rev
 	return
 }
 
+// GetTeamReviewerByIssueIDAndTeamID get the pending review request of a team for a pull request
+func GetTeamReviewerByIssueIDAndTeamID(issueID, teamID int64) (review *Review, err error) {
+	return getTeamReviewerByIssueIDAndTeamID(x, issueID, teamID)
+}
+
+func getTeamReviewerByIssueIDAndTeamID(e Engine, issueID, teamID int64) (review *Review, err error) {
+	review = new(Review)
+
+	has, err := e.Where("issue_id = ? AND reviewer_team_id = ? AND type = ?", issueID, teamID, ReviewTypeRequest).
+		Get(review)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+
+	return review, nil
+}
+
 // MarkReviewsAsStale marks existing reviews as stale
 func MarkReviewsAsStale(issueID int64) (err error) {
 	_, err = x.Exec("UPDATE `review` SET stale=? WHERE issue_id=?", true, issueID)
@@ -612,6 +715,127 @@ func RemoveReviewRequest(issue *Issue, reviewer *User, doer *User) (comment *Com
 	return comment, sess.Commit()
 }
 
+// AddTeamReviewRequest add a review request from one team
+func AddTeamReviewRequest(issue *Issue, reviewer *Team, doer *User) (comment *Comment, err error) {
+	review, err := GetTeamReviewerByIssueIDAndTeamID(issue.ID, reviewer.ID)
+	if err != nil {
+		return
+	}
+
+	// skip it when the team has been requested to review
+	if review != nil {
+		return nil, nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	var official bool
+	official, err = isOfficialReviewerTeam(sess, issue, reviewer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !official {
+		official, err = isOfficialReviewer(sess, issue, doer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = createReview(sess, CreateReviewOptions{
+		Type:         ReviewTypeRequest,
+		Issue:        issue,
+		ReviewerTeam: reviewer,
+		Official:     official,
+		Stale:        false,
+	})
+	if err != nil {
+		return
+	}
+
+	comment, err = createComment(sess, &CreateCommentOptions{
+		Type:            CommentTypeReviewRequest,
+		Doer:            doer,
+		Repo:            issue.Repo,
+		Issue:           issue,
+		RemovedAssignee: false,       // Use RemovedAssignee as !isRequest
+		AssigneeTeamID:  reviewer.ID, // Use AssigneeTeamID as reviewer team ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, sess.Commit()
+}
+
+// RemoveTeamReviewRequest remove a review request from one team
+func RemoveTeamReviewRequest(issue *Issue, reviewer *Team, doer *User) (comment *Comment, err error) {
+	review, err := GetTeamReviewerByIssueIDAndTeamID(issue.ID, reviewer.ID)
+	if err != nil {
+		return
+	}
+
+	if review == nil {
+		return nil, nil
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	if _, err = sess.Delete(review); err != nil {
+		return nil, err
+	}
+
+	comment, err = createComment(sess, &CreateCommentOptions{
+		Type:            CommentTypeReviewRequest,
+		Doer:            doer,
+		Repo:            issue.Repo,
+		Issue:           issue,
+		RemovedAssignee: true,        // Use RemovedAssignee as !isRequest
+		AssigneeTeamID:  reviewer.ID, // Use AssigneeTeamID as reviewer team ID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, sess.Commit()
+}
+
+// removeTeamReviewRequestsOfMember removes the pending review requests of the teams the doer is a member of,
+// the review of any member satisfies the request of the team
+func removeTeamReviewRequestsOfMember(e Engine, issue *Issue, doer *User) error {
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+	if err := issue.Repo.getOwner(e); err != nil {
+		return err
+	}
+	if !issue.Repo.Owner.IsOrganization() {
+		return nil
+	}
+
+	teams, err := getUserOrgTeams(e, issue.Repo.OwnerID, doer.ID)
+	if err != nil || len(teams) == 0 {
+		return err
+	}
+	teamIDs := make([]int64, len(teams))
+	for i, team := range teams {
+		teamIDs[i] = team.ID
+	}
+
+	_, err = e.Where("issue_id = ? AND type = ?", issue.ID, ReviewTypeRequest).
+		In("reviewer_team_id", teamIDs).
+		Delete(new(Review))
+	return err
+}
+
 // MarkConversation Add or remove Conversation mark for a code comment
 func MarkConversation(comment *Comment, doer *User, isResolve bool) (err error) {
 	if comment.Type != CommentTypeCode {
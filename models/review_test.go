@@ -139,3 +139,97 @@ func TestGetReviewersByIssueID(t *testing.T) {
 		}
 	}
 }
+
+func TestTeamReviewRequest(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	repo := AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	doer := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	member := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	team := AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+
+	issue := &Issue{RepoID: repo.ID, Repo: repo, Index: 100, PosterID: doer.ID, Title: "team review", IsPull: true}
+	_, err := x.Insert(issue)
+	assert.NoError(t, err)
+	pr := &PullRequest{IssueID: issue.ID, BaseRepoID: repo.ID, HeadRepoID: repo.ID, BaseBranch: "master", HeadBranch: "feature"}
+	_, err = x.Insert(pr)
+	assert.NoError(t, err)
+	protectedBranch := &ProtectedBranch{
+		RepoID:                    repo.ID,
+		BranchName:                "master",
+		EnableApprovalsWhitelist:  true,
+		ApprovalsWhitelistTeamIDs: []int64{team.ID},
+		BlockOnRejectedReviews:    true,
+	}
+	_, err = x.Insert(protectedBranch)
+	assert.NoError(t, err)
+
+	comment, err := AddTeamReviewRequest(issue, team, doer)
+	assert.NoError(t, err)
+	if assert.NotNil(t, comment) {
+		assert.EqualValues(t, team.ID, comment.AssigneeTeamID)
+		assert.False(t, comment.RemovedAssignee)
+	}
+	review := AssertExistsAndLoadBean(t, &Review{IssueID: issue.ID, ReviewerTeamID: team.ID, Type: ReviewTypeRequest}).(*Review)
+	assert.True(t, review.Official)
+	assert.True(t, protectedBranch.MergeBlockedByRejectedReview(pr))
+
+	// requesting the same team again does nothing
+	comment, err = AddTeamReviewRequest(issue, team, doer)
+	assert.NoError(t, err)
+	assert.Nil(t, comment)
+
+	teamReviews, err := GetReviewerTeamsByIssueID(issue.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, teamReviews, 1) {
+		assert.Equal(t, team.Name, teamReviews[0].ReviewerTeam.Name)
+	}
+	reviews, err := GetReviewersByIssueID(issue.ID)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 0)
+
+	// the approval of any member satisfies the request of the team
+	_, _, err = SubmitReview(member, issue, ReviewTypeApprove, "", "", false)
+	assert.NoError(t, err)
+	AssertNotExistsBean(t, &Review{IssueID: issue.ID, ReviewerTeamID: team.ID, Type: ReviewTypeRequest})
+	assert.False(t, protectedBranch.MergeBlockedByRejectedReview(pr))
+	assert.EqualValues(t, 1, protectedBranch.GetGrantedApprovalsCount(pr))
+
+	_, err = AddTeamReviewRequest(issue, team, doer)
+	assert.NoError(t, err)
+	comment, err = RemoveTeamReviewRequest(issue, team, doer)
+	assert.NoError(t, err)
+	if assert.NotNil(t, comment) {
+		assert.True(t, comment.RemovedAssignee)
+	}
+	AssertNotExistsBean(t, &Review{IssueID: issue.ID, ReviewerTeamID: team.ID, Type: ReviewTypeRequest})
+}
+
+func TestReviewExists(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	issue := AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+	exists, err := ReviewExists(issue, "README.md", 4, 0)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// a range ending on the line of a single line thread is another thread
+	exists, err = ReviewExists(issue, "README.md", 4, 2)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = x.ID(4).Cols("start_line").Update(&Comment{StartLine: 2})
+	assert.NoError(t, err)
+	exists, err = ReviewExists(issue, "README.md", 4, 2)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	startLine, has, err := GetReviewThreadStartLine(4, "README.md", 4)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	assert.EqualValues(t, 2, startLine)
+
+	_, has, err = GetReviewThreadStartLine(4, "README.md", 5)
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	tplUserCommand  = "%s --config=%q serv user-%d"
+	tplCertificate  = tplCommentPrefix + "\n" + `cert-authority,principals=%q,command=%q,no-port-forwarding,no-X11-forwarding,no-agent-forwarding,no-pty %s` + "\n"
+	sourceAddressOp = "source-address"
+)
+
+// IsTrustedUserCA returns true if the key is one of the certificate authorities trusted to sign user certificates
+func IsTrustedUserCA(key ssh.PublicKey) bool {
+	marshaled := key.Marshal()
+	for _, caKey := range setting.SSH.TrustedUserCAKeysParsed {
+		if bytes.Equal(caKey.Marshal(), marshaled) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUserByCertificate verifies that the user certificate is signed by a trusted certificate
+// authority, is currently valid and only has supported critical options. It returns the user
+// the first valid principal maps to and the principal. The source addresses the certificate
+// is restricted to are checked if the address of the client is given.
+func GetUserByCertificate(cert *ssh.Certificate, remoteAddr net.Addr) (*User, string, error) {
+	if len(setting.SSH.TrustedUserCAKeysParsed) == 0 {
+		return nil, "", ErrCertificateNotAuthorized{"no trusted certificate authorities are configured"}
+	}
+	if cert.CertType != ssh.UserCert {
+		return nil, "", ErrCertificateNotAuthorized{"not a user certificate"}
+	}
+	if !IsTrustedUserCA(cert.SignatureKey) {
+		return nil, "", ErrCertificateNotAuthorized{"signed by an untrusted certificate authority"}
+	}
+
+	checker := &ssh.CertChecker{
+		IsUserAuthority:          IsTrustedUserCA,
+		SupportedCriticalOptions: []string{sourceAddressOp},
+	}
+	for _, principal := range cert.ValidPrincipals {
+		user, err := getUserByPrincipal(principal)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, "", err
+		}
+
+		if err := checker.CheckCert(principal, cert); err != nil {
+			return nil, "", ErrCertificateNotAuthorized{err.Error()}
+		}
+		if sourceAddresses, ok := cert.CriticalOptions[sourceAddressOp]; ok && remoteAddr != nil {
+			if err := checkSourceAddress(remoteAddr, sourceAddresses); err != nil {
+				return nil, "", ErrCertificateNotAuthorized{err.Error()}
+			}
+		}
+		if !user.IsActive || user.ProhibitLogin {
+			return nil, "", ErrCertificateNotAuthorized{fmt.Sprintf("user %s is not allowed to log in", user.Name)}
+		}
+		return user, principal, nil
+	}
+	return nil, "", ErrCertificateNotAuthorized{"no principal maps to a user"}
+}
+
+// getUserByPrincipal maps a principal to a user by the allowed principal kinds
+func getUserByPrincipal(principal string) (*User, error) {
+	// principals are listed in the authorized keys line and must not be ambiguous there
+	if principal == "" || strings.ContainsAny(principal, ",\"\\ ") {
+		return nil, ErrUserNotExist{Name: principal}
+	}
+	for _, allow := range setting.SSH.AuthorizedPrincipalsAllow {
+		var user *User
+		var err error
+		switch allow {
+		case "username":
+			user, err = GetUserByName(principal)
+		case "email":
+			user, err = GetUserByEmail(principal)
+		default:
+			continue
+		}
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if user.IsOrganization() {
+			continue
+		}
+		return user, nil
+	}
+	return nil, ErrUserNotExist{Name: principal}
+}
+
+// checkSourceAddress checks the address of the client against the comma separated
+// addresses and CIDR ranges of the source-address critical option
+func checkSourceAddress(addr net.Addr, sourceAddresses string) error {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return fmt.Errorf("remote address %s is not a TCP address", addr)
+	}
+	for _, sourceAddress := range strings.Split(sourceAddresses, ",") {
+		if allowedIP := net.ParseIP(sourceAddress); allowedIP != nil {
+			if allowedIP.Equal(tcpAddr.IP) {
+				return nil
+			}
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(sourceAddress)
+		if err != nil {
+			return fmt.Errorf("invalid source address %q: %v", sourceAddress, err)
+		}
+		if ipNet.Contains(tcpAddr.IP) {
+			return nil
+		}
+	}
+	return fmt.Errorf("remote address %s is not in the source addresses %s", addr, sourceAddresses)
+}
+
+// AuthorizedCertificateString returns the authorized keys line which lets sshd accept
+// certificates of the principal signed by the certificate authority as the user
+func AuthorizedCertificateString(user *User, principal string, caKey ssh.PublicKey) string {
+	return fmt.Sprintf(tplCertificate, principal,
+		fmt.Sprintf(tplUserCommand, setting.AppPath, setting.CustomConf, user.ID),
+		strings.TrimSpace(string(ssh.MarshalAuthorizedKey(caKey))))
+}
@@ -0,0 +1,124 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+func newTestSigner(t *testing.T) ssh.Signer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	assert.NoError(t, err)
+	return signer
+}
+
+func newTestCertificate(t *testing.T, ca ssh.Signer, principals []string, criticalOptions map[string]string) *ssh.Certificate {
+	userKey := newTestSigner(t)
+	cert := &ssh.Certificate{
+		Key:             userKey.PublicKey(),
+		CertType:        ssh.UserCert,
+		KeyId:           "test",
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(time.Now().Add(-time.Hour).Unix()),
+		ValidBefore:     uint64(time.Now().Add(time.Hour).Unix()),
+		Permissions: ssh.Permissions{
+			CriticalOptions: criticalOptions,
+		},
+	}
+	assert.NoError(t, cert.SignCert(rand.Reader, ca))
+	return cert
+}
+
+func TestGetUserByCertificate(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	ca := newTestSigner(t)
+	defer func(keys []ssh.PublicKey, allow []string) {
+		setting.SSH.TrustedUserCAKeysParsed = keys
+		setting.SSH.AuthorizedPrincipalsAllow = allow
+	}(setting.SSH.TrustedUserCAKeysParsed, setting.SSH.AuthorizedPrincipalsAllow)
+	setting.SSH.AuthorizedPrincipalsAllow = []string{"username"}
+
+	cert := newTestCertificate(t, ca, []string{"user2"}, nil)
+
+	setting.SSH.TrustedUserCAKeysParsed = nil
+	_, _, err := GetUserByCertificate(cert, nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+
+	setting.SSH.TrustedUserCAKeysParsed = []ssh.PublicKey{ca.PublicKey()}
+	user, principal, err := GetUserByCertificate(cert, nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, user.ID)
+	assert.Equal(t, "user2", principal)
+
+	// the first principal mapping to a user is used, organizations are skipped
+	user, principal, err = GetUserByCertificate(newTestCertificate(t, ca, []string{"nobody", "user3", "user4"}, nil), nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, user.ID)
+	assert.Equal(t, "user4", principal)
+
+	_, _, err = GetUserByCertificate(newTestCertificate(t, ca, []string{"user2@example.com"}, nil), nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+	setting.SSH.AuthorizedPrincipalsAllow = []string{"email"}
+	user, _, err = GetUserByCertificate(newTestCertificate(t, ca, []string{"user2@example.com"}, nil), nil)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, user.ID)
+	setting.SSH.AuthorizedPrincipalsAllow = []string{"username"}
+
+	// inactive users are not allowed to log in
+	_, _, err = GetUserByCertificate(newTestCertificate(t, ca, []string{"user9"}, nil), nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+
+	_, _, err = GetUserByCertificate(newTestCertificate(t, newTestSigner(t), []string{"user2"}, nil), nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+
+	expired := newTestCertificate(t, ca, []string{"user2"}, nil)
+	expired.ValidBefore = uint64(time.Now().Add(-time.Minute).Unix())
+	assert.NoError(t, expired.SignCert(rand.Reader, ca))
+	_, _, err = GetUserByCertificate(expired, nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+
+	hostCert := newTestCertificate(t, ca, []string{"user2"}, nil)
+	hostCert.CertType = ssh.HostCert
+	assert.NoError(t, hostCert.SignCert(rand.Reader, ca))
+	_, _, err = GetUserByCertificate(hostCert, nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+
+	_, _, err = GetUserByCertificate(newTestCertificate(t, ca, []string{"user2"}, map[string]string{"force-command": "true"}), nil)
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+
+	restricted := newTestCertificate(t, ca, []string{"user2"}, map[string]string{"source-address": "192.168.1.1,10.0.0.0/8"})
+	_, _, err = GetUserByCertificate(restricted, &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 22})
+	assert.NoError(t, err)
+	_, _, err = GetUserByCertificate(restricted, &net.TCPAddr{IP: net.ParseIP("192.168.1.1"), Port: 22})
+	assert.NoError(t, err)
+	_, _, err = GetUserByCertificate(restricted, &net.TCPAddr{IP: net.ParseIP("192.168.1.2"), Port: 22})
+	assert.True(t, IsErrCertificateNotAuthorized(err))
+}
+
+func TestAuthorizedCertificateString(t *testing.T) {
+	ca := newTestSigner(t)
+	line := AuthorizedCertificateString(&User{ID: 2}, "user2", ca.PublicKey())
+	assert.Contains(t, line, `cert-authority,principals="user2",command=`)
+	assert.Contains(t, line, "serv user-2")
+	assert.Contains(t, line, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(ca.PublicKey()))))
+
+	pubKey, _, options, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimPrefix(line, tplCommentPrefix)))
+	assert.NoError(t, err)
+	assert.Equal(t, ca.PublicKey().Marshal(), pubKey.Marshal())
+	assert.Contains(t, options, "no-pty")
+}
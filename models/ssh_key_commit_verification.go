@@ -0,0 +1,269 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshSignatureArmorStart = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureArmorEnd   = "-----END SSH SIGNATURE-----"
+	sshSignatureMagic      = "SSHSIG"
+	sshSignatureVersion    = 1
+	// sshSignatureNamespace is the namespace git signs commits and tags in
+	sshSignatureNamespace = "git"
+)
+
+// sshSignature is a signature in the SSHSIG format made by ssh-keygen -Y sign
+type sshSignature struct {
+	PublicKey     ssh.PublicKey
+	Namespace     string
+	HashAlgorithm string
+	Signature     *ssh.Signature
+}
+
+// IsSSHSignature returns true if the armored signature of a commit is an SSH signature
+func IsSSHSignature(signature string) bool {
+	return strings.HasPrefix(strings.TrimSpace(signature), sshSignatureArmorStart)
+}
+
+func parseSSHSignature(armored string) (*sshSignature, error) {
+	armored = strings.TrimSpace(armored)
+	if !strings.HasPrefix(armored, sshSignatureArmorStart) || !strings.HasSuffix(armored, sshSignatureArmorEnd) {
+		return nil, fmt.Errorf("Failed to read SSH signature armor")
+	}
+	body := strings.Join(strings.Fields(armored[len(sshSignatureArmorStart):len(armored)-len(sshSignatureArmorEnd)]), "")
+	blob, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode SSH signature: %v", err)
+	}
+
+	var wire struct {
+		Magic         [6]byte
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}
+	if err := ssh.Unmarshal(blob, &wire); err != nil {
+		return nil, fmt.Errorf("Failed to read SSH signature: %v", err)
+	}
+	if string(wire.Magic[:]) != sshSignatureMagic || wire.Version != sshSignatureVersion {
+		return nil, fmt.Errorf("Unsupported SSH signature version: %d", wire.Version)
+	}
+	publicKey, err := ssh.ParsePublicKey(wire.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read SSH signature key: %v", err)
+	}
+	signature := new(ssh.Signature)
+	if err := ssh.Unmarshal(wire.Signature, signature); err != nil {
+		return nil, fmt.Errorf("Failed to read SSH signature blob: %v", err)
+	}
+	return &sshSignature{
+		PublicKey:     publicKey,
+		Namespace:     wire.Namespace,
+		HashAlgorithm: wire.HashAlgorithm,
+		Signature:     signature,
+	}, nil
+}
+
+// verify checks that the signature over the payload is made by the key of the signature
+func (sig *sshSignature) verify(payload string) error {
+	if sig.Namespace != sshSignatureNamespace {
+		return fmt.Errorf("signature namespace is %q instead of %q", sig.Namespace, sshSignatureNamespace)
+	}
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported hash algorithm %q", sig.HashAlgorithm)
+	}
+	_, _ = h.Write([]byte(payload))
+
+	signedData := append([]byte(sshSignatureMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{
+		Namespace:     sig.Namespace,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})...)
+	return sig.PublicKey.Verify(signedData, sig.Signature)
+}
+
+// isSignedBy returns true if the signature names the public key
+func (sig *sshSignature) isSignedBy(publicKey ssh.PublicKey) bool {
+	return bytes.Equal(sig.PublicKey.Marshal(), publicKey.Marshal())
+}
+
+// parseCommitWithSSHSignature checks the SSH signature of a commit against the SSH keys of the
+// committer and the default signing keys.
+func parseCommitWithSSHSignature(c *git.Commit, committer *User) *CommitVerification {
+	sig, err := parseSSHSignature(c.Signature.Signature)
+	if err != nil {
+		log.Error("SignatureRead err: %v", err)
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Reason:         "gpg.error.extract_sign",
+		}
+	}
+	fingerprint := ssh.FingerprintSHA256(sig.PublicKey)
+
+	if committer.ID != 0 {
+		emails, err := GetEmailAddresses(committer.ID)
+		if err != nil {
+			log.Error("GetEmailAddresses: %v", err)
+			return &CommitVerification{
+				CommittingUser: committer,
+				Verified:       false,
+				Reason:         "gpg.error.failed_retrieval_gpg_keys",
+			}
+		}
+		// The keys of the committer only vouch for the commit if they proved to own its email
+		canValidate := false
+		for _, e := range emails {
+			if e.IsActivated && strings.EqualFold(e.Email, c.Committer.Email) {
+				canValidate = true
+				break
+			}
+		}
+
+		var keys []*PublicKey
+		if canValidate {
+			if keys, err = ListPublicKeys(committer.ID, ListOptions{}); err != nil {
+				log.Error("ListPublicKeys: %v", err)
+				return &CommitVerification{
+					CommittingUser: committer,
+					Verified:       false,
+					Reason:         "gpg.error.failed_retrieval_gpg_keys",
+				}
+			}
+		}
+
+		for _, k := range keys {
+			if k.Type != KeyTypeUser {
+				continue
+			}
+			publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k.Content))
+			if err != nil || !sig.isSignedBy(publicKey) {
+				continue
+			}
+			if err := sig.verify(c.Signature.Payload); err != nil {
+				log.Debug("SSH signature of %s does not verify with key %s: %v", c.ID, k.Fingerprint, err)
+				return &CommitVerification{
+					CommittingUser: committer,
+					Verified:       false,
+					Warning:        true,
+					Reason:         BadSignature,
+					SigningSSHKey:  k,
+				}
+			}
+			return &CommitVerification{
+				CommittingUser: committer,
+				Verified:       true,
+				Reason:         fmt.Sprintf("%s / %s", committer.Name, k.Fingerprint),
+				SigningUser:    committer,
+				SigningSSHKey:  k,
+				SigningEmail:   c.Committer.Email,
+			}
+		}
+	}
+
+	if setting.Repository.Signing.SigningFormat == git.SigningFormatSSH && setting.Repository.Signing.SigningKey != "" &&
+		setting.Repository.Signing.SigningKey != "default" && setting.Repository.Signing.SigningKey != "none" {
+		// OK we should try the default key
+		gpgSettings := git.GPGSettings{
+			Sign:   true,
+			KeyID:  setting.Repository.Signing.SigningKey,
+			Name:   setting.Repository.Signing.SigningName,
+			Email:  setting.Repository.Signing.SigningEmail,
+			Format: git.SigningFormatSSH,
+		}
+		if err := gpgSettings.LoadPublicKeyContent(); err != nil {
+			log.Error("Error getting default signing key: %s %v", gpgSettings.KeyID, err)
+		} else if commitVerification := verifyWithSSHSettings(&gpgSettings, sig, c.Signature.Payload, committer); commitVerification != nil {
+			return commitVerification
+		}
+	}
+
+	defaultGPGSettings, err := c.GetRepositoryDefaultPublicGPGKey(false)
+	if err != nil {
+		log.Error("Error getting default public gpg key: %v", err)
+	} else if defaultGPGSettings == nil {
+		log.Warn("Unable to get defaultGPGSettings for unattached commit: %s", c.ID.String())
+	} else if defaultGPGSettings.Sign && defaultGPGSettings.Format == git.SigningFormatSSH {
+		if commitVerification := verifyWithSSHSettings(defaultGPGSettings, sig, c.Signature.Payload, committer); commitVerification != nil {
+			return commitVerification
+		}
+	}
+
+	return &CommitVerification{
+		CommittingUser: committer,
+		Verified:       false,
+		Reason:         NoKeyFound,
+		SigningSSHKey: &PublicKey{
+			Fingerprint: fingerprint,
+		},
+	}
+}
+
+func verifyWithSSHSettings(gpgSettings *git.GPGSettings, sig *sshSignature, payload string, committer *User) *CommitVerification {
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(gpgSettings.PublicKeyContent))
+	if err != nil {
+		log.Error("Unable to parse default SSH signing key: %s %v", gpgSettings.KeyID, err)
+		return nil
+	}
+	if !sig.isSignedBy(publicKey) {
+		return nil
+	}
+
+	key := &PublicKey{
+		Name:        gpgSettings.Name,
+		Fingerprint: ssh.FingerprintSHA256(publicKey),
+		Content:     gpgSettings.PublicKeyContent,
+	}
+	if err := sig.verify(payload); err != nil {
+		// This is a bad situation ... The signature names our default key but the key doesn't verify it.
+		return &CommitVerification{
+			CommittingUser: committer,
+			Verified:       false,
+			Warning:        true,
+			Reason:         BadDefaultSignature,
+			SigningSSHKey:  key,
+		}
+	}
+	return &CommitVerification{
+		CommittingUser: committer,
+		Verified:       true,
+		Reason:         fmt.Sprintf("%s / %s", gpgSettings.Name, key.Fingerprint),
+		SigningUser: &User{
+			Name:  gpgSettings.Name,
+			Email: gpgSettings.Email,
+		},
+		SigningSSHKey: key,
+		SigningEmail:  gpgSettings.Email,
+	}
+}
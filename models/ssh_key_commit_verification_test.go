@@ -0,0 +1,111 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// signSSH signs the payload like ssh-keygen -Y sign -n git
+func signSSH(t *testing.T, signer ssh.Signer, payload string) string {
+	h := sha512.Sum512([]byte(payload))
+	signedData := append([]byte(sshSignatureMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{sshSignatureNamespace, "", "sha512", h[:]})...)
+	signature, err := signer.Sign(rand.Reader, signedData)
+	assert.NoError(t, err)
+
+	blob := ssh.Marshal(struct {
+		Magic         [6]byte
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{
+		Version:       sshSignatureVersion,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(signature),
+	})
+	copy(blob, sshSignatureMagic)
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var armored strings.Builder
+	armored.WriteString(sshSignatureArmorStart + "\n")
+	for len(encoded) > 70 {
+		armored.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	armored.WriteString(encoded + "\n" + sshSignatureArmorEnd + "\n")
+	return armored.String()
+}
+
+func TestParseCommitWithSSHSignature(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	signer := newTestSigner(t)
+	key, err := AddPublicKey(2, "signing", strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), 0)
+	assert.NoError(t, err)
+
+	payload := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\nauthor user2 <user2@example.com> 1600000000 +0000\ncommitter user2 <user2@example.com> 1600000000 +0000\n\nsigned with ssh\n"
+	newCommit := func(email, signature, payload string) *git.Commit {
+		return &git.Commit{
+			Committer: &git.Signature{Name: "user2", Email: email},
+			Signature: &git.CommitGPGSignature{Signature: signature, Payload: payload},
+		}
+	}
+	signature := signSSH(t, signer, payload)
+	assert.True(t, IsSSHSignature(signature))
+
+	verification := ParseCommitWithSignature(newCommit("user2@example.com", signature, payload))
+	assert.True(t, verification.Verified)
+	assert.EqualValues(t, 2, verification.SigningUser.ID)
+	assert.Equal(t, key.Fingerprint, verification.SigningSSHKey.Fingerprint)
+	assert.Equal(t, "user2@example.com", verification.SigningEmail)
+
+	// the payload was changed after signing
+	verification = ParseCommitWithSignature(newCommit("user2@example.com", signature, payload+"tampered"))
+	assert.False(t, verification.Verified)
+	assert.True(t, verification.Warning)
+	assert.Equal(t, BadSignature, verification.Reason)
+
+	// the key belongs to another user than the committer
+	verification = ParseCommitWithSignature(newCommit("user4@example.com", signature, payload))
+	assert.False(t, verification.Verified)
+	assert.Equal(t, NoKeyFound, verification.Reason)
+	assert.Equal(t, key.Fingerprint, verification.SigningSSHKey.Fingerprint)
+
+	// the committer email is the primary email of the user but was never activated
+	_, err = x.ID(2).Cols("email").Update(&User{Email: "user21@example.com"})
+	assert.NoError(t, err)
+	verification = ParseCommitWithSignature(newCommit("user21@example.com", signature, payload))
+	assert.False(t, verification.Verified)
+	assert.Equal(t, NoKeyFound, verification.Reason)
+	_, err = x.ID(2).Cols("email").Update(&User{Email: "user2@example.com"})
+	assert.NoError(t, err)
+
+	verification = ParseCommitWithSignature(newCommit("user2@example.com", signSSH(t, newTestSigner(t), payload), payload))
+	assert.False(t, verification.Verified)
+	assert.Equal(t, NoKeyFound, verification.Reason)
+
+	verification = ParseCommitWithSignature(newCommit("user2@example.com", sshSignatureArmorStart+"\nAAAA\n"+sshSignatureArmorEnd, payload))
+	assert.False(t, verification.Verified)
+	assert.Equal(t, "gpg.error.extract_sign", verification.Reason)
+}
@@ -21,36 +21,49 @@ func StarRepo(userID, repoID int64, star bool) error {
 	}
 
 	if star {
-		if isStaring(sess, userID, repoID) {
+		if err := checkUserBlockedFromRepo(sess, userID, repoID); err != nil {
+			return err
+		}
+	}
+
+	if err := starRepo(sess, userID, repoID, star); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+func starRepo(e Engine, userID, repoID int64, star bool) error {
+	if star {
+		if isStaring(e, userID, repoID) {
 			return nil
 		}
 
-		if _, err := sess.Insert(&Star{UID: userID, RepoID: repoID}); err != nil {
+		if _, err := e.Insert(&Star{UID: userID, RepoID: repoID}); err != nil {
 			return err
 		}
-		if _, err := sess.Exec("UPDATE `repository` SET num_stars = num_stars + 1 WHERE id = ?", repoID); err != nil {
+		if _, err := e.Exec("UPDATE `repository` SET num_stars = num_stars + 1 WHERE id = ?", repoID); err != nil {
 			return err
 		}
-		if _, err := sess.Exec("UPDATE `user` SET num_stars = num_stars + 1 WHERE id = ?", userID); err != nil {
+		if _, err := e.Exec("UPDATE `user` SET num_stars = num_stars + 1 WHERE id = ?", userID); err != nil {
 			return err
 		}
 	} else {
-		if !isStaring(sess, userID, repoID) {
+		if !isStaring(e, userID, repoID) {
 			return nil
 		}
 
-		if _, err := sess.Delete(&Star{0, userID, repoID}); err != nil {
+		if _, err := e.Delete(&Star{0, userID, repoID}); err != nil {
 			return err
 		}
-		if _, err := sess.Exec("UPDATE `repository` SET num_stars = num_stars - 1 WHERE id = ?", repoID); err != nil {
+		if _, err := e.Exec("UPDATE `repository` SET num_stars = num_stars - 1 WHERE id = ?", repoID); err != nil {
 			return err
 		}
-		if _, err := sess.Exec("UPDATE `user` SET num_stars = num_stars - 1 WHERE id = ?", userID); err != nil {
+		if _, err := e.Exec("UPDATE `user` SET num_stars = num_stars - 1 WHERE id = ?", userID); err != nil {
 			return err
 		}
 	}
-
-	return sess.Commit()
+	return nil
 }
 
 // IsStaring checks if user has starred given repository.
@@ -17,20 +17,21 @@ import (
 
 // Task represents a task
 type Task struct {
-	ID             int64
-	DoerID         int64       `xorm:"index"` // operator
-	Doer           *User       `xorm:"-"`
-	OwnerID        int64       `xorm:"index"` // repo owner id, when creating, the repoID maybe zero
-	Owner          *User       `xorm:"-"`
-	RepoID         int64       `xorm:"index"`
-	Repo           *Repository `xorm:"-"`
-	Type           structs.TaskType
-	Status         structs.TaskStatus `xorm:"index"`
-	StartTime      timeutil.TimeStamp
-	EndTime        timeutil.TimeStamp
-	PayloadContent string             `xorm:"TEXT"`
-	Errors         string             `xorm:"TEXT"` // if task failed, saved the error reason
-	Created        timeutil.TimeStamp `xorm:"created"`
+	ID              int64
+	DoerID          int64       `xorm:"index"` // operator
+	Doer            *User       `xorm:"-"`
+	OwnerID         int64       `xorm:"index"` // repo owner id, when creating, the repoID maybe zero
+	Owner           *User       `xorm:"-"`
+	RepoID          int64       `xorm:"index"`
+	Repo            *Repository `xorm:"-"`
+	Type            structs.TaskType
+	Status          structs.TaskStatus `xorm:"index"`
+	StartTime       timeutil.TimeStamp
+	EndTime         timeutil.TimeStamp
+	PayloadContent  string             `xorm:"TEXT"`
+	ProgressContent string             `xorm:"TEXT"` // the progress of a migration at its last checkpoint
+	Errors          string             `xorm:"TEXT"` // if task failed, saved the error reason
+	Created         timeutil.TimeStamp `xorm:"created"`
 }
 
 // LoadRepo loads repository of the task
@@ -114,6 +115,31 @@ func (task *Task) MigrateConfig() (*migration.MigrateOptions, error) {
 	return nil, fmt.Errorf("Task type is %s, not Migrate Repo", task.Type.Name())
 }
 
+// MigrateProgress returns the progress of a migration, which is nil before the migration has started
+func (task *Task) MigrateProgress() (*migration.MigrateProgress, error) {
+	if task.Type != structs.TaskTypeMigrateRepo {
+		return nil, fmt.Errorf("Task type is %s, not Migrate Repo", task.Type.Name())
+	}
+	if task.ProgressContent == "" {
+		return nil, nil
+	}
+	var progress migration.MigrateProgress
+	if err := json.Unmarshal([]byte(task.ProgressContent), &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+// UpdateMigrateProgress saves the progress of a migration
+func (task *Task) UpdateMigrateProgress(progress *migration.MigrateProgress) error {
+	bs, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	task.ProgressContent = string(bs)
+	return task.UpdateCols("progress_content")
+}
+
 // ErrTaskDoesNotExist represents a "TaskDoesNotExist" kind of error.
 type ErrTaskDoesNotExist struct {
 	ID     int64
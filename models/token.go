@@ -7,6 +7,7 @@ package models
 
 import (
 	"crypto/subtle"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/modules/base"
@@ -16,7 +17,8 @@ import (
 	gouuid "github.com/google/uuid"
 )
 
-// AccessToken represents a personal access token.
+// AccessToken represents a personal access token. The scope limits the resources the token
+// grants access to and a non-empty list of repository IDs restricts it to these repositories.
 type AccessToken struct {
 	ID             int64 `xorm:"pk autoincr"`
 	UID            int64 `xorm:"INDEX"`
@@ -24,7 +26,9 @@ type AccessToken struct {
 	Token          string `xorm:"-"`
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
 	TokenSalt      string
-	TokenLastEight string `xorm:"token_last_eight"`
+	TokenLastEight string           `xorm:"token_last_eight"`
+	Scope          AccessTokenScope `xorm:"NOT NULL DEFAULT 'all'"`
+	RepoIDs        []int64          `xorm:"JSON TEXT"`
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -38,6 +42,82 @@ func (t *AccessToken) AfterLoad() {
 	t.HasRecentActivity = t.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
 }
 
+// HasScope returns true if the token grants at least the level on the category
+func (t *AccessToken) HasScope(category AccessTokenScopeCategory, level AccessTokenScopeLevel) bool {
+	return t.Scope.HasScope(category, level)
+}
+
+// CanAccessRepo returns true if the token is not restricted to other repositories
+func (t *AccessToken) CanAccessRepo(repoID int64) bool {
+	if len(t.RepoIDs) == 0 {
+		return true
+	}
+	for _, id := range t.RepoIDs {
+		if id == repoID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRestricted returns true if the token has a limited scope or is restricted to repositories
+func (t *AccessToken) IsRestricted() bool {
+	return !t.Scope.IsAll() || len(t.RepoIDs) > 0
+}
+
+// SetRepositories restricts the token to the repositories with the full names "owner/name".
+// The owner of the token needs access to all of them.
+func (t *AccessToken) SetRepositories(fullNames []string) error {
+	u, err := GetUserByID(t.UID)
+	if err != nil {
+		return err
+	}
+
+	repoIDs := make([]int64, 0, len(fullNames))
+	for _, fullName := range fullNames {
+		fullName = strings.TrimSpace(fullName)
+		if fullName == "" {
+			continue
+		}
+		parts := strings.SplitN(fullName, "/", 2)
+		if len(parts) != 2 {
+			return ErrRepoNotExist{Name: fullName}
+		}
+		repo, err := GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil {
+			return err
+		}
+		perm, err := GetUserRepoPermission(repo, u)
+		if err != nil {
+			return err
+		}
+		if !perm.HasAccess() {
+			return ErrRepoNotExist{OwnerName: parts[0], Name: parts[1]}
+		}
+		repoIDs = append(repoIDs, repo.ID)
+	}
+	t.RepoIDs = repoIDs
+	return nil
+}
+
+// RepositoryNames returns the full names of the repositories the token is restricted to
+func (t *AccessToken) RepositoryNames() ([]string, error) {
+	if len(t.RepoIDs) == 0 {
+		return []string{}, nil
+	}
+	repos, err := GetRepositoriesMapByIDs(t.RepoIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(repos))
+	for _, id := range t.RepoIDs {
+		if repo, ok := repos[id]; ok {
+			names = append(names, repo.FullName())
+		}
+	}
+	return names, nil
+}
+
 // NewAccessToken creates new access token.
 func NewAccessToken(t *AccessToken) error {
 	salt, err := generate.GetRandomString(10)
@@ -48,6 +128,9 @@ func NewAccessToken(t *AccessToken) error {
 	t.Token = base.EncodeSha1(gouuid.New().String())
 	t.TokenHash = hashToken(t.Token, t.TokenSalt)
 	t.TokenLastEight = t.Token[len(t.Token)-8:]
+	if t.Scope == "" {
+		t.Scope = AccessTokenScopeAll
+	}
 	_, err = x.Insert(t)
 	return err
 }
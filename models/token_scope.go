@@ -0,0 +1,161 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+)
+
+// AccessTokenScopeCategory is a group of resources an access token can grant access to
+type AccessTokenScopeCategory string
+
+// Enumerate all the access token scope categories
+const (
+	AccessTokenScopeCategoryRepository   AccessTokenScopeCategory = "repository"
+	AccessTokenScopeCategoryIssue        AccessTokenScopeCategory = "issue"
+	AccessTokenScopeCategoryOrganization AccessTokenScopeCategory = "org"
+	AccessTokenScopeCategoryUser         AccessTokenScopeCategory = "user"
+	AccessTokenScopeCategoryPackage      AccessTokenScopeCategory = "package"
+	AccessTokenScopeCategoryAdmin        AccessTokenScopeCategory = "admin"
+)
+
+// AllAccessTokenScopeCategories contains all access token scope categories in display order
+var AllAccessTokenScopeCategories = []AccessTokenScopeCategory{
+	AccessTokenScopeCategoryRepository,
+	AccessTokenScopeCategoryIssue,
+	AccessTokenScopeCategoryOrganization,
+	AccessTokenScopeCategoryUser,
+	AccessTokenScopeCategoryPackage,
+	AccessTokenScopeCategoryAdmin,
+}
+
+// SupportsAdminLevel returns true if the admin level can be granted for the category.
+// It allows the administration of repositories and organizations, e.g. deleting them.
+func (c AccessTokenScopeCategory) SupportsAdminLevel() bool {
+	return c == AccessTokenScopeCategoryRepository || c == AccessTokenScopeCategoryOrganization
+}
+
+// AccessTokenScopeLevel is the access an access token grants on a category
+type AccessTokenScopeLevel int
+
+// Enumerate all the access token scope levels
+const (
+	AccessTokenScopeLevelNone AccessTokenScopeLevel = iota
+	AccessTokenScopeLevelRead
+	AccessTokenScopeLevelWrite
+	AccessTokenScopeLevelAdmin
+)
+
+var accessTokenScopeLevelNames = map[AccessTokenScopeLevel]string{
+	AccessTokenScopeLevelRead:  "read",
+	AccessTokenScopeLevelWrite: "write",
+	AccessTokenScopeLevelAdmin: "admin",
+}
+
+func (l AccessTokenScopeLevel) String() string {
+	return accessTokenScopeLevelNames[l]
+}
+
+// AccessTokenScope is a comma separated list of scopes in the form "level:category",
+// e.g. "read:repository,write:issue".
+type AccessTokenScope string
+
+// AccessTokenScopeAll grants full access to the account of the token owner
+const AccessTokenScopeAll AccessTokenScope = "all"
+
+// FormatAccessTokenScope returns the scope string of a level on a category, e.g. "write:issue"
+func FormatAccessTokenScope(level AccessTokenScopeLevel, category AccessTokenScopeCategory) string {
+	return level.String() + ":" + string(category)
+}
+
+// ParseAccessTokenScope validates the scopes and returns them as normalized AccessTokenScope.
+// Higher levels include the lower levels of the same category.
+func ParseAccessTokenScope(scopes []string) (AccessTokenScope, error) {
+	levels := make(map[AccessTokenScopeCategory]AccessTokenScopeLevel)
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if scope == "" {
+			continue
+		}
+		if AccessTokenScope(scope) == AccessTokenScopeAll {
+			return AccessTokenScopeAll, nil
+		}
+
+		level, category, ok := parseSingleAccessTokenScope(scope)
+		if !ok {
+			return "", ErrAccessTokenScopeInvalid{Scope: scope}
+		}
+		if level > levels[category] {
+			levels[category] = level
+		}
+	}
+	if len(levels) == 0 {
+		return "", ErrAccessTokenScopeInvalid{Scope: strings.Join(scopes, ",")}
+	}
+
+	normalized := make([]string, 0, len(levels))
+	for _, category := range AllAccessTokenScopeCategories {
+		if level, ok := levels[category]; ok {
+			normalized = append(normalized, FormatAccessTokenScope(level, category))
+		}
+	}
+	return AccessTokenScope(strings.Join(normalized, ",")), nil
+}
+
+func parseSingleAccessTokenScope(scope string) (AccessTokenScopeLevel, AccessTokenScopeCategory, bool) {
+	parts := strings.SplitN(scope, ":", 2)
+	if len(parts) != 2 {
+		return AccessTokenScopeLevelNone, "", false
+	}
+
+	var level AccessTokenScopeLevel
+	for l, name := range accessTokenScopeLevelNames {
+		if name == parts[0] {
+			level = l
+		}
+	}
+	category := AccessTokenScopeCategory(parts[1])
+	for _, c := range AllAccessTokenScopeCategories {
+		if c == category {
+			if level == AccessTokenScopeLevelNone || level == AccessTokenScopeLevelAdmin && !c.SupportsAdminLevel() {
+				return AccessTokenScopeLevelNone, "", false
+			}
+			return level, category, true
+		}
+	}
+	return AccessTokenScopeLevelNone, "", false
+}
+
+// IsAll returns true if the scope grants full access. Tokens created before scopes were
+// introduced have full access.
+func (s AccessTokenScope) IsAll() bool {
+	return s == "" || s == AccessTokenScopeAll
+}
+
+// Level returns the level the scope grants on the category
+func (s AccessTokenScope) Level(category AccessTokenScopeCategory) AccessTokenScopeLevel {
+	if s.IsAll() {
+		return AccessTokenScopeLevelAdmin
+	}
+	for _, scope := range strings.Split(string(s), ",") {
+		if level, c, ok := parseSingleAccessTokenScope(scope); ok && c == category {
+			return level
+		}
+	}
+	return AccessTokenScopeLevelNone
+}
+
+// HasScope returns true if the scope grants at least the level on the category
+func (s AccessTokenScope) HasScope(category AccessTokenScopeCategory, level AccessTokenScopeLevel) bool {
+	return s.Level(category) >= level
+}
+
+// StringSlice returns the single scopes
+func (s AccessTokenScope) StringSlice() []string {
+	if s.IsAll() {
+		return []string{string(AccessTokenScopeAll)}
+	}
+	return strings.Split(string(s), ",")
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAccessTokenScope(t *testing.T) {
+	tests := []struct {
+		in       []string
+		expected AccessTokenScope
+	}{
+		{[]string{"all"}, AccessTokenScopeAll},
+		{[]string{"read:issue", "ALL"}, AccessTokenScopeAll},
+		{[]string{"read:repository"}, "read:repository"},
+		{[]string{" write:issue ", "", "read:repository"}, "read:repository,write:issue"},
+		{[]string{"read:org", "admin:org", "write:org"}, "admin:org"},
+		{[]string{"write:admin", "read:package", "read:user"}, "read:user,read:package,write:admin"},
+	}
+	for _, test := range tests {
+		scope, err := ParseAccessTokenScope(test.in)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, scope)
+	}
+
+	for _, in := range [][]string{{}, {""}, {"read"}, {"read:unknown"}, {"none:issue"}, {"admin:issue"}, {"read:issue", "all:repository"}} {
+		_, err := ParseAccessTokenScope(in)
+		assert.True(t, IsErrAccessTokenScopeInvalid(err), "%v", in)
+	}
+}
+
+func TestAccessTokenScope_HasScope(t *testing.T) {
+	for _, scope := range []AccessTokenScope{"", AccessTokenScopeAll} {
+		assert.True(t, scope.IsAll())
+		assert.True(t, scope.HasScope(AccessTokenScopeCategoryAdmin, AccessTokenScopeLevelWrite))
+		assert.True(t, scope.HasScope(AccessTokenScopeCategoryRepository, AccessTokenScopeLevelAdmin))
+		assert.Equal(t, []string{"all"}, scope.StringSlice())
+	}
+
+	scope := AccessTokenScope("admin:repository,read:issue")
+	assert.False(t, scope.IsAll())
+	assert.True(t, scope.HasScope(AccessTokenScopeCategoryRepository, AccessTokenScopeLevelRead))
+	assert.True(t, scope.HasScope(AccessTokenScopeCategoryRepository, AccessTokenScopeLevelAdmin))
+	assert.True(t, scope.HasScope(AccessTokenScopeCategoryIssue, AccessTokenScopeLevelRead))
+	assert.False(t, scope.HasScope(AccessTokenScopeCategoryIssue, AccessTokenScopeLevelWrite))
+	assert.False(t, scope.HasScope(AccessTokenScopeCategoryUser, AccessTokenScopeLevelRead))
+	assert.Equal(t, []string{"admin:repository", "read:issue"}, scope.StringSlice())
+}
+
+func TestAccessToken_SetRepositories(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	token := &AccessToken{UID: 2, Name: "Restricted Token"}
+	assert.NoError(t, token.SetRepositories([]string{"user2/repo1", " user2/repo2", ""}))
+	assert.Equal(t, []int64{1, 2}, token.RepoIDs)
+	assert.True(t, token.IsRestricted())
+	assert.True(t, token.CanAccessRepo(2))
+	assert.False(t, token.CanAccessRepo(3))
+
+	assert.NoError(t, NewAccessToken(token))
+	loaded, err := GetAccessTokenBySHA(token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, AccessTokenScopeAll, loaded.Scope)
+	assert.Equal(t, []int64{1, 2}, loaded.RepoIDs)
+	names, err := loaded.RepositoryNames()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2/repo1", "user2/repo2"}, names)
+
+	// user4 has no access to the private repository of user2
+	token = &AccessToken{UID: 4, Name: "Restricted Token"}
+	assert.True(t, IsErrRepoNotExist(token.SetRepositories([]string{"user2/repo2"})))
+	assert.True(t, IsErrRepoNotExist(token.SetRepositories([]string{"user2/unknown"})))
+	assert.True(t, IsErrRepoNotExist(token.SetRepositories([]string{"invalid"})))
+}
@@ -266,6 +266,11 @@ func (u *User) IsOAuth2() bool {
 	return u.LoginType == LoginOAuth2
 }
 
+// IsSAML returns true if user login type is LoginSAML.
+func (u *User) IsSAML() bool {
+	return u.LoginType == LoginSAML
+}
+
 // HasForkedRepo checks if user has already forked a repository with given ID.
 func (u *User) HasForkedRepo(repoID int64) bool {
 	_, has := HasForkedRepo(u.ID, repoID)
@@ -914,6 +919,7 @@ var (
 		"stars",
 		"template",
 		"user",
+		"v2",
 	}, public.KnownPublicEntries...)
 
 	reservedUserPatterns = []string{"*.keys", "*.gpg"}
@@ -1179,6 +1185,14 @@ func deleteUser(e *xorm.Session, u *User) error {
 		return ErrUserOwnRepos{UID: u.ID}
 	}
 
+	// Check ownership of packages.
+	count, err = countPackagesByOwnerID(e, u.ID)
+	if err != nil {
+		return fmt.Errorf("CountPackagesByOwnerID: %v", err)
+	} else if count > 0 {
+		return ErrUserOwnPackages{UID: u.ID}
+	}
+
 	// Check membership of organization.
 	count, err = u.getOrganizationCount(e)
 	if err != nil {
@@ -1242,6 +1256,10 @@ func deleteUser(e *xorm.Session, u *User) error {
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&WebAuthnCredential{UserID: u.ID},
+		&BlockedUser{BlockerID: u.ID},
+		&BlockedUser{BlockeeID: u.ID},
+		&FlaggedUser{UserID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -1267,6 +1285,10 @@ func deleteUser(e *xorm.Session, u *User) error {
 		return fmt.Errorf("clear assignee: %v", err)
 	}
 
+	if err = deleteProjectsByOwnerID(e, u.ID); err != nil {
+		return fmt.Errorf("deleteProjectsByOwnerID: %v", err)
+	}
+
 	// ***** START: ExternalLoginUser *****
 	if err = removeAllAccountLinks(e, u); err != nil {
 		return fmt.Errorf("ExternalLoginUser: %v", err)
@@ -1345,7 +1367,7 @@ func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration) (err erro
 		}
 		if err = DeleteUser(u); err != nil {
 			// Ignore users that were set inactive by admin.
-			if IsErrUserOwnRepos(err) || IsErrUserHasOrgs(err) {
+			if IsErrUserOwnRepos(err) || IsErrUserOwnPackages(err) || IsErrUserHasOrgs(err) {
 				continue
 			}
 			return err
@@ -1379,6 +1401,18 @@ func GetUserByID(id int64) (*User, error) {
 	return getUserByID(x, id)
 }
 
+// GetAdminUser returns the first administrator
+func GetAdminUser() (*User, error) {
+	var admin User
+	has, err := x.Where("is_admin=?", true).Asc("id").Get(&admin)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrUserNotExist{}
+	}
+	return &admin, nil
+}
+
 // GetUserByName returns user by given name.
 func GetUserByName(name string) (*User, error) {
 	return getUserByName(x, name)
@@ -1835,6 +1869,86 @@ func synchronizeLdapSSHPublicKeys(usr *User, s *LoginSource, sshPublicKeys []str
 	return sshKeysNeedUpdate
 }
 
+// SynchronizeGroupTeams adds a user to the teams its LDAP or SAML groups are mapped to and, if
+// GroupTeamMapRemoval is set, removes it from the mapped teams of the groups it is not a member of.
+func SynchronizeGroupTeams(usr *User, s *LoginSource, groups []string) {
+	var (
+		mapping map[string]map[string][]string
+		removal bool
+		err     error
+	)
+	switch s.Type {
+	case LoginLDAP, LoginDLDAP:
+		mapping, err = s.LDAP().GroupTeamMapping()
+		removal = s.LDAP().GroupTeamMapRemoval
+	case LoginSAML:
+		mapping, err = s.SAML().GroupTeamMapping()
+		removal = s.SAML().GroupTeamMapRemoval
+	default:
+		return
+	}
+	if err != nil {
+		log.Error("SynchronizeGroupTeams[%s]: %v", s.Name, err)
+		return
+	}
+
+	// LDAP DNs are case-insensitive, SAML group names are treated alike
+	memberOf := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		memberOf[strings.ToLower(group)] = true
+	}
+
+	// A user is kept in a team when any of its groups is mapped to it
+	wantTeams := make(map[string]map[string]bool)
+	for group, orgTeams := range mapping {
+		isMember := memberOf[strings.ToLower(group)]
+		for orgName, teamNames := range orgTeams {
+			orgName = strings.ToLower(orgName)
+			if wantTeams[orgName] == nil {
+				wantTeams[orgName] = make(map[string]bool)
+			}
+			for _, teamName := range teamNames {
+				teamName = strings.ToLower(teamName)
+				wantTeams[orgName][teamName] = wantTeams[orgName][teamName] || isMember
+			}
+		}
+	}
+
+	for orgName, teams := range wantTeams {
+		org, err := GetOrgByName(orgName)
+		if err != nil {
+			log.Error("SynchronizeGroupTeams[%s]: Error getting organization %s: %v", s.Name, orgName, err)
+			continue
+		}
+		for teamName, want := range teams {
+			if !want && !removal {
+				continue
+			}
+			team, err := org.GetTeam(teamName)
+			if err != nil {
+				log.Error("SynchronizeGroupTeams[%s]: Error getting team %s of organization %s: %v", s.Name, teamName, org.Name, err)
+				continue
+			}
+			isMember, err := IsTeamMember(org.ID, team.ID, usr.ID)
+			if err != nil {
+				log.Error("SynchronizeGroupTeams[%s]: Error checking membership of user %s in team %s/%s: %v", s.Name, usr.Name, org.Name, team.Name, err)
+				continue
+			}
+			if want && !isMember {
+				log.Trace("SynchronizeGroupTeams[%s]: Adding user %s to team %s/%s", s.Name, usr.Name, org.Name, team.Name)
+				if err := AddTeamMember(team, usr.ID); err != nil {
+					log.Error("SynchronizeGroupTeams[%s]: Error adding user %s to team %s/%s: %v", s.Name, usr.Name, org.Name, team.Name, err)
+				}
+			} else if !want && isMember {
+				log.Trace("SynchronizeGroupTeams[%s]: Removing user %s from team %s/%s", s.Name, usr.Name, org.Name, team.Name)
+				if err := RemoveTeamMember(team, usr.ID); err != nil {
+					log.Error("SynchronizeGroupTeams[%s]: Error removing user %s from team %s/%s: %v", s.Name, usr.Name, org.Name, team.Name, err)
+				}
+			}
+		}
+	}
+}
+
 // SyncExternalUsers is used to synchronize users with external authorization source
 func SyncExternalUsers(ctx context.Context, updateExisting bool) error {
 	log.Trace("Doing: SyncExternalUsers")
@@ -1947,15 +2061,25 @@ func SyncExternalUsers(ctx context.Context, updateExisting bool) error {
 
 					if err != nil {
 						log.Error("SyncExternalUsers[%s]: Error creating user %s: %v", s.Name, su.Username, err)
-					} else if isAttributeSSHPublicKeySet {
+						continue
+					}
+					if isAttributeSSHPublicKeySet {
 						log.Trace("SyncExternalUsers[%s]: Adding LDAP Public SSH Keys for user %s", s.Name, usr.Name)
 						if addLdapSSHPublicKeys(usr, s, su.SSHPublicKey) {
 							sshKeysNeedUpdate = true
 						}
 					}
+					if s.LDAP().UseGroupTeamMap() {
+						SynchronizeGroupTeams(usr, s, su.Groups)
+					}
 				} else if updateExisting {
 					existingUsers = append(existingUsers, usr.ID)
 
+					// Synchronize team memberships if LDAP groups are mapped to teams
+					if s.LDAP().UseGroupTeamMap() {
+						SynchronizeGroupTeams(usr, s, su.Groups)
+					}
+
 					// Synchronize SSH Public Key if that attribute is set
 					if isAttributeSSHPublicKeySet && synchronizeLdapSSHPublicKeys(usr, s, su.SSHPublicKey) {
 						sshKeysNeedUpdate = true
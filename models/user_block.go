@@ -0,0 +1,186 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// BlockedUser represents a user blocked by another user or by an organization.
+type BlockedUser struct {
+	ID          int64 `xorm:"pk autoincr"`
+	BlockerID   int64 `xorm:"UNIQUE(block) NOT NULL"`
+	BlockeeID   int64 `xorm:"UNIQUE(block) INDEX NOT NULL"`
+	Blockee     *User `xorm:"-"`
+	Note        string
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// IsBlocked returns true if the blocker has blocked the blockee.
+func IsBlocked(blockerID, blockeeID int64) (bool, error) {
+	return x.Get(&BlockedUser{BlockerID: blockerID, BlockeeID: blockeeID})
+}
+
+func checkUserBlockedBy(e Engine, userID int64, blockerIDs ...int64) error {
+	block := new(BlockedUser)
+	has, err := e.Where("blockee_id = ?", userID).In("blocker_id", blockerIDs).Get(block)
+	if err != nil {
+		return err
+	} else if has {
+		return ErrBlockedByUser{BlockerID: block.BlockerID, UserID: userID}
+	}
+	return nil
+}
+
+func checkUserBlockedFromRepo(e Engine, userID, repoID int64) error {
+	repo, err := getRepositoryByID(e, repoID)
+	if err != nil {
+		return err
+	}
+	return checkUserBlockedBy(e, userID, repo.OwnerID)
+}
+
+func checkUserBlockedFromIssue(e Engine, userID int64, issue *Issue) error {
+	if err := issue.loadRepo(e); err != nil {
+		return err
+	}
+	return checkUserBlockedBy(e, userID, issue.Repo.OwnerID, issue.PosterID)
+}
+
+// CheckUserBlockedFromIssue returns an ErrBlockedByUser if the owner of the repository or the
+// poster of the issue has blocked the user.
+func CheckUserBlockedFromIssue(userID int64, issue *Issue) error {
+	return checkUserBlockedFromIssue(x, userID, issue)
+}
+
+// CheckUserBlockedBy returns an ErrBlockedByUser if any of the blockers has blocked the user.
+func CheckUserBlockedBy(userID int64, blockerIDs ...int64) error {
+	return checkUserBlockedBy(x, userID, blockerIDs...)
+}
+
+// BlockUser blocks the blockee for the blocker, which is a user or an organization. The blockee
+// can no longer interact with the blocker and its repositories: follows in both directions are
+// removed, as well as the stars, watches and collaborations of the blockee on these repositories.
+func BlockUser(blocker, blockee *User, note string) error {
+	if blocker.ID == blockee.ID {
+		return ErrCannotBlockUser{BlockerID: blocker.ID, BlockeeID: blockee.ID, Reason: "a user can not block themselves"}
+	}
+	if blockee.IsOrganization() {
+		return ErrCannotBlockUser{BlockerID: blocker.ID, BlockeeID: blockee.ID, Reason: "organizations can not be blocked"}
+	}
+
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if blocker.IsOrganization() {
+		isMember, err := isOrganizationMember(sess, blocker.ID, blockee.ID)
+		if err != nil {
+			return err
+		} else if isMember {
+			return ErrCannotBlockUser{BlockerID: blocker.ID, BlockeeID: blockee.ID, Reason: "members of the organization can not be blocked"}
+		}
+	}
+
+	block := &BlockedUser{BlockerID: blocker.ID, BlockeeID: blockee.ID}
+	if has, err := sess.Get(block); err != nil {
+		return err
+	} else if has {
+		return nil
+	}
+	block.Note = note
+	if _, err := sess.Insert(block); err != nil {
+		return err
+	}
+
+	if err := unfollowUser(sess, blockee.ID, blocker.ID); err != nil {
+		return err
+	}
+	if err := unfollowUser(sess, blocker.ID, blockee.ID); err != nil {
+		return err
+	}
+
+	blockerRepoIDs := builder.Select("id").From("repository").Where(builder.Eq{"owner_id": blocker.ID})
+
+	repos := make([]*Repository, 0, 10)
+	if err := sess.Where(builder.Eq{"owner_id": blocker.ID}).
+		And(builder.In("id", builder.Select("repo_id").From("collaboration").Where(builder.Eq{"user_id": blockee.ID}))).
+		Find(&repos); err != nil {
+		return fmt.Errorf("find collaborations: %v", err)
+	}
+	for _, repo := range repos {
+		repo.Owner = blocker
+		if err := repo.deleteCollaboration(sess, blockee.ID); err != nil {
+			return fmt.Errorf("deleteCollaboration: %v", err)
+		}
+	}
+
+	stars := make([]*Star, 0, 10)
+	if err := sess.Where(builder.Eq{"uid": blockee.ID}).And(builder.In("repo_id", blockerRepoIDs)).Find(&stars); err != nil {
+		return fmt.Errorf("find stars: %v", err)
+	}
+	for _, star := range stars {
+		if err := starRepo(sess, blockee.ID, star.RepoID, false); err != nil {
+			return err
+		}
+	}
+
+	watches := make([]Watch, 0, 10)
+	if err := sess.Where(builder.Eq{"user_id": blockee.ID}).And(builder.In("repo_id", blockerRepoIDs)).Find(&watches); err != nil {
+		return fmt.Errorf("find watches: %v", err)
+	}
+	for _, watch := range watches {
+		if err := watchRepoMode(sess, watch, RepoWatchModeNone); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// UnblockUser removes the block of the blockee by the blocker.
+func UnblockUser(blockerID, blockeeID int64) error {
+	_, err := x.Delete(&BlockedUser{BlockerID: blockerID, BlockeeID: blockeeID})
+	return err
+}
+
+// GetBlockedUsers returns the users blocked by the blocker, most recently blocked first.
+func GetBlockedUsers(blockerID int64, listOptions ListOptions) ([]*BlockedUser, error) {
+	sess := x.Where("blocker_id = ?", blockerID).Desc("id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	blocks := make([]*BlockedUser, 0, listOptions.PageSize)
+	if err := sess.Find(&blocks); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, len(blocks))
+	for i, block := range blocks {
+		userIDs[i] = block.BlockeeID
+	}
+	userMaps := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&userMaps); err != nil {
+		return nil, fmt.Errorf("find user: %v", err)
+	}
+	for _, block := range blocks {
+		block.Blockee = userMaps[block.BlockeeID]
+		if block.Blockee == nil {
+			block.Blockee = NewGhostUser()
+		}
+	}
+	return blocks, nil
+}
+
+// CountBlockedUsers returns the number of users blocked by the blocker.
+func CountBlockedUsers(blockerID int64) (int64, error) {
+	return x.Where("blocker_id = ?", blockerID).Count(new(BlockedUser))
+}
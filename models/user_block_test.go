@@ -0,0 +1,117 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockUser(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	isBlocked := func(blockerID, blockeeID int64) bool {
+		has, err := IsBlocked(blockerID, blockeeID)
+		assert.NoError(t, err)
+		return has
+	}
+	assert.True(t, IsFollowing(4, 2))
+	assert.True(t, IsWatching(4, 1))
+
+	assert.NoError(t, BlockUser(user2, user4, "spam"))
+	AssertExistsAndLoadBean(t, &BlockedUser{BlockerID: 2, BlockeeID: 4, Note: "spam"})
+	assert.True(t, isBlocked(2, 4))
+	assert.False(t, isBlocked(4, 2))
+	assert.False(t, IsFollowing(4, 2))
+	assert.False(t, IsWatching(4, 1))
+	AssertExistsAndLoadBean(t, &Repository{ID: 1, NumWatches: repo1.NumWatches - 1})
+	CheckConsistencyFor(t, &User{}, &Repository{})
+
+	// blocking twice keeps the first block
+	assert.NoError(t, BlockUser(user2, user4, "again"))
+	AssertExistsAndLoadBean(t, &BlockedUser{BlockerID: 2, BlockeeID: 4, Note: "spam"})
+
+	assert.True(t, IsErrBlockedByUser(CheckUserBlockedBy(4, 1, 2)))
+	assert.NoError(t, CheckUserBlockedBy(2, 4))
+	assert.True(t, IsErrBlockedByUser(FollowUser(4, 2)))
+	assert.True(t, IsErrBlockedByUser(FollowUser(2, 4)))
+	assert.True(t, IsErrBlockedByUser(StarRepo(4, 1, true)))
+	assert.True(t, IsErrBlockedByUser(WatchRepo(4, 1, true)))
+	assert.True(t, IsErrBlockedByUser(repo1.AddCollaborator(user4)))
+
+	assert.NoError(t, UnblockUser(2, 4))
+	assert.False(t, isBlocked(2, 4))
+	assert.NoError(t, FollowUser(4, 2))
+}
+
+func TestBlockUser_Collaborator(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	AssertExistsAndLoadBean(t, &Collaboration{RepoID: 4, UserID: 4})
+
+	assert.NoError(t, BlockUser(user5, user4, ""))
+	AssertNotExistsBean(t, &Collaboration{RepoID: 4, UserID: 4})
+	AssertNotExistsBean(t, &Access{RepoID: 4, UserID: 4})
+	// collaborations on repositories of other owners are kept
+	AssertExistsAndLoadBean(t, &Collaboration{RepoID: 40, UserID: 4})
+}
+
+func TestBlockUser_Invalid(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	org3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	user4 := AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+
+	assert.True(t, IsErrCannotBlockUser(BlockUser(user2, user2, "")))
+	assert.True(t, IsErrCannotBlockUser(BlockUser(user2, org3, "")))
+	// user4 is a member of org3
+	assert.True(t, IsErrCannotBlockUser(BlockUser(org3, user4, "")))
+	AssertNotExistsBean(t, &BlockedUser{})
+}
+
+func TestGetBlockedUsers(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	org3 := AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	for _, id := range []int64{8, 9} {
+		assert.NoError(t, BlockUser(org3, AssertExistsAndLoadBean(t, &User{ID: id}).(*User), ""))
+	}
+
+	blocks, err := GetBlockedUsers(3, ListOptions{Page: 1, PageSize: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, blocks, 1) {
+		assert.EqualValues(t, 9, blocks[0].Blockee.ID)
+	}
+	count, err := CountBlockedUsers(3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	assert.True(t, IsErrBlockedByUser(AddTeamMember(AssertExistsAndLoadBean(t, &Team{ID: 1}).(*Team), 8)))
+}
+
+func TestBlockUser_Delete(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user8 := AssertExistsAndLoadBean(t, &User{ID: 8}).(*User)
+	org6 := AssertExistsAndLoadBean(t, &User{ID: 6}).(*User)
+	assert.NoError(t, BlockUser(user2, user8, ""))
+	assert.NoError(t, BlockUser(user8, user2, ""))
+	assert.NoError(t, BlockUser(org6, user2, ""))
+
+	assert.NoError(t, DeleteUser(user8))
+	AssertNotExistsBean(t, &BlockedUser{BlockerID: 8})
+	AssertNotExistsBean(t, &BlockedUser{BlockeeID: 8})
+
+	assert.NoError(t, DeleteOrganization(org6))
+	AssertNotExistsBean(t, &BlockedUser{BlockerID: 6})
+}
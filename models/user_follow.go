@@ -13,7 +13,11 @@ type Follow struct {
 
 // IsFollowing returns true if user is following followID.
 func IsFollowing(userID, followID int64) bool {
-	has, _ := x.Get(&Follow{UserID: userID, FollowID: followID})
+	return isFollowing(x, userID, followID)
+}
+
+func isFollowing(e Engine, userID, followID int64) bool {
+	has, _ := e.Get(&Follow{UserID: userID, FollowID: followID})
 	return has
 }
 
@@ -23,6 +27,14 @@ func FollowUser(userID, followID int64) (err error) {
 		return nil
 	}
 
+	// users who block each other can not follow each other
+	if err = CheckUserBlockedBy(userID, followID); err != nil {
+		return err
+	}
+	if err = CheckUserBlockedBy(followID, userID); err != nil {
+		return err
+	}
+
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
@@ -45,26 +57,33 @@ func FollowUser(userID, followID int64) (err error) {
 
 // UnfollowUser unmarks someone as another's follower.
 func UnfollowUser(userID, followID int64) (err error) {
-	if userID == followID || !IsFollowing(userID, followID) {
-		return nil
-	}
-
 	sess := x.NewSession()
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
 		return err
 	}
 
-	if _, err = sess.Delete(&Follow{UserID: userID, FollowID: followID}); err != nil {
+	if err = unfollowUser(sess, userID, followID); err != nil {
 		return err
 	}
+	return sess.Commit()
+}
+
+func unfollowUser(e Engine, userID, followID int64) (err error) {
+	if userID == followID || !isFollowing(e, userID, followID) {
+		return nil
+	}
 
-	if _, err = sess.Exec("UPDATE `user` SET num_followers = num_followers - 1 WHERE id = ?", followID); err != nil {
+	if _, err = e.Delete(&Follow{UserID: userID, FollowID: followID}); err != nil {
 		return err
 	}
 
-	if _, err = sess.Exec("UPDATE `user` SET num_following = num_following - 1 WHERE id = ?", userID); err != nil {
+	if _, err = e.Exec("UPDATE `user` SET num_followers = num_followers - 1 WHERE id = ?", followID); err != nil {
 		return err
 	}
-	return sess.Commit()
+
+	if _, err = e.Exec("UPDATE `user` SET num_following = num_following - 1 WHERE id = ?", userID); err != nil {
+		return err
+	}
+	return nil
 }
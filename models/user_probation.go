@@ -0,0 +1,162 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// Kinds of content limited for accounts in probation
+const (
+	ProbationLimitRepo  = "repo"
+	ProbationLimitIssue = "issue"
+)
+
+// FlaggedUser represents an account whose registration was flagged by the sign-up checks and
+// which awaits the review of an administrator.
+type FlaggedUser struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"UNIQUE NOT NULL"`
+	User        *User              `xorm:"-"`
+	Reason      string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// FlagUser queues the account for review, flagging an account again replaces the reason.
+func FlagUser(userID int64, reason string) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Delete(&FlaggedUser{UserID: userID}); err != nil {
+		return err
+	}
+	if _, err := sess.Insert(&FlaggedUser{UserID: userID, Reason: reason}); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func isUserFlagged(e Engine, userID int64) (bool, error) {
+	return e.Get(&FlaggedUser{UserID: userID})
+}
+
+// IsUserFlagged returns true if the account awaits review.
+func IsUserFlagged(userID int64) (bool, error) {
+	return isUserFlagged(x, userID)
+}
+
+// GetFlaggedUsers returns the accounts awaiting review, oldest first.
+func GetFlaggedUsers(listOptions ListOptions) ([]*FlaggedUser, error) {
+	sess := x.Asc("id")
+	if listOptions.Page != 0 {
+		sess = listOptions.setSessionPagination(sess)
+	}
+	flagged := make([]*FlaggedUser, 0, listOptions.PageSize)
+	if err := sess.Find(&flagged); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, len(flagged))
+	for i, f := range flagged {
+		userIDs[i] = f.UserID
+	}
+	userMaps := make(map[int64]*User, len(userIDs))
+	if err := x.In("id", userIDs).Find(&userMaps); err != nil {
+		return nil, fmt.Errorf("find user: %v", err)
+	}
+	for _, f := range flagged {
+		f.User = userMaps[f.UserID]
+		if f.User == nil {
+			f.User = NewGhostUser()
+		}
+	}
+	return flagged, nil
+}
+
+// CountFlaggedUsers returns the number of accounts awaiting review.
+func CountFlaggedUsers() (int64, error) {
+	return x.Count(new(FlaggedUser))
+}
+
+// ApproveFlaggedUser removes the account from the review queue, which lifts the probation it was
+// kept in while flagged.
+func ApproveFlaggedUser(userID int64) error {
+	_, err := x.Delete(&FlaggedUser{UserID: userID})
+	return err
+}
+
+// RejectFlaggedUser removes the account from the review queue and prohibits it to sign in.
+func RejectFlaggedUser(u *User) error {
+	sess := x.NewSession()
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Delete(&FlaggedUser{UserID: u.ID}); err != nil {
+		return err
+	}
+	u.ProhibitLogin = true
+	if _, err := sess.ID(u.ID).Cols("prohibit_login").Update(u); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+func isUserInProbation(e Engine, u *User) (bool, error) {
+	if u == nil || u.IsAdmin || u.IsOrganization() {
+		return false, nil
+	}
+	if setting.Registration.ProbationPeriod > 0 &&
+		u.CreatedUnix.AsTime().Add(setting.Registration.ProbationPeriod).After(time.Now()) {
+		return true, nil
+	}
+	return isUserFlagged(e, u.ID)
+}
+
+// IsUserInProbation returns true if the account is new or awaits review, accounts in probation
+// can only create a limited number of repositories and issues in a period of time.
+func IsUserInProbation(u *User) (bool, error) {
+	return isUserInProbation(x, u)
+}
+
+// checkProbationLimit returns an ErrProbationLimit if the user is in probation and has already
+// created the allowed number of repositories or issues in the current period.
+func checkProbationLimit(e Engine, u *User, kind string) error {
+	if inProbation, err := isUserInProbation(e, u); err != nil || !inProbation {
+		return err
+	}
+
+	var (
+		limit int
+		count int64
+		err   error
+	)
+	since := timeutil.TimeStamp(time.Now().Add(-setting.Registration.ProbationLimitPeriod).Unix())
+	switch kind {
+	case ProbationLimitRepo:
+		limit = setting.Registration.ProbationRepoLimit
+		count, err = e.Where("owner_id = ? AND created_unix >= ?", u.ID, since).Count(new(Repository))
+	case ProbationLimitIssue:
+		limit = setting.Registration.ProbationIssueLimit
+		count, err = e.Where("poster_id = ? AND created_unix >= ?", u.ID, since).Count(new(Issue))
+	default:
+		return fmt.Errorf("unknown probation limit: %s", kind)
+	}
+	if err != nil {
+		return err
+	}
+	if limit >= 0 && count >= int64(limit) {
+		return ErrProbationLimit{UserID: u.ID, Kind: kind, Limit: limit}
+	}
+	return nil
+}
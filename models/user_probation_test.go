@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlagUser(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	user5 := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	inProbation, err := IsUserInProbation(user5)
+	assert.NoError(t, err)
+	assert.False(t, inProbation)
+
+	assert.NoError(t, FlagUser(5, "disposable email"))
+	assert.NoError(t, FlagUser(5, "new accounts are reviewed"))
+	AssertExistsAndLoadBean(t, &FlaggedUser{UserID: 5, Reason: "new accounts are reviewed"})
+
+	flagged, err := GetFlaggedUsers(ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, flagged, 1) {
+		assert.EqualValues(t, 5, flagged[0].User.ID)
+	}
+	count, err := CountFlaggedUsers()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	inProbation, err = IsUserInProbation(user5)
+	assert.NoError(t, err)
+	assert.True(t, inProbation)
+
+	assert.NoError(t, ApproveFlaggedUser(5))
+	isFlagged, err := IsUserFlagged(5)
+	assert.NoError(t, err)
+	assert.False(t, isFlagged)
+
+	assert.NoError(t, FlagUser(5, "disposable email"))
+	assert.NoError(t, RejectFlaggedUser(user5))
+	AssertNotExistsBean(t, &FlaggedUser{UserID: 5})
+	AssertExistsAndLoadBean(t, &User{ID: 5, ProhibitLogin: true})
+
+	// deleting an account removes it from the review queue
+	user8 := AssertExistsAndLoadBean(t, &User{ID: 8}).(*User)
+	assert.NoError(t, FlagUser(8, "disposable email"))
+	assert.NoError(t, DeleteUser(user8))
+	AssertNotExistsBean(t, &FlaggedUser{UserID: 8})
+}
+
+func TestProbationLimit(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+	defer func(period time.Duration, repoLimit, issueLimit int) {
+		setting.Registration.ProbationPeriod = period
+		setting.Registration.ProbationRepoLimit = repoLimit
+		setting.Registration.ProbationIssueLimit = issueLimit
+	}(setting.Registration.ProbationPeriod, setting.Registration.ProbationRepoLimit, setting.Registration.ProbationIssueLimit)
+	setting.Registration.ProbationRepoLimit = 0
+	setting.Registration.ProbationIssueLimit = 1
+
+	user1 := AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo1 := AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	// accounts are in probation while they are new
+	assert.NoError(t, checkProbationLimit(x, user2, ProbationLimitRepo))
+	setting.Registration.ProbationPeriod = time.Hour
+	newUser := &User{ID: 2, CreatedUnix: timeutil.TimeStampNow()}
+	assert.True(t, IsErrProbationLimit(checkProbationLimit(x, newUser, ProbationLimitRepo)))
+
+	// or until they are reviewed, administrators are never in probation
+	assert.NoError(t, FlagUser(1, "reviewed"))
+	assert.NoError(t, FlagUser(2, "reviewed"))
+	assert.NoError(t, checkProbationLimit(x, user1, ProbationLimitRepo))
+	assert.True(t, IsErrProbationLimit(checkProbationLimit(x, user2, ProbationLimitRepo)))
+
+	newIssue := func() error {
+		return NewIssue(repo1, &Issue{RepoID: repo1.ID, PosterID: user2.ID, Poster: user2, Title: "probation"}, nil, nil)
+	}
+	assert.NoError(t, newIssue())
+	assert.True(t, IsErrProbationLimit(newIssue()))
+
+	setting.Registration.ProbationIssueLimit = -1
+	assert.NoError(t, newIssue())
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/modules/auth/ldap"
+	"code.gitea.io/gitea/modules/auth/saml"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 
@@ -406,3 +408,42 @@ func TestGetMaileableUsersByIDs(t *testing.T) {
 		assert.Equal(t, results[1].ID, 4)
 	}
 }
+
+func TestSynchronizeGroupTeams(t *testing.T) {
+	assert.NoError(t, PrepareTestDatabase())
+
+	ls := &ldap.Source{
+		GroupsEnabled: true,
+		GroupTeamMap:  `{"cn=devs,ou=group,dc=example,dc=com": {"user3": ["team1"]}, "cn=admins,ou=group,dc=example,dc=com": {"user3": ["Owners"]}}`,
+	}
+	source := &LoginSource{ID: 99, Type: LoginLDAP, Name: "ldap", Cfg: &LDAPConfig{Source: ls}}
+	user := AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+
+	// group DNs are compared case-insensitively
+	SynchronizeGroupTeams(user, source, []string{"CN=Devs,ou=group,dc=example,dc=com"})
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: 5})
+	AssertNotExistsBean(t, &TeamUser{TeamID: 1, UID: 5})
+
+	// users are only removed from mapped teams when removal is enabled
+	SynchronizeGroupTeams(user, source, nil)
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: 5})
+
+	ls.GroupTeamMapRemoval = true
+	SynchronizeGroupTeams(user, source, nil)
+	AssertNotExistsBean(t, &TeamUser{TeamID: 2, UID: 5})
+	// unmapped team memberships are left untouched
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 3, UID: 5})
+
+	// an invalid mapping changes nothing
+	ls.GroupTeamMap = "{"
+	SynchronizeGroupTeams(user, source, []string{"cn=devs,ou=group,dc=example,dc=com"})
+	AssertNotExistsBean(t, &TeamUser{TeamID: 2, UID: 5})
+
+	// SAML groups are mapped alike
+	samlSource := &LoginSource{ID: 100, Type: LoginSAML, Name: "saml", Cfg: &SAMLConfig{Source: &saml.Source{
+		AttributeGroups: "groups",
+		GroupTeamMap:    `{"developers": {"user3": ["team1"]}}`,
+	}}}
+	SynchronizeGroupTeams(user, samlSource, []string{"Developers"})
+	AssertExistsAndLoadBean(t, &TeamUser{TeamID: 2, UID: 5})
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"encoding/base64"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/duo-labs/webauthn/webauthn"
+)
+
+// WebAuthnCredential represents a security key registered by a user as second factor or for passwordless login
+type WebAuthnCredential struct {
+	ID              int64 `xorm:"pk autoincr"`
+	Name            string
+	UserID          int64  `xorm:"INDEX"`
+	CredentialID    string `xorm:"INDEX VARCHAR(410)"`
+	PublicKey       []byte
+	AttestationType string
+	AAGUID          []byte
+	SignCount       uint32 `xorm:"BIGINT"`
+	CloneWarning    bool
+	CreatedUnix     timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// TableName returns a better table name for WebAuthnCredential
+func (cred WebAuthnCredential) TableName() string {
+	return "webauthn_credential"
+}
+
+// RawCredentialID returns the credential id as sent by the authenticator
+func (cred *WebAuthnCredential) RawCredentialID() []byte {
+	id, _ := base64.RawURLEncoding.DecodeString(cred.CredentialID)
+	return id
+}
+
+// ToCredential converts the credential to the type used by the WebAuthn library
+func (cred *WebAuthnCredential) ToCredential() webauthn.Credential {
+	return webauthn.Credential{
+		ID:              cred.RawCredentialID(),
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		Authenticator: webauthn.Authenticator{
+			AAGUID:       cred.AAGUID,
+			SignCount:    cred.SignCount,
+			CloneWarning: cred.CloneWarning,
+		},
+	}
+}
+
+func (cred *WebAuthnCredential) updateSignCount(e Engine) error {
+	_, err := e.ID(cred.ID).Cols("sign_count", "clone_warning").Update(cred)
+	return err
+}
+
+// UpdateSignCount will update the database value of the signature counter and the clone warning
+func (cred *WebAuthnCredential) UpdateSignCount() error {
+	return cred.updateSignCount(x)
+}
+
+// WebAuthnCredentialList is a list of *WebAuthnCredential
+type WebAuthnCredentialList []*WebAuthnCredential
+
+// ToCredentials will convert all WebAuthnCredentials to webauthn.Credentials
+func (list WebAuthnCredentialList) ToCredentials() []webauthn.Credential {
+	creds := make([]webauthn.Credential, 0, len(list))
+	for _, cred := range list {
+		creds = append(creds, cred.ToCredential())
+	}
+	return creds
+}
+
+func getWebAuthnCredentialsByUID(e Engine, uid int64) (WebAuthnCredentialList, error) {
+	creds := make(WebAuthnCredentialList, 0)
+	return creds, e.Where("user_id = ?", uid).Find(&creds)
+}
+
+// GetWebAuthnCredentialsByUID returns all WebAuthn credentials of the given user
+func GetWebAuthnCredentialsByUID(uid int64) (WebAuthnCredentialList, error) {
+	return getWebAuthnCredentialsByUID(x, uid)
+}
+
+// HasWebAuthnCredentials returns whether the given user has registered any WebAuthn credential
+func HasWebAuthnCredentials(uid int64) (bool, error) {
+	return x.Where("user_id = ?", uid).Exist(new(WebAuthnCredential))
+}
+
+// GetWebAuthnCredentialByID returns WebAuthn credential by id
+func GetWebAuthnCredentialByID(id int64) (*WebAuthnCredential, error) {
+	return getWebAuthnCredentialByID(x, id)
+}
+
+func getWebAuthnCredentialByID(e Engine, id int64) (*WebAuthnCredential, error) {
+	cred := new(WebAuthnCredential)
+	if found, err := e.ID(id).Get(cred); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, ErrWebAuthnCredentialNotExist{ID: id}
+	}
+	return cred, nil
+}
+
+// GetWebAuthnCredentialByName returns the WebAuthn credential of the user with the given name
+func GetWebAuthnCredentialByName(uid int64, name string) (*WebAuthnCredential, error) {
+	cred := new(WebAuthnCredential)
+	if found, err := x.Where("user_id = ? AND name = ?", uid, name).Get(cred); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, ErrWebAuthnCredentialNotExist{}
+	}
+	return cred, nil
+}
+
+// GetWebAuthnCredentialByCredID returns the WebAuthn credential with the raw credential id sent by an authenticator
+func GetWebAuthnCredentialByCredID(credID []byte) (*WebAuthnCredential, error) {
+	cred := new(WebAuthnCredential)
+	encoded := base64.RawURLEncoding.EncodeToString(credID)
+	if found, err := x.Where("credential_id = ?", encoded).Get(cred); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, ErrWebAuthnCredentialNotExist{CredentialID: encoded}
+	}
+	return cred, nil
+}
+
+// CreateCredential will create a new WebAuthnCredential from the given credential
+func CreateCredential(userID int64, name string, cred *webauthn.Credential) (*WebAuthnCredential, error) {
+	c := &WebAuthnCredential{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    base64.RawURLEncoding.EncodeToString(cred.ID),
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		AAGUID:          cred.Authenticator.AAGUID,
+		SignCount:       cred.Authenticator.SignCount,
+		CloneWarning:    false,
+	}
+	if _, err := x.InsertOne(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// DeleteCredential will delete the WebAuthnCredential with the given id if it belongs to the user
+func DeleteCredential(id, userID int64) (bool, error) {
+	had, err := x.ID(id).Where("user_id = ?", userID).Delete(&WebAuthnCredential{})
+	return had > 0, err
+}
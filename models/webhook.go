@@ -8,6 +8,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"time"
 
 	"code.gitea.io/gitea/modules/log"
@@ -133,6 +134,22 @@ func (w *Webhook) History(page int) ([]*HookTask, error) {
 	return HookTasks(w.ID, page)
 }
 
+// AuditEvent returns an audit event of the action on the webhook. Only the host of the URL is
+// recorded as the URL of some hook types contains credentials.
+func (w *Webhook) AuditEvent(action AuditAction) *AuditEvent {
+	event := &AuditEvent{
+		Action:     action,
+		OwnerID:    w.OrgID,
+		RepoID:     w.RepoID,
+		TargetID:   w.ID,
+		TargetName: w.HookTaskType.Name(),
+	}
+	if u, err := url.Parse(w.URL); err == nil {
+		event.Description = u.Host
+	}
+	return event
+}
+
 // UpdateEvent handles conversion from HookEvent to Events.
 func (w *Webhook) UpdateEvent() error {
 	data, err := json.Marshal(w.HookEvent)
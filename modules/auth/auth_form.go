@@ -12,7 +12,7 @@ import (
 // AuthenticationForm form for authentication
 type AuthenticationForm struct {
 	ID                            int64
-	Type                          int    `binding:"Range(2,7)"`
+	Type                          int    `binding:"Range(2,8)"`
 	Name                          string `binding:"Required;MaxSize(30)"`
 	Host                          string
 	Port                          int
@@ -35,6 +35,8 @@ type AuthenticationForm struct {
 	GroupFilter                   string
 	GroupMemberUID                string
 	UserUID                       string
+	GroupTeamMap                  string
+	GroupTeamMapRemoval           bool
 	RestrictedFilter              string
 	AllowDeactivateAll            bool
 	IsActive                      bool
@@ -61,6 +63,19 @@ type AuthenticationForm struct {
 	SSPIStripDomainNames          bool
 	SSPISeparatorReplacement      string `binding:"AlphaDashDot;MaxSize(5)"`
 	SSPIDefaultLanguage           string
+	SAMLIdpMetadataURL            string
+	SAMLIdpMetadata               string
+	SAMLSpCertificate             string
+	SAMLSpPrivateKey              string
+	SAMLSignRequests              bool
+	SAMLNameIDFormat              string
+	SAMLAttributeUsername         string
+	SAMLAttributeEmail            string
+	SAMLAttributeFullName         string
+	SAMLAttributeGroups           string
+	SAMLGroupTeamMap              string
+	SAMLGroupTeamMapRemoval       bool
+	SAMLEnableAutoRegistration    bool
 }
 
 // Validate validates fields
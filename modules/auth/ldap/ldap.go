@@ -9,6 +9,7 @@ package ldap
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -55,6 +56,8 @@ type Source struct {
 	GroupFilter           string // Group Name Filter
 	GroupMemberUID        string // Group Attribute containing array of UserUID
 	UserUID               string // User Attribute listed in Group
+	GroupTeamMap          string // Map LDAP groups to teams, JSON of {"group DN": {"Org": ["Team"]}}
+	GroupTeamMapRemoval   bool   // Remove user from mapped teams when not in the corresponding LDAP group
 }
 
 // SearchResult : user data
@@ -66,6 +69,7 @@ type SearchResult struct {
 	SSHPublicKey []string // SSH Public Key
 	IsAdmin      bool     // if user is administrator
 	IsRestricted bool     // if user is restricted
+	Groups       []string // DNs of the LDAP groups the user is a member of, only filled if groups are mapped to teams
 }
 
 func (ls *Source) sanitizedUserQuery(username string) (string, bool) {
@@ -112,6 +116,56 @@ func (ls *Source) sanitizedGroupDN(groupDn string) (string, bool) {
 	return groupDn, true
 }
 
+// GroupTeamMapping parses the mapping of LDAP group DNs to the teams of organizations
+func (ls *Source) GroupTeamMapping() (map[string]map[string][]string, error) {
+	mapping := make(map[string]map[string][]string)
+	if len(strings.TrimSpace(ls.GroupTeamMap)) == 0 {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(ls.GroupTeamMap), &mapping); err != nil {
+		return nil, fmt.Errorf("invalid group team map: %v", err)
+	}
+	return mapping, nil
+}
+
+// UseGroupTeamMap returns if LDAP groups are mapped to teams
+func (ls *Source) UseGroupTeamMap() bool {
+	return ls.GroupsEnabled && len(strings.TrimSpace(ls.GroupTeamMap)) > 0
+}
+
+// listGroupMemberships returns the DNs of the groups below GroupDN which list uid as a member
+func (ls *Source) listGroupMemberships(l *ldap.Conn, uid string) ([]string, error) {
+	groupDN, ok := ls.sanitizedGroupDN(ls.GroupDN)
+	if !ok {
+		return nil, fmt.Errorf("invalid group DN: %s", ls.GroupDN)
+	}
+	groupFilter := fmt.Sprintf("(%s=%s)", ls.GroupMemberUID, ldap.EscapeFilter(uid))
+
+	log.Trace("Fetching group memberships with filter '%s' and base '%s'", groupFilter, groupDN)
+	search := ldap.NewSearchRequest(
+		groupDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, groupFilter,
+		[]string{"dn"}, nil)
+
+	sr, err := l.Search(search)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(sr.Entries))
+	for _, entry := range sr.Entries {
+		groups = append(groups, entry.DN)
+	}
+	return groups, nil
+}
+
+// memberUID returns the value which identifies the entry in the GroupMemberUID attribute of groups
+func (ls *Source) memberUID(entry *ldap.Entry) string {
+	if ls.UserUID == "dn" {
+		return entry.DN
+	}
+	return entry.GetAttributeValue(ls.UserUID)
+}
+
 func (ls *Source) findUserDN(l *ldap.Conn, name string) (string, bool) {
 	log.Trace("Search for LDAP user: %s", name)
 
@@ -384,6 +438,15 @@ func (ls *Source) SearchEntry(name, passwd string, directBind bool) *SearchResul
 		}
 	}
 
+	var groups []string
+	if ls.UseGroupTeamMap() {
+		groups, err = ls.listGroupMemberships(l, ls.memberUID(sr.Entries[0]))
+		if err != nil {
+			log.Error("LDAP group membership search failed: %v", err)
+			return nil
+		}
+	}
+
 	if isAttributeSSHPublicKeySet {
 		sshPublicKey = sr.Entries[0].GetAttributeValues(ls.AttributeSSHPublicKey)
 	}
@@ -409,6 +472,7 @@ func (ls *Source) SearchEntry(name, passwd string, directBind bool) *SearchResul
 		SSHPublicKey: sshPublicKey,
 		IsAdmin:      isAdmin,
 		IsRestricted: isRestricted,
+		Groups:       groups,
 	}
 }
 
@@ -446,6 +510,9 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 	if isAttributeSSHPublicKeySet {
 		attribs = append(attribs, ls.AttributeSSHPublicKey)
 	}
+	if ls.UseGroupTeamMap() && ls.UserUID != "dn" {
+		attribs = append(attribs, ls.UserUID)
+	}
 
 	log.Trace("Fetching attributes '%v', '%v', '%v', '%v', '%v' with filter %s and base %s", ls.AttributeUsername, ls.AttributeName, ls.AttributeSurname, ls.AttributeMail, ls.AttributeSSHPublicKey, userFilter, ls.UserBase)
 	search := ldap.NewSearchRequest(
@@ -479,6 +546,13 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 		if isAttributeSSHPublicKeySet {
 			result[i].SSHPublicKey = v.GetAttributeValues(ls.AttributeSSHPublicKey)
 		}
+		if ls.UseGroupTeamMap() {
+			result[i].Groups, err = ls.listGroupMemberships(l, ls.memberUID(v))
+			if err != nil {
+				log.Error("LDAP group membership search failed: %v", err)
+				return nil, err
+			}
+		}
 	}
 
 	return result, nil
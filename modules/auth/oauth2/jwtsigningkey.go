@@ -0,0 +1,138 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// rsaKeyBits is the size of generated signing keys
+const rsaKeyBits = 2048
+
+// DefaultSigningKey is the key which signs the ID tokens issued to OpenID Connect clients
+var DefaultSigningKey *SigningKey
+
+// SigningKey is an RSA key signing JWTs with RS256 which clients verify with the published public key
+type SigningKey struct {
+	privateKey *rsa.PrivateKey
+	keyID      string
+}
+
+// NewSigningKey creates a signing key from an RSA private key
+func NewSigningKey(privateKey *rsa.PrivateKey) *SigningKey {
+	return &SigningKey{
+		privateKey: privateKey,
+		keyID:      thumbprint(&privateKey.PublicKey),
+	}
+}
+
+// SigningMethod returns the algorithm of the signatures
+func (key *SigningKey) SigningMethod() jwt.SigningMethod {
+	return jwt.SigningMethodRS256
+}
+
+// KeyID returns the identifier of the key which is sent in the "kid" header of signed tokens
+func (key *SigningKey) KeyID() string {
+	return key.keyID
+}
+
+// PublicKey returns the key verifying the signatures
+func (key *SigningKey) PublicKey() *rsa.PublicKey {
+	return &key.privateKey.PublicKey
+}
+
+// Sign returns the signed JWT of the claims
+func (key *SigningKey) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(key.SigningMethod(), claims)
+	token.Header["kid"] = key.keyID
+	return token.SignedString(key.privateKey)
+}
+
+// ToJWK returns the public key as JSON Web Key (RFC 7517)
+func (key *SigningKey) ToJWK() map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"alg": key.SigningMethod().Alg(),
+		"use": "sig",
+		"kid": key.keyID,
+		"n":   base64.RawURLEncoding.EncodeToString(key.privateKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.privateKey.E)).Bytes()),
+	}
+}
+
+// thumbprint returns the JWK thumbprint (RFC 7638) of an RSA public key
+func thumbprint(publicKey *rsa.PublicKey) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()))))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+// InitSigningKey loads the signing key from the configured file, a new key is generated if the file does not exist
+func InitSigningKey() error {
+	privateKey, err := loadOrCreateRSAKey(setting.OAuth2.JWTSigningPrivateKeyFile)
+	if err != nil {
+		return err
+	}
+	DefaultSigningKey = NewSigningKey(privateKey)
+	return nil
+}
+
+func loadOrCreateRSAKey(keyPath string) (*rsa.PrivateKey, error) {
+	buf, err := ioutil.ReadFile(keyPath)
+	if os.IsNotExist(err) {
+		log.Info("Generating OAuth2 signing key %s", keyPath)
+		privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(keyPath), os.ModePerm); err != nil {
+			return nil, err
+		}
+		buf = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+		if err := ioutil.WriteFile(keyPath, buf, 0600); err != nil {
+			return nil, err
+		}
+		return privateKey, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(buf)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", keyPath)
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%s does not contain an RSA private key", keyPath)
+		}
+		return privateKey, nil
+	default:
+		return nil, fmt.Errorf("%s contains an unsupported %s block", keyPath, block.Type)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oauth2
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadOrCreateRSAKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwt")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	keyPath := filepath.Join(dir, "jwt", "private.pem")
+
+	created, err := loadOrCreateRSAKey(keyPath)
+	assert.NoError(t, err)
+	loaded, err := loadOrCreateRSAKey(keyPath)
+	assert.NoError(t, err)
+	assert.Equal(t, created.N, loaded.N)
+
+	assert.NoError(t, ioutil.WriteFile(keyPath, []byte("not a key"), 0600))
+	_, err = loadOrCreateRSAKey(keyPath)
+	assert.Error(t, err)
+}
+
+func TestSigningKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "jwt")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	privateKey, err := loadOrCreateRSAKey(filepath.Join(dir, "private.pem"))
+	assert.NoError(t, err)
+	key := NewSigningKey(privateKey)
+
+	signed, err := key.Sign(&jwt.StandardClaims{Subject: "2"})
+	assert.NoError(t, err)
+	claims := new(jwt.StandardClaims)
+	token, err := jwt.ParseWithClaims(signed, claims, func(token *jwt.Token) (interface{}, error) {
+		assert.Equal(t, "RS256", token.Header["alg"])
+		assert.Equal(t, key.KeyID(), token.Header["kid"])
+		return key.PublicKey(), nil
+	})
+	assert.NoError(t, err)
+	assert.True(t, token.Valid)
+	assert.Equal(t, "2", claims.Subject)
+
+	jwk := key.ToJWK()
+	assert.Equal(t, "RSA", jwk["kty"])
+	assert.Equal(t, key.KeyID(), jwk["kid"])
+	n, err := base64.RawURLEncoding.DecodeString(jwk["n"])
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey.N, new(big.Int).SetBytes(n))
+	assert.Equal(t, "AQAB", jwk["e"])
+}
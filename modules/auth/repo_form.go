@@ -122,6 +122,13 @@ type RepoSettingForm struct {
 	Template       bool
 	EnablePrune    bool
 
+	// Push mirror settings
+	PushMirrorID       int64
+	PushMirrorAddress  string
+	PushMirrorUsername string
+	PushMirrorPassword string
+	PushMirrorInterval string
+
 	// Advanced settings
 	EnableWiki                       bool
 	EnableExternalWiki               bool
@@ -181,6 +188,7 @@ type ProtectBranchForm struct {
 	ApprovalsWhitelistTeams  string
 	BlockOnRejectedReviews   bool
 	BlockOnOutdatedBranch    bool
+	BlockOnCodeOwnerReviews  bool
 	DismissStaleApprovals    bool
 	RequireSignedCommits     bool
 	ProtectedFilePatterns    string
@@ -191,6 +199,18 @@ func (f *ProtectBranchForm) Validate(ctx *macaron.Context, errs binding.Errors)
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ProtectTagForm form for changing protected tag settings
+type ProtectTagForm struct {
+	NamePattern    string `binding:"Required"`
+	AllowlistUsers string
+	AllowlistTeams string
+}
+
+// Validate validates the fields
+func (f *ProtectTagForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //  __      __      ___.   .__    .__            __
 // /  \    /  \ ____\_ |__ |  |__ |  |__   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \|  |  \ /  _ \|  |/ /
@@ -430,6 +450,16 @@ func (i IssueLockForm) HasValidReason() bool {
 	return false
 }
 
+// TransferIssueForm form for moving an issue to another repository
+type TransferIssueForm struct {
+	RepoName string `binding:"Required"`
+}
+
+// Validate validates the fields
+func (f *TransferIssueForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // __________                   __               __
 // \______   \_______  ____    |__| ____   _____/  |_  ______
 //  |     ___/\_  __ \/  _ \   |  |/ __ \_/ ___\   __\/  ___/
@@ -444,13 +474,10 @@ type CreateProjectForm struct {
 	BoardType models.ProjectBoardType
 }
 
-// UserCreateProjectForm is a from for creating an individual or organization
-// form.
-type UserCreateProjectForm struct {
-	Title     string `binding:"Required;MaxSize(100)"`
-	Content   string
-	BoardType models.ProjectBoardType
-	UID       int64 `binding:"Required"`
+// AddProjectCardForm is a form for putting an issue or pull request of any repository
+// on a project board of a user or an organization
+type AddProjectCardForm struct {
+	Issue string `binding:"Required;MaxSize(255)"`
 }
 
 // EditProjectBoardTitleForm is a form for editing the title of a project's
@@ -520,10 +547,11 @@ func (f *InitializeLabelsForm) Validate(ctx *macaron.Context, errs binding.Error
 type MergePullRequestForm struct {
 	// required: true
 	// enum: merge,rebase,rebase-merge,squash
-	Do                string `binding:"Required;In(merge,rebase,rebase-merge,squash)"`
-	MergeTitleField   string
-	MergeMessageField string
-	ForceMerge        *bool `json:"force_merge,omitempty"`
+	Do                     string `binding:"Required;In(merge,rebase,rebase-merge,squash)"`
+	MergeTitleField        string
+	MergeMessageField      string
+	ForceMerge             *bool `json:"force_merge,omitempty"`
+	MergeWhenChecksSucceed bool  `json:"merge_when_checks_succeed,omitempty"`
 }
 
 // Validate validates the fields
@@ -536,6 +564,7 @@ type CodeCommentForm struct {
 	Content        string `binding:"Required"`
 	Side           string `binding:"Required;In(previous,proposed)"`
 	Line           int64
+	StartLine      int64  `form:"start_line"`
 	TreePath       string `form:"path" binding:"Required"`
 	IsReview       bool   `form:"is_review"`
 	Reply          int64  `form:"reply"`
@@ -559,6 +588,17 @@ func (f *SubmitReviewForm) Validate(ctx *macaron.Context, errs binding.Errors) b
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ApplySuggestionsForm for committing the changes suggested in code comments
+type ApplySuggestionsForm struct {
+	CommentIDs []int64 `form:"comment_ids" binding:"Required"`
+	Message    string  `binding:"MaxSize(255)"`
+}
+
+// Validate validates the fields
+func (f *ApplySuggestionsForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ReviewType will return the corresponding reviewtype for type
 func (f SubmitReviewForm) ReviewType() models.ReviewType {
 	switch f.Type {
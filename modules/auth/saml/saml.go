@@ -0,0 +1,338 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package saml provides a SAML 2.0 service provider for identity provider login sources
+package saml
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// NameIDFormat is the format of the name identifier requested from the identity provider
+type NameIDFormat string
+
+// enumerate all supported name identifier formats
+const (
+	NameIDFormatPersistent   NameIDFormat = "persistent"
+	NameIDFormatEmailAddress NameIDFormat = "emailAddress"
+	NameIDFormatUnspecified  NameIDFormat = "unspecified"
+)
+
+// NameIDFormats lists all supported name identifier formats
+var NameIDFormats = []NameIDFormat{
+	NameIDFormatPersistent,
+	NameIDFormatEmailAddress,
+	NameIDFormatUnspecified,
+}
+
+func (f NameIDFormat) samlFormat() saml.NameIDFormat {
+	switch f {
+	case NameIDFormatEmailAddress:
+		return saml.EmailAddressNameIDFormat
+	case NameIDFormatUnspecified:
+		return saml.UnspecifiedNameIDFormat
+	default:
+		return saml.PersistentNameIDFormat
+	}
+}
+
+// metadataCacheDuration is how long metadata fetched from an identity provider is reused
+const metadataCacheDuration = time.Hour
+
+// Source holds the configuration of a SAML identity provider
+type Source struct {
+	IdentityProviderMetadataURL string       // URL of the identity provider metadata
+	IdentityProviderMetadata    string       // XML of the identity provider metadata, used instead of the URL if set
+	ServiceProviderCertificate  string       // PEM encoded certificate of the service provider
+	ServiceProviderPrivateKey   string       // PEM encoded RSA private key of the service provider
+	SignRequests                bool         // sign authentication requests with the service provider key
+	NameIDFormat                NameIDFormat // requested name identifier format
+	AttributeUsername           string       // Username attribute, the name identifier is used if empty
+	AttributeEmail              string       // E-mail attribute
+	AttributeFullName           string       // Full name attribute
+	AttributeGroups             string       // Group membership attribute
+	GroupTeamMap                string       // Map groups to teams, JSON of {"group": {"Org": ["Team"]}}
+	GroupTeamMapRemoval         bool         // Remove user from mapped teams when not in the corresponding group
+	EnableAutoRegistration      bool         // create accounts for unknown users instead of asking them to link one
+}
+
+// User is the user asserted by the identity provider
+type User struct {
+	NameID   string
+	Username string
+	Email    string
+	FullName string
+	Groups   []string
+	// Attributes contains the values of all asserted attributes by name
+	Attributes map[string][]string
+}
+
+type cachedMetadata struct {
+	descriptor *saml.EntityDescriptor
+	expires    time.Time
+}
+
+var (
+	metadataCache      = make(map[string]cachedMetadata)
+	metadataCacheMutex sync.Mutex
+)
+
+// ParseMetadata parses identity provider metadata, which may be wrapped in an EntitiesDescriptor
+func ParseMetadata(data []byte) (*saml.EntityDescriptor, error) {
+	if err := xrv.Validate(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("invalid metadata: %v", err)
+	}
+
+	entity := &saml.EntityDescriptor{}
+	err := xml.Unmarshal(data, entity)
+	if err != nil && strings.Contains(err.Error(), "but have <EntitiesDescriptor>") {
+		entities := &saml.EntitiesDescriptor{}
+		if err := xml.Unmarshal(data, entities); err != nil {
+			return nil, fmt.Errorf("invalid metadata: %v", err)
+		}
+		for i := range entities.EntityDescriptors {
+			if len(entities.EntityDescriptors[i].IDPSSODescriptors) > 0 {
+				return &entities.EntityDescriptors[i], nil
+			}
+		}
+		return nil, errors.New("metadata contains no identity provider")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid metadata: %v", err)
+	}
+
+	if len(entity.IDPSSODescriptors) == 0 {
+		return nil, errors.New("metadata contains no identity provider")
+	}
+	return entity, nil
+}
+
+func fetchMetadata(metadataURL string) (*saml.EntityDescriptor, error) {
+	metadataCacheMutex.Lock()
+	defer metadataCacheMutex.Unlock()
+
+	if cached, ok := metadataCache[metadataURL]; ok && time.Now().Before(cached.expires) {
+		return cached.descriptor, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(metadataURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching metadata from %s failed: %s", metadataURL, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := ParseMetadata(data)
+	if err != nil {
+		return nil, err
+	}
+	metadataCache[metadataURL] = cachedMetadata{
+		descriptor: descriptor,
+		expires:    time.Now().Add(metadataCacheDuration),
+	}
+	return descriptor, nil
+}
+
+// IdentityProviderEntity returns the metadata of the identity provider
+func (s *Source) IdentityProviderEntity() (*saml.EntityDescriptor, error) {
+	if len(strings.TrimSpace(s.IdentityProviderMetadata)) > 0 {
+		return ParseMetadata([]byte(s.IdentityProviderMetadata))
+	}
+	if len(s.IdentityProviderMetadataURL) == 0 {
+		return nil, errors.New("neither identity provider metadata nor its URL is configured")
+	}
+	return fetchMetadata(s.IdentityProviderMetadataURL)
+}
+
+func (s *Source) keyPair() (*rsa.PrivateKey, tls.Certificate, error) {
+	keyPair, err := tls.X509KeyPair([]byte(s.ServiceProviderCertificate), []byte(s.ServiceProviderPrivateKey))
+	if err != nil {
+		return nil, keyPair, fmt.Errorf("invalid service provider key pair: %v", err)
+	}
+	key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, keyPair, errors.New("service provider private key is not an RSA key")
+	}
+	return key, keyPair, nil
+}
+
+// ServiceProvider returns the service provider of the source, its metadata and assertion consumer
+// service are served below baseURL
+func (s *Source) ServiceProvider(baseURL string) (*saml.ServiceProvider, error) {
+	key, keyPair, err := s.keyPair()
+	if err != nil {
+		return nil, err
+	}
+	idpMetadata, err := s.IdentityProviderEntity()
+	if err != nil {
+		return nil, err
+	}
+
+	metadataURL, err := url.Parse(baseURL + "/metadata")
+	if err != nil {
+		return nil, err
+	}
+	acsURL, err := url.Parse(baseURL + "/acs")
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &saml.ServiceProvider{
+		Key:               key,
+		Certificate:       keyPair.Leaf,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		IDPMetadata:       idpMetadata,
+		AuthnNameIDFormat: s.NameIDFormat.samlFormat(),
+	}
+	if sp.Certificate == nil {
+		if sp.Certificate, err = x509.ParseCertificate(keyPair.Certificate[0]); err != nil {
+			return nil, err
+		}
+	}
+	if s.SignRequests {
+		sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+	return sp, nil
+}
+
+// Validate checks whether the source is configured correctly
+func (s *Source) Validate() error {
+	if _, _, err := s.keyPair(); err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(s.IdentityProviderMetadata)) > 0 {
+		if _, err := ParseMetadata([]byte(s.IdentityProviderMetadata)); err != nil {
+			return err
+		}
+	} else if len(s.IdentityProviderMetadataURL) == 0 {
+		return errors.New("neither identity provider metadata nor its URL is configured")
+	} else if u, err := url.Parse(s.IdentityProviderMetadataURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid identity provider metadata URL: %s", s.IdentityProviderMetadataURL)
+	}
+	if _, err := s.GroupTeamMapping(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GroupTeamMapping parses the mapping of groups to the teams of organizations
+func (s *Source) GroupTeamMapping() (map[string]map[string][]string, error) {
+	mapping := make(map[string]map[string][]string)
+	if len(strings.TrimSpace(s.GroupTeamMap)) == 0 {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(s.GroupTeamMap), &mapping); err != nil {
+		return nil, fmt.Errorf("invalid group team map: %v", err)
+	}
+	return mapping, nil
+}
+
+// UseGroupTeamMap returns if groups are mapped to teams
+func (s *Source) UseGroupTeamMap() bool {
+	return len(s.AttributeGroups) > 0 && len(strings.TrimSpace(s.GroupTeamMap)) > 0
+}
+
+// checkSingleAssertion rejects responses which contain more than one assertion, the signature of
+// one assertion must not be accepted for another.
+func checkSingleAssertion(response []byte) error {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(response); err != nil {
+		return err
+	}
+	if len(doc.FindElements("//Assertion"))+len(doc.FindElements("//EncryptedAssertion")) != 1 {
+		return errors.New("response must contain exactly one assertion")
+	}
+	return nil
+}
+
+// ParseResponse validates the response posted by the identity provider in reply to one of the
+// requests with possibleRequestIDs and returns the asserted user
+func (s *Source) ParseResponse(sp *saml.ServiceProvider, req *http.Request, possibleRequestIDs []string) (*User, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	response, err := base64.StdEncoding.DecodeString(req.PostForm.Get("SAMLResponse"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAML response: %v", err)
+	}
+	if err := checkSingleAssertion(response); err != nil {
+		return nil, fmt.Errorf("invalid SAML response: %v", err)
+	}
+
+	assertion, err := sp.ParseXMLResponse(response, possibleRequestIDs)
+	if err != nil {
+		if invalidErr, ok := err.(*saml.InvalidResponseError); ok {
+			return nil, fmt.Errorf("invalid SAML response: %v", invalidErr.PrivateErr)
+		}
+		return nil, err
+	}
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || len(assertion.Subject.NameID.Value) == 0 {
+		return nil, errors.New("SAML assertion has no subject")
+	}
+
+	user := &User{
+		NameID:     assertion.Subject.NameID.Value,
+		Attributes: make(map[string][]string),
+	}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			for _, value := range attr.Values {
+				user.Attributes[attr.Name] = append(user.Attributes[attr.Name], value.Value)
+				if len(attr.FriendlyName) > 0 && attr.FriendlyName != attr.Name {
+					user.Attributes[attr.FriendlyName] = append(user.Attributes[attr.FriendlyName], value.Value)
+				}
+			}
+		}
+	}
+
+	user.Username = user.attribute(s.AttributeUsername)
+	if len(user.Username) == 0 {
+		user.Username = user.NameID
+	}
+	user.Email = user.attribute(s.AttributeEmail)
+	if len(user.Email) == 0 && strings.Contains(user.NameID, "@") {
+		user.Email = user.NameID
+	}
+	user.FullName = user.attribute(s.AttributeFullName)
+	if len(s.AttributeGroups) > 0 {
+		user.Groups = user.Attributes[s.AttributeGroups]
+	}
+	return user, nil
+}
+
+func (u *User) attribute(name string) string {
+	if len(name) == 0 {
+		return ""
+	}
+	if values := u.Attributes[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package saml
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+)
+
+const testBaseURL = "https://gitea.example.com/user/saml/test"
+
+func newTestIdentityProvider(t *testing.T) *saml.IdentityProvider {
+	certPEM, keyPEM, err := GenerateKeyPair("idp.example.com")
+	assert.NoError(t, err)
+	keyPair, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	assert.NoError(t, err)
+
+	metadataURL, _ := url.Parse("https://idp.example.com/metadata")
+	ssoURL, _ := url.Parse("https://idp.example.com/sso")
+	return &saml.IdentityProvider{
+		Key:         keyPair.PrivateKey,
+		Certificate: cert,
+		MetadataURL: *metadataURL,
+		SSOURL:      *ssoURL,
+	}
+}
+
+func newTestSource(t *testing.T, idp *saml.IdentityProvider) *Source {
+	metadata, err := xml.Marshal(idp.Metadata())
+	assert.NoError(t, err)
+	certPEM, keyPEM, err := GenerateKeyPair("gitea.example.com")
+	assert.NoError(t, err)
+
+	return &Source{
+		IdentityProviderMetadata:   string(metadata),
+		ServiceProviderCertificate: certPEM,
+		ServiceProviderPrivateKey:  keyPEM,
+		NameIDFormat:               NameIDFormatPersistent,
+		AttributeUsername:          "uid",
+		AttributeEmail:             "eduPersonPrincipalName",
+		AttributeFullName:          "cn",
+		AttributeGroups:            "eduPersonAffiliation",
+	}
+}
+
+// makeTestResponse returns the XML response of the identity provider to the request with requestID
+func makeTestResponse(t *testing.T, idp *saml.IdentityProvider, sp *saml.ServiceProvider, requestID string) []byte {
+	spMetadata := sp.Metadata()
+	req := &saml.IdpAuthnRequest{
+		IDP:                     idp,
+		HTTPRequest:             httptest.NewRequest("GET", idp.SSOURL.String(), nil),
+		Request:                 saml.AuthnRequest{ID: requestID},
+		ServiceProviderMetadata: spMetadata,
+		SPSSODescriptor:         &spMetadata.SPSSODescriptors[0],
+		ACSEndpoint:             &spMetadata.SPSSODescriptors[0].AssertionConsumerServices[0],
+		Now:                     saml.TimeNow(),
+	}
+	err := saml.DefaultAssertionMaker{}.MakeAssertion(req, &saml.Session{
+		ID:             "session",
+		CreateTime:     time.Now(),
+		ExpireTime:     time.Now().Add(time.Hour),
+		NameID:         "f2c6a8d3",
+		UserName:       "user2",
+		UserEmail:      "user2@example.com",
+		UserCommonName: "User Two",
+		Groups:         []string{"developers", "admins"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, req.MakeResponse())
+
+	doc := etree.NewDocument()
+	doc.SetRoot(req.ResponseEl)
+	response, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	return response
+}
+
+func postResponse(response []byte) *http.Request {
+	form := url.Values{"SAMLResponse": {base64.StdEncoding.EncodeToString(response)}}
+	req := httptest.NewRequest("POST", testBaseURL+"/acs", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestSource_ParseResponse(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	source := newTestSource(t, idp)
+	assert.NoError(t, source.Validate())
+
+	sp, err := source.ServiceProvider(testBaseURL)
+	assert.NoError(t, err)
+	assert.Equal(t, testBaseURL+"/metadata", sp.MetadataURL.String())
+	assert.Equal(t, testBaseURL+"/acs", sp.AcsURL.String())
+
+	authnRequest, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding))
+	assert.NoError(t, err)
+	response := makeTestResponse(t, idp, sp, authnRequest.ID)
+
+	user, err := source.ParseResponse(sp, postResponse(response), []string{authnRequest.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "f2c6a8d3", user.NameID)
+	assert.Equal(t, "user2", user.Username)
+	assert.Equal(t, "user2@example.com", user.Email)
+	assert.Equal(t, "User Two", user.FullName)
+	assert.Equal(t, []string{"developers", "admins"}, user.Groups)
+
+	// the username falls back to the name identifier
+	source.AttributeUsername = ""
+	user, err = source.ParseResponse(sp, postResponse(response), []string{authnRequest.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, "f2c6a8d3", user.Username)
+
+	// responses to other requests are rejected
+	_, err = source.ParseResponse(sp, postResponse(response), []string{"id-other"})
+	assert.Error(t, err)
+
+	// responses with more than one assertion are rejected
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromBytes(response))
+	assertion := doc.FindElement("//EncryptedAssertion")
+	if assert.NotNil(t, assertion) {
+		doc.Root().AddChild(assertion.Copy())
+	}
+	duplicated, err := doc.WriteToBytes()
+	assert.NoError(t, err)
+	_, err = source.ParseResponse(sp, postResponse(duplicated), []string{authnRequest.ID})
+	assert.Error(t, err)
+
+	// responses signed by another identity provider are rejected
+	other := newTestIdentityProvider(t)
+	_, err = source.ParseResponse(sp, postResponse(makeTestResponse(t, other, sp, authnRequest.ID)), []string{authnRequest.ID})
+	assert.Error(t, err)
+}
+
+func TestSource_Validate(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	source := newTestSource(t, idp)
+	assert.NoError(t, source.Validate())
+
+	source.GroupTeamMap = `{"developers": {"org3": ["team1"]}}`
+	assert.NoError(t, source.Validate())
+	assert.True(t, source.UseGroupTeamMap())
+	source.GroupTeamMap = "{"
+	assert.Error(t, source.Validate())
+	source.GroupTeamMap = ""
+
+	metadata := source.IdentityProviderMetadata
+	source.IdentityProviderMetadata = "<EntityDescriptor"
+	assert.Error(t, source.Validate())
+	source.IdentityProviderMetadata = ""
+	assert.Error(t, source.Validate())
+	source.IdentityProviderMetadataURL = "ftp://idp.example.com/metadata"
+	assert.Error(t, source.Validate())
+	source.IdentityProviderMetadataURL = "https://idp.example.com/metadata"
+	assert.NoError(t, source.Validate())
+	source.IdentityProviderMetadata = metadata
+
+	source.ServiceProviderPrivateKey = ""
+	assert.Error(t, source.Validate())
+}
+
+func TestParseMetadata(t *testing.T) {
+	idp := newTestIdentityProvider(t)
+	entity, err := xml.Marshal(idp.Metadata())
+	assert.NoError(t, err)
+
+	descriptor, err := ParseMetadata(entity)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/metadata", descriptor.EntityID)
+
+	entities := `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">` + string(entity) + `</EntitiesDescriptor>`
+	descriptor, err = ParseMetadata([]byte(entities))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/metadata", descriptor.EntityID)
+
+	_, err = ParseMetadata([]byte(`<EntityDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sp.example.com"></EntityDescriptor>`))
+	assert.Error(t, err)
+}
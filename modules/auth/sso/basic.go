@@ -85,6 +85,11 @@ func (b *Basic) VerifyAuthData(ctx *macaron.Context, sess session.Store) *models
 	}
 	token, err := models.GetAccessTokenBySHA(authToken)
 	if err == nil {
+		if token.IsRestricted() && !isScopedTokenPath(ctx) {
+			return nil
+		}
+		ctx.Data["ApiToken"] = token
+
 		u, err = models.GetUserByID(token.UID)
 		if err != nil {
 			log.Error("GetUserByID:  %v", err)
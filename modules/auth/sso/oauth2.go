@@ -98,11 +98,15 @@ func (o *OAuth2) userIDFromToken(ctx *macaron.Context) int64 {
 		}
 		return 0
 	}
+	if t.IsRestricted() && !isScopedTokenPath(ctx) {
+		return 0
+	}
 	t.UpdatedUnix = timeutil.TimeStampNow()
 	if err = models.UpdateAccessToken(t); err != nil {
 		log.Error("UpdateAccessToken: %v", err)
 	}
 	ctx.Data["IsApiToken"] = true
+	ctx.Data["ApiToken"] = t
 	return t.UID
 }
 
@@ -8,6 +8,7 @@ package sso
 import (
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 
 	"code.gitea.io/gitea/models"
@@ -100,6 +101,17 @@ func isAPIPath(ctx *macaron.Context) bool {
 	return strings.HasPrefix(ctx.Req.URL.Path, "/api/")
 }
 
+// gitPathRegex matches the paths of git smart HTTP and LFS requests
+var gitPathRegex = regexp.MustCompile(`^/[^/]+/[^/]+/(?:info/refs|git-upload-pack|git-receive-pack|info/lfs/)`)
+
+// isScopedTokenPath returns true if restricted access tokens may authenticate
+// requests to the specified URL. These are the API, the container registry, git and LFS,
+// the web interface requires an unrestricted token.
+func isScopedTokenPath(ctx *macaron.Context) bool {
+	path := ctx.Req.URL.Path
+	return isAPIPath(ctx) || path == "/v2" || strings.HasPrefix(path, "/v2/") || gitPathRegex.MatchString(path)
+}
+
 // isInternalPath returns true if the specified URL is an internal API path
 func isInternalPath(ctx *macaron.Context) bool {
 	return strings.HasPrefix(ctx.Req.URL.Path, "/api/internal/")
@@ -118,7 +130,7 @@ func handleSignIn(ctx *macaron.Context, sess session.Store, user *models.User) {
 	_ = sess.Delete("openid_determined_username")
 	_ = sess.Delete("twofaUid")
 	_ = sess.Delete("twofaRemember")
-	_ = sess.Delete("u2fChallenge")
+	_ = sess.Delete("webauthnAssertion")
 	_ = sess.Delete("linkAccount")
 	err := sess.Set("uid", user.ID)
 	if err != nil {
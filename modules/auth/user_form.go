@@ -83,6 +83,7 @@ type RegisterForm struct {
 	Password           string `binding:"MaxSize(255)"`
 	Retype             string
 	GRecaptchaResponse string `form:"g-recaptcha-response"`
+	HcaptchaResponse   string `form:"h-captcha-response"`
 }
 
 // Validate validates the fields
@@ -146,10 +147,14 @@ type AuthorizationForm struct {
 	ClientID     string `binding:"Required"`
 	RedirectURI  string
 	State        string
+	Scope        string
 
 	// PKCE support
 	CodeChallengeMethod string // S256, plain
 	CodeChallenge       string
+
+	// OpenID Connect support
+	Nonce string
 }
 
 // Validate validates the fields
@@ -302,7 +307,9 @@ func (f *AddKeyForm) Validate(ctx *macaron.Context, errs binding.Errors) binding
 
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
-	Name string `binding:"Required;MaxSize(255)"`
+	Name         string `binding:"Required;MaxSize(255)"`
+	Scope        []string
+	Repositories string
 }
 
 // Validate validates the fields
@@ -341,22 +348,34 @@ func (f *TwoFactorScratchAuthForm) Validate(ctx *macaron.Context, errs binding.E
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
-// U2FRegistrationForm for reserving an U2F name
-type U2FRegistrationForm struct {
-	Name string `binding:"Required"`
+// WebAuthnRegistrationForm for reserving a WebAuthn name
+type WebAuthnRegistrationForm struct {
+	Name         string `binding:"Required;MaxSize(255)"`
+	Passwordless bool
 }
 
 // Validate validates the fields
-func (f *U2FRegistrationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+func (f *WebAuthnRegistrationForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
 
-// U2FDeleteForm for deleting U2F keys
-type U2FDeleteForm struct {
+// WebAuthnDeleteForm for deleting WebAuthn keys
+type WebAuthnDeleteForm struct {
 	ID int64 `binding:"Required"`
 }
 
 // Validate validates the fields
-func (f *U2FDeleteForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+func (f *WebAuthnDeleteForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
+	return validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// BlockUserForm form for blocking a user
+type BlockUserForm struct {
+	Blockee string `binding:"Required"`
+	Note    string `binding:"MaxSize(255)"`
+}
+
+// Validate validates the fields
+func (f *BlockUserForm) Validate(ctx *macaron.Context, errs binding.Errors) binding.Errors {
 	return validate(errs, ctx.Data, f, ctx.Locale)
 }
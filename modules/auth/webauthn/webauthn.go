@@ -0,0 +1,126 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+)
+
+// WebAuthn is the relying party of the instance, it is available after Init has been called
+var WebAuthn *webauthn.WebAuthn
+
+// Init initializes the relying party from the configured application URL
+func Init() error {
+	appURL, err := url.Parse(setting.AppURL)
+	if err != nil {
+		return err
+	}
+	WebAuthn, err = webauthn.New(&webauthn.Config{
+		RPDisplayName:         setting.AppName,
+		RPID:                  appURL.Hostname(),
+		RPOrigin:              appURL.Scheme + "://" + appURL.Host,
+		AttestationPreference: protocol.PreferNoAttestation,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			UserVerification: protocol.VerificationDiscouraged,
+		},
+	})
+	return err
+}
+
+// User represents an implementation of webauthn.User based on models.User
+type User models.User
+
+// WebAuthnID implements the webauthn.User interface
+func (u *User) WebAuthnID() []byte {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, uint64(u.ID))
+	return id
+}
+
+// WebAuthnName implements the webauthn.User interface
+func (u *User) WebAuthnName() string {
+	return u.Name
+}
+
+// WebAuthnDisplayName implements the webauthn.User interface
+func (u *User) WebAuthnDisplayName() string {
+	return (*models.User)(u).DisplayName()
+}
+
+// WebAuthnIcon implements the webauthn.User interface
+func (u *User) WebAuthnIcon() string {
+	return (*models.User)(u).AvatarLink()
+}
+
+// WebAuthnCredentials implements the webauthn.User interface
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	creds, err := models.GetWebAuthnCredentialsByUID(u.ID)
+	if err != nil {
+		return nil
+	}
+	return creds.ToCredentials()
+}
+
+// AppIDExtension returns the extension which lets authenticators sign with credentials
+// that were registered through the legacy FIDO U2F API
+func AppIDExtension() protocol.AuthenticationExtensions {
+	return protocol.AuthenticationExtensions{"appid": legacyAppID()}
+}
+
+func legacyAppID() string {
+	return strings.TrimSuffix(setting.U2F.AppID, "/")
+}
+
+// BeginDiscoverableLogin creates the options for a passwordless login where the
+// authenticator chooses the credential and thereby identifies the user
+func BeginDiscoverableLogin() (*protocol.CredentialAssertion, *webauthn.SessionData, error) {
+	challenge, err := protocol.CreateChallenge()
+	if err != nil {
+		return nil, nil, err
+	}
+	assertion := &protocol.CredentialAssertion{
+		Response: protocol.PublicKeyCredentialRequestOptions{
+			Challenge:        challenge,
+			Timeout:          WebAuthn.Config.Timeout,
+			RelyingPartyID:   WebAuthn.Config.RPID,
+			UserVerification: protocol.VerificationRequired,
+		},
+	}
+	session := &webauthn.SessionData{
+		Challenge:        base64.RawURLEncoding.EncodeToString(challenge),
+		UserVerification: protocol.VerificationRequired,
+	}
+	return assertion, session, nil
+}
+
+// ValidateLogin validates the assertion of the user, assertions of credentials migrated
+// from FIDO U2F are scoped to the legacy AppID instead of the relying party id
+func ValidateLogin(user *User, session webauthn.SessionData, parsed *protocol.ParsedCredentialAssertionData) (*webauthn.Credential, error) {
+	rp := WebAuthn
+	appIDHash := sha256.Sum256([]byte(legacyAppID()))
+	if legacyAppID() != rp.Config.RPID && bytes.Equal(parsed.Response.AuthenticatorData.RPIDHash, appIDHash[:]) &&
+		isMigratedCredential(parsed.RawID) {
+		config := *rp.Config
+		config.RPID = legacyAppID()
+		rp = &webauthn.WebAuthn{Config: &config}
+	}
+	return rp.ValidateLogin(user, session, parsed)
+}
+
+func isMigratedCredential(credID []byte) bool {
+	cred, err := models.GetWebAuthnCredentialByCredID(credID)
+	return err == nil && cred.AttestationType == "fido-u2f"
+}
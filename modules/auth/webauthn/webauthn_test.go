@@ -0,0 +1,43 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package webauthn
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit(t *testing.T) {
+	setting.AppName = "Gitea"
+	setting.AppURL = "https://gitea.example.com:3000/sub/"
+	assert.NoError(t, Init())
+
+	assert.Equal(t, "Gitea", WebAuthn.Config.RPDisplayName)
+	assert.Equal(t, "gitea.example.com", WebAuthn.Config.RPID)
+	assert.Equal(t, "https://gitea.example.com:3000", WebAuthn.Config.RPOrigin)
+}
+
+func TestUser_WebAuthnID(t *testing.T) {
+	u := &User{ID: 258}
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 1, 2}, u.WebAuthnID())
+}
+
+func TestBeginDiscoverableLogin(t *testing.T) {
+	setting.AppURL = "https://gitea.example.com/"
+	assert.NoError(t, Init())
+
+	assertion, session, err := BeginDiscoverableLogin()
+	assert.NoError(t, err)
+	assert.Empty(t, assertion.Response.AllowedCredentials)
+	assert.Equal(t, "gitea.example.com", assertion.Response.RelyingPartyID)
+	assert.Equal(t, protocol.VerificationRequired, assertion.Response.UserVerification)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(assertion.Response.Challenge), session.Challenge)
+	assert.Empty(t, session.UserID)
+}
@@ -41,6 +41,11 @@ func NewContext() error {
 	return err
 }
 
+// GetCache returns the cache of the cache service, it is nil if the cache service is disabled
+func GetCache() mc.Cache {
+	return conn
+}
+
 // GetString returns the key value from cache with callback when no key exists in cache
 func GetString(key string, getFunc func() (string, error)) (string, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
@@ -0,0 +1,38 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Audit records a security relevant event with the IP address of the request. The signed in user
+// is the actor and the current repository or organization the owner unless the event sets them,
+// events outside of a repository or organization belong to the account of the actor.
+func (ctx *Context) Audit(event *models.AuditEvent) {
+	if event.ActorID == 0 && event.ActorName == "" && ctx.User != nil {
+		event.ActorID = ctx.User.ID
+		event.ActorName = ctx.User.Name
+	}
+	if event.OwnerID == 0 && ctx.Repo != nil && ctx.Repo.Repository != nil &&
+		(event.RepoID == 0 || event.RepoID == ctx.Repo.Repository.ID) {
+		event.OwnerID = ctx.Repo.Repository.OwnerID
+		event.RepoID = ctx.Repo.Repository.ID
+	}
+	if event.OwnerID == 0 && ctx.Org != nil && ctx.Org.Organization != nil {
+		event.OwnerID = ctx.Org.Organization.ID
+	}
+	if event.OwnerID == 0 {
+		event.OwnerID = event.ActorID
+	}
+	event.IPAddress = strings.Trim(ctx.RemoteAddr(), "[]")
+
+	if err := models.CreateAuditEvent(event); err != nil {
+		log.Error("Unable to record audit event %s of %s: %v", event.Action, event.ActorName, err)
+	}
+}
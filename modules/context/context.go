@@ -44,8 +44,10 @@ type Context struct {
 	IsSigned    bool
 	IsBasicAuth bool
 
-	Repo *Repository
-	Org  *Organization
+	Repo         *Repository
+	Org          *Organization
+	Package      *Package
+	ProjectOwner *ProjectOwner
 }
 
 // IsUserSiteAdmin returns true if current user is a site admin
@@ -84,6 +86,26 @@ func (ctx *Context) IsUserRepoReaderAny() bool {
 	return ctx.Repo.HasAccess()
 }
 
+// AccessToken returns the personal access token the request was authenticated with, if any
+func (ctx *Context) AccessToken() *models.AccessToken {
+	t, _ := ctx.Data["ApiToken"].(*models.AccessToken)
+	return t
+}
+
+// HasTokenScope returns true if the request is not authenticated by an access token or
+// the access token grants at least the level on the category
+func (ctx *Context) HasTokenScope(category models.AccessTokenScopeCategory, level models.AccessTokenScopeLevel) bool {
+	t := ctx.AccessToken()
+	return t == nil || t.HasScope(category, level)
+}
+
+// CanTokenAccessRepo returns true if the request is not authenticated by an access token or
+// the access token is not restricted to other repositories
+func (ctx *Context) CanTokenAccessRepo(repoID int64) bool {
+	t := ctx.AccessToken()
+	return t == nil || t.CanAccessRepo(repoID)
+}
+
 // HasAPIError returns true if error occurs in form validation.
 func (ctx *Context) HasAPIError() bool {
 	hasErr, ok := ctx.Data["HasError"]
@@ -342,6 +364,7 @@ func Contexter() macaron.Handler {
 		ctx.Data["ShowFooterVersion"] = setting.ShowFooterVersion
 
 		ctx.Data["EnableSwagger"] = setting.API.EnableSwagger
+		ctx.Data["EnablePackages"] = setting.Packages.Enabled
 		ctx.Data["EnableOpenIDSignIn"] = setting.Service.EnableOpenIDSignIn
 
 		c.Map(ctx)
@@ -0,0 +1,67 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+
+	"gitea.com/macaron/macaron"
+)
+
+// Package contains owner and access mode of the packages in the current request
+type Package struct {
+	Owner      *models.User
+	AccessMode models.AccessMode
+}
+
+// PackageAssignment returns a middleware to handle Context.Package assignment
+func PackageAssignment() macaron.Handler {
+	return func(ctx *Context) {
+		packageAssignment(ctx, func(status int, title string, obj interface{}) {
+			err, ok := obj.(error)
+			if !ok {
+				err = fmt.Errorf("%s", obj)
+			}
+			if status == http.StatusNotFound {
+				ctx.NotFound(title, err)
+			} else {
+				ctx.ServerError(title, err)
+			}
+		})
+	}
+}
+
+// PackageAssignmentAPI returns a middleware to handle Context.Package assignment for API routes
+func PackageAssignmentAPI() macaron.Handler {
+	return func(ctx *APIContext) {
+		packageAssignment(ctx.Context, ctx.Error)
+	}
+}
+
+func packageAssignment(ctx *Context, errCb func(int, string, interface{})) {
+	owner, err := models.GetUserByName(ctx.Params(":username"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			errCb(http.StatusNotFound, "GetUserByName", err)
+		} else {
+			errCb(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	accessMode, err := models.GetPackageAccessMode(owner, ctx.User)
+	if err != nil {
+		errCb(http.StatusInternalServerError, "GetPackageAccessMode", err)
+		return
+	}
+
+	ctx.Package = &Package{
+		Owner:      owner,
+		AccessMode: accessMode,
+	}
+}
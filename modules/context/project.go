@@ -0,0 +1,60 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"code.gitea.io/gitea/models"
+
+	"gitea.com/macaron/macaron"
+)
+
+// ProjectOwner contains the user or organization owning the projects in the current request
+// and the access mode the doer has on them
+type ProjectOwner struct {
+	Owner      *models.User
+	AccessMode models.AccessMode
+}
+
+// CanWrite returns true if the doer may create and change projects of the owner
+func (p *ProjectOwner) CanWrite() bool {
+	return p.AccessMode >= models.AccessModeWrite
+}
+
+// ProjectOwnerAssignment returns a middleware to handle Context.ProjectOwner assignment
+func ProjectOwnerAssignment() macaron.Handler {
+	return func(ctx *Context) {
+		owner, err := models.GetUserByName(ctx.Params(":username"))
+		if err != nil {
+			ctx.NotFoundOrServerError("GetUserByName", models.IsErrUserNotExist, err)
+			return
+		}
+
+		accessMode, err := models.GetOwnerProjectsAccessMode(owner, ctx.User)
+		if err != nil {
+			ctx.ServerError("GetOwnerProjectsAccessMode", err)
+			return
+		} else if accessMode < models.AccessModeRead {
+			ctx.NotFound("ProjectOwnerAssignment", nil)
+			return
+		}
+
+		ctx.ProjectOwner = &ProjectOwner{
+			Owner:      owner,
+			AccessMode: accessMode,
+		}
+		ctx.Data["ContextUser"] = owner
+		ctx.Data["CanWriteProjects"] = ctx.ProjectOwner.CanWrite()
+	}
+}
+
+// RequireProjectOwnerWriter returns a middleware for requiring write access to the projects
+// of the owner
+func RequireProjectOwnerWriter() macaron.Handler {
+	return func(ctx *Context) {
+		if !ctx.ProjectOwner.CanWrite() {
+			ctx.NotFound("RequireProjectOwnerWriter", nil)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package context
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/ratelimit"
+	"code.gitea.io/gitea/modules/setting"
+
+	"gitea.com/macaron/macaron"
+)
+
+// IsRateLimited takes the request from the budget of the access token, the user or else the IP
+// address of the request. It sets the X-RateLimit-* headers and returns true if the budget is
+// exhausted, the caller then responds with http.StatusTooManyRequests.
+func (ctx *Context) IsRateLimited(limiter *ratelimit.Limiter, user *models.User) bool {
+	if !limiter.Enabled() {
+		return false
+	}
+
+	remoteAddr := strings.Trim(ctx.RemoteAddr(), "[]")
+	var key string
+	if token, ok := ctx.Data["ApiToken"].(*models.AccessToken); ok && token.ID > 0 {
+		key = fmt.Sprintf("token_%d", token.ID)
+	} else if user != nil && user.ID > 0 {
+		key = fmt.Sprintf("user_%d", user.ID)
+	} else {
+		key = "ip_" + remoteAddr
+	}
+
+	exempt := false
+	if user != nil {
+		exempt = ratelimit.IsExempt(user.Name, user.IsAdmin, remoteAddr)
+	} else {
+		exempt = ratelimit.IsExempt("", false, remoteAddr)
+	}
+
+	result, err := limiter.Take(key, exempt)
+	if err != nil {
+		// Don't refuse requests because of a broken cache
+		log.Error("Unable to check the %s rate limit of %s: %v", limiter.Name(), key, err)
+		return false
+	}
+	if result == nil {
+		return false
+	}
+
+	header := ctx.Resp.Header()
+	header.Set("X-RateLimit-Limit", fmt.Sprint(result.Limit))
+	header.Set("X-RateLimit-Remaining", fmt.Sprint(result.Remaining))
+	header.Set("X-RateLimit-Reset", fmt.Sprint(result.Reset.Unix()))
+	if !result.Allowed {
+		header.Set("Retry-After", fmt.Sprint(int64(math.Ceil(result.RetryAfter.Seconds()))))
+		log.Warn("Rate limit of %s requests exceeded by %s from %s", limiter.Name(), key, remoteAddr)
+		return true
+	}
+	return false
+}
+
+// RateLimit returns a middleware which limits the requests of signed in users and anonymous
+// visitors to the budget of the limiter
+func RateLimit(limiter *ratelimit.Limiter) macaron.Handler {
+	return func(ctx *Context) {
+		if ctx.IsRateLimited(limiter, ctx.User) {
+			ctx.Error(http.StatusTooManyRequests, ctx.Tr("error.too_many_requests"))
+		}
+	}
+}
+
+// APIRateLimit returns a middleware which limits the API requests to the budget of the limiter
+func APIRateLimit(limiter *ratelimit.Limiter) macaron.Handler {
+	return func(ctx *APIContext) {
+		if ctx.IsRateLimited(limiter, ctx.User) {
+			ctx.JSON(http.StatusTooManyRequests, APIError{
+				Message: "API rate limit exceeded",
+				URL:     setting.API.SwaggerURL,
+			})
+		}
+	}
+}
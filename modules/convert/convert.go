@@ -122,6 +122,7 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 		ApprovalsWhitelistTeams:     approvalsWhitelistTeams,
 		BlockOnRejectedReviews:      bp.BlockOnRejectedReviews,
 		BlockOnOutdatedBranch:       bp.BlockOnOutdatedBranch,
+		BlockOnCodeOwnerReviews:     bp.BlockOnCodeOwnerReviews,
 		DismissStaleApprovals:       bp.DismissStaleApprovals,
 		RequireSignedCommits:        bp.RequireSignedCommits,
 		ProtectedFilePatterns:       bp.ProtectedFilePatterns,
@@ -130,6 +131,27 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 	}
 }
 
+// ToTagProtection convert a ProtectedTag to api.TagProtection
+func ToTagProtection(pt *models.ProtectedTag) *api.TagProtection {
+	allowlistUsernames, err := models.GetUserNamesByIDs(pt.AllowlistUserIDs)
+	if err != nil {
+		log.Error("GetUserNamesByIDs (AllowlistUserIDs): %v", err)
+	}
+	allowlistTeams, err := models.GetTeamNamesByID(pt.AllowlistTeamIDs)
+	if err != nil {
+		log.Error("GetTeamNamesByID (AllowlistTeamIDs): %v", err)
+	}
+
+	return &api.TagProtection{
+		ID:                 pt.ID,
+		NamePattern:        pt.NamePattern,
+		AllowlistUsernames: allowlistUsernames,
+		AllowlistTeams:     allowlistTeams,
+		Created:            pt.CreatedUnix.AsTime(),
+		Updated:            pt.UpdatedUnix.AsTime(),
+	}
+}
+
 // ToTag convert a git.Tag to an api.Tag
 func ToTag(repo *models.Repository, t *git.Tag) *api.Tag {
 	return &api.Tag{
@@ -339,3 +361,27 @@ func ToOAuth2Application(app *models.OAuth2Application) *api.OAuth2Application {
 		Created:      app.CreatedUnix.AsTime(),
 	}
 }
+
+// ToAuditEvent convert from models.AuditEvent to api.AuditEvent
+func ToAuditEvent(event *models.AuditEvent) *api.AuditEvent {
+	apiEvent := &api.AuditEvent{
+		ID:          event.ID,
+		Action:      string(event.Action),
+		ActorID:     event.ActorID,
+		ActorName:   event.ActorName,
+		OwnerID:     event.OwnerID,
+		RepoID:      event.RepoID,
+		TargetID:    event.TargetID,
+		TargetName:  event.TargetName,
+		IPAddress:   event.IPAddress,
+		Description: event.Description,
+		Created:     event.CreatedUnix.AsTime(),
+	}
+	if event.Owner != nil {
+		apiEvent.OwnerName = event.Owner.Name
+	}
+	if event.Repo != nil {
+		apiEvent.RepoName = event.Repo.FullName()
+	}
+	return apiEvent
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToPushMirror convert models.PushMirror to api.PushMirror
+func ToPushMirror(m *models.PushMirror) *api.PushMirror {
+	apiMirror := &api.PushMirror{
+		ID:            m.ID,
+		RemoteAddress: m.RemoteAddress,
+		Interval:      m.Interval.String(),
+		Created:       m.CreatedUnix.AsTime(),
+		LastError:     m.LastError,
+	}
+	if m.LastUpdateUnix != 0 {
+		lastUpdate := m.LastUpdateUnix.AsTime()
+		apiMirror.LastUpdate = &lastUpdate
+	}
+	return apiMirror
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+var projectTypeNames = map[models.ProjectType]string{
+	models.ProjectTypeIndividual:   "individual",
+	models.ProjectTypeRepository:   "repository",
+	models.ProjectTypeOrganization: "organization",
+}
+
+// ToProject converts a models.Project with its repository or owner loaded to an api.Project
+func ToProject(p *models.Project, doer *models.User) *api.Project {
+	result := &api.Project{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Type:        projectTypeNames[p.Type],
+		State:       api.StateOpen,
+		HTMLURL:     p.HTMLURL(),
+		Created:     p.CreatedUnix.AsTime(),
+		Updated:     p.UpdatedUnix.AsTime(),
+	}
+	if p.IsClosed {
+		result.State = api.StateClosed
+		closed := p.ClosedDateUnix.AsTime()
+		result.Closed = &closed
+	}
+	if p.Repo != nil {
+		result.Repo = &api.RepositoryMeta{
+			ID:       p.Repo.ID,
+			Name:     p.Repo.Name,
+			Owner:    p.Repo.OwnerName,
+			FullName: p.Repo.FullName(),
+		}
+	}
+	if p.Owner != nil {
+		result.Owner = ToUser(p.Owner, doer != nil, doer != nil && doer.IsAdmin)
+	}
+	return result
+}
+
+// ToProjectBoard converts a models.ProjectBoard to an api.ProjectBoard
+func ToProjectBoard(board *models.ProjectBoard) *api.ProjectBoard {
+	return &api.ProjectBoard{
+		ID:      board.ID,
+		Title:   board.Title,
+		Created: board.CreatedUnix.AsTime(),
+		Updated: board.UpdatedUnix.AsTime(),
+	}
+}
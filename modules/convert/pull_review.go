@@ -27,7 +27,6 @@ func ToPullReview(r *models.Review, doer *models.User) (*api.PullReview, error)
 
 	result := &api.PullReview{
 		ID:                r.ID,
-		Reviewer:          ToUser(r.Reviewer, doer != nil, auth),
 		State:             api.ReviewStateUnknown,
 		Body:              r.Content,
 		CommitID:          r.CommitID,
@@ -39,6 +38,12 @@ func ToPullReview(r *models.Review, doer *models.User) (*api.PullReview, error)
 		HTMLPullURL:       r.Issue.HTMLURL(),
 	}
 
+	if r.ReviewerTeam != nil {
+		result.ReviewerTeam = ToTeam(r.ReviewerTeam)
+	} else {
+		result.Reviewer = ToUser(r.Reviewer, doer != nil, auth)
+	}
+
 	switch r.Type {
 	case models.ReviewTypeApprove:
 		result.State = api.ReviewStateApproved
@@ -108,8 +113,14 @@ func ToPullReviewCommentList(review *models.Review, doer *models.User) ([]*api.P
 
 				if comment.Line < 0 {
 					apiComment.OldLineNum = comment.UnsignedLine()
+					if comment.IsMultiLine() {
+						apiComment.OldStartLineNum = comment.UnsignedStartLine()
+					}
 				} else {
 					apiComment.LineNum = comment.UnsignedLine()
+					if comment.IsMultiLine() {
+						apiComment.StartLineNum = comment.UnsignedStartLine()
+					}
 				}
 				apiComments = append(apiComments, apiComment)
 			}
@@ -33,3 +33,12 @@ func ToUser(user *models.User, signed, authed bool) *api.User {
 	}
 	return result
 }
+
+// ToBlockedUser convert models.BlockedUser to api.BlockedUser
+func ToBlockedUser(block *models.BlockedUser, doer *models.User) *api.BlockedUser {
+	return &api.BlockedUser{
+		User:    ToUser(block.Blockee, doer != nil, doer != nil && doer.IsAdmin),
+		Note:    block.Note,
+		Created: block.CreatedUnix.AsTime(),
+	}
+}
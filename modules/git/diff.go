@@ -133,9 +133,19 @@ func isHeader(lof string) bool {
 // it also recalculates hunks and adds the appropriate headers to the new diff.
 // Warning: Only one-file diffs are allowed.
 func CutDiffAroundLine(originalDiff io.Reader, line int64, old bool, numbersOfLine int) string {
+	// a single line is always within one hunk
+	diff, _ := CutDiffAroundLines(originalDiff, line, line, old, numbersOfLine)
+	return diff
+}
+
+// CutDiffAroundLines works like CutDiffAroundLine but keeps at least all lines from startLine to line,
+// so that the whole range of a multi-line comment is shown. It returns an ErrRangeSpansHunks if
+// startLine is not in the hunk of line.
+// Warning: Only one-file diffs are allowed.
+func CutDiffAroundLines(originalDiff io.Reader, startLine, line int64, old bool, numbersOfLine int) (string, error) {
 	if line == 0 || numbersOfLine == 0 {
 		// no line or num of lines => no diff
-		return ""
+		return "", nil
 	}
 	scanner := bufio.NewScanner(originalDiff)
 	hunk := make([]string, 0)
@@ -144,7 +154,7 @@ func CutDiffAroundLine(originalDiff io.Reader, line int64, old bool, numbersOfLi
 	// currentLine is the line number on the side of the searched line (differentiated by old)
 	// otherLine is the line number on the opposite side of the searched line (differentiated by old)
 	var begin, end, currentLine, otherLine int64
-	var headerLines int
+	var headerLines, startIndex int
 	for scanner.Scan() {
 		lof := scanner.Text()
 		// Add header to enable parsing
@@ -211,17 +221,29 @@ func CutDiffAroundLine(originalDiff io.Reader, line int64, old bool, numbersOfLi
 				currentLine++
 				otherLine++
 			}
+			// currentLine is one ahead of the line just read
+			if startIndex == 0 && currentLine == startLine+1 {
+				startIndex = len(hunk) - 1
+			}
 		}
 	}
 
 	// No hunk found
 	if currentLine == 0 {
-		return ""
+		return "", nil
+	}
+	// the hunk does not contain the whole commented range
+	if startLine < begin {
+		return "", ErrRangeSpansHunks{StartLine: startLine, Line: line}
+	}
+	// keep the whole commented range
+	if startLine < line && startIndex > 0 && len(hunk)-startIndex > numbersOfLine {
+		numbersOfLine = len(hunk) - startIndex
 	}
 	// headerLines + hunkLine (1) = totalNonCodeLines
 	if len(hunk)-headerLines-1 <= numbersOfLine {
 		// No need to cut the hunk => return existing hunk
-		return strings.Join(hunk, "\n")
+		return strings.Join(hunk, "\n"), nil
 	}
 	var oldBegin, oldNumOfLines, newBegin, newNumOfLines int64
 	if old {
@@ -256,5 +278,5 @@ func CutDiffAroundLine(originalDiff io.Reader, line int64, old bool, numbersOfLi
 	// construct the new hunk header
 	newHunk[headerLines] = fmt.Sprintf("@@ -%d,%d +%d,%d @@",
 		oldBegin, oldNumOfLines, newBegin, newNumOfLines)
-	return strings.Join(newHunk, "\n")
+	return strings.Join(newHunk, "\n"), nil
 }
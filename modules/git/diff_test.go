@@ -51,6 +51,47 @@ func TestCutDiffAroundLine(t *testing.T) {
 	assert.Empty(t, emptyResult)
 }
 
+func TestCutDiffAroundLines(t *testing.T) {
+	// the range is shorter than the context, so the result is the same as for the last line alone
+	result, err := CutDiffAroundLines(strings.NewReader(exampleDiff), 3, 4, false, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, CutDiffAroundLine(strings.NewReader(exampleDiff), 4, false, 3), result)
+
+	result, err = CutDiffAroundLines(strings.NewReader(exampleDiff), 2, 6, false, 1)
+	assert.NoError(t, err)
+	resultByLine := strings.Split(result, "\n")
+	assert.Len(t, resultByLine, 10)
+	assert.Equal(t, "@@ -2,2 +2,5 @@", resultByLine[3])
+	assert.Equal(t, "+", resultByLine[4])
+	assert.Equal(t, "+ cut off", resultByLine[9])
+
+	result, err = CutDiffAroundLines(strings.NewReader(exampleDiff), 2, 3, true, 1)
+	assert.NoError(t, err)
+	resultByLine = strings.Split(result, "\n")
+	assert.Equal(t, "@@ -2,2 +4,1 @@", resultByLine[3])
+	assert.Equal(t, "- Latest Release", resultByLine[4])
+	assert.Equal(t, " Docker Pulls", resultByLine[5])
+
+	// the range starts in an earlier hunk than it ends
+	twoHunksDiff := `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,2 +1,3 @@
+ # gitea-github-migrator
++ Build Status
+ Docker Pulls
+@@ -20,2 +21,3 @@
+ Usage
++ cut off
+ License`
+	_, err = CutDiffAroundLines(strings.NewReader(twoHunksDiff), 2, 22, false, 3)
+	assert.True(t, IsErrRangeSpansHunks(err))
+
+	result, err = CutDiffAroundLines(strings.NewReader(twoHunksDiff), 21, 22, false, 3)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "@@ -20,2 +21,3 @@")
+}
+
 func BenchmarkCutDiffAroundLine(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		CutDiffAroundLine(strings.NewReader(exampleDiff), 3, true, 3)
@@ -159,3 +159,19 @@ func (err *ErrPushRejected) GenerateMessage() {
 	}
 	err.Message = strings.TrimSpace(messageBuilder.String())
 }
+
+// ErrRangeSpansHunks represents an error when a range of lines of a diff is not within one hunk
+type ErrRangeSpansHunks struct {
+	StartLine int64
+	Line      int64
+}
+
+// IsErrRangeSpansHunks checks if an error is a ErrRangeSpansHunks.
+func IsErrRangeSpansHunks(err error) bool {
+	_, ok := err.(ErrRangeSpansHunks)
+	return ok
+}
+
+func (err ErrRangeSpansHunks) Error() string {
+	return fmt.Sprintf("range of lines spans several hunks [start_line: %d, line: %d]", err.StartLine, err.Line)
+}
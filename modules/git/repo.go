@@ -42,6 +42,7 @@ type GPGSettings struct {
 	KeyID            string
 	Email            string
 	Name             string
+	Format           string
 	PublicKeyContent string
 }
 
@@ -7,13 +7,25 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"code.gitea.io/gitea/modules/process"
 )
 
-// LoadPublicKeyContent will load the key from gpg
+const (
+	// SigningFormatOpenPGP is the gpg.format of commits signed with a GPG key
+	SigningFormatOpenPGP = "openpgp"
+	// SigningFormatSSH is the gpg.format of commits signed with an SSH key, it requires git >= 2.34
+	SigningFormatSSH = "ssh"
+)
+
+// LoadPublicKeyContent will load the key from gpg, or the SSH public key for the ssh format
 func (gpgSettings *GPGSettings) LoadPublicKeyContent() error {
+	if gpgSettings.Format == SigningFormatSSH {
+		return gpgSettings.loadSSHPublicKeyContent()
+	}
+
 	content, stderr, err := process.GetManager().Exec(
 		"gpg -a --export",
 		"gpg", "-a", "--export", gpgSettings.KeyID)
@@ -24,6 +36,28 @@ func (gpgSettings *GPGSettings) LoadPublicKeyContent() error {
 	return nil
 }
 
+// loadSSHPublicKeyContent loads the SSH public key of the signing key, which like in
+// user.signingkey is either a literal key prefixed with "key::" or the path of a key file
+func (gpgSettings *GPGSettings) loadSSHPublicKeyContent() error {
+	if strings.HasPrefix(gpgSettings.KeyID, "key::") {
+		gpgSettings.PublicKeyContent = strings.TrimPrefix(gpgSettings.KeyID, "key::")
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(gpgSettings.KeyID)
+	if err != nil {
+		return fmt.Errorf("Unable to read SSH signing key: %s, %v", gpgSettings.KeyID, err)
+	}
+	if strings.HasPrefix(string(content), "-----BEGIN") {
+		// This is a private key, its public key is expected next to it
+		if content, err = ioutil.ReadFile(gpgSettings.KeyID + ".pub"); err != nil {
+			return fmt.Errorf("Unable to read SSH signing key: %s.pub, %v", gpgSettings.KeyID, err)
+		}
+	}
+	gpgSettings.PublicKeyContent = strings.TrimSpace(string(content))
+	return nil
+}
+
 // GetDefaultPublicGPGKey will return and cache the default public GPG settings for this repository
 func (repo *Repository) GetDefaultPublicGPGKey(forceUpdate bool) (*GPGSettings, error) {
 	if repo.gpgSettings != nil && !forceUpdate {
@@ -45,6 +79,12 @@ func (repo *Repository) GetDefaultPublicGPGKey(forceUpdate bool) (*GPGSettings,
 	signingKey, _ := NewCommand("config", "--get", "user.signingkey").RunInDir(repo.Path)
 	gpgSettings.KeyID = strings.TrimSpace(signingKey)
 
+	format, _ := NewCommand("config", "--get", "gpg.format").RunInDir(repo.Path)
+	gpgSettings.Format = strings.TrimSpace(format)
+	if gpgSettings.Format == "" {
+		gpgSettings.Format = SigningFormatOpenPGP
+	}
+
 	defaultEmail, _ := NewCommand("config", "--get", "user.email").RunInDir(repo.Path)
 	gpgSettings.Email = strings.TrimSpace(defaultEmail)
 
@@ -591,8 +591,15 @@ func logRequest(r macaron.Request, status int) {
 // or not to proceed. This server assumes an HTTP Basic auth format.
 func authenticate(ctx *context.Context, repository *models.Repository, authorization string, requireWrite bool) bool {
 	accessMode := models.AccessModeRead
+	scopeLevel := models.AccessTokenScopeLevelRead
 	if requireWrite {
 		accessMode = models.AccessModeWrite
+		scopeLevel = models.AccessTokenScopeLevelWrite
+	}
+
+	// an access token used to sign in might be restricted
+	if !ctx.HasTokenScope(models.AccessTokenScopeCategoryRepository, scopeLevel) || !ctx.CanTokenAccessRepo(repository.ID) {
+		return false
 	}
 
 	// ctx.IsSigned is unnecessary here, this will be checked in perm.CanAccess
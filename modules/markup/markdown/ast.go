@@ -6,6 +6,7 @@ package markdown
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/yuin/goldmark/ast"
 )
@@ -144,3 +145,42 @@ func IsIcon(node ast.Node) bool {
 	_, ok := node.(*Icon)
 	return ok
 }
+
+// Suggestion is a block of lines suggested in a code comment to replace the commented lines
+type Suggestion struct {
+	ast.BaseBlock
+	Original  []string
+	Suggested []string
+}
+
+// Dump implements Node.Dump .
+func (n *Suggestion) Dump(source []byte, level int) {
+	m := map[string]string{}
+	m["Original"] = strings.Join(n.Original, "\n")
+	m["Suggested"] = strings.Join(n.Suggested, "\n")
+	ast.DumpHelper(n, source, level, m, nil)
+}
+
+// KindSuggestion is the NodeKind for Suggestion
+var KindSuggestion = ast.NewNodeKind("Suggestion")
+
+// Kind implements Node.Kind.
+func (n *Suggestion) Kind() ast.NodeKind {
+	return KindSuggestion
+}
+
+// NewSuggestion returns a new Suggestion node.
+func NewSuggestion(original, suggested []string) *Suggestion {
+	return &Suggestion{
+		BaseBlock: ast.BaseBlock{},
+		Original:  original,
+		Suggested: suggested,
+	}
+}
+
+// IsSuggestion returns true if the given node implements the Suggestion interface,
+// otherwise false.
+func IsSuggestion(node ast.Node) bool {
+	_, ok := node.(*Suggestion)
+	return ok
+}
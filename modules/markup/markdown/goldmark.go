@@ -59,12 +59,17 @@ func (g *ASTTransformer) Transform(node *ast.Document, reader text.Reader, pc pa
 		toc = make([]Header, 0, 100)
 	}
 
+	var suggestions []*ast.FencedCodeBlock
 	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
 			return ast.WalkContinue, nil
 		}
 
 		switch v := n.(type) {
+		case *ast.FencedCodeBlock:
+			if isSuggestionBlock(v, reader.Source()) {
+				suggestions = append(suggestions, v)
+			}
 		case *ast.Heading:
 			if createTOC {
 				text := n.Text(reader.Source())
@@ -165,6 +170,9 @@ func (g *ASTTransformer) Transform(node *ast.Document, reader text.Reader, pc pa
 		return ast.WalkContinue, nil
 	})
 
+	// Replace the suggestions after the walk as replacing nodes stops the walk through their siblings
+	transformSuggestions(suggestions, reader.Source(), pc)
+
 	if createTOC && len(toc) > 0 {
 		lang := rc.Lang
 		if len(lang) == 0 {
@@ -251,6 +259,7 @@ func (r *HTMLRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
 	reg.Register(KindDetails, r.renderDetails)
 	reg.Register(KindSummary, r.renderSummary)
 	reg.Register(KindIcon, r.renderIcon)
+	reg.Register(KindSuggestion, r.renderSuggestion)
 	reg.Register(KindTaskCheckBoxListItem, r.renderTaskCheckBoxListItem)
 	reg.Register(east.KindTaskCheckBox, r.renderTaskCheckBox)
 }
@@ -310,6 +319,47 @@ func (r *HTMLRenderer) renderSummary(w util.BufWriter, source []byte, node ast.N
 	return ast.WalkContinue, nil
 }
 
+func (r *HTMLRenderer) renderSuggestion(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*Suggestion)
+
+	// render as a highlighted diff, "gd" and "gi" are the chroma classes of deleted and inserted lines
+	_, err := w.WriteString(`<pre><code class="chroma language-diff">`)
+	for _, line := range n.Original {
+		if err == nil {
+			_, err = w.WriteString(`<span class="gd">-`)
+		}
+		if err == nil {
+			_, err = w.Write(util.EscapeHTML([]byte(line)))
+		}
+		if err == nil {
+			_, err = w.WriteString("\n</span>")
+		}
+	}
+	for _, line := range n.Suggested {
+		if err == nil {
+			_, err = w.WriteString(`<span class="gi">+`)
+		}
+		if err == nil {
+			_, err = w.Write(util.EscapeHTML([]byte(line)))
+		}
+		if err == nil {
+			_, err = w.WriteString("\n</span>")
+		}
+	}
+	if err == nil {
+		_, err = w.WriteString("</code></pre>")
+	}
+	if err != nil {
+		return ast.WalkStop, err
+	}
+
+	return ast.WalkContinue, nil
+}
+
 var validNameRE = regexp.MustCompile("^[a-z ]+$")
 
 func (r *HTMLRenderer) renderIcon(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
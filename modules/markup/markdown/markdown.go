@@ -43,8 +43,7 @@ func NewGiteaParseContext(urlPrefix string, metas map[string]string, isWiki bool
 	return pc
 }
 
-// render renders Markdown to HTML without handling special links.
-func render(body []byte, urlPrefix string, metas map[string]string, wikiMarkdown bool) []byte {
+func getConverter() goldmark.Markdown {
 	once.Do(func() {
 		converter = goldmark.New(
 			goldmark.WithExtensions(extension.Table,
@@ -118,10 +117,14 @@ func render(body []byte, urlPrefix string, metas map[string]string, wikiMarkdown
 		)
 
 	})
+	return converter
+}
 
+// render renders Markdown to HTML without handling special links.
+func render(body []byte, urlPrefix string, metas map[string]string, wikiMarkdown bool) []byte {
 	pc := NewGiteaParseContext(urlPrefix, metas, wikiMarkdown)
 	var buf bytes.Buffer
-	if err := converter.Convert(giteautil.NormalizeEOL(body), &buf, parser.WithContext(pc)); err != nil {
+	if err := getConverter().Convert(giteautil.NormalizeEOL(body), &buf, parser.WithContext(pc)); err != nil {
 		log.Error("Unable to render: %v", err)
 	}
 	return markup.SanitizeReader(&buf).Bytes()
@@ -308,3 +308,35 @@ func TestRender_RenderParagraphs(t *testing.T) {
 	test(t, "A\n\nB\nC\n", 2)
 	test(t, "A\n\n\nB\nC\n", 2)
 }
+
+func TestRenderCodeComment_Suggestion(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+
+	input := "Use a constant.\n\n```suggestion\nconst a = 1 & 2\n```\n"
+	expected := `<p>Use a constant.</p>
+<pre><code class="chroma language-diff"><span class="gd">-var a = 1
+</span><span class="gi">+const a = 1 &amp; 2
+</span></code></pre>`
+	res := string(RenderCodeComment([]byte(input), setting.AppSubURL, localMetas, []string{"var a = 1"}))
+	assert.Equal(t, expected, strings.TrimSpace(res))
+	_, has := localMetas["commentedLines"]
+	assert.False(t, has)
+
+	// without commented lines the suggestion is a code block
+	res = string(RenderCodeComment([]byte(input), setting.AppSubURL, localMetas, nil))
+	assert.Contains(t, res, `<code class="chroma language-suggestion">`)
+}
+
+func TestGetSuggestion(t *testing.T) {
+	lines, ok := GetSuggestion([]byte("Better:\r\n```suggestion\r\nfoo()\r\n\r\nbar()\r\n```\r\n```suggestion\nbaz()\n```"))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"foo()", "", "bar()"}, lines)
+
+	lines, ok = GetSuggestion([]byte("Remove this line\n```suggestion\n```"))
+	assert.True(t, ok)
+	assert.Len(t, lines, 0)
+
+	_, ok = GetSuggestion([]byte("```go\nfoo()\n```"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package markdown
+
+import (
+	"strings"
+
+	giteautil "code.gitea.io/gitea/modules/util"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// SuggestionLanguage is the info string of the fenced code blocks suggesting a change in code comments
+const SuggestionLanguage = "suggestion"
+
+// commentedLinesMeta is the render meta holding the lines a code comment refers to
+const commentedLinesMeta = "commentedLines"
+
+// RenderCodeComment renders the content of a code comment. A ```suggestion block is rendered as a diff
+// replacing the commented lines, if there are no commented lines it is rendered as a code block.
+func RenderCodeComment(rawBytes []byte, urlPrefix string, metas map[string]string, commentedLines []string) []byte {
+	if len(commentedLines) == 0 {
+		return Render(rawBytes, urlPrefix, metas)
+	}
+
+	codeMetas := make(map[string]string, len(metas)+1)
+	for k, v := range metas {
+		codeMetas[k] = v
+	}
+	codeMetas[commentedLinesMeta] = strings.Join(commentedLines, "\n")
+	return Render(rawBytes, urlPrefix, codeMetas)
+}
+
+// GetSuggestion returns the lines of the first ```suggestion block of the content
+func GetSuggestion(content []byte) ([]string, bool) {
+	content = giteautil.NormalizeEOL(content)
+	pc := NewGiteaParseContext("", map[string]string{}, false)
+	doc := getConverter().Parser().Parse(text.NewReader(content), parser.WithContext(pc))
+
+	var suggested []string
+	found := false
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || found {
+			return ast.WalkContinue, nil
+		}
+		if block, ok := n.(*ast.FencedCodeBlock); ok && isSuggestionBlock(block, content) {
+			suggested = blockLines(block, content)
+			found = true
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return suggested, found
+}
+
+func isSuggestionBlock(block *ast.FencedCodeBlock, source []byte) bool {
+	return string(block.Language(source)) == SuggestionLanguage
+}
+
+// blockLines returns the lines of a code block without their line endings
+func blockLines(block ast.Node, source []byte) []string {
+	lines := make([]string, 0, block.Lines().Len())
+	for i := 0; i < block.Lines().Len(); i++ {
+		segment := block.Lines().At(i)
+		lines = append(lines, strings.TrimRight(string(segment.Value(source)), "\n"))
+	}
+	return lines
+}
+
+// transformSuggestions replaces the ```suggestion blocks by Suggestion nodes if the commented lines are known
+func transformSuggestions(blocks []*ast.FencedCodeBlock, source []byte, pc parser.Context) {
+	if len(blocks) == 0 {
+		return
+	}
+	renderMetas := pc.Get(renderMetasKey).(map[string]string)
+	commented, ok := renderMetas[commentedLinesMeta]
+	if !ok {
+		return
+	}
+
+	original := strings.Split(commented, "\n")
+	for _, block := range blocks {
+		parent := block.Parent()
+		if parent == nil {
+			continue
+		}
+		parent.ReplaceChild(parent, block, NewSuggestion(original, blockLines(block, source)))
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RateLimitRequests counts the requests checked by the rate limiter by budget and result,
+// which is one of allowed, limited or exempt
+var RateLimitRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: namespace + "ratelimit_requests_total",
+		Help: "Number of requests checked by the rate limiter",
+	},
+	[]string{"budget", "result"},
+)
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package base
+
+// MigrateStage represents a stage of a migration
+type MigrateStage string
+
+// enumerate all the stages of a migration in the order they are migrated
+const (
+	MigrateStageRepo         MigrateStage = "repo"
+	MigrateStageTopics       MigrateStage = "topics"
+	MigrateStageMilestones   MigrateStage = "milestones"
+	MigrateStageLabels       MigrateStage = "labels"
+	MigrateStageReleases     MigrateStage = "releases"
+	MigrateStageIssues       MigrateStage = "issues"
+	MigrateStagePullRequests MigrateStage = "pull_requests"
+)
+
+var migrateStages = []MigrateStage{
+	MigrateStageRepo,
+	MigrateStageTopics,
+	MigrateStageMilestones,
+	MigrateStageLabels,
+	MigrateStageReleases,
+	MigrateStageIssues,
+	MigrateStagePullRequests,
+}
+
+func (stage MigrateStage) index() int {
+	for i, s := range migrateStages {
+		if s == stage {
+			return i
+		}
+	}
+	return 0
+}
+
+// MigrateProgress represents the progress of a migration at its last checkpoint, a failed
+// migration can be resumed from it
+type MigrateProgress struct {
+	// Stage is the stage which is being migrated and Page the next page of issues or pull requests
+	Stage MigrateStage `json:"stage"`
+	Page  int          `json:"page,omitempty"`
+
+	Milestones   int `json:"milestones"`
+	Labels       int `json:"labels"`
+	Releases     int `json:"releases"`
+	Issues       int `json:"issues"`
+	Comments     int `json:"comments"`
+	PullRequests int `json:"pull_requests"`
+	Reviews      int `json:"reviews"`
+
+	// Checkpoint is the state of the uploaded data which a ResumableUploader reverts to
+	Checkpoint map[string]int64 `json:"checkpoint,omitempty"`
+}
+
+// IsDone returns whether the stage has been completed
+func (p *MigrateProgress) IsDone(stage MigrateStage) bool {
+	return p.Stage.index() > stage.index()
+}
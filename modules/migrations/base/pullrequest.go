@@ -12,29 +12,29 @@ import (
 
 // PullRequest defines a standard pull request information
 type PullRequest struct {
-	Number         int64
-	OriginalNumber int64
-	Title          string
-	PosterName     string
-	PosterID       int64
-	PosterEmail    string
-	Content        string
-	Milestone      string
-	State          string
-	Created        time.Time
-	Updated        time.Time
-	Closed         *time.Time
-	Labels         []*Label
-	PatchURL       string
-	Merged         bool
-	MergedTime     *time.Time
-	MergeCommitSHA string
-	Head           PullRequestBranch
-	Base           PullRequestBranch
-	Assignee       string
-	Assignees      []string
-	IsLocked       bool
-	Reactions      []*Reaction
+	Number         int64             `yaml:"number"`
+	OriginalNumber int64             `yaml:"original_number"`
+	Title          string            `yaml:"title"`
+	PosterName     string            `yaml:"poster_name"`
+	PosterID       int64             `yaml:"poster_id"`
+	PosterEmail    string            `yaml:"poster_email"`
+	Content        string            `yaml:"content"`
+	Milestone      string            `yaml:"milestone"`
+	State          string            `yaml:"state"`
+	Created        time.Time         `yaml:"created"`
+	Updated        time.Time         `yaml:"updated"`
+	Closed         *time.Time        `yaml:"closed"`
+	Labels         []*Label          `yaml:"labels"`
+	PatchURL       string            `yaml:"patch_url"`
+	Merged         bool              `yaml:"merged"`
+	MergedTime     *time.Time        `yaml:"merged_time"`
+	MergeCommitSHA string            `yaml:"merge_commit_sha"`
+	Head           PullRequestBranch `yaml:"head"`
+	Base           PullRequestBranch `yaml:"base"`
+	Assignee       string            `yaml:"assignee"`
+	Assignees      []string          `yaml:"assignees"`
+	IsLocked       bool              `yaml:"is_locked"`
+	Reactions      []*Reaction       `yaml:"reactions"`
 }
 
 // IsForkPullRequest returns true if the pull request from a forked repository but not the same repository
@@ -44,11 +44,11 @@ func (p *PullRequest) IsForkPullRequest() bool {
 
 // PullRequestBranch represents a pull request branch
 type PullRequestBranch struct {
-	CloneURL  string
-	Ref       string
-	SHA       string
-	RepoName  string
-	OwnerName string
+	CloneURL  string `yaml:"clone_url"`
+	Ref       string `yaml:"ref"`
+	SHA       string `yaml:"sha"`
+	RepoName  string `yaml:"repo_name"`
+	OwnerName string `yaml:"owner_name"`
 }
 
 // RepoPath returns pull request repo path
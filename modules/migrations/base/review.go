@@ -16,29 +16,29 @@ const (
 
 // Review is a standard review information
 type Review struct {
-	ID           int64
-	IssueIndex   int64
-	ReviewerID   int64
-	ReviewerName string
-	Official     bool
-	CommitID     string
-	Content      string
-	CreatedAt    time.Time
-	State        string // PENDING, APPROVED, REQUEST_CHANGES, or COMMENT
-	Comments     []*ReviewComment
+	ID           int64            `yaml:"id"`
+	IssueIndex   int64            `yaml:"issue_index"`
+	ReviewerID   int64            `yaml:"reviewer_id"`
+	ReviewerName string           `yaml:"reviewer_name"`
+	Official     bool             `yaml:"official"`
+	CommitID     string           `yaml:"commit_id"`
+	Content      string           `yaml:"content"`
+	CreatedAt    time.Time        `yaml:"created_at"`
+	State        string           `yaml:"state"` // PENDING, APPROVED, REQUEST_CHANGES, or COMMENT
+	Comments     []*ReviewComment `yaml:"comments"`
 }
 
 // ReviewComment represents a review comment
 type ReviewComment struct {
-	ID        int64
-	InReplyTo int64
-	Content   string
-	TreePath  string
-	DiffHunk  string
-	Position  int
-	CommitID  string
-	PosterID  int64
-	Reactions []*Reaction
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID        int64       `yaml:"id"`
+	InReplyTo int64       `yaml:"in_reply_to"`
+	Content   string      `yaml:"content"`
+	TreePath  string      `yaml:"tree_path"`
+	DiffHunk  string      `yaml:"diff_hunk"`
+	Position  int         `yaml:"position"`
+	CommitID  string      `yaml:"commit_id"`
+	PosterID  int64       `yaml:"poster_id"`
+	Reactions []*Reaction `yaml:"reactions"`
+	CreatedAt time.Time   `yaml:"created_at"`
+	UpdatedAt time.Time   `yaml:"updated_at"`
 }
@@ -21,3 +21,13 @@ type Uploader interface {
 	Rollback() error
 	Close()
 }
+
+// ResumableUploader is an Uploader which is able to resume an interrupted migration
+type ResumableUploader interface {
+	Uploader
+	// Checkpoint returns the state of the uploaded data
+	Checkpoint() (map[string]int64, error)
+	// ResumeRepo continues uploading to the repository of an interrupted migration, the data
+	// uploaded after the checkpoint is removed
+	ResumeRepo(repo *Repository, opts MigrateOptions, checkpoint map[string]int64) error
+}
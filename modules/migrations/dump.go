@@ -0,0 +1,329 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	_ base.Uploader = &RepositoryDumper{}
+)
+
+// dumpedRepository is the content of the repo.yml of a dumped repository
+type dumpedRepository struct {
+	base.Repository `yaml:",inline"`
+	ServiceType     string `yaml:"service_type"`
+	Wiki            bool   `yaml:"wiki"`
+	Milestones      bool   `yaml:"milestones"`
+	Labels          bool   `yaml:"labels"`
+	Releases        bool   `yaml:"releases"`
+	Issues          bool   `yaml:"issues"`
+	Comments        bool   `yaml:"comments"`
+	PullRequests    bool   `yaml:"pull_requests"`
+}
+
+// RepositoryDumper implements an Uploader which writes a repository with all its information
+// to a directory:
+// - repo.yml describes the repository and the kinds of information which have been dumped
+// - repo.git and repo.wiki.git are bare mirrors of the repository and its wiki
+// - topic.yml, milestone.yml, label.yml, release.yml, issue.yml and pull_request.yml list these objects
+// - release_assets/<tag>/<asset id> are the files of the release assets
+// - comments/<issue number>.yml and reviews/<pull request number>.yml list the comments and reviews
+type RepositoryDumper struct {
+	ctx     context.Context
+	baseDir string
+	opts    base.MigrateOptions
+	gitRepo *git.Repository
+}
+
+// NewRepositoryDumper creates a dumper which writes into baseDir, which must not exist or be empty
+func NewRepositoryDumper(ctx context.Context, baseDir string, opts base.MigrateOptions) (*RepositoryDumper, error) {
+	files, err := ioutil.ReadDir(baseDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	} else if len(files) > 0 {
+		return nil, fmt.Errorf("directory %s is not empty", baseDir)
+	}
+	if err := os.MkdirAll(baseDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &RepositoryDumper{
+		ctx:     ctx,
+		baseDir: baseDir,
+		opts:    opts,
+	}, nil
+}
+
+func (g *RepositoryDumper) gitPath() string {
+	return filepath.Join(g.baseDir, "repo.git")
+}
+
+func (g *RepositoryDumper) wikiPath() string {
+	return filepath.Join(g.baseDir, "repo.wiki.git")
+}
+
+// MaxBatchInsertSize returns the table's max batch insert size
+func (g *RepositoryDumper) MaxBatchInsertSize(tp string) int {
+	return 1000
+}
+
+// writeYAML appends the list of objects to the YAML list in the file
+func (g *RepositoryDumper) writeYAML(filename string, objs interface{}) error {
+	// an empty list would be written as [] which can't be continued
+	if reflect.ValueOf(objs).Len() == 0 {
+		return nil
+	}
+
+	p := filepath.Join(g.baseDir, filename)
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	bs, err := yaml.Marshal(objs)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(bs); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// CreateRepo writes repo.yml and mirrors the git data of the repository
+func (g *RepositoryDumper) CreateRepo(repo *base.Repository, opts base.MigrateOptions) error {
+	bs, err := yaml.Marshal(&dumpedRepository{
+		Repository:   *repo,
+		ServiceType:  opts.GitServiceType.Name(),
+		Wiki:         opts.Wiki,
+		Milestones:   opts.Milestones,
+		Labels:       opts.Labels,
+		Releases:     opts.Releases,
+		Issues:       opts.Issues,
+		Comments:     opts.Comments,
+		PullRequests: opts.PullRequests,
+	})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(g.baseDir, "repo.yml"), bs, 0644); err != nil {
+		return err
+	}
+
+	var remoteAddr = repo.CloneURL
+	if len(opts.AuthToken) > 0 || len(opts.AuthUsername) > 0 {
+		u, err := url.Parse(repo.CloneURL)
+		if err != nil {
+			return err
+		}
+		u.User = url.UserPassword(opts.AuthUsername, opts.AuthPassword)
+		if len(opts.AuthToken) > 0 {
+			if opts.GitServiceType == structs.GogsService {
+				u.User = url.User(opts.AuthToken)
+			} else {
+				u.User = url.UserPassword("oauth2", opts.AuthToken)
+			}
+		}
+		remoteAddr = u.String()
+	}
+
+	timeout := time.Duration(setting.Git.Timeout.Migrate) * time.Second
+	if err := git.Clone(remoteAddr, g.gitPath(), git.CloneRepoOptions{
+		Mirror:  true,
+		Quiet:   true,
+		Timeout: timeout,
+	}); err != nil {
+		return fmt.Errorf("Clone: %v", err)
+	}
+	// the remote address may contain credentials which must not end up in the dump
+	if _, err := git.NewCommand("remote", "set-url", "origin", repo.CloneURL).RunInDir(g.gitPath()); err != nil {
+		return err
+	}
+
+	if opts.Wiki {
+		if wikiRemoteAddr := repository.WikiRemoteURL(remoteAddr); wikiRemoteAddr != "" {
+			if err := git.Clone(wikiRemoteAddr, g.wikiPath(), git.CloneRepoOptions{
+				Mirror:  true,
+				Quiet:   true,
+				Timeout: timeout,
+			}); err != nil {
+				log.Warn("Clone wiki: %v", err)
+				if err := os.RemoveAll(g.wikiPath()); err != nil {
+					return fmt.Errorf("Failed to remove %s: %v", g.wikiPath(), err)
+				}
+			} else if _, err := git.NewCommand("remote", "set-url", "origin", util.SanitizeURLCredentials(wikiRemoteAddr, false)).RunInDir(g.wikiPath()); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.gitRepo, err = git.OpenRepository(g.gitPath())
+	return err
+}
+
+// Close closes this uploader
+func (g *RepositoryDumper) Close() {
+	if g.gitRepo != nil {
+		g.gitRepo.Close()
+	}
+}
+
+// CreateTopics writes topic.yml
+func (g *RepositoryDumper) CreateTopics(topics ...string) error {
+	return g.writeYAML("topic.yml", topics)
+}
+
+// CreateMilestones writes milestone.yml
+func (g *RepositoryDumper) CreateMilestones(milestones ...*base.Milestone) error {
+	return g.writeYAML("milestone.yml", milestones)
+}
+
+// CreateLabels writes label.yml
+func (g *RepositoryDumper) CreateLabels(labels ...*base.Label) error {
+	return g.writeYAML("label.yml", labels)
+}
+
+// CreateReleases writes release.yml and downloads the release assets
+func (g *RepositoryDumper) CreateReleases(downloader base.Downloader, releases ...*base.Release) error {
+	for _, release := range releases {
+		for _, asset := range release.Assets {
+			assetDir := filepath.Join(g.baseDir, "release_assets", release.TagName)
+			if err := os.MkdirAll(assetDir, os.ModePerm); err != nil {
+				return err
+			}
+
+			err := func() error {
+				rc, err := downloader.GetAsset(release.TagName, asset.ID)
+				if err != nil {
+					return err
+				}
+				defer rc.Close()
+
+				f, err := os.Create(filepath.Join(assetDir, strconv.FormatInt(asset.ID, 10)))
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				_, err = io.Copy(f, rc)
+				return err
+			}()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return g.writeYAML("release.yml", releases)
+}
+
+// SyncTags does nothing as the tags are part of the git data
+func (g *RepositoryDumper) SyncTags() error {
+	return nil
+}
+
+// CreateIssues writes issue.yml
+func (g *RepositoryDumper) CreateIssues(issues ...*base.Issue) error {
+	return g.writeYAML("issue.yml", issues)
+}
+
+// CreateComments writes the comments to the files of their issues
+func (g *RepositoryDumper) CreateComments(comments ...*base.Comment) error {
+	var commentsMap = make(map[int64][]*base.Comment, len(comments))
+	for _, comment := range comments {
+		commentsMap[comment.IssueIndex] = append(commentsMap[comment.IssueIndex], comment)
+	}
+
+	for issueIndex, cs := range commentsMap {
+		if err := g.writeYAML(filepath.Join("comments", fmt.Sprintf("%d.yml", issueIndex)), cs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePullRequests writes pull_request.yml and keeps the heads of the pull requests in the git data
+func (g *RepositoryDumper) CreatePullRequests(prs ...*base.PullRequest) error {
+	for _, pr := range prs {
+		if pr.Head.SHA == "" {
+			continue
+		}
+
+		// the commits of open pull requests from forks have to be fetched from the forks
+		if pr.IsForkPullRequest() && pr.State != "closed" && pr.Head.CloneURL != "" {
+			if _, err := git.NewCommand("fetch", pr.Head.CloneURL, pr.Head.Ref).RunInDir(g.gitPath()); err != nil {
+				log.Error("Fetch branch from %s failed: %v", pr.Head.CloneURL, err)
+			}
+		}
+
+		if _, err := git.NewCommand("update-ref", fmt.Sprintf("refs/pull/%d/head", pr.Number), pr.Head.SHA).RunInDir(g.gitPath()); err != nil {
+			log.Error("Unable to keep the head of pull request %d: %v", pr.Number, err)
+		}
+	}
+	return g.writeYAML("pull_request.yml", prs)
+}
+
+// CreateReviews writes the reviews to the files of their pull requests
+func (g *RepositoryDumper) CreateReviews(reviews ...*base.Review) error {
+	var reviewsMap = make(map[int64][]*base.Review, len(reviews))
+	for _, review := range reviews {
+		reviewsMap[review.IssueIndex] = append(reviewsMap[review.IssueIndex], review)
+	}
+
+	for issueIndex, rs := range reviewsMap {
+		if err := g.writeYAML(filepath.Join("reviews", fmt.Sprintf("%d.yml", issueIndex)), rs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback removes the incomplete dump
+func (g *RepositoryDumper) Rollback() error {
+	g.Close()
+	return os.RemoveAll(g.baseDir)
+}
+
+// DumpRepository dumps a repository of a git service into baseDir according MigrateOptions
+func DumpRepository(ctx context.Context, baseDir, ownerName string, opts base.MigrateOptions) error {
+	downloader, err := newDownloader(ctx, ownerName, &opts)
+	if err != nil {
+		return err
+	}
+	uploader, err := NewRepositoryDumper(ctx, baseDir, opts)
+	if err != nil {
+		return err
+	}
+
+	if err := migrateRepository(downloader, uploader, opts, nil, nil); err != nil {
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
+		return err
+	}
+	return nil
+}
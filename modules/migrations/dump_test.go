@@ -0,0 +1,165 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// localGogsDownloader serves the recorded Gogs data together with a local git repository
+type localGogsDownloader struct {
+	*GogsDownloader
+	cloneURL string
+}
+
+func (d *localGogsDownloader) GetRepoInfo() (*base.Repository, error) {
+	repo, err := d.GogsDownloader.GetRepoInfo()
+	if err != nil {
+		return nil, err
+	}
+	repo.CloneURL = d.cloneURL
+	return repo, nil
+}
+
+func TestDumpRestoreRepository(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	server := newRecordedServer(t, "gogs")
+	defer server.Close()
+
+	tmpDir, err := ioutil.TempDir("", "gitea-dump-repo")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	baseDir := filepath.Join(tmpDir, "dump")
+
+	opts := base.MigrateOptions{
+		GitServiceType: structs.GogsService,
+		RepoName:       "test_repo",
+		Milestones:     true,
+		Labels:         true,
+		Releases:       true,
+		Issues:         true,
+		Comments:       true,
+		PullRequests:   true,
+	}
+	downloader := &localGogsDownloader{
+		GogsDownloader: NewGogsDownloader(context.Background(), server.URL, "", "", "token", "gogs-user", "test_repo"),
+		cloneURL:       models.RepoPath("user2", "repo1"),
+	}
+	dumper, err := NewRepositoryDumper(context.Background(), baseDir, opts)
+	assert.NoError(t, err)
+	assert.NoError(t, migrateRepository(downloader, dumper, opts, nil, nil))
+
+	// a dump never overwrites existing data
+	_, err = NewRepositoryDumper(context.Background(), baseDir, opts)
+	assert.Error(t, err)
+
+	for _, name := range []string{"repo.yml", "repo.git", "milestone.yml", "label.yml", "release.yml", "issue.yml", "comments/1.yml"} {
+		_, err := os.Stat(filepath.Join(baseDir, name))
+		assert.NoError(t, err, name)
+	}
+
+	// the data of the dump is compared to the one of a fresh downloader
+	expected := NewGogsDownloader(context.Background(), server.URL, "", "", "token", "gogs-user", "test_repo")
+
+	restorer, err := NewRepositoryRestorer(context.Background(), baseDir, "user2", "restored")
+	assert.NoError(t, err)
+
+	restoreOpts, err := restorer.MigrateOptions()
+	assert.NoError(t, err)
+	assert.EqualValues(t, structs.GogsService, restoreOpts.GitServiceType)
+	assert.EqualValues(t, "restored", restoreOpts.RepoName)
+	assert.True(t, restoreOpts.Issues)
+	assert.False(t, restoreOpts.Wiki)
+
+	repo, err := restorer.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "Test repository for migrating from Gogs", repo.Description)
+	assert.EqualValues(t, filepath.Join(baseDir, "repo.git"), repo.CloneURL)
+
+	expectedMilestones, err := expected.GetMilestones()
+	assert.NoError(t, err)
+	milestones, err := restorer.GetMilestones()
+	assert.NoError(t, err)
+	assert.Len(t, milestones, len(expectedMilestones))
+	for i := range milestones {
+		assert.EqualValues(t, expectedMilestones[i].Title, milestones[i].Title)
+		assert.EqualValues(t, expectedMilestones[i].State, milestones[i].State)
+	}
+
+	expectedLabels, err := expected.GetLabels()
+	assert.NoError(t, err)
+	labels, err := restorer.GetLabels()
+	assert.NoError(t, err)
+	assert.EqualValues(t, expectedLabels, labels)
+
+	var expectedIssues []*base.Issue
+	for page := 1; ; page++ {
+		pageIssues, isEnd, err := expected.GetIssues(page, 50)
+		assert.NoError(t, err)
+		expectedIssues = append(expectedIssues, pageIssues...)
+		if isEnd {
+			break
+		}
+	}
+	assert.Len(t, expectedIssues, 2)
+	issues, isEnd, err := restorer.GetIssues(1, 1)
+	assert.NoError(t, err)
+	assert.False(t, isEnd)
+	assert.Len(t, issues, 1)
+	assert.EqualValues(t, expectedIssues[0].Number, issues[0].Number)
+	assert.EqualValues(t, expectedIssues[0].Title, issues[0].Title)
+	assert.EqualValues(t, expectedIssues[0].Labels, issues[0].Labels)
+	assert.True(t, expectedIssues[0].Created.Equal(issues[0].Created))
+	issues, isEnd, err = restorer.GetIssues(2, 1)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	assert.Len(t, issues, 1)
+	assert.EqualValues(t, expectedIssues[1].Number, issues[0].Number)
+
+	expectedComments, err := expected.GetComments(1)
+	assert.NoError(t, err)
+	comments, err := restorer.GetComments(1)
+	assert.NoError(t, err)
+	assert.Len(t, comments, len(expectedComments))
+	for i := range comments {
+		assert.EqualValues(t, expectedComments[i].Content, comments[i].Content)
+		assert.EqualValues(t, expectedComments[i].PosterName, comments[i].PosterName)
+	}
+
+	// issues without comments have no file
+	comments, err = restorer.GetComments(2)
+	assert.NoError(t, err)
+	assert.Empty(t, comments)
+
+	// the recorded release refers to a tag which the local repository lacks
+	_, err = git.NewCommand("tag", "v1.0", "master").RunInDir(filepath.Join(baseDir, "repo.git"))
+	assert.NoError(t, err)
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	restored, err := RestoreRepository(context.Background(), user, baseDir, "user2", "restored")
+	assert.NoError(t, err)
+	assert.EqualValues(t, models.RepositoryReady, restored.Status)
+	assert.EqualValues(t, structs.GogsService, restored.OriginalServiceType)
+
+	restoredIssues, err := models.Issues(&models.IssuesOptions{RepoIDs: []int64{restored.ID}})
+	assert.NoError(t, err)
+	assert.Len(t, restoredIssues, 2)
+
+	releases, err := models.GetReleasesByRepoID(restored.ID, models.FindReleasesOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, releases, 1)
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+var (
+	_ base.Downloader        = &GitBucketDownloader{}
+	_ base.DownloaderFactory = &GitBucketDownloaderFactory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&GitBucketDownloaderFactory{})
+}
+
+// GitBucketDownloaderFactory defines a GitBucket downloader factory
+type GitBucketDownloaderFactory struct {
+}
+
+// New returns a Downloader related to this factory according MigrateOptions
+func (f *GitBucketDownloaderFactory) New(ctx context.Context, opts base.MigrateOptions) (base.Downloader, error) {
+	u, err := url.Parse(opts.CloneAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// GitBucket may be served below a context path and serves its clone URLs below /git
+	fields := strings.Split(strings.TrimSuffix(u.Path, "/"), "/")
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid path: %s", u.Path)
+	}
+	baseURL := u.Scheme + "://" + u.Host + strings.TrimSuffix(strings.Join(fields[:len(fields)-2], "/"), "/git")
+	oldOwner := fields[len(fields)-2]
+	oldName := strings.TrimSuffix(fields[len(fields)-1], ".git")
+
+	log.Trace("Create GitBucket downloader. BaseURL: %s RepoName: %s/%s", baseURL, oldOwner, oldName)
+
+	return NewGitBucketDownloader(ctx, baseURL, opts.AuthUsername, opts.AuthPassword, opts.AuthToken, oldOwner, oldName), nil
+}
+
+// GitServiceType returns the type of git service
+func (f *GitBucketDownloaderFactory) GitServiceType() structs.GitServiceType {
+	return structs.GitBucketService
+}
+
+// GitBucketDownloader implements a Downloader interface to get repository informations
+// from GitBucket via its GitHub compatible APIv3. GitBucket has no reactions and reviews
+// and doesn't identify release assets, so these are not migrated.
+type GitBucketDownloader struct {
+	*GithubDownloaderV3
+}
+
+// NewGitBucketDownloader creates a GitBucket downloader
+func NewGitBucketDownloader(ctx context.Context, baseURL, userName, password, token, repoOwner, repoName string) *GitBucketDownloader {
+	githubDownloader := NewGithubDownloaderV3(ctx, baseURL, userName, password, token, repoOwner, repoName)
+	githubDownloader.SkipReactions = true
+	return &GitBucketDownloader{
+		githubDownloader,
+	}
+}
+
+// GetReleases returns releases without their assets
+func (g *GitBucketDownloader) GetReleases() ([]*base.Release, error) {
+	releases, err := g.GithubDownloaderV3.GetReleases()
+	if err != nil {
+		return nil, err
+	}
+	for _, release := range releases {
+		release.Assets = nil
+	}
+	return releases, nil
+}
+
+// GetAsset is not supported
+func (g *GitBucketDownloader) GetAsset(_ string, _ int64) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+// GetReviews returns no reviews as GitBucket has no pull request reviews
+func (g *GitBucketDownloader) GetReviews(_ int64) ([]*base.Review, error) {
+	return []*base.Review{}, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitBucketDownloaderFactory(t *testing.T) {
+	downloader, err := (&GitBucketDownloaderFactory{}).New(context.Background(), base.MigrateOptions{
+		CloneAddr: "https://example.com/gitbucket/git/gitbucket-user/test_repo.git",
+	})
+	assert.NoError(t, err)
+	gitbucketDownloader := downloader.(*GitBucketDownloader)
+	assert.EqualValues(t, "https://example.com/gitbucket/api/v3/", gitbucketDownloader.client.BaseURL.String())
+	assert.EqualValues(t, "gitbucket-user", gitbucketDownloader.repoOwner)
+	assert.EqualValues(t, "test_repo", gitbucketDownloader.repoName)
+}
+
+func TestGitBucketDownloadRepo(t *testing.T) {
+	server := newRecordedServer(t, "gitbucket")
+	defer server.Close()
+
+	downloader := NewGitBucketDownloader(context.Background(), server.URL, "", "", "token", "gitbucket-user", "test_repo")
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, &base.Repository{
+		Name:          "test_repo",
+		Owner:         "gitbucket-user",
+		Description:   "Test repository for migrating from GitBucket",
+		CloneURL:      "https://gitbucket.example.com/git/gitbucket-user/test_repo.git",
+		OriginalURL:   "https://gitbucket.example.com/gitbucket-user/test_repo",
+		DefaultBranch: "master",
+	}, repo)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 1)
+	assertMilestoneEqual(t, "First release", "v1.0", "2020-09-30 00:00:00 +0000 UTC", "", "", "", "open", milestones[0])
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.Len(t, labels, 1)
+	assertLabelEqual(t, "bug", "fc2929", "", labels[0])
+
+	releases, err := downloader.GetReleases()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Release{
+		{
+			TagName:        "v1.0",
+			Name:           "First release",
+			Body:           "The first release",
+			PublisherName:  "gitbucket-user",
+			PublisherEmail: "gitbucket-user@example.com",
+		},
+	}, releases)
+
+	// pull requests are listed by the issues API but skipped
+	issues, isEnd, err := downloader.GetIssues(1, 50)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	assert.EqualValues(t, []*base.Issue{
+		{
+			Number:      1,
+			Title:       "Something is broken",
+			Content:     "It doesn't work.",
+			Milestone:   "v1.0",
+			PosterName:  "contributor",
+			PosterEmail: "contributor@example.com",
+			State:       "closed",
+			Created:     time.Date(2020, 9, 2, 10, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 4, 11, 0, 0, 0, time.UTC),
+			Labels: []*base.Label{
+				{Name: "bug", Color: "fc2929"},
+			},
+		},
+	}, issues)
+
+	comments, err := downloader.GetComments(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Comment{
+		{
+			IssueIndex:  1,
+			PosterName:  "gitbucket-user",
+			PosterEmail: "gitbucket-user@example.com",
+			Content:     "Thanks for the report.",
+			Created:     time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+		},
+	}, comments)
+
+	prs, err := downloader.GetPullRequests(1, 50)
+	assert.NoError(t, err)
+	merged := time.Date(2020, 9, 4, 10, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, []*base.PullRequest{
+		{
+			Number:      2,
+			Title:       "Fix the bug",
+			Content:     "Fixes #1",
+			PosterName:  "contributor",
+			PosterEmail: "contributor@example.com",
+			State:       "closed",
+			Created:     time.Date(2020, 9, 3, 10, 0, 0, 0, time.UTC),
+			Updated:     merged,
+			Labels:      []*base.Label{},
+			Merged:      true,
+			MergedTime:  &merged,
+			Head: base.PullRequestBranch{
+				Ref:       "fix",
+				SHA:       "1234567890abcdef1234567890abcdef12345678",
+				RepoName:  "test_repo",
+				OwnerName: "gitbucket-user",
+				CloneURL:  "https://gitbucket.example.com/git/gitbucket-user/test_repo.git",
+			},
+			Base: base.PullRequestBranch{
+				Ref:       "master",
+				SHA:       "abcdef1234567890abcdef1234567890abcdef12",
+				RepoName:  "test_repo",
+				OwnerName: "gitbucket-user",
+			},
+		},
+	}, prs)
+
+	reviews, err := downloader.GetReviews(2)
+	assert.NoError(t, err)
+	assert.Empty(t, reviews)
+}
@@ -33,7 +33,8 @@ import (
 )
 
 var (
-	_ base.Uploader = &GiteaLocalUploader{}
+	_ base.Uploader          = &GiteaLocalUploader{}
+	_ base.ResumableUploader = &GiteaLocalUploader{}
 )
 
 // GiteaLocalUploader implements an Uploader to gitea sites
@@ -100,7 +101,12 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 		}
 		u.User = url.UserPassword(opts.AuthUsername, opts.AuthPassword)
 		if len(opts.AuthToken) > 0 {
-			u.User = url.UserPassword("oauth2", opts.AuthToken)
+			if opts.GitServiceType == structs.GogsService {
+				// Gogs only accepts access tokens as user name
+				u.User = url.User(opts.AuthToken)
+			} else {
+				u.User = url.UserPassword("oauth2", opts.AuthToken)
+			}
 		}
 		remoteAddr = u.String()
 	}
@@ -144,6 +150,54 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 	return err
 }
 
+// ResumeRepo continues the migration into the repository created by an interrupted migration
+func (g *GiteaLocalUploader) ResumeRepo(repo *base.Repository, opts base.MigrateOptions, checkpoint map[string]int64) error {
+	var (
+		r   *models.Repository
+		err error
+	)
+	if opts.MigrateToRepoID > 0 {
+		r, err = models.GetRepositoryByID(opts.MigrateToRepoID)
+	} else {
+		r, err = models.GetRepositoryByOwnerAndName(g.repoOwner, g.repoName)
+	}
+	if err != nil {
+		return err
+	}
+	g.repo = r
+
+	if err := models.DeleteMigratedDataAfterCheckpoint(r.ID, checkpoint); err != nil {
+		return err
+	}
+
+	// the labels and milestones of the issues are looked up by name
+	labels, err := models.GetLabelsByRepoID(r.ID, "", models.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, lb := range labels {
+		g.labels.Store(lb.Name, lb)
+	}
+	milestones, err := models.GetMilestones(models.GetMilestonesOption{
+		RepoID: r.ID,
+		State:  structs.StateAll,
+	})
+	if err != nil {
+		return err
+	}
+	for _, ms := range milestones {
+		g.milestones.Store(ms.Name, ms.ID)
+	}
+
+	g.gitRepo, err = git.OpenRepository(r.RepoPath())
+	return err
+}
+
+// Checkpoint returns the greatest IDs of the migrated data
+func (g *GiteaLocalUploader) Checkpoint() (map[string]int64, error) {
+	return models.GetMigrationCheckpoint(g.repo.ID)
+}
+
 // Close closes this uploader
 func (g *GiteaLocalUploader) Close() {
 	if g.gitRepo != nil {
@@ -536,8 +590,11 @@ func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullR
 		}
 	}
 
-	// download patch file
+	// download patch file, not every source provides one
 	err := func() error {
+		if pr.PatchURL == "" {
+			return nil
+		}
 		resp, err := http.Get(pr.PatchURL)
 		if err != nil {
 			return err
@@ -47,13 +47,14 @@ func (f *GithubDownloaderV3Factory) New(ctx context.Context, opts base.MigrateOp
 		return nil, err
 	}
 
+	baseURL := u.Scheme + "://" + u.Host
 	fields := strings.Split(u.Path, "/")
 	oldOwner := fields[1]
 	oldName := strings.TrimSuffix(fields[2], ".git")
 
 	log.Trace("Create github downloader: %s/%s", oldOwner, oldName)
 
-	return NewGithubDownloaderV3(ctx, opts.AuthUsername, opts.AuthPassword, opts.AuthToken, oldOwner, oldName), nil
+	return NewGithubDownloaderV3(ctx, baseURL, opts.AuthUsername, opts.AuthPassword, opts.AuthToken, oldOwner, oldName), nil
 }
 
 // GitServiceType returns the type of git service
@@ -64,17 +65,29 @@ func (f *GithubDownloaderV3Factory) GitServiceType() structs.GitServiceType {
 // GithubDownloaderV3 implements a Downloader interface to get repository informations
 // from github via APIv3
 type GithubDownloaderV3 struct {
-	ctx       context.Context
-	client    *github.Client
-	repoOwner string
-	repoName  string
-	userName  string
-	password  string
-	rate      *github.Rate
+	ctx           context.Context
+	client        *github.Client
+	repoOwner     string
+	repoName      string
+	userName      string
+	password      string
+	rate          *github.Rate
+	SkipReactions bool
 }
 
-// NewGithubDownloaderV3 creates a github Downloader via github v3 API
-func NewGithubDownloaderV3(ctx context.Context, userName, password, token, repoOwner, repoName string) *GithubDownloaderV3 {
+// isGithubCom returns whether the base URL points to github.com rather than to a GitHub
+// Enterprise server, regardless of the scheme, the case of the host and a leading www.
+func isGithubCom(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") == "github.com"
+}
+
+// NewGithubDownloaderV3 creates a github Downloader via github v3 API. Servers other than
+// github.com are expected to serve the API below /api/v3 like GitHub Enterprise.
+func NewGithubDownloaderV3(ctx context.Context, baseURL, userName, password, token, repoOwner, repoName string) *GithubDownloaderV3 {
 	var downloader = GithubDownloaderV3{
 		userName:  userName,
 		password:  password,
@@ -98,6 +111,13 @@ func NewGithubDownloaderV3(ctx context.Context, userName, password, token, repoO
 		client = oauth2.NewClient(downloader.ctx, ts)
 	}
 	downloader.client = github.NewClient(client)
+	if baseURL != "" && !isGithubCom(baseURL) {
+		var err error
+		if downloader.client, err = github.NewEnterpriseClient(baseURL, baseURL, client); err != nil {
+			log.Error("github.NewEnterpriseClient: %v", err)
+			downloader.client = github.NewClient(client)
+		}
+	}
 	return &downloader
 }
 
@@ -107,7 +127,8 @@ func (g *GithubDownloaderV3) SetContext(ctx context.Context) {
 }
 
 func (g *GithubDownloaderV3) sleep() {
-	for g.rate != nil && g.rate.Remaining <= GithubLimitRateRemaining {
+	// servers which don't send rate limit headers report a zero limit
+	for g.rate != nil && g.rate.Limit > 0 && g.rate.Remaining <= GithubLimitRateRemaining {
 		timer := time.NewTimer(time.Until(g.rate.Reset.Time))
 		select {
 		case <-g.ctx.Done():
@@ -152,7 +173,7 @@ func (g *GithubDownloaderV3) GetRepoInfo() (*base.Repository, error) {
 	return &base.Repository{
 		Owner:         g.repoOwner,
 		Name:          gr.GetName(),
-		IsPrivate:     *gr.Private,
+		IsPrivate:     gr.GetPrivate(),
 		Description:   gr.GetDescription(),
 		OriginalURL:   gr.GetHTMLURL(),
 		CloneURL:      gr.GetCloneURL(),
@@ -203,7 +224,7 @@ func (g *GithubDownloaderV3) GetMilestones() ([]*base.Milestone, error) {
 				Description: desc,
 				Deadline:    m.DueOn,
 				State:       state,
-				Created:     *m.CreatedAt,
+				Created:     m.GetCreatedAt(),
 				Updated:     m.UpdatedAt,
 				Closed:      m.ClosedAt,
 			})
@@ -272,27 +293,27 @@ func (g *GithubDownloaderV3) convertGithubRelease(rel *github.RepositoryRelease)
 
 	r := &base.Release{
 		TagName:         *rel.TagName,
-		TargetCommitish: *rel.TargetCommitish,
+		TargetCommitish: rel.GetTargetCommitish(),
 		Name:            name,
 		Body:            desc,
-		Draft:           *rel.Draft,
-		Prerelease:      *rel.Prerelease,
-		Created:         rel.CreatedAt.Time,
+		Draft:           rel.GetDraft(),
+		Prerelease:      rel.GetPrerelease(),
+		Created:         rel.GetCreatedAt().Time,
 		PublisherID:     *rel.Author.ID,
 		PublisherName:   *rel.Author.Login,
 		PublisherEmail:  email,
-		Published:       rel.PublishedAt.Time,
+		Published:       rel.GetPublishedAt().Time,
 	}
 
 	for _, asset := range rel.Assets {
 		r.Assets = append(r.Assets, base.ReleaseAsset{
-			ID:            *asset.ID,
-			Name:          *asset.Name,
+			ID:            asset.GetID(),
+			Name:          asset.GetName(),
 			ContentType:   asset.ContentType,
 			Size:          asset.Size,
 			DownloadCount: asset.DownloadCount,
-			Created:       asset.CreatedAt.Time,
-			Updated:       asset.UpdatedAt.Time,
+			Created:       asset.GetCreatedAt().Time,
+			Updated:       asset.GetUpdatedAt().Time,
 		})
 	}
 	return r
@@ -379,7 +400,7 @@ func (g *GithubDownloaderV3) GetIssues(page, perPage int) ([]*base.Issue, bool,
 
 		// get reactions
 		var reactions []*base.Reaction
-		for i := 1; ; i++ {
+		for i := 1; !g.SkipReactions; i++ {
 			g.sleep()
 			res, resp, err := g.client.Reactions.ListIssueReactions(g.ctx, g.repoOwner, g.repoName, issue.GetNumber(), &github.ListOptions{
 				Page:    i,
@@ -415,7 +436,7 @@ func (g *GithubDownloaderV3) GetIssues(page, perPage int) ([]*base.Issue, bool,
 			Labels:      labels,
 			Reactions:   reactions,
 			Closed:      issue.ClosedAt,
-			IsLocked:    *issue.Locked,
+			IsLocked:    issue.GetLocked(),
 		})
 	}
 
@@ -451,7 +472,7 @@ func (g *GithubDownloaderV3) GetComments(issueNumber int64) ([]*base.Comment, er
 
 			// get reactions
 			var reactions []*base.Reaction
-			for i := 1; ; i++ {
+			for i := 1; !g.SkipReactions; i++ {
 				g.sleep()
 				res, resp, err := g.client.Reactions.ListIssueCommentReactions(g.ctx, g.repoOwner, g.repoName, comment.GetID(), &github.ListOptions{
 					Page:    i,
@@ -565,7 +586,7 @@ func (g *GithubDownloaderV3) GetPullRequests(page, perPage int) ([]*base.PullReq
 
 		// get reactions
 		var reactions []*base.Reaction
-		for i := 1; ; i++ {
+		for i := 1; !g.SkipReactions; i++ {
 			g.sleep()
 			res, resp, err := g.client.Reactions.ListIssueReactions(g.ctx, g.repoOwner, g.repoName, pr.GetNumber(), &github.ListOptions{
 				Page:    i,
@@ -617,7 +638,7 @@ func (g *GithubDownloaderV3) GetPullRequests(page, perPage int) ([]*base.PullReq
 				RepoName:  *pr.Base.Repo.Name,
 				OwnerName: *pr.Base.User.Login,
 			},
-			PatchURL:  *pr.PatchURL,
+			PatchURL:  pr.GetPatchURL(),
 			Reactions: reactions,
 		})
 	}
@@ -63,9 +63,34 @@ func assertLabelEqual(t *testing.T, name, color, description string, label *base
 	}, label)
 }
 
+func TestGithubDownloaderV3Factory(t *testing.T) {
+	for _, cloneAddr := range []string{
+		"https://github.com/go-gitea/test_repo.git",
+		"http://github.com/go-gitea/test_repo",
+		"https://www.github.com/go-gitea/test_repo.git",
+		"https://GitHub.com/go-gitea/test_repo.git",
+	} {
+		downloader, err := (&GithubDownloaderV3Factory{}).New(context.Background(), base.MigrateOptions{
+			CloneAddr: cloneAddr,
+		})
+		assert.NoError(t, err)
+		githubDownloader := downloader.(*GithubDownloaderV3)
+		assert.EqualValues(t, "https://api.github.com/", githubDownloader.client.BaseURL.String(), cloneAddr)
+		assert.EqualValues(t, "go-gitea", githubDownloader.repoOwner)
+		assert.EqualValues(t, "test_repo", githubDownloader.repoName)
+	}
+
+	// Other servers are GitHub Enterprise servers
+	downloader, err := (&GithubDownloaderV3Factory{}).New(context.Background(), base.MigrateOptions{
+		CloneAddr: "https://github.example.com/go-gitea/test_repo.git",
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "https://github.example.com/api/v3/", downloader.(*GithubDownloaderV3).client.BaseURL.String())
+}
+
 func TestGitHubDownloadRepo(t *testing.T) {
 	GithubLimitRateRemaining = 3 //Wait at 3 remaining since we could have 3 CI in //
-	downloader := NewGithubDownloaderV3(context.Background(), "", "", os.Getenv("GITHUB_READ_TOKEN"), "go-gitea", "test_repo")
+	downloader := NewGithubDownloaderV3(context.Background(), "https://github.com", "", "", os.Getenv("GITHUB_READ_TOKEN"), "go-gitea", "test_repo")
 	err := downloader.RefreshRate()
 	assert.NoError(t, err)
 
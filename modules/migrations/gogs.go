@@ -0,0 +1,402 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+var (
+	_ base.Downloader        = &GogsDownloader{}
+	_ base.DownloaderFactory = &GogsDownloaderFactory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&GogsDownloaderFactory{})
+}
+
+// GogsDownloaderFactory defines a gogs downloader factory
+type GogsDownloaderFactory struct {
+}
+
+// New returns a Downloader related to this factory according MigrateOptions
+func (f *GogsDownloaderFactory) New(ctx context.Context, opts base.MigrateOptions) (base.Downloader, error) {
+	u, err := url.Parse(opts.CloneAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Split(strings.TrimSuffix(u.Path, "/"), "/")
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid path: %s", u.Path)
+	}
+	baseURL := u.Scheme + "://" + u.Host + strings.Join(fields[:len(fields)-2], "/")
+	repoOwner := fields[len(fields)-2]
+	repoName := strings.TrimSuffix(fields[len(fields)-1], ".git")
+
+	log.Trace("Create gogs downloader. BaseURL: %s RepoName: %s/%s", baseURL, repoOwner, repoName)
+
+	return NewGogsDownloader(ctx, baseURL, opts.AuthUsername, opts.AuthPassword, opts.AuthToken, repoOwner, repoName), nil
+}
+
+// GitServiceType returns the type of git service
+func (f *GogsDownloaderFactory) GitServiceType() structs.GitServiceType {
+	return structs.GogsService
+}
+
+type gogsUser struct {
+	ID       int64  `json:"id"`
+	UserName string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type gogsLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type gogsMilestone struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	Closed      *time.Time `json:"closed_at"`
+	Deadline    *time.Time `json:"due_on"`
+}
+
+type gogsIssue struct {
+	Number    int64          `json:"number"`
+	Poster    *gogsUser      `json:"user"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body"`
+	Labels    []*gogsLabel   `json:"labels"`
+	Milestone *gogsMilestone `json:"milestone"`
+	State     string         `json:"state"`
+	Created   time.Time      `json:"created_at"`
+	Updated   time.Time      `json:"updated_at"`
+}
+
+type gogsComment struct {
+	Poster  *gogsUser `json:"user"`
+	Body    string    `json:"body"`
+	Created time.Time `json:"created_at"`
+	Updated time.Time `json:"updated_at"`
+}
+
+type gogsRelease struct {
+	TagName         string    `json:"tag_name"`
+	TargetCommitish string    `json:"target_commitish"`
+	Name            string    `json:"name"`
+	Body            string    `json:"body"`
+	Draft           bool      `json:"draft"`
+	Prerelease      bool      `json:"prerelease"`
+	Publisher       *gogsUser `json:"author"`
+	Created         time.Time `json:"created_at"`
+}
+
+// GogsDownloader implements a Downloader interface to get repository informations
+// from gogs via its v1 API. The API has no pull requests, reviews, reactions, topics
+// and release assets, so these are not migrated.
+// - Issues are listed per state, so all open issues are fetched before the closed ones.
+type GogsDownloader struct {
+	ctx                context.Context
+	client             *http.Client
+	baseURL            string
+	repoOwner          string
+	repoName           string
+	userName           string
+	password           string
+	token              string
+	openIssuesFinished bool
+	openIssuesPages    int
+}
+
+// NewGogsDownloader creates a gogs Downloader via gogs API v1
+func NewGogsDownloader(ctx context.Context, baseURL, userName, password, token, repoOwner, repoName string) *GogsDownloader {
+	return &GogsDownloader{
+		ctx:       ctx,
+		client:    &http.Client{},
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		repoOwner: repoOwner,
+		repoName:  repoName,
+		userName:  userName,
+		password:  password,
+		token:     token,
+	}
+}
+
+// SetContext set context
+func (g *GogsDownloader) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// callAPI requests the endpoint of the repository and decodes the JSON response into result
+func (g *GogsDownloader) callAPI(endpoint string, parameter url.Values, result interface{}) error {
+	u := fmt.Sprintf("%s/api/v1/repos/%s/%s%s", g.baseURL, url.PathEscape(g.repoOwner), url.PathEscape(g.repoName), endpoint)
+	if len(parameter) > 0 {
+		u += "?" + parameter.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(g.ctx)
+	if g.token != "" {
+		req.Header.Set("Authorization", "token "+g.token)
+	} else if g.userName != "" {
+		req.SetBasicAuth(g.userName, g.password)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of %s", resp.StatusCode, u)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// GetRepoInfo returns a repository information
+func (g *GogsDownloader) GetRepoInfo() (*base.Repository, error) {
+	var gr struct {
+		Name          string    `json:"name"`
+		Owner         *gogsUser `json:"owner"`
+		Description   string    `json:"description"`
+		Private       bool      `json:"private"`
+		HTMLURL       string    `json:"html_url"`
+		CloneURL      string    `json:"clone_url"`
+		DefaultBranch string    `json:"default_branch"`
+	}
+	if err := g.callAPI("", nil, &gr); err != nil {
+		return nil, err
+	}
+
+	owner := g.repoOwner
+	if gr.Owner != nil {
+		owner = gr.Owner.UserName
+	}
+
+	// convert gogs repo to stand Repo
+	return &base.Repository{
+		Owner:         owner,
+		Name:          gr.Name,
+		IsPrivate:     gr.Private,
+		Description:   gr.Description,
+		CloneURL:      gr.CloneURL,
+		OriginalURL:   gr.HTMLURL,
+		DefaultBranch: gr.DefaultBranch,
+	}, nil
+}
+
+// GetTopics returns no topics as gogs has none
+func (g *GogsDownloader) GetTopics() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetMilestones returns milestones
+func (g *GogsDownloader) GetMilestones() ([]*base.Milestone, error) {
+	var ms []*gogsMilestone
+	if err := g.callAPI("/milestones", nil, &ms); err != nil {
+		return nil, err
+	}
+
+	milestones := make([]*base.Milestone, 0, len(ms))
+	for _, m := range ms {
+		milestones = append(milestones, &base.Milestone{
+			Title:       m.Title,
+			Description: m.Description,
+			Deadline:    m.Deadline,
+			State:       m.State,
+			Closed:      m.Closed,
+		})
+	}
+	return milestones, nil
+}
+
+func convertGogsLabel(label *gogsLabel) *base.Label {
+	return &base.Label{
+		Name:  label.Name,
+		Color: strings.TrimPrefix(label.Color, "#"),
+	}
+}
+
+// GetLabels returns labels
+func (g *GogsDownloader) GetLabels() ([]*base.Label, error) {
+	var ls []*gogsLabel
+	if err := g.callAPI("/labels", nil, &ls); err != nil {
+		return nil, err
+	}
+
+	labels := make([]*base.Label, 0, len(ls))
+	for _, label := range ls {
+		labels = append(labels, convertGogsLabel(label))
+	}
+	return labels, nil
+}
+
+// GetReleases returns releases
+func (g *GogsDownloader) GetReleases() ([]*base.Release, error) {
+	var rels []*gogsRelease
+	if err := g.callAPI("/releases", nil, &rels); err != nil {
+		return nil, err
+	}
+
+	releases := make([]*base.Release, 0, len(rels))
+	for _, rel := range rels {
+		r := &base.Release{
+			TagName:         rel.TagName,
+			TargetCommitish: rel.TargetCommitish,
+			Name:            rel.Name,
+			Body:            rel.Body,
+			Draft:           rel.Draft,
+			Prerelease:      rel.Prerelease,
+			Created:         rel.Created,
+			Published:       rel.Created,
+		}
+		if rel.Publisher != nil {
+			r.PublisherID = rel.Publisher.ID
+			r.PublisherName = rel.Publisher.UserName
+			r.PublisherEmail = rel.Publisher.Email
+		}
+		releases = append(releases, r)
+	}
+	return releases, nil
+}
+
+// GetAsset is not supported
+func (g *GogsDownloader) GetAsset(_ string, _ int64) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+// GetIssues returns issues according start and limit, perPage is not supported by gogs
+func (g *GogsDownloader) GetIssues(page, _ int) ([]*base.Issue, bool, error) {
+	var state string
+	if g.openIssuesFinished {
+		state = "closed"
+		page -= g.openIssuesPages
+	} else {
+		state = "open"
+		g.openIssuesPages = page
+	}
+
+	issues, isEnd, err := g.getIssues(page, state)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if isEnd {
+		if g.openIssuesFinished {
+			return issues, true, nil
+		}
+		g.openIssuesFinished = true
+	}
+
+	return issues, false, nil
+}
+
+func (g *GogsDownloader) getIssues(page int, state string) ([]*base.Issue, bool, error) {
+	var issues []*gogsIssue
+	if err := g.callAPI("/issues", url.Values{
+		"page":  {strconv.Itoa(page)},
+		"state": {state},
+	}, &issues); err != nil {
+		return nil, false, fmt.Errorf("error while listing issues: %v", err)
+	}
+
+	allIssues := make([]*base.Issue, 0, len(issues))
+	for _, issue := range issues {
+		allIssues = append(allIssues, convertGogsIssue(issue))
+	}
+
+	return allIssues, len(issues) == 0, nil
+}
+
+func convertGogsIssue(issue *gogsIssue) *base.Issue {
+	var milestone string
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Title
+	}
+	var labels = make([]*base.Label, 0, len(issue.Labels))
+	for _, l := range issue.Labels {
+		labels = append(labels, convertGogsLabel(l))
+	}
+
+	var closed *time.Time
+	if issue.State == "closed" {
+		// gogs doesn't provide the closing time, so use the update time instead
+		closed = &issue.Updated
+	}
+
+	var poster gogsUser
+	if issue.Poster != nil {
+		poster = *issue.Poster
+	}
+
+	return &base.Issue{
+		Title:       issue.Title,
+		Number:      issue.Number,
+		PosterID:    poster.ID,
+		PosterName:  poster.UserName,
+		PosterEmail: poster.Email,
+		Content:     issue.Body,
+		Milestone:   milestone,
+		State:       issue.State,
+		Created:     issue.Created,
+		Updated:     issue.Updated,
+		Labels:      labels,
+		Closed:      closed,
+	}
+}
+
+// GetComments returns comments according issueNumber
+func (g *GogsDownloader) GetComments(issueNumber int64) ([]*base.Comment, error) {
+	var comments []*gogsComment
+	if err := g.callAPI(fmt.Sprintf("/issues/%d/comments", issueNumber), nil, &comments); err != nil {
+		return nil, fmt.Errorf("error while listing comments: %v", err)
+	}
+
+	allComments := make([]*base.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if len(comment.Body) == 0 || comment.Poster == nil {
+			continue
+		}
+		allComments = append(allComments, &base.Comment{
+			IssueIndex:  issueNumber,
+			PosterID:    comment.Poster.ID,
+			PosterName:  comment.Poster.UserName,
+			PosterEmail: comment.Poster.Email,
+			Content:     comment.Body,
+			Created:     comment.Created,
+			Updated:     comment.Updated,
+		})
+	}
+
+	return allComments, nil
+}
+
+// GetPullRequests returns no pull requests as the gogs API doesn't provide them
+func (g *GogsDownloader) GetPullRequests(_, _ int) ([]*base.PullRequest, error) {
+	return []*base.PullRequest{}, nil
+}
+
+// GetReviews returns no reviews as gogs has no pull request reviews
+func (g *GogsDownloader) GetReviews(_ int64) ([]*base.Review, error) {
+	return []*base.Review{}, nil
+}
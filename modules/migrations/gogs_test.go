@@ -0,0 +1,157 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGogsDownloaderFactory(t *testing.T) {
+	downloader, err := (&GogsDownloaderFactory{}).New(context.Background(), base.MigrateOptions{
+		CloneAddr: "https://example.com/gogs/gogs-user/test_repo.git",
+	})
+	assert.NoError(t, err)
+	gogsDownloader := downloader.(*GogsDownloader)
+	assert.EqualValues(t, "https://example.com/gogs", gogsDownloader.baseURL)
+	assert.EqualValues(t, "gogs-user", gogsDownloader.repoOwner)
+	assert.EqualValues(t, "test_repo", gogsDownloader.repoName)
+
+	_, err = (&GogsDownloaderFactory{}).New(context.Background(), base.MigrateOptions{
+		CloneAddr: "https://example.com/test_repo",
+	})
+	assert.Error(t, err)
+}
+
+func TestGogsDownloadRepo(t *testing.T) {
+	server := newRecordedServer(t, "gogs")
+	defer server.Close()
+
+	downloader := NewGogsDownloader(context.Background(), server.URL, "", "", "token", "gogs-user", "test_repo")
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, &base.Repository{
+		Name:          "test_repo",
+		Owner:         "gogs-user",
+		Description:   "Test repository for migrating from Gogs",
+		CloneURL:      "https://try.gogs.io/gogs-user/test_repo.git",
+		OriginalURL:   "https://try.gogs.io/gogs-user/test_repo",
+		DefaultBranch: "master",
+	}, repo)
+
+	topics, err := downloader.GetTopics()
+	assert.NoError(t, err)
+	assert.Empty(t, topics)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 2)
+	assertMilestoneEqual(t, "First release", "v1.0", "2020-09-30 00:00:00 +0000 UTC", "", "", "2020-09-04 12:00:00 +0000 UTC", "closed", milestones[0])
+	assertMilestoneEqual(t, "", "v2.0", "", "", "", "", "open", milestones[1])
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assertLabelEqual(t, "bug", "ee0701", "", labels[0])
+	assertLabelEqual(t, "enhancement", "84b6eb", "", labels[1])
+
+	releases, err := downloader.GetReleases()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Release{
+		{
+			TagName:         "v1.0",
+			TargetCommitish: "master",
+			Name:            "First release",
+			Body:            "The first release",
+			PublisherID:     1,
+			PublisherName:   "gogs-user",
+			PublisherEmail:  "gogs-user@example.com",
+			Created:         time.Date(2020, 9, 4, 12, 0, 0, 0, time.UTC),
+			Published:       time.Date(2020, 9, 4, 12, 0, 0, 0, time.UTC),
+		},
+	}, releases)
+
+	// open issues are listed before the closed ones
+	var issues []*base.Issue
+	for page := 1; ; page++ {
+		pageIssues, isEnd, err := downloader.GetIssues(page, 50)
+		assert.NoError(t, err)
+		issues = append(issues, pageIssues...)
+		if isEnd {
+			break
+		}
+		assert.True(t, page < 5, "GetIssues doesn't end")
+	}
+	closed := time.Date(2020, 9, 4, 11, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, []*base.Issue{
+		{
+			Number:      2,
+			Title:       "Add a feature",
+			Content:     "It would be nice to have a feature.",
+			Milestone:   "v2.0",
+			PosterID:    2,
+			PosterName:  "reviewer",
+			PosterEmail: "reviewer@example.com",
+			State:       "open",
+			Created:     time.Date(2020, 9, 3, 10, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 3, 10, 0, 0, 0, time.UTC),
+			Labels: []*base.Label{
+				{Name: "enhancement", Color: "84b6eb"},
+			},
+		},
+		{
+			Number:      1,
+			Title:       "Something is broken",
+			Content:     "It doesn't work.",
+			Milestone:   "v1.0",
+			PosterID:    1,
+			PosterName:  "gogs-user",
+			PosterEmail: "gogs-user@example.com",
+			State:       "closed",
+			Created:     time.Date(2020, 9, 2, 10, 0, 0, 0, time.UTC),
+			Updated:     closed,
+			Closed:      &closed,
+			Labels: []*base.Label{
+				{Name: "bug", Color: "ee0701"},
+			},
+		},
+	}, issues)
+
+	comments, err := downloader.GetComments(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Comment{
+		{
+			IssueIndex:  1,
+			PosterID:    2,
+			PosterName:  "reviewer",
+			PosterEmail: "reviewer@example.com",
+			Content:     "I can reproduce this.",
+			Created:     time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			IssueIndex:  1,
+			PosterID:    1,
+			PosterName:  "gogs-user",
+			PosterEmail: "gogs-user@example.com",
+			Content:     "Fixed.",
+			Created:     time.Date(2020, 9, 4, 11, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 4, 11, 30, 0, 0, time.UTC),
+		},
+	}, comments)
+
+	prs, err := downloader.GetPullRequests(1, 50)
+	assert.NoError(t, err)
+	assert.Empty(t, prs)
+
+	_, err = downloader.GetComments(3)
+	assert.Error(t, err)
+}
@@ -6,7 +6,12 @@
 package migrations
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"code.gitea.io/gitea/models"
@@ -15,3 +20,32 @@ import (
 func TestMain(m *testing.M) {
 	models.MainTest(m, filepath.Join("..", ".."))
 }
+
+// newRecordedServer returns a test server which answers requests with the responses recorded in
+// testdata/<name>. The file of a response is named after the path and the sorted query of the request.
+func newRecordedServer(t *testing.T, name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", name, recordedFileName(r.URL)))
+		if err != nil {
+			t.Logf("no recorded response for %s", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+}
+
+func recordedFileName(u *url.URL) string {
+	name := u.Path
+	if query := u.Query(); len(query) > 0 {
+		unescaped, _ := url.QueryUnescape(query.Encode())
+		name += "?" + unescaped
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, strings.TrimPrefix(name, "/")) + ".json"
+}
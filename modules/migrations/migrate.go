@@ -29,15 +29,61 @@ func RegisterDownloaderFactory(factory base.DownloaderFactory) {
 
 // MigrateRepository migrate repository according MigrateOptions
 func MigrateRepository(ctx context.Context, doer *models.User, ownerName string, opts base.MigrateOptions) (*models.Repository, error) {
+	downloader, err := newDownloader(ctx, ownerName, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	uploader := NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
+	uploader.gitServiceType = opts.GitServiceType
+
+	if err := migrateRepository(downloader, uploader, opts, nil, nil); err != nil {
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
+
+		if err2 := models.CreateRepositoryNotice(fmt.Sprintf("Migrate repository from %s failed: %v", opts.OriginalURL, err)); err2 != nil {
+			log.Error("create respotiry notice failed: ", err2)
+		}
+		return nil, err
+	}
+
+	return uploader.repo, nil
+}
+
+// MigrateRepositoryWithProgress migrates the repository according MigrateOptions starting at the stage of
+// the progress, which is passed to saveProgress at every checkpoint. Unlike MigrateRepository the migrated
+// data is kept if the migration fails, so that the migration can be resumed.
+func MigrateRepositoryWithProgress(ctx context.Context, doer *models.User, ownerName string, opts base.MigrateOptions, progress *base.MigrateProgress, saveProgress func(*base.MigrateProgress) error) (*models.Repository, error) {
+	downloader, err := newDownloader(ctx, ownerName, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	uploader := NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
+	uploader.gitServiceType = opts.GitServiceType
+
+	if err := migrateRepository(downloader, uploader, opts, progress, saveProgress); err != nil {
+		if err2 := models.CreateRepositoryNotice(fmt.Sprintf("Migrate repository from %s failed: %v", opts.OriginalURL, err)); err2 != nil {
+			log.Error("create respotiry notice failed: ", err2)
+		}
+		return nil, err
+	}
+
+	return uploader.repo, nil
+}
+
+// newDownloader creates the downloader of the git service of the options, only the git data
+// of repositories of unsupported git services is migrated
+func newDownloader(ctx context.Context, ownerName string, opts *base.MigrateOptions) (base.Downloader, error) {
 	var (
 		downloader base.Downloader
-		uploader   = NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
 		err        error
 	)
 
 	for _, factory := range factories {
 		if factory.GitServiceType() == opts.GitServiceType {
-			downloader, err = factory.New(ctx, opts)
+			downloader, err = factory.New(ctx, *opts)
 			if err != nil {
 				return nil, err
 			}
@@ -57,30 +103,38 @@ func MigrateRepository(ctx context.Context, doer *models.User, ownerName string,
 		log.Trace("Will migrate from git: %s", opts.OriginalURL)
 	}
 
-	uploader.gitServiceType = opts.GitServiceType
-
 	if setting.Migrations.MaxAttempts > 1 {
 		downloader = base.NewRetryDownloader(ctx, downloader, setting.Migrations.MaxAttempts, setting.Migrations.RetryBackoff)
 	}
+	return downloader, nil
+}
 
-	if err := migrateRepository(downloader, uploader, opts); err != nil {
-		if err1 := uploader.Rollback(); err1 != nil {
-			log.Error("rollback failed: %v", err1)
+// migrateRepository will download information and then upload it to Uploader, this is a simple
+// process for small repository. For a big repository, save all the data to disk
+// before upload is better. The migration starts at the stage of progress, which is passed to
+// saveProgress whenever a stage or a page of issues or pull requests has been completed.
+func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts base.MigrateOptions, progress *base.MigrateProgress, saveProgress func(*base.MigrateProgress) error) error {
+	if progress == nil {
+		progress = &base.MigrateProgress{}
+	}
+	resumedStage := progress.Stage
+	resumable, isResumable := uploader.(base.ResumableUploader)
+	setStage := func(stage base.MigrateStage, page int) error {
+		progress.Stage = stage
+		progress.Page = page
+		if isResumable {
+			checkpoint, err := resumable.Checkpoint()
+			if err != nil {
+				return err
+			}
+			progress.Checkpoint = checkpoint
 		}
-
-		if err2 := models.CreateRepositoryNotice(fmt.Sprintf("Migrate repository from %s failed: %v", opts.OriginalURL, err)); err2 != nil {
-			log.Error("create respotiry notice failed: ", err2)
+		if saveProgress != nil {
+			return saveProgress(progress)
 		}
-		return nil, err
+		return nil
 	}
 
-	return uploader.repo, nil
-}
-
-// migrateRepository will download information and then upload it to Uploader, this is a simple
-// process for small repository. For a big repository, save all the data to disk
-// before upload is better
-func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts base.MigrateOptions) error {
 	repo, err := downloader.GetRepoInfo()
 	if err != nil {
 		return err
@@ -90,24 +144,45 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 	if opts.Description != "" {
 		repo.Description = opts.Description
 	}
-	log.Trace("migrating git data")
-	if err := uploader.CreateRepo(repo, opts); err != nil {
-		return err
+	if progress.IsDone(base.MigrateStageRepo) {
+		if !isResumable {
+			return fmt.Errorf("%T is unable to resume migrations", uploader)
+		}
+		log.Trace("resuming migration at %s", progress.Stage)
+		if err := resumable.ResumeRepo(repo, opts, progress.Checkpoint); err != nil {
+			return err
+		}
+	} else {
+		log.Trace("migrating git data")
+		if err := uploader.CreateRepo(repo, opts); err != nil {
+			return err
+		}
 	}
 	defer uploader.Close()
 
-	log.Trace("migrating topics")
-	topics, err := downloader.GetTopics()
-	if err != nil {
-		return err
-	}
-	if len(topics) > 0 {
-		if err := uploader.CreateTopics(topics...); err != nil {
+	if !progress.IsDone(base.MigrateStageTopics) {
+		if err := setStage(base.MigrateStageTopics, 0); err != nil {
 			return err
 		}
+
+		log.Trace("migrating topics")
+		topics, err := downloader.GetTopics()
+		if err != nil {
+			return err
+		}
+		if len(topics) > 0 {
+			if err := uploader.CreateTopics(topics...); err != nil {
+				return err
+			}
+		}
 	}
 
-	if opts.Milestones {
+	if !progress.IsDone(base.MigrateStageMilestones) {
+		if err := setStage(base.MigrateStageMilestones, 0); err != nil {
+			return err
+		}
+	}
+	if opts.Milestones && !progress.IsDone(base.MigrateStageMilestones) {
 		log.Trace("migrating milestones")
 		milestones, err := downloader.GetMilestones()
 		if err != nil {
@@ -123,11 +198,17 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 			if err := uploader.CreateMilestones(milestones...); err != nil {
 				return err
 			}
+			progress.Milestones += msBatchSize
 			milestones = milestones[msBatchSize:]
 		}
 	}
 
-	if opts.Labels {
+	if !progress.IsDone(base.MigrateStageLabels) {
+		if err := setStage(base.MigrateStageLabels, 0); err != nil {
+			return err
+		}
+	}
+	if opts.Labels && !progress.IsDone(base.MigrateStageLabels) {
 		log.Trace("migrating labels")
 		labels, err := downloader.GetLabels()
 		if err != nil {
@@ -143,11 +224,17 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 			if err := uploader.CreateLabels(labels...); err != nil {
 				return err
 			}
+			progress.Labels += lbBatchSize
 			labels = labels[lbBatchSize:]
 		}
 	}
 
-	if opts.Releases {
+	if !progress.IsDone(base.MigrateStageReleases) {
+		if err := setStage(base.MigrateStageReleases, 0); err != nil {
+			return err
+		}
+	}
+	if opts.Releases && !progress.IsDone(base.MigrateStageReleases) {
 		log.Trace("migrating releases")
 		releases, err := downloader.GetReleases()
 		if err != nil {
@@ -163,6 +250,7 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 			if err := uploader.CreateReleases(downloader, releases[:relBatchSize]...); err != nil {
 				return err
 			}
+			progress.Releases += relBatchSize
 			releases = releases[relBatchSize:]
 		}
 
@@ -175,13 +263,38 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 	var (
 		commentBatchSize = uploader.MaxBatchInsertSize("comment")
 		reviewBatchSize  = uploader.MaxBatchInsertSize("review")
+		issueBatchSize   = uploader.MaxBatchInsertSize("issue")
+		prBatchSize      = uploader.MaxBatchInsertSize("pullrequest")
 	)
 
-	if opts.Issues {
+	if !progress.IsDone(base.MigrateStageIssues) && progress.Stage != base.MigrateStageIssues {
+		if err := setStage(base.MigrateStageIssues, 1); err != nil {
+			return err
+		}
+	}
+	// downloaders may keep state while listing the issues and pull requests, so the pages which
+	// have been migrated before the migration has been resumed are listed again without uploading them
+	if opts.Issues && resumedStage == base.MigrateStagePullRequests {
+		for i := 1; ; i++ {
+			_, isEnd, err := downloader.GetIssues(i, issueBatchSize)
+			if err != nil {
+				return err
+			}
+			if isEnd {
+				break
+			}
+		}
+	} else if opts.Issues && resumedStage == base.MigrateStageIssues {
+		for i := 1; i < progress.Page; i++ {
+			if _, _, err := downloader.GetIssues(i, issueBatchSize); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.Issues && !progress.IsDone(base.MigrateStageIssues) {
 		log.Trace("migrating issues and comments")
-		var issueBatchSize = uploader.MaxBatchInsertSize("issue")
 
-		for i := 1; ; i++ {
+		for i := progress.Page; ; i++ {
 			issues, isEnd, err := downloader.GetIssues(i, issueBatchSize)
 			if err != nil {
 				return err
@@ -190,45 +303,62 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 			if err := uploader.CreateIssues(issues...); err != nil {
 				return err
 			}
+			progress.Issues += len(issues)
 
-			if !opts.Comments {
-				continue
-			}
+			if opts.Comments {
+				var allComments = make([]*base.Comment, 0, commentBatchSize)
+				for _, issue := range issues {
+					comments, err := downloader.GetComments(issue.Number)
+					if err != nil {
+						return err
+					}
 
-			var allComments = make([]*base.Comment, 0, commentBatchSize)
-			for _, issue := range issues {
-				comments, err := downloader.GetComments(issue.Number)
-				if err != nil {
-					return err
-				}
+					allComments = append(allComments, comments...)
 
-				allComments = append(allComments, comments...)
+					if len(allComments) >= commentBatchSize {
+						if err := uploader.CreateComments(allComments[:commentBatchSize]...); err != nil {
+							return err
+						}
+						progress.Comments += commentBatchSize
 
-				if len(allComments) >= commentBatchSize {
-					if err := uploader.CreateComments(allComments[:commentBatchSize]...); err != nil {
-						return err
+						allComments = allComments[commentBatchSize:]
 					}
-
-					allComments = allComments[commentBatchSize:]
 				}
-			}
 
-			if len(allComments) > 0 {
-				if err := uploader.CreateComments(allComments...); err != nil {
-					return err
+				if len(allComments) > 0 {
+					if err := uploader.CreateComments(allComments...); err != nil {
+						return err
+					}
+					progress.Comments += len(allComments)
 				}
 			}
 
 			if isEnd {
 				break
 			}
+			if err := setStage(base.MigrateStageIssues, i+1); err != nil {
+				return err
+			}
 		}
 	}
 
+	if progress.Stage != base.MigrateStagePullRequests {
+		if err := setStage(base.MigrateStagePullRequests, 1); err != nil {
+			return err
+		}
+	}
 	if opts.PullRequests {
 		log.Trace("migrating pull requests and comments")
-		var prBatchSize = uploader.MaxBatchInsertSize("pullrequest")
-		for i := 1; ; i++ {
+
+		if resumedStage == base.MigrateStagePullRequests {
+			for i := 1; i < progress.Page; i++ {
+				if _, err := downloader.GetPullRequests(i, prBatchSize); err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := progress.Page; ; i++ {
 			prs, err := downloader.GetPullRequests(i, prBatchSize)
 			if err != nil {
 				return err
@@ -237,72 +367,78 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 			if err := uploader.CreatePullRequests(prs...); err != nil {
 				return err
 			}
+			progress.PullRequests += len(prs)
+
+			if opts.Comments {
+				// plain comments
+				var allComments = make([]*base.Comment, 0, commentBatchSize)
+				for _, pr := range prs {
+					comments, err := downloader.GetComments(pr.Number)
+					if err != nil {
+						return err
+					}
 
-			if !opts.Comments {
-				continue
-			}
+					allComments = append(allComments, comments...)
 
-			// plain comments
-			var allComments = make([]*base.Comment, 0, commentBatchSize)
-			for _, pr := range prs {
-				comments, err := downloader.GetComments(pr.Number)
-				if err != nil {
-					return err
+					if len(allComments) >= commentBatchSize {
+						if err := uploader.CreateComments(allComments[:commentBatchSize]...); err != nil {
+							return err
+						}
+						progress.Comments += commentBatchSize
+						allComments = allComments[commentBatchSize:]
+					}
 				}
-
-				allComments = append(allComments, comments...)
-
-				if len(allComments) >= commentBatchSize {
-					if err := uploader.CreateComments(allComments[:commentBatchSize]...); err != nil {
+				if len(allComments) > 0 {
+					if err := uploader.CreateComments(allComments...); err != nil {
 						return err
 					}
-					allComments = allComments[commentBatchSize:]
+					progress.Comments += len(allComments)
 				}
-			}
-			if len(allComments) > 0 {
-				if err := uploader.CreateComments(allComments...); err != nil {
-					return err
-				}
-			}
 
-			// migrate reviews
-			var allReviews = make([]*base.Review, 0, reviewBatchSize)
-			for _, pr := range prs {
-				number := pr.Number
+				// migrate reviews
+				var allReviews = make([]*base.Review, 0, reviewBatchSize)
+				for _, pr := range prs {
+					number := pr.Number
 
-				// on gitlab migrations pull number change
-				if pr.OriginalNumber > 0 {
-					number = pr.OriginalNumber
-				}
+					// on gitlab migrations pull number change
+					if pr.OriginalNumber > 0 {
+						number = pr.OriginalNumber
+					}
 
-				reviews, err := downloader.GetReviews(number)
-				if pr.OriginalNumber > 0 {
-					for i := range reviews {
-						reviews[i].IssueIndex = pr.Number
+					reviews, err := downloader.GetReviews(number)
+					if pr.OriginalNumber > 0 {
+						for i := range reviews {
+							reviews[i].IssueIndex = pr.Number
+						}
+					}
+					if err != nil {
+						return err
 					}
-				}
-				if err != nil {
-					return err
-				}
 
-				allReviews = append(allReviews, reviews...)
+					allReviews = append(allReviews, reviews...)
 
-				if len(allReviews) >= reviewBatchSize {
-					if err := uploader.CreateReviews(allReviews[:reviewBatchSize]...); err != nil {
-						return err
+					if len(allReviews) >= reviewBatchSize {
+						if err := uploader.CreateReviews(allReviews[:reviewBatchSize]...); err != nil {
+							return err
+						}
+						progress.Reviews += reviewBatchSize
+						allReviews = allReviews[reviewBatchSize:]
 					}
-					allReviews = allReviews[reviewBatchSize:]
 				}
-			}
-			if len(allReviews) > 0 {
-				if err := uploader.CreateReviews(allReviews...); err != nil {
-					return err
+				if len(allReviews) > 0 {
+					if err := uploader.CreateReviews(allReviews...); err != nil {
+						return err
+					}
+					progress.Reviews += len(allReviews)
 				}
 			}
 
 			if len(prs) < prBatchSize {
 				break
 			}
+			if err := setStage(base.MigrateStagePullRequests, i+1); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,113 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingDownloader fails to list the comments of an issue once
+type failingDownloader struct {
+	*localGogsDownloader
+	failIssue int64
+}
+
+func (d *failingDownloader) GetComments(issueNumber int64) ([]*base.Comment, error) {
+	if issueNumber == d.failIssue {
+		d.failIssue = 0
+		return nil, errors.New("connection reset")
+	}
+	return d.localGogsDownloader.GetComments(issueNumber)
+}
+
+func TestResumeMigration(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	server := newRecordedServer(t, "gogs")
+	defer server.Close()
+
+	user := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	opts := base.MigrateOptions{
+		GitServiceType: structs.GogsService,
+		RepoName:       "resumed",
+		Milestones:     true,
+		Labels:         true,
+		Issues:         true,
+		Comments:       true,
+		PullRequests:   true,
+	}
+	newDownloader := func() *localGogsDownloader {
+		return &localGogsDownloader{
+			GogsDownloader: NewGogsDownloader(context.Background(), server.URL, "", "", "token", "gogs-user", "test_repo"),
+			cloneURL:       models.RepoPath("user2", "repo1"),
+		}
+	}
+
+	// the progress is saved like the migrate task does
+	var saved string
+	saveProgress := func(progress *base.MigrateProgress) error {
+		bs, err := json.Marshal(progress)
+		saved = string(bs)
+		return err
+	}
+	loadProgress := func() *base.MigrateProgress {
+		var progress base.MigrateProgress
+		assert.NoError(t, json.Unmarshal([]byte(saved), &progress))
+		return &progress
+	}
+
+	// the closed issue #1 is listed on the third page after the open issues, it is created
+	// before listing its comments fails
+	uploader := NewGiteaLocalUploader(context.Background(), user, user.Name, opts.RepoName)
+	uploader.gitServiceType = opts.GitServiceType
+	err := migrateRepository(&failingDownloader{newDownloader(), 1}, uploader, opts, nil, saveProgress)
+	assert.EqualError(t, err, "connection reset")
+
+	repo := models.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: user.ID, Name: opts.RepoName}).(*models.Repository)
+	models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1})
+
+	progress := loadProgress()
+	assert.EqualValues(t, base.MigrateStageIssues, progress.Stage)
+	assert.EqualValues(t, 3, progress.Page)
+	assert.EqualValues(t, 2, progress.Milestones)
+	assert.EqualValues(t, 2, progress.Labels)
+	assert.EqualValues(t, 1, progress.Issues)
+	assert.EqualValues(t, 0, progress.Comments)
+
+	uploader = NewGiteaLocalUploader(context.Background(), user, user.Name, opts.RepoName)
+	uploader.gitServiceType = opts.GitServiceType
+	assert.NoError(t, migrateRepository(newDownloader(), uploader, opts, progress, saveProgress))
+
+	progress = loadProgress()
+	assert.EqualValues(t, base.MigrateStagePullRequests, progress.Stage)
+	assert.EqualValues(t, 2, progress.Issues)
+	assert.EqualValues(t, 2, progress.Comments)
+
+	// nothing has been migrated twice
+	repo = models.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository)
+	assert.EqualValues(t, 2, repo.NumIssues)
+	assert.EqualValues(t, 1, repo.NumClosedIssues)
+	assert.EqualValues(t, 2, repo.NumMilestones)
+	models.AssertCount(t, &models.Label{RepoID: repo.ID}, 2)
+	models.AssertCount(t, &models.Issue{RepoID: repo.ID}, 2)
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 1}).(*models.Issue)
+	assert.EqualValues(t, 2, issue.NumComments)
+	models.AssertCount(t, &models.Comment{IssueID: issue.ID}, 2)
+	models.AssertCount(t, &models.IssueLabel{IssueID: issue.ID}, 1)
+
+	label := models.AssertExistsAndLoadBean(t, &models.Label{RepoID: repo.ID, Name: "bug"}).(*models.Label)
+	assert.EqualValues(t, 1, label.NumIssues)
+	assert.EqualValues(t, 1, label.NumClosedIssues)
+}
@@ -0,0 +1,561 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+	"code.gitea.io/gitea/modules/structs"
+)
+
+var (
+	_ base.Downloader        = &OneDevDownloader{}
+	_ base.DownloaderFactory = &OneDevDownloaderFactory{}
+)
+
+func init() {
+	RegisterDownloaderFactory(&OneDevDownloaderFactory{})
+}
+
+// OneDevDownloaderFactory defines a downloader factory
+type OneDevDownloaderFactory struct {
+}
+
+// New returns a downloader related to this factory according MigrateOptions
+func (f *OneDevDownloaderFactory) New(ctx context.Context, opts base.MigrateOptions) (base.Downloader, error) {
+	u, err := url.Parse(opts.CloneAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	// the clone URL of a project is the URL of the server followed by its name
+	fields := strings.Split(strings.TrimSuffix(u.Path, "/"), "/")
+	if len(fields) < 2 || fields[len(fields)-1] == "" {
+		return nil, fmt.Errorf("invalid path: %s", u.Path)
+	}
+	baseURL := u.Scheme + "://" + u.Host + strings.Join(fields[:len(fields)-1], "/")
+	repoName := strings.TrimSuffix(fields[len(fields)-1], ".git")
+
+	log.Trace("Create onedev downloader. BaseURL: %s RepoName: %s", baseURL, repoName)
+
+	return NewOneDevDownloader(ctx, baseURL, opts.AuthUsername, opts.AuthPassword, repoName)
+}
+
+// GitServiceType returns the type of git service
+func (f *OneDevDownloaderFactory) GitServiceType() structs.GitServiceType {
+	return structs.OneDevService
+}
+
+// onedevLabels are the choices of the issue type field of OneDev, which are migrated as labels
+var onedevLabels = []*base.Label{
+	{Name: "Bug", Color: "f64e60"},
+	{Name: "Build Failure", Color: "f64e60"},
+	{Name: "Discussion", Color: "8950fc"},
+	{Name: "Improvement", Color: "1bc5bd"},
+	{Name: "New Feature", Color: "1bc5bd"},
+	{Name: "Support Request", Color: "8950fc"},
+}
+
+type onedevUser struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type onedevComment struct {
+	UserID  int64     `json:"userId"`
+	Content string    `json:"content"`
+	Date    time.Time `json:"date"`
+}
+
+// OneDevDownloader implements a Downloader interface to get repository informations
+// from OneDev via its REST API.
+// - Issues and pull requests have individual numbers in OneDev, so the numbers of the pull
+// requests are offset by the highest issue number.
+// - issueIDs and pullIDs map the migrated numbers to the OneDev IDs, which the API uses to
+// reference issues and pull requests, originalPullIDs maps the OneDev numbers of pull requests.
+type OneDevDownloader struct {
+	ctx             context.Context
+	client          *http.Client
+	baseURL         string
+	repoName        string
+	repoID          int64
+	userName        string
+	password        string
+	maxIssueNumber  int64
+	issuesFetched   bool
+	issueIDs        map[int64]int64
+	pullIDs         map[int64]int64
+	originalPullIDs map[int64]int64
+	userMap         map[int64]*onedevUser
+}
+
+// NewOneDevDownloader creates a new downloader
+func NewOneDevDownloader(ctx context.Context, baseURL, userName, password, repoName string) (*OneDevDownloader, error) {
+	downloader := &OneDevDownloader{
+		ctx:             ctx,
+		client:          &http.Client{},
+		baseURL:         strings.TrimSuffix(baseURL, "/"),
+		repoName:        repoName,
+		userName:        userName,
+		password:        password,
+		issueIDs:        make(map[int64]int64),
+		pullIDs:         make(map[int64]int64),
+		originalPullIDs: make(map[int64]int64),
+		userMap:         make(map[int64]*onedevUser),
+	}
+
+	var rawRepos []struct {
+		ID int64 `json:"id"`
+	}
+	if err := downloader.callAPI("/api/projects", url.Values{
+		"query":  {`"Name" is "` + repoName + `"`},
+		"offset": {"0"},
+		"count":  {"1"},
+	}, &rawRepos); err != nil {
+		return nil, err
+	}
+	if len(rawRepos) != 1 {
+		return nil, fmt.Errorf("project %s not found", repoName)
+	}
+	downloader.repoID = rawRepos[0].ID
+
+	return downloader, nil
+}
+
+// SetContext set context
+func (d *OneDevDownloader) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// callAPI requests the endpoint and decodes the JSON response into result
+func (d *OneDevDownloader) callAPI(endpoint string, parameter url.Values, result interface{}) error {
+	u := d.baseURL + endpoint
+	if len(parameter) > 0 {
+		u += "?" + parameter.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(d.ctx)
+	if d.userName != "" {
+		req.SetBasicAuth(d.userName, d.password)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of %s", resp.StatusCode, u)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func pageParameter(query string, page, perPage int) url.Values {
+	return url.Values{
+		"query":  {query},
+		"offset": {strconv.Itoa((page - 1) * perPage)},
+		"count":  {strconv.Itoa(perPage)},
+	}
+}
+
+// GetRepoInfo returns repository information
+func (d *OneDevDownloader) GetRepoInfo() (*base.Repository, error) {
+	var rawRepo struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := d.callAPI(fmt.Sprintf("/api/projects/%d", d.repoID), nil, &rawRepo); err != nil {
+		return nil, err
+	}
+
+	cloneURL := d.baseURL + "/" + rawRepo.Name
+	return &base.Repository{
+		Name:        rawRepo.Name,
+		Description: rawRepo.Description,
+		CloneURL:    cloneURL,
+		OriginalURL: cloneURL,
+	}, nil
+}
+
+// GetTopics returns no topics as OneDev has none
+func (d *OneDevDownloader) GetTopics() ([]string, error) {
+	return []string{}, nil
+}
+
+// GetMilestones returns milestones
+func (d *OneDevDownloader) GetMilestones() ([]*base.Milestone, error) {
+	var perPage = 100
+	var milestones = make([]*base.Milestone, 0, perPage)
+	endpoint := fmt.Sprintf("/api/projects/%d/milestones", d.repoID)
+	for page := 1; ; page++ {
+		var rawMilestones []struct {
+			Name        string     `json:"name"`
+			Description string     `json:"description"`
+			DueDate     *time.Time `json:"dueDate"`
+			Closed      bool       `json:"closed"`
+		}
+		if err := d.callAPI(endpoint, url.Values{
+			"offset": {strconv.Itoa((page - 1) * perPage)},
+			"count":  {strconv.Itoa(perPage)},
+		}, &rawMilestones); err != nil {
+			return nil, err
+		}
+
+		for _, m := range rawMilestones {
+			state := "open"
+			if m.Closed {
+				state = "closed"
+			}
+			milestones = append(milestones, &base.Milestone{
+				Title:       m.Name,
+				Description: m.Description,
+				Deadline:    m.DueDate,
+				State:       state,
+			})
+		}
+		if len(rawMilestones) < perPage {
+			break
+		}
+	}
+	return milestones, nil
+}
+
+// GetLabels returns the issue types of OneDev as labels
+func (d *OneDevDownloader) GetLabels() ([]*base.Label, error) {
+	return onedevLabels, nil
+}
+
+// GetReleases returns no releases as OneDev has none
+func (d *OneDevDownloader) GetReleases() ([]*base.Release, error) {
+	return []*base.Release{}, nil
+}
+
+// GetAsset is not supported
+func (d *OneDevDownloader) GetAsset(_ string, _ int64) (io.ReadCloser, error) {
+	return nil, ErrNotSupported
+}
+
+func (d *OneDevDownloader) issueQuery() string {
+	return `"Project" is "` + d.repoName + `"`
+}
+
+// GetIssues returns issues
+func (d *OneDevDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	var rawIssues []struct {
+		ID          int64     `json:"id"`
+		Number      int64     `json:"number"`
+		State       string    `json:"state"`
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		SubmitterID int64     `json:"submitterId"`
+		SubmitDate  time.Time `json:"submitDate"`
+	}
+	if err := d.callAPI("/api/issues", pageParameter(d.issueQuery(), page, perPage), &rawIssues); err != nil {
+		return nil, false, err
+	}
+
+	issues := make([]*base.Issue, 0, len(rawIssues))
+	for _, issue := range rawIssues {
+		var fields []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		}
+		if err := d.callAPI(fmt.Sprintf("/api/issues/%d/fields", issue.ID), nil, &fields); err != nil {
+			return nil, false, err
+		}
+		var labels []*base.Label
+		for _, field := range fields {
+			if field.Name != "Type" {
+				continue
+			}
+			for _, l := range onedevLabels {
+				if l.Name == field.Value {
+					labels = append(labels, l)
+				}
+			}
+		}
+
+		var milestones []struct {
+			Name string `json:"name"`
+		}
+		if err := d.callAPI(fmt.Sprintf("/api/issues/%d/milestones", issue.ID), nil, &milestones); err != nil {
+			return nil, false, err
+		}
+		var milestone string
+		if len(milestones) > 0 {
+			milestone = milestones[0].Name
+		}
+
+		state := "open"
+		var closed *time.Time
+		if strings.EqualFold(issue.State, "closed") || strings.EqualFold(issue.State, "released") {
+			state = "closed"
+			// OneDev doesn't provide the closing time, so use the submit time instead
+			submitDate := issue.SubmitDate
+			closed = &submitDate
+		}
+
+		poster, err := d.tryGetUser(issue.SubmitterID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if issue.Number > d.maxIssueNumber {
+			d.maxIssueNumber = issue.Number
+		}
+		d.issueIDs[issue.Number] = issue.ID
+
+		issues = append(issues, &base.Issue{
+			Title:       issue.Title,
+			Number:      issue.Number,
+			PosterID:    poster.ID,
+			PosterName:  poster.Name,
+			PosterEmail: poster.Email,
+			Content:     issue.Description,
+			Milestone:   milestone,
+			State:       state,
+			Created:     issue.SubmitDate,
+			Updated:     issue.SubmitDate,
+			Closed:      closed,
+			Labels:      labels,
+		})
+	}
+
+	isEnd := len(rawIssues) < perPage
+	if isEnd {
+		d.issuesFetched = true
+	}
+	return issues, isEnd, nil
+}
+
+// GetComments returns comments of the issue or pull request with the migrated number
+func (d *OneDevDownloader) GetComments(issueNumber int64) ([]*base.Comment, error) {
+	var endpoint string
+	if id, ok := d.issueIDs[issueNumber]; ok {
+		endpoint = fmt.Sprintf("/api/issues/%d/comments", id)
+	} else if id, ok := d.pullIDs[issueNumber]; ok {
+		endpoint = fmt.Sprintf("/api/pull-requests/%d/comments", id)
+	} else {
+		return nil, fmt.Errorf("unknown issue or pull request %d", issueNumber)
+	}
+
+	var rawComments []*onedevComment
+	if err := d.callAPI(endpoint, nil, &rawComments); err != nil {
+		return nil, err
+	}
+
+	comments := make([]*base.Comment, 0, len(rawComments))
+	for _, comment := range rawComments {
+		if len(comment.Content) == 0 {
+			continue
+		}
+		poster, err := d.tryGetUser(comment.UserID)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, &base.Comment{
+			IssueIndex:  issueNumber,
+			PosterID:    poster.ID,
+			PosterName:  poster.Name,
+			PosterEmail: poster.Email,
+			Content:     comment.Content,
+			Created:     comment.Date,
+			Updated:     comment.Date,
+		})
+	}
+	return comments, nil
+}
+
+// fetchMaxIssueNumber determines the highest issue number if the issues have not been migrated
+func (d *OneDevDownloader) fetchMaxIssueNumber() error {
+	var perPage = 100
+	for page := 1; !d.issuesFetched; page++ {
+		var rawIssues []struct {
+			Number int64 `json:"number"`
+		}
+		if err := d.callAPI("/api/issues", pageParameter(d.issueQuery(), page, perPage), &rawIssues); err != nil {
+			return err
+		}
+		for _, issue := range rawIssues {
+			if issue.Number > d.maxIssueNumber {
+				d.maxIssueNumber = issue.Number
+			}
+		}
+		d.issuesFetched = len(rawIssues) < perPage
+	}
+	return nil
+}
+
+// GetPullRequests returns pull requests
+func (d *OneDevDownloader) GetPullRequests(page, perPage int) ([]*base.PullRequest, error) {
+	if err := d.fetchMaxIssueNumber(); err != nil {
+		return nil, err
+	}
+
+	var rawPullRequests []struct {
+		ID             int64     `json:"id"`
+		Number         int64     `json:"number"`
+		Title          string    `json:"title"`
+		SubmitterID    int64     `json:"submitterId"`
+		SubmitDate     time.Time `json:"submitDate"`
+		Description    string    `json:"description"`
+		TargetBranch   string    `json:"targetBranch"`
+		SourceBranch   string    `json:"sourceBranch"`
+		BaseCommitHash string    `json:"baseCommitHash"`
+		CloseInfo      *struct {
+			Date   time.Time `json:"date"`
+			Status string    `json:"status"`
+		} `json:"closeInfo"`
+	}
+	if err := d.callAPI("/api/pull-requests", pageParameter(`"Target Project" is "`+d.repoName+`"`, page, perPage), &rawPullRequests); err != nil {
+		return nil, err
+	}
+
+	pullRequests := make([]*base.PullRequest, 0, len(rawPullRequests))
+	for _, pr := range rawPullRequests {
+		var mergePreview struct {
+			HeadCommitHash  string `json:"headCommitHash"`
+			MergeCommitHash string `json:"mergeCommitHash"`
+		}
+		if err := d.callAPI(fmt.Sprintf("/api/pull-requests/%d/merge-preview", pr.ID), nil, &mergePreview); err != nil {
+			return nil, err
+		}
+		headSHA := mergePreview.HeadCommitHash
+		if headSHA == "" {
+			headSHA = pr.BaseCommitHash
+		}
+
+		state := "open"
+		var (
+			merged     bool
+			closed     *time.Time
+			mergedTime *time.Time
+		)
+		if pr.CloseInfo != nil {
+			state = "closed"
+			closed = &pr.CloseInfo.Date
+			if pr.CloseInfo.Status == "MERGED" {
+				merged = true
+				mergedTime = &pr.CloseInfo.Date
+			}
+		}
+
+		poster, err := d.tryGetUser(pr.SubmitterID)
+		if err != nil {
+			return nil, err
+		}
+
+		number := pr.Number + d.maxIssueNumber
+		d.pullIDs[number] = pr.ID
+		d.originalPullIDs[pr.Number] = pr.ID
+
+		pullRequests = append(pullRequests, &base.PullRequest{
+			Title:          pr.Title,
+			Number:         number,
+			OriginalNumber: pr.Number,
+			PosterName:     poster.Name,
+			PosterID:       poster.ID,
+			PosterEmail:    poster.Email,
+			Content:        pr.Description,
+			State:          state,
+			Created:        pr.SubmitDate,
+			Updated:        pr.SubmitDate,
+			Closed:         closed,
+			Merged:         merged,
+			MergedTime:     mergedTime,
+			MergeCommitSHA: mergePreview.MergeCommitHash,
+			Head: base.PullRequestBranch{
+				Ref:      pr.SourceBranch,
+				SHA:      headSHA,
+				RepoName: d.repoName,
+			},
+			Base: base.PullRequestBranch{
+				Ref:      pr.TargetBranch,
+				SHA:      pr.BaseCommitHash,
+				RepoName: d.repoName,
+			},
+		})
+	}
+
+	return pullRequests, nil
+}
+
+// GetReviews returns the reviews of the pull request with the original OneDev number
+func (d *OneDevDownloader) GetReviews(pullRequestNumber int64) ([]*base.Review, error) {
+	id, ok := d.originalPullIDs[pullRequestNumber]
+	if !ok {
+		return nil, fmt.Errorf("unknown pull request %d", pullRequestNumber)
+	}
+
+	var rawReviews []struct {
+		UserID int64 `json:"userId"`
+		Result *struct {
+			Commit   string `json:"commit"`
+			Approved bool   `json:"approved"`
+			Comment  string `json:"comment"`
+		} `json:"result"`
+	}
+	if err := d.callAPI(fmt.Sprintf("/api/pull-requests/%d/reviews", id), nil, &rawReviews); err != nil {
+		return nil, err
+	}
+
+	reviews := make([]*base.Review, 0, len(rawReviews))
+	for _, review := range rawReviews {
+		// reviews without result are pending review requests
+		if review.Result == nil {
+			continue
+		}
+		state := base.ReviewStateChangesRequested
+		if review.Result.Approved {
+			state = base.ReviewStateApproved
+		}
+
+		poster, err := d.tryGetUser(review.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		reviews = append(reviews, &base.Review{
+			IssueIndex:   pullRequestNumber,
+			ReviewerID:   poster.ID,
+			ReviewerName: poster.Name,
+			CommitID:     review.Result.Commit,
+			Content:      review.Result.Comment,
+			State:        state,
+		})
+	}
+	return reviews, nil
+}
+
+// tryGetUser returns the user with the ID, users which can't be found are returned with their ID only
+func (d *OneDevDownloader) tryGetUser(userID int64) (*onedevUser, error) {
+	if user, ok := d.userMap[userID]; ok {
+		return user, nil
+	}
+
+	user := &onedevUser{}
+	if err := d.callAPI(fmt.Sprintf("/api/users/%d", userID), nil, user); err != nil {
+		log.Trace("Unable to get OneDev user %d: %v", userID, err)
+		user = &onedevUser{ID: userID}
+	}
+	d.userMap[userID] = user
+	return user, nil
+}
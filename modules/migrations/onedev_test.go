@@ -0,0 +1,161 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/migrations/base"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOneDevDownloadRepo(t *testing.T) {
+	server := newRecordedServer(t, "onedev")
+	defer server.Close()
+
+	downloader, err := (&OneDevDownloaderFactory{}).New(context.Background(), base.MigrateOptions{
+		CloneAddr: server.URL + "/test_repo",
+	})
+	assert.NoError(t, err)
+
+	repo, err := downloader.GetRepoInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, &base.Repository{
+		Name:        "test_repo",
+		Description: "Test repository for migrating from OneDev",
+		CloneURL:    server.URL + "/test_repo",
+		OriginalURL: server.URL + "/test_repo",
+	}, repo)
+
+	milestones, err := downloader.GetMilestones()
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 2)
+	assertMilestoneEqual(t, "First release", "v1.0", "2020-09-30 00:00:00 +0000 UTC", "", "", "", "closed", milestones[0])
+	assertMilestoneEqual(t, "", "v2.0", "", "", "", "", "open", milestones[1])
+
+	labels, err := downloader.GetLabels()
+	assert.NoError(t, err)
+	assert.Len(t, labels, 6)
+
+	releases, err := downloader.GetReleases()
+	assert.NoError(t, err)
+	assert.Empty(t, releases)
+
+	issues, isEnd, err := downloader.GetIssues(1, 50)
+	assert.NoError(t, err)
+	assert.True(t, isEnd)
+	closed := time.Date(2020, 9, 2, 10, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, []*base.Issue{
+		{
+			Number:      1,
+			Title:       "Something is broken",
+			Content:     "It doesn't work.",
+			Milestone:   "v1.0",
+			PosterID:    1,
+			PosterName:  "admin",
+			PosterEmail: "admin@example.com",
+			State:       "closed",
+			Created:     closed,
+			Updated:     closed,
+			Closed:      &closed,
+			Labels: []*base.Label{
+				{Name: "Bug", Color: "f64e60"},
+			},
+		},
+		{
+			Number:      2,
+			Title:       "Add a feature",
+			Content:     "It would be nice to have a feature.",
+			PosterID:    2,
+			PosterName:  "developer",
+			PosterEmail: "developer@example.com",
+			State:       "open",
+			Created:     time.Date(2020, 9, 3, 10, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 3, 10, 0, 0, 0, time.UTC),
+		},
+	}, issues)
+
+	// comments without content are skipped
+	comments, err := downloader.GetComments(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Comment{
+		{
+			IssueIndex:  1,
+			PosterID:    2,
+			PosterName:  "developer",
+			PosterEmail: "developer@example.com",
+			Content:     "I can reproduce this.",
+			Created:     time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+		},
+	}, comments)
+
+	// the numbers of pull requests follow the ones of the issues
+	prs, err := downloader.GetPullRequests(1, 50)
+	assert.NoError(t, err)
+	merged := time.Date(2020, 9, 4, 10, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, []*base.PullRequest{
+		{
+			Number:         3,
+			OriginalNumber: 1,
+			Title:          "Fix the bug",
+			Content:        "Fixes #1",
+			PosterID:       2,
+			PosterName:     "developer",
+			PosterEmail:    "developer@example.com",
+			State:          "closed",
+			Created:        time.Date(2020, 9, 3, 12, 0, 0, 0, time.UTC),
+			Updated:        time.Date(2020, 9, 3, 12, 0, 0, 0, time.UTC),
+			Closed:         &merged,
+			Merged:         true,
+			MergedTime:     &merged,
+			MergeCommitSHA: "0123456789abcdef0123456789abcdef01234567",
+			Head: base.PullRequestBranch{
+				Ref:      "fix",
+				SHA:      "1234567890abcdef1234567890abcdef12345678",
+				RepoName: "test_repo",
+			},
+			Base: base.PullRequestBranch{
+				Ref:      "master",
+				SHA:      "abcdef1234567890abcdef1234567890abcdef12",
+				RepoName: "test_repo",
+			},
+		},
+	}, prs)
+
+	comments, err = downloader.GetComments(3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Comment{
+		{
+			IssueIndex:  3,
+			PosterID:    1,
+			PosterName:  "admin",
+			PosterEmail: "admin@example.com",
+			Content:     "Looks good.",
+			Created:     time.Date(2020, 9, 4, 9, 0, 0, 0, time.UTC),
+			Updated:     time.Date(2020, 9, 4, 9, 0, 0, 0, time.UTC),
+		},
+	}, comments)
+
+	// pending review requests are skipped
+	reviews, err := downloader.GetReviews(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*base.Review{
+		{
+			IssueIndex:   1,
+			ReviewerID:   1,
+			ReviewerName: "admin",
+			CommitID:     "1234567890abcdef1234567890abcdef12345678",
+			Content:      "Approved.",
+			State:        base.ReviewStateApproved,
+		},
+	}, reviews)
+
+	_, err = downloader.GetComments(4)
+	assert.Error(t, err)
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations/base"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	_ base.Downloader = &RepositoryRestorer{}
+)
+
+// RepositoryRestorer implements a Downloader which reads a repository written by the RepositoryDumper
+type RepositoryRestorer struct {
+	ctx          context.Context
+	baseDir      string
+	repoOwner    string
+	repoName     string
+	repo         *dumpedRepository
+	issues       []*base.Issue
+	pullRequests []*base.PullRequest
+}
+
+// NewRepositoryRestorer creates a restorer which reads from baseDir
+func NewRepositoryRestorer(ctx context.Context, baseDir, owner, repoName string) (*RepositoryRestorer, error) {
+	baseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	return &RepositoryRestorer{
+		ctx:       ctx,
+		baseDir:   baseDir,
+		repoOwner: owner,
+		repoName:  repoName,
+	}, nil
+}
+
+// SetContext set context
+func (r *RepositoryRestorer) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// readYAML decodes the file into result, missing files are left empty
+func (r *RepositoryRestorer) readYAML(filename string, result interface{}) error {
+	bs, err := ioutil.ReadFile(filepath.Join(r.baseDir, filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := yaml.Unmarshal(bs, result); err != nil {
+		return fmt.Errorf("unable to parse %s: %v", filename, err)
+	}
+	return nil
+}
+
+func (r *RepositoryRestorer) getRepo() (*dumpedRepository, error) {
+	if r.repo != nil {
+		return r.repo, nil
+	}
+	bs, err := ioutil.ReadFile(filepath.Join(r.baseDir, "repo.yml"))
+	if err != nil {
+		return nil, err
+	}
+	var repo dumpedRepository
+	if err := yaml.Unmarshal(bs, &repo); err != nil {
+		return nil, fmt.Errorf("unable to parse repo.yml: %v", err)
+	}
+	r.repo = &repo
+	return r.repo, nil
+}
+
+// MigrateOptions returns the options to restore the dumped information
+func (r *RepositoryRestorer) MigrateOptions() (base.MigrateOptions, error) {
+	repo, err := r.getRepo()
+	if err != nil {
+		return base.MigrateOptions{}, err
+	}
+	return base.MigrateOptions{
+		RepoName:       r.repoName,
+		Private:        repo.IsPrivate,
+		Description:    repo.Description,
+		OriginalURL:    repo.OriginalURL,
+		GitServiceType: convert.ToGitServiceType(repo.ServiceType),
+		Wiki:           repo.Wiki,
+		Milestones:     repo.Milestones,
+		Labels:         repo.Labels,
+		Releases:       repo.Releases,
+		Issues:         repo.Issues,
+		Comments:       repo.Comments,
+		PullRequests:   repo.PullRequests,
+	}, nil
+}
+
+// GetRepoInfo returns the dumped repository which is cloned from the dumped git data
+func (r *RepositoryRestorer) GetRepoInfo() (*base.Repository, error) {
+	repo, err := r.getRepo()
+	if err != nil {
+		return nil, err
+	}
+	return &base.Repository{
+		Owner:         r.repoOwner,
+		Name:          r.repoName,
+		IsPrivate:     repo.IsPrivate,
+		Description:   repo.Description,
+		CloneURL:      filepath.Join(r.baseDir, "repo.git"),
+		OriginalURL:   repo.OriginalURL,
+		DefaultBranch: repo.DefaultBranch,
+	}, nil
+}
+
+// GetTopics returns the dumped topics
+func (r *RepositoryRestorer) GetTopics() ([]string, error) {
+	var topics []string
+	return topics, r.readYAML("topic.yml", &topics)
+}
+
+// GetMilestones returns the dumped milestones
+func (r *RepositoryRestorer) GetMilestones() ([]*base.Milestone, error) {
+	var milestones []*base.Milestone
+	return milestones, r.readYAML("milestone.yml", &milestones)
+}
+
+// GetReleases returns the dumped releases
+func (r *RepositoryRestorer) GetReleases() ([]*base.Release, error) {
+	var releases []*base.Release
+	return releases, r.readYAML("release.yml", &releases)
+}
+
+// GetAsset opens the dumped file of a release asset
+func (r *RepositoryRestorer) GetAsset(tagName string, id int64) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(r.baseDir, "release_assets", tagName, strconv.FormatInt(id, 10)))
+}
+
+// GetLabels returns the dumped labels
+func (r *RepositoryRestorer) GetLabels() ([]*base.Label, error) {
+	var labels []*base.Label
+	return labels, r.readYAML("label.yml", &labels)
+}
+
+// GetIssues returns the dumped issues according page and perPage
+func (r *RepositoryRestorer) GetIssues(page, perPage int) ([]*base.Issue, bool, error) {
+	if r.issues == nil {
+		r.issues = make([]*base.Issue, 0, 10)
+		if err := r.readYAML("issue.yml", &r.issues); err != nil {
+			return nil, false, err
+		}
+	}
+
+	start, end := (page-1)*perPage, page*perPage
+	if start >= len(r.issues) {
+		return []*base.Issue{}, true, nil
+	}
+	if end >= len(r.issues) {
+		return r.issues[start:], true, nil
+	}
+	return r.issues[start:end], false, nil
+}
+
+// GetComments returns the dumped comments of an issue or pull request
+func (r *RepositoryRestorer) GetComments(issueNumber int64) ([]*base.Comment, error) {
+	var comments []*base.Comment
+	return comments, r.readYAML(filepath.Join("comments", fmt.Sprintf("%d.yml", issueNumber)), &comments)
+}
+
+// GetPullRequests returns the dumped pull requests according page and perPage
+func (r *RepositoryRestorer) GetPullRequests(page, perPage int) ([]*base.PullRequest, error) {
+	if r.pullRequests == nil {
+		r.pullRequests = make([]*base.PullRequest, 0, 10)
+		if err := r.readYAML("pull_request.yml", &r.pullRequests); err != nil {
+			return nil, err
+		}
+		for _, pr := range r.pullRequests {
+			// the numbers have been translated and the heads are part of the dumped git data
+			pr.OriginalNumber = 0
+			pr.PatchURL = ""
+		}
+	}
+
+	start, end := (page-1)*perPage, page*perPage
+	if start >= len(r.pullRequests) {
+		return []*base.PullRequest{}, nil
+	}
+	if end > len(r.pullRequests) {
+		end = len(r.pullRequests)
+	}
+	return r.pullRequests[start:end], nil
+}
+
+// GetReviews returns the dumped reviews of a pull request
+func (r *RepositoryRestorer) GetReviews(pullRequestNumber int64) ([]*base.Review, error) {
+	var reviews []*base.Review
+	return reviews, r.readYAML(filepath.Join("reviews", fmt.Sprintf("%d.yml", pullRequestNumber)), &reviews)
+}
+
+// RestoreRepository restores a repository dumped into baseDir as repository of the owner
+func RestoreRepository(ctx context.Context, doer *models.User, baseDir, ownerName, repoName string) (*models.Repository, error) {
+	downloader, err := NewRepositoryRestorer(ctx, baseDir, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := downloader.MigrateOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	uploader := NewGiteaLocalUploader(ctx, doer, ownerName, repoName)
+	uploader.gitServiceType = opts.GitServiceType
+
+	if err := migrateRepository(downloader, uploader, opts, nil, nil); err != nil {
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
+		return nil, err
+	}
+
+	uploader.repo.Status = models.RepositoryReady
+	if err := models.UpdateRepositoryCols(uploader.repo, "status"); err != nil {
+		return nil, err
+	}
+	return uploader.repo, nil
+}
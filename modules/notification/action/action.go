@@ -86,6 +86,37 @@ func (a *actionNotifier) NotifyIssueChangeStatus(doer *models.User, issue *model
 	}
 }
 
+// NotifyIssueTransfer notifies an issue moved to another repository to notifiers
+func (a *actionNotifier) NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository, oldIndex int64) {
+	if err := issue.LoadRepo(); err != nil {
+		log.Error("issue.LoadRepo: %v", err)
+		return
+	}
+	content := fmt.Sprintf("%s#%d|%s#%d", oldRepo.FullName(), oldIndex, issue.Repo.FullName(), issue.Index)
+	isPrivate := oldRepo.IsPrivate || issue.Repo.IsPrivate
+
+	// Watchers of both repositories should learn where the issue went
+	if err := models.NotifyWatchers(&models.Action{
+		ActUserID: doer.ID,
+		ActUser:   doer,
+		OpType:    models.ActionTransferIssue,
+		Content:   content,
+		RepoID:    oldRepo.ID,
+		Repo:      oldRepo,
+		IsPrivate: isPrivate,
+	}, &models.Action{
+		ActUserID: doer.ID,
+		ActUser:   doer,
+		OpType:    models.ActionTransferIssue,
+		Content:   content,
+		RepoID:    issue.Repo.ID,
+		Repo:      issue.Repo,
+		IsPrivate: isPrivate,
+	}); err != nil {
+		log.Error("NotifyWatchers: %v", err)
+	}
+}
+
 // NotifyCreateIssueComment notifies comment on an issue to notifiers
 func (a *actionNotifier) NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
 	issue *models.Issue, comment *models.Comment) {
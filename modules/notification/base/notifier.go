@@ -29,6 +29,7 @@ type Notifier interface {
 	NotifyIssueClearLabels(doer *models.User, issue *models.Issue)
 	NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string)
 	NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldRef string)
+	NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository, oldIndex int64)
 	NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
 		addedLabels []*models.Label, removedLabels []*models.Label)
 
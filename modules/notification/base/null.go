@@ -106,6 +106,10 @@ func (*NullNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.Iss
 func (*NullNotifier) NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldTitle string) {
 }
 
+// NotifyIssueTransfer places a place holder function
+func (*NullNotifier) NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository, oldIndex int64) {
+}
+
 // NotifyIssueChangeLabels places a place holder function
 func (*NullNotifier) NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
 	addedLabels []*models.Label, removedLabels []*models.Label) {
@@ -152,3 +152,7 @@ func (r *indexerNotifier) NotifyIssueChangeTitle(doer *models.User, issue *model
 func (r *indexerNotifier) NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldRef string) {
 	issue_indexer.UpdateIssueIndexer(issue)
 }
+
+func (r *indexerNotifier) NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository, oldIndex int64) {
+	issue_indexer.UpdateIssueIndexer(issue)
+}
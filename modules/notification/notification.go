@@ -185,6 +185,13 @@ func NotifyIssueChangeRef(doer *models.User, issue *models.Issue, oldRef string)
 	}
 }
 
+// NotifyIssueTransfer notifies an issue moved to another repository to notifiers
+func NotifyIssueTransfer(doer *models.User, issue *models.Issue, oldRepo *models.Repository, oldIndex int64) {
+	for _, notifier := range notifiers {
+		notifier.NotifyIssueTransfer(doer, issue, oldRepo, oldIndex)
+	}
+}
+
 // NotifyIssueChangeLabels notifies change labels to notifiers
 func NotifyIssueChangeLabels(doer *models.User, issue *models.Issue,
 	addedLabels []*models.Label, removedLabels []*models.Label) {
@@ -0,0 +1,193 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/metrics"
+	"code.gitea.io/gitea/modules/setting"
+
+	mc "gitea.com/macaron/cache"
+)
+
+var (
+	// API limits the requests to the API
+	API = &Limiter{name: "api"}
+	// Git limits the git requests over HTTP
+	Git = &Limiter{name: "git"}
+	// Login limits the sign in attempts on the web interface
+	Login = &Limiter{name: "login"}
+
+	store mc.Cache
+)
+
+// Init sets the configured budgets of the limiters, the buckets are kept in the cache of
+// the cache service so replicas sharing a redis or memcache server share the budgets
+func Init() error {
+	if !setting.RateLimit.Enabled {
+		return nil
+	}
+
+	store = cache.GetCache()
+	if store == nil {
+		var err error
+		if store, err = mc.NewCacher("memory", mc.Options{Adapter: "memory", Interval: 60}); err != nil {
+			return fmt.Errorf("unable to create the rate limit store: %v", err)
+		}
+	}
+
+	API.setBudget(setting.RateLimit.API)
+	Git.setBudget(setting.RateLimit.Git)
+	Login.setBudget(setting.RateLimit.Login)
+	return nil
+}
+
+// Result is the state of a bucket after taking a request from it
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Time
+	RetryAfter time.Duration
+}
+
+// Limiter is a token bucket rate limiter with a bucket per key
+type Limiter struct {
+	name    string
+	enabled bool
+	burst   float64
+	// rate is the number of requests added to a bucket per second
+	rate float64
+
+	// lock serializes the updates of the buckets within this process, concurrent requests
+	// on different replicas may still let a few additional requests through
+	lock sync.Mutex
+}
+
+func (l *Limiter) setBudget(budget setting.RateLimitBudget) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.enabled = budget.Enabled
+	l.burst = float64(budget.Burst)
+	l.rate = float64(budget.Requests) / budget.Period.Seconds()
+}
+
+// Name returns the name of the budget of the limiter
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Enabled returns true if the requests of the budget are limited
+func (l *Limiter) Enabled() bool {
+	return setting.RateLimit.Enabled && l.enabled && store != nil
+}
+
+// Take takes a request from the bucket of the key, the result tells if the request is allowed.
+// The result is nil if the budget is disabled or the request is exempt from rate limiting.
+func (l *Limiter) Take(key string, exempt bool) (*Result, error) {
+	if !l.Enabled() {
+		return nil, nil
+	}
+	if exempt {
+		metrics.RateLimitRequests.WithLabelValues(l.name, "exempt").Inc()
+		return nil, nil
+	}
+
+	result, err := l.take(key)
+	if err != nil {
+		return nil, err
+	}
+	if result.Allowed {
+		metrics.RateLimitRequests.WithLabelValues(l.name, "allowed").Inc()
+	} else {
+		metrics.RateLimitRequests.WithLabelValues(l.name, "limited").Inc()
+	}
+	return result, nil
+}
+
+func (l *Limiter) take(key string) (*Result, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	cacheKey := "ratelimit_" + l.name + "_" + key
+	tokens, updated := l.burst, now
+	if value := store.Get(cacheKey); value != nil {
+		var err error
+		if tokens, updated, err = parseBucket(fmt.Sprint(value)); err != nil {
+			return nil, err
+		}
+		tokens = math.Min(l.burst, tokens+now.Sub(updated).Seconds()*l.rate)
+	}
+
+	result := &Result{
+		Limit: int(l.burst),
+	}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) / l.rate * float64(time.Second))
+	}
+	result.Remaining = int(tokens)
+	refill := time.Duration((l.burst - tokens) / l.rate * float64(time.Second))
+	result.Reset = now.Add(refill)
+
+	// The bucket is full again when it expires
+	if err := store.Put(cacheKey, formatBucket(tokens, now), int64(math.Ceil(refill.Seconds()))+1); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func formatBucket(tokens float64, updated time.Time) string {
+	return strconv.FormatFloat(tokens, 'f', -1, 64) + ":" + strconv.FormatInt(updated.UnixNano(), 10)
+}
+
+func parseBucket(value string) (float64, time.Time, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return 0, time.Time{}, fmt.Errorf("invalid rate limit bucket: %q", value)
+	}
+	tokens, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid rate limit bucket: %q", value)
+	}
+	updated, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid rate limit bucket: %q", value)
+	}
+	return tokens, time.Unix(0, updated), nil
+}
+
+// IsExempt returns true if the user or IP address is exempt from rate limiting
+func IsExempt(userName string, isAdmin bool, remoteAddr string) bool {
+	if isAdmin && setting.RateLimit.ExemptAdmins {
+		return true
+	}
+	if userName != "" {
+		for _, exemptUser := range setting.RateLimit.ExemptUsers {
+			if strings.EqualFold(strings.TrimSpace(exemptUser), userName) {
+				return true
+			}
+		}
+	}
+	if ip := net.ParseIP(remoteAddr); ip != nil {
+		for _, ipNet := range setting.RateLimit.ExemptIPNets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	mc "gitea.com/macaron/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Take(t *testing.T) {
+	var err error
+	store, err = mc.NewCacher("memory", mc.Options{Adapter: "memory", Interval: 60})
+	assert.NoError(t, err)
+	defer func(enabled bool) {
+		setting.RateLimit.Enabled = enabled
+	}(setting.RateLimit.Enabled)
+	setting.RateLimit.Enabled = true
+
+	limiter := &Limiter{name: "test"}
+	limiter.setBudget(setting.RateLimitBudget{Enabled: true, Requests: 3600, Period: time.Hour, Burst: 2})
+
+	result, err := limiter.Take("user_1", false)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Limit)
+	assert.Equal(t, 1, result.Remaining)
+
+	result, err = limiter.Take("user_1", false)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, err = limiter.Take("user_1", false)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.True(t, result.RetryAfter > 0 && result.RetryAfter <= time.Second)
+	assert.True(t, result.Reset.After(time.Now()))
+
+	// every key has its own bucket
+	result, err = limiter.Take("user_2", false)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Take("user_1", true)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+
+	// the bucket is refilled at one request per second
+	time.Sleep(1100 * time.Millisecond)
+	result, err = limiter.Take("user_1", false)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	limiter.setBudget(setting.RateLimitBudget{Enabled: false})
+	result, err = limiter.Take("user_1", false)
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestIsExempt(t *testing.T) {
+	defer func(users []string, ipNets []*net.IPNet, admins bool) {
+		setting.RateLimit.ExemptUsers = users
+		setting.RateLimit.ExemptIPNets = ipNets
+		setting.RateLimit.ExemptAdmins = admins
+	}(setting.RateLimit.ExemptUsers, setting.RateLimit.ExemptIPNets, setting.RateLimit.ExemptAdmins)
+
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+	setting.RateLimit.ExemptUsers = []string{"ci-bot"}
+	setting.RateLimit.ExemptIPNets = []*net.IPNet{ipNet}
+	setting.RateLimit.ExemptAdmins = false
+
+	assert.True(t, IsExempt("CI-Bot", false, "192.168.1.1"))
+	assert.True(t, IsExempt("", false, "10.1.2.3"))
+	assert.False(t, IsExempt("user2", false, "192.168.1.1"))
+	assert.False(t, IsExempt("admin", true, "192.168.1.1"))
+
+	setting.RateLimit.ExemptAdmins = true
+	assert.True(t, IsExempt("admin", true, "192.168.1.1"))
+}
@@ -0,0 +1,247 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Suggestion is a change suggested in a code comment which replaces some lines of a file
+type Suggestion struct {
+	TreePath string
+	// Line is the first replaced line, starting at 1
+	Line int64
+	// Original are the lines the suggestion was made against
+	Original []string
+	// Content are the lines replacing Original
+	Content []string
+}
+
+// ApplySuggestionsOptions holds the suggestions to commit to a branch
+type ApplySuggestionsOptions struct {
+	Branch      string
+	Message     string
+	Suggestions []*Suggestion
+	// CoAuthors are credited with Co-authored-by trailers, the doer is always skipped
+	CoAuthors []*models.User
+}
+
+// ApplySuggestions commits the given suggestions to a branch and returns the new commit ID
+func ApplySuggestions(repo *models.Repository, doer *models.User, opts *ApplySuggestionsOptions) (string, error) {
+	if len(opts.Suggestions) == 0 {
+		return "", nil
+	}
+
+	suggestionsByPath := make(map[string][]*Suggestion)
+	treePaths := make([]string, 0, len(opts.Suggestions))
+	for _, suggestion := range opts.Suggestions {
+		treePath := CleanUploadFileName(suggestion.TreePath)
+		if treePath == "" {
+			return "", models.ErrFilenameInvalid{Path: suggestion.TreePath}
+		}
+		if _, ok := suggestionsByPath[treePath]; !ok {
+			treePaths = append(treePaths, treePath)
+		}
+		suggestionsByPath[treePath] = append(suggestionsByPath[treePath], suggestion)
+	}
+	sort.Strings(treePaths)
+
+	if err := checkSuggestionBranchProtection(repo, doer, opts.Branch, treePaths); err != nil {
+		return "", err
+	}
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+	if err := t.Clone(opts.Branch); err != nil {
+		return "", err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return "", err
+	}
+
+	commit, err := t.GetBranchCommit(opts.Branch)
+	if err != nil {
+		return "", err
+	}
+
+	for _, treePath := range treePaths {
+		suggestions := suggestionsByPath[treePath]
+		entry, err := commit.GetTreeEntryByPath(treePath)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				return "", models.ErrSuggestionOutdated{TreePath: treePath, Line: suggestions[0].Line}
+			}
+			return "", err
+		}
+		if !entry.IsRegular() && !entry.IsExecutable() {
+			return "", models.ErrSuggestionOutdated{TreePath: treePath, Line: suggestions[0].Line}
+		}
+
+		dataRc, err := entry.Blob().DataAsync()
+		if err != nil {
+			return "", err
+		}
+		data, err := ioutil.ReadAll(dataRc)
+		dataRc.Close()
+		if err != nil {
+			return "", err
+		}
+
+		content, err := applySuggestionsToContent(treePath, string(data), suggestions)
+		if err != nil {
+			return "", err
+		}
+
+		objectHash, err := t.HashObject(strings.NewReader(content))
+		if err != nil {
+			return "", err
+		}
+		mode := "100644"
+		if entry.IsExecutable() {
+			mode = "100755"
+		}
+		if err := t.AddObjectToIndex(mode, objectHash, treePath); err != nil {
+			return "", err
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return "", err
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	var trailers strings.Builder
+	seen := map[int64]bool{doer.ID: true}
+	for _, coAuthor := range opts.CoAuthors {
+		if coAuthor == nil || seen[coAuthor.ID] {
+			continue
+		}
+		seen[coAuthor.ID] = true
+		trailers.WriteString("\nCo-authored-by: ")
+		trailers.WriteString(coAuthor.NewGitSig().String())
+	}
+	if trailers.Len() > 0 {
+		message += "\n" + trailers.String()
+	}
+
+	commitHash, err := t.CommitTree(doer, doer, treeHash, message)
+	if err != nil {
+		return "", err
+	}
+
+	if err := t.Push(doer, commitHash, opts.Branch); err != nil {
+		log.Error("%T %v", err, err)
+		return "", err
+	}
+	return commitHash, nil
+}
+
+// checkSuggestionBranchProtection checks the doer may commit the changed paths directly to the branch
+func checkSuggestionBranchProtection(repo *models.Repository, doer *models.User, branch string, treePaths []string) error {
+	protectedBranch, err := repo.GetBranchProtection(branch)
+	if err != nil {
+		return err
+	}
+	if protectedBranch == nil {
+		return nil
+	}
+	if !protectedBranch.CanUserPush(doer.ID) {
+		return models.ErrUserCannotCommit{
+			UserName: doer.LowerName,
+		}
+	}
+	if protectedBranch.RequireSignedCommits {
+		_, _, _, err := repo.SignCRUDAction(doer, repo.RepoPath(), branch)
+		if err != nil {
+			if !models.IsErrWontSign(err) {
+				return err
+			}
+			return models.ErrUserCannotCommit{
+				UserName: doer.LowerName,
+			}
+		}
+	}
+	patterns := protectedBranch.GetProtectedFilePatterns()
+	for _, treePath := range treePaths {
+		for _, pat := range patterns {
+			if pat.Match(strings.ToLower(treePath)) {
+				return models.ErrFilePathProtected{
+					Path: treePath,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// applySuggestionsToContent replaces the suggested lines of content, checking
+// the lines still read as they did when the suggestions were made.
+func applySuggestionsToContent(treePath, content string, suggestions []*Suggestion) (string, error) {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	eol := "\n"
+	if len(lines) > 0 && strings.HasSuffix(lines[0], "\r\n") {
+		eol = "\r\n"
+	}
+
+	sorted := make([]*Suggestion, len(suggestions))
+	copy(sorted, suggestions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Line < sorted[j].Line
+	})
+	for i := 1; i < len(sorted); i++ {
+		prev := sorted[i-1]
+		if sorted[i].Line < prev.Line+int64(len(prev.Original)) || sorted[i].Line == prev.Line {
+			return "", models.ErrSuggestionsOverlap{TreePath: treePath, Line: sorted[i].Line}
+		}
+	}
+
+	// Apply from the bottom up so that the line numbers of earlier suggestions stay valid
+	for i := len(sorted) - 1; i >= 0; i-- {
+		suggestion := sorted[i]
+		start := int(suggestion.Line) - 1
+		end := start + len(suggestion.Original)
+		if start < 0 || end > len(lines) {
+			return "", models.ErrSuggestionOutdated{TreePath: treePath, Line: suggestion.Line}
+		}
+
+		for j, original := range suggestion.Original {
+			line := strings.TrimSuffix(strings.TrimSuffix(lines[start+j], "\n"), "\r")
+			if line != strings.TrimSuffix(original, "\r") {
+				return "", models.ErrSuggestionOutdated{TreePath: treePath, Line: suggestion.Line}
+			}
+		}
+		// Keep a missing newline at the end of file as it was
+		lastEOL := eol
+		if end == len(lines) && end > 0 && !strings.HasSuffix(lines[end-1], "\n") {
+			lastEOL = ""
+		}
+
+		replacement := make([]string, len(suggestion.Content))
+		for j, line := range suggestion.Content {
+			if j == len(suggestion.Content)-1 {
+				replacement[j] = line + lastEOL
+			} else {
+				replacement[j] = line + eol
+			}
+		}
+
+		lines = append(lines[:start], append(replacement, lines[end:]...)...)
+	}
+
+	return strings.Join(lines, ""), nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySuggestionsToContent(t *testing.T) {
+	content := "a\nb\nc\nd\n"
+
+	t.Run("Single suggestion", func(t *testing.T) {
+		result, err := applySuggestionsToContent("file", content, []*Suggestion{
+			{Line: 2, Original: []string{"b"}, Content: []string{"B", "B2"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "a\nB\nB2\nc\nd\n", result)
+	})
+
+	t.Run("Several suggestions", func(t *testing.T) {
+		result, err := applySuggestionsToContent("file", content, []*Suggestion{
+			{Line: 4, Original: []string{"d"}, Content: []string{}},
+			{Line: 1, Original: []string{"a"}, Content: []string{"A"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "A\nb\nc\n", result)
+	})
+
+	t.Run("Keep line endings", func(t *testing.T) {
+		result, err := applySuggestionsToContent("file", "a\r\nb", []*Suggestion{
+			{Line: 2, Original: []string{"b"}, Content: []string{"x", "y"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "a\r\nx\r\ny", result)
+	})
+
+	t.Run("Outdated", func(t *testing.T) {
+		_, err := applySuggestionsToContent("file", content, []*Suggestion{
+			{Line: 2, Original: []string{"x"}, Content: []string{"y"}},
+		})
+		assert.True(t, models.IsErrSuggestionOutdated(err))
+
+		_, err = applySuggestionsToContent("file", content, []*Suggestion{
+			{Line: 5, Original: []string{"d"}, Content: []string{"y"}},
+		})
+		assert.True(t, models.IsErrSuggestionOutdated(err))
+	})
+
+	t.Run("Overlap", func(t *testing.T) {
+		_, err := applySuggestionsToContent("file", content, []*Suggestion{
+			{Line: 2, Original: []string{"b", "c"}, Content: []string{"x"}},
+			{Line: 3, Original: []string{"c"}, Content: []string{"y"}},
+		})
+		assert.True(t, models.IsErrSuggestionsOverlap(err))
+	})
+}
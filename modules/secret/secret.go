@@ -5,8 +5,13 @@
 package secret
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"errors"
 )
 
 // New creats a new secret
@@ -31,3 +36,62 @@ func randomString(len int64) (string, error) {
 	b, err := randomBytes(len)
 	return base64.URLEncoding.EncodeToString(b), err
 }
+
+// AesEncrypt encrypts text with the given key using AES-GCM.
+// The returned ciphertext is prefixed with the random nonce.
+func AesEncrypt(key, text []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomBytes(int64(gcm.NonceSize()))
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, text, nil), nil
+}
+
+// AesDecrypt decrypts text previously encrypted by AesEncrypt with the given key.
+func AesDecrypt(key, text []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(text) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := text[:gcm.NonceSize()], text[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// EncryptSecret encrypts a string with the given key into a hex string
+func EncryptSecret(key, str string) (string, error) {
+	keyHash := sha256.Sum256([]byte(key))
+	ciphertext, err := AesEncrypt(keyHash[:], []byte(str))
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(ciphertext), nil
+}
+
+// DecryptSecret decrypts a hex string previously encrypted by EncryptSecret with the given key
+func DecryptSecret(key, encrypted string) (string, error) {
+	ciphertext, err := hex.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	keyHash := sha256.Sum256([]byte(key))
+	plaintext, err := AesDecrypt(keyHash[:], ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -79,6 +79,15 @@ func newGit() {
 		git.GlobalCommandArgs = append(git.GlobalCommandArgs, "-c", "credential.helper=")
 	}
 
+	if Repository.Signing.SigningFormat == git.SigningFormatSSH && Repository.Signing.SigningKey != "" &&
+		Repository.Signing.SigningKey != "default" && Repository.Signing.SigningKey != "none" {
+		if git.CheckGitVersionConstraint(">= 2.34") != nil {
+			log.Fatal("Signing commits with an SSH key requires git >= 2.34")
+		}
+		// The configured signing key is an SSH key whatever the gpg.format of the git config is
+		git.GlobalCommandArgs = append(git.GlobalCommandArgs, "-c", "gpg.format=ssh")
+	}
+
 	var format = "Git Version: %s"
 	var args = []interface{}{version.Original()}
 	// Since git wire protocol has been released from git v2.18
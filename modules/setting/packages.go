@@ -0,0 +1,69 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path"
+	"path/filepath"
+)
+
+var (
+	// Packages settings
+	Packages = struct {
+		Enabled   bool
+		StoreType string
+		Path      string
+		Minio     struct {
+			Endpoint        string
+			AccessKeyID     string
+			SecretAccessKey string
+			UseSSL          bool
+			Bucket          string
+			Location        string
+			BasePath        string
+		}
+		MaxFileSize int64
+	}{
+		Enabled:   true,
+		StoreType: "local",
+		Minio: struct {
+			Endpoint        string
+			AccessKeyID     string
+			SecretAccessKey string
+			UseSSL          bool
+			Bucket          string
+			Location        string
+			BasePath        string
+		}{},
+		MaxFileSize: -1,
+	}
+)
+
+func newPackagesService() {
+	sec := Cfg.Section("packages")
+	Packages.Enabled = sec.Key("ENABLED").MustBool(true)
+	Packages.StoreType = sec.Key("STORE_TYPE").MustString("local")
+	switch Packages.StoreType {
+	case "local":
+		Packages.Path = sec.Key("PATH").MustString(path.Join(AppDataPath, "packages"))
+		if !filepath.IsAbs(Packages.Path) {
+			Packages.Path = path.Join(AppWorkPath, Packages.Path)
+		}
+	case "minio":
+		Packages.Minio.Endpoint = sec.Key("MINIO_ENDPOINT").MustString("localhost:9000")
+		Packages.Minio.AccessKeyID = sec.Key("MINIO_ACCESS_KEY_ID").MustString("")
+		Packages.Minio.SecretAccessKey = sec.Key("MINIO_SECRET_ACCESS_KEY").MustString("")
+		Packages.Minio.Bucket = sec.Key("MINIO_BUCKET").MustString("gitea")
+		Packages.Minio.Location = sec.Key("MINIO_LOCATION").MustString("us-east-1")
+		Packages.Minio.BasePath = sec.Key("MINIO_BASE_PATH").MustString("packages/")
+		Packages.Minio.UseSSL = sec.Key("MINIO_USE_SSL").MustBool(false)
+	}
+
+	// MAX_FILE_SIZE is given in MB, -1 means unlimited
+	Packages.MaxFileSize = sec.Key("MAX_FILE_SIZE").MustInt64(-1)
+	if Packages.MaxFileSize > 0 {
+		Packages.MaxFileSize *= 1024 * 1024
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// RateLimitBudget is a token bucket of requests, it holds up to Burst requests
+// and is refilled with Requests requests per Period
+type RateLimitBudget struct {
+	Enabled  bool
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+var (
+	// RateLimit settings
+	RateLimit = struct {
+		Enabled      bool
+		ExemptUsers  []string
+		ExemptIPs    []string     `ini:"EXEMPT_IPS"`
+		ExemptIPNets []*net.IPNet `ini:"-"`
+		ExemptAdmins bool
+
+		API   RateLimitBudget `ini:"-"`
+		Git   RateLimitBudget `ini:"-"`
+		Login RateLimitBudget `ini:"-"`
+	}{
+		Enabled: false,
+		API: RateLimitBudget{
+			Enabled:  true,
+			Requests: 5000,
+			Period:   time.Hour,
+		},
+		Git: RateLimitBudget{
+			Enabled:  true,
+			Requests: 1000,
+			Period:   time.Hour,
+		},
+		Login: RateLimitBudget{
+			Enabled:  true,
+			Requests: 10,
+			Period:   time.Minute,
+		},
+	}
+)
+
+func newRateLimitService() {
+	if err := Cfg.Section("rate_limit").MapTo(&RateLimit); err != nil {
+		log.Fatal("Failed to map Rate Limit settings: %v", err)
+	}
+
+	RateLimit.ExemptIPNets = make([]*net.IPNet, 0, len(RateLimit.ExemptIPs))
+	for _, exemptIP := range RateLimit.ExemptIPs {
+		exemptIP = strings.TrimSpace(exemptIP)
+		if !strings.Contains(exemptIP, "/") {
+			if strings.Contains(exemptIP, ":") {
+				exemptIP += "/128"
+			} else {
+				exemptIP += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(exemptIP)
+		if err != nil {
+			log.Fatal("Invalid [rate_limit] EXEMPT_IPS entry %q: %v", exemptIP, err)
+		}
+		RateLimit.ExemptIPNets = append(RateLimit.ExemptIPNets, ipNet)
+	}
+
+	for name, budget := range map[string]*RateLimitBudget{
+		"api":   &RateLimit.API,
+		"git":   &RateLimit.Git,
+		"login": &RateLimit.Login,
+	} {
+		if err := Cfg.Section("rate_limit." + name).MapTo(budget); err != nil {
+			log.Fatal("Failed to map Rate Limit %s settings: %v", name, err)
+		}
+		if budget.Burst <= 0 {
+			budget.Burst = budget.Requests
+		}
+		if budget.Enabled && (budget.Requests <= 0 || budget.Period <= 0) {
+			log.Fatal("Invalid [rate_limit.%s] budget: REQUESTS and PERIOD must be positive", name)
+		}
+	}
+
+	if RateLimit.Enabled {
+		log.Info("Rate Limiting Enabled")
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"path/filepath"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// enumerates the actions taken on a registration matched by a check
+const (
+	RegistrationActionNone   = "none"
+	RegistrationActionFlag   = "flag"
+	RegistrationActionReject = "reject"
+)
+
+var (
+	// Registration settings
+	Registration = struct {
+		DisposableEmailDomains     string
+		DisposableEmailDomainsFile string
+		ReviewNewAccounts          bool
+		ProbationPeriod            time.Duration
+		ProbationLimitPeriod       time.Duration
+		ProbationRepoLimit         int
+		ProbationIssueLimit        int
+	}{
+		DisposableEmailDomains: RegistrationActionNone,
+		ProbationPeriod:        0,
+		ProbationLimitPeriod:   time.Hour,
+		ProbationRepoLimit:     1,
+		ProbationIssueLimit:    5,
+	}
+)
+
+func newRegistrationService() {
+	sec := Cfg.Section("service.registration")
+	if err := sec.MapTo(&Registration); err != nil {
+		log.Fatal("Failed to map Registration settings: %v", err)
+	}
+
+	Registration.DisposableEmailDomains = sec.Key("DISPOSABLE_EMAIL_DOMAINS").In(RegistrationActionNone,
+		[]string{RegistrationActionNone, RegistrationActionFlag, RegistrationActionReject})
+	if len(Registration.DisposableEmailDomainsFile) > 0 && !filepath.IsAbs(Registration.DisposableEmailDomainsFile) {
+		Registration.DisposableEmailDomainsFile = filepath.Join(CustomPath, Registration.DisposableEmailDomainsFile)
+	}
+	if Registration.ProbationLimitPeriod <= 0 {
+		log.Fatal("Invalid [service.registration] PROBATION_LIMIT_PERIOD: must be positive")
+	}
+}
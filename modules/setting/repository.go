@@ -86,6 +86,7 @@ var (
 			SigningKey        string
 			SigningName       string
 			SigningEmail      string
+			SigningFormat     string
 			InitialCommit     []string
 			CRUDActions       []string `ini:"CRUD_ACTIONS"`
 			Merges            []string
@@ -213,6 +214,7 @@ var (
 			SigningKey        string
 			SigningName       string
 			SigningEmail      string
+			SigningFormat     string
 			InitialCommit     []string
 			CRUDActions       []string `ini:"CRUD_ACTIONS"`
 			Merges            []string
@@ -222,6 +224,7 @@ var (
 			SigningKey:        "default",
 			SigningName:       "",
 			SigningEmail:      "",
+			SigningFormat:     "openpgp",
 			InitialCommit:     []string{"always"},
 			CRUDActions:       []string{"pubkey", "twofa", "parentsigned"},
 			Merges:            []string{"pubkey", "twofa", "basesigned", "commitssigned"},
@@ -277,6 +280,11 @@ func newRepository() {
 		Repository.Signing.DefaultTrustModel = "collaborator"
 	}
 
+	Repository.Signing.SigningFormat = strings.ToLower(strings.TrimSpace(Repository.Signing.SigningFormat))
+	if Repository.Signing.SigningFormat != "openpgp" && Repository.Signing.SigningFormat != "ssh" {
+		log.Fatal("Invalid [repository.signing] SIGNING_FORMAT: %s, it must be openpgp or ssh", Repository.Signing.SigningFormat)
+	}
+
 	// Handle preferred charset orders
 	preferred := make([]string, 0, len(Repository.DetectedCharsetsOrder))
 	for _, charset := range Repository.DetectedCharsetsOrder {
@@ -26,8 +26,8 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/user"
 
-	shellquote "github.com/kballard/go-shellquote"
 	"github.com/unknwon/com"
+	gossh "golang.org/x/crypto/ssh"
 	ini "gopkg.in/ini.v1"
 	"strk.kbt.io/projects/go/libravatar"
 )
@@ -59,6 +59,7 @@ const (
 const (
 	ImageCaptcha = "image"
 	ReCaptcha    = "recaptcha"
+	HCaptcha     = "hcaptcha"
 )
 
 // settings
@@ -102,34 +103,38 @@ var (
 	StaticURLPrefix      string
 
 	SSH = struct {
-		Disabled                 bool           `ini:"DISABLE_SSH"`
-		StartBuiltinServer       bool           `ini:"START_SSH_SERVER"`
-		BuiltinServerUser        string         `ini:"BUILTIN_SSH_SERVER_USER"`
-		Domain                   string         `ini:"SSH_DOMAIN"`
-		Port                     int            `ini:"SSH_PORT"`
-		ListenHost               string         `ini:"SSH_LISTEN_HOST"`
-		ListenPort               int            `ini:"SSH_LISTEN_PORT"`
-		RootPath                 string         `ini:"SSH_ROOT_PATH"`
-		ServerCiphers            []string       `ini:"SSH_SERVER_CIPHERS"`
-		ServerKeyExchanges       []string       `ini:"SSH_SERVER_KEY_EXCHANGES"`
-		ServerMACs               []string       `ini:"SSH_SERVER_MACS"`
-		KeyTestPath              string         `ini:"SSH_KEY_TEST_PATH"`
-		KeygenPath               string         `ini:"SSH_KEYGEN_PATH"`
-		AuthorizedKeysBackup     bool           `ini:"SSH_AUTHORIZED_KEYS_BACKUP"`
-		MinimumKeySizeCheck      bool           `ini:"-"`
-		MinimumKeySizes          map[string]int `ini:"-"`
-		CreateAuthorizedKeysFile bool           `ini:"SSH_CREATE_AUTHORIZED_KEYS_FILE"`
-		ExposeAnonymous          bool           `ini:"SSH_EXPOSE_ANONYMOUS"`
+		Disabled                  bool              `ini:"DISABLE_SSH"`
+		StartBuiltinServer        bool              `ini:"START_SSH_SERVER"`
+		BuiltinServerUser         string            `ini:"BUILTIN_SSH_SERVER_USER"`
+		Domain                    string            `ini:"SSH_DOMAIN"`
+		Port                      int               `ini:"SSH_PORT"`
+		ListenHost                string            `ini:"SSH_LISTEN_HOST"`
+		ListenPort                int               `ini:"SSH_LISTEN_PORT"`
+		RootPath                  string            `ini:"SSH_ROOT_PATH"`
+		ServerCiphers             []string          `ini:"SSH_SERVER_CIPHERS"`
+		ServerKeyExchanges        []string          `ini:"SSH_SERVER_KEY_EXCHANGES"`
+		ServerMACs                []string          `ini:"SSH_SERVER_MACS"`
+		KeyTestPath               string            `ini:"SSH_KEY_TEST_PATH"`
+		KeygenPath                string            `ini:"SSH_KEYGEN_PATH"`
+		AuthorizedKeysBackup      bool              `ini:"SSH_AUTHORIZED_KEYS_BACKUP"`
+		MinimumKeySizeCheck       bool              `ini:"-"`
+		MinimumKeySizes           map[string]int    `ini:"-"`
+		CreateAuthorizedKeysFile  bool              `ini:"SSH_CREATE_AUTHORIZED_KEYS_FILE"`
+		ExposeAnonymous           bool              `ini:"SSH_EXPOSE_ANONYMOUS"`
+		TrustedUserCAKeys         []string          `ini:"SSH_TRUSTED_USER_CA_KEYS"`
+		TrustedUserCAKeysParsed   []gossh.PublicKey `ini:"-"`
+		AuthorizedPrincipalsAllow []string          `ini:"SSH_AUTHORIZED_PRINCIPALS_ALLOW"`
 	}{
-		Disabled:           false,
-		StartBuiltinServer: false,
-		Domain:             "",
-		Port:               22,
-		ServerCiphers:      []string{"aes128-ctr", "aes192-ctr", "aes256-ctr", "aes128-gcm@openssh.com", "arcfour256", "arcfour128"},
-		ServerKeyExchanges: []string{"diffie-hellman-group1-sha1", "diffie-hellman-group14-sha1", "ecdh-sha2-nistp256", "ecdh-sha2-nistp384", "ecdh-sha2-nistp521", "curve25519-sha256@libssh.org"},
-		ServerMACs:         []string{"hmac-sha2-256-etm@openssh.com", "hmac-sha2-256", "hmac-sha1", "hmac-sha1-96"},
-		KeygenPath:         "ssh-keygen",
-		MinimumKeySizes:    map[string]int{"ed25519": 256, "ecdsa": 256, "rsa": 2048, "dsa": 1024},
+		Disabled:                  false,
+		StartBuiltinServer:        false,
+		Domain:                    "",
+		Port:                      22,
+		ServerCiphers:             []string{"aes128-ctr", "aes192-ctr", "aes256-ctr", "aes128-gcm@openssh.com", "arcfour256", "arcfour128"},
+		ServerKeyExchanges:        []string{"diffie-hellman-group1-sha1", "diffie-hellman-group14-sha1", "ecdh-sha2-nistp256", "ecdh-sha2-nistp384", "ecdh-sha2-nistp521", "curve25519-sha256@libssh.org"},
+		ServerMACs:                []string{"hmac-sha2-256-etm@openssh.com", "hmac-sha2-256", "hmac-sha1", "hmac-sha1-96"},
+		KeygenPath:                "ssh-keygen",
+		MinimumKeySizes:           map[string]int{"ed25519": 256, "ecdsa": 256, "rsa": 2048, "dsa": 1024},
+		AuthorizedPrincipalsAllow: []string{"username"},
 	}
 
 	// Security settings
@@ -326,18 +331,20 @@ var (
 		InvalidateRefreshTokens    bool
 		JWTSecretBytes             []byte `ini:"-"`
 		JWTSecretBase64            string `ini:"JWT_SECRET"`
+		JWTSigningPrivateKeyFile   string `ini:"JWT_SIGNING_PRIVATE_KEY_FILE"`
 		MaxTokenLength             int
 	}{
 		Enable:                     true,
 		AccessTokenExpirationTime:  3600,
 		RefreshTokenExpirationTime: 730,
-		InvalidateRefreshTokens:    false,
+		InvalidateRefreshTokens:    true,
+		JWTSigningPrivateKeyFile:   "jwt/private.pem",
 		MaxTokenLength:             math.MaxInt16,
 	}
 
+	// U2F holds the AppID of the legacy FIDO U2F API which security keys registered before WebAuthn was supported are scoped to
 	U2F = struct {
-		AppID         string
-		TrustedFacets []string
+		AppID string
 	}{}
 
 	// Metrics settings
@@ -691,12 +698,29 @@ func NewContext() {
 	SSH.CreateAuthorizedKeysFile = sec.Key("SSH_CREATE_AUTHORIZED_KEYS_FILE").MustBool(true)
 	SSH.ExposeAnonymous = sec.Key("SSH_EXPOSE_ANONYMOUS").MustBool(false)
 
+	SSH.TrustedUserCAKeysParsed = make([]gossh.PublicKey, 0, len(SSH.TrustedUserCAKeys))
+	for _, caKey := range SSH.TrustedUserCAKeys {
+		pubKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(caKey))
+		if err != nil {
+			log.Fatal("Failed to parse SSH_TRUSTED_USER_CA_KEYS key %q: %v", caKey, err)
+		}
+		SSH.TrustedUserCAKeysParsed = append(SSH.TrustedUserCAKeysParsed, pubKey)
+	}
+	for _, allow := range SSH.AuthorizedPrincipalsAllow {
+		if allow != "username" && allow != "email" {
+			log.Fatal("Invalid SSH_AUTHORIZED_PRINCIPALS_ALLOW value: %s, it must be username or email", allow)
+		}
+	}
+
 	newLFSService()
 
 	if err = Cfg.Section("oauth2").MapTo(&OAuth2); err != nil {
 		log.Fatal("Failed to OAuth2 settings: %v", err)
 		return
 	}
+	if !filepath.IsAbs(OAuth2.JWTSigningPrivateKeyFile) {
+		OAuth2.JWTSigningPrivateKeyFile = filepath.Join(AppDataPath, OAuth2.JWTSigningPrivateKeyFile)
+	}
 
 	if OAuth2.Enable {
 		OAuth2.JWTSecretBytes = make([]byte, 32)
@@ -762,6 +786,7 @@ func NewContext() {
 	}
 
 	newAttachmentService()
+	newPackagesService()
 
 	timeFormatKey := Cfg.Section("time").Key("FORMAT").MustString("")
 	if timeFormatKey != "" {
@@ -933,7 +958,6 @@ func NewContext() {
 	newMarkup()
 
 	sec = Cfg.Section("U2F")
-	U2F.TrustedFacets, _ = shellquote.Split(sec.Key("TRUSTED_FACETS").MustString(strings.TrimRight(AppURL, "/")))
 	U2F.AppID = sec.Key("APP_ID").MustString(strings.TrimRight(AppURL, "/"))
 
 	UI.ReactionsMap = make(map[string]bool)
@@ -1019,6 +1043,8 @@ func NewServices() {
 	NewLogServices(false)
 	ensureLFSDirectory()
 	newCacheService()
+	newRateLimitService()
+	newRegistrationService()
 	newSessionService()
 	newCORSService()
 	newMailService()
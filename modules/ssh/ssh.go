@@ -29,7 +29,10 @@ import (
 
 type contextKey string
 
-const giteaKeyID = contextKey("gitea-key-id")
+const (
+	giteaKeyID  = contextKey("gitea-key-id")
+	giteaUserID = contextKey("gitea-user-id")
+)
 
 func getExitStatusFromError(err error) int {
 	if err == nil {
@@ -56,13 +59,17 @@ func getExitStatusFromError(err error) int {
 }
 
 func sessionHandler(session ssh.Session) {
-	keyID := session.Context().Value(giteaKeyID).(int64)
+	// users authenticated by a certificate have no stored key
+	keyArg := "key-" + com.ToStr(session.Context().Value(giteaKeyID))
+	if userID, ok := session.Context().Value(giteaUserID).(int64); ok {
+		keyArg = "user-" + com.ToStr(userID)
+	}
 
 	command := session.RawCommand()
 
 	log.Trace("SSH: Payload: %v", command)
 
-	args := []string{"serv", "key-" + com.ToStr(keyID), "--config=" + setting.CustomConf}
+	args := []string{"serv", keyArg, "--config=" + setting.CustomConf}
 	log.Trace("SSH: Arguments: %v", args)
 	cmd := exec.Command(setting.AppPath, args...)
 	cmd.Env = append(
@@ -136,6 +143,21 @@ func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 		return false
 	}
 
+	if cert, ok := key.(*gossh.Certificate); ok {
+		user, principal, err := models.GetUserByCertificate(cert, ctx.RemoteAddr())
+		if err != nil {
+			if models.IsErrCertificateNotAuthorized(err) {
+				log.Warn("SSH certificate %s (serial %d) from %s is not authorized: %v", cert.KeyId, cert.Serial, ctx.RemoteAddr(), err)
+			} else {
+				log.Error("GetUserByCertificate: %v", err)
+			}
+			return false
+		}
+		log.Trace("SSH: certificate %s (serial %d) authenticated %s by principal %s", cert.KeyId, cert.Serial, user.Name, principal)
+		ctx.SetValue(giteaUserID, user.ID)
+		return true
+	}
+
 	pkey, err := models.SearchPublicKeyByContent(strings.TrimSpace(string(gossh.MarshalAuthorizedKey(key))))
 	if err != nil {
 		log.Error("SearchPublicKeyByContent: %v", err)
@@ -59,6 +59,9 @@ var (
 
 	// LFS represents lfs storage
 	LFS ObjectStorage
+
+	// Packages represents packages storage
+	Packages ObjectStorage
 )
 
 // Init init the stoarge
@@ -67,7 +70,11 @@ func Init() error {
 		return err
 	}
 
-	return initLFS()
+	if err := initLFS(); err != nil {
+		return err
+	}
+
+	return initPackages()
 }
 
 func initAttachments() error {
@@ -125,3 +132,35 @@ func initLFS() error {
 
 	return nil
 }
+
+func initPackages() error {
+	if !setting.Packages.Enabled {
+		return nil
+	}
+
+	var err error
+	switch setting.Packages.StoreType {
+	case "local":
+		Packages, err = NewLocalStorage(setting.Packages.Path)
+	case "minio":
+		minio := setting.Packages.Minio
+		Packages, err = NewMinioStorage(
+			context.Background(),
+			minio.Endpoint,
+			minio.AccessKeyID,
+			minio.SecretAccessKey,
+			minio.Bucket,
+			minio.Location,
+			minio.BasePath,
+			minio.UseSSL,
+		)
+	default:
+		return fmt.Errorf("Unsupported packages store type: %s", setting.Packages.StoreType)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
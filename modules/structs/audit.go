@@ -0,0 +1,27 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// AuditEvent represents a security relevant event of the audit log
+type AuditEvent struct {
+	ID     int64  `json:"id"`
+	Action string `json:"action"`
+	// the id is 0 if the actor is not a user, such as a failed sign in with an unknown user name
+	ActorID   int64  `json:"actor_id"`
+	ActorName string `json:"actor_name"`
+	// the user or organization whose account, organization or repository is affected
+	OwnerID     int64  `json:"owner_id"`
+	OwnerName   string `json:"owner_name"`
+	RepoID      int64  `json:"repo_id"`
+	RepoName    string `json:"repo_name"`
+	TargetID    int64  `json:"target_id"`
+	TargetName  string `json:"target_name"`
+	IPAddress   string `json:"ip_address"`
+	Description string `json:"description"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
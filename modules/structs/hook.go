@@ -93,7 +93,7 @@ type PayloadCommit struct {
 	Modified  []string  `json:"modified"`
 }
 
-// PayloadCommitVerification represents the GPG verification of a commit
+// PayloadCommitVerification represents the GPG or SSH signature verification of a commit
 type PayloadCommitVerification struct {
 	Verified  bool         `json:"verified"`
 	Reason    string       `json:"reason"`
@@ -114,6 +114,14 @@ type EditDeadlineOption struct {
 	Deadline *time.Time `json:"due_date"`
 }
 
+// TransferIssueOption options for moving an issue to another repository
+type TransferIssueOption struct {
+	// required: true
+	NewOwner string `json:"new_owner" binding:"Required"`
+	// required: true
+	NewRepo string `json:"new_repo" binding:"Required"`
+}
+
 // IssueDeadline represents an issue deadline
 // swagger:model
 type IssueDeadline struct {
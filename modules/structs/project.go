@@ -0,0 +1,84 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// Project represents a project board of a repository, a user or an organization
+type Project struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// enum: individual,repository,organization
+	Type string `json:"type"`
+	// Owner of a project of type individual or organization
+	Owner *User `json:"owner,omitempty"`
+	// Repository of a project of type repository
+	Repo    *RepositoryMeta `json:"repository,omitempty"`
+	State   StateType       `json:"state"`
+	HTMLURL string          `json:"html_url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	Closed *time.Time `json:"closed_at"`
+}
+
+// CreateProjectOption options for creating a project
+type CreateProjectOption struct {
+	// required: true
+	Title       string `json:"title" binding:"Required;MaxSize(100)"`
+	Description string `json:"description"`
+	// boards to create the project with
+	// enum: none,basic_kanban,bug_triage
+	BoardType string `json:"board_type"`
+}
+
+// EditProjectOption options for editing a project
+type EditProjectOption struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	// enum: open,closed
+	State *string `json:"state"`
+}
+
+// ProjectBoard represents a column of a project board
+type ProjectBoard struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateProjectBoardOption options for creating or renaming a column of a project board
+type CreateProjectBoardOption struct {
+	// required: true
+	Title string `json:"title" binding:"Required;MaxSize(100)"`
+}
+
+// ProjectCard represents an issue or pull request on a project board
+type ProjectCard struct {
+	// ID of the column of the card, 0 if it is not assigned to one
+	BoardID int64  `json:"board_id"`
+	Issue   *Issue `json:"issue"`
+}
+
+// AddProjectCardOption options for putting an issue or pull request on a project board
+type AddProjectCardOption struct {
+	// ID of the issue or pull request
+	// required: true
+	IssueID int64 `json:"issue_id" binding:"Required"`
+	// ID of the column to put the card in, by default it is not assigned to one
+	BoardID int64 `json:"board_id"`
+}
+
+// MoveProjectCardOption options for moving a card to another column of its project board
+type MoveProjectCardOption struct {
+	// ID of the column, 0 to take the card out of all columns
+	BoardID int64 `json:"board_id"`
+}
@@ -30,6 +30,7 @@ const (
 type PullReview struct {
 	ID                int64           `json:"id"`
 	Reviewer          *User           `json:"user"`
+	ReviewerTeam      *Team           `json:"team"`
 	State             ReviewStateType `json:"state"`
 	Body              string          `json:"body"`
 	CommitID          string          `json:"commit_id"`
@@ -61,6 +62,10 @@ type PullReviewComment struct {
 	DiffHunk     string `json:"diff_hunk"`
 	LineNum      uint64 `json:"position"`
 	OldLineNum   uint64 `json:"original_position"`
+	// first line of a multi-line comment on the new file, or 0
+	StartLineNum uint64 `json:"start_position"`
+	// first line of a multi-line comment on the old file, or 0
+	OldStartLineNum uint64 `json:"original_start_position"`
 
 	HTMLURL     string `json:"html_url"`
 	HTMLPullURL string `json:"pull_request_url"`
@@ -83,6 +88,10 @@ type CreatePullReviewComment struct {
 	OldLineNum int64 `json:"old_position"`
 	// if comment to new file line or 0
 	NewLineNum int64 `json:"new_position"`
+	// first line of a multi-line comment to old file lines or 0
+	OldStartLineNum int64 `json:"old_start_position"`
+	// first line of a multi-line comment to new file lines or 0
+	NewStartLineNum int64 `json:"new_start_position"`
 }
 
 // SubmitPullReviewOptions are options to submit a pending pull review
@@ -90,3 +99,15 @@ type SubmitPullReviewOptions struct {
 	Event ReviewStateType `json:"event"`
 	Body  string          `json:"body"`
 }
+
+// PullReviewRequestOptions are options to add or remove pull review requests
+type PullReviewRequestOptions struct {
+	Reviewers     []string `json:"reviewers"`
+	TeamReviewers []string `json:"team_reviewers"`
+}
+
+// PullReviewRequests represents the pending review requests of a pull request
+type PullReviewRequests struct {
+	Users []*User `json:"users"`
+	Teams []*Team `json:"teams"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// PushMirror represents a remote the repository is pushed to
+type PushMirror struct {
+	ID            int64  `json:"id"`
+	RemoteAddress string `json:"remote_address"`
+	Interval      string `json:"interval"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created"`
+	// swagger:strfmt date-time
+	LastUpdate *time.Time `json:"last_update"`
+	LastError  string     `json:"last_error"`
+}
+
+// CreatePushMirrorOption options when creating a push mirror
+type CreatePushMirrorOption struct {
+	// URL of the remote repository, only http(s) is supported
+	//
+	// required: true
+	RemoteAddress  string `json:"remote_address" binding:"Required"`
+	RemoteUsername string `json:"remote_username"`
+	RemotePassword string `json:"remote_password"`
+	// Interval between scheduled pushes (e.g. 8h0m0s), 0 disables scheduled pushes
+	Interval string `json:"interval"`
+}
@@ -4,6 +4,8 @@
 
 package structs
 
+import "time"
+
 // Tag represents a repository tag
 type Tag struct {
 	Name       string      `json:"name"`
@@ -30,3 +32,30 @@ type AnnotatedTagObject struct {
 	URL  string `json:"url"`
 	SHA  string `json:"sha"`
 }
+
+// TagProtection represents a tag protection
+type TagProtection struct {
+	ID                 int64    `json:"id"`
+	NamePattern        string   `json:"name_pattern"`
+	AllowlistUsernames []string `json:"allowlist_usernames"`
+	AllowlistTeams     []string `json:"allowlist_teams"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateTagProtectionOption options for creating a tag protection
+type CreateTagProtectionOption struct {
+	// required: true
+	NamePattern        string   `json:"name_pattern" binding:"Required"`
+	AllowlistUsernames []string `json:"allowlist_usernames"`
+	AllowlistTeams     []string `json:"allowlist_teams"`
+}
+
+// EditTagProtectionOption options for editing a tag protection
+type EditTagProtectionOption struct {
+	NamePattern        *string  `json:"name_pattern"`
+	AllowlistUsernames []string `json:"allowlist_usernames"`
+	AllowlistTeams     []string `json:"allowlist_teams"`
+}
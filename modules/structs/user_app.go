@@ -12,10 +12,12 @@ import (
 // AccessToken represents an API access token.
 // swagger:response AccessToken
 type AccessToken struct {
-	ID             int64  `json:"id"`
-	Name           string `json:"name"`
-	Token          string `json:"sha1"`
-	TokenLastEight string `json:"token_last_eight"`
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Token          string   `json:"sha1"`
+	TokenLastEight string   `json:"token_last_eight"`
+	Scopes         []string `json:"scopes"`
+	Repositories   []string `json:"repositories"`
 }
 
 // AccessTokenList represents a list of API access token.
@@ -25,7 +27,9 @@ type AccessTokenList []*AccessToken
 // CreateAccessTokenOption options when create access token
 // swagger:parameters userCreateToken
 type CreateAccessTokenOption struct {
-	Name string `json:"name" binding:"Required"`
+	Name         string   `json:"name" binding:"Required"`
+	Scopes       []string `json:"scopes"`
+	Repositories []string `json:"repositories"`
 }
 
 // CreateOAuth2ApplicationOptions holds options to create an oauth2 application
@@ -0,0 +1,15 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// BlockedUser represents a user blocked by a user or an organization
+type BlockedUser struct {
+	User *User  `json:"user"`
+	Note string `json:"note"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -25,6 +25,8 @@ func handleCreateError(owner *models.User, err error, name string) error {
 	switch {
 	case models.IsErrReachLimitOfRepo(err):
 		return fmt.Errorf("You have already reached your limit of %d repositories", owner.MaxCreationLimit())
+	case models.IsErrProbationLimit(err):
+		return fmt.Errorf("New accounts can only create %d repositories in a short time, please try again later", err.(models.ErrProbationLimit).Limit)
 	case models.IsErrRepoAlreadyExist(err):
 		return errors.New("The repository name is already used")
 	case models.IsErrNameReserved(err):
@@ -55,18 +57,13 @@ func runMigrateTask(t *models.Task) (err error) {
 			log.Error("FinishMigrateTask failed: %s", err.Error())
 		}
 
+		// the migrated data is kept, so that the migration can be resumed or the repository be deleted
 		t.EndTime = timeutil.TimeStampNow()
 		t.Status = structs.TaskStatusFailed
 		t.Errors = err.Error()
 		if err := t.UpdateCols("status", "errors", "end_time"); err != nil {
 			log.Error("Task UpdateCols failed: %s", err.Error())
 		}
-
-		if t.Repo != nil {
-			if errDelete := models.DeleteRepository(t.Doer, t.OwnerID, t.Repo.ID); errDelete != nil {
-				log.Error("DeleteRepository: %v", errDelete)
-			}
-		}
 	}()
 
 	if err := t.LoadRepo(); err != nil {
@@ -96,8 +93,14 @@ func runMigrateTask(t *models.Task) (err error) {
 		return err
 	}
 
+	var progress *migration.MigrateProgress
+	progress, err = t.MigrateProgress()
+	if err != nil {
+		return err
+	}
+
 	opts.MigrateToRepoID = t.RepoID
-	repo, err := migrations.MigrateRepository(graceful.GetManager().HammerContext(), t.Doer, t.Owner.Name, *opts)
+	repo, err := migrations.MigrateRepositoryWithProgress(graceful.GetManager().HammerContext(), t.Doer, t.Owner.Name, *opts, progress, t.UpdateMigrateProgress)
 	if err == nil {
 		log.Trace("Repository migrated [%d]: %s/%s", repo.ID, t.Owner.Name, repo.Name)
 		return nil
@@ -63,6 +63,21 @@ func MigrateRepository(doer, u *models.User, opts base.MigrateOptions) error {
 	return taskQueue.Push(task)
 }
 
+// RetryMigrateTask queues a failed migrate task again, the migration is resumed from its last checkpoint
+func RetryMigrateTask(t *models.Task) error {
+	if t.Status != structs.TaskStatusFailed {
+		return fmt.Errorf("Task %d has not failed", t.ID)
+	}
+
+	t.Status = structs.TaskStatusQueue
+	t.Errors = ""
+	if err := t.UpdateCols("status", "errors"); err != nil {
+		return err
+	}
+
+	return taskQueue.Push(t)
+}
+
 // CreateMigrateTask creates a migrate task
 func CreateMigrateTask(doer, u *models.User, opts base.MigrateOptions) (*models.Task, error) {
 	bs, err := json.Marshal(&opts)
@@ -681,6 +681,8 @@ func ActionIcon(opType models.ActionType) string {
 		return "diff"
 	case models.ActionPublishRelease:
 		return "tag"
+	case models.ActionTransferIssue:
+		return "arrow-right"
 	default:
 		return "question"
 	}
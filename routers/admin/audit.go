@@ -0,0 +1,25 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	userSetting "code.gitea.io/gitea/routers/user/setting"
+)
+
+const (
+	tplAuditLog base.TplName = "admin/audit_log"
+)
+
+// AuditLog show the security events of all users, organizations and repositories
+func AuditLog(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.audit_log")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminAuditLog"] = true
+
+	userSetting.RenderAuditLog(ctx, &models.FindAuditEventsOptions{}, tplAuditLog)
+}
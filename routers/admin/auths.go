@@ -8,11 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/auth/ldap"
 	"code.gitea.io/gitea/modules/auth/oauth2"
+	"code.gitea.io/gitea/modules/auth/saml"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -64,6 +66,7 @@ var (
 		{models.LoginNames[models.LoginPAM], models.LoginPAM},
 		{models.LoginNames[models.LoginOAuth2], models.LoginOAuth2},
 		{models.LoginNames[models.LoginSSPI], models.LoginSSPI},
+		{models.LoginNames[models.LoginSAML], models.LoginSAML},
 	}
 	securityProtocols = []dropdownItem{
 		{models.SecurityProtocolNames[ldap.SecurityProtocolUnencrypted], ldap.SecurityProtocolUnencrypted},
@@ -96,6 +99,9 @@ func NewAuthSource(ctx *context.Context) {
 	ctx.Data["SSPISeparatorReplacement"] = "_"
 	ctx.Data["SSPIDefaultLanguage"] = ""
 
+	ctx.Data["SAMLNameIDFormats"] = saml.NameIDFormats
+	ctx.Data["saml_name_id_format"] = saml.NameIDFormatPersistent
+
 	// only the first as default
 	for key := range models.OAuth2Providers {
 		ctx.Data["oauth2_provider"] = key
@@ -134,6 +140,8 @@ func parseLDAPConfig(form auth.AuthenticationForm) *models.LDAPConfig {
 			GroupFilter:           form.GroupFilter,
 			GroupMemberUID:        form.GroupMemberUID,
 			UserUID:               form.UserUID,
+			GroupTeamMap:          form.GroupTeamMap,
+			GroupTeamMapRemoval:   form.GroupTeamMapRemoval,
 			AdminFilter:           form.AdminFilter,
 			RestrictedFilter:      form.RestrictedFilter,
 			AllowDeactivateAll:    form.AllowDeactivateAll,
@@ -198,6 +206,47 @@ func parseSSPIConfig(ctx *context.Context, form auth.AuthenticationForm) (*model
 	}, nil
 }
 
+// parseSAMLConfig parses the SAML configuration of the form, an empty service provider key pair is
+// kept from the existing configuration or generated
+func parseSAMLConfig(ctx *context.Context, form auth.AuthenticationForm, existing *models.SAMLConfig) (*models.SAMLConfig, error) {
+	source := &saml.Source{
+		IdentityProviderMetadataURL: strings.TrimSpace(form.SAMLIdpMetadataURL),
+		IdentityProviderMetadata:    strings.TrimSpace(form.SAMLIdpMetadata),
+		ServiceProviderCertificate:  strings.TrimSpace(form.SAMLSpCertificate),
+		ServiceProviderPrivateKey:   strings.TrimSpace(form.SAMLSpPrivateKey),
+		SignRequests:                form.SAMLSignRequests,
+		NameIDFormat:                saml.NameIDFormat(form.SAMLNameIDFormat),
+		AttributeUsername:           form.SAMLAttributeUsername,
+		AttributeEmail:              form.SAMLAttributeEmail,
+		AttributeFullName:           form.SAMLAttributeFullName,
+		AttributeGroups:             form.SAMLAttributeGroups,
+		GroupTeamMap:                form.SAMLGroupTeamMap,
+		GroupTeamMapRemoval:         form.SAMLGroupTeamMapRemoval,
+		EnableAutoRegistration:      form.SAMLEnableAutoRegistration,
+	}
+
+	if len(source.ServiceProviderPrivateKey) == 0 {
+		if existing != nil && existing.Source != nil && (len(source.ServiceProviderCertificate) == 0 ||
+			source.ServiceProviderCertificate == strings.TrimSpace(existing.ServiceProviderCertificate)) {
+			source.ServiceProviderCertificate = existing.ServiceProviderCertificate
+			source.ServiceProviderPrivateKey = existing.ServiceProviderPrivateKey
+		} else if len(source.ServiceProviderCertificate) == 0 {
+			cert, key, err := saml.GenerateKeyPair(setting.Domain)
+			if err != nil {
+				return nil, err
+			}
+			source.ServiceProviderCertificate = cert
+			source.ServiceProviderPrivateKey = key
+		}
+	}
+
+	if err := source.Validate(); err != nil {
+		ctx.Data["Err_SAML"] = true
+		return nil, errors.New(ctx.Tr("admin.auths.invalid_saml_config", err.Error()))
+	}
+	return &models.SAMLConfig{Source: source}, nil
+}
+
 // NewAuthSourcePost response for adding an auth source
 func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	ctx.Data["Title"] = ctx.Tr("admin.auths.new")
@@ -218,11 +267,19 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	ctx.Data["SSPISeparatorReplacement"] = "_"
 	ctx.Data["SSPIDefaultLanguage"] = ""
 
+	ctx.Data["SAMLNameIDFormats"] = saml.NameIDFormats
+
 	hasTLS := false
 	var config convert.Conversion
 	switch models.LoginType(form.Type) {
 	case models.LoginLDAP, models.LoginDLDAP:
-		config = parseLDAPConfig(form)
+		ldapConfig := parseLDAPConfig(form)
+		if _, err := ldapConfig.GroupTeamMapping(); err != nil {
+			ctx.Data["Err_GroupTeamMap"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.invalid_group_team_map", err.Error()), tplAuthNew, form)
+			return
+		}
+		config = ldapConfig
 		hasTLS = ldap.SecurityProtocol(form.SecurityProtocol) > ldap.SecurityProtocolUnencrypted
 	case models.LoginSMTP:
 		config = parseSMTPConfig(form)
@@ -246,6 +303,13 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 			ctx.RenderWithErr(ctx.Tr("admin.auths.login_source_of_type_exist"), tplAuthNew, form)
 			return
 		}
+	case models.LoginSAML:
+		var err error
+		config, err = parseSAMLConfig(ctx, form, nil)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthNew, form)
+			return
+		}
 	default:
 		ctx.Error(400)
 		return
@@ -273,6 +337,10 @@ func NewAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 		return
 	}
 
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminAuthSourceCreate,
+		TargetName: form.Name,
+	})
 	log.Trace("Authentication created by admin(%s): %s", ctx.User.Name, form.Name)
 
 	ctx.Flash.Success(ctx.Tr("admin.auths.new_success", form.Name))
@@ -297,6 +365,7 @@ func EditAuthSource(ctx *context.Context) {
 	}
 	ctx.Data["Source"] = source
 	ctx.Data["HasTLS"] = source.HasTLS()
+	ctx.Data["SAMLNameIDFormats"] = saml.NameIDFormats
 
 	if source.IsOAuth2() {
 		ctx.Data["CurrentOAuth2Provider"] = models.OAuth2Providers[source.OAuth2().Provider]
@@ -321,6 +390,7 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	}
 	ctx.Data["Source"] = source
 	ctx.Data["HasTLS"] = source.HasTLS()
+	ctx.Data["SAMLNameIDFormats"] = saml.NameIDFormats
 
 	if ctx.HasError() {
 		ctx.HTML(200, tplAuthEdit)
@@ -330,7 +400,13 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 	var config convert.Conversion
 	switch models.LoginType(form.Type) {
 	case models.LoginLDAP, models.LoginDLDAP:
-		config = parseLDAPConfig(form)
+		ldapConfig := parseLDAPConfig(form)
+		if _, err := ldapConfig.GroupTeamMapping(); err != nil {
+			ctx.Data["Err_GroupTeamMap"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.invalid_group_team_map", err.Error()), tplAuthEdit, form)
+			return
+		}
+		config = ldapConfig
 	case models.LoginSMTP:
 		config = parseSMTPConfig(form)
 	case models.LoginPAM:
@@ -345,6 +421,16 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 			ctx.RenderWithErr(err.Error(), tplAuthEdit, form)
 			return
 		}
+	case models.LoginSAML:
+		var existing *models.SAMLConfig
+		if source.IsSAML() {
+			existing = source.SAML()
+		}
+		config, err = parseSAMLConfig(ctx, form, existing)
+		if err != nil {
+			ctx.RenderWithErr(err.Error(), tplAuthEdit, form)
+			return
+		}
 	default:
 		ctx.Error(400)
 		return
@@ -363,6 +449,11 @@ func EditAuthSourcePost(ctx *context.Context, form auth.AuthenticationForm) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminAuthSourceUpdate,
+		TargetID:   source.ID,
+		TargetName: source.Name,
+	})
 	log.Trace("Authentication changed by admin(%s): %d", ctx.User.Name, source.ID)
 
 	ctx.Flash.Success(ctx.Tr("admin.auths.update_success"))
@@ -388,6 +479,11 @@ func DeleteAuthSource(ctx *context.Context) {
 		})
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminAuthSourceDelete,
+		TargetID:   source.ID,
+		TargetName: source.Name,
+	})
 	log.Trace("Authentication deleted by admin(%s): %d", ctx.User.Name, source.ID)
 
 	ctx.Flash.Success(ctx.Tr("admin.auths.deletion_success"))
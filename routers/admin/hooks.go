@@ -50,6 +50,7 @@ func DeleteDefaultOrSystemWebhook(ctx *context.Context) {
 	if err := models.DeleteDefaultSystemWebhook(ctx.QueryInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteDefaultWebhook: " + err.Error())
 	} else {
+		ctx.Audit(&models.AuditEvent{Action: models.AuditWebhookDelete, TargetID: ctx.QueryInt64("id")})
 		ctx.Flash.Success(ctx.Tr("repo.settings.webhook_deletion_success"))
 	}
 
@@ -43,6 +43,13 @@ func DeleteRepo(ctx *context.Context) {
 		ctx.ServerError("DeleteRepository", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminRepoDelete,
+		OwnerID:    repo.OwnerID,
+		RepoID:     repo.ID,
+		TargetID:   repo.ID,
+		TargetName: repo.FullName(),
+	})
 	log.Trace("Repository deleted: %s", repo.FullName())
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
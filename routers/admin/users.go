@@ -6,6 +6,7 @@
 package admin
 
 import (
+	"fmt"
 	"strings"
 
 	"code.gitea.io/gitea/models"
@@ -22,9 +23,10 @@ import (
 )
 
 const (
-	tplUsers    base.TplName = "admin/user/list"
-	tplUserNew  base.TplName = "admin/user/new"
-	tplUserEdit base.TplName = "admin/user/edit"
+	tplUsers      base.TplName = "admin/user/list"
+	tplUserNew    base.TplName = "admin/user/new"
+	tplUserEdit   base.TplName = "admin/user/edit"
+	tplUserReview base.TplName = "admin/user/review"
 )
 
 // Users show all the users
@@ -33,6 +35,13 @@ func Users(ctx *context.Context) {
 	ctx.Data["PageIsAdmin"] = true
 	ctx.Data["PageIsAdminUsers"] = true
 
+	numFlagged, err := models.CountFlaggedUsers()
+	if err != nil {
+		ctx.ServerError("CountFlaggedUsers", err)
+		return
+	}
+	ctx.Data["NumFlaggedUsers"] = numFlagged
+
 	routers.RenderUserSearch(ctx, &models.SearchUserOptions{
 		Type: models.UserTypeIndividual,
 		ListOptions: models.ListOptions{
@@ -143,6 +152,12 @@ func NewUserPost(ctx *context.Context, form auth.AdminCreateUserForm) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminUserCreate,
+		OwnerID:    u.ID,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
 	log.Trace("Account created by admin (%s): %s", ctx.User.Name, u.Name)
 
 	// Send email notification.
@@ -282,6 +297,13 @@ func EditUserPost(ctx *context.Context, form auth.AdminEditUserForm) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditAdminUserUpdate,
+		OwnerID:     u.ID,
+		TargetID:    u.ID,
+		TargetName:  u.Name,
+		Description: fmt.Sprintf("admin: %t, active: %t, prohibit login: %t", u.IsAdmin, u.IsActive, u.ProhibitLogin),
+	})
 	log.Trace("Account profile updated by admin (%s): %s", ctx.User.Name, u.Name)
 
 	ctx.Flash.Success(ctx.Tr("admin.users.update_profile_success"))
@@ -303,6 +325,11 @@ func DeleteUser(ctx *context.Context) {
 			ctx.JSON(200, map[string]interface{}{
 				"redirect": setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"),
 			})
+		case models.IsErrUserOwnPackages(err):
+			ctx.Flash.Error(ctx.Tr("admin.users.still_own_packages"))
+			ctx.JSON(200, map[string]interface{}{
+				"redirect": setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"),
+			})
 		case models.IsErrUserHasOrgs(err):
 			ctx.Flash.Error(ctx.Tr("admin.users.still_has_org"))
 			ctx.JSON(200, map[string]interface{}{
@@ -313,6 +340,12 @@ func DeleteUser(ctx *context.Context) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminUserDelete,
+		OwnerID:    u.ID,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
 	log.Trace("Account deleted by admin (%s): %s", ctx.User.Name, u.Name)
 
 	ctx.Flash.Success(ctx.Tr("admin.users.deletion_success"))
@@ -320,3 +353,101 @@ func DeleteUser(ctx *context.Context) {
 		"redirect": setting.AppSubURL + "/admin/users",
 	})
 }
+
+// ReviewUsers shows the accounts flagged by the registration checks
+func ReviewUsers(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.users.review_queue")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminUsers"] = true
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	count, err := models.CountFlaggedUsers()
+	if err != nil {
+		ctx.ServerError("CountFlaggedUsers", err)
+		return
+	}
+	flagged, err := models.GetFlaggedUsers(models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.Admin.UserPagingNum,
+	})
+	if err != nil {
+		ctx.ServerError("GetFlaggedUsers", err)
+		return
+	}
+	ctx.Data["FlaggedUsers"] = flagged
+	ctx.Data["Total"] = count
+	ctx.Data["Page"] = context.NewPagination(int(count), setting.UI.Admin.UserPagingNum, page, 5)
+
+	ctx.HTML(200, tplUserReview)
+}
+
+// getFlaggedUser returns the flagged account of the URL. The queue entry of an account which
+// was deleted is removed instead. If there is an error, write to `ctx` accordingly
+func getFlaggedUser(ctx *context.Context) *models.User {
+	userID := ctx.ParamsInt64(":userid")
+	u, err := models.GetUserByID(userID)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			ctx.ServerError("GetUserByID", err)
+			return nil
+		}
+		if err = models.ApproveFlaggedUser(userID); err != nil {
+			ctx.ServerError("ApproveFlaggedUser", err)
+			return nil
+		}
+		ctx.Flash.Info(ctx.Tr("admin.users.review_deleted"))
+		ctx.Redirect(setting.AppSubURL + "/admin/users/review")
+		return nil
+	}
+	return u
+}
+
+// ApproveUser removes a flagged account from the review queue
+func ApproveUser(ctx *context.Context) {
+	u := getFlaggedUser(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.ApproveFlaggedUser(u.ID); err != nil {
+		ctx.ServerError("ApproveFlaggedUser", err)
+		return
+	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminUserApprove,
+		OwnerID:    u.ID,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
+	log.Trace("Flagged account approved by admin (%s): %s", ctx.User.Name, u.Name)
+
+	ctx.Flash.Success(ctx.Tr("admin.users.approve_success", u.Name))
+	ctx.Redirect(setting.AppSubURL + "/admin/users/review")
+}
+
+// RejectUser removes a flagged account from the review queue and prohibits it to sign in
+func RejectUser(ctx *context.Context) {
+	u := getFlaggedUser(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.RejectFlaggedUser(u); err != nil {
+		ctx.ServerError("RejectFlaggedUser", err)
+		return
+	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminUserReject,
+		OwnerID:    u.ID,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
+	log.Trace("Flagged account rejected by admin (%s): %s", ctx.User.Name, u.Name)
+
+	ctx.Flash.Success(ctx.Tr("admin.users.reject_success", u.Name))
+	ctx.Redirect(setting.AppSubURL + "/admin/users/review")
+}
@@ -0,0 +1,92 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/packages/container"
+	"code.gitea.io/gitea/routers/api/packages/generic"
+
+	"gitea.com/macaron/macaron"
+)
+
+func reqPackageAccess(accessMode models.AccessMode) macaron.Handler {
+	scopeLevel := models.AccessTokenScopeLevelRead
+	if accessMode >= models.AccessModeWrite {
+		scopeLevel = models.AccessTokenScopeLevelWrite
+	}
+
+	return func(ctx *context.APIContext) {
+		if !ctx.HasTokenScope(models.AccessTokenScopeCategoryPackage, scopeLevel) {
+			ctx.Error(http.StatusForbidden, "reqPackageAccess", "token does not have the required scope "+models.FormatAccessTokenScope(scopeLevel, models.AccessTokenScopeCategoryPackage))
+			return
+		}
+		if ctx.Package.AccessMode < accessMode {
+			if !ctx.IsSigned {
+				ctx.Resp.Header().Set("WWW-Authenticate", `Basic realm="Gitea Package API"`)
+				ctx.Error(http.StatusUnauthorized, "reqPackageAccess", "user should be signed in")
+				return
+			}
+			ctx.Error(http.StatusForbidden, "reqPackageAccess", "user should have the required permission for the packages of the owner")
+			return
+		}
+
+		// Write requests which are only authenticated by the session cookie need a CSRF token
+		if accessMode >= models.AccessModeWrite && ctx.IsSigned && !ctx.IsBasicAuth && ctx.Data["IsApiToken"] != true {
+			ctx.RequireCSRF()
+		}
+	}
+}
+
+// RegisterRoutes registers the package registry routes
+func RegisterRoutes(m *macaron.Macaron) {
+	m.Group("/packages/:username", func() {
+		m.Group("/generic", func() {
+			m.Group("/:packagename/:packageversion", func() {
+				m.Delete("", reqPackageAccess(models.AccessModeWrite), generic.DeletePackage)
+				m.Group("/:filename", func() {
+					m.Get("", reqPackageAccess(models.AccessModeRead), generic.DownloadPackageFile)
+					m.Put("", reqPackageAccess(models.AccessModeWrite), generic.UploadPackage)
+					m.Delete("", reqPackageAccess(models.AccessModeWrite), generic.DeletePackageFile)
+				})
+			})
+		})
+	}, context.APIContexter(), context.PackageAssignmentAPI())
+}
+
+// RegisterContainerRoutes registers the routes of the container registry. The OCI distribution
+// spec requires the registry to be served at /v2/ of the host.
+func RegisterContainerRoutes(m *macaron.Macaron) {
+	m.Group("/v2", func() {
+		m.Get("", container.CheckAuthenticate)
+		m.Get("/token", container.Authorization)
+		m.Group("/:username/:image", func() {
+			m.Get("/tags/list", container.ReqContainerAccess(models.AccessModeRead), container.GetTagList)
+			m.Group("/manifests/:reference", func() {
+				m.Head("", container.ReqContainerAccess(models.AccessModeRead), container.HeadManifest)
+				m.Get("", container.ReqContainerAccess(models.AccessModeRead), container.GetManifest)
+				m.Put("", container.ReqContainerAccess(models.AccessModeWrite), container.PutManifest)
+				m.Delete("", container.ReqContainerAccess(models.AccessModeWrite), container.DeleteManifest)
+			})
+			m.Group("/blobs/uploads", func() {
+				m.Post("", container.InitiateUploadBlob)
+				m.Group("/:uuid", func() {
+					m.Get("", container.GetUploadBlob)
+					m.Patch("", container.UploadBlobChunk)
+					m.Put("", container.EndUploadBlob)
+					m.Delete("", container.CancelUploadBlob)
+				})
+			}, container.ReqContainerAccess(models.AccessModeWrite))
+			m.Group("/blobs/:digest", func() {
+				m.Head("", container.ReqContainerAccess(models.AccessModeRead), container.HeadBlob)
+				m.Get("", container.ReqContainerAccess(models.AccessModeRead), container.GetBlob)
+				m.Delete("", container.ReqContainerAccess(models.AccessModeWrite), container.DeleteBlob)
+			})
+		}, context.PackageAssignmentAPI(), container.ReqValidImageName())
+	}, context.APIContexter(), container.Authenticate())
+}
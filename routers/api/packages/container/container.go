@@ -0,0 +1,693 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	packages_service "code.gitea.io/gitea/services/packages"
+	container_service "code.gitea.io/gitea/services/packages/container"
+
+	"gitea.com/macaron/macaron"
+)
+
+// Error codes defined by the OCI distribution spec
+const (
+	errCodeBlobUnknown         = "BLOB_UNKNOWN"
+	errCodeBlobUploadInvalid   = "BLOB_UPLOAD_INVALID"
+	errCodeBlobUploadUnknown   = "BLOB_UPLOAD_UNKNOWN"
+	errCodeDigestInvalid       = "DIGEST_INVALID"
+	errCodeManifestBlobUnknown = "MANIFEST_BLOB_UNKNOWN"
+	errCodeManifestInvalid     = "MANIFEST_INVALID"
+	errCodeManifestUnknown     = "MANIFEST_UNKNOWN"
+	errCodeNameInvalid         = "NAME_INVALID"
+	errCodeNameUnknown         = "NAME_UNKNOWN"
+	errCodeSizeInvalid         = "SIZE_INVALID"
+	errCodeTagInvalid          = "TAG_INVALID"
+	errCodeUnauthorized        = "UNAUTHORIZED"
+	errCodeDenied              = "DENIED"
+)
+
+type apiErrorEntry struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiErrors struct {
+	Errors []apiErrorEntry `json:"errors"`
+}
+
+// apiError writes an error response in the format of the distribution spec
+func apiError(ctx *context.APIContext, status int, code string, err error) {
+	message := http.StatusText(status)
+	if err != nil {
+		message = err.Error()
+	}
+	if status == http.StatusInternalServerError {
+		log.ErrorWithSkip(1, "%s: %s", code, message)
+		message = http.StatusText(status)
+	}
+	setDistributionHeader(ctx)
+	ctx.JSON(status, apiErrors{
+		Errors: []apiErrorEntry{{Code: code, Message: message}},
+	})
+}
+
+// apiUnauthorizedError writes a 401 response which tells the client where to get a token
+func apiUnauthorizedError(ctx *context.APIContext) {
+	ctx.Resp.Header().Set("WWW-Authenticate", `Bearer realm="`+setting.AppURL+`v2/token",service="container_registry",scope="*"`)
+	apiError(ctx, http.StatusUnauthorized, errCodeUnauthorized, errors.New("authentication required"))
+}
+
+func setDistributionHeader(ctx *context.APIContext) {
+	ctx.Resp.Header().Set("Docker-Distribution-Api-Version", "registry/2.0")
+}
+
+type containerHeaders struct {
+	Status        int
+	ContentType   string
+	ContentLength int64
+	Location      string
+	Range         string
+	UploadUUID    string
+	ContentDigest string
+}
+
+func setResponseHeaders(ctx *context.APIContext, h *containerHeaders) {
+	header := ctx.Resp.Header()
+	if h.Location != "" {
+		header.Set("Location", h.Location)
+	}
+	if h.Range != "" {
+		header.Set("Range", h.Range)
+	}
+	if h.ContentType != "" {
+		header.Set("Content-Type", h.ContentType)
+	}
+	if h.ContentLength >= 0 {
+		header.Set("Content-Length", strconv.FormatInt(h.ContentLength, 10))
+	}
+	if h.UploadUUID != "" {
+		header.Set("Docker-Upload-UUID", h.UploadUUID)
+	}
+	if h.ContentDigest != "" {
+		header.Set("Docker-Content-Digest", h.ContentDigest)
+		header.Set("ETag", `"`+h.ContentDigest+`"`)
+	}
+	setDistributionHeader(ctx)
+	if h.Status != 0 {
+		ctx.Status(h.Status)
+	}
+}
+
+// Authenticate signs in the user of a registry token. Only tokens and basic auth are
+// accepted, a session cookie does not authorize registry requests.
+func Authenticate() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if ctx.IsSigned && !ctx.IsBasicAuth {
+			ctx.User = nil
+			ctx.IsSigned = false
+		}
+
+		auth := strings.Fields(ctx.Req.Header.Get("Authorization"))
+		if len(auth) != 2 || auth[0] != "Bearer" {
+			return
+		}
+
+		uid, scope, err := container_service.ParseAuthorizationToken(auth[1])
+		if err != nil {
+			// Access tokens can be used as bearer tokens directly
+			token, err := models.GetAccessTokenBySHA(auth[1])
+			if err != nil {
+				return
+			}
+			uid, scope = token.UID, token.Scope
+		}
+		ctx.Data["IsContainerToken"] = true
+		if uid == 0 {
+			return
+		}
+
+		u, err := models.GetUserByID(uid)
+		if err != nil {
+			if !models.IsErrUserNotExist(err) {
+				log.Error("GetUserByID: %v", err)
+			}
+			return
+		}
+		if !u.IsActive || u.ProhibitLogin {
+			return
+		}
+		ctx.User = u
+		ctx.IsSigned = true
+		ctx.Data["ApiToken"] = &models.AccessToken{UID: uid, Scope: scope}
+	}
+}
+
+// ReqContainerAccess checks if the user has the access mode on the packages of the owner
+func ReqContainerAccess(accessMode models.AccessMode) macaron.Handler {
+	scopeLevel := models.AccessTokenScopeLevelRead
+	if accessMode >= models.AccessModeWrite {
+		scopeLevel = models.AccessTokenScopeLevelWrite
+	}
+
+	return func(ctx *context.APIContext) {
+		if !ctx.HasTokenScope(models.AccessTokenScopeCategoryPackage, scopeLevel) {
+			apiError(ctx, http.StatusForbidden, errCodeDenied, errors.New("token does not have the required scope "+models.FormatAccessTokenScope(scopeLevel, models.AccessTokenScopeCategoryPackage)))
+			return
+		}
+		if ctx.Package.AccessMode < accessMode {
+			if !ctx.IsSigned {
+				apiUnauthorizedError(ctx)
+				return
+			}
+			apiError(ctx, http.StatusForbidden, errCodeDenied, errors.New("requested access to the resource is denied"))
+		}
+	}
+}
+
+// ReqValidImageName checks the image name of the request
+func ReqValidImageName() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if !container_service.IsValidImageName(ctx.Params(":image")) {
+			apiError(ctx, http.StatusBadRequest, errCodeNameInvalid, container_service.ErrNameInvalid)
+		}
+	}
+}
+
+// CheckAuthenticate responds to the version check of the client
+// GET /v2/
+func CheckAuthenticate(ctx *context.APIContext) {
+	if ctx.User == nil && ctx.Data["IsContainerToken"] != true {
+		apiUnauthorizedError(ctx)
+		return
+	}
+	setResponseHeaders(ctx, &containerHeaders{Status: http.StatusOK, ContentLength: -1})
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IssuedAt    string `json:"issued_at"`
+}
+
+// Authorization issues a registry token for the basic auth credentials of the request
+// GET /v2/token
+func Authorization(ctx *context.APIContext) {
+	if ctx.User == nil && (ctx.Req.Header.Get("Authorization") != "" || setting.Service.RequireSignInView) {
+		apiUnauthorizedError(ctx)
+		return
+	}
+
+	scope := models.AccessTokenScopeAll
+	if t := ctx.AccessToken(); t != nil {
+		if !t.HasScope(models.AccessTokenScopeCategoryPackage, models.AccessTokenScopeLevelRead) {
+			apiError(ctx, http.StatusForbidden, errCodeDenied, errors.New("token does not have the required scope read:package"))
+			return
+		}
+		scope = t.Scope
+	}
+
+	token, issuedAt, err := container_service.CreateAuthorizationToken(ctx.User, scope)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeUnauthorized, err)
+		return
+	}
+
+	setDistributionHeader(ctx)
+	ctx.JSON(http.StatusOK, tokenResponse{
+		Token:       token,
+		AccessToken: token,
+		ExpiresIn:   int64(container_service.TokenExpiration / time.Second),
+		IssuedAt:    issuedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func imageURL(ctx *context.APIContext) string {
+	return fmt.Sprintf("/v2/%s/%s", ctx.Package.Owner.LowerName, ctx.Params(":image"))
+}
+
+func imageInfo(ctx *context.APIContext) *container_service.ImageInfo {
+	return &container_service.ImageInfo{
+		Owner:   ctx.Package.Owner,
+		Creator: ctx.User,
+		Name:    ctx.Params(":image"),
+	}
+}
+
+// getPackage returns the image of the request or writes an error response with the code
+func getPackage(ctx *context.APIContext, code string) *models.Package {
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, models.PackageContainer, ctx.Params(":image"))
+	if err != nil {
+		if models.IsErrPackageNotExist(err) {
+			apiError(ctx, http.StatusNotFound, code, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, code, err)
+		}
+		return nil
+	}
+	return p
+}
+
+type tagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// GetTagList lists the tags of the image
+// GET /v2/{owner}/{image}/tags/list?n={n}&last={last}
+func GetTagList(ctx *context.APIContext) {
+	p := getPackage(ctx, errCodeNameUnknown)
+	if p == nil {
+		return
+	}
+
+	n := -1
+	if ctx.Query("n") != "" {
+		n = ctx.QueryInt("n")
+	}
+	last := ctx.Query("last")
+
+	tags := []string{}
+	if n != 0 {
+		var err error
+		if tags, err = models.GetContainerTags(p.ID, last, n); err != nil {
+			apiError(ctx, http.StatusInternalServerError, errCodeNameUnknown, err)
+			return
+		}
+	}
+
+	if n > 0 && len(tags) == n {
+		ctx.Resp.Header().Set("Link", fmt.Sprintf(`<%s/tags/list?n=%d&last=%s>; rel="next"`, imageURL(ctx), n, url.QueryEscape(tags[len(tags)-1])))
+	}
+	setDistributionHeader(ctx)
+	ctx.JSON(http.StatusOK, tagList{
+		Name: ctx.Package.Owner.LowerName + "/" + p.LowerName,
+		Tags: tags,
+	})
+}
+
+// getManifestFile returns the manifest of the reference or writes an error response
+func getManifestFile(ctx *context.APIContext) (*models.PackageVersion, *models.PackageFile) {
+	p := getPackage(ctx, errCodeManifestUnknown)
+	if p == nil {
+		return nil, nil
+	}
+
+	pv, pf, err := container_service.GetManifest(p, ctx.Params(":reference"))
+	if err != nil {
+		if models.IsErrPackageVersionNotExist(err) || models.IsErrPackageFileNotExist(err) {
+			apiError(ctx, http.StatusNotFound, errCodeManifestUnknown, errors.New("manifest unknown"))
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeManifestUnknown, err)
+		}
+		return nil, nil
+	}
+	return pv, pf
+}
+
+// HeadManifest checks if a manifest exists
+// HEAD /v2/{owner}/{image}/manifests/{reference}
+func HeadManifest(ctx *context.APIContext) {
+	_, pf := getManifestFile(ctx)
+	if pf == nil {
+		return
+	}
+
+	setResponseHeaders(ctx, &containerHeaders{
+		Status:        http.StatusOK,
+		ContentType:   pf.ContentType,
+		ContentLength: pf.Blob.Size,
+		ContentDigest: pf.Name,
+	})
+}
+
+// GetManifest serves a manifest
+// GET /v2/{owner}/{image}/manifests/{reference}
+func GetManifest(ctx *context.APIContext) {
+	pv, pf := getManifestFile(ctx)
+	if pf == nil {
+		return
+	}
+
+	s, err := packages_service.OpenPackageFile(pv, pf)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeManifestUnknown, err)
+		return
+	}
+	defer s.Close()
+
+	setResponseHeaders(ctx, &containerHeaders{
+		ContentType:   pf.ContentType,
+		ContentLength: -1,
+		ContentDigest: pf.Name,
+	})
+	http.ServeContent(ctx.Resp, ctx.Req.Request, pf.Name, pf.CreatedUnix.AsTime(), s)
+}
+
+// PutManifest stores a manifest and tags it if the reference is a tag
+// PUT /v2/{owner}/{image}/manifests/{reference}
+func PutManifest(ctx *context.APIContext) {
+	body := ctx.Req.Request.Body
+	defer body.Close()
+
+	contentType := strings.TrimSpace(strings.SplitN(ctx.Req.Header.Get("Content-Type"), ";", 2)[0])
+
+	digest, err := container_service.PushManifest(imageInfo(ctx), ctx.Params(":reference"), contentType, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, container_service.ErrTagInvalid):
+			apiError(ctx, http.StatusBadRequest, errCodeTagInvalid, err)
+		case errors.Is(err, container_service.ErrDigestInvalid):
+			apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, err)
+		case errors.Is(err, container_service.ErrManifestInvalid):
+			apiError(ctx, http.StatusBadRequest, errCodeManifestInvalid, err)
+		case errors.Is(err, container_service.ErrManifestBlobUnknown):
+			apiError(ctx, http.StatusBadRequest, errCodeManifestBlobUnknown, err)
+		case errors.Is(err, packages_service.ErrFileTooLarge):
+			apiError(ctx, http.StatusRequestEntityTooLarge, errCodeSizeInvalid, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, errCodeManifestInvalid, err)
+		}
+		return
+	}
+
+	log.Trace("Container manifest pushed: %s/%s:%s", ctx.Package.Owner.Name, ctx.Params(":image"), ctx.Params(":reference"))
+	setResponseHeaders(ctx, &containerHeaders{
+		Status:        http.StatusCreated,
+		Location:      imageURL(ctx) + "/manifests/" + digest,
+		ContentLength: 0,
+		ContentDigest: digest,
+	})
+}
+
+// DeleteManifest deletes a tag or a manifest with all its tags
+// DELETE /v2/{owner}/{image}/manifests/{reference}
+func DeleteManifest(ctx *context.APIContext) {
+	p := getPackage(ctx, errCodeManifestUnknown)
+	if p == nil {
+		return
+	}
+
+	if err := container_service.DeleteManifest(p, ctx.Params(":reference")); err != nil {
+		if models.IsErrPackageVersionNotExist(err) {
+			apiError(ctx, http.StatusNotFound, errCodeManifestUnknown, errors.New("manifest unknown"))
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeManifestUnknown, err)
+		}
+		return
+	}
+
+	setResponseHeaders(ctx, &containerHeaders{Status: http.StatusAccepted, ContentLength: 0})
+}
+
+// getBlobFile returns a file holding the blob of the digest or writes an error response
+func getBlobFile(ctx *context.APIContext) *models.PackageFile {
+	p := getPackage(ctx, errCodeBlobUnknown)
+	if p == nil {
+		return nil
+	}
+
+	digest := ctx.Params(":digest")
+	if !container_service.IsDigest(digest) {
+		apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, container_service.ErrDigestInvalid)
+		return nil
+	}
+
+	pf, err := models.GetContainerBlobFile(p.ID, digest)
+	if err != nil {
+		if models.IsErrPackageFileNotExist(err) {
+			apiError(ctx, http.StatusNotFound, errCodeBlobUnknown, errors.New("blob unknown to registry"))
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeBlobUnknown, err)
+		}
+		return nil
+	}
+	return pf
+}
+
+// HeadBlob checks if a blob exists
+// HEAD /v2/{owner}/{image}/blobs/{digest}
+func HeadBlob(ctx *context.APIContext) {
+	pf := getBlobFile(ctx)
+	if pf == nil {
+		return
+	}
+
+	setResponseHeaders(ctx, &containerHeaders{
+		Status:        http.StatusOK,
+		ContentType:   "application/octet-stream",
+		ContentLength: pf.Blob.Size,
+		ContentDigest: pf.LowerName,
+	})
+}
+
+// GetBlob serves a blob
+// GET /v2/{owner}/{image}/blobs/{digest}
+func GetBlob(ctx *context.APIContext) {
+	pf := getBlobFile(ctx)
+	if pf == nil {
+		return
+	}
+
+	s, err := storage.Packages.Open(pf.Blob.RelativePath())
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeBlobUnknown, err)
+		return
+	}
+	defer s.Close()
+
+	setResponseHeaders(ctx, &containerHeaders{
+		ContentType:   "application/octet-stream",
+		ContentLength: -1,
+		ContentDigest: pf.LowerName,
+	})
+	http.ServeContent(ctx.Resp, ctx.Req.Request, pf.LowerName, pf.CreatedUnix.AsTime(), s)
+}
+
+// DeleteBlob removes an uploaded blob from the image
+// DELETE /v2/{owner}/{image}/blobs/{digest}
+func DeleteBlob(ctx *context.APIContext) {
+	p := getPackage(ctx, errCodeBlobUnknown)
+	if p == nil {
+		return
+	}
+
+	if err := container_service.DeleteBlob(p, ctx.Params(":digest")); err != nil {
+		if models.IsErrPackageFileNotExist(err) {
+			apiError(ctx, http.StatusNotFound, errCodeBlobUnknown, errors.New("blob unknown to registry"))
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeBlobUnknown, err)
+		}
+		return
+	}
+
+	setResponseHeaders(ctx, &containerHeaders{Status: http.StatusAccepted, ContentLength: 0})
+}
+
+// blobUploadError writes the error response for a failed blob upload
+func blobUploadError(ctx *context.APIContext, err error) {
+	switch {
+	case errors.Is(err, container_service.ErrDigestInvalid):
+		apiError(ctx, http.StatusBadRequest, errCodeDigestInvalid, err)
+	case errors.Is(err, container_service.ErrRangeInvalid):
+		apiError(ctx, http.StatusRequestedRangeNotSatisfiable, errCodeBlobUploadInvalid, err)
+	case errors.Is(err, packages_service.ErrFileTooLarge):
+		apiError(ctx, http.StatusRequestEntityTooLarge, errCodeSizeInvalid, err)
+	default:
+		apiError(ctx, http.StatusInternalServerError, errCodeBlobUploadInvalid, err)
+	}
+}
+
+func blobCreated(ctx *context.APIContext, digest string) {
+	setResponseHeaders(ctx, &containerHeaders{
+		Status:        http.StatusCreated,
+		Location:      imageURL(ctx) + "/blobs/" + digest,
+		ContentLength: 0,
+		ContentDigest: digest,
+	})
+}
+
+func uploadAccepted(ctx *context.APIContext, status int, pbu *models.PackageBlobUpload) {
+	end := pbu.BytesReceived
+	if end > 0 {
+		end--
+	}
+	setResponseHeaders(ctx, &containerHeaders{
+		Status:        status,
+		Location:      imageURL(ctx) + "/blobs/uploads/" + pbu.ID,
+		Range:         fmt.Sprintf("0-%d", end),
+		ContentLength: 0,
+		UploadUUID:    pbu.ID,
+	})
+}
+
+// mountBlob makes the blob of another image of a visible owner available in the image
+func mountBlob(ctx *context.APIContext, digest, from string) (bool, error) {
+	parts := strings.SplitN(from, "/", 2)
+	if len(parts) != 2 {
+		return false, nil
+	}
+
+	owner, err := models.GetUserByName(parts[0])
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	mode, err := models.GetPackageAccessMode(owner, ctx.User)
+	if err != nil || mode < models.AccessModeRead {
+		return false, err
+	}
+
+	p, err := models.GetPackageByName(owner.ID, models.PackageContainer, parts[1])
+	if err != nil {
+		if models.IsErrPackageNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return container_service.MountBlob(imageInfo(ctx), digest, p)
+}
+
+// InitiateUploadBlob starts a blob upload, uploads a blob in a single request or mounts a blob
+// of another image
+// POST /v2/{owner}/{image}/blobs/uploads/
+func InitiateUploadBlob(ctx *context.APIContext) {
+	body := ctx.Req.Request.Body
+	defer body.Close()
+
+	digest := ctx.Query("digest")
+	if mount := ctx.Query("mount"); mount != "" {
+		mounted, err := mountBlob(ctx, mount, ctx.Query("from"))
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, errCodeBlobUnknown, err)
+			return
+		}
+		if mounted {
+			blobCreated(ctx, mount)
+			return
+		}
+	} else if digest != "" {
+		if err := container_service.UploadBlob(imageInfo(ctx), digest, body); err != nil {
+			blobUploadError(ctx, err)
+			return
+		}
+		blobCreated(ctx, digest)
+		return
+	}
+
+	pbu, err := container_service.StartUpload(imageInfo(ctx))
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, errCodeBlobUploadInvalid, err)
+		return
+	}
+	uploadAccepted(ctx, http.StatusAccepted, pbu)
+}
+
+// getBlobUpload returns the upload of the request or writes an error response
+func getBlobUpload(ctx *context.APIContext) *models.PackageBlobUpload {
+	pbu, err := models.GetPackageBlobUpload(ctx.Package.Owner.ID, ctx.Params(":image"), ctx.Params(":uuid"))
+	if err != nil {
+		if models.IsErrPackageBlobUploadNotExist(err) {
+			apiError(ctx, http.StatusNotFound, errCodeBlobUploadUnknown, errors.New("blob upload unknown to registry"))
+		} else {
+			apiError(ctx, http.StatusInternalServerError, errCodeBlobUploadUnknown, err)
+		}
+		return nil
+	}
+	return pbu
+}
+
+// GetUploadBlob returns the progress of an upload
+// GET /v2/{owner}/{image}/blobs/uploads/{uuid}
+func GetUploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+	uploadAccepted(ctx, http.StatusNoContent, pbu)
+}
+
+// UploadBlobChunk appends a chunk to an upload
+// PATCH /v2/{owner}/{image}/blobs/uploads/{uuid}
+func UploadBlobChunk(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+
+	body := ctx.Req.Request.Body
+	defer body.Close()
+
+	rangeStart := int64(-1)
+	if contentRange := ctx.Req.Header.Get("Content-Range"); contentRange != "" {
+		parts := strings.SplitN(strings.TrimPrefix(contentRange, "bytes="), "-", 2)
+		start, err := strconv.ParseInt(parts[0], 10, 64)
+		if len(parts) != 2 || err != nil {
+			apiError(ctx, http.StatusRequestedRangeNotSatisfiable, errCodeBlobUploadInvalid, container_service.ErrRangeInvalid)
+			return
+		}
+		rangeStart = start
+	}
+
+	if err := container_service.AppendUpload(pbu, rangeStart, body); err != nil {
+		blobUploadError(ctx, err)
+		return
+	}
+	uploadAccepted(ctx, http.StatusAccepted, pbu)
+}
+
+// EndUploadBlob completes an upload with an optional last chunk
+// PUT /v2/{owner}/{image}/blobs/uploads/{uuid}?digest={digest}
+func EndUploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+
+	body := ctx.Req.Request.Body
+	defer body.Close()
+
+	var r io.Reader
+	if ctx.Req.ContentLength != 0 {
+		r = body
+	}
+
+	digest := ctx.Query("digest")
+	if err := container_service.FinishUpload(imageInfo(ctx), pbu, digest, r); err != nil {
+		blobUploadError(ctx, err)
+		return
+	}
+
+	log.Trace("Container blob uploaded: %s/%s@%s", ctx.Package.Owner.Name, ctx.Params(":image"), digest)
+	blobCreated(ctx, digest)
+}
+
+// CancelUploadBlob cancels an upload
+// DELETE /v2/{owner}/{image}/blobs/uploads/{uuid}
+func CancelUploadBlob(ctx *context.APIContext) {
+	pbu := getBlobUpload(ctx)
+	if pbu == nil {
+		return
+	}
+
+	container_service.CancelUpload(pbu)
+	setResponseHeaders(ctx, &containerHeaders{Status: http.StatusNoContent, ContentLength: -1})
+}
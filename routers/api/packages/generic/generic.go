@@ -0,0 +1,159 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package generic
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+var (
+	packageNameRegex = regexp.MustCompile(`\A[A-Za-z0-9\.\_\-\+]+\z`)
+	filenameRegex    = packageNameRegex
+)
+
+// validateParams checks the package name, version and optional filename of the request
+func validateParams(ctx *context.APIContext, withFilename bool) (name, version, filename string, ok bool) {
+	name = ctx.Params(":packagename")
+	version = ctx.Params(":packageversion")
+	filename = ctx.Params(":filename")
+
+	if !packageNameRegex.MatchString(name) || !packageNameRegex.MatchString(version) {
+		ctx.Error(http.StatusBadRequest, "", errors.New("invalid package name or version"))
+		return "", "", "", false
+	}
+	if withFilename && (!filenameRegex.MatchString(filename) || filename == "." || filename == "..") {
+		ctx.Error(http.StatusBadRequest, "", errors.New("invalid filename"))
+		return "", "", "", false
+	}
+	return name, version, filename, true
+}
+
+// getPackageVersion returns the requested version or writes an error response
+func getPackageVersion(ctx *context.APIContext, name, version string) *models.PackageVersion {
+	pv, err := models.GetPackageVersionByName(ctx.Package.Owner.ID, models.PackageGeneric, name, version)
+	if err != nil {
+		if models.IsErrPackageNotExist(err) || models.IsErrPackageVersionNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageVersionByName", err)
+		}
+		return nil
+	}
+	return pv
+}
+
+// getPackageFile returns the requested version and file or writes an error response
+func getPackageFile(ctx *context.APIContext) (*models.PackageVersion, *models.PackageFile) {
+	name, version, filename, ok := validateParams(ctx, true)
+	if !ok {
+		return nil, nil
+	}
+
+	pv := getPackageVersion(ctx, name, version)
+	if pv == nil {
+		return nil, nil
+	}
+
+	pf, err := models.GetPackageFileByName(pv.ID, filename)
+	if err != nil {
+		if models.IsErrPackageFileNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageFileByName", err)
+		}
+		return nil, nil
+	}
+	return pv, pf
+}
+
+// DownloadPackageFile serves the content of a package file
+func DownloadPackageFile(ctx *context.APIContext) {
+	pv, pf := getPackageFile(ctx)
+	if pf == nil {
+		return
+	}
+
+	s, err := packages_service.OpenPackageFile(pv, pf)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenPackageFile", err)
+		return
+	}
+	defer s.Close()
+
+	ctx.ServeContent(pf.Name, s, pf.CreatedUnix.AsTime())
+}
+
+// UploadPackage adds the request body as a file to the package version
+func UploadPackage(ctx *context.APIContext) {
+	name, version, filename, ok := validateParams(ctx, true)
+	if !ok {
+		return
+	}
+
+	body := ctx.Req.Request.Body
+	defer body.Close()
+
+	_, _, err := packages_service.AddFileToPackage(&packages_service.PackageInfo{
+		Owner:   ctx.Package.Owner,
+		Creator: ctx.User,
+		Type:    models.PackageGeneric,
+		Name:    name,
+		Version: version,
+	}, filename, body)
+	if err != nil {
+		switch {
+		case models.IsErrPackageFileAlreadyExist(err):
+			ctx.Error(http.StatusConflict, "", err)
+		case errors.Is(err, packages_service.ErrFileTooLarge):
+			ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "AddFileToPackage", err)
+		}
+		return
+	}
+
+	log.Trace("Package file uploaded: %s/%s/%s/%s", ctx.Package.Owner.Name, name, version, filename)
+	ctx.Status(http.StatusCreated)
+}
+
+// DeletePackage deletes a package version with all its files
+func DeletePackage(ctx *context.APIContext) {
+	name, version, _, ok := validateParams(ctx, false)
+	if !ok {
+		return
+	}
+
+	pv := getPackageVersion(ctx, name, version)
+	if pv == nil {
+		return
+	}
+
+	if err := packages_service.RemovePackageVersion(pv); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemovePackageVersion", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeletePackageFile deletes a single file of a package version
+func DeletePackageFile(ctx *context.APIContext) {
+	pv, pf := getPackageFile(ctx)
+	if pf == nil {
+		return
+	}
+
+	if err := packages_service.RemovePackageFile(pv, pf); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RemovePackageFile", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListAuditEvents api for listing the events of the audit log
+func ListAuditEvents(ctx *context.APIContext) {
+	// swagger:operation GET /admin/audit_log admin adminListAuditEvents
+	// ---
+	// summary: List the events of the audit log, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: action
+	//   in: query
+	//   description: only show events of this action
+	//   type: string
+	// - name: actor
+	//   in: query
+	//   description: only show events caused by this user
+	//   type: string
+	// - name: owner
+	//   in: query
+	//   description: only show events affecting this user or organization and its repositories
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only show events recorded after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only show events recorded before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuditEventList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+	opts := &models.FindAuditEventsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Since:       since,
+		Before:      before,
+	}
+
+	if action := ctx.Query("action"); len(action) > 0 {
+		opts.Action = models.AuditAction(action)
+		if !opts.Action.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown audit action: %s", action))
+			return
+		}
+	}
+	if opts.ActorID = auditUserID(ctx, "actor"); ctx.Written() {
+		return
+	}
+	if opts.OwnerID = auditUserID(ctx, "owner"); ctx.Written() {
+		return
+	}
+
+	events, count, err := models.FindAuditEvents(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAuditEvents", err)
+		return
+	}
+	if err := events.LoadAttributes(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadAttributes", err)
+		return
+	}
+
+	results := make([]*api.AuditEvent, len(events))
+	for i := range events {
+		results[i] = convert.ToAuditEvent(events[i])
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &results)
+}
+
+// auditUserID returns the id of the user named by the query parameter, or 0 if it is not set
+func auditUserID(ctx *context.APIContext, key string) int64 {
+	name := ctx.Query(key)
+	if len(name) == 0 {
+		return 0
+	}
+	u, err := models.GetUserByName(name)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return 0
+	}
+	return u.ID
+}
@@ -108,6 +108,12 @@ func CreateUser(ctx *context.APIContext, form api.CreateUserOption) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminUserCreate,
+		OwnerID:    u.ID,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
 	log.Trace("Account created by admin (%s): %s", ctx.User.Name, u.Name)
 
 	// Send email notification.
@@ -215,6 +221,13 @@ func EditUser(ctx *context.APIContext, form api.EditUserOption) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditAdminUserUpdate,
+		OwnerID:     u.ID,
+		TargetID:    u.ID,
+		TargetName:  u.Name,
+		Description: fmt.Sprintf("admin: %t, active: %t, prohibit login: %t", u.IsAdmin, u.IsActive, u.ProhibitLogin),
+	})
 	log.Trace("Account profile updated by admin (%s): %s", ctx.User.Name, u.Name)
 
 	ctx.JSON(http.StatusOK, convert.ToUser(u, ctx.IsSigned, ctx.User.IsAdmin))
@@ -253,6 +266,7 @@ func DeleteUser(ctx *context.APIContext) {
 
 	if err := models.DeleteUser(u); err != nil {
 		if models.IsErrUserOwnRepos(err) ||
+			models.IsErrUserOwnPackages(err) ||
 			models.IsErrUserHasOrgs(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
@@ -260,6 +274,12 @@ func DeleteUser(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAdminUserDelete,
+		OwnerID:    u.ID,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
 	log.Trace("Account deleted by admin(%s): %s", ctx.User.Name, u.Name)
 
 	ctx.Status(http.StatusNoContent)
@@ -341,6 +361,11 @@ func DeleteUserPublicKey(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:   models.AuditPublicKeyDelete,
+		OwnerID:  u.ID,
+		TargetID: ctx.ParamsInt64(":id"),
+	})
 	log.Trace("Key deleted by admin(%s): %s", ctx.User.Name, u.Name)
 
 	ctx.Status(http.StatusNoContent)
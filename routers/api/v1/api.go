@@ -72,12 +72,14 @@ import (
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/ratelimit"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/admin"
 	"code.gitea.io/gitea/routers/api/v1/misc"
 	"code.gitea.io/gitea/routers/api/v1/notify"
 	"code.gitea.io/gitea/routers/api/v1/org"
+	"code.gitea.io/gitea/routers/api/v1/project"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/settings"
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
@@ -95,7 +97,7 @@ func sudo() macaron.Handler {
 		}
 
 		if len(sudo) > 0 {
-			if ctx.IsSigned && ctx.User.IsAdmin {
+			if ctx.IsSigned && ctx.User.IsAdmin && ctx.HasTokenScope(models.AccessTokenScopeCategoryAdmin, models.AccessTokenScopeLevelWrite) {
 				user, err := models.GetUserByName(sudo)
 				if err != nil {
 					if models.IsErrUserNotExist(err) {
@@ -161,6 +163,11 @@ func repoAssignment() macaron.Handler {
 			return
 		}
 
+		if !ctx.CanTokenAccessRepo(repo.ID) {
+			ctx.NotFound()
+			return
+		}
+
 		repo.Owner = owner
 		ctx.Repo.Repository = repo
 
@@ -195,6 +202,40 @@ func reqToken() macaron.Handler {
 	}
 }
 
+// reqTokenScope checks that the access token of the request grants access to the category.
+// Reading requests require the read level, all other requests the write level.
+func reqTokenScope(category models.AccessTokenScopeCategory) macaron.Handler {
+	return func(ctx *context.APIContext) {
+		level := models.AccessTokenScopeLevelWrite
+		if ctx.Req.Method == http.MethodGet || ctx.Req.Method == http.MethodHead {
+			level = models.AccessTokenScopeLevelRead
+		}
+		if !ctx.HasTokenScope(category, level) {
+			ctx.Error(http.StatusForbidden, "reqTokenScope", "token does not have the required scope "+models.FormatAccessTokenScope(level, category))
+			return
+		}
+	}
+}
+
+// reqAdminTokenScope checks that the access token of the request grants the admin level on the category
+func reqAdminTokenScope(ctx *context.Context, category models.AccessTokenScopeCategory) bool {
+	if !ctx.HasTokenScope(category, models.AccessTokenScopeLevelAdmin) {
+		ctx.Error(http.StatusForbidden, "token does not have the required scope "+models.FormatAccessTokenScope(models.AccessTokenScopeLevelAdmin, category))
+		return false
+	}
+	return true
+}
+
+// reqUnrestrictedToken prevents restricted access tokens from managing access tokens
+func reqUnrestrictedToken() macaron.Handler {
+	return func(ctx *context.APIContext) {
+		if t := ctx.AccessToken(); t != nil && t.IsRestricted() {
+			ctx.Error(http.StatusForbidden, "reqUnrestrictedToken", "restricted access tokens can not manage access tokens")
+			return
+		}
+	}
+}
+
 func reqBasicAuth() macaron.Handler {
 	return func(ctx *context.APIContext) {
 		if !ctx.Context.IsBasicAuth {
@@ -218,6 +259,9 @@ func reqSiteAdmin() macaron.Handler {
 // reqOwner user should be the owner of the repo or site admin.
 func reqOwner() macaron.Handler {
 	return func(ctx *context.Context) {
+		if !reqAdminTokenScope(ctx, models.AccessTokenScopeCategoryRepository) {
+			return
+		}
 		if !ctx.IsUserRepoOwner() && !ctx.IsUserSiteAdmin() {
 			ctx.Error(http.StatusForbidden)
 			return
@@ -228,6 +272,9 @@ func reqOwner() macaron.Handler {
 // reqAdmin user should be an owner or a collaborator with admin write of a repository, or site admin
 func reqAdmin() macaron.Handler {
 	return func(ctx *context.Context) {
+		if !reqAdminTokenScope(ctx, models.AccessTokenScopeCategoryRepository) {
+			return
+		}
 		if !ctx.IsUserRepoAdmin() && !ctx.IsUserSiteAdmin() {
 			ctx.Error(http.StatusForbidden)
 			return
@@ -268,6 +315,9 @@ func reqAnyRepoReader() macaron.Handler {
 // reqOrgOwnership user should be an organization owner, or a site admin
 func reqOrgOwnership() macaron.Handler {
 	return func(ctx *context.APIContext) {
+		if !reqAdminTokenScope(ctx.Context, models.AccessTokenScopeCategoryOrganization) {
+			return
+		}
 		if ctx.Context.IsUserSiteAdmin() {
 			return
 		}
@@ -535,7 +585,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Combo("/threads/:id").
 				Get(notify.GetThread).
 				Patch(notify.ReadThread)
-		}, reqToken())
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeCategoryUser))
 
 		// Users
 		m.Group("/users", func() {
@@ -550,9 +600,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Combo("").Get(user.ListAccessTokens).
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
 					m.Combo("/:id").Delete(user.DeleteAccessToken)
-				}, reqBasicAuth())
+				}, reqBasicAuth(), reqUnrestrictedToken())
 			})
-		})
+		}, reqTokenScope(models.AccessTokenScopeCategoryUser))
 
 		m.Group("/users", func() {
 			m.Group("/:username", func() {
@@ -569,7 +619,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 
 				m.Get("/subscriptions", user.GetWatchedRepos)
 			})
-		}, reqToken())
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeCategoryUser))
 
 		m.Group("/user", func() {
 			m.Get("", user.GetAuthenticatedUser)
@@ -582,6 +632,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("", user.ListMyFollowing)
 				m.Combo("/:username").Get(user.CheckMyFollowing).Put(user.Follow).Delete(user.Unfollow)
 			})
+			m.Group("/blocks", func() {
+				m.Get("", user.ListBlockedUsers)
+				m.Combo("/:username").Get(user.CheckUserBlocked).Put(user.BlockUser).Delete(user.UnblockUser)
+			})
 
 			m.Group("/keys", func() {
 				m.Combo("").Get(user.ListMyPublicKeys).
@@ -605,7 +659,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Combo("/:id").Get(user.GetGPGKey).
 					Delete(user.DeleteGPGKey)
 			})
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeCategoryUser))
 
+		m.Group("/user", func() {
 			m.Combo("/repos").Get(user.ListMyRepos).
 				Post(bind(api.CreateRepoOption{}), repo.Create)
 
@@ -617,25 +673,47 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Delete("", user.Unstar)
 				}, repoAssignment())
 			})
-			m.Get("/times", repo.ListMyTrackedTimes)
-
-			m.Get("/stopwatches", repo.GetStopwatches)
 
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeCategoryRepository))
 
-			m.Get("/teams", org.ListUserTeams)
-		}, reqToken())
+		m.Group("/user", func() {
+			m.Get("/times", repo.ListMyTrackedTimes)
+
+			m.Get("/stopwatches", repo.GetStopwatches)
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeCategoryIssue))
+
+		// Projects
+		m.Get("/users/:username/projects", reqTokenScope(models.AccessTokenScopeCategoryIssue), user.ListProjects)
+		m.Post("/user/projects", reqToken(), reqTokenScope(models.AccessTokenScopeCategoryIssue), bind(api.CreateProjectOption{}), user.CreateProject)
+		m.Combo("/orgs/:org/projects", reqTokenScope(models.AccessTokenScopeCategoryIssue), orgAssignment(true)).Get(org.ListProjects).
+			Post(reqToken(), bind(api.CreateProjectOption{}), org.CreateProject)
+		m.Group("/projects/:id", func() {
+			m.Combo("").Get(project.GetProject).
+				Patch(reqToken(), bind(api.EditProjectOption{}), project.EditProject).
+				Delete(reqToken(), project.DeleteProject)
+			m.Group("/boards", func() {
+				m.Combo("").Get(project.ListBoards).
+					Post(reqToken(), bind(api.CreateProjectBoardOption{}), project.CreateBoard)
+				m.Combo("/:board").Patch(reqToken(), bind(api.CreateProjectBoardOption{}), project.EditBoard).
+					Delete(reqToken(), project.DeleteBoard)
+			})
+			m.Group("/cards", func() {
+				m.Combo("").Get(project.ListCards).
+					Post(reqToken(), bind(api.AddProjectCardOption{}), project.AddCard)
+				m.Combo("/:issue").Patch(reqToken(), bind(api.MoveProjectCardOption{}), project.MoveCard).
+					Delete(reqToken(), project.RemoveCard)
+			})
+		}, reqTokenScope(models.AccessTokenScopeCategoryIssue))
 
 		// Repositories
-		m.Post("/org/:org/repos", reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepoDeprecated)
+		m.Post("/org/:org/repos", reqToken(), reqTokenScope(models.AccessTokenScopeCategoryRepository), bind(api.CreateRepoOption{}), repo.CreateOrgRepoDeprecated)
 
-		m.Combo("/repositories/:id", reqToken()).Get(repo.GetByID)
+		m.Combo("/repositories/:id", reqToken(), reqTokenScope(models.AccessTokenScopeCategoryRepository)).Get(repo.GetByID)
 
 		m.Group("/repos", func() {
 			m.Get("/search", repo.Search)
 
-			m.Get("/issues/search", repo.SearchIssues)
-
 			m.Post("/migrate", reqToken(), bind(api.MigrateRepoOptions{}), repo.Migrate)
 
 			m.Group("/:username/:reponame", func() {
@@ -689,6 +767,15 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Delete("", repo.DeleteBranchProtection)
 					})
 				}, reqToken(), reqAdmin())
+				m.Group("/tag_protections", func() {
+					m.Get("", repo.ListTagProtection)
+					m.Post("", bind(api.CreateTagProtectionOption{}), repo.CreateTagProtection)
+					m.Group("/:id", func() {
+						m.Get("", repo.GetTagProtection)
+						m.Patch("", bind(api.EditTagProtectionOption{}), repo.EditTagProtection)
+						m.Delete("", repo.DeleteTagProtection)
+					})
+				}, reqToken(), reqAdmin())
 				m.Group("/tags", func() {
 					m.Get("", repo.ListTags)
 				}, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(true))
@@ -698,83 +785,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Combo("/:id").Get(repo.GetDeployKey).
 						Delete(repo.DeleteDeploykey)
 				}, reqToken(), reqAdmin())
-				m.Group("/times", func() {
-					m.Combo("").Get(repo.ListTrackedTimesByRepository)
-					m.Combo("/:timetrackingusername").Get(repo.ListTrackedTimesByUser)
-				}, mustEnableIssues, reqToken())
-				m.Group("/issues", func() {
-					m.Combo("").Get(repo.ListIssues).
-						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
-					m.Group("/comments", func() {
-						m.Get("", repo.ListRepoIssueComments)
-						m.Group("/:id", func() {
-							m.Combo("").
-								Get(repo.GetIssueComment).
-								Patch(mustNotBeArchived, reqToken(), bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
-								Delete(reqToken(), repo.DeleteIssueComment)
-							m.Combo("/reactions").
-								Get(repo.GetIssueCommentReactions).
-								Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueCommentReaction).
-								Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueCommentReaction)
-						})
-					})
-					m.Group("/:index", func() {
-						m.Combo("").Get(repo.GetIssue).
-							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
-						m.Group("/comments", func() {
-							m.Combo("").Get(repo.ListIssueComments).
-								Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
-							m.Combo("/:id", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
-								Delete(repo.DeleteIssueCommentDeprecated)
-						})
-						m.Group("/labels", func() {
-							m.Combo("").Get(repo.ListIssueLabels).
-								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
-								Put(reqToken(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
-								Delete(reqToken(), repo.ClearIssueLabels)
-							m.Delete("/:id", reqToken(), repo.DeleteIssueLabel)
-						})
-						m.Group("/times", func() {
-							m.Combo("").
-								Get(repo.ListTrackedTimes).
-								Post(bind(api.AddTimeOption{}), repo.AddTime).
-								Delete(repo.ResetIssueTime)
-							m.Delete("/:id", repo.DeleteTime)
-						}, reqToken())
-						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
-						m.Group("/stopwatch", func() {
-							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
-							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
-							m.Delete("/delete", reqToken(), repo.DeleteIssueStopwatch)
-						})
-						m.Group("/subscriptions", func() {
-							m.Get("", repo.GetIssueSubscribers)
-							m.Get("/check", reqToken(), repo.CheckIssueSubscription)
-							m.Put("/:user", reqToken(), repo.AddIssueSubscription)
-							m.Delete("/:user", reqToken(), repo.DelIssueSubscription)
-						})
-						m.Combo("/reactions").
-							Get(repo.GetIssueReactions).
-							Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueReaction).
-							Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueReaction)
-					})
-				}, mustEnableIssuesOrPulls)
-				m.Group("/labels", func() {
-					m.Combo("").Get(repo.ListLabels).
-						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateLabelOption{}), repo.CreateLabel)
-					m.Combo("/:id").Get(repo.GetLabel).
-						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditLabelOption{}), repo.EditLabel).
-						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteLabel)
-				})
 				m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 				m.Post("/markdown/raw", misc.MarkdownRaw)
-				m.Group("/milestones", func() {
-					m.Combo("").Get(repo.ListMilestones).
-						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
-					m.Combo("/:id").Get(repo.GetMilestone).
-						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
-						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
-				})
 				m.Get("/stargazers", repo.ListStargazers)
 				m.Get("/subscribers", repo.ListSubscribers)
 				m.Group("/subscription", func() {
@@ -799,6 +811,13 @@ func RegisterRoutes(m *macaron.Macaron) {
 					})
 				}, reqRepoReader(models.UnitTypeReleases))
 				m.Post("/mirror-sync", reqToken(), reqRepoWriter(models.UnitTypeCode), repo.MirrorSync)
+				m.Group("/push_mirrors", func() {
+					m.Combo("").Get(repo.ListPushMirrors).
+						Post(bind(api.CreatePushMirrorOption{}), repo.AddPushMirror)
+					m.Combo("/:id").Get(repo.GetPushMirror).
+						Delete(repo.DeletePushMirror)
+				}, reqToken(), reqAdmin())
+				m.Post("/push_mirrors-sync", reqToken(), reqAdmin(), repo.PushMirrorSync)
 				m.Get("/editorconfig/:filename", context.RepoRef(), reqRepoReader(models.UnitTypeCode), repo.GetEditorconfig)
 				m.Group("/pulls", func() {
 					m.Combo("").Get(bind(api.ListPullRequestsOptions{}), repo.ListPullRequests).
@@ -810,7 +829,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 						m.Get(".patch", repo.DownloadPullPatch)
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
-							Post(reqToken(), mustNotBeArchived, bind(auth.MergePullRequestForm{}), repo.MergePullRequest)
+							Post(reqToken(), mustNotBeArchived, bind(auth.MergePullRequestForm{}), repo.MergePullRequest).
+							Delete(reqToken(), mustNotBeArchived, repo.CancelScheduledAutoMerge)
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -824,6 +844,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 									Get(repo.GetPullReviewComments)
 							})
 						})
+						m.Combo("/requested_reviewers").
+							Get(repo.GetReviewRequests).
+							Post(reqToken(), bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests).
+							Delete(reqToken(), bind(api.PullReviewRequestOptions{}), repo.DeleteReviewRequests)
 
 					})
 				}, mustAllowPulls, reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false))
@@ -869,19 +893,103 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
 			}, repoAssignment())
-		})
+		}, reqTokenScope(models.AccessTokenScopeCategoryRepository))
+
+		m.Group("/repos", func() {
+			m.Get("/issues/search", repo.SearchIssues)
+
+			m.Group("/:username/:reponame", func() {
+				m.Group("/times", func() {
+					m.Combo("").Get(repo.ListTrackedTimesByRepository)
+					m.Combo("/:timetrackingusername").Get(repo.ListTrackedTimesByUser)
+				}, mustEnableIssues, reqToken())
+				m.Group("/issues", func() {
+					m.Combo("").Get(repo.ListIssues).
+						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Group("/comments", func() {
+						m.Get("", repo.ListRepoIssueComments)
+						m.Group("/:id", func() {
+							m.Combo("").
+								Get(repo.GetIssueComment).
+								Patch(mustNotBeArchived, reqToken(), bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
+								Delete(reqToken(), repo.DeleteIssueComment)
+							m.Combo("/reactions").
+								Get(repo.GetIssueCommentReactions).
+								Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueCommentReaction).
+								Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueCommentReaction)
+						})
+					})
+					m.Group("/:index", func() {
+						m.Combo("").Get(repo.GetIssue).
+							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
+						m.Group("/comments", func() {
+							m.Combo("").Get(repo.ListIssueComments).
+								Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
+							m.Combo("/:id", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
+								Delete(repo.DeleteIssueCommentDeprecated)
+						})
+						m.Group("/labels", func() {
+							m.Combo("").Get(repo.ListIssueLabels).
+								Post(reqToken(), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
+								Put(reqToken(), bind(api.IssueLabelsOption{}), repo.ReplaceIssueLabels).
+								Delete(reqToken(), repo.ClearIssueLabels)
+							m.Delete("/:id", reqToken(), repo.DeleteIssueLabel)
+						})
+						m.Group("/times", func() {
+							m.Combo("").
+								Get(repo.ListTrackedTimes).
+								Post(bind(api.AddTimeOption{}), repo.AddTime).
+								Delete(repo.ResetIssueTime)
+							m.Delete("/:id", repo.DeleteTime)
+						}, reqToken())
+						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Post("/transfer", reqToken(), mustNotBeArchived, bind(api.TransferIssueOption{}), repo.TransferIssue)
+						m.Group("/stopwatch", func() {
+							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
+							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
+							m.Delete("/delete", reqToken(), repo.DeleteIssueStopwatch)
+						})
+						m.Group("/subscriptions", func() {
+							m.Get("", repo.GetIssueSubscribers)
+							m.Get("/check", reqToken(), repo.CheckIssueSubscription)
+							m.Put("/:user", reqToken(), repo.AddIssueSubscription)
+							m.Delete("/:user", reqToken(), repo.DelIssueSubscription)
+						})
+						m.Combo("/reactions").
+							Get(repo.GetIssueReactions).
+							Post(bind(api.EditReactionOption{}), reqToken(), repo.PostIssueReaction).
+							Delete(bind(api.EditReactionOption{}), reqToken(), repo.DeleteIssueReaction)
+					})
+				}, mustEnableIssuesOrPulls)
+				m.Group("/labels", func() {
+					m.Combo("").Get(repo.ListLabels).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateLabelOption{}), repo.CreateLabel)
+					m.Combo("/:id").Get(repo.GetLabel).
+						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditLabelOption{}), repo.EditLabel).
+						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteLabel)
+				})
+				m.Group("/milestones", func() {
+					m.Combo("").Get(repo.ListMilestones).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
+					m.Combo("/:id").Get(repo.GetMilestone).
+						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
+						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
+				})
+			}, repoAssignment())
+		}, reqTokenScope(models.AccessTokenScopeCategoryIssue))
 
 		// Organizations
-		m.Get("/user/orgs", reqToken(), org.ListMyOrgs)
-		m.Get("/users/:username/orgs", org.ListUserOrgs)
-		m.Post("/orgs", reqToken(), bind(api.CreateOrgOption{}), org.Create)
-		m.Get("/orgs", org.GetAll)
+		m.Get("/user/orgs", reqToken(), reqTokenScope(models.AccessTokenScopeCategoryOrganization), org.ListMyOrgs)
+		m.Get("/user/teams", reqToken(), reqTokenScope(models.AccessTokenScopeCategoryOrganization), org.ListUserTeams)
+		m.Get("/users/:username/orgs", reqTokenScope(models.AccessTokenScopeCategoryOrganization), org.ListUserOrgs)
+		m.Post("/orgs", reqToken(), reqTokenScope(models.AccessTokenScopeCategoryOrganization), bind(api.CreateOrgOption{}), org.Create)
+		m.Get("/orgs", reqTokenScope(models.AccessTokenScopeCategoryOrganization), org.GetAll)
+		m.Combo("/orgs/:org/repos", reqTokenScope(models.AccessTokenScopeCategoryRepository), orgAssignment(true)).Get(user.ListOrgRepos).
+			Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 		m.Group("/orgs/:org", func() {
 			m.Combo("").Get(org.Get).
 				Patch(reqToken(), reqOrgOwnership(), bind(api.EditOrgOption{}), org.Edit).
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
-			m.Combo("/repos").Get(user.ListOrgRepos).
-				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 			m.Group("/members", func() {
 				m.Get("", org.ListMembers)
 				m.Combo("/:username").Get(org.IsMember).
@@ -912,7 +1020,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
 			}, reqToken(), reqOrgOwnership())
-		}, orgAssignment(true))
+			m.Group("/blocks", func() {
+				m.Get("", org.ListBlockedUsers)
+				m.Combo("/:username").Get(org.CheckUserBlocked).Put(org.BlockUser).Delete(org.UnblockUser)
+			}, reqToken(), reqOrgOwnership())
+		}, reqTokenScope(models.AccessTokenScopeCategoryOrganization), orgAssignment(true))
 		m.Group("/teams/:teamid", func() {
 			m.Combo("").Get(org.GetTeam).
 				Patch(reqOrgOwnership(), bind(api.EditTeamOption{}), org.EditTeam).
@@ -930,13 +1042,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Put(org.AddTeamRepository).
 					Delete(org.RemoveTeamRepository)
 			})
-		}, orgAssignment(false, true), reqToken(), reqTeamMembership())
+		}, reqTokenScope(models.AccessTokenScopeCategoryOrganization), orgAssignment(false, true), reqToken(), reqTeamMembership())
 
 		m.Any("/*", func(ctx *context.APIContext) {
 			ctx.NotFound()
 		})
 
 		m.Group("/admin", func() {
+			m.Get("/audit_log", admin.ListAuditEvents)
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
 				m.Post("/:task", admin.PostCronTask)
@@ -957,12 +1070,12 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 				})
 			})
-		}, reqToken(), reqSiteAdmin())
+		}, reqToken(), reqTokenScope(models.AccessTokenScopeCategoryAdmin), reqSiteAdmin())
 
 		m.Group("/topics", func() {
 			m.Get("/search", repo.TopicSearch)
-		})
-	}, securityHeaders(), context.APIContexter(), sudo())
+		}, reqTokenScope(models.AccessTokenScopeCategoryRepository))
+	}, securityHeaders(), context.APIContexter(), context.APIRateLimit(ratelimit.API), sudo())
 }
 
 func securityHeaders() macaron.Handler {
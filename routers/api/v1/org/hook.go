@@ -182,5 +182,10 @@ func DeleteHook(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:   models.AuditWebhookDelete,
+		OwnerID:  org.ID,
+		TargetID: hookID,
+	})
 	ctx.Status(http.StatusNoContent)
 }
@@ -299,6 +299,13 @@ func DeleteMember(ctx *context.APIContext) {
 	}
 	if err := ctx.Org.Organization.RemoveMember(member.ID); err != nil {
 		ctx.Error(http.StatusInternalServerError, "RemoveMember", err)
+		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditOrgMemberRemove,
+		OwnerID:    ctx.Org.Organization.ID,
+		TargetID:   member.ID,
+		TargetName: member.Name,
+	})
 	ctx.Status(http.StatusNoContent)
 }
@@ -185,6 +185,13 @@ func CreateTeam(ctx *context.APIContext, form api.CreateTeamOption) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditTeamCreate,
+		OwnerID:     team.OrgID,
+		TargetID:    team.ID,
+		TargetName:  team.Name,
+		Description: team.Authorize.String(),
+	})
 
 	ctx.JSON(http.StatusCreated, convert.ToTeam(team))
 }
@@ -265,6 +272,13 @@ func EditTeam(ctx *context.APIContext, form api.EditTeamOption) {
 		ctx.Error(http.StatusInternalServerError, "EditTeam", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditTeamUpdate,
+		OwnerID:     team.OrgID,
+		TargetID:    team.ID,
+		TargetName:  team.Name,
+		Description: team.Authorize.String(),
+	})
 	ctx.JSON(http.StatusOK, convert.ToTeam(team))
 }
 
@@ -288,6 +302,7 @@ func DeleteTeam(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteTeam", err)
 		return
 	}
+	auditTeamEvent(ctx, models.AuditTeamDelete, 0, "")
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -402,6 +417,8 @@ func AddTeamMember(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
@@ -410,9 +427,14 @@ func AddTeamMember(ctx *context.APIContext) {
 		return
 	}
 	if err := ctx.Org.Team.AddMember(u.ID); err != nil {
-		ctx.Error(http.StatusInternalServerError, "AddMember", err)
+		if models.IsErrBlockedByUser(err) {
+			ctx.Error(http.StatusForbidden, "AddMember", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddMember", err)
+		}
 		return
 	}
+	auditTeamEvent(ctx, models.AuditTeamMemberAdd, 0, u.Name)
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -450,6 +472,7 @@ func RemoveTeamMember(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "RemoveMember", err)
 		return
 	}
+	auditTeamEvent(ctx, models.AuditTeamMemberRemove, 0, u.Name)
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -556,6 +579,7 @@ func AddTeamRepository(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "AddRepository", err)
 		return
 	}
+	auditTeamEvent(ctx, models.AuditRepoTeamAdd, repo.ID, "")
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -606,9 +630,22 @@ func RemoveTeamRepository(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "RemoveRepository", err)
 		return
 	}
+	auditTeamEvent(ctx, models.AuditRepoTeamRemove, repo.ID, "")
 	ctx.Status(http.StatusNoContent)
 }
 
+// auditTeamEvent records an audit event of the team of the request
+func auditTeamEvent(ctx *context.APIContext, action models.AuditAction, repoID int64, description string) {
+	ctx.Audit(&models.AuditEvent{
+		Action:      action,
+		OwnerID:     ctx.Org.Team.OrgID,
+		RepoID:      repoID,
+		TargetID:    ctx.Org.Team.ID,
+		TargetName:  ctx.Org.Team.Name,
+		Description: description,
+	})
+}
+
 // SearchTeam api for searching teams
 func SearchTeam(ctx *context.APIContext) {
 	// swagger:operation GET /orgs/{org}/teams/search organization teamSearch
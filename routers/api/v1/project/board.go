@@ -0,0 +1,233 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getProjectBoard returns the board of the project, the board with ID 0 holds the cards not
+// assigned to any other board. It returns nil if the project has no such board
+func getProjectBoard(p *models.Project, boardID int64) (*models.ProjectBoard, error) {
+	if boardID == 0 {
+		return models.GetUncategorizedBoard(p.ID)
+	}
+
+	board, err := models.GetProjectBoard(boardID)
+	if err != nil {
+		if models.IsErrProjectBoardNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if board.ProjectID != p.ID {
+		return nil, nil
+	}
+	return board, nil
+}
+
+// getProjectBoardFromParams returns the board of the URL, which must not be the one holding
+// the cards not assigned to any other board. If there is an error, write to `ctx` accordingly
+func getProjectBoardFromParams(ctx *context.APIContext, p *models.Project) *models.ProjectBoard {
+	boardID := ctx.ParamsInt64(":board")
+	if boardID <= 0 {
+		ctx.NotFound()
+		return nil
+	}
+	board, err := getProjectBoard(p, boardID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectBoard", err)
+		return nil
+	} else if board == nil {
+		ctx.NotFound()
+		return nil
+	}
+	return board
+}
+
+// ListBoards list the columns of a project
+func ListBoards(ctx *context.APIContext) {
+	// swagger:operation GET /projects/{id}/boards project projectListBoards
+	// ---
+	// summary: List the columns of a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectBoardList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getProjectFromParams(ctx, models.AccessModeRead)
+	if ctx.Written() {
+		return
+	}
+
+	boards, err := models.GetProjectBoards(p.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectBoards", err)
+		return
+	}
+
+	results := make([]*api.ProjectBoard, len(boards))
+	for i := range boards {
+		results[i] = convert.ToProjectBoard(boards[i])
+	}
+	ctx.JSON(http.StatusOK, &results)
+}
+
+// CreateBoard add a column to a project
+func CreateBoard(ctx *context.APIContext, form api.CreateProjectBoardOption) {
+	// swagger:operation POST /projects/{id}/boards project projectCreateBoard
+	// ---
+	// summary: Add a column to a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectBoardOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectBoard"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+
+	board := &models.ProjectBoard{
+		ProjectID: p.ID,
+		Title:     form.Title,
+		CreatorID: ctx.User.ID,
+	}
+	if err := models.NewProjectBoard(board); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewProjectBoard", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToProjectBoard(board))
+}
+
+// EditBoard rename a column of a project
+func EditBoard(ctx *context.APIContext, form api.CreateProjectBoardOption) {
+	// swagger:operation PATCH /projects/{id}/boards/{board} project projectEditBoard
+	// ---
+	// summary: Rename a column of a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: board
+	//   in: path
+	//   description: id of the column
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateProjectBoardOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectBoard"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardFromParams(ctx, p)
+	if ctx.Written() {
+		return
+	}
+
+	board.Title = form.Title
+	if err := models.UpdateProjectBoard(board); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateProjectBoard", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToProjectBoard(board))
+}
+
+// DeleteBoard delete a column of a project
+func DeleteBoard(ctx *context.APIContext) {
+	// swagger:operation DELETE /projects/{id}/boards/{board} project projectDeleteBoard
+	// ---
+	// summary: Delete a column of a project, its cards are no longer assigned to a column
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: board
+	//   in: path
+	//   description: id of the column
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardFromParams(ctx, p)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectBoardByID(board.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteProjectBoardByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,296 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getVisibleIssue returns the issue or pull request if the doer is allowed to read it,
+// otherwise nil
+func getVisibleIssue(ctx *context.APIContext, issueID int64) (*models.Issue, error) {
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err = issue.LoadRepo(); err != nil {
+		return nil, err
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) || !ctx.CanTokenAccessRepo(issue.RepoID) {
+		return nil, nil
+	}
+	return issue, nil
+}
+
+// ListCards list the issues and pull requests on a project board
+func ListCards(ctx *context.APIContext) {
+	// swagger:operation GET /projects/{id}/cards project projectListCards
+	// ---
+	// summary: List the issues and pull requests on a project board
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectCardList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getProjectFromParams(ctx, models.AccessModeRead)
+	if ctx.Written() {
+		return
+	}
+
+	uncategorizedBoard, err := models.GetUncategorizedBoard(p.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUncategorizedBoard", err)
+		return
+	}
+	boards, err := models.GetProjectBoards(p.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectBoards", err)
+		return
+	}
+	allBoards := append(models.ProjectBoardList{uncategorizedBoard}, boards...)
+	if _, err = allBoards.LoadIssuesVisibleTo(ctx.User); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssuesVisibleTo", err)
+		return
+	}
+
+	results := make([]*api.ProjectCard, 0, 10)
+	for _, board := range allBoards {
+		for _, issue := range board.Issues {
+			if !ctx.CanTokenAccessRepo(issue.RepoID) {
+				continue
+			}
+			results = append(results, &api.ProjectCard{
+				BoardID: board.ID,
+				Issue:   convert.ToAPIIssue(issue),
+			})
+		}
+	}
+	ctx.JSON(http.StatusOK, &results)
+}
+
+// AddCard put an issue or pull request on a project board
+func AddCard(ctx *context.APIContext, form api.AddProjectCardOption) {
+	// swagger:operation POST /projects/{id}/cards project projectAddCard
+	// ---
+	// summary: Put an issue or pull request on a project board
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/AddProjectCardOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ProjectCard"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := getVisibleIssue(ctx, form.IssueID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
+		return
+	} else if issue == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "AddCard", "issue does not exist")
+		return
+	}
+
+	board, err := getProjectBoard(p, form.BoardID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectBoard", err)
+		return
+	} else if board == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "AddCard", "board does not belong to the project")
+		return
+	}
+
+	if err = models.AddIssueToProject(issue, ctx.User, p); err != nil {
+		if models.IsErrProjectIssueNotAllowed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "AddIssueToProject", err)
+		} else if models.IsErrBlockedByUser(err) {
+			ctx.Error(http.StatusForbidden, "AddIssueToProject", "user is blocked")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddIssueToProject", err)
+		}
+		return
+	}
+	if err = models.MoveIssueAcrossProjectBoards(issue, board); err != nil {
+		ctx.Error(http.StatusInternalServerError, "MoveIssueAcrossProjectBoards", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ProjectCard{
+		BoardID: board.ID,
+		Issue:   convert.ToAPIIssue(issue),
+	})
+}
+
+// getIssueFromParams returns the issue or pull request of the URL if the doer is allowed to read it.
+// If there is an error, write to `ctx` accordingly
+func getIssueFromParams(ctx *context.APIContext) *models.Issue {
+	issue, err := getVisibleIssue(ctx, ctx.ParamsInt64(":issue"))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
+		return nil
+	} else if issue == nil {
+		ctx.NotFound()
+		return nil
+	}
+	return issue
+}
+
+// MoveCard move an issue or pull request to another column of its project board
+func MoveCard(ctx *context.APIContext, form api.MoveProjectCardOption) {
+	// swagger:operation PATCH /projects/{id}/cards/{issue} project projectMoveCard
+	// ---
+	// summary: Move an issue or pull request to another column of its project board
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: issue
+	//   in: path
+	//   description: id of the issue or pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MoveProjectCardOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProjectCard"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+	issue := getIssueFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	board, err := getProjectBoard(p, form.BoardID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectBoard", err)
+		return
+	} else if board == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "MoveCard", "board does not belong to the project")
+		return
+	}
+
+	if err = models.MoveIssueAcrossProjectBoards(issue, board); err != nil {
+		if models.IsErrProjectIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "MoveIssueAcrossProjectBoards", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ProjectCard{
+		BoardID: board.ID,
+		Issue:   convert.ToAPIIssue(issue),
+	})
+}
+
+// RemoveCard take an issue or pull request off a project board
+func RemoveCard(ctx *context.APIContext) {
+	// swagger:operation DELETE /projects/{id}/cards/{issue} project projectRemoveCard
+	// ---
+	// summary: Take an issue or pull request off a project board
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: issue
+	//   in: path
+	//   description: id of the issue or pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+	issue := getIssueFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.RemoveIssueFromProject(issue, ctx.User, p); err != nil {
+		if models.IsErrProjectIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveIssueFromProject", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package project
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// getProjectFromParams returns the project of the URL if the doer has at least the given access
+// mode on it. If there is an error, write to `ctx` accordingly
+func getProjectFromParams(ctx *context.APIContext, mode models.AccessMode) *models.Project {
+	if models.UnitTypeProjects.UnitGlobalDisabled() {
+		ctx.NotFound()
+		return nil
+	}
+
+	p, err := models.GetProjectByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProjectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProjectByID", err)
+		}
+		return nil
+	}
+
+	accessMode, err := models.GetProjectAccessMode(p, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjectAccessMode", err)
+		return nil
+	}
+	if accessMode < models.AccessModeRead || (p.Type == models.ProjectTypeRepository && !ctx.CanTokenAccessRepo(p.RepoID)) {
+		ctx.NotFound()
+		return nil
+	}
+	if accessMode < mode {
+		ctx.Error(http.StatusForbidden, "GetProjectAccessMode", "no permission to change the project")
+		return nil
+	}
+	return p
+}
+
+// GetProject get a project
+func GetProject(ctx *context.APIContext) {
+	// swagger:operation GET /projects/{id} project projectGet
+	// ---
+	// summary: Get a project
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getProjectFromParams(ctx, models.AccessModeRead)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToProject(p, ctx.User))
+}
+
+// EditProject edit a project
+func EditProject(ctx *context.APIContext, form api.EditProjectOption) {
+	// swagger:operation PATCH /projects/{id} project projectEdit
+	// ---
+	// summary: Edit a project
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditProjectOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Project"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Title != nil && (len(*form.Title) == 0 || len(*form.Title) > 100) {
+		ctx.Error(http.StatusUnprocessableEntity, "EditProject", "title must have 1 to 100 characters")
+		return
+	}
+	if form.State != nil && *form.State != string(api.StateOpen) && *form.State != string(api.StateClosed) {
+		ctx.Error(http.StatusUnprocessableEntity, "EditProject", "state must be open or closed")
+		return
+	}
+
+	if form.Title != nil || form.Description != nil {
+		if form.Title != nil {
+			p.Title = *form.Title
+		}
+		if form.Description != nil {
+			p.Description = *form.Description
+		}
+		if err := models.UpdateProject(p); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateProject", err)
+			return
+		}
+	}
+
+	if form.State != nil {
+		if isClosed := *form.State == string(api.StateClosed); isClosed != p.IsClosed {
+			if err := models.ChangeProjectStatus(p, isClosed); err != nil {
+				ctx.Error(http.StatusInternalServerError, "ChangeProjectStatus", err)
+				return
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToProject(p, ctx.User))
+}
+
+// DeleteProject delete a project
+func DeleteProject(ctx *context.APIContext) {
+	// swagger:operation DELETE /projects/{id} project projectDelete
+	// ---
+	// summary: Delete a project
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the project
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	p := getProjectFromParams(ctx, models.AccessModeWrite)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectByID(p.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteProjectByID", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
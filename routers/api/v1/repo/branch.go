@@ -530,6 +530,7 @@ func CreateBranchProtection(ctx *context.APIContext, form api.CreateBranchProtec
 		RequireSignedCommits:     form.RequireSignedCommits,
 		ProtectedFilePatterns:    form.ProtectedFilePatterns,
 		BlockOnOutdatedBranch:    form.BlockOnOutdatedBranch,
+		BlockOnCodeOwnerReviews:  form.BlockOnCodeOwnerReviews,
 	}
 
 	err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
@@ -544,6 +545,11 @@ func CreateBranchProtection(ctx *context.APIContext, form api.CreateBranchProtec
 		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranch", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditBranchProtectionUpdate,
+		TargetID:   protectBranch.ID,
+		TargetName: protectBranch.BranchName,
+	})
 
 	// Reload from db to get all whitelists
 	bp, err := models.GetProtectedBranchBy(ctx.Repo.Repository.ID, form.BranchName)
@@ -669,6 +675,10 @@ func EditBranchProtection(ctx *context.APIContext, form api.EditBranchProtection
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
 
+	if form.BlockOnCodeOwnerReviews != nil {
+		protectBranch.BlockOnCodeOwnerReviews = *form.BlockOnCodeOwnerReviews
+	}
+
 	var whitelistUsers []int64
 	if form.PushWhitelistUsernames != nil {
 		whitelistUsers, err = models.GetUserIDsByNames(form.PushWhitelistUsernames, false)
@@ -767,6 +777,11 @@ func EditBranchProtection(ctx *context.APIContext, form api.EditBranchProtection
 		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranch", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditBranchProtectionUpdate,
+		TargetID:   protectBranch.ID,
+		TargetName: protectBranch.BranchName,
+	})
 
 	// Reload from db to ensure get all whitelists
 	bp, err := models.GetProtectedBranchBy(repo.ID, bpName)
@@ -827,6 +842,11 @@ func DeleteBranchProtection(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteProtectedBranch", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditBranchProtectionDelete,
+		TargetID:   bp.ID,
+		TargetName: bp.BranchName,
+	})
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -159,15 +159,31 @@ func AddCollaborator(ctx *context.APIContext, form api.AddCollaboratorOption) {
 	}
 
 	if err := ctx.Repo.Repository.AddCollaborator(collaborator); err != nil {
-		ctx.Error(http.StatusInternalServerError, "AddCollaborator", err)
+		if models.IsErrBlockedByUser(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddCollaborator", err)
+		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditCollaboratorAdd,
+		TargetID:   collaborator.ID,
+		TargetName: collaborator.Name,
+	})
 
 	if form.Permission != nil {
-		if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(collaborator.ID, models.ParseAccessMode(*form.Permission)); err != nil {
+		mode := models.ParseAccessMode(*form.Permission)
+		if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(collaborator.ID, mode); err != nil {
 			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationAccessMode", err)
 			return
 		}
+		ctx.Audit(&models.AuditEvent{
+			Action:      models.AuditCollaboratorAccessChange,
+			TargetID:    collaborator.ID,
+			TargetName:  collaborator.Name,
+			Description: mode.String(),
+		})
 	}
 
 	ctx.Status(http.StatusNoContent)
@@ -216,5 +232,10 @@ func DeleteCollaborator(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "DeleteCollaboration", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditCollaboratorRemove,
+		TargetID:   collaborator.ID,
+		TargetName: collaborator.Name,
+	})
 	ctx.Status(http.StatusNoContent)
 }
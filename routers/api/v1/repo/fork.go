@@ -120,6 +120,10 @@ func CreateFork(ctx *context.APIContext, form api.CreateForkOption) {
 
 	fork, err := repo_service.ForkRepository(ctx.User, forker, repo, repo.Name, repo.Description)
 	if err != nil {
+		if models.IsErrProbationLimit(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ForkRepository", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "ForkRepository", err)
 		return
 	}
@@ -262,5 +262,6 @@ func DeleteHook(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditWebhookDelete, TargetID: ctx.ParamsInt64(":id")})
 	ctx.Status(http.StatusNoContent)
 }
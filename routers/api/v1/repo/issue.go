@@ -362,7 +362,7 @@ func GetIssue(ctx *context.APIContext) {
 	issue, err := models.GetIssueWithAttrsByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrIssueNotExist(err) {
-			ctx.NotFound()
+			redirectTransferredIssue(ctx, ctx.ParamsInt64(":index"))
 		} else {
 			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
 		}
@@ -461,6 +461,9 @@ func CreateIssue(ctx *context.APIContext, form api.CreateIssueOption) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if models.IsErrBlockedByUser(err) || models.IsErrProbationLimit(err) {
+			ctx.Error(http.StatusForbidden, "NewIssue", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewIssue", err)
 		return
@@ -189,7 +189,7 @@ func changeIssueCommentReaction(ctx *context.APIContext, form api.EditReactionOp
 		// PostIssueCommentReaction part
 		reaction, err := models.CreateCommentReaction(ctx.User, comment.Issue, comment, form.Reaction)
 		if err != nil {
-			if models.IsErrForbiddenIssueReaction(err) {
+			if models.IsErrForbiddenIssueReaction(err) || models.IsErrBlockedByUser(err) {
 				ctx.Error(http.StatusForbidden, err.Error(), err)
 			} else if models.IsErrReactionAlreadyExist(err) {
 				ctx.JSON(http.StatusOK, api.Reaction{
@@ -398,7 +398,7 @@ func changeIssueReaction(ctx *context.APIContext, form api.EditReactionOption, i
 		// PostIssueReaction part
 		reaction, err := models.CreateIssueReaction(ctx.User, issue, form.Reaction)
 		if err != nil {
-			if models.IsErrForbiddenIssueReaction(err) {
+			if models.IsErrForbiddenIssueReaction(err) || models.IsErrBlockedByUser(err) {
 				ctx.Error(http.StatusForbidden, err.Error(), err)
 			} else if models.IsErrReactionAlreadyExist(err) {
 				ctx.JSON(http.StatusOK, api.Reaction{
@@ -0,0 +1,146 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// TransferIssue moves an issue to another repository
+func TransferIssue(ctx *context.APIContext, form api.TransferIssueOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/transfer issue issueTransferIssue
+	// ---
+	// summary: Move an issue to another repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue to transfer
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/TransferIssueOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	issue.Repo = ctx.Repo.Repository
+
+	newRepo, err := models.GetRepositoryByOwnerAndName(form.NewOwner, form.NewRepo)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+	perm, err := models.GetUserRepoPermission(newRepo, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return
+	}
+	if !perm.HasAccess() || !ctx.CanTokenAccessRepo(newRepo.ID) {
+		ctx.NotFound()
+		return
+	}
+
+	if err := issue_service.TransferIssue(ctx.User, issue, newRepo); err != nil {
+		switch {
+		case models.IsErrUserDoesNotHaveAccessToRepo(err):
+			ctx.Error(http.StatusForbidden, "TransferIssue", err)
+		case models.IsErrBlockedByUser(err):
+			ctx.Error(http.StatusForbidden, "TransferIssue", "user is blocked")
+		case models.IsErrCannotTransferIssue(err):
+			ctx.Error(http.StatusUnprocessableEntity, "TransferIssue", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "TransferIssue", err)
+		}
+		return
+	}
+
+	issue, err = models.GetIssueByID(issue.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(issue))
+}
+
+// redirectTransferredIssue redirects to the API URL of the issue which had the given
+// index in the current repository before it was transferred, or responds not found if there
+// is none or the user may not read it.
+func redirectTransferredIssue(ctx *context.APIContext, index int64) {
+	issueID, err := models.LookupIssueRedirect(ctx.Repo.Repository.ID, index)
+	if err != nil {
+		if models.IsErrIssueRedirectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "LookupIssueRedirect", err)
+		}
+		return
+	}
+
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
+		}
+		return
+	}
+	if err = issue.LoadRepo(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepo", err)
+		return
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return
+	}
+	if !perm.CanRead(models.UnitTypeIssues) || !ctx.CanTokenAccessRepo(issue.RepoID) {
+		ctx.NotFound()
+		return
+	}
+	ctx.Redirect(issue.APIURL())
+}
@@ -223,6 +223,12 @@ func CreateDeployKey(ctx *context.APIContext, form api.CreateKeyOption) {
 		HandleAddKeyError(ctx, err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditDeployKeyAdd,
+		TargetID:    key.ID,
+		TargetName:  key.Name,
+		Description: key.Fingerprint,
+	})
 
 	key.Content = content
 	apiLink := composeDeployKeysAPILink(ctx.Repo.Owner.Name + "/" + ctx.Repo.Repository.Name)
@@ -265,6 +271,7 @@ func DeleteDeploykey(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditDeployKeyDelete, TargetID: ctx.ParamsInt64(":id")})
 
 	ctx.Status(http.StatusNoContent)
 }
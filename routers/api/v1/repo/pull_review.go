@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
 
@@ -288,6 +289,8 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/PullReview"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 	//   "422":
@@ -334,9 +337,9 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 
 	// create review comments
 	for _, c := range opts.Comments {
-		line := c.NewLineNum
+		line, startLine := c.NewLineNum, c.NewStartLineNum
 		if c.OldLineNum > 0 {
-			line = c.OldLineNum * -1
+			line, startLine = c.OldLineNum*-1, c.OldStartLineNum*-1
 		}
 
 		if _, err := pull_service.CreateCodeComment(
@@ -344,12 +347,21 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 			ctx.Repo.GitRepo,
 			pr.Issue,
 			line,
+			startLine,
 			c.Body,
 			c.Path,
 			true, // is review
 			0,    // no reply
 			opts.CommitID,
 		); err != nil {
+			if models.IsErrBlockedByUser(err) {
+				ctx.Error(http.StatusForbidden, "CreateCodeComment", err)
+				return
+			}
+			if models.IsErrInvalidCommentRange(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "CreateCodeComment", err)
+				return
+			}
 			ctx.ServerError("CreateCodeComment", err)
 			return
 		}
@@ -358,6 +370,10 @@ func CreatePullReview(ctx *context.APIContext, opts api.CreatePullReviewOptions)
 	// create review and associate all pending review comments
 	review, _, err := pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, opts.CommitID)
 	if err != nil {
+		if models.IsErrBlockedByUser(err) {
+			ctx.Error(http.StatusForbidden, "SubmitReview", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
 		return
 	}
@@ -409,6 +425,8 @@ func SubmitPullReview(ctx *context.APIContext, opts api.SubmitPullReviewOptions)
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/PullReview"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 	//   "422":
@@ -445,6 +463,10 @@ func SubmitPullReview(ctx *context.APIContext, opts api.SubmitPullReviewOptions)
 	// create review and associate all pending review comments
 	review, _, err = pull_service.SubmitReview(ctx.User, ctx.Repo.GitRepo, pr.Issue, reviewType, opts.Body, headCommitID)
 	if err != nil {
+		if models.IsErrBlockedByUser(err) {
+			ctx.Error(http.StatusForbidden, "SubmitReview", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "SubmitReview", err)
 		return
 	}
@@ -539,3 +561,274 @@ func prepareSingleReview(ctx *context.APIContext) (*models.Review, *models.PullR
 
 	return review, pr, false
 }
+
+// GetReviewRequests lists the pending review requests of a pull request
+func GetReviewRequests(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/requested_reviewers repository repoGetPullReviewRequests
+	// ---
+	// summary: List the users and teams requested to review a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullReviewRequests"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewersByIssueID", err)
+		return
+	}
+
+	teamReviews, err := models.GetReviewerTeamsByIssueID(pr.IssueID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetReviewerTeamsByIssueID", err)
+		return
+	}
+
+	requests := api.PullReviewRequests{
+		Users: make([]*api.User, 0, len(reviews)),
+		Teams: make([]*api.Team, 0, len(teamReviews)),
+	}
+	for _, review := range reviews {
+		if review.Type == models.ReviewTypeRequest {
+			requests.Users = append(requests.Users, convert.ToUser(review.Reviewer, ctx.IsSigned, false))
+		}
+	}
+	for _, review := range teamReviews {
+		requests.Teams = append(requests.Teams, convert.ToTeam(review.ReviewerTeam))
+	}
+
+	ctx.JSON(http.StatusOK, &requests)
+}
+
+// CreateReviewRequests create review requests to an pull request
+func CreateReviewRequests(ctx *context.APIContext, opts api.PullReviewRequestOptions) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/requested_reviewers repository repoCreatePullReviewRequests
+	// ---
+	// summary: create review requests for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/PullReviewRequestOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PullReviewList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	apiReviewRequest(ctx, opts, true)
+}
+
+// DeleteReviewRequests delete review requests to an pull request
+func DeleteReviewRequests(ctx *context.APIContext, opts api.PullReviewRequestOptions) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/requested_reviewers repository repoDeletePullReviewRequests
+	// ---
+	// summary: cancel review requests for a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/PullReviewRequestOptions"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	apiReviewRequest(ctx, opts, false)
+}
+
+func apiReviewRequest(ctx *context.APIContext, opts api.PullReviewRequestOptions, isAdd bool) {
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	if err := pr.Issue.LoadRepo(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepo", err)
+		return
+	}
+
+	reviewers := make([]*models.User, 0, len(opts.Reviewers))
+	for _, r := range opts.Reviewers {
+		var reviewer *models.User
+		if strings.Contains(r, "@") {
+			reviewer, err = models.GetUserByEmail(r)
+		} else {
+			reviewer, err = models.GetUserByName(r)
+		}
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound("UserNotExist", fmt.Sprintf("User '%s' not exist", r))
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "GetUser", err)
+			return
+		}
+
+		if err := issue_service.IsLegalReviewRequest(reviewer, ctx.User, isAdd, pr.Issue); err != nil {
+			if models.IsErrNotValidReviewRequest(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "NotValidReviewRequest", err)
+				return
+			}
+			ctx.Error(http.StatusInternalServerError, "IsLegalReviewRequest", err)
+			return
+		}
+		reviewers = append(reviewers, reviewer)
+	}
+
+	teamReviewers := make([]*models.Team, 0, len(opts.TeamReviewers))
+	if len(opts.TeamReviewers) > 0 {
+		if !ctx.Repo.Owner.IsOrganization() {
+			ctx.Error(http.StatusUnprocessableEntity, "", "Only repositories of organizations can have team reviewers")
+			return
+		}
+		for _, t := range opts.TeamReviewers {
+			team, err := models.GetTeam(ctx.Repo.Owner.ID, t)
+			if err != nil {
+				if models.IsErrTeamNotExist(err) {
+					ctx.NotFound("TeamNotExist", fmt.Sprintf("Team '%s' not exist", t))
+					return
+				}
+				ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+				return
+			}
+
+			if err := issue_service.IsLegalTeamReviewRequest(team, ctx.User, isAdd, pr.Issue); err != nil {
+				if models.IsErrNotValidReviewRequest(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "NotValidReviewRequest", err)
+					return
+				}
+				ctx.Error(http.StatusInternalServerError, "IsLegalTeamReviewRequest", err)
+				return
+			}
+			teamReviewers = append(teamReviewers, team)
+		}
+	}
+
+	reviews := make([]*models.Review, 0, len(reviewers)+len(teamReviewers))
+	for _, reviewer := range reviewers {
+		if err := issue_service.ReviewRequest(pr.Issue, ctx.User, reviewer, isAdd); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ReviewRequest", err)
+			return
+		}
+
+		if isAdd {
+			review, err := models.GetReviewerByIssueIDAndUserID(pr.IssueID, reviewer.ID)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetReviewerByIssueIDAndUserID", err)
+				return
+			}
+			review.Reviewer = reviewer
+			reviews = append(reviews, review)
+		}
+	}
+
+	for _, team := range teamReviewers {
+		if err := issue_service.TeamReviewRequest(pr.Issue, ctx.User, team, isAdd); err != nil {
+			ctx.Error(http.StatusInternalServerError, "TeamReviewRequest", err)
+			return
+		}
+
+		if isAdd {
+			review, err := models.GetTeamReviewerByIssueIDAndTeamID(pr.IssueID, team.ID)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetTeamReviewerByIssueIDAndTeamID", err)
+				return
+			}
+			if review != nil {
+				review.ReviewerTeam = team
+				reviews = append(reviews, review)
+			}
+		}
+	}
+
+	if !isAdd {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	apiReviews, err := convert.ToPullReviewList(reviews, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "convertToPullReviewList", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiReviews)
+}
@@ -250,7 +250,8 @@ func CreateUserRepo(ctx *context.APIContext, owner *models.User, opt api.CreateR
 		if models.IsErrRepoAlreadyExist(err) {
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if models.IsErrNameReserved(err) ||
-			models.IsErrNamePatternNotAllowed(err) {
+			models.IsErrNamePatternNotAllowed(err) ||
+			models.IsErrProbationLimit(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -435,6 +436,10 @@ func GetByID(ctx *context.APIContext) {
 		}
 		return
 	}
+	if !ctx.CanTokenAccessRepo(repo.ID) {
+		ctx.NotFound()
+		return
+	}
 
 	perm, err := models.GetUserRepoPermission(repo, ctx.User)
 	if err != nil {
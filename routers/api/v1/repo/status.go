@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/repofiles"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	pull_service "code.gitea.io/gitea/services/pull"
 )
 
 // NewCommitStatus creates a new CommitStatus
@@ -63,6 +64,7 @@ func NewCommitStatus(ctx *context.APIContext, form api.CreateStatusOption) {
 		ctx.Error(http.StatusInternalServerError, "CreateCommitStatus", err)
 		return
 	}
+	pull_service.AddToAutoMergeQueueByRepo(ctx.Repo.Repository)
 
 	ctx.JSON(http.StatusCreated, status.APIFormat())
 }
@@ -7,6 +7,7 @@ package repo
 import (
 	"net/http"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
@@ -104,3 +105,275 @@ func GetTag(ctx *context.APIContext) {
 		ctx.JSON(http.StatusOK, convert.ToAnnotatedTag(ctx.Repo.Repository, tag, commit))
 	}
 }
+
+// ListTagProtection lists tag protections for a repo
+func ListTagProtection(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tag_protections repository repoListTagProtection
+	// ---
+	// summary: List tag protections for a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagProtectionList"
+
+	pts, err := ctx.Repo.Repository.GetProtectedTags()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedTags", err)
+		return
+	}
+	apiPts := make([]*api.TagProtection, len(pts))
+	for i := range pts {
+		apiPts[i] = convert.ToTagProtection(pts[i])
+	}
+
+	ctx.JSON(http.StatusOK, apiPts)
+}
+
+// GetTagProtection gets a tag protection
+func GetTagProtection(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/tag_protections/{id} repository repoGetTagProtection
+	// ---
+	// summary: Get a specific tag protection for the repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the tag protection to get
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagProtection"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pt, err := ctx.Repo.Repository.GetProtectedTagByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProtectedTagNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProtectedTagByID", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToTagProtection(pt))
+}
+
+// CreateTagProtection creates a tag protection for a repo
+func CreateTagProtection(ctx *context.APIContext, form api.CreateTagProtectionOption) {
+	// swagger:operation POST /repos/{owner}/{repo}/tag_protections repository repoCreateTagProtection
+	// ---
+	// summary: Create a tag protections for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateTagProtectionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/TagProtection"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pt := &models.ProtectedTag{
+		NamePattern: form.NamePattern,
+	}
+	if !saveTagProtection(ctx, pt, form.AllowlistUsernames, form.AllowlistTeams) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToTagProtection(pt))
+}
+
+// EditTagProtection edits a tag protection for a repo
+func EditTagProtection(ctx *context.APIContext, form api.EditTagProtectionOption) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/tag_protections/{id} repository repoEditTagProtection
+	// ---
+	// summary: Edit a tag protections for a repository. Only fields that are set will be changed
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of protected tag
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditTagProtectionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TagProtection"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pt, err := ctx.Repo.Repository.GetProtectedTagByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrProtectedTagNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetProtectedTagByID", err)
+		}
+		return
+	}
+
+	if form.NamePattern != nil {
+		pt.NamePattern = *form.NamePattern
+	}
+
+	allowlistUsernames := form.AllowlistUsernames
+	if allowlistUsernames == nil {
+		if allowlistUsernames, err = models.GetUserNamesByIDs(pt.AllowlistUserIDs); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserNamesByIDs", err)
+			return
+		}
+	}
+	allowlistTeams := form.AllowlistTeams
+	if allowlistTeams == nil {
+		if allowlistTeams, err = models.GetTeamNamesByID(pt.AllowlistTeamIDs); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetTeamNamesByID", err)
+			return
+		}
+	}
+	if !saveTagProtection(ctx, pt, allowlistUsernames, allowlistTeams) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToTagProtection(pt))
+}
+
+// DeleteTagProtection deletes a tag protection for a repo
+func DeleteTagProtection(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/tag_protections/{id} repository repoDeleteTagProtection
+	// ---
+	// summary: Delete a specific tag protection for the repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of protected tag
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := ctx.Repo.Repository.DeleteProtectedTag(ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrProtectedTagNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteProtectedTag", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// saveTagProtection resolves the allowlists and saves the tag protection. It returns false if an error was written.
+func saveTagProtection(ctx *context.APIContext, pt *models.ProtectedTag, allowlistUsernames, allowlistTeams []string) bool {
+	repo := ctx.Repo.Repository
+
+	allowlistUserIDs, err := models.GetUserIDsByNames(allowlistUsernames, false)
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
+			return false
+		}
+		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
+		return false
+	}
+	var allowlistTeamIDs []int64
+	if repo.Owner.IsOrganization() {
+		allowlistTeamIDs, err = models.GetTeamIDsByNames(repo.OwnerID, allowlistTeams, false)
+		if err != nil {
+			if models.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
+				return false
+			}
+			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
+			return false
+		}
+	}
+
+	if err := models.SaveProtectedTag(repo, pt, allowlistUserIDs, allowlistTeamIDs); err != nil {
+		if models.IsErrProtectedTagPatternInvalid(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ProtectedTagPatternInvalid", err)
+			return false
+		}
+		ctx.Error(http.StatusInternalServerError, "SaveProtectedTag", err)
+		return false
+	}
+	return true
+}
@@ -37,6 +37,8 @@ type swaggerParameterBodies struct {
 	EditIssueOption api.EditIssueOption
 	// in:body
 	EditDeadlineOption api.EditDeadlineOption
+	// in:body
+	TransferIssueOption api.TransferIssueOption
 
 	// in:body
 	CreateIssueCommentOption api.CreateIssueCommentOption
@@ -138,6 +140,12 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditBranchProtectionOption api.EditBranchProtectionOption
 
+	// in:body
+	CreateTagProtectionOption api.CreateTagProtectionOption
+
+	// in:body
+	EditTagProtectionOption api.EditTagProtectionOption
+
 	// in:body
 	CreateOAuth2ApplicationOptions api.CreateOAuth2ApplicationOptions
 
@@ -150,6 +158,27 @@ type swaggerParameterBodies struct {
 	// in:body
 	SubmitPullReviewOptions api.SubmitPullReviewOptions
 
+	// in:body
+	PullReviewRequestOptions api.PullReviewRequestOptions
+
 	// in:body
 	MigrateRepoOptions api.MigrateRepoOptions
+
+	// in:body
+	CreatePushMirrorOption api.CreatePushMirrorOption
+
+	// in:body
+	CreateProjectOption api.CreateProjectOption
+
+	// in:body
+	EditProjectOption api.EditProjectOption
+
+	// in:body
+	CreateProjectBoardOption api.CreateProjectBoardOption
+
+	// in:body
+	AddProjectCardOption api.AddProjectCardOption
+
+	// in:body
+	MoveProjectCardOption api.MoveProjectCardOption
 }
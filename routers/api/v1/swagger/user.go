@@ -42,3 +42,10 @@ type swaggerResponseUserHeatmapData struct {
 	// in:body
 	Body []models.UserHeatmapData `json:"body"`
 }
+
+// BlockedUserList
+// swagger:response BlockedUserList
+type swaggerResponseBlockedUserList struct {
+	// in:body
+	Body []api.BlockedUser `json:"body"`
+}
@@ -51,10 +51,17 @@ func ListAccessTokens(ctx *context.APIContext) {
 
 	apiTokens := make([]*api.AccessToken, len(tokens))
 	for i := range tokens {
+		repoNames, err := tokens[i].RepositoryNames()
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "RepositoryNames", err)
+			return
+		}
 		apiTokens[i] = &api.AccessToken{
 			ID:             tokens[i].ID,
 			Name:           tokens[i].Name,
 			TokenLastEight: tokens[i].TokenLastEight,
+			Scopes:         tokens[i].Scope.StringSlice(),
+			Repositories:   repoNames,
 		}
 	}
 	ctx.JSON(http.StatusOK, &apiTokens)
@@ -84,13 +91,44 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 	//     properties:
 	//       name:
 	//         type: string
+	//       scopes:
+	//         description: scopes in the form "level:category", e.g. "read:repository", or "all" for full access
+	//         type: array
+	//         items:
+	//           type: string
+	//       repositories:
+	//         description: full names of the repositories the token is restricted to, e.g. "owner/repo"
+	//         type: array
+	//         items:
+	//           type: string
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/AccessToken"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: models.AccessTokenScopeAll,
+	}
+
+	if len(form.Scopes) > 0 {
+		scope, err := models.ParseAccessTokenScope(form.Scopes)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ParseAccessTokenScope", err)
+			return
+		}
+		t.Scope = scope
+	}
+
+	if err := t.SetRepositories(form.Repositories); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "SetRepositories", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetRepositories", err)
+		}
+		return
 	}
 
 	exist, err := models.AccessTokenByNameExists(t)
@@ -107,11 +145,23 @@ func CreateAccessToken(ctx *context.APIContext, form api.CreateAccessTokenOption
 		ctx.Error(http.StatusInternalServerError, "NewAccessToken", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAccessTokenCreate,
+		TargetID:   t.ID,
+		TargetName: t.Name,
+	})
+	repoNames, err := t.RepositoryNames()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepositoryNames", err)
+		return
+	}
 	ctx.JSON(http.StatusCreated, &api.AccessToken{
 		Name:           t.Name,
 		Token:          t.Token,
 		ID:             t.ID,
 		TokenLastEight: t.TokenLastEight,
+		Scopes:         t.Scope.StringSlice(),
+		Repositories:   repoNames,
 	})
 }
 
@@ -176,6 +226,7 @@ func DeleteAccessToken(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditAccessTokenDelete, TargetID: tokenID})
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -230,13 +230,19 @@ func Follow(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 
 	target := GetUserByParams(ctx)
 	if ctx.Written() {
 		return
 	}
 	if err := models.FollowUser(ctx.User.ID, target.ID); err != nil {
-		ctx.Error(http.StatusInternalServerError, "FollowUser", err)
+		if models.IsErrBlockedByUser(err) {
+			ctx.Error(http.StatusForbidden, "FollowUser", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "FollowUser", err)
+		}
 		return
 	}
 	ctx.Status(http.StatusNoContent)
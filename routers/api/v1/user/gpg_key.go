@@ -123,6 +123,14 @@ func CreateUserGPGKey(ctx *context.APIContext, form api.CreateGPGKeyOption, uid
 		HandleAddGPGKeyError(ctx, err)
 		return
 	}
+	for _, key := range keys {
+		ctx.Audit(&models.AuditEvent{
+			Action:     models.AuditGPGKeyAdd,
+			OwnerID:    uid,
+			TargetID:   key.ID,
+			TargetName: key.KeyID,
+		})
+	}
 	ctx.JSON(http.StatusCreated, convert.ToGPGKey(keys[0]))
 }
 
@@ -182,6 +190,7 @@ func DeleteGPGKey(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditGPGKeyDelete, TargetID: ctx.ParamsInt64(":id")})
 
 	ctx.Status(http.StatusNoContent)
 }
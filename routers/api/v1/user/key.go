@@ -214,6 +214,13 @@ func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid
 		repo.HandleAddKeyError(ctx, err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditPublicKeyAdd,
+		OwnerID:     uid,
+		TargetID:    key.ID,
+		TargetName:  key.Name,
+		Description: key.Fingerprint,
+	})
 	apiLink := composePublicKeysAPILink()
 	apiKey := convert.ToPublicKey(apiLink, key)
 	if ctx.User.IsAdmin || ctx.User.ID == key.OwnerID {
@@ -277,6 +284,7 @@ func DeletePublicKey(ctx *context.APIContext) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditPublicKeyDelete, TargetID: ctx.ParamsInt64(":id")})
 
 	ctx.Status(http.StatusNoContent)
 }
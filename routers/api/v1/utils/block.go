@@ -0,0 +1,104 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListBlockedUsers writes the users blocked by the blocker
+func ListBlockedUsers(ctx *context.APIContext, blocker *models.User) {
+	listOptions := GetListOptions(ctx)
+
+	blocks, err := models.GetBlockedUsers(blocker.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBlockedUsers", err)
+		return
+	}
+	count, err := models.CountBlockedUsers(blocker.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CountBlockedUsers", err)
+		return
+	}
+
+	results := make([]*api.BlockedUser, len(blocks))
+	for i := range blocks {
+		results[i] = convert.ToBlockedUser(blocks[i], ctx.User)
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &results)
+}
+
+// getBlockee returns the user named by the username parameter. If there is an error, write to
+// `ctx` accordingly
+func getBlockee(ctx *context.APIContext) *models.User {
+	u, err := models.GetUserByName(ctx.Params(":username"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return nil
+	}
+	return u
+}
+
+// CheckUserBlocked writes 204 if the blocker has blocked the user named by the username
+// parameter, 404 otherwise
+func CheckUserBlocked(ctx *context.APIContext, blocker *models.User) {
+	blockee := getBlockee(ctx)
+	if ctx.Written() {
+		return
+	}
+	isBlocked, err := models.IsBlocked(blocker.ID, blockee.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsBlocked", err)
+		return
+	} else if !isBlocked {
+		ctx.NotFound()
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// BlockUser blocks the user named by the username parameter for the blocker
+func BlockUser(ctx *context.APIContext, blocker *models.User) {
+	blockee := getBlockee(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.BlockUser(blocker, blockee, ctx.Query("note")); err != nil {
+		if models.IsErrCannotBlockUser(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "BlockUser", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnblockUser removes the block of the user named by the username parameter by the blocker
+func UnblockUser(ctx *context.APIContext, blocker *models.User) {
+	blockee := getBlockee(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := models.UnblockUser(blocker.ID, blockee.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnblockUser", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -167,6 +167,7 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 		ctx.Error(http.StatusInternalServerError, "CreateWebhook", err)
 		return nil, false
 	}
+	ctx.Audit(w.AuditEvent(models.AuditWebhookCreate))
 	return w, true
 }
 
@@ -270,5 +271,6 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 		ctx.Error(http.StatusInternalServerError, "UpdateWebhook", err)
 		return false
 	}
+	ctx.Audit(w.AuditEvent(models.AuditWebhookUpdate))
 	return true
 }
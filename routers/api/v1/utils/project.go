@@ -0,0 +1,120 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var projectBoardTypes = map[string]models.ProjectBoardType{
+	"":             models.ProjectBoardTypeNone,
+	"none":         models.ProjectBoardTypeNone,
+	"basic_kanban": models.ProjectBoardTypeBasicKanban,
+	"bug_triage":   models.ProjectBoardTypeBugTriage,
+}
+
+// getOwnerProjectsAccessMode returns the access mode the doer has on the projects of the owner
+// or writes not found if projects are disabled or the doer may not see them
+func getOwnerProjectsAccessMode(ctx *context.APIContext, owner *models.User) models.AccessMode {
+	if models.UnitTypeProjects.UnitGlobalDisabled() {
+		ctx.NotFound()
+		return models.AccessModeNone
+	}
+	mode, err := models.GetOwnerProjectsAccessMode(owner, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOwnerProjectsAccessMode", err)
+		return models.AccessModeNone
+	}
+	if mode < models.AccessModeRead {
+		ctx.NotFound()
+	}
+	return mode
+}
+
+// ListOwnerProjects writes the projects of a user or an organization
+func ListOwnerProjects(ctx *context.APIContext, owner *models.User) {
+	if getOwnerProjectsAccessMode(ctx, owner); ctx.Written() {
+		return
+	}
+
+	listOptions := GetListOptions(ctx)
+	opts := models.ProjectSearchOptions{
+		OwnerID:  owner.ID,
+		Page:     listOptions.Page,
+		PageSize: listOptions.PageSize,
+	}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	switch api.StateType(ctx.Query("state")) {
+	case api.StateClosed:
+		opts.IsClosed = util.OptionalBoolTrue
+	case api.StateAll:
+		opts.IsClosed = util.OptionalBoolNone
+	default:
+		opts.IsClosed = util.OptionalBoolFalse
+	}
+
+	projects, count, err := models.GetProjects(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProjects", err)
+		return
+	}
+
+	results := make([]*api.Project, len(projects))
+	for i := range projects {
+		projects[i].Owner = owner
+		results[i] = convert.ToProject(projects[i], ctx.User)
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.Header().Set("X-Total-Count", fmt.Sprintf("%d", count))
+	ctx.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link")
+	ctx.JSON(http.StatusOK, &results)
+}
+
+// CreateOwnerProject creates a project of a user or an organization
+func CreateOwnerProject(ctx *context.APIContext, owner *models.User, form api.CreateProjectOption) {
+	mode := getOwnerProjectsAccessMode(ctx, owner)
+	if ctx.Written() {
+		return
+	}
+	if mode < models.AccessModeWrite {
+		ctx.Error(http.StatusForbidden, "CreateProject", "no permission to create projects")
+		return
+	}
+
+	boardType, ok := projectBoardTypes[form.BoardType]
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "CreateProject", fmt.Sprintf("unknown board type %q", form.BoardType))
+		return
+	}
+
+	p := &models.Project{
+		OwnerID:     owner.ID,
+		Title:       form.Title,
+		Description: form.Description,
+		CreatorID:   ctx.User.ID,
+		BoardType:   boardType,
+		Type:        models.ProjectTypeIndividual,
+		Owner:       owner,
+	}
+	if owner.IsOrganization() {
+		p.Type = models.ProjectTypeOrganization
+	}
+	if err := models.NewProject(p); err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewProject", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToProject(p, ctx.User))
+}
@@ -12,7 +12,9 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/migrations"
+	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/auth/sso"
+	"code.gitea.io/gitea/modules/auth/webauthn"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/cron"
 	"code.gitea.io/gitea/modules/eventsource"
@@ -26,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/markup/external"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/options"
+	"code.gitea.io/gitea/modules/ratelimit"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/ssh"
 	"code.gitea.io/gitea/modules/storage"
@@ -35,6 +38,7 @@ import (
 	"code.gitea.io/gitea/services/mailer"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	pull_service "code.gitea.io/gitea/services/pull"
+	"code.gitea.io/gitea/services/registration"
 	"code.gitea.io/gitea/services/repository"
 
 	"gitea.com/macaron/i18n"
@@ -64,6 +68,12 @@ func NewServices() {
 	}
 	mailer.NewContext()
 	_ = cache.NewContext()
+	if err := ratelimit.Init(); err != nil {
+		log.Fatal("rate limit init failed: %v", err)
+	}
+	if err := registration.Init(); err != nil {
+		log.Fatal("registration checks init failed: %v", err)
+	}
 	notification.NewContext()
 }
 
@@ -147,6 +157,14 @@ func GlobalInit(ctx context.Context) {
 		if err := models.InitOAuth2(); err != nil {
 			log.Fatal("Failed to initialize OAuth2 support: %v", err)
 		}
+		if setting.OAuth2.Enable {
+			if err := oauth2.InitSigningKey(); err != nil {
+				log.Fatal("Failed to initialize OAuth2 signing key: %v", err)
+			}
+		}
+		if err := webauthn.Init(); err != nil {
+			log.Fatal("Failed to initialize WebAuthn: %v", err)
+		}
 
 		models.NewRepoContext()
 
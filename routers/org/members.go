@@ -120,6 +120,17 @@ func MembersAction(ctx *context.Context) {
 		return
 	}
 
+	switch ctx.Params(":action") {
+	case "remove":
+		ctx.Audit(&models.AuditEvent{Action: models.AuditOrgMemberRemove, TargetID: uid})
+	case "leave":
+		ctx.Audit(&models.AuditEvent{
+			Action:     models.AuditOrgMemberRemove,
+			TargetID:   ctx.User.ID,
+			TargetName: ctx.User.Name,
+		})
+	}
+
 	if ctx.Params(":action") != "leave" {
 		ctx.Redirect(ctx.Org.OrgLink + "/members")
 	} else {
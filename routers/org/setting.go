@@ -26,6 +26,10 @@ const (
 	tplSettingsHooks base.TplName = "org/settings/hooks"
 	// tplSettingsLabels template path for render labels settings
 	tplSettingsLabels base.TplName = "org/settings/labels"
+	// tplSettingsAuditLog template path for render audit log
+	tplSettingsAuditLog base.TplName = "org/settings/audit_log"
+	// tplSettingsBlockedUsers template path for render blocked users
+	tplSettingsBlockedUsers base.TplName = "org/settings/blocked_users"
 )
 
 // Settings render the main settings page
@@ -155,6 +159,9 @@ func SettingsDelete(ctx *context.Context) {
 			if models.IsErrUserOwnRepos(err) {
 				ctx.Flash.Error(ctx.Tr("form.org_still_own_repo"))
 				ctx.Redirect(ctx.Org.OrgLink + "/settings/delete")
+			} else if models.IsErrUserOwnPackages(err) {
+				ctx.Flash.Error(ctx.Tr("form.org_still_own_packages"))
+				ctx.Redirect(ctx.Org.OrgLink + "/settings/delete")
 			} else {
 				ctx.ServerError("DeleteOrganization", err)
 			}
@@ -185,11 +192,38 @@ func Webhooks(ctx *context.Context) {
 	ctx.HTML(200, tplSettingsHooks)
 }
 
+// AuditLog render the security events of the organization and its repositories
+func AuditLog(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsAuditLog"] = true
+
+	userSetting.RenderAuditLog(ctx, &models.FindAuditEventsOptions{OwnerID: ctx.Org.Organization.ID}, tplSettingsAuditLog)
+}
+
+// BlockedUsers render the users blocked by the organization
+func BlockedUsers(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("org.settings")
+	ctx.Data["PageIsSettingsBlockedUsers"] = true
+
+	userSetting.RenderBlockedUsers(ctx, ctx.Org.Organization, tplSettingsBlockedUsers)
+}
+
+// BlockedUsersPost response for blocking a user
+func BlockedUsersPost(ctx *context.Context, form auth.BlockUserForm) {
+	userSetting.HandleBlockUser(ctx, ctx.Org.Organization, form, ctx.Org.OrgLink+"/settings/blocked_users")
+}
+
+// UnblockUser response for unblocking a user
+func UnblockUser(ctx *context.Context) {
+	userSetting.HandleUnblockUser(ctx, ctx.Org.Organization, ctx.Org.OrgLink+"/settings/blocked_users")
+}
+
 // DeleteWebhook response for delete webhook
 func DeleteWebhook(ctx *context.Context) {
 	if err := models.DeleteWebhookByOrgID(ctx.Org.Organization.ID, ctx.QueryInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteWebhookByOrgID: " + err.Error())
 	} else {
+		ctx.Audit(&models.AuditEvent{Action: models.AuditWebhookDelete, TargetID: ctx.QueryInt64("id")})
 		ctx.Flash.Success(ctx.Tr("repo.settings.webhook_deletion_success"))
 	}
 
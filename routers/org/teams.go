@@ -58,6 +58,8 @@ func TeamsAction(ctx *context.Context) {
 
 	page := ctx.Query("page")
 	var err error
+	var auditAction models.AuditAction
+	var member string
 	switch ctx.Params(":action") {
 	case "join":
 		if !ctx.Org.IsOwner {
@@ -65,14 +67,20 @@ func TeamsAction(ctx *context.Context) {
 			return
 		}
 		err = ctx.Org.Team.AddMember(ctx.User.ID)
+		auditAction, member = models.AuditTeamMemberAdd, ctx.User.Name
 	case "leave":
 		err = ctx.Org.Team.RemoveMember(ctx.User.ID)
+		auditAction, member = models.AuditTeamMemberRemove, ctx.User.Name
 	case "remove":
 		if !ctx.Org.IsOwner {
 			ctx.Error(404)
 			return
 		}
+		if u, err := models.GetUserByID(uid); err == nil {
+			member = u.Name
+		}
 		err = ctx.Org.Team.RemoveMember(uid)
+		auditAction = models.AuditTeamMemberRemove
 		page = "team"
 	case "add":
 		if !ctx.Org.IsOwner {
@@ -102,14 +110,26 @@ func TeamsAction(ctx *context.Context) {
 			ctx.Flash.Error(ctx.Tr("org.teams.add_duplicate_users"))
 		} else {
 			err = ctx.Org.Team.AddMember(u.ID)
+			auditAction, member = models.AuditTeamMemberAdd, u.Name
 		}
 
 		page = "team"
 	}
 
+	if err == nil && auditAction != "" {
+		ctx.Audit(&models.AuditEvent{
+			Action:      auditAction,
+			TargetID:    ctx.Org.Team.ID,
+			TargetName:  ctx.Org.Team.Name,
+			Description: member,
+		})
+	}
+
 	if err != nil {
 		if models.IsErrLastOrgOwner(err) {
 			ctx.Flash.Error(ctx.Tr("form.last_org_owner"))
+		} else if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("org.teams.add_blocked_user"))
 		} else {
 			log.Error("Action(%s): %v", ctx.Params(":action"), err)
 			ctx.JSON(200, map[string]interface{}{
@@ -138,6 +158,11 @@ func TeamsRepoAction(ctx *context.Context) {
 	}
 
 	var err error
+	event := &models.AuditEvent{
+		OwnerID:    ctx.Org.Organization.ID,
+		TargetID:   ctx.Org.Team.ID,
+		TargetName: ctx.Org.Team.Name,
+	}
 	action := ctx.Params(":action")
 	switch action {
 	case "add":
@@ -154,12 +179,16 @@ func TeamsRepoAction(ctx *context.Context) {
 			return
 		}
 		err = ctx.Org.Team.AddRepository(repo)
+		event.Action, event.RepoID = models.AuditRepoTeamAdd, repo.ID
 	case "remove":
-		err = ctx.Org.Team.RemoveRepository(com.StrTo(ctx.Query("repoid")).MustInt64())
+		event.Action, event.RepoID = models.AuditRepoTeamRemove, com.StrTo(ctx.Query("repoid")).MustInt64()
+		err = ctx.Org.Team.RemoveRepository(event.RepoID)
 	case "addall":
 		err = ctx.Org.Team.AddAllRepositories()
+		event.Action, event.Description = models.AuditRepoTeamAdd, "all repositories"
 	case "removeall":
 		err = ctx.Org.Team.RemoveAllRepositories()
+		event.Action, event.Description = models.AuditRepoTeamRemove, "all repositories"
 	}
 
 	if err != nil {
@@ -167,6 +196,9 @@ func TeamsRepoAction(ctx *context.Context) {
 		ctx.ServerError("TeamsRepoAction", err)
 		return
 	}
+	if event.Action != "" {
+		ctx.Audit(event)
+	}
 
 	if action == "addall" || action == "removeall" {
 		ctx.JSON(200, map[string]interface{}{
@@ -237,6 +269,12 @@ func NewTeamPost(ctx *context.Context, form auth.CreateTeamForm) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditTeamCreate,
+		TargetID:    t.ID,
+		TargetName:  t.Name,
+		Description: t.Authorize.String(),
+	})
 	log.Trace("Team created: %s/%s", ctx.Org.Organization.Name, t.Name)
 	ctx.Redirect(ctx.Org.OrgLink + "/teams/" + t.LowerName)
 }
@@ -339,6 +377,12 @@ func EditTeamPost(ctx *context.Context, form auth.CreateTeamForm) {
 		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditTeamUpdate,
+		TargetID:    t.ID,
+		TargetName:  t.Name,
+		Description: t.Authorize.String(),
+	})
 	ctx.Redirect(ctx.Org.OrgLink + "/teams/" + t.LowerName)
 }
 
@@ -347,6 +391,11 @@ func DeleteTeam(ctx *context.Context) {
 	if err := models.DeleteTeam(ctx.Org.Team); err != nil {
 		ctx.Flash.Error("DeleteTeam: " + err.Error())
 	} else {
+		ctx.Audit(&models.AuditEvent{
+			Action:     models.AuditTeamDelete,
+			TargetID:   ctx.Org.Team.ID,
+			TargetName: ctx.Org.Team.Name,
+		})
 		ctx.Flash.Success(ctx.Tr("org.teams.delete_team_success"))
 	}
 
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
+	mirror_service "code.gitea.io/gitea/services/mirror"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 
@@ -202,11 +203,48 @@ func HookPreReceive(ctx *macaron.Context, opts private.HookOptions) {
 			private.GitQuarantinePath+"="+opts.GitQuarantinePath)
 	}
 
+	var protectedTags []*models.ProtectedTag
 	for i := range opts.OldCommitIDs {
 		oldCommitID := opts.OldCommitIDs[i]
 		newCommitID := opts.NewCommitIDs[i]
 		refFullName := opts.RefFullNames[i]
 
+		if strings.HasPrefix(refFullName, git.TagPrefix) {
+			if protectedTags == nil {
+				protectedTags, err = repo.GetProtectedTags()
+				if err != nil {
+					log.Error("Unable to get protected tags for %-v Error: %v", repo, err)
+					ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+						"err": err.Error(),
+					})
+					return
+				}
+			}
+
+			// Deploy keys are never on the allowlists of protected tags
+			userID := opts.UserID
+			if opts.IsDeployKey {
+				userID = 0
+			}
+			tagName := strings.TrimPrefix(refFullName, git.TagPrefix)
+			isAllowed, err := models.IsUserAllowedToControlTag(protectedTags, tagName, userID)
+			if err != nil {
+				log.Error("Unable to check if user %d is allowed to push tag %s in %-v Error: %v", opts.UserID, tagName, repo, err)
+				ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+					"err": err.Error(),
+				})
+				return
+			}
+			if !isAllowed {
+				log.Warn("Forbidden: Tag %s in %-v is protected", tagName, repo)
+				ctx.JSON(http.StatusForbidden, map[string]interface{}{
+					"err": fmt.Sprintf("Tag %s is protected", tagName),
+				})
+				return
+			}
+			continue
+		}
+
 		branchName := strings.TrimPrefix(refFullName, git.BranchPrefix)
 		if branchName == repo.DefaultBranch && newCommitID == git.EmptySHA {
 			log.Warn("Forbidden: Branch: %s is the default branch in %-v and cannot be deleted", branchName, repo)
@@ -434,6 +472,9 @@ func HookPostReceive(ctx *macaron.Context, opts private.HookOptions) {
 			})
 			return
 		}
+
+		// Keep the push mirrors of the repository up to date
+		mirror_service.StartPushMirrorSync(repo.ID)
 	}
 
 	// Push Options
@@ -6,12 +6,16 @@
 package private
 
 import (
+	"encoding/base64"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
 
 	"gitea.com/macaron/macaron"
+	"golang.org/x/crypto/ssh"
 )
 
 // UpdatePublicKeyInRepo update public key and deploy key updates
@@ -52,6 +56,11 @@ func UpdatePublicKeyInRepo(ctx *macaron.Context) {
 func AuthorizedPublicKeyByContent(ctx *macaron.Context) {
 	content := ctx.Query("content")
 
+	if cert := parseCertificate(content); cert != nil {
+		authorizedCertificate(ctx, cert)
+		return
+	}
+
 	publicKey, err := models.SearchPublicKeyByContent(content)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -61,3 +70,42 @@ func AuthorizedPublicKeyByContent(ctx *macaron.Context) {
 	}
 	ctx.PlainText(http.StatusOK, []byte(publicKey.AuthorizedString()))
 }
+
+// parseCertificate returns the SSH certificate of the content, which is the key type
+// followed by the base64 encoded key, or nil if the content is not a certificate
+func parseCertificate(content string) *ssh.Certificate {
+	fields := strings.Fields(content)
+	if len(fields) < 2 || !strings.Contains(fields[0], "-cert-") {
+		return nil
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil
+	}
+	pubKey, err := ssh.ParsePublicKey(keyBytes)
+	if err != nil {
+		return nil
+	}
+	cert, _ := pubKey.(*ssh.Certificate)
+	return cert
+}
+
+// authorizedCertificate returns the authorized keys line for a certificate signed by a
+// trusted certificate authority, sshd then verifies the certificate itself
+func authorizedCertificate(ctx *macaron.Context, cert *ssh.Certificate) {
+	user, principal, err := models.GetUserByCertificate(cert, nil)
+	if err != nil {
+		if models.IsErrCertificateNotAuthorized(err) {
+			log.Warn("SSH certificate %s (serial %d) is not authorized: %v", cert.KeyId, cert.Serial, err)
+			ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"err": err.Error(),
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": err.Error(),
+		})
+		return
+	}
+	ctx.PlainText(http.StatusOK, []byte(models.AuthorizedCertificateString(user, principal, cert.SignatureKey)))
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/private"
+
+	"gitea.com/macaron/macaron"
+)
+
+// RestoreRepo restores a repository from a directory written by dump-repo
+func RestoreRepo(ctx *macaron.Context, params private.RestoreParams) {
+	owner, err := models.GetUserByName(params.OwnerName)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": fmt.Sprintf("Failed to get owner %s: %v", params.OwnerName, err),
+		})
+		return
+	}
+
+	// the repository is restored on behalf of the site administrator
+	doer, err := models.GetAdminUser()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": fmt.Sprintf("Failed to get an administrator: %v", err),
+		})
+		return
+	}
+
+	repo, err := migrations.RestoreRepository(
+		graceful.GetManager().HammerContext(),
+		doer,
+		params.RepoDir,
+		owner.Name,
+		params.RepoName,
+	)
+	if err != nil {
+		log.Error("Failed to restore repository %s/%s from %s: %v", owner.Name, params.RepoName, params.RepoDir, err)
+		ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"err": fmt.Sprintf("Failed to restore repository %s/%s: %v", owner.Name, params.RepoName, err),
+		})
+		return
+	}
+
+	notification.NotifyMigrateRepository(doer, owner, repo)
+	ctx.PlainText(http.StatusOK, []byte("success"))
+}
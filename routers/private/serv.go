@@ -23,14 +23,16 @@ import (
 // ServNoCommand returns information about the provided keyid
 func ServNoCommand(ctx *macaron.Context) {
 	keyID := ctx.ParamsInt64(":keyid")
-	if keyID <= 0 {
+	userID := ctx.QueryInt64("user")
+	if keyID <= 0 && userID <= 0 {
 		ctx.JSON(http.StatusBadRequest, map[string]interface{}{
 			"err": fmt.Sprintf("Bad key id: %d", keyID),
 		})
+		return
 	}
 	results := private.KeyAndOwner{}
 
-	key, err := models.GetPublicKeyByID(keyID)
+	key, err := servKey(keyID, userID)
 	if err != nil {
 		if models.IsErrKeyNotExist(err) {
 			ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
@@ -66,6 +68,19 @@ func ServNoCommand(ctx *macaron.Context) {
 	ctx.JSON(http.StatusOK, &results)
 }
 
+// servKey returns the public key represented by the keyID, users authenticated
+// by an SSH certificate have no key and are represented by a key without ID
+func servKey(keyID, userID int64) (*models.PublicKey, error) {
+	if keyID == 0 && userID > 0 {
+		return &models.PublicKey{
+			OwnerID: userID,
+			Name:    "SSH certificate",
+			Type:    models.KeyTypeUser,
+		}, nil
+	}
+	return models.GetPublicKeyByID(keyID)
+}
+
 // ServCommand returns information about the provided keyid
 func ServCommand(ctx *macaron.Context) {
 	keyID := ctx.ParamsInt64(":keyid")
@@ -151,7 +166,7 @@ func ServCommand(ctx *macaron.Context) {
 	}
 
 	// Get the Public Key represented by the keyID
-	key, err := models.GetPublicKeyByID(keyID)
+	key, err := servKey(keyID, ctx.QueryInt64("user"))
 	if err != nil {
 		if models.IsErrKeyNotExist(err) {
 			ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
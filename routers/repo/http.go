@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/ratelimit"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -206,10 +207,22 @@ func HTTP(ctx *context.Context) {
 					return
 				}
 
+				scopeLevel := models.AccessTokenScopeLevelWrite
+				if isPull {
+					scopeLevel = models.AccessTokenScopeLevelRead
+				}
+				// Tokens restricted to repositories can't create new ones by pushing
+				if !token.HasScope(models.AccessTokenScopeCategoryRepository, scopeLevel) ||
+					(repoExist && !token.CanAccessRepo(repo.ID)) || (!repoExist && len(token.RepoIDs) > 0) {
+					ctx.HandleText(http.StatusForbidden, "Access token does not grant access to this repository")
+					return
+				}
+
 				token.UpdatedUnix = timeutil.TimeStampNow()
 				if err = models.UpdateAccessToken(token); err != nil {
 					ctx.ServerError("UpdateAccessToken", err)
 				}
+				ctx.Data["ApiToken"] = token
 			} else if !models.IsErrAccessTokenNotExist(err) && !models.IsErrAccessTokenEmpty(err) {
 				log.Error("GetAccessTokenBySha: %v", err)
 			}
@@ -282,6 +295,11 @@ func HTTP(ctx *context.Context) {
 		}
 	}
 
+	if ctx.IsRateLimited(ratelimit.Git, authUser) {
+		ctx.PlainText(http.StatusTooManyRequests, []byte("Rate limit exceeded, please try again later"))
+		return
+	}
+
 	if !repoExist {
 		if !receivePack {
 			ctx.HandleText(http.StatusNotFound, "Repository not found")
@@ -442,6 +442,12 @@ func RetrieveRepoReviewers(ctx *context.Context, repo *models.Repository, issueP
 		ctx.ServerError("GetReviewers", err)
 		return
 	}
+
+	ctx.Data["TeamReviewers"], err = repo.GetReviewerTeams()
+	if err != nil {
+		ctx.ServerError("GetReviewerTeams", err)
+		return
+	}
 }
 
 // RetrieveRepoMetas find all the meta information of a repository
@@ -771,6 +777,12 @@ func NewIssuePost(ctx *context.Context, form auth.CreateIssueForm) {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(400, "UserDoesNotHaveAccessToRepo", err.Error())
 			return
+		} else if models.IsErrBlockedByUser(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.blocked_by_owner"), tplIssueNew, form)
+			return
+		} else if models.IsErrProbationLimit(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.issues.probation_limit", err.(models.ErrProbationLimit).Limit), tplIssueNew, form)
+			return
 		}
 		ctx.ServerError("NewIssue", err)
 		return
@@ -836,6 +848,9 @@ func ViewIssue(ctx *context.Context) {
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
 		if models.IsErrIssueNotExist(err) {
+			if redirectTransferredIssue(ctx, ctx.ParamsInt64(":index")) {
+				return
+			}
 			ctx.NotFound("GetIssueByIndex", err)
 		} else {
 			ctx.ServerError("GetIssueByIndex", err)
@@ -1129,8 +1144,8 @@ func ViewIssue(ctx *context.Context) {
 			}
 
 		} else if comment.Type == models.CommentTypeAssignees || comment.Type == models.CommentTypeReviewRequest {
-			if err = comment.LoadAssigneeUser(); err != nil {
-				ctx.ServerError("LoadAssigneeUser", err)
+			if err = comment.LoadAssigneeUserAndTeam(); err != nil {
+				ctx.ServerError("LoadAssigneeUserAndTeam", err)
 				return
 			}
 		} else if comment.Type == models.CommentTypeRemoveDependency || comment.Type == models.CommentTypeAddDependency {
@@ -1141,8 +1156,8 @@ func ViewIssue(ctx *context.Context) {
 				}
 			}
 		} else if comment.Type == models.CommentTypeCode || comment.Type == models.CommentTypeReview {
-			comment.RenderedContent = string(markdown.Render([]byte(comment.Content), ctx.Repo.RepoLink,
-				ctx.Repo.Repository.ComposeMetas()))
+			comment.RenderedContent = string(markdown.RenderCodeComment([]byte(comment.Content), ctx.Repo.RepoLink,
+				ctx.Repo.Repository.ComposeMetas(), comment.CommentedLines()))
 			if err = comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
 				ctx.ServerError("LoadReview", err)
 				return
@@ -1173,6 +1188,18 @@ func ViewIssue(ctx *context.Context) {
 				ctx.ServerError("LoadPushCommits", err)
 				return
 			}
+		} else if comment.Type == models.CommentTypeIssueTransfer || comment.Type == models.CommentTypeIssueTransferOut {
+			// Do not reveal the names of repositories the user can not read
+			for _, ref := range []*string{&comment.OldRef, &comment.NewRef} {
+				canRead, err := models.CanReadIssueRef(*ref, ctx.User)
+				if err != nil {
+					ctx.ServerError("CanReadIssueRef", err)
+					return
+				}
+				if !canRead {
+					*ref = ctx.Tr("repo.issues.transfer.private_repo")
+				}
+			}
 		}
 	}
 
@@ -1254,6 +1281,11 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["IsBlockedByApprovals"] = !pull.ProtectedBranch.HasEnoughApprovals(pull)
 			ctx.Data["IsBlockedByRejection"] = pull.ProtectedBranch.MergeBlockedByRejectedReview(pull)
 			ctx.Data["IsBlockedByOutdatedBranch"] = pull.ProtectedBranch.MergeBlockedByOutdatedBranch(pull)
+			if !issue.IsClosed {
+				if ctx.Data["IsBlockedByCodeOwners"], err = pull_service.IsBlockedByCodeOwners(pull); err != nil {
+					log.Error("IsBlockedByCodeOwners[%d]: %v", pull.ID, err)
+				}
+			}
 			ctx.Data["GrantedApprovals"] = cnt
 			ctx.Data["RequireSigned"] = pull.ProtectedBranch.RequireSignedCommits
 		}
@@ -1283,6 +1315,27 @@ func ViewIssue(ctx *context.Context) {
 			ctx.ServerError("GetReviewersByIssueID", err)
 			return
 		}
+
+		ctx.Data["PullReviewerTeams"], err = models.GetReviewerTeamsByIssueID(issue.ID)
+		if err != nil {
+			ctx.ServerError("GetReviewerTeamsByIssueID", err)
+			return
+		}
+
+		autoMerge, err := models.GetScheduledAutoMergeByPullID(pull.ID)
+		if err == nil {
+			if err = autoMerge.LoadDoer(); err != nil {
+				if !models.IsErrUserNotExist(err) {
+					ctx.ServerError("LoadDoer", err)
+					return
+				}
+				autoMerge.Doer = models.NewGhostUser()
+			}
+			ctx.Data["PullAutoMerge"] = autoMerge
+		} else if !models.IsErrPullAutoMergeNotExist(err) {
+			ctx.ServerError("GetScheduledAutoMergeByPullID", err)
+			return
+		}
 	}
 
 	// Get Dependencies
@@ -1307,6 +1360,7 @@ func ViewIssue(ctx *context.Context) {
 	ctx.Data["HasProjectsWritePermission"] = ctx.Repo.CanWrite(models.UnitTypeProjects)
 	ctx.Data["IsRepoAdmin"] = ctx.IsSigned && (ctx.Repo.IsAdmin() || ctx.User.IsAdmin)
 	ctx.Data["LockReasons"] = setting.Repository.Issue.LockReasons
+	ctx.Data["CanTransferIssue"] = !issue.IsPull && ctx.Repo.CanWrite(models.UnitTypeIssues)
 	ctx.Data["RefEndName"] = git.RefEndName(issue.Ref)
 	ctx.HTML(200, tplIssueView)
 }
@@ -1524,72 +1578,6 @@ func UpdateIssueAssignee(ctx *context.Context) {
 	})
 }
 
-func isLegalReviewRequest(reviewer, doer *models.User, isAdd bool, issue *models.Issue) error {
-	if reviewer.IsOrganization() {
-		return fmt.Errorf("Organization can't be added as reviewer [user_id: %d, repo_id: %d]", reviewer.ID, issue.PullRequest.BaseRepo.ID)
-	}
-	if doer.IsOrganization() {
-		return fmt.Errorf("Organization can't be doer to add reviewer [user_id: %d, repo_id: %d]", doer.ID, issue.PullRequest.BaseRepo.ID)
-	}
-
-	permReviewer, err := models.GetUserRepoPermission(issue.Repo, reviewer)
-	if err != nil {
-		return err
-	}
-
-	permDoer, err := models.GetUserRepoPermission(issue.Repo, doer)
-	if err != nil {
-		return err
-	}
-
-	lastreview, err := models.GetReviewerByIssueIDAndUserID(issue.ID, reviewer.ID)
-	if err != nil {
-		return err
-	}
-
-	var pemResult bool
-	if isAdd {
-		pemResult = permReviewer.CanAccessAny(models.AccessModeRead, models.UnitTypePullRequests)
-		if !pemResult {
-			return fmt.Errorf("Reviewer can't read [user_id: %d, repo_name: %s]", reviewer.ID, issue.Repo.Name)
-		}
-
-		if doer.ID == issue.PosterID && lastreview != nil && lastreview.Type != models.ReviewTypeRequest {
-			return nil
-		}
-
-		pemResult = permDoer.CanAccessAny(models.AccessModeWrite, models.UnitTypePullRequests)
-		if !pemResult {
-			pemResult, err = models.IsOfficialReviewer(issue, doer)
-			if err != nil {
-				return err
-			}
-			if !pemResult {
-				return fmt.Errorf("Doer can't choose reviewer [user_id: %d, repo_name: %s, issue_id: %d]", doer.ID, issue.Repo.Name, issue.ID)
-			}
-		}
-
-		if doer.ID == reviewer.ID {
-			return fmt.Errorf("doer can't be reviewer [user_id: %d, repo_name: %s]", doer.ID, issue.Repo.Name)
-		}
-
-		if reviewer.ID == issue.PosterID {
-			return fmt.Errorf("poster of pr can't be reviewer [user_id: %d, repo_name: %s]", reviewer.ID, issue.Repo.Name)
-		}
-	} else {
-		if lastreview.Type == models.ReviewTypeRequest && lastreview.ReviewerID == doer.ID {
-			return nil
-		}
-
-		pemResult = permDoer.IsAdmin()
-		if !pemResult {
-			return fmt.Errorf("Doer is not admin [user_id: %d, repo_name: %s]", doer.ID, issue.Repo.Name)
-		}
-	}
-
-	return nil
-}
-
 // updatePullReviewRequest change pull's request reviewers
 func updatePullReviewRequest(ctx *context.Context) {
 	issues := getActionIssues(ctx)
@@ -1608,6 +1596,27 @@ func updatePullReviewRequest(ctx *context.Context) {
 
 	for _, issue := range issues {
 		if issue.IsPull {
+			// negative ids refer to teams
+			if reviewID < 0 {
+				team, err := models.GetTeamByID(-reviewID)
+				if err != nil {
+					ctx.ServerError("GetTeamByID", err)
+					return
+				}
+
+				err = issue_service.IsLegalTeamReviewRequest(team, ctx.User, action == "attach", issue)
+				if err != nil {
+					ctx.ServerError("IsLegalTeamReviewRequest", err)
+					return
+				}
+
+				err = issue_service.TeamReviewRequest(issue, ctx.User, team, action == "attach")
+				if err != nil {
+					ctx.ServerError("TeamReviewRequest", err)
+					return
+				}
+				continue
+			}
 
 			reviewer, err := models.GetUserByID(reviewID)
 			if err != nil {
@@ -1615,9 +1624,9 @@ func updatePullReviewRequest(ctx *context.Context) {
 				return
 			}
 
-			err = isLegalReviewRequest(reviewer, ctx.User, action == "attach", issue)
+			err = issue_service.IsLegalReviewRequest(reviewer, ctx.User, action == "attach", issue)
 			if err != nil {
-				ctx.ServerError("isLegalRequestReview", err)
+				ctx.ServerError("IsLegalReviewRequest", err)
 				return
 			}
 
@@ -1803,6 +1812,10 @@ func NewComment(ctx *context.Context, form auth.CreateCommentForm) {
 
 	comment, err := comment_service.CreateIssueComment(ctx.User, ctx.Repo.Repository, issue, form.Content, attachments)
 	if err != nil {
+		if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.blocked_by_user"))
+			return
+		}
 		ctx.ServerError("CreateIssueComment", err)
 		return
 	}
@@ -1857,7 +1870,7 @@ func UpdateCommentContent(ctx *context.Context) {
 	}
 
 	ctx.JSON(200, map[string]interface{}{
-		"content":     string(markdown.Render([]byte(comment.Content), ctx.Query("context"), ctx.Repo.Repository.ComposeMetas())),
+		"content":     string(markdown.RenderCodeComment([]byte(comment.Content), ctx.Query("context"), ctx.Repo.Repository.ComposeMetas(), comment.CommentedLines())),
 		"attachments": attachmentsHTML(ctx, comment.Attachments),
 	})
 }
@@ -1933,6 +1946,9 @@ func ChangeIssueReaction(ctx *context.Context, form auth.ReactionForm) {
 			if models.IsErrForbiddenIssueReaction(err) {
 				ctx.ServerError("ChangeIssueReaction", err)
 				return
+			} else if models.IsErrBlockedByUser(err) {
+				ctx.Error(403)
+				return
 			}
 			log.Info("CreateIssueReaction: %s", err)
 			break
@@ -2032,6 +2048,9 @@ func ChangeCommentReaction(ctx *context.Context, form auth.ReactionForm) {
 			if models.IsErrForbiddenIssueReaction(err) {
 				ctx.ServerError("ChangeIssueReaction", err)
 				return
+			} else if models.IsErrBlockedByUser(err) {
+				ctx.Error(403)
+				return
 			}
 			log.Info("CreateCommentReaction: %s", err)
 			break
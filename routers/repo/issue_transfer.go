@@ -0,0 +1,94 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/context"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// TransferIssue moves an issue to another repository
+func TransferIssue(ctx *context.Context, form auth.TransferIssueForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsPull {
+		ctx.NotFound("TransferIssue", nil)
+		return
+	}
+
+	var newRepo *models.Repository
+	if parts := strings.SplitN(strings.TrimSpace(form.RepoName), "/", 2); len(parts) == 2 {
+		var err error
+		newRepo, err = models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+		if err != nil && !models.IsErrRepoNotExist(err) {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+			return
+		}
+	}
+	if newRepo == nil {
+		ctx.Flash.Error(ctx.Tr("repo.issues.transfer.repo_invalid"))
+		ctx.Redirect(issue.HTMLURL())
+		return
+	}
+
+	if err := issue_service.TransferIssue(ctx.User, issue, newRepo); err != nil {
+		switch {
+		case models.IsErrUserDoesNotHaveAccessToRepo(err), models.IsErrCannotTransferIssue(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.transfer.repo_invalid"))
+		case models.IsErrBlockedByUser(err):
+			ctx.Flash.Error(ctx.Tr("repo.issues.transfer.blocked"))
+		default:
+			ctx.ServerError("TransferIssue", err)
+			return
+		}
+		ctx.Redirect(issue.HTMLURL())
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.issues.transfer.success", newRepo.FullName()))
+	ctx.Redirect(issue.HTMLURL(), http.StatusSeeOther)
+}
+
+// redirectTransferredIssue redirects to the issue which had the given index in the
+// current repository before it was transferred, or responds not found if the user may not
+// read it. It returns false if there is no such issue.
+func redirectTransferredIssue(ctx *context.Context, index int64) bool {
+	issueID, err := models.LookupIssueRedirect(ctx.Repo.Repository.ID, index)
+	if err != nil {
+		if !models.IsErrIssueRedirectNotExist(err) {
+			ctx.ServerError("LookupIssueRedirect", err)
+			return true
+		}
+		return false
+	}
+
+	issue, err := models.GetIssueByID(issueID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", models.IsErrIssueNotExist, err)
+		return true
+	}
+	if err = issue.LoadRepo(); err != nil {
+		ctx.ServerError("LoadRepo", err)
+		return true
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return true
+	}
+	if !perm.CanRead(models.UnitTypeIssues) {
+		ctx.NotFound("redirectTransferredIssue", nil)
+		return true
+	}
+	ctx.Redirect(issue.HTMLURL())
+	return true
+}
@@ -12,11 +12,13 @@ import (
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/migrations"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/modules/util"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 const (
@@ -64,6 +66,8 @@ func handleMigrateError(ctx *context.Context, owner *models.User, err error, nam
 		ctx.RenderWithErr(ctx.Tr("form.2fa_auth_required"), tpl, form)
 	case models.IsErrReachLimitOfRepo(err):
 		ctx.RenderWithErr(ctx.Tr("repo.form.reach_limit_of_creation", owner.MaxCreationLimit()), tpl, form)
+	case models.IsErrProbationLimit(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.probation_limit", err.(models.ErrProbationLimit).Limit), tpl, form)
 	case models.IsErrRepoAlreadyExist(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("form.repo_name_been_taken"), tpl, form)
@@ -171,3 +175,56 @@ func MigratePost(ctx *context.Context, form auth.MigrateRepoForm) {
 
 	handleMigrateError(ctx, ctxUser, err, "MigratePost", tplMigrate, &form)
 }
+
+// getFailedMigrateTask returns the failed migrate task of the repository
+func getFailedMigrateTask(ctx *context.Context) *models.Task {
+	if !ctx.Repo.Repository.IsBeingMigrated() {
+		ctx.NotFound("IsBeingMigrated", nil)
+		return nil
+	}
+	t, err := models.GetMigratingTask(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetMigratingTask", err)
+		return nil
+	}
+	if t.Status != structs.TaskStatusFailed {
+		ctx.NotFound("TaskStatusFailed", nil)
+		return nil
+	}
+	return t
+}
+
+// MigrateRetryPost resumes a failed migration from its last checkpoint
+func MigrateRetryPost(ctx *context.Context) {
+	t := getFailedMigrateTask(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := task.RetryMigrateTask(t); err != nil {
+		ctx.ServerError("RetryMigrateTask", err)
+		return
+	}
+	ctx.Redirect(ctx.Repo.RepoLink)
+}
+
+// MigrateCancelPost deletes the repository of a failed migration
+func MigrateCancelPost(ctx *context.Context) {
+	if !ctx.Repo.IsOwner() {
+		ctx.Error(404)
+		return
+	}
+	getFailedMigrateTask(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := repo_service.DeleteRepository(ctx.User, ctx.Repo.Repository); err != nil {
+		ctx.ServerError("DeleteRepository", err)
+		return
+	}
+	log.Trace("Repository of failed migration deleted: %s/%s", ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
+	ctx.Redirect(ctx.Repo.Owner.DashboardLink())
+}
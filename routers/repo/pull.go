@@ -232,6 +232,8 @@ func ForkPost(ctx *context.Context, form auth.CreateRepoForm) {
 			ctx.RenderWithErr(ctx.Tr("repo.form.name_reserved", err.(models.ErrNameReserved).Name), tplFork, &form)
 		case models.IsErrNamePatternNotAllowed(err):
 			ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(models.ErrNamePatternNotAllowed).Pattern), tplFork, &form)
+		case models.IsErrProbationLimit(err):
+			ctx.RenderWithErr(ctx.Tr("repo.form.probation_limit", err.(models.ErrProbationLimit).Limit), tplFork, &form)
 		default:
 			ctx.ServerError("ForkPost", err)
 		}
@@ -642,6 +644,12 @@ func ViewPullFiles(ctx *context.Context) {
 			ctx.ServerError("CanMarkConversation", err)
 			return
 		}
+		if !issue.IsClosed {
+			if ctx.Data["CanApplySuggestions"], err = pull_service.IsUserAllowedToApplySuggestions(pull, ctx.User); err != nil {
+				ctx.ServerError("IsUserAllowedToApplySuggestions", err)
+				return
+			}
+		}
 	}
 
 	setImageCompareContext(ctx, baseCommit, commit)
@@ -753,6 +761,28 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 		return
 	}
 
+	if form.MergeWhenChecksSucceed {
+		scheduled, err := pull_service.ScheduleAutoMerge(ctx.User, pr, models.MergeStyle(form.Do), getMergeMessage(pr, form))
+		if err != nil {
+			if models.IsErrInvalidMergeStyle(err) {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
+			} else if models.IsErrPullAutoMergeAlreadyScheduled(err) {
+				ctx.Flash.Error(ctx.Tr("repo.pulls.auto_merge_already_scheduled"))
+			} else {
+				ctx.ServerError("ScheduleAutoMerge", err)
+				return
+			}
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+			return
+		}
+		if scheduled {
+			ctx.Flash.Success(ctx.Tr("repo.pulls.auto_merge_newly_scheduled"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+			return
+		}
+		// the pull request is ready, so it is merged right away
+	}
+
 	if !pr.CanAutoMerge() {
 		ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_not_ready"))
 		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
@@ -792,23 +822,7 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 		return
 	}
 
-	message := strings.TrimSpace(form.MergeTitleField)
-	if len(message) == 0 {
-		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleRebaseMerge {
-			message = pr.GetDefaultMergeMessage()
-		}
-		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
-			message = pr.GetDefaultSquashMessage()
-		}
-	}
-
-	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
-	if len(form.MergeMessageField) > 0 {
-		message += "\n\n" + form.MergeMessageField
-	}
+	message := getMergeMessage(pr, form)
 
 	pr.Issue = issue
 	pr.Issue.Repo = ctx.Repo.Repository
@@ -874,6 +888,60 @@ func MergePullRequest(ctx *context.Context, form auth.MergePullRequestForm) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pr.Index))
 }
 
+// getMergeMessage returns the commit message of the merge from the form or the default message of the merge style
+func getMergeMessage(pr *models.PullRequest, form auth.MergePullRequestForm) string {
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
+			message = pr.GetDefaultMergeMessage()
+		}
+		if models.MergeStyle(form.Do) == models.MergeStyleRebaseMerge {
+			message = pr.GetDefaultMergeMessage()
+		}
+		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
+			message = pr.GetDefaultSquashMessage()
+		}
+	}
+
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+	return message
+}
+
+// CancelAutoMergePullRequest cancels the scheduled automatic merge of a pull request
+func CancelAutoMergePullRequest(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	pr := issue.PullRequest
+
+	allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToMerge", err)
+		return
+	}
+	if !allowedMerge {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.update_not_allowed"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+		return
+	}
+
+	if err := models.UnscheduleAutoMerge(ctx.User, pr); err != nil {
+		if models.IsErrPullAutoMergeNotExist(err) {
+			ctx.NotFound("UnscheduleAutoMerge", err)
+			return
+		}
+		ctx.ServerError("UnscheduleAutoMerge", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.pulls.auto_merge_canceled_schedule"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(issue.Index))
+}
+
 func stopTimerIfAvailable(user *models.User, issue *models.Issue) error {
 
 	if models.StopwatchExists(user.ID, issue.ID) {
@@ -974,6 +1042,14 @@ func CompareAndPullRequestPost(ctx *context.Context, form auth.CreateIssueForm)
 			}
 			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + com.ToStr(pullIssue.Index))
 			return
+		} else if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.blocked_by_owner"))
+			ctx.Redirect(ctx.Link)
+			return
+		} else if models.IsErrProbationLimit(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.probation_limit", err.(models.ErrProbationLimit).Limit))
+			ctx.Redirect(ctx.Link)
+			return
 		}
 		ctx.ServerError("NewPullRequest", err)
 		return
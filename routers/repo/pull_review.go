@@ -30,9 +30,10 @@ func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
 		return
 	}
 
-	signedLine := form.Line
+	signedLine, signedStartLine := form.Line, form.StartLine
 	if form.Side == "previous" {
 		signedLine *= -1
+		signedStartLine *= -1
 	}
 
 	comment, err := pull_service.CreateCodeComment(
@@ -40,6 +41,7 @@ func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
 		ctx.Repo.GitRepo,
 		issue,
 		signedLine,
+		signedStartLine,
 		form.Content,
 		form.TreePath,
 		form.IsReview,
@@ -47,6 +49,16 @@ func CreateCodeComment(ctx *context.Context, form auth.CodeCommentForm) {
 		form.LatestCommitID,
 	)
 	if err != nil {
+		if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.blocked_by_user"))
+			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+			return
+		}
+		if models.IsErrInvalidCommentRange(err) {
+			ctx.Flash.Error(ctx.Tr("repo.diff.comment.invalid_range"))
+			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+			return
+		}
 		ctx.ServerError("CreateCodeComment", err)
 		return
 	}
@@ -150,6 +162,9 @@ func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
 		if models.IsContentEmptyErr(err) {
 			ctx.Flash.Error(ctx.Tr("repo.issues.review.content.empty"))
 			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
+		} else if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.blocked_by_user"))
+			ctx.Redirect(fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index))
 		} else {
 			ctx.ServerError("SubmitReview", err)
 		}
@@ -158,3 +173,73 @@ func SubmitReview(ctx *context.Context, form auth.SubmitReviewForm) {
 
 	ctx.Redirect(fmt.Sprintf("%s/pulls/%d#%s", ctx.Repo.RepoLink, issue.Index, comm.HashTag()))
 }
+
+// ApplySuggestions commits the changes suggested in code comments to the head branch of the pull request
+func ApplySuggestions(ctx *context.Context, form auth.ApplySuggestionsForm) {
+	issue := GetActionIssue(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.IsPull {
+		ctx.NotFound("ApplySuggestions", nil)
+		return
+	}
+	filesLink := fmt.Sprintf("%s/pulls/%d/files", ctx.Repo.RepoLink, issue.Index)
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(filesLink)
+		return
+	}
+
+	if issue.IsClosed {
+		ctx.NotFound("ApplySuggestions", nil)
+		return
+	}
+	allowed, err := pull_service.IsUserAllowedToApplySuggestions(issue.PullRequest, ctx.User)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToApplySuggestions", err)
+		return
+	}
+	if !allowed {
+		ctx.Error(403)
+		return
+	}
+
+	comments := make([]*models.Comment, 0, len(form.CommentIDs))
+	for _, id := range form.CommentIDs {
+		comment, err := models.GetCommentByID(id)
+		if err != nil {
+			if models.IsErrCommentNotExist(err) {
+				ctx.NotFound("GetCommentByID", err)
+				return
+			}
+			ctx.ServerError("GetCommentByID", err)
+			return
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := pull_service.ApplySuggestions(issue.PullRequest, ctx.User, comments, form.Message); err != nil {
+		switch {
+		case models.IsErrCommentNotExist(err):
+			ctx.NotFound("ApplySuggestions", err)
+		case models.IsErrSuggestionOutdated(err):
+			ctx.Flash.Error(ctx.Tr("repo.pulls.suggestion_outdated", err.(models.ErrSuggestionOutdated).TreePath))
+			ctx.Redirect(filesLink)
+		case models.IsErrSuggestionsOverlap(err):
+			ctx.Flash.Error(ctx.Tr("repo.pulls.suggestions_overlap", err.(models.ErrSuggestionsOverlap).TreePath))
+			ctx.Redirect(filesLink)
+		case models.IsErrUserCannotCommit(err), models.IsErrFilePathProtected(err):
+			ctx.Flash.Error(ctx.Tr("repo.pulls.suggestion_protected_branch", issue.PullRequest.HeadBranch))
+			ctx.Redirect(filesLink)
+		default:
+			ctx.ServerError("ApplySuggestions", err)
+		}
+		return
+	}
+
+	log.Trace("Suggestions applied: %-v #%d[%d] Comments%v", ctx.Repo.Repository, issue.Index, issue.ID, form.CommentIDs)
+	ctx.Flash.Success(ctx.Tr("repo.pulls.suggestions_applied", len(comments)))
+	ctx.Redirect(filesLink)
+}
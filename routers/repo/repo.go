@@ -157,6 +157,8 @@ func handleCreateError(ctx *context.Context, owner *models.User, err error, name
 	switch {
 	case models.IsErrReachLimitOfRepo(err):
 		ctx.RenderWithErr(ctx.Tr("repo.form.reach_limit_of_creation", owner.MaxCreationLimit()), tpl, form)
+	case models.IsErrProbationLimit(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.probation_limit", err.(models.ErrProbationLimit).Limit), tpl, form)
 	case models.IsErrRepoAlreadyExist(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("form.repo_name_been_taken"), tpl, form)
@@ -274,8 +276,12 @@ func Action(ctx *context.Context) {
 	}
 
 	if err != nil {
-		ctx.ServerError(fmt.Sprintf("Action (%s)", ctx.Params(":action")), err)
-		return
+		if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.blocked_by_owner"))
+		} else {
+			ctx.ServerError(fmt.Sprintf("Action (%s)", ctx.Params(":action")), err)
+			return
+		}
 	}
 
 	ctx.RedirectToFirst(ctx.Query("redirect_to"), ctx.Repo.RepoLink)
@@ -399,8 +405,25 @@ func Status(ctx *context.Context) {
 		return
 	}
 
+	progress, err := task.MigrateProgress()
+	if err != nil {
+		ctx.JSON(500, map[string]interface{}{
+			"err": err,
+		})
+		return
+	}
+
+	var message string
+	if progress != nil {
+		message = ctx.Tr("repo.migrate.progress", ctx.Tr("repo.migrate.stage."+string(progress.Stage)),
+			progress.Milestones, progress.Labels, progress.Releases, progress.Issues,
+			progress.PullRequests, progress.Comments, progress.Reviews)
+	}
+
 	ctx.JSON(200, map[string]interface{}{
-		"status": ctx.Repo.Repository.Status,
-		"err":    task.Errors,
+		"status":   ctx.Repo.Repository.Status,
+		"err":      task.Errors,
+		"progress": progress,
+		"message":  message,
 	})
 }
@@ -42,6 +42,7 @@ const (
 	tplGithookEdit     base.TplName = "repo/settings/githook_edit"
 	tplDeployKeys      base.TplName = "repo/settings/deploy_keys"
 	tplProtectedBranch base.TplName = "repo/settings/protected_branch"
+	tplTags            base.TplName = "repo/settings/tags"
 )
 
 var validFormAddress *regexp.Regexp
@@ -56,6 +57,14 @@ func Settings(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 
+	pushMirrors, err := models.GetPushMirrorsByRepoID(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetPushMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PushMirrors"] = pushMirrors
+	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
+
 	ctx.HTML(200, tplSettingsOptions)
 }
 
@@ -66,6 +75,14 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 
 	repo := ctx.Repo.Repository
 
+	pushMirrors, err := models.GetPushMirrorsByRepoID(repo.ID)
+	if err != nil {
+		ctx.ServerError("GetPushMirrorsByRepoID", err)
+		return
+	}
+	ctx.Data["PushMirrors"] = pushMirrors
+	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
+
 	switch ctx.Query("action") {
 	case "update":
 		if ctx.HasError() {
@@ -209,6 +226,62 @@ func SettingsPost(ctx *context.Context, form auth.RepoSettingForm) {
 		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
 		ctx.Redirect(repo.Link() + "/settings")
 
+	case "push-mirror-add":
+		// This section doesn't require repo_name/RepoName to be set in the form, don't show it
+		// as an error on the UI for this action
+		ctx.Data["Err_RepoName"] = nil
+
+		interval, err := time.ParseDuration(form.PushMirrorInterval)
+		if err != nil || (interval != 0 && interval < setting.Mirror.MinInterval) {
+			ctx.Data["Err_PushMirrorInterval"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.mirror_interval_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		address, err := mirror_service.ValidatePushMirrorAddress(form.PushMirrorAddress, form.PushMirrorUsername, form.PushMirrorPassword)
+		if err != nil {
+			ctx.Data["Err_PushMirrorAddress"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.push_mirror_address_invalid"), tplSettingsOptions, &form)
+			return
+		}
+
+		m, err := mirror_service.AddPushMirror(repo, address, interval)
+		if err != nil {
+			ctx.ServerError("AddPushMirror", err)
+			return
+		}
+		mirror_service.AddPushMirrorToQueue(m.ID)
+
+		log.Trace("Push mirror added to repository %s: %s", repo.FullName(), m.RemoteAddress)
+		ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-sync":
+		m, err := models.GetPushMirrorByRepoIDAndID(repo.ID, form.PushMirrorID)
+		if err != nil {
+			if models.IsErrPushMirrorNotExist(err) {
+				ctx.NotFound("", nil)
+				return
+			}
+			ctx.ServerError("GetPushMirrorByRepoIDAndID", err)
+			return
+		}
+
+		mirror_service.AddPushMirrorToQueue(m.ID)
+
+		ctx.Flash.Info(ctx.Tr("repo.settings.mirror_sync_in_progress"))
+		ctx.Redirect(repo.Link() + "/settings")
+
+	case "push-mirror-remove":
+		if err := models.DeletePushMirrorByRepoIDAndID(repo.ID, form.PushMirrorID); err != nil {
+			ctx.ServerError("DeletePushMirrorByRepoIDAndID", err)
+			return
+		}
+
+		log.Trace("Push mirror %d removed from repository %s", form.PushMirrorID, repo.FullName())
+		ctx.Flash.Success(ctx.Tr("repo.settings.push_mirror_remove_success"))
+		ctx.Redirect(repo.Link() + "/settings")
+
 	case "advanced":
 		var units []models.RepoUnit
 		var deleteUnitTypes []models.UnitType
@@ -627,9 +700,19 @@ func CollaborationPost(ctx *context.Context) {
 	}
 
 	if err = ctx.Repo.Repository.AddCollaborator(u); err != nil {
-		ctx.ServerError("AddCollaborator", err)
+		if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.add_collaborator_blocked"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/settings/collaboration")
+		} else {
+			ctx.ServerError("AddCollaborator", err)
+		}
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditCollaboratorAdd,
+		TargetID:   u.ID,
+		TargetName: u.Name,
+	})
 
 	if setting.Service.EnableNotifyMail {
 		mailer.SendCollaboratorMail(u, ctx.User, ctx.Repo.Repository)
@@ -641,11 +724,16 @@ func CollaborationPost(ctx *context.Context) {
 
 // ChangeCollaborationAccessMode response for changing access of a collaboration
 func ChangeCollaborationAccessMode(ctx *context.Context) {
-	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(
-		ctx.QueryInt64("uid"),
-		models.AccessMode(ctx.QueryInt("mode"))); err != nil {
+	mode := models.AccessMode(ctx.QueryInt("mode"))
+	if err := ctx.Repo.Repository.ChangeCollaborationAccessMode(ctx.QueryInt64("uid"), mode); err != nil {
 		log.Error("ChangeCollaborationAccessMode: %v", err)
+		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditCollaboratorAccessChange,
+		TargetID:    ctx.QueryInt64("uid"),
+		Description: mode.String(),
+	})
 }
 
 // DeleteCollaboration delete a collaboration for a repository
@@ -653,6 +741,7 @@ func DeleteCollaboration(ctx *context.Context) {
 	if err := ctx.Repo.Repository.DeleteCollaboration(ctx.QueryInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteCollaboration: " + err.Error())
 	} else {
+		ctx.Audit(&models.AuditEvent{Action: models.AuditCollaboratorRemove, TargetID: ctx.QueryInt64("id")})
 		ctx.Flash.Success(ctx.Tr("repo.settings.remove_collaborator_success"))
 	}
 
@@ -702,6 +791,11 @@ func AddTeamPost(ctx *context.Context) {
 		ctx.ServerError("team.AddRepository", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditRepoTeamAdd,
+		TargetID:   team.ID,
+		TargetName: team.Name,
+	})
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_team_success"))
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/collaboration")
@@ -725,6 +819,11 @@ func DeleteTeam(ctx *context.Context) {
 		ctx.ServerError("team.RemoveRepositorys", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditRepoTeamRemove,
+		TargetID:   team.ID,
+		TargetName: team.Name,
+	})
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.remove_team_success"))
 	ctx.JSON(200, map[string]interface{}{
@@ -879,6 +978,12 @@ func DeployKeysPost(ctx *context.Context, form auth.AddKeyForm) {
 		return
 	}
 
+	ctx.Audit(&models.AuditEvent{
+		Action:      models.AuditDeployKeyAdd,
+		TargetID:    key.ID,
+		TargetName:  key.Name,
+		Description: key.Fingerprint,
+	})
 	log.Trace("Deploy key added: %d", ctx.Repo.Repository.ID)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_key_success", key.Name))
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/keys")
@@ -889,6 +994,7 @@ func DeleteDeployKey(ctx *context.Context) {
 	if err := models.DeleteDeployKey(ctx.User, ctx.QueryInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteDeployKey: " + err.Error())
 	} else {
+		ctx.Audit(&models.AuditEvent{Action: models.AuditDeployKeyDelete, TargetID: ctx.QueryInt64("id")})
 		ctx.Flash.Success(ctx.Tr("repo.settings.deploy_key_deletion_success"))
 	}
 
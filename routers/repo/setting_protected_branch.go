@@ -249,6 +249,7 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 		protectBranch.RequireSignedCommits = f.RequireSignedCommits
 		protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 		protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+		protectBranch.BlockOnCodeOwnerReviews = f.BlockOnCodeOwnerReviews
 
 		err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
 			UserIDs:          whitelistUsers,
@@ -262,6 +263,11 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 			ctx.ServerError("UpdateProtectBranch", err)
 			return
 		}
+		ctx.Audit(&models.AuditEvent{
+			Action:     models.AuditBranchProtectionUpdate,
+			TargetID:   protectBranch.ID,
+			TargetName: protectBranch.BranchName,
+		})
 		ctx.Flash.Success(ctx.Tr("repo.settings.update_protect_branch_success", branch))
 		ctx.Redirect(fmt.Sprintf("%s/settings/branches/%s", ctx.Repo.RepoLink, branch))
 	} else {
@@ -270,6 +276,11 @@ func SettingsProtectedBranchPost(ctx *context.Context, f auth.ProtectBranchForm)
 				ctx.ServerError("DeleteProtectedBranch", err)
 				return
 			}
+			ctx.Audit(&models.AuditEvent{
+				Action:     models.AuditBranchProtectionDelete,
+				TargetID:   protectBranch.ID,
+				TargetName: protectBranch.BranchName,
+			})
 		}
 		ctx.Flash.Success(ctx.Tr("repo.settings.remove_protected_branch_success", branch))
 		ctx.Redirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
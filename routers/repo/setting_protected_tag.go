@@ -0,0 +1,168 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ProtectedTags render the page to protect tags
+func ProtectedTags(ctx *context.Context) {
+	if setTagsContext(ctx) != nil {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplTags)
+}
+
+// NewProtectedTagPost handles creation of a protected tag
+func NewProtectedTagPost(ctx *context.Context, form auth.ProtectTagForm) {
+	if setTagsContext(ctx) != nil {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplTags)
+		return
+	}
+
+	saveProtectedTag(ctx, &models.ProtectedTag{}, form)
+}
+
+// EditProtectedTag render the page to edit a protected tag
+func EditProtectedTag(ctx *context.Context) {
+	if setTagsContext(ctx) != nil {
+		return
+	}
+
+	ctx.Data["PageIsEditProtectedTag"] = true
+
+	pt := selectProtectedTagByContext(ctx)
+	if pt == nil {
+		return
+	}
+
+	ctx.Data["name_pattern"] = pt.NamePattern
+	ctx.Data["allowlist_users"] = strings.Join(base.Int64sToStrings(pt.AllowlistUserIDs), ",")
+	ctx.Data["allowlist_teams"] = strings.Join(base.Int64sToStrings(pt.AllowlistTeamIDs), ",")
+
+	ctx.HTML(http.StatusOK, tplTags)
+}
+
+// EditProtectedTagPost handles update of a protected tag
+func EditProtectedTagPost(ctx *context.Context, form auth.ProtectTagForm) {
+	if setTagsContext(ctx) != nil {
+		return
+	}
+
+	ctx.Data["PageIsEditProtectedTag"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplTags)
+		return
+	}
+
+	pt := selectProtectedTagByContext(ctx)
+	if pt == nil {
+		return
+	}
+
+	saveProtectedTag(ctx, pt, form)
+}
+
+// DeleteProtectedTagPost handles deletion of a protected tag
+func DeleteProtectedTagPost(ctx *context.Context) {
+	if err := ctx.Repo.Repository.DeleteProtectedTag(ctx.QueryInt64("id")); err != nil {
+		ctx.Flash.Error("DeleteProtectedTag: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.tags.protection.deletion_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/settings/tags",
+	})
+}
+
+func saveProtectedTag(ctx *context.Context, pt *models.ProtectedTag, form auth.ProtectTagForm) {
+	pt.NamePattern = form.NamePattern
+	allowlistUsers, _ := base.StringsToInt64s(strings.Split(form.AllowlistUsers, ","))
+	allowlistTeams, _ := base.StringsToInt64s(strings.Split(form.AllowlistTeams, ","))
+
+	if err := models.SaveProtectedTag(ctx.Repo.Repository, pt, allowlistUsers, allowlistTeams); err != nil {
+		if models.IsErrProtectedTagPatternInvalid(err) {
+			ctx.Data["Err_NamePattern"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.settings.tags.protection.pattern_invalid", form.NamePattern), tplTags, &form)
+			return
+		}
+		ctx.ServerError("SaveProtectedTag", err)
+		return
+	}
+
+	log.Trace("Protected tag %q of repository %s/%s saved", pt.NamePattern, ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.tags.protection.update_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/tags")
+}
+
+func setTagsContext(ctx *context.Context) error {
+	ctx.Data["Title"] = ctx.Tr("repo.settings")
+	ctx.Data["PageIsSettingsTags"] = true
+
+	protectedTags, err := ctx.Repo.Repository.GetProtectedTags()
+	if err != nil {
+		ctx.ServerError("GetProtectedTags", err)
+		return err
+	}
+	ctx.Data["ProtectedTags"] = protectedTags
+
+	users, err := ctx.Repo.Repository.GetReaders()
+	if err != nil {
+		ctx.ServerError("Repo.Repository.GetReaders", err)
+		return err
+	}
+	ctx.Data["Users"] = users
+	usersMap := make(map[int64]*models.User, len(users))
+	for _, u := range users {
+		usersMap[u.ID] = u
+	}
+	ctx.Data["UsersMap"] = usersMap
+
+	if ctx.Repo.Owner.IsOrganization() {
+		teams, err := ctx.Repo.Owner.TeamsWithAccessToRepo(ctx.Repo.Repository.ID, models.AccessModeRead)
+		if err != nil {
+			ctx.ServerError("Repo.Owner.TeamsWithAccessToRepo", err)
+			return err
+		}
+		ctx.Data["Teams"] = teams
+		teamsMap := make(map[int64]*models.Team, len(teams))
+		for _, t := range teams {
+			teamsMap[t.ID] = t
+		}
+		ctx.Data["TeamsMap"] = teamsMap
+	}
+
+	return nil
+}
+
+func selectProtectedTagByContext(ctx *context.Context) *models.ProtectedTag {
+	id := ctx.ParamsInt64(":id")
+	pt, err := ctx.Repo.Repository.GetProtectedTagByID(id)
+	if err != nil {
+		if models.IsErrProtectedTagNotExist(err) {
+			ctx.NotFound("GetProtectedTagByID", err)
+		} else {
+			ctx.ServerError("GetProtectedTagByID", err)
+		}
+		return nil
+	}
+	return pt
+}
@@ -20,11 +20,13 @@ import (
 	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/options"
 	"code.gitea.io/gitea/modules/public"
+	"code.gitea.io/gitea/modules/ratelimit"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/templates"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers"
 	"code.gitea.io/gitea/routers/admin"
+	apipackages "code.gitea.io/gitea/routers/api/packages"
 	apiv1 "code.gitea.io/gitea/routers/api/v1"
 	"code.gitea.io/gitea/routers/dev"
 	"code.gitea.io/gitea/routers/events"
@@ -48,8 +50,8 @@ import (
 	"gitea.com/macaron/macaron"
 	"gitea.com/macaron/session"
 	"gitea.com/macaron/toolbox"
+	"github.com/duo-labs/webauthn/webauthn"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/tstranex/u2f"
 )
 
 type routerLoggerOptions struct {
@@ -109,7 +111,7 @@ func RouterHandler(level log.Level) func(ctx *macaron.Context) {
 
 // NewMacaron initializes Macaron instance.
 func NewMacaron() *macaron.Macaron {
-	gob.Register(&u2f.Challenge{})
+	gob.Register(&webauthn.SessionData{})
 	var m *macaron.Macaron
 	if setting.RedirectMacaronLog {
 		loggerAsWriter := log.NewLoggerAsWriter("INFO", log.GetLogger("macaron"))
@@ -301,13 +303,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 	m.Get("/milestones", reqSignIn, reqMilestonesDashboardPageEnabled, user.Milestones)
 
 	// ***** START: User *****
+	loginRateLimit := context.RateLimit(ratelimit.Login)
 	m.Group("/user", func() {
 		m.Get("/login", user.SignIn)
-		m.Post("/login", bindIgnErr(auth.SignInForm{}), user.SignInPost)
+		m.Post("/login", loginRateLimit, bindIgnErr(auth.SignInForm{}), user.SignInPost)
 		m.Group("", func() {
 			m.Combo("/login/openid").
 				Get(user.SignInOpenID).
-				Post(bindIgnErr(auth.SignInOpenIDForm{}), user.SignInOpenIDPost)
+				Post(loginRateLimit, bindIgnErr(auth.SignInOpenIDForm{}), user.SignInOpenIDPost)
 		}, openIDSignInEnabled)
 		m.Group("/openid", func() {
 			m.Combo("/connect").
@@ -325,24 +328,28 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get("/:provider", user.SignInOAuth)
 			m.Get("/:provider/callback", user.SignInOAuthCallback)
 		})
+		m.Group("/saml/:provider", func() {
+			m.Get("", user.SignInSAML)
+			m.Post("/acs", user.SAMLAssertionConsumerService)
+		})
 		m.Get("/link_account", user.LinkAccount)
-		m.Post("/link_account_signin", bindIgnErr(auth.SignInForm{}), user.LinkAccountPostSignIn)
+		m.Post("/link_account_signin", loginRateLimit, bindIgnErr(auth.SignInForm{}), user.LinkAccountPostSignIn)
 		m.Post("/link_account_signup", bindIgnErr(auth.RegisterForm{}), user.LinkAccountPostRegister)
 		m.Group("/two_factor", func() {
 			m.Get("", user.TwoFactor)
-			m.Post("", bindIgnErr(auth.TwoFactorAuthForm{}), user.TwoFactorPost)
+			m.Post("", loginRateLimit, bindIgnErr(auth.TwoFactorAuthForm{}), user.TwoFactorPost)
 			m.Get("/scratch", user.TwoFactorScratch)
-			m.Post("/scratch", bindIgnErr(auth.TwoFactorScratchAuthForm{}), user.TwoFactorScratchPost)
+			m.Post("/scratch", loginRateLimit, bindIgnErr(auth.TwoFactorScratchAuthForm{}), user.TwoFactorScratchPost)
 		})
-		m.Group("/u2f", func() {
-			m.Get("", user.U2F)
-			m.Get("/challenge", user.U2FChallenge)
-			m.Post("/sign", bindIgnErr(u2f.SignResponse{}), user.U2FSign)
-
+		m.Group("/webauthn", func() {
+			m.Get("", user.WebAuthn)
+			m.Combo("/assertion").Get(user.WebAuthnLoginAssertion).Post(loginRateLimit, user.WebAuthnLoginAssertionPost)
+			m.Combo("/passwordless/assertion").Get(user.WebAuthnPasswordlessLoginAssertion).Post(loginRateLimit, user.WebAuthnPasswordlessLoginAssertionPost)
 		})
 	}, reqSignOut)
 
 	m.Any("/user/events", reqSignIn, events.Events)
+	m.Get("/user/saml/:provider/metadata", user.SAMLMetadata)
 
 	m.Group("/login/oauth", func() {
 		m.Get("/authorize", bindIgnErr(auth.AuthorizationForm{}), user.AuthorizeOAuth)
@@ -351,6 +358,11 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Post("/authorize", bindIgnErr(auth.AuthorizationForm{}), user.AuthorizeOAuth)
 	}, ignSignInAndCsrf, reqSignIn)
 	m.Post("/login/oauth/access_token", bindIgnErr(auth.AccessTokenForm{}), ignSignInAndCsrf, user.AccessTokenOAuth)
+	if setting.OAuth2.Enable {
+		m.Combo("/login/oauth/userinfo", ignSignInAndCsrf).Get(user.InfoOAuth).Post(user.InfoOAuth)
+		m.Get("/login/oauth/keys", user.OIDCKeys)
+		m.Get("/.well-known/openid-configuration", user.OIDCWellKnown)
+	}
 
 	m.Group("/user/settings", func() {
 		m.Get("", userSetting.Profile)
@@ -374,10 +386,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Get("/enroll", userSetting.EnrollTwoFactor)
 				m.Post("/enroll", bindIgnErr(auth.TwoFactorAuthForm{}), userSetting.EnrollTwoFactorPost)
 			})
-			m.Group("/u2f", func() {
-				m.Post("/request_register", bindIgnErr(auth.U2FRegistrationForm{}), userSetting.U2FRegister)
-				m.Post("/register", bindIgnErr(u2f.RegisterResponse{}), userSetting.U2FRegisterPost)
-				m.Post("/delete", bindIgnErr(auth.U2FDeleteForm{}), userSetting.U2FDelete)
+			m.Group("/webauthn", func() {
+				m.Post("/request_register", bindIgnErr(auth.WebAuthnRegistrationForm{}), userSetting.WebAuthnRegister)
+				m.Post("/register", userSetting.WebAuthnRegisterPost)
+				m.Post("/delete", bindIgnErr(auth.WebAuthnDeleteForm{}), userSetting.WebAuthnDelete)
 			})
 			m.Group("/openid", func() {
 				m.Post("", bindIgnErr(auth.AddOpenIDForm{}), userSetting.OpenIDPost)
@@ -402,6 +414,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Post("/keys/delete", userSetting.DeleteKey)
 		m.Get("/organization", userSetting.Organization)
 		m.Get("/repos", userSetting.Repos)
+		m.Get("/audit_log", userSetting.AuditLog)
+		m.Combo("/blocked_users").Get(userSetting.BlockedUsers).
+			Post(bindIgnErr(auth.BlockUserForm{}), userSetting.BlockedUsersPost)
+		m.Post("/blocked_users/unblock", userSetting.UnblockUser)
 	}, reqSignIn, func(ctx *context.Context) {
 		ctx.Data["PageIsUserSettings"] = true
 		ctx.Data["AllThemes"] = setting.UI.Themes
@@ -446,6 +462,9 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Group("/users", func() {
 			m.Get("", admin.Users)
 			m.Combo("/new").Get(admin.NewUser).Post(bindIgnErr(auth.AdminCreateUserForm{}), admin.NewUserPost)
+			m.Get("/review", admin.ReviewUsers)
+			m.Post("/review/:userid/approve", admin.ApproveUser)
+			m.Post("/review/:userid/reject", admin.RejectUser)
 			m.Combo("/:userid").Get(admin.EditUser).Post(bindIgnErr(auth.AdminEditUserForm{}), admin.EditUserPost)
 			m.Post("/:userid/delete", admin.DeleteUser)
 		})
@@ -502,6 +521,8 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Post("/delete", admin.DeleteNotices)
 			m.Post("/empty", admin.EmptyNotices)
 		})
+
+		m.Get("/audit_log", admin.AuditLog)
 	}, adminReq)
 	// ***** END: Admin *****
 
@@ -519,6 +540,44 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Post("/action/:action", user.Action)
 	}, reqSignIn)
 
+	if setting.Packages.Enabled {
+		m.Group("/:username/-/packages", func() {
+			m.Get("", user.Packages)
+			m.Group("/:type/:name", func() {
+				m.Get("", user.PackageLatestVersion)
+				m.Get("/:version", user.PackageVersion)
+				m.Post("/:version/delete", reqSignIn, user.DeletePackageVersion)
+			})
+		}, ignSignIn, context.PackageAssignment())
+	}
+
+	m.Group("/:username/-/projects", func() {
+		m.Get("", user.Projects)
+		m.Get("/:id", user.ViewProject)
+		m.Group("", func() {
+			m.Get("/new", user.NewProject)
+			m.Post("/new", bindIgnErr(auth.CreateProjectForm{}), user.NewProjectPost)
+			m.Group("/:id", func() {
+				m.Post("", bindIgnErr(auth.EditProjectBoardTitleForm{}), user.AddBoardToProjectPost)
+				m.Post("/delete", user.DeleteProject)
+
+				m.Get("/edit", user.EditProject)
+				m.Post("/edit", bindIgnErr(auth.CreateProjectForm{}), user.EditProjectPost)
+				m.Post("/^:action(open|close)$", user.ChangeProjectStatus)
+
+				m.Post("/cards", bindIgnErr(auth.AddProjectCardForm{}), user.AddIssueToProjectPost)
+				m.Post("/cards/:index/delete", user.RemoveIssueFromProject)
+
+				m.Group("/:boardID", func() {
+					m.Put("", bindIgnErr(auth.EditProjectBoardTitleForm{}), user.EditProjectBoardTitle)
+					m.Delete("", user.DeleteProjectBoard)
+
+					m.Post("/:index", user.MoveIssueAcrossBoards)
+				})
+			})
+		}, reqSignIn, context.RequireProjectOwnerWriter())
+	}, ignSignIn, repo.MustEnableProjects, context.ProjectOwnerAssignment())
+
 	if macaron.Env == macaron.DEV {
 		m.Get("/template/*", dev.TemplatePreview)
 	}
@@ -607,6 +666,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 					m.Post("/initialize", bindIgnErr(auth.InitializeLabelsForm{}), org.InitializeLabels)
 				})
 
+				m.Get("/audit_log", org.AuditLog)
+				m.Combo("/blocked_users").Get(org.BlockedUsers).
+					Post(bindIgnErr(auth.BlockUserForm{}), org.BlockedUsersPost)
+				m.Post("/blocked_users/unblock", org.UnblockUser)
 				m.Route("/delete", "GET,POST", org.SettingsDelete)
 			})
 		}, context.OrgAssignment(true, true))
@@ -650,6 +713,14 @@ func RegisterRoutes(m *macaron.Macaron) {
 					Post(bindIgnErr(auth.ProtectBranchForm{}), context.RepoMustNotBeArchived(), repo.SettingsProtectedBranchPost)
 			}, repo.MustBeNotEmpty)
 
+			m.Group("/tags", func() {
+				m.Combo("").Get(repo.ProtectedTags).
+					Post(bindIgnErr(auth.ProtectTagForm{}), context.RepoMustNotBeArchived(), repo.NewProtectedTagPost)
+				m.Post("/delete", context.RepoMustNotBeArchived(), repo.DeleteProtectedTagPost)
+				m.Combo("/:id").Get(repo.EditProtectedTag).
+					Post(bindIgnErr(auth.ProtectTagForm{}), context.RepoMustNotBeArchived(), repo.EditProtectedTagPost)
+			}, repo.MustBeNotEmpty)
+
 			m.Group("/hooks", func() {
 				m.Get("", repo.Webhooks)
 				m.Post("/delete", repo.DeleteWebhook)
@@ -752,6 +823,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 				m.Post("/reactions/:action", bindIgnErr(auth.ReactionForm{}), repo.ChangeIssueReaction)
 				m.Post("/lock", reqRepoIssueWriter, bindIgnErr(auth.IssueLockForm{}), repo.LockIssue)
 				m.Post("/unlock", reqRepoIssueWriter, repo.UnlockIssue)
+				m.Post("/transfer", reqRepoIssueWriter, bindIgnErr(auth.TransferIssueForm{}), repo.TransferIssue)
 				m.Get("/attachments", repo.GetIssueAttachments)
 			}, context.RepoMustNotBeArchived())
 
@@ -920,6 +992,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 		m.Get("/archive/*", repo.MustBeNotEmpty, reqRepoCodeReader, repo.Download)
 
 		m.Get("/status", reqRepoCodeReader, repo.Status)
+		m.Group("/migrate", func() {
+			m.Post("/retry", repo.MigrateRetryPost)
+			m.Post("/cancel", repo.MigrateCancelPost)
+		}, reqRepoAdmin)
 
 		m.Group("/branches", func() {
 			m.Get("", repo.Branches)
@@ -934,8 +1010,10 @@ func RegisterRoutes(m *macaron.Macaron) {
 			m.Get(".patch", repo.DownloadPullPatch)
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Post("/merge", context.RepoMustNotBeArchived(), bindIgnErr(auth.MergePullRequestForm{}), repo.MergePullRequest)
+			m.Post("/cancel_auto_merge", context.RepoMustNotBeArchived(), repo.CancelAutoMergePullRequest)
 			m.Post("/update", repo.UpdatePullRequest)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
+			m.Post("/suggestions/apply", context.RepoMustNotBeArchived(), bindIgnErr(auth.ApplySuggestionsForm{}), repo.ApplySuggestions)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
 				m.Group("/reviews", func() {
@@ -1055,8 +1133,16 @@ func RegisterRoutes(m *macaron.Macaron) {
 	handlers = append(handlers, ignSignIn)
 	m.Group("/api", func() {
 		apiv1.RegisterRoutes(m)
+		if setting.Packages.Enabled {
+			apipackages.RegisterRoutes(m)
+		}
 	}, handlers...)
 
+	if setting.Packages.Enabled {
+		// The container registry uses its own token authentication and must not require CSRF tokens
+		apipackages.RegisterContainerRoutes(m)
+	}
+
 	m.Group("/api/internal", func() {
 		// package name internal is ideal but Golang is not allowed, so we use private as package name.
 		private.RegisterRoutes(m)
@@ -1083,7 +1169,7 @@ func RegisterRoutes(m *macaron.Macaron) {
 	// prometheus metrics endpoint
 	if setting.Metrics.Enabled {
 		c := metrics.NewCollector()
-		prometheus.MustRegister(c)
+		prometheus.MustRegister(c, metrics.RateLimitRequests)
 
 		m.Get("/metrics", routers.Metrics)
 	}
@@ -0,0 +1,218 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/externalaccount"
+
+	"github.com/crewjam/saml"
+	"github.com/markbates/goth"
+)
+
+// samlRequestCookieName is the cookie which binds the response of the identity provider to
+// the browser that sent the authentication request
+const samlRequestCookieName = "saml_request_id"
+
+// samlRequestMaxAge is how long, in seconds, a user may take to sign in at the identity provider
+const samlRequestMaxAge = 10 * 60
+
+func samlServiceProvider(ctx *context.Context) (*models.LoginSource, *saml.ServiceProvider, bool) {
+	loginSource, err := models.GetActiveSAMLLoginSourceByName(ctx.Params(":provider"))
+	if err != nil {
+		ctx.ServerError("GetActiveSAMLLoginSourceByName", err)
+		return nil, nil, false
+	} else if loginSource == nil {
+		ctx.NotFound("GetActiveSAMLLoginSourceByName", nil)
+		return nil, nil, false
+	}
+
+	sp, err := loginSource.SAML().ServiceProvider(loginSource.SAMLServiceProviderURL())
+	if err != nil {
+		ctx.ServerError("ServiceProvider", err)
+		return nil, nil, false
+	}
+	return loginSource, sp, true
+}
+
+// setSAMLRequestCookie stores the ID of an authentication request, the response is posted
+// cross-site by the identity provider so the cookie must not be restricted to same-site requests
+func setSAMLRequestCookie(ctx *context.Context, requestID string, maxAge int) {
+	cookie := &http.Cookie{
+		Name:     samlRequestCookieName,
+		Value:    requestID,
+		Path:     setting.AppSubURL + "/user/saml/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+	}
+	if strings.HasPrefix(setting.AppURL, "https://") {
+		cookie.Secure = true
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(ctx.Resp, cookie)
+}
+
+// SignInSAML sends an authentication request to the identity provider of a SAML login source
+func SignInSAML(ctx *context.Context) {
+	_, sp, ok := samlServiceProvider(ctx)
+	if !ok {
+		return
+	}
+
+	binding := saml.HTTPRedirectBinding
+	location := sp.GetSSOBindingLocation(binding)
+	if len(location) == 0 {
+		binding = saml.HTTPPostBinding
+		location = sp.GetSSOBindingLocation(binding)
+	}
+	if len(location) == 0 {
+		ctx.ServerError("SignInSAML", errors.New("identity provider has no single sign-on service with a supported binding"))
+		return
+	}
+
+	req, err := sp.MakeAuthenticationRequest(location)
+	if err != nil {
+		ctx.ServerError("MakeAuthenticationRequest", err)
+		return
+	}
+	setSAMLRequestCookie(ctx, req.ID, samlRequestMaxAge)
+
+	if binding == saml.HTTPRedirectBinding {
+		ctx.Redirect(req.Redirect("").String())
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(append([]byte("<!DOCTYPE html><html><body>"), append(req.Post(""), []byte("</body></html>")...)...)); err != nil {
+		log.Error("Error writing SAML authentication request: %v", err)
+	}
+}
+
+// SAMLMetadata serves the service provider metadata of a SAML login source
+func SAMLMetadata(ctx *context.Context) {
+	_, sp, ok := samlServiceProvider(ctx)
+	if !ok {
+		return
+	}
+
+	metadata := sp.Metadata()
+	// single logout is not supported
+	for i := range metadata.SPSSODescriptors {
+		metadata.SPSSODescriptors[i].SingleLogoutServices = nil
+	}
+	buf, err := xml.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		ctx.ServerError("MarshalIndent", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "application/samlmetadata+xml")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(buf); err != nil {
+		log.Error("Error writing SAML metadata: %v", err)
+	}
+}
+
+// SAMLAssertionConsumerService handles the response posted by the identity provider of a SAML login source
+func SAMLAssertionConsumerService(ctx *context.Context) {
+	loginSource, sp, ok := samlServiceProvider(ctx)
+	if !ok {
+		return
+	}
+	cfg := loginSource.SAML()
+
+	var requestIDs []string
+	if requestID := ctx.GetCookie(samlRequestCookieName); len(requestID) > 0 {
+		requestIDs = append(requestIDs, requestID)
+	}
+	setSAMLRequestCookie(ctx, "", -1)
+
+	samlUser, err := cfg.ParseResponse(sp, ctx.Req.Request, requestIDs)
+	if err != nil {
+		log.Warn("SAML login via %s from %s failed: %v", loginSource.Name, ctx.RemoteAddr(), err)
+		ctx.Flash.Error(ctx.Tr("auth.saml_login_failed"))
+		ctx.Redirect(setting.AppSubURL + "/user/login")
+		return
+	}
+
+	rawData := make(map[string]interface{}, len(samlUser.Attributes))
+	for name, values := range samlUser.Attributes {
+		rawData[name] = values
+	}
+	gothUser := goth.User{
+		Provider: loginSource.Name,
+		UserID:   samlUser.NameID,
+		Email:    samlUser.Email,
+		Name:     samlUser.FullName,
+		NickName: samlUser.Username,
+		RawData:  rawData,
+	}
+
+	u, err := samlUserSignIn(loginSource, gothUser)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+
+	if u == nil && cfg.EnableAutoRegistration && !setting.Service.DisableRegistration &&
+		len(gothUser.NickName) > 0 && len(gothUser.Email) > 0 {
+		u, err = externalaccount.RegisterAccount(loginSource, gothUser)
+		if err != nil {
+			if !models.IsErrUserAlreadyExist(err) && !models.IsErrEmailAlreadyUsed(err) && !models.IsErrNameReserved(err) &&
+				!models.IsErrNamePatternNotAllowed(err) && !models.IsErrNameCharsNotAllowed(err) {
+				ctx.ServerError("RegisterAccount", err)
+				return
+			}
+			// let the user choose another name or link an existing account
+			log.Info("SAML auto-registration of %s via %s failed: %v", gothUser.NickName, loginSource.Name, err)
+			u = nil
+		} else {
+			log.Trace("Account created: %s", u.Name)
+		}
+	}
+
+	if u != nil && cfg.UseGroupTeamMap() {
+		models.SynchronizeGroupTeams(u, loginSource, samlUser.Groups)
+	}
+
+	handleOAuth2SignIn(u, gothUser, ctx, nil)
+}
+
+// samlUserSignIn returns the user which signs in as gothUser, nil if no account is linked yet
+func samlUserSignIn(loginSource *models.LoginSource, gothUser goth.User) (*models.User, error) {
+	user := &models.User{
+		LoginName:   gothUser.UserID,
+		LoginType:   models.LoginSAML,
+		LoginSource: loginSource.ID,
+	}
+	hasUser, err := models.GetUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if hasUser {
+		return user, nil
+	}
+
+	// search in external linked users
+	externalLoginUser := &models.ExternalLoginUser{
+		ExternalID:    gothUser.UserID,
+		LoginSourceID: loginSource.ID,
+	}
+	hasUser, err = models.GetExternalLogin(externalLoginUser)
+	if err != nil {
+		return nil, err
+	}
+	if hasUser {
+		return models.GetUserByID(externalLoginUser.UserID)
+	}
+	return nil, nil
+}
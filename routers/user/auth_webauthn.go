@@ -0,0 +1,246 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models"
+	wa "code.gitea.io/gitea/modules/auth/webauthn"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/externalaccount"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+	"github.com/markbates/goth"
+)
+
+// WebAuthn shows the WebAuthn login page
+func WebAuthn(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("twofa")
+
+	// Check auto-login.
+	if checkAutoLogin(ctx) {
+		return
+	}
+
+	// Ensure user is in a 2FA session.
+	if ctx.Session.Get("twofaUid") == nil {
+		ctx.ServerError("UserSignIn", errors.New("not in WebAuthn session"))
+		return
+	}
+
+	ctx.HTML(200, tplWebAuthn)
+}
+
+// WebAuthnLoginAssertion submits a WebAuthn challenge to the browser
+func WebAuthnLoginAssertion(ctx *context.Context) {
+	// Ensure user is in a WebAuthn session.
+	idSess, ok := ctx.Session.Get("twofaUid").(int64)
+	if !ok || idSess == 0 {
+		ctx.ServerError("UserSignIn", errors.New("not in WebAuthn session"))
+		return
+	}
+
+	user, err := models.GetUserByID(idSess)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+
+	exists, err := models.HasWebAuthnCredentials(user.ID)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+	if !exists {
+		ctx.ServerError("UserSignIn", errors.New("no device registered"))
+		return
+	}
+
+	assertion, sessionData, err := wa.WebAuthn.BeginLogin((*wa.User)(user), webauthn.WithAssertionExtensions(wa.AppIDExtension()))
+	if err != nil {
+		ctx.ServerError("webauthn.BeginLogin", err)
+		return
+	}
+
+	if err := ctx.Session.Set("webauthnAssertion", sessionData); err != nil {
+		ctx.ServerError("UserSignIn: unable to set webauthnAssertion in session", err)
+		return
+	}
+	if err := ctx.Session.Release(); err != nil {
+		ctx.ServerError("UserSignIn: unable to store session", err)
+		return
+	}
+
+	ctx.JSON(200, assertion)
+}
+
+// WebAuthnLoginAssertionPost validates the signature and logs the user in
+func WebAuthnLoginAssertionPost(ctx *context.Context) {
+	idSess, ok := ctx.Session.Get("twofaUid").(int64)
+	sessionData, okData := ctx.Session.Get("webauthnAssertion").(*webauthn.SessionData)
+	if !ok || !okData || sessionData == nil || idSess == 0 {
+		ctx.ServerError("UserSignIn", errors.New("not in WebAuthn session"))
+		return
+	}
+	defer func() {
+		_ = ctx.Session.Delete("webauthnAssertion")
+	}()
+
+	user, err := models.GetUserByID(idSess)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+
+	if !verifyWebAuthnAssertion(ctx, user, sessionData) {
+		return
+	}
+
+	if ctx.Session.Get("linkAccount") != nil {
+		gothUser := ctx.Session.Get("linkAccountGothUser")
+		if gothUser == nil {
+			ctx.ServerError("UserSignIn", errors.New("not in LinkAccount session"))
+			return
+		}
+
+		if err := externalaccount.LinkAccountToUser(user, gothUser.(goth.User)); err != nil {
+			ctx.ServerError("UserSignIn", err)
+			return
+		}
+	}
+
+	remember, _ := ctx.Session.Get("twofaRemember").(bool)
+	redirect := handleSignInFull(ctx, user, remember, false)
+	if redirect == "" {
+		redirect = setting.AppSubURL + "/"
+	}
+	ctx.PlainText(200, []byte(redirect))
+}
+
+// WebAuthnPasswordlessLoginAssertion submits a challenge for a passwordless login to the browser
+func WebAuthnPasswordlessLoginAssertion(ctx *context.Context) {
+	assertion, sessionData, err := wa.BeginDiscoverableLogin()
+	if err != nil {
+		ctx.ServerError("BeginDiscoverableLogin", err)
+		return
+	}
+
+	if err := ctx.Session.Set("webauthnAssertion", sessionData); err != nil {
+		ctx.ServerError("UserSignIn: unable to set webauthnAssertion in session", err)
+		return
+	}
+	if err := ctx.Session.Release(); err != nil {
+		ctx.ServerError("UserSignIn: unable to store session", err)
+		return
+	}
+
+	ctx.JSON(200, assertion)
+}
+
+// WebAuthnPasswordlessLoginAssertionPost logs in the user owning the credential the authenticator signed with
+func WebAuthnPasswordlessLoginAssertionPost(ctx *context.Context) {
+	sessionData, ok := ctx.Session.Get("webauthnAssertion").(*webauthn.SessionData)
+	if !ok || sessionData == nil || len(sessionData.UserID) > 0 {
+		ctx.ServerError("UserSignIn", errors.New("not in WebAuthn session"))
+		return
+	}
+	defer func() {
+		_ = ctx.Session.Delete("webauthnAssertion")
+	}()
+
+	parsedResponse, err := protocol.ParseCredentialRequestResponse(ctx.Req.Request)
+	if err != nil {
+		log.Info("Failed passwordless authentication attempt from %s: %v", ctx.RemoteAddr(), err)
+		ctx.Error(401)
+		return
+	}
+	if len(parsedResponse.Response.UserHandle) == 0 {
+		log.Info("Failed passwordless authentication attempt from %s: no user handle", ctx.RemoteAddr())
+		ctx.Error(401)
+		return
+	}
+
+	cred, err := models.GetWebAuthnCredentialByCredID(parsedResponse.RawID)
+	if err != nil {
+		if models.IsErrWebAuthnCredentialNotExist(err) {
+			log.Info("Failed passwordless authentication attempt from %s: %v", ctx.RemoteAddr(), err)
+			ctx.Error(401)
+			return
+		}
+		ctx.ServerError("GetWebAuthnCredentialByCredID", err)
+		return
+	}
+	user, err := models.GetUserByID(cred.UserID)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+	if user.ProhibitLogin || !user.IsActive {
+		log.Info("Failed passwordless authentication attempt for %s from %s: login is prohibited", user.Name, ctx.RemoteAddr())
+		ctx.Error(403)
+		return
+	}
+
+	// the authenticator has chosen the credential, so the user is only known now
+	sessionData.UserID = (*wa.User)(user).WebAuthnID()
+	if !validateWebAuthnAssertion(ctx, user, sessionData, parsedResponse) {
+		return
+	}
+
+	redirect := handleSignInFull(ctx, user, false, false)
+	if redirect == "" {
+		redirect = setting.AppSubURL + "/"
+	}
+	ctx.PlainText(200, []byte(redirect))
+}
+
+// verifyWebAuthnAssertion parses the assertion posted by the browser and validates it
+func verifyWebAuthnAssertion(ctx *context.Context, user *models.User, sessionData *webauthn.SessionData) bool {
+	parsedResponse, err := protocol.ParseCredentialRequestResponse(ctx.Req.Request)
+	if err != nil {
+		auditFailedSignIn(ctx, user, user.Name, "invalid security key assertion")
+		log.Info("Failed WebAuthn authentication attempt for %s from %s: %v", user.Name, ctx.RemoteAddr(), err)
+		ctx.Error(401)
+		return false
+	}
+	return validateWebAuthnAssertion(ctx, user, sessionData, parsedResponse)
+}
+
+// validateWebAuthnAssertion validates the assertion against the credentials of the user and
+// stores the new signature counter, an error response is sent if the assertion is not valid
+func validateWebAuthnAssertion(ctx *context.Context, user *models.User, sessionData *webauthn.SessionData, parsedResponse *protocol.ParsedCredentialAssertionData) bool {
+	credential, err := wa.ValidateLogin((*wa.User)(user), *sessionData, parsedResponse)
+	if err != nil {
+		auditFailedSignIn(ctx, user, user.Name, "invalid security key assertion")
+		log.Info("Failed WebAuthn authentication attempt for %s from %s: %v", user.Name, ctx.RemoteAddr(), err)
+		ctx.Error(401)
+		return false
+	}
+
+	cred, err := models.GetWebAuthnCredentialByCredID(credential.ID)
+	if err != nil {
+		ctx.ServerError("GetWebAuthnCredentialByCredID", err)
+		return false
+	}
+	cred.SignCount = credential.Authenticator.SignCount
+	cred.CloneWarning = credential.Authenticator.CloneWarning
+	if err := cred.UpdateSignCount(); err != nil {
+		ctx.ServerError("UpdateSignCount", err)
+		return false
+	}
+
+	// a signature counter which did not increase indicates that the authenticator may have been cloned
+	if cred.CloneWarning {
+		auditFailedSignIn(ctx, user, user.Name, "security key may have been cloned")
+		log.Warn("Signature counter of WebAuthn credential %d of %s did not increase, the authenticator may have been cloned", cred.ID, user.Name)
+		ctx.Error(401)
+		return false
+	}
+	return true
+}
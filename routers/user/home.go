@@ -97,6 +97,21 @@ func retrieveFeeds(ctx *context.Context, options models.GetFeedsOptions) {
 			userCache[repoOwner.ID] = repoOwner
 		}
 		act.Repo.Owner = repoOwner
+
+		// Do not reveal the names of repositories the user can not read
+		if act.OpType == models.ActionTransferIssue {
+			refs := act.GetIssueInfos()
+			for i, ref := range refs {
+				canRead, err := models.CanReadIssueRef(ref, ctx.User)
+				if err != nil {
+					ctx.ServerError("CanReadIssueRef", err)
+					return
+				} else if !canRead {
+					refs[i] = ""
+				}
+			}
+			act.Content = strings.Join(refs, "|")
+		}
 	}
 	ctx.Data["Feeds"] = actions
 }
@@ -13,6 +13,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/auth/oauth2"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
@@ -28,6 +29,9 @@ const (
 	tplGrantError  base.TplName = "user/auth/grant_error"
 )
 
+// oidcScopes are the OpenID Connect scopes which release claims about the user
+var oidcScopes = []string{"openid", "profile", "email", "groups"}
+
 // TODO move error and responses to SDK or models
 
 // AuthorizeErrorCode represents an error code specified in RFC 6749
@@ -107,9 +111,10 @@ type AccessTokenResponse struct {
 	TokenType    TokenType `json:"token_type"`
 	ExpiresIn    int64     `json:"expires_in"`
 	RefreshToken string    `json:"refresh_token"`
+	IDToken      string    `json:"id_token,omitempty"`
 }
 
-func newAccessTokenResponse(grant *models.OAuth2Grant) (*AccessTokenResponse, *AccessTokenError) {
+func newAccessTokenResponse(app *models.OAuth2Application, grant *models.OAuth2Grant, nonce string) (*AccessTokenResponse, *AccessTokenError) {
 	if setting.OAuth2.InvalidateRefreshTokens {
 		if err := grant.IncreaseCounter(); err != nil {
 			return nil, &AccessTokenError{
@@ -153,14 +158,138 @@ func newAccessTokenResponse(grant *models.OAuth2Grant) (*AccessTokenResponse, *A
 		}
 	}
 
+	// generate OpenID Connect id_token
+	signedIDToken := ""
+	if grant.ScopeContains("openid") {
+		user, err := models.GetUserByID(grant.UserID)
+		if err != nil {
+			log.Error("Error loading user %d of grant %d: %v", grant.UserID, grant.ID, err)
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot find user",
+			}
+		}
+		claims, err := grant.GetOIDCUserClaims(user)
+		if err != nil {
+			log.Error("Error getting OpenID Connect claims of user %d: %v", user.ID, err)
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot get user claims",
+			}
+		}
+		idToken := &models.OIDCToken{
+			StandardClaims: jwt.StandardClaims{
+				ExpiresAt: expirationDate.AsTime().Unix(),
+				Issuer:    setting.AppURL,
+				Audience:  app.ClientID,
+				Subject:   fmt.Sprint(user.ID),
+			},
+			Nonce:          nonce,
+			OIDCUserClaims: claims,
+		}
+		signedIDToken, err = idToken.SignToken(oauth2.DefaultSigningKey)
+		if err != nil {
+			return nil, &AccessTokenError{
+				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+				ErrorDescription: "cannot sign token",
+			}
+		}
+	}
+
 	return &AccessTokenResponse{
 		AccessToken:  signedAccessToken,
 		TokenType:    TokenTypeBearer,
 		ExpiresIn:    setting.OAuth2.AccessTokenExpirationTime,
 		RefreshToken: signedRefreshToken,
+		IDToken:      signedIDToken,
 	}, nil
 }
 
+// userInfoResponse represents a successful response of the OpenID Connect userinfo endpoint
+type userInfoResponse struct {
+	Subject string `json:"sub"`
+	*models.OIDCUserClaims
+}
+
+// InfoOAuth returns the claims about the user who authorized the bearer access token (OpenID Connect userinfo endpoint)
+func InfoOAuth(ctx *context.Context) {
+	authContent := strings.SplitN(ctx.Req.Header.Get("Authorization"), " ", 2)
+	if len(authContent) != 2 || !strings.EqualFold(authContent[0], "Bearer") {
+		handleBearerTokenError(ctx, 401, "invalid_request", "bearer access token required")
+		return
+	}
+	token, err := models.ParseOAuth2Token(authContent[1])
+	if err != nil || token.Type != models.TypeAccessToken {
+		handleBearerTokenError(ctx, 401, "invalid_token", "invalid access token")
+		return
+	}
+	grant, err := models.GetOAuth2GrantByID(token.GrantID)
+	if err != nil {
+		ctx.ServerError("GetOAuth2GrantByID", err)
+		return
+	} else if grant == nil {
+		handleBearerTokenError(ctx, 401, "invalid_token", "grant does not exist")
+		return
+	}
+	if !grant.ScopeContains("openid") {
+		handleBearerTokenError(ctx, 403, "insufficient_scope", "openid scope required")
+		return
+	}
+	user, err := models.GetUserByID(grant.UserID)
+	if err != nil {
+		ctx.ServerError("GetUserByID", err)
+		return
+	}
+	claims, err := grant.GetOIDCUserClaims(user)
+	if err != nil {
+		ctx.ServerError("GetOIDCUserClaims", err)
+		return
+	}
+	ctx.JSON(200, &userInfoResponse{
+		Subject:        fmt.Sprint(user.ID),
+		OIDCUserClaims: claims,
+	})
+}
+
+// handleBearerTokenError responds with an error specified in RFC 6750
+func handleBearerTokenError(ctx *context.Context, status int, errorCode, description string) {
+	ctx.Resp.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="%s", error_description="%s"`, errorCode, description))
+	ctx.JSON(status, map[string]string{
+		"error":             errorCode,
+		"error_description": description,
+	})
+}
+
+// OIDCWellKnown serves the OpenID Connect discovery document
+func OIDCWellKnown(ctx *context.Context) {
+	ctx.JSON(200, map[string]interface{}{
+		"issuer":                                setting.AppURL,
+		"authorization_endpoint":                setting.AppURL + "login/oauth/authorize",
+		"token_endpoint":                        setting.AppURL + "login/oauth/access_token",
+		"userinfo_endpoint":                     setting.AppURL + "login/oauth/userinfo",
+		"jwks_uri":                              setting.AppURL + "login/oauth/keys",
+		"scopes_supported":                      oidcScopes,
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{oauth2.DefaultSigningKey.SigningMethod().Alg()},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{"plain", "S256"},
+		"claims_supported": []string{
+			"aud", "exp", "iat", "iss", "sub", "nonce",
+			"name", "preferred_username", "profile", "picture", "website", "locale", "updated_at",
+			"email", "email_verified", "groups",
+		},
+	})
+}
+
+// OIDCKeys serves the JSON Web Key Set with the public key verifying the ID tokens
+func OIDCKeys(ctx *context.Context) {
+	ctx.JSON(200, map[string]interface{}{
+		"keys": []map[string]string{oauth2.DefaultSigningKey.ToJWK()},
+	})
+}
+
 // AuthorizeOAuth manages authorize requests
 func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
 	errs := binding.Errors{}
@@ -212,8 +341,7 @@ func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
 
 	// pkce support
 	switch form.CodeChallengeMethod {
-	case "S256":
-	case "plain":
+	case "S256", "plain":
 		if err := ctx.Session.Set("CodeChallengeMethod", form.CodeChallengeMethod); err != nil {
 			handleAuthorizeError(ctx, AuthorizeError{
 				ErrorCode:        ErrorCodeServerError,
@@ -222,7 +350,7 @@ func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
 			}, form.RedirectURI)
 			return
 		}
-		if err := ctx.Session.Set("CodeChallengeMethod", form.CodeChallenge); err != nil {
+		if err := ctx.Session.Set("CodeChallenge", form.CodeChallenge); err != nil {
 			handleAuthorizeError(ctx, AuthorizeError{
 				ErrorCode:        ErrorCodeServerError,
 				ErrorDescription: "cannot set code challenge",
@@ -252,9 +380,11 @@ func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
 		return
 	}
 
-	// Redirect if user already granted access
-	if grant != nil {
-		code, err := grant.GenerateNewAuthorizationCode(form.RedirectURI, form.CodeChallenge, form.CodeChallengeMethod)
+	scope := strings.Join(strings.Fields(form.Scope), " ")
+
+	// Redirect if user already granted access to the requested scope
+	if grant != nil && grantContainsScope(grant, scope) {
+		code, err := grant.GenerateNewAuthorizationCode(form.RedirectURI, form.CodeChallenge, form.CodeChallengeMethod, form.Nonce)
 		if err != nil {
 			handleServerError(ctx, form.State, form.RedirectURI)
 			return
@@ -274,6 +404,13 @@ func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
 	ctx.Data["State"] = form.State
 	ctx.Data["ApplicationUserLink"] = "<a href=\"" + html.EscapeString(setting.AppURL) + html.EscapeString(url.PathEscape(app.User.LowerName)) + "\">@" + html.EscapeString(app.User.Name) + "</a>"
 	ctx.Data["ApplicationRedirectDomainHTML"] = "<strong>" + html.EscapeString(form.RedirectURI) + "</strong>"
+	var scopes []string
+	for _, s := range oidcScopes {
+		if scopeContains(scope, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	ctx.Data["Scopes"] = scopes
 	// TODO document SESSION <=> FORM
 	err = ctx.Session.Set("client_id", app.ClientID)
 	if err != nil {
@@ -293,6 +430,18 @@ func AuthorizeOAuth(ctx *context.Context, form auth.AuthorizationForm) {
 		log.Error(err.Error())
 		return
 	}
+	err = ctx.Session.Set("scope", scope)
+	if err != nil {
+		handleServerError(ctx, form.State, form.RedirectURI)
+		log.Error(err.Error())
+		return
+	}
+	err = ctx.Session.Set("nonce", form.Nonce)
+	if err != nil {
+		handleServerError(ctx, form.State, form.RedirectURI)
+		log.Error(err.Error())
+		return
+	}
 	// Here we're just going to try to release the session early
 	if err := ctx.Session.Release(); err != nil {
 		// we'll tolerate errors here as they *should* get saved elsewhere
@@ -313,7 +462,20 @@ func GrantApplicationOAuth(ctx *context.Context, form auth.GrantApplicationForm)
 		ctx.ServerError("GetOAuth2ApplicationByClientID", err)
 		return
 	}
-	grant, err := app.CreateGrant(ctx.User.ID)
+	scope, _ := ctx.Session.Get("scope").(string)
+	nonce, _ := ctx.Session.Get("nonce").(string)
+
+	grant, err := app.GetGrantByUserID(ctx.User.ID)
+	if err != nil {
+		handleServerError(ctx, form.State, form.RedirectURI)
+		return
+	}
+	if grant == nil {
+		grant, err = app.CreateGrant(ctx.User.ID, scope)
+	} else if !grantContainsScope(grant, scope) {
+		// the user granted access to the additional scope
+		err = grant.SetScope(strings.Join(append(strings.Fields(grant.Scope), strings.Fields(scope)...), " "))
+	}
 	if err != nil {
 		handleAuthorizeError(ctx, AuthorizeError{
 			State:            form.State,
@@ -327,7 +489,7 @@ func GrantApplicationOAuth(ctx *context.Context, form auth.GrantApplicationForm)
 	codeChallenge, _ = ctx.Session.Get("CodeChallenge").(string)
 	codeChallengeMethod, _ = ctx.Session.Get("CodeChallengeMethod").(string)
 
-	code, err := grant.GenerateNewAuthorizationCode(form.RedirectURI, codeChallenge, codeChallengeMethod)
+	code, err := grant.GenerateNewAuthorizationCode(form.RedirectURI, codeChallenge, codeChallengeMethod, nonce)
 	if err != nil {
 		handleServerError(ctx, form.State, form.RedirectURI)
 		return
@@ -382,8 +544,24 @@ func AccessTokenOAuth(ctx *context.Context, form auth.AccessTokenForm) {
 }
 
 func handleRefreshToken(ctx *context.Context, form auth.AccessTokenForm) {
-	token, err := models.ParseOAuth2Token(form.RefreshToken)
+	app, err := models.GetOAuth2ApplicationByClientID(form.ClientID)
 	if err != nil {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidClient,
+			ErrorDescription: fmt.Sprintf("cannot load client with client id: '%s'", form.ClientID),
+		})
+		return
+	}
+	if !app.ValidateClientSecret([]byte(form.ClientSecret)) {
+		handleAccessTokenError(ctx, AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
+			ErrorDescription: "client is not authorized",
+		})
+		return
+	}
+
+	token, err := models.ParseOAuth2Token(form.RefreshToken)
+	if err != nil || token.Type != models.TypeRefreshToken {
 		handleAccessTokenError(ctx, AccessTokenError{
 			ErrorCode:        AccessTokenErrorCodeUnauthorizedClient,
 			ErrorDescription: "client is not authorized",
@@ -392,7 +570,7 @@ func handleRefreshToken(ctx *context.Context, form auth.AccessTokenForm) {
 	}
 	// get grant before increasing counter
 	grant, err := models.GetOAuth2GrantByID(token.GrantID)
-	if err != nil || grant == nil {
+	if err != nil || grant == nil || grant.ApplicationID != app.ID {
 		handleAccessTokenError(ctx, AccessTokenError{
 			ErrorCode:        AccessTokenErrorCodeInvalidGrant,
 			ErrorDescription: "grant does not exist",
@@ -407,9 +585,13 @@ func handleRefreshToken(ctx *context.Context, form auth.AccessTokenForm) {
 			ErrorDescription: "token was already used",
 		})
 		log.Warn("A client tried to use a refresh token for grant_id = %d was used twice!", grant.ID)
+		// the token may have been stolen, revoke the refresh token issued in exchange for it as well
+		if err := grant.IncreaseCounter(); err != nil {
+			log.Error("Error increasing the counter of grant %d: %v", grant.ID, err)
+		}
 		return
 	}
-	accessToken, tokenErr := newAccessTokenResponse(grant)
+	accessToken, tokenErr := newAccessTokenResponse(app, grant, "")
 	if tokenErr != nil {
 		handleAccessTokenError(ctx, *tokenErr)
 		return
@@ -470,8 +652,9 @@ func handleAuthorizationCode(ctx *context.Context, form auth.AccessTokenForm) {
 			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
 			ErrorDescription: "cannot proceed your request",
 		})
+		return
 	}
-	resp, tokenErr := newAccessTokenResponse(authorizationCode.Grant)
+	resp, tokenErr := newAccessTokenResponse(app, authorizationCode.Grant, authorizationCode.Nonce)
 	if tokenErr != nil {
 		handleAccessTokenError(ctx, *tokenErr)
 		return
@@ -480,6 +663,26 @@ func handleAuthorizationCode(ctx *context.Context, form auth.AccessTokenForm) {
 	ctx.JSON(200, resp)
 }
 
+// grantContainsScope returns true if the scope of the grant contains every space separated scope
+func grantContainsScope(grant *models.OAuth2Grant, scope string) bool {
+	for _, s := range strings.Fields(scope) {
+		if !grant.ScopeContains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// scopeContains returns true if the space separated scope contains s
+func scopeContains(scope, s string) bool {
+	for _, field := range strings.Fields(scope) {
+		if field == s {
+			return true
+		}
+	}
+	return false
+}
+
 func handleAccessTokenError(ctx *context.Context, acErr AccessTokenError) {
 	ctx.JSON(400, acErr)
 }
@@ -0,0 +1,211 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+const (
+	tplPackagesList base.TplName = "package/list"
+	tplPackageView  base.TplName = "package/view"
+)
+
+// Packages renders the list of packages of a user or an organization
+func Packages(ctx *context.Context) {
+	if ctx.Package.AccessMode < models.AccessModeRead {
+		ctx.NotFound("Packages", nil)
+		return
+	}
+
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	keyword := strings.Trim(ctx.Query("q"), " ")
+
+	packages, total, err := models.SearchPackages(&models.PackageSearchOptions{
+		ListOptions: models.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.ExplorePagingNum,
+		},
+		OwnerID: ctx.Package.Owner.ID,
+		Keyword: keyword,
+	})
+	if err != nil {
+		ctx.ServerError("SearchPackages", err)
+		return
+	}
+	for _, p := range packages {
+		p.Owner = ctx.Package.Owner
+	}
+
+	ctx.Data["Title"] = ctx.Tr("packages.title")
+	ctx.Data["ContextUser"] = ctx.Package.Owner
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["Packages"] = packages
+	ctx.Data["Total"] = total
+
+	pager := context.NewPagination(int(total), setting.UI.ExplorePagingNum, page, 5)
+	pager.AddParam(ctx, "q", "Keyword")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplPackagesList)
+}
+
+// getPackageFromParams returns the package of the URL or renders a not found page
+func getPackageFromParams(ctx *context.Context) *models.Package {
+	if ctx.Package.AccessMode < models.AccessModeRead {
+		ctx.NotFound("Package", nil)
+		return nil
+	}
+
+	packageType, ok := models.ParsePackageType(ctx.Params(":type"))
+	if !ok {
+		ctx.NotFound("ParsePackageType", nil)
+		return nil
+	}
+
+	p, err := models.GetPackageByName(ctx.Package.Owner.ID, packageType, ctx.Params(":name"))
+	if err != nil {
+		if models.IsErrPackageNotExist(err) {
+			ctx.NotFound("GetPackageByName", err)
+		} else {
+			ctx.ServerError("GetPackageByName", err)
+		}
+		return nil
+	}
+	p.Owner = ctx.Package.Owner
+	return p
+}
+
+// getPackageVersionFromParams returns the package version of the URL or renders a not found page
+func getPackageVersionFromParams(ctx *context.Context) *models.PackageVersion {
+	p := getPackageFromParams(ctx)
+	if ctx.Written() {
+		return nil
+	}
+
+	pv, err := models.GetPackageVersionByName(p.OwnerID, p.Type, p.Name, ctx.Params(":version"))
+	if err != nil {
+		if models.IsErrPackageVersionNotExist(err) {
+			ctx.NotFound("GetPackageVersionByName", err)
+		} else {
+			ctx.ServerError("GetPackageVersionByName", err)
+		}
+		return nil
+	}
+	pv.Package = p
+	if pv.IsInternal() {
+		ctx.NotFound("GetPackageVersionByName", nil)
+		return nil
+	}
+	if err := pv.LoadAttributes(); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return nil
+	}
+	return pv
+}
+
+// PackageLatestVersion redirects to the most recent version of a package
+func PackageLatestVersion(ctx *context.Context) {
+	p := getPackageFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	versions, _, err := models.GetPackageVersionsByPackageID(p.ID, models.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetPackageVersionsByPackageID", err)
+		return
+	}
+	for _, pv := range versions {
+		pv.Package = p
+		if !pv.IsInternal() {
+			ctx.Redirect(pv.HTMLURL())
+			return
+		}
+	}
+	ctx.NotFound("GetPackageVersionsByPackageID", nil)
+}
+
+// PackageVersion renders a version of a package with its files
+func PackageVersion(ctx *context.Context) {
+	pv := getPackageVersionFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	files, err := models.GetPackageFilesByVersionID(pv.ID)
+	if err != nil {
+		ctx.ServerError("GetPackageFilesByVersionID", err)
+		return
+	}
+
+	allVersions, _, err := models.GetPackageVersionsByPackageID(pv.PackageID, models.ListOptions{})
+	if err != nil {
+		ctx.ServerError("GetPackageVersionsByPackageID", err)
+		return
+	}
+	versions := make([]*models.PackageVersion, 0, len(allVersions))
+	for _, v := range allVersions {
+		v.Package = pv.Package
+		if !v.IsInternal() {
+			versions = append(versions, v)
+		}
+	}
+
+	ctx.Data["Title"] = pv.Package.Name + " " + pv.Version
+	ctx.Data["ContextUser"] = ctx.Package.Owner
+	ctx.Data["PackageVersion"] = pv
+	ctx.Data["PackageFiles"] = files
+	ctx.Data["PackageVersions"] = versions
+	if pv.Package.Type == models.PackageContainer {
+		// The container registry is always served at the root of the host
+		appURL, err := url.Parse(setting.AppURL)
+		if err != nil {
+			ctx.ServerError("url.Parse", err)
+			return
+		}
+		ctx.Data["PackageRegistryURL"] = appURL.Host + "/" + ctx.Package.Owner.LowerName
+	} else {
+		ctx.Data["PackageRegistryURL"] = setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/" + pv.Package.Type.Name()
+	}
+	ctx.Data["CanWritePackages"] = ctx.Package.AccessMode >= models.AccessModeWrite
+
+	ctx.HTML(200, tplPackageView)
+}
+
+// DeletePackageVersion deletes a version of a package with all its files
+func DeletePackageVersion(ctx *context.Context) {
+	if ctx.Package.AccessMode < models.AccessModeWrite {
+		ctx.NotFound("DeletePackageVersion", nil)
+		return
+	}
+
+	pv := getPackageVersionFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages_service.RemovePackageVersion(pv); err != nil {
+		ctx.ServerError("RemovePackageVersion", err)
+		return
+	}
+	log.Trace("Package version deleted: %s/%s/%s", ctx.Package.Owner.Name, pv.Package.Name, pv.Version)
+
+	ctx.Flash.Success(ctx.Tr("packages.delete.success"))
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": ctx.Package.Owner.HTMLURL() + "/-/packages",
+	})
+}
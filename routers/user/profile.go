@@ -276,8 +276,12 @@ func Action(ctx *context.Context) {
 	}
 
 	if err != nil {
-		ctx.ServerError(fmt.Sprintf("Action (%s)", ctx.Params(":action")), err)
-		return
+		if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("user.follow_blocked"))
+		} else {
+			ctx.ServerError(fmt.Sprintf("Action (%s)", ctx.Params(":action")), err)
+			return
+		}
 	}
 
 	ctx.RedirectToFirst(ctx.Query("redirect_to"), u.HomeLink())
@@ -0,0 +1,508 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	tplProjects     base.TplName = "project/list"
+	tplProjectsNew  base.TplName = "project/new"
+	tplProjectsView base.TplName = "project/view"
+)
+
+// projectsLink returns the link to the projects of the owner of the current request
+func projectsLink(ctx *context.Context) string {
+	return ctx.ProjectOwner.Owner.HomeLink() + "/-/projects"
+}
+
+// Projects renders the list of projects of a user or an organization
+func Projects(ctx *context.Context) {
+	owner := ctx.ProjectOwner.Owner
+	sortType := ctx.QueryTrim("sort")
+	isShowClosed := strings.ToLower(ctx.QueryTrim("state")) == "closed"
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	projects, total, err := models.GetProjects(models.ProjectSearchOptions{
+		OwnerID:  owner.ID,
+		Page:     page,
+		IsClosed: util.OptionalBoolOf(isShowClosed),
+		SortType: sortType,
+	})
+	if err != nil {
+		ctx.ServerError("GetProjects", err)
+		return
+	}
+	for _, p := range projects {
+		p.Owner = owner
+		p.RenderedContent = string(markdown.Render([]byte(p.Description), owner.HomeLink(), nil))
+	}
+
+	openCount, err := models.CountProjects(models.ProjectSearchOptions{OwnerID: owner.ID, IsClosed: util.OptionalBoolFalse})
+	if err != nil {
+		ctx.ServerError("CountProjects", err)
+		return
+	}
+	closedCount, err := models.CountProjects(models.ProjectSearchOptions{OwnerID: owner.ID, IsClosed: util.OptionalBoolTrue})
+	if err != nil {
+		ctx.ServerError("CountProjects", err)
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.project_board")
+	ctx.Data["ProjectsLink"] = projectsLink(ctx)
+	ctx.Data["Projects"] = projects
+	ctx.Data["OpenCount"] = openCount
+	ctx.Data["ClosedCount"] = closedCount
+	ctx.Data["IsShowClosed"] = isShowClosed
+	ctx.Data["SortType"] = sortType
+	if isShowClosed {
+		ctx.Data["State"] = "closed"
+	} else {
+		ctx.Data["State"] = "open"
+	}
+
+	pager := context.NewPagination(int(total), setting.UI.IssuePagingNum, page, 5)
+	pager.AddParam(ctx, "state", "State")
+	pager.AddParam(ctx, "sort", "SortType")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplProjects)
+}
+
+// NewProject renders the page to create a project of a user or an organization
+func NewProject(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.new")
+	ctx.Data["ProjectsLink"] = projectsLink(ctx)
+	ctx.Data["ProjectTypes"] = models.GetProjectsConfig()
+	ctx.HTML(200, tplProjectsNew)
+}
+
+// NewProjectPost creates a project of a user or an organization
+func NewProjectPost(ctx *context.Context, form auth.CreateProjectForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.new")
+	ctx.Data["ProjectsLink"] = projectsLink(ctx)
+
+	if ctx.HasError() {
+		ctx.Data["ProjectTypes"] = models.GetProjectsConfig()
+		ctx.HTML(200, tplProjectsNew)
+		return
+	}
+
+	owner := ctx.ProjectOwner.Owner
+	projectType := models.ProjectTypeIndividual
+	if owner.IsOrganization() {
+		projectType = models.ProjectTypeOrganization
+	}
+
+	if err := models.NewProject(&models.Project{
+		OwnerID:     owner.ID,
+		Title:       form.Title,
+		Description: form.Content,
+		CreatorID:   ctx.User.ID,
+		BoardType:   form.BoardType,
+		Type:        projectType,
+	}); err != nil {
+		ctx.ServerError("NewProject", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.create_success", form.Title))
+	ctx.Redirect(projectsLink(ctx))
+}
+
+// getProjectFromParams returns the project of the URL or renders a not found page
+func getProjectFromParams(ctx *context.Context) *models.Project {
+	p, err := models.GetProjectByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetProjectByID", models.IsErrProjectNotExist, err)
+		return nil
+	}
+	if p.Type == models.ProjectTypeRepository || p.OwnerID != ctx.ProjectOwner.Owner.ID {
+		ctx.NotFound("GetProjectByID", nil)
+		return nil
+	}
+	p.Owner = ctx.ProjectOwner.Owner
+	return p
+}
+
+// getProjectBoardFromParams returns the board of the project of the URL or renders a not found
+// page, the board with ID 0 holds the issues not assigned to any other board
+func getProjectBoardFromParams(ctx *context.Context, p *models.Project) *models.ProjectBoard {
+	boardID := ctx.ParamsInt64(":boardID")
+	if boardID == 0 {
+		board, err := models.GetUncategorizedBoard(p.ID)
+		if err != nil {
+			ctx.ServerError("GetUncategorizedBoard", err)
+			return nil
+		}
+		return board
+	}
+
+	board, err := models.GetProjectBoard(boardID)
+	if err != nil {
+		ctx.NotFoundOrServerError("GetProjectBoard", models.IsErrProjectBoardNotExist, err)
+		return nil
+	}
+	if board.ProjectID != p.ID {
+		ctx.NotFound("GetProjectBoard", nil)
+		return nil
+	}
+	return board
+}
+
+// getVisibleIssueFromParams returns the issue or pull request of the URL if the signed in
+// user may read it or renders a not found page
+func getVisibleIssueFromParams(ctx *context.Context) *models.Issue {
+	issue, err := models.GetIssueByID(ctx.ParamsInt64(":index"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetIssueByID", models.IsErrIssueNotExist, err)
+		return nil
+	}
+	if err = issue.LoadRepo(); err != nil {
+		ctx.ServerError("LoadRepo", err)
+		return nil
+	}
+	perm, err := models.GetUserRepoPermission(issue.Repo, ctx.User)
+	if err != nil {
+		ctx.ServerError("GetUserRepoPermission", err)
+		return nil
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.NotFound("CanReadIssuesOrPulls", nil)
+		return nil
+	}
+	return issue
+}
+
+// ViewProject renders the boards of a project of a user or an organization
+func ViewProject(ctx *context.Context) {
+	project := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	uncategorizedBoard, err := models.GetUncategorizedBoard(project.ID)
+	if err != nil {
+		ctx.ServerError("GetUncategorizedBoard", err)
+		return
+	}
+	uncategorizedBoard.Title = ctx.Tr("repo.projects.type.uncategorized")
+
+	boards, err := models.GetProjectBoards(project.ID)
+	if err != nil {
+		ctx.ServerError("GetProjectBoards", err)
+		return
+	}
+
+	allBoards := models.ProjectBoardList{uncategorizedBoard}
+	allBoards = append(allBoards, boards...)
+
+	if ctx.Data["Issues"], err = allBoards.LoadIssuesVisibleTo(ctx.User); err != nil {
+		ctx.ServerError("LoadIssuesOfBoards", err)
+		return
+	}
+
+	ctx.Data["Title"] = project.Title
+	ctx.Data["ProjectsLink"] = projectsLink(ctx)
+	ctx.Data["Project"] = project
+	ctx.Data["Boards"] = allBoards
+	ctx.Data["PageIsProjects"] = true
+	ctx.Data["RequiresDraggable"] = true
+
+	ctx.HTML(200, tplProjectsView)
+}
+
+// EditProject renders the page to edit a project of a user or an organization
+func EditProject(ctx *context.Context) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.Data["Title"] = ctx.Tr("repo.projects.edit")
+	ctx.Data["ProjectsLink"] = projectsLink(ctx)
+	ctx.Data["PageIsEditProjects"] = true
+	ctx.Data["title"] = p.Title
+	ctx.Data["content"] = p.Description
+	ctx.HTML(200, tplProjectsNew)
+}
+
+// EditProjectPost updates a project of a user or an organization
+func EditProjectPost(ctx *context.Context, form auth.CreateProjectForm) {
+	ctx.Data["Title"] = ctx.Tr("repo.projects.edit")
+	ctx.Data["ProjectsLink"] = projectsLink(ctx)
+	ctx.Data["PageIsEditProjects"] = true
+
+	if ctx.HasError() {
+		ctx.HTML(200, tplProjectsNew)
+		return
+	}
+
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	p.Title = form.Title
+	p.Description = form.Content
+	if err := models.UpdateProject(p); err != nil {
+		ctx.ServerError("UpdateProject", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.projects.edit_success", p.Title))
+	ctx.Redirect(projectsLink(ctx))
+}
+
+// ChangeProjectStatus opens or closes a project of a user or an organization
+func ChangeProjectStatus(ctx *context.Context) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.ChangeProjectStatus(p, ctx.Params(":action") == "close"); err != nil {
+		ctx.ServerError("ChangeProjectStatus", err)
+		return
+	}
+	ctx.Redirect(projectsLink(ctx) + "?state=" + ctx.Params(":action"))
+}
+
+// DeleteProject deletes a project of a user or an organization
+func DeleteProject(ctx *context.Context) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.DeleteProjectByID(p.ID); err != nil {
+		ctx.Flash.Error("DeleteProjectByID: " + err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.projects.deletion_success"))
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": projectsLink(ctx),
+	})
+}
+
+// AddBoardToProjectPost adds a board to a project of a user or an organization
+func AddBoardToProjectPost(ctx *context.Context, form auth.EditProjectBoardTitleForm) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.NewProjectBoard(&models.ProjectBoard{
+		ProjectID: p.ID,
+		Title:     form.Title,
+		CreatorID: ctx.User.ID,
+	}); err != nil {
+		ctx.ServerError("NewProjectBoard", err)
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// EditProjectBoardTitle changes the title of a board of a project of a user or an organization
+func EditProjectBoardTitle(ctx *context.Context, form auth.EditProjectBoardTitleForm) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardFromParams(ctx, p)
+	if ctx.Written() {
+		return
+	}
+	if board.ID == 0 {
+		ctx.NotFound("EditProjectBoardTitle", nil)
+		return
+	}
+
+	if form.Title != "" {
+		board.Title = form.Title
+	}
+	if err := models.UpdateProjectBoard(board); err != nil {
+		ctx.ServerError("UpdateProjectBoard", err)
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// DeleteProjectBoard deletes a board of a project of a user or an organization, its issues
+// become uncategorized
+func DeleteProjectBoard(ctx *context.Context) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardFromParams(ctx, p)
+	if ctx.Written() {
+		return
+	}
+	if board.ID == 0 {
+		ctx.NotFound("DeleteProjectBoard", nil)
+		return
+	}
+
+	if err := models.DeleteProjectBoardByID(board.ID); err != nil {
+		ctx.ServerError("DeleteProjectBoardByID", err)
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// getIssueByRef returns the issue or pull request referenced like "owner/repo#1" if the signed
+// in user may read it, or nil if there is no such issue
+func getIssueByRef(ctx *context.Context, ref string) (*models.Issue, error) {
+	ref = strings.TrimSpace(ref)
+	sep := strings.LastIndexByte(ref, '#')
+	if sep < 0 {
+		return nil, nil
+	}
+	index, err := strconv.ParseInt(ref[sep+1:], 10, 64)
+	if err != nil {
+		return nil, nil
+	}
+	parts := strings.SplitN(ref[:sep], "/", 2)
+	if len(parts) != 2 {
+		return nil, nil
+	}
+
+	repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	issue, err := models.GetIssueByIndex(repo.ID, index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	perm, err := models.GetUserRepoPermission(repo, ctx.User)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return nil, nil
+	}
+	issue.Repo = repo
+	return issue, nil
+}
+
+// AddIssueToProjectPost puts an issue or pull request of any repository visible to the signed
+// in user on a project of a user or an organization
+func AddIssueToProjectPost(ctx *context.Context, form auth.AddProjectCardForm) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	issue, err := getIssueByRef(ctx, form.Issue)
+	if err != nil {
+		ctx.ServerError("getIssueByRef", err)
+		return
+	}
+	if ctx.HasError() || issue == nil {
+		ctx.Flash.Error(ctx.Tr("repo.projects.card.issue_invalid", form.Issue))
+		ctx.Redirect(p.Link())
+		return
+	}
+
+	if err := models.AddIssueToProject(issue, ctx.User, p); err != nil {
+		if models.IsErrBlockedByUser(err) {
+			ctx.Flash.Error(ctx.Tr("repo.projects.card.blocked", form.Issue))
+			ctx.Redirect(p.Link())
+			return
+		}
+		ctx.ServerError("AddIssueToProject", err)
+		return
+	}
+
+	ctx.Redirect(p.Link())
+}
+
+// RemoveIssueFromProject takes an issue or pull request off a project of a user or an organization
+func RemoveIssueFromProject(ctx *context.Context) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	issue := getVisibleIssueFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.RemoveIssueFromProject(issue, ctx.User, p); err != nil {
+		if models.IsErrProjectIssueNotExist(err) {
+			ctx.NotFound("RemoveIssueFromProject", nil)
+		} else {
+			ctx.ServerError("RemoveIssueFromProject", err)
+		}
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
+
+// MoveIssueAcrossBoards moves a card from one board to another of a project of a user or
+// an organization
+func MoveIssueAcrossBoards(ctx *context.Context) {
+	p := getProjectFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	board := getProjectBoardFromParams(ctx, p)
+	if ctx.Written() {
+		return
+	}
+	issue := getVisibleIssueFromParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := models.MoveIssueAcrossProjectBoards(issue, board); err != nil {
+		if models.IsErrProjectIssueNotExist(err) {
+			ctx.NotFound("MoveIssueAcrossProjectBoards", nil)
+		} else {
+			ctx.ServerError("MoveIssueAcrossProjectBoards", err)
+		}
+		return
+	}
+
+	ctx.JSON(200, map[string]interface{}{
+		"ok": true,
+	})
+}
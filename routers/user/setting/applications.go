@@ -6,6 +6,8 @@
 package setting
 
 import (
+	"strings"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/auth"
 	"code.gitea.io/gitea/modules/base"
@@ -39,9 +41,26 @@ func ApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm) {
 		return
 	}
 
+	scope, err := models.ParseAccessTokenScope(form.Scope)
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("settings.token_scope_invalid"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+		return
+	}
+
 	t := &models.AccessToken{
-		UID:  ctx.User.ID,
-		Name: form.Name,
+		UID:   ctx.User.ID,
+		Name:  form.Name,
+		Scope: scope,
+	}
+	if err := t.SetRepositories(strings.Split(form.Repositories, ",")); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("settings.token_repositories_invalid"))
+			ctx.Redirect(setting.AppSubURL + "/user/settings/applications")
+			return
+		}
+		ctx.ServerError("SetRepositories", err)
+		return
 	}
 
 	exist, err := models.AccessTokenByNameExists(t)
@@ -59,6 +78,11 @@ func ApplicationsPost(ctx *context.Context, form auth.NewAccessTokenForm) {
 		ctx.ServerError("NewAccessToken", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditAccessTokenCreate,
+		TargetID:   t.ID,
+		TargetName: t.Name,
+	})
 
 	ctx.Flash.Success(ctx.Tr("settings.generate_token_success"))
 	ctx.Flash.Info(t.Token)
@@ -71,6 +95,7 @@ func DeleteApplication(ctx *context.Context) {
 	if err := models.DeleteAccessTokenByID(ctx.QueryInt64("id"), ctx.User.ID); err != nil {
 		ctx.Flash.Error("DeleteAccessTokenByID: " + err.Error())
 	} else {
+		ctx.Audit(&models.AuditEvent{Action: models.AuditAccessTokenDelete, TargetID: ctx.QueryInt64("id")})
 		ctx.Flash.Success(ctx.Tr("settings.delete_token_success"))
 	}
 
@@ -86,6 +111,7 @@ func loadApplicationsData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["Tokens"] = tokens
+	ctx.Data["TokenScopeCategories"] = models.AllAccessTokenScopeCategories
 	ctx.Data["EnableOAuth2"] = setting.OAuth2.Enable
 	if setting.OAuth2.Enable {
 		ctx.Data["Applications"], err = models.GetOAuth2ApplicationsByUserID(ctx.User.ID)
@@ -0,0 +1,86 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsAuditLog base.TplName = "user/settings/audit_log"
+)
+
+// AuditLog render the security events of the account of the user
+func AuditLog(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsAuditLog"] = true
+
+	RenderAuditLog(ctx, &models.FindAuditEventsOptions{OwnerID: ctx.User.ID}, tplSettingsAuditLog)
+}
+
+// RenderAuditLog renders a page of the audit events matching the options, the events can be
+// filtered further by the action and the actor query parameters
+func RenderAuditLog(ctx *context.Context, opts *models.FindAuditEventsOptions, tplName base.TplName) {
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	opts.ListOptions = models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.Admin.NoticePagingNum,
+	}
+
+	if action := models.AuditAction(ctx.Query("action")); action.IsValid() {
+		opts.Action = action
+		ctx.Data["AuditAction"] = action
+	}
+
+	var (
+		events models.AuditEventList
+		count  int64
+		err    error
+	)
+	actorName := strings.TrimSpace(ctx.Query("actor"))
+	if len(actorName) > 0 {
+		ctx.Data["AuditActor"] = actorName
+		actor, err := models.GetUserByName(actorName)
+		if err != nil && !models.IsErrUserNotExist(err) {
+			ctx.ServerError("GetUserByName", err)
+			return
+		}
+		if actor != nil {
+			opts.ActorID = actor.ID
+		}
+	}
+
+	// an unknown actor has no events
+	if len(actorName) == 0 || opts.ActorID > 0 {
+		events, count, err = models.FindAuditEvents(opts)
+		if err != nil {
+			ctx.ServerError("FindAuditEvents", err)
+			return
+		}
+		if err = events.LoadAttributes(); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+	}
+
+	ctx.Data["AuditActions"] = models.AuditActions
+	ctx.Data["AuditEvents"] = events
+	ctx.Data["Total"] = count
+
+	pager := context.NewPagination(int(count), opts.PageSize, page, 5)
+	pager.AddParam(ctx, "action", "AuditAction")
+	pager.AddParam(ctx, "actor", "AuditActor")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(200, tplName)
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplSettingsBlockedUsers base.TplName = "user/settings/blocked_users"
+)
+
+// BlockedUsers render the users blocked by the user
+func BlockedUsers(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("settings")
+	ctx.Data["PageIsSettingsBlockedUsers"] = true
+
+	RenderBlockedUsers(ctx, ctx.User, tplSettingsBlockedUsers)
+}
+
+// BlockedUsersPost response for blocking a user
+func BlockedUsersPost(ctx *context.Context, form auth.BlockUserForm) {
+	HandleBlockUser(ctx, ctx.User, form, setting.AppSubURL+"/user/settings/blocked_users")
+}
+
+// UnblockUser response for unblocking a user
+func UnblockUser(ctx *context.Context) {
+	HandleUnblockUser(ctx, ctx.User, setting.AppSubURL+"/user/settings/blocked_users")
+}
+
+// RenderBlockedUsers renders a page of the users blocked by the blocker
+func RenderBlockedUsers(ctx *context.Context, blocker *models.User, tplName base.TplName) {
+	page := ctx.QueryInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	listOptions := models.ListOptions{
+		Page:     page,
+		PageSize: setting.UI.User.RepoPagingNum,
+	}
+
+	blocks, err := models.GetBlockedUsers(blocker.ID, listOptions)
+	if err != nil {
+		ctx.ServerError("GetBlockedUsers", err)
+		return
+	}
+	count, err := models.CountBlockedUsers(blocker.ID)
+	if err != nil {
+		ctx.ServerError("CountBlockedUsers", err)
+		return
+	}
+	ctx.Data["BlockedUsers"] = blocks
+	ctx.Data["Page"] = context.NewPagination(int(count), listOptions.PageSize, page, 5)
+
+	ctx.HTML(200, tplName)
+}
+
+// HandleBlockUser blocks the user of the form for the blocker and redirects to the list of
+// blocked users
+func HandleBlockUser(ctx *context.Context, blocker *models.User, form auth.BlockUserForm, redirectTo string) {
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(redirectTo)
+		return
+	}
+
+	blockee, err := models.GetUserByName(strings.TrimSpace(form.Blockee))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("form.user_not_exist"))
+			ctx.Redirect(redirectTo)
+		} else {
+			ctx.ServerError("GetUserByName", err)
+		}
+		return
+	}
+
+	if err := models.BlockUser(blocker, blockee, form.Note); err != nil {
+		if models.IsErrCannotBlockUser(err) {
+			ctx.Flash.Error(ctx.Tr("settings.blocked_users.cannot_block"))
+			ctx.Redirect(redirectTo)
+		} else {
+			ctx.ServerError("BlockUser", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.blocked_users.block_success", blockee.Name))
+	ctx.Redirect(redirectTo)
+}
+
+// HandleUnblockUser removes the block of the user given by the uid for the blocker and redirects
+// to the list of blocked users
+func HandleUnblockUser(ctx *context.Context, blocker *models.User, redirectTo string) {
+	if err := models.UnblockUser(blocker.ID, ctx.QueryInt64("uid")); err != nil {
+		ctx.ServerError("UnblockUser", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("settings.blocked_users.unblock_success"))
+	ctx.Redirect(redirectTo)
+}
@@ -65,6 +65,11 @@ func KeysPost(ctx *context.Context, form auth.AddKeyForm) {
 		}
 		keyIDs := ""
 		for _, key := range keys {
+			ctx.Audit(&models.AuditEvent{
+				Action:     models.AuditGPGKeyAdd,
+				TargetID:   key.ID,
+				TargetName: key.KeyID,
+			})
 			keyIDs += key.KeyID
 			keyIDs += ", "
 		}
@@ -87,7 +92,8 @@ func KeysPost(ctx *context.Context, form auth.AddKeyForm) {
 			return
 		}
 
-		if _, err = models.AddPublicKey(ctx.User.ID, form.Title, content, 0); err != nil {
+		key, err := models.AddPublicKey(ctx.User.ID, form.Title, content, 0)
+		if err != nil {
 			ctx.Data["HasSSHError"] = true
 			switch {
 			case models.IsErrKeyAlreadyExist(err):
@@ -108,6 +114,12 @@ func KeysPost(ctx *context.Context, form auth.AddKeyForm) {
 			}
 			return
 		}
+		ctx.Audit(&models.AuditEvent{
+			Action:      models.AuditPublicKeyAdd,
+			TargetID:    key.ID,
+			TargetName:  key.Name,
+			Description: key.Fingerprint,
+		})
 		ctx.Flash.Success(ctx.Tr("settings.add_key_success", form.Title))
 		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
 
@@ -126,12 +138,14 @@ func DeleteKey(ctx *context.Context) {
 		if err := models.DeleteGPGKey(ctx.User, ctx.QueryInt64("id")); err != nil {
 			ctx.Flash.Error("DeleteGPGKey: " + err.Error())
 		} else {
+			ctx.Audit(&models.AuditEvent{Action: models.AuditGPGKeyDelete, TargetID: ctx.QueryInt64("id")})
 			ctx.Flash.Success(ctx.Tr("settings.gpg_key_deletion_success"))
 		}
 	case "ssh":
 		if err := models.DeletePublicKey(ctx.User, ctx.QueryInt64("id")); err != nil {
 			ctx.Flash.Error("DeletePublicKey: " + err.Error())
 		} else {
+			ctx.Audit(&models.AuditEvent{Action: models.AuditPublicKeyDelete, TargetID: ctx.QueryInt64("id")})
 			ctx.Flash.Success(ctx.Tr("settings.ssh_key_deletion_success"))
 		}
 	default:
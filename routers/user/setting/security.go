@@ -63,12 +63,11 @@ func loadSecurityData(ctx *context.Context) {
 	}
 	ctx.Data["TwofaEnrolled"] = enrolled
 	if enrolled {
-		ctx.Data["U2FRegistrations"], err = models.GetU2FRegistrationsByUID(ctx.User.ID)
+		ctx.Data["WebAuthnCredentials"], err = models.GetWebAuthnCredentialsByUID(ctx.User.ID)
 		if err != nil {
-			ctx.ServerError("GetU2FRegistrationsByUID", err)
+			ctx.ServerError("GetWebAuthnCredentialsByUID", err)
 			return
 		}
-		ctx.Data["RequireU2F"] = true
 	}
 
 	tokens, err := models.ListAccessTokens(models.ListAccessTokensOptions{UserID: ctx.User.ID})
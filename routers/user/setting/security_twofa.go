@@ -47,6 +47,7 @@ func RegenerateScratchTwoFactor(ctx *context.Context) {
 		ctx.ServerError("SettingsTwoFactor: Failed to UpdateTwoFactor", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditTwoFactorRegenerateScratch})
 
 	ctx.Flash.Success(ctx.Tr("settings.twofa_scratch_token_regenerated", token))
 	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
@@ -76,6 +77,7 @@ func DisableTwoFactor(ctx *context.Context) {
 		ctx.ServerError("SettingsTwoFactor: Failed to DeleteTwoFactorByID", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditTwoFactorDisable})
 
 	ctx.Flash.Success(ctx.Tr("settings.twofa_disabled"))
 	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
@@ -234,6 +236,7 @@ func EnrollTwoFactorPost(ctx *context.Context, form auth.TwoFactorAuthForm) {
 		ctx.ServerError("SettingsTwoFactor: Failed to save two factor", err)
 		return
 	}
+	ctx.Audit(&models.AuditEvent{Action: models.AuditTwoFactorEnable})
 
 	ctx.Flash.Success(ctx.Tr("settings.twofa_enrolled", token))
 	ctx.Redirect(setting.AppSubURL + "/user/settings/security")
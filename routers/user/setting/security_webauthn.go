@@ -0,0 +1,145 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"errors"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/auth"
+	wa "code.gitea.io/gitea/modules/auth/webauthn"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/duo-labs/webauthn/protocol"
+	"github.com/duo-labs/webauthn/webauthn"
+)
+
+// WebAuthnRegister initializes the webauthn registration procedure
+func WebAuthnRegister(ctx *context.Context, form auth.WebAuthnRegistrationForm) {
+	if form.Name == "" {
+		ctx.Error(409)
+		return
+	}
+
+	if _, err := models.GetWebAuthnCredentialByName(ctx.User.ID, form.Name); err == nil {
+		ctx.Error(409, "Name already taken")
+		return
+	} else if !models.IsErrWebAuthnCredentialNotExist(err) {
+		ctx.ServerError("GetWebAuthnCredentialByName", err)
+		return
+	}
+
+	creds, err := models.GetWebAuthnCredentialsByUID(ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("GetWebAuthnCredentialsByUID", err)
+		return
+	}
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(creds))
+	for _, cred := range creds {
+		exclusions = append(exclusions, protocol.CredentialDescriptor{
+			Type:         protocol.PublicKeyCredentialType,
+			CredentialID: cred.RawCredentialID(),
+		})
+	}
+
+	opts := []webauthn.RegistrationOption{webauthn.WithExclusions(exclusions)}
+	if form.Passwordless {
+		// passwordless login needs a credential which is stored on the authenticator and
+		// an authenticator which verifies the user, e.g. by a PIN or biometrics
+		requireResidentKey := true
+		opts = append(opts, webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			RequireResidentKey: &requireResidentKey,
+			UserVerification:   protocol.VerificationRequired,
+		}))
+	}
+
+	credentialOptions, sessionData, err := wa.WebAuthn.BeginRegistration((*wa.User)(ctx.User), opts...)
+	if err != nil {
+		ctx.ServerError("Unable to BeginRegistration", err)
+		return
+	}
+
+	if err := ctx.Session.Set("webauthnRegistration", sessionData); err != nil {
+		ctx.ServerError("Unable to set session key for webauthnRegistration", err)
+		return
+	}
+	if err := ctx.Session.Set("webauthnName", form.Name); err != nil {
+		ctx.ServerError("Unable to set session key for webauthnName", err)
+		return
+	}
+	// Here we're just going to try to release the session early
+	if err := ctx.Session.Release(); err != nil {
+		// we'll tolerate errors here as they *should* get saved elsewhere
+		log.Error("Unable to save changes to the session: %v", err)
+	}
+
+	ctx.JSON(200, credentialOptions)
+}
+
+// WebAuthnRegisterPost receives the response of the security key
+func WebAuthnRegisterPost(ctx *context.Context) {
+	name, ok := ctx.Session.Get("webauthnName").(string)
+	sessionData, okData := ctx.Session.Get("webauthnRegistration").(*webauthn.SessionData)
+	if !ok || !okData || name == "" || sessionData == nil {
+		ctx.ServerError("WebAuthnRegisterPost", errors.New("not in WebAuthn session"))
+		return
+	}
+	defer func() {
+		_ = ctx.Session.Delete("webauthnRegistration")
+		_ = ctx.Session.Delete("webauthnName")
+	}()
+
+	parsedResponse, err := protocol.ParseCredentialCreationResponse(ctx.Req.Request)
+	if err != nil {
+		log.Info("Invalid WebAuthn registration of %s: %v", ctx.User.Name, err)
+		ctx.Error(400, err.Error())
+		return
+	}
+
+	// Verify that the challenge succeeded
+	cred, err := wa.WebAuthn.CreateCredential((*wa.User)(ctx.User), *sessionData, parsedResponse)
+	if err != nil {
+		log.Info("Invalid WebAuthn registration of %s: %v", ctx.User.Name, err)
+		ctx.Error(400, err.Error())
+		return
+	}
+
+	if _, err := models.GetWebAuthnCredentialByCredID(cred.ID); err == nil {
+		ctx.Error(409, "Credential already registered")
+		return
+	} else if !models.IsErrWebAuthnCredentialNotExist(err) {
+		ctx.ServerError("GetWebAuthnCredentialByCredID", err)
+		return
+	}
+
+	credential, err := models.CreateCredential(ctx.User.ID, name, cred)
+	if err != nil {
+		ctx.ServerError("CreateCredential", err)
+		return
+	}
+	ctx.Audit(&models.AuditEvent{
+		Action:     models.AuditWebAuthnRegister,
+		TargetID:   credential.ID,
+		TargetName: credential.Name,
+	})
+	ctx.Status(201)
+}
+
+// WebAuthnDelete deletes a security key by id
+func WebAuthnDelete(ctx *context.Context, form auth.WebAuthnDeleteForm) {
+	deleted, err := models.DeleteCredential(form.ID, ctx.User.ID)
+	if err != nil {
+		ctx.ServerError("DeleteCredential", err)
+		return
+	}
+	if deleted {
+		ctx.Audit(&models.AuditEvent{Action: models.AuditWebAuthnRemove, TargetID: form.ID})
+	}
+	ctx.JSON(200, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/security",
+	})
+}
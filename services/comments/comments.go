@@ -11,6 +11,10 @@ import (
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(doer *models.User, repo *models.Repository, issue *models.Issue, content string, attachments []string) (*models.Comment, error) {
+	if err := models.CheckUserBlockedFromIssue(doer.ID, issue); err != nil {
+		return nil, err
+	}
+
 	comment, err := models.CreateComment(&models.CreateCommentOptions{
 		Type:        models.CommentTypeComment,
 		Doer:        doer,
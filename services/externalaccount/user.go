@@ -15,7 +15,7 @@ import (
 
 // LinkAccountToUser link the gothUser to the user
 func LinkAccountToUser(user *models.User, gothUser goth.User) error {
-	loginSource, err := models.GetActiveOAuth2LoginSourceByName(gothUser.Provider)
+	loginSource, err := models.GetActiveExternalLoginSourceByName(gothUser.Provider)
 	if err != nil {
 		return err
 	}
@@ -60,3 +60,25 @@ func LinkAccountToUser(user *models.User, gothUser goth.User) error {
 
 	return nil
 }
+
+// RegisterAccount creates an activated account for the gothUser of a login source which
+// registers its users automatically and links the gothUser to it
+func RegisterAccount(loginSource *models.LoginSource, gothUser goth.User) (*models.User, error) {
+	u := &models.User{
+		Name:        gothUser.NickName,
+		FullName:    gothUser.Name,
+		Email:       gothUser.Email,
+		IsActive:    true,
+		LoginType:   loginSource.Type,
+		LoginSource: loginSource.ID,
+		LoginName:   gothUser.UserID,
+	}
+	if err := models.CreateUser(u); err != nil {
+		return nil, err
+	}
+
+	if err := LinkAccountToUser(u, gothUser); err != nil {
+		return u, err
+	}
+	return u, nil
+}
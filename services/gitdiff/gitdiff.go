@@ -76,6 +76,10 @@ type DiffLine struct {
 	Content     string
 	Comments    []*models.Comment
 	SectionInfo *DiffLineSectionInfo
+
+	// LeftInCommentRange and RightInCommentRange mark lines covered by a multi-line comment
+	LeftInCommentRange  bool
+	RightInCommentRange bool
 }
 
 // DiffLineSectionInfo represents diff line section meta data
@@ -110,6 +114,26 @@ func (d *DiffLine) GetCommentSide() string {
 	return d.Comments[0].DiffSide()
 }
 
+// InCommentRange returns whether the line is covered by a multi-line comment on either side
+func (d *DiffLine) InCommentRange() bool {
+	return d.LeftInCommentRange || d.RightInCommentRange
+}
+
+// markCommentRange marks the line if it is covered by the given multi-line comment
+func (d *DiffLine) markCommentRange(c *models.Comment) {
+	if !c.IsMultiLine() || d.Type == DiffLineSection {
+		return
+	}
+	start, end := int(c.UnsignedStartLine()), int(c.UnsignedLine())
+	if c.Line < 0 {
+		if d.LeftIdx >= start && d.LeftIdx <= end {
+			d.LeftInCommentRange = true
+		}
+	} else if d.RightIdx >= start && d.RightIdx <= end {
+		d.RightInCommentRange = true
+	}
+}
+
 // GetLineTypeMarker returns the line type marker
 func (d *DiffLine) GetLineTypeMarker() string {
 	if strings.IndexByte(" +-", d.Content[0]) > -1 {
@@ -420,8 +444,19 @@ func (diff *Diff) LoadComments(issue *models.Issue, currentUser *models.User) er
 	}
 	for _, file := range diff.Files {
 		if lineCommits, ok := allComments[file.Name]; ok {
+			var multiLineComments []*models.Comment
+			for _, comments := range lineCommits {
+				for _, comment := range comments {
+					if comment.IsMultiLine() {
+						multiLineComments = append(multiLineComments, comment)
+					}
+				}
+			}
 			for _, section := range file.Sections {
 				for _, line := range section.Lines {
+					for _, comment := range multiLineComments {
+						line.markCommentRange(comment)
+					}
 					if comments, ok := lineCommits[int64(line.LeftIdx*-1)]; ok {
 						line.Comments = append(line.Comments, comments...)
 					}
@@ -837,6 +872,11 @@ func CommentAsDiff(c *models.Comment) (*Diff, error) {
 	if len(secs) == 0 {
 		return nil, fmt.Errorf("no sections found for comment ID: %d", c.ID)
 	}
+	for _, sec := range secs {
+		for _, line := range sec.Lines {
+			line.markCommentRange(c)
+		}
+	}
 	return diff, nil
 }
 
@@ -326,6 +326,27 @@ func TestDiffLine_GetCommentSide(t *testing.T) {
 	assert.Equal(t, "proposed", (&DiffLine{Comments: []*models.Comment{{Line: 3}}}).GetCommentSide())
 }
 
+func TestDiffLine_markCommentRange(t *testing.T) {
+	proposed := &models.Comment{StartLine: 2, Line: 4}
+	previous := &models.Comment{StartLine: -2, Line: -4}
+
+	line := &DiffLine{Type: DiffLinePlain, LeftIdx: 3, RightIdx: 5}
+	line.markCommentRange(proposed)
+	line.markCommentRange(previous)
+	assert.False(t, line.RightInCommentRange)
+	assert.True(t, line.LeftInCommentRange)
+	assert.True(t, line.InCommentRange())
+
+	line = &DiffLine{Type: DiffLineAdd, RightIdx: 4}
+	line.markCommentRange(proposed)
+	assert.True(t, line.RightInCommentRange)
+
+	// single line comments do not mark ranges
+	line = &DiffLine{Type: DiffLineAdd, RightIdx: 4}
+	line.markCommentRange(&models.Comment{Line: 4})
+	assert.False(t, line.InCommentRange())
+}
+
 func TestGetDiffRangeWithWhitespaceBehavior(t *testing.T) {
 	git.Debug = true
 	for _, behavior := range []string{"-w", "--ignore-space-at-eol", "-b", ""} {
@@ -71,3 +71,186 @@ func ReviewRequest(issue *models.Issue, doer *models.User, reviewer *models.User
 
 	return nil
 }
+
+// TeamReviewRequest add or remove a review request from a team for this PR, and make comment for it.
+func TeamReviewRequest(issue *models.Issue, doer *models.User, reviewer *models.Team, isAdd bool) (err error) {
+	var comment *models.Comment
+	if isAdd {
+		comment, err = models.AddTeamReviewRequest(issue, reviewer, doer)
+	} else {
+		comment, err = models.RemoveTeamReviewRequest(issue, reviewer, doer)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if comment == nil || !isAdd {
+		return nil
+	}
+
+	// notify all members of the team
+	members, err := models.GetTeamMembers(reviewer.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if member.ID == doer.ID || member.ID == issue.PosterID {
+			continue
+		}
+		notification.NotifyPullReviewRequest(doer, issue, member, isAdd, comment)
+	}
+
+	return nil
+}
+
+// IsLegalReviewRequest check permission for ReviewRequest
+func IsLegalReviewRequest(reviewer, doer *models.User, isAdd bool, issue *models.Issue) error {
+	if reviewer.IsOrganization() {
+		return models.ErrNotValidReviewRequest{
+			Reason: "Organization can't be added as reviewer",
+			UserID: doer.ID,
+			RepoID: issue.Repo.ID,
+		}
+	}
+	if doer.IsOrganization() {
+		return models.ErrNotValidReviewRequest{
+			Reason: "Organization can't be doer to add reviewer",
+			UserID: doer.ID,
+			RepoID: issue.Repo.ID,
+		}
+	}
+
+	permReviewer, err := models.GetUserRepoPermission(issue.Repo, reviewer)
+	if err != nil {
+		return err
+	}
+
+	permDoer, err := models.GetUserRepoPermission(issue.Repo, doer)
+	if err != nil {
+		return err
+	}
+
+	lastreview, err := models.GetReviewerByIssueIDAndUserID(issue.ID, reviewer.ID)
+	if err != nil {
+		return err
+	}
+
+	var pemResult bool
+	if isAdd {
+		pemResult = permReviewer.CanAccessAny(models.AccessModeRead, models.UnitTypePullRequests)
+		if !pemResult {
+			return models.ErrNotValidReviewRequest{
+				Reason: "Reviewer can't read",
+				UserID: doer.ID,
+				RepoID: issue.Repo.ID,
+			}
+		}
+
+		if doer.ID == issue.PosterID && lastreview != nil && lastreview.Type != models.ReviewTypeRequest {
+			return nil
+		}
+
+		pemResult = permDoer.CanAccessAny(models.AccessModeWrite, models.UnitTypePullRequests)
+		if !pemResult {
+			pemResult, err = models.IsOfficialReviewer(issue, doer)
+			if err != nil {
+				return err
+			}
+			if !pemResult {
+				return models.ErrNotValidReviewRequest{
+					Reason: "Doer can't choose reviewer",
+					UserID: doer.ID,
+					RepoID: issue.Repo.ID,
+				}
+			}
+		}
+
+		if doer.ID == reviewer.ID {
+			return models.ErrNotValidReviewRequest{
+				Reason: "doer can't be reviewer",
+				UserID: doer.ID,
+				RepoID: issue.Repo.ID,
+			}
+		}
+
+		if reviewer.ID == issue.PosterID {
+			return models.ErrNotValidReviewRequest{
+				Reason: "poster of pr can't be reviewer",
+				UserID: doer.ID,
+				RepoID: issue.Repo.ID,
+			}
+		}
+	} else {
+		if lastreview.Type == models.ReviewTypeRequest && lastreview.ReviewerID == doer.ID {
+			return nil
+		}
+
+		pemResult = permDoer.IsAdmin()
+		if !pemResult {
+			return models.ErrNotValidReviewRequest{
+				Reason: "Doer is not admin",
+				UserID: doer.ID,
+				RepoID: issue.Repo.ID,
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsLegalTeamReviewRequest check permission for ReviewRequest Team
+func IsLegalTeamReviewRequest(reviewer *models.Team, doer *models.User, isAdd bool, issue *models.Issue) error {
+	if doer.IsOrganization() {
+		return models.ErrNotValidReviewRequest{
+			Reason: "Organization can't be doer to add reviewer",
+			UserID: doer.ID,
+			RepoID: issue.Repo.ID,
+		}
+	}
+	if reviewer.OrgID != issue.Repo.OwnerID {
+		return models.ErrNotValidReviewRequest{
+			Reason: "Reviewer team doesn't belong to the repository owner",
+			UserID: doer.ID,
+			RepoID: issue.Repo.ID,
+		}
+	}
+
+	permDoer, err := models.GetUserRepoPermission(issue.Repo, doer)
+	if err != nil {
+		return err
+	}
+
+	if isAdd {
+		if !reviewer.HasRepository(issue.Repo.ID) || !reviewer.UnitEnabled(models.UnitTypePullRequests) {
+			return models.ErrNotValidReviewRequest{
+				Reason: "Reviewer team can't read",
+				UserID: doer.ID,
+				RepoID: issue.Repo.ID,
+			}
+		}
+
+		if !permDoer.CanAccessAny(models.AccessModeWrite, models.UnitTypePullRequests) {
+			official, err := models.IsOfficialReviewer(issue, doer)
+			if err != nil {
+				return err
+			}
+			if !official {
+				return models.ErrNotValidReviewRequest{
+					Reason: "Doer can't choose reviewer",
+					UserID: doer.ID,
+					RepoID: issue.Repo.ID,
+				}
+			}
+		}
+	} else if !permDoer.IsAdmin() {
+		return models.ErrNotValidReviewRequest{
+			Reason: "Doer is not admin",
+			UserID: doer.ID,
+			RepoID: issue.Repo.ID,
+		}
+	}
+
+	return nil
+}
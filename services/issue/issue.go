@@ -13,6 +13,10 @@ import (
 
 // NewIssue creates new issue with labels for repository.
 func NewIssue(repo *models.Repository, issue *models.Issue, labelIDs []int64, uuids []string, assigneeIDs []int64) error {
+	if err := models.CheckUserBlockedBy(issue.PosterID, repo.OwnerID); err != nil {
+		return err
+	}
+
 	if err := models.NewIssue(repo, issue, labelIDs, uuids); err != nil {
 		return err
 	}
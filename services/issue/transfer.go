@@ -0,0 +1,47 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// TransferIssue moves an issue to another repository the doer can write issues to.
+func TransferIssue(doer *models.User, issue *models.Issue, newRepo *models.Repository) error {
+	if err := issue.LoadRepo(); err != nil {
+		return err
+	}
+	oldRepo := issue.Repo
+	oldIndex := issue.Index
+
+	if newRepo.IsArchived {
+		return models.ErrCannotTransferIssue{IssueID: issue.ID, RepoID: newRepo.ID, Reason: "repository is archived"}
+	}
+	if !newRepo.UnitEnabled(models.UnitTypeIssues) {
+		return models.ErrCannotTransferIssue{IssueID: issue.ID, RepoID: newRepo.ID, Reason: "repository has no issue tracker"}
+	}
+
+	for _, repo := range []*models.Repository{oldRepo, newRepo} {
+		perm, err := models.GetUserRepoPermission(repo, doer)
+		if err != nil {
+			return err
+		}
+		if !perm.CanWrite(models.UnitTypeIssues) {
+			return models.ErrUserDoesNotHaveAccessToRepo{UserID: doer.ID, RepoName: repo.Name}
+		}
+	}
+
+	if err := models.CheckUserBlockedBy(doer.ID, newRepo.OwnerID, issue.PosterID); err != nil {
+		return err
+	}
+
+	if err := models.TransferIssue(issue, doer, newRepo); err != nil {
+		return err
+	}
+
+	notification.NotifyIssueTransfer(doer, issue, oldRepo, oldIndex)
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferIssue(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	issue := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 1}).(*models.Issue)
+	newRepo := models.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+
+	// user4 cannot write to either repository
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	err := TransferIssue(doer, issue, newRepo)
+	assert.True(t, models.IsErrUserDoesNotHaveAccessToRepo(err))
+
+	doer = models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	assert.NoError(t, TransferIssue(doer, issue, newRepo))
+	models.AssertExistsAndLoadBean(t, &models.Issue{ID: issue.ID, RepoID: newRepo.ID, Index: 2})
+
+	// pull requests stay where they are
+	pull := models.AssertExistsAndLoadBean(t, &models.Issue{ID: 2}).(*models.Issue)
+	err = TransferIssue(doer, pull, newRepo)
+	assert.True(t, models.IsErrCannotTransferIssue(err))
+}
@@ -338,7 +338,7 @@ func Password(m *models.Mirror) string {
 	return password
 }
 
-// Update checks and updates mirror repositories.
+// Update checks and updates mirror repositories and push mirrors.
 func Update(ctx context.Context) error {
 	log.Trace("Doing: Update")
 	if err := models.MirrorsIterate(func(idx int, bean interface{}) error {
@@ -358,11 +358,28 @@ func Update(ctx context.Context) error {
 		log.Trace("Update: %v", err)
 		return err
 	}
+	if err := models.PushMirrorsIterate(func(idx int, bean interface{}) error {
+		m := bean.(*models.PushMirror)
+		if m.Repo == nil {
+			log.Error("Disconnected push mirror found: %d", m.ID)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted")
+		default:
+			pushMirrorQueue.Add(m.ID)
+			return nil
+		}
+	}); err != nil {
+		log.Trace("Update: %v", err)
+		return err
+	}
 	log.Trace("Finished: Update")
 	return nil
 }
 
-// SyncMirrors checks and syncs mirrors.
+// SyncMirrors checks and syncs mirrors and push mirrors.
 // FIXME: graceful: this should be a persistable queue
 func SyncMirrors(ctx context.Context) {
 	// Start listening on new sync requests.
@@ -370,9 +387,12 @@ func SyncMirrors(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			mirrorQueue.Close()
+			pushMirrorQueue.Close()
 			return
 		case repoID := <-mirrorQueue.Queue():
 			syncMirror(repoID)
+		case mirrorID := <-pushMirrorQueue.Queue():
+			syncPushMirror(mirrorID)
 		}
 	}
 }
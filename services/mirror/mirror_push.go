@@ -0,0 +1,172 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/unknwon/com"
+)
+
+// pushMirrorQueue holds an UniqueQueue object of the push mirrors
+var pushMirrorQueue = sync.NewUniqueQueue(setting.Repository.MirrorQueueLength)
+
+var (
+	// ErrInvalidPushMirrorAddress is returned when a push mirror address is not acceptable
+	ErrInvalidPushMirrorAddress = errors.New("invalid push mirror address")
+	// ErrInvalidPushMirrorInterval is returned when a push mirror interval is below the minimum
+	ErrInvalidPushMirrorInterval = errors.New("invalid push mirror interval")
+)
+
+// pushMirrorRefSpecs are the references pushed to a push mirror.
+// Internal references like refs/pull/* are deliberately not pushed.
+var pushMirrorRefSpecs = []string{
+	"+" + git.BranchPrefix + "*:" + git.BranchPrefix + "*",
+	"+" + git.TagPrefix + "*:" + git.TagPrefix + "*",
+}
+
+// ValidatePushMirrorAddress checks the given address and returns it with the credentials applied
+func ValidatePushMirrorAddress(address, username, password string) (string, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return "", ErrInvalidPushMirrorAddress
+	}
+	if u.Opaque != "" || !(u.Scheme == "http" || u.Scheme == "https") {
+		return "", ErrInvalidPushMirrorAddress
+	}
+	if u.EscapedPath() == "" || u.Host == "" || !u.IsAbs() {
+		return "", ErrInvalidPushMirrorAddress
+	}
+	if username != "" || password != "" {
+		u.User = url.UserPassword(username, password)
+	}
+	return u.String(), nil
+}
+
+// AddPushMirror creates a new push mirror for the repository
+func AddPushMirror(repo *models.Repository, address string, interval time.Duration) (*models.PushMirror, error) {
+	if interval != 0 && interval < setting.Mirror.MinInterval {
+		return nil, ErrInvalidPushMirrorInterval
+	}
+	m := &models.PushMirror{
+		RepoID:   repo.ID,
+		Repo:     repo,
+		Interval: interval,
+	}
+	if err := m.SetRemoteAddress(address); err != nil {
+		return nil, err
+	}
+	if err := models.InsertPushMirror(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AddPushMirrorToQueue adds the push mirror to the sync queue
+func AddPushMirrorToQueue(mirrorID int64) {
+	go pushMirrorQueue.Add(mirrorID)
+}
+
+// StartPushMirrorSync queues all push mirrors of the repository to be synchronized
+func StartPushMirrorSync(repoID int64) {
+	mirrors, err := models.GetPushMirrorsByRepoID(repoID)
+	if err != nil {
+		log.Error("GetPushMirrorsByRepoID [%d]: %v", repoID, err)
+		return
+	}
+	for _, m := range mirrors {
+		AddPushMirrorToQueue(m.ID)
+	}
+}
+
+func syncPushMirror(mirrorID string) {
+	log.Trace("SyncPushMirror [mirror_id: %v]", mirrorID)
+	defer func() {
+		err := recover()
+		if err == nil {
+			return
+		}
+		// There was a panic whilst syncPushMirror...
+		log.Error("PANIC whilst syncPushMirror[%s] Panic: %v\nStacktrace: %s", mirrorID, err, log.Stack(2))
+	}()
+	pushMirrorQueue.Remove(mirrorID)
+
+	m, err := models.GetPushMirrorByID(com.StrTo(mirrorID).MustInt64())
+	if err != nil {
+		log.Error("GetPushMirrorByID [%s]: %v", mirrorID, err)
+		return
+	}
+	if m.Repo == nil {
+		log.Error("Disconnected push mirror found: %d", m.ID)
+		return
+	}
+
+	m.LastError = ""
+	if err := runPushSync(m); err != nil {
+		log.Error("SyncPushMirror [mirror: %d][repo: %-v]: %v", m.ID, m.Repo, err)
+		m.LastError = err.Error()
+	}
+	m.LastUpdateUnix = timeutil.TimeStampNow()
+	m.ScheduleNextUpdate()
+
+	if err := models.UpdatePushMirror(m); err != nil {
+		log.Error("UpdatePushMirror [%d]: %v", m.ID, err)
+		return
+	}
+
+	log.Trace("SyncPushMirror [mirror: %d][repo: %-v]: Finished", m.ID, m.Repo)
+}
+
+func runPushSync(m *models.PushMirror) error {
+	timeout := time.Duration(setting.Git.Timeout.Mirror) * time.Second
+
+	address, err := m.DecryptRemoteAddress()
+	if err != nil {
+		return fmt.Errorf("unable to decrypt remote address: %v", err)
+	}
+
+	performPush := func(path, address string) error {
+		log.Trace("Pushing %s mirror[%d] remote %s", path, m.ID, m.RemoteAddress)
+
+		args := append([]string{"push", "--force", "--prune", address}, pushMirrorRefSpecs...)
+		stderrBuilder := strings.Builder{}
+		if err := git.NewCommand(args...).
+			SetDescription(fmt.Sprintf("PushMirror.runPushSync: %s", m.Repo.FullName())).
+			RunInDirTimeoutPipeline(timeout, path, nil, &stderrBuilder); err != nil {
+			// sanitize the output, since it may contain the remote address, which may
+			// contain a password
+			message := fmt.Sprintf("%v - %s", err, strings.TrimSpace(stderrBuilder.String()))
+			return errors.New(util.SanitizeMessage(message, address))
+		}
+		return nil
+	}
+
+	if err := performPush(m.Repo.RepoPath(), address); err != nil {
+		return err
+	}
+
+	if m.Repo.HasWiki() {
+		if wikiAddress := repo_module.WikiRemoteURL(address); wikiAddress != "" {
+			if err := performPush(m.Repo.WikiPath(), wikiAddress); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// TokenExpiration is the lifetime of the tokens issued by the registry
+const TokenExpiration = 24 * time.Hour
+
+// claims are the claims of a registry token. A UserID of zero represents an anonymous user.
+// The Scope is inherited from the access token the registry token was issued for.
+type claims struct {
+	jwt.StandardClaims
+	UserID int64
+	Scope  models.AccessTokenScope
+}
+
+func signingKey() []byte {
+	key := sha256.Sum256([]byte("container registry" + setting.SecretKey))
+	return key[:]
+}
+
+// CreateAuthorizationToken returns a signed registry token with the scope for the user which may be nil
+func CreateAuthorizationToken(u *models.User, scope models.AccessTokenScope) (string, time.Time, error) {
+	now := time.Now()
+	c := claims{
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(TokenExpiration).Unix(),
+			NotBefore: now.Unix(),
+		},
+		Scope: scope,
+	}
+	if u != nil {
+		c.UserID = u.ID
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(signingKey())
+	return token, now, err
+}
+
+// ParseAuthorizationToken validates the registry token and returns the ID of the user and the scope
+// it was issued for
+func ParseAuthorizationToken(tokenString string) (int64, models.AccessTokenScope, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &claims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return signingKey(), nil
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	c, ok := token.Claims.(*claims)
+	if !token.Valid || !ok {
+		return 0, "", fmt.Errorf("invalid token claim")
+	}
+	return c.UserID, c.Scope, nil
+}
@@ -0,0 +1,528 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// Supported manifest media types
+const (
+	MediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	MediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	MediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	MediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// maxManifestSize is the maximum size of a manifest accepted by the registry
+const maxManifestSize = 10 * 1024 * 1024
+
+var (
+	// ErrNameInvalid is returned when the image name is not valid
+	ErrNameInvalid = errors.New("invalid image name")
+	// ErrTagInvalid is returned when the tag is not valid
+	ErrTagInvalid = errors.New("invalid tag")
+	// ErrDigestInvalid is returned when a digest is malformed or does not match the content
+	ErrDigestInvalid = errors.New("provided digest did not match uploaded content")
+	// ErrManifestInvalid is returned when a manifest can not be parsed or has an unsupported media type
+	ErrManifestInvalid = errors.New("manifest invalid")
+	// ErrManifestBlobUnknown is returned when a manifest references a blob which is not part of the image
+	ErrManifestBlobUnknown = errors.New("blob unknown to registry")
+	// ErrRangeInvalid is returned when a chunk does not continue the upload
+	ErrRangeInvalid = errors.New("invalid content range")
+)
+
+var (
+	imageNameRegex = regexp.MustCompile(`\A[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*\z`)
+	tagRegex       = regexp.MustCompile(`\A[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}\z`)
+	digestRegex    = regexp.MustCompile(`\Asha256:[a-f0-9]{64}\z`)
+)
+
+// IsValidImageName checks if the name can be used as image name
+func IsValidImageName(name string) bool {
+	return imageNameRegex.MatchString(name)
+}
+
+// IsDigest checks if the reference is a valid digest
+func IsDigest(reference string) bool {
+	return digestRegex.MatchString(reference)
+}
+
+// IsValidTag checks if the reference is a valid tag
+func IsValidTag(reference string) bool {
+	return tagRegex.MatchString(reference) && strings.ToLower(reference) != models.ContainerUploadVersion
+}
+
+// IsManifestListMediaType returns true if the media type describes a list of manifests
+func IsManifestListMediaType(mediaType string) bool {
+	return mediaType == MediaTypeOCIIndex || mediaType == MediaTypeDockerManifestList
+}
+
+func isSupportedManifestMediaType(mediaType string) bool {
+	switch mediaType {
+	case MediaTypeOCIManifest, MediaTypeOCIIndex, MediaTypeDockerManifest, MediaTypeDockerManifestList:
+		return true
+	}
+	return false
+}
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+type manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType"`
+	Config        *descriptor  `json:"config"`
+	Layers        []descriptor `json:"layers"`
+	Manifests     []descriptor `json:"manifests"`
+}
+
+func digestFromHash(hashSHA256 string) string {
+	return models.ContainerDigestPrefix + hashSHA256
+}
+
+// ImageInfo describes the image a request refers to
+type ImageInfo struct {
+	Owner   *models.User
+	Creator *models.User
+	Name    string
+}
+
+func (info *ImageInfo) getOrInsertVersion(version string) (*models.PackageVersion, error) {
+	return models.GetOrInsertPackageVersion(info.Owner.ID, models.PackageContainer, info.Name, version, info.Creator.ID)
+}
+
+// addBlobToUploads registers the blob as uploaded to the image
+func (info *ImageInfo) addBlobToUploads(pb *models.PackageBlob) error {
+	pv, err := info.getOrInsertVersion(models.ContainerUploadVersion)
+	if err != nil {
+		return err
+	}
+	err = models.InsertPackageFile(&models.PackageFile{
+		VersionID: pv.ID,
+		BlobID:    pb.ID,
+		Name:      digestFromHash(pb.HashSHA256),
+	})
+	if models.IsErrPackageFileAlreadyExist(err) {
+		return nil
+	}
+	return err
+}
+
+// saveBlobWithDigest stores the content and verifies that it matches the digest
+func saveBlobWithDigest(digest string, r io.Reader) (*models.PackageBlob, error) {
+	pb, exists, err := packages_service.SaveBlob(r)
+	if err != nil {
+		return nil, err
+	}
+	if digestFromHash(pb.HashSHA256) != digest {
+		if !exists {
+			packages_service.RemoveBlobIfUnreferenced(pb)
+		}
+		return nil, ErrDigestInvalid
+	}
+	return pb, nil
+}
+
+// UploadBlob stores the content as blob of the image in a single request
+func UploadBlob(info *ImageInfo, digest string, r io.Reader) error {
+	if !IsDigest(digest) {
+		return ErrDigestInvalid
+	}
+	pb, err := saveBlobWithDigest(digest, r)
+	if err != nil {
+		return err
+	}
+	if err := info.addBlobToUploads(pb); err != nil {
+		packages_service.RemoveBlobIfUnreferenced(pb)
+		return err
+	}
+	return nil
+}
+
+// MountBlob makes a blob of another image available in the image without uploading it again
+func MountBlob(info *ImageInfo, digest string, from *models.Package) (bool, error) {
+	if !IsDigest(digest) {
+		return false, nil
+	}
+	pf, err := models.GetContainerBlobFile(from.ID, digest)
+	if err != nil {
+		if models.IsErrPackageFileNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, info.addBlobToUploads(pf.Blob)
+}
+
+func uploadChunkPath(pbu *models.PackageBlobUpload, n int) string {
+	return fmt.Sprintf("uploads/%s-%d", pbu.ID, n)
+}
+
+// StartUpload creates a new chunked upload for the image
+func StartUpload(info *ImageInfo) (*models.PackageBlobUpload, error) {
+	return models.NewPackageBlobUpload(info.Owner.ID, info.Name)
+}
+
+// AppendUpload adds the content as next chunk to the upload. If rangeStart is not negative
+// it must match the number of bytes received so far.
+func AppendUpload(pbu *models.PackageBlobUpload, rangeStart int64, r io.Reader) error {
+	if rangeStart >= 0 && rangeStart != pbu.BytesReceived {
+		return ErrRangeInvalid
+	}
+	if setting.Packages.MaxFileSize > 0 {
+		r = io.LimitReader(r, setting.Packages.MaxFileSize-pbu.BytesReceived+1)
+	}
+
+	size, err := storage.Packages.Save(uploadChunkPath(pbu, pbu.NumChunks), r)
+	if err != nil {
+		return err
+	}
+	pbu.NumChunks++
+	pbu.BytesReceived += size
+	if err := models.UpdatePackageBlobUpload(pbu); err != nil {
+		return err
+	}
+	if setting.Packages.MaxFileSize > 0 && pbu.BytesReceived > setting.Packages.MaxFileSize {
+		CancelUpload(pbu)
+		return packages_service.ErrFileTooLarge
+	}
+	return nil
+}
+
+// FinishUpload completes the upload with an optional last chunk and stores the blob
+func FinishUpload(info *ImageInfo, pbu *models.PackageBlobUpload, digest string, r io.Reader) error {
+	if !IsDigest(digest) {
+		return ErrDigestInvalid
+	}
+	if r != nil {
+		if err := AppendUpload(pbu, -1, r); err != nil {
+			return err
+		}
+	}
+
+	readers := make([]io.Reader, 0, pbu.NumChunks)
+	for i := 0; i < pbu.NumChunks; i++ {
+		chunk, err := storage.Packages.Open(uploadChunkPath(pbu, i))
+		if err != nil {
+			return err
+		}
+		defer chunk.Close()
+		readers = append(readers, chunk)
+	}
+
+	pb, err := saveBlobWithDigest(digest, io.MultiReader(readers...))
+	if err != nil {
+		return err
+	}
+	if err := info.addBlobToUploads(pb); err != nil {
+		packages_service.RemoveBlobIfUnreferenced(pb)
+		return err
+	}
+
+	CancelUpload(pbu)
+	return nil
+}
+
+// CancelUpload removes the upload with all received chunks
+func CancelUpload(pbu *models.PackageBlobUpload) {
+	for i := 0; i < pbu.NumChunks; i++ {
+		if err := storage.Packages.Delete(uploadChunkPath(pbu, i)); err != nil {
+			log.Error("Unable to remove upload chunk %s: %v", uploadChunkPath(pbu, i), err)
+		}
+	}
+	if err := models.DeletePackageBlobUpload(pbu); err != nil {
+		log.Error("DeletePackageBlobUpload [%s]: %v", pbu.ID, err)
+	}
+}
+
+// DeleteBlob removes an uploaded blob from the image. Blobs which are referenced by
+// manifests of the image stay available.
+func DeleteBlob(p *models.Package, digest string) error {
+	pv, err := models.GetPackageVersionByName(p.OwnerID, p.Type, p.Name, models.ContainerUploadVersion)
+	if err != nil {
+		if models.IsErrPackageVersionNotExist(err) {
+			return models.ErrPackageFileNotExist{Name: digest}
+		}
+		return err
+	}
+	pf, err := models.GetPackageFileByName(pv.ID, digest)
+	if err != nil {
+		return err
+	}
+	return packages_service.RemovePackageFile(pv, pf)
+}
+
+// GetManifest returns the manifest file of the image for the tag or digest
+func GetManifest(p *models.Package, reference string) (*models.PackageVersion, *models.PackageFile, error) {
+	if IsDigest(reference) {
+		pf, err := models.GetContainerManifestFile(p.ID, reference)
+		if err != nil {
+			return nil, nil, err
+		}
+		pv, err := models.GetPackageVersionByID(pf.VersionID)
+		return pv, pf, err
+	}
+
+	pv, err := models.GetPackageVersionByName(p.OwnerID, p.Type, p.Name, reference)
+	if err != nil {
+		return nil, nil, err
+	}
+	pf, err := models.GetLeadPackageFile(pv.ID)
+	return pv, pf, err
+}
+
+// PushManifest stores the manifest for the image and tags it if the reference is a tag.
+// It returns the digest of the manifest.
+func PushManifest(info *ImageInfo, reference, contentType string, r io.Reader) (string, error) {
+	if !IsDigest(reference) && !IsValidTag(reference) {
+		return "", ErrTagInvalid
+	}
+
+	buf, err := ioutil.ReadAll(io.LimitReader(r, maxManifestSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(buf) > maxManifestSize {
+		return "", ErrManifestInvalid
+	}
+	hash := sha256.Sum256(buf)
+	digest := digestFromHash(hex.EncodeToString(hash[:]))
+	if IsDigest(reference) && reference != digest {
+		return "", ErrDigestInvalid
+	}
+
+	var m manifest
+	if err := json.Unmarshal(buf, &m); err != nil || m.SchemaVersion != 2 {
+		return "", ErrManifestInvalid
+	}
+	mediaType := m.MediaType
+	if contentType != "" {
+		mediaType = contentType
+	}
+	if mediaType == "" {
+		if m.Manifests != nil {
+			mediaType = MediaTypeOCIIndex
+		} else {
+			mediaType = MediaTypeOCIManifest
+		}
+	}
+	if !isSupportedManifestMediaType(mediaType) {
+		return "", ErrManifestInvalid
+	}
+
+	// Collect the files referenced by the manifest, they must be part of the image already
+	var references []*models.PackageFile
+	p, err := models.GetPackageByName(info.Owner.ID, models.PackageContainer, info.Name)
+	if err != nil && !models.IsErrPackageNotExist(err) {
+		return "", err
+	}
+	if IsManifestListMediaType(mediaType) {
+		for _, d := range m.Manifests {
+			if p == nil {
+				return "", ErrManifestBlobUnknown
+			}
+			pf, err := models.GetContainerManifestFile(p.ID, d.Digest)
+			if err != nil {
+				if models.IsErrPackageFileNotExist(err) {
+					return "", ErrManifestBlobUnknown
+				}
+				return "", err
+			}
+			references = append(references, pf)
+		}
+	} else {
+		if m.Config == nil {
+			return "", ErrManifestInvalid
+		}
+		for _, d := range append([]descriptor{*m.Config}, m.Layers...) {
+			if p == nil {
+				return "", ErrManifestBlobUnknown
+			}
+			pf, err := models.GetContainerBlobFile(p.ID, d.Digest)
+			if err != nil {
+				if models.IsErrPackageFileNotExist(err) {
+					return "", ErrManifestBlobUnknown
+				}
+				return "", err
+			}
+			references = append(references, pf)
+		}
+	}
+
+	pb, err := saveBlobWithDigest(digest, bytes.NewReader(buf))
+	if err != nil {
+		return "", err
+	}
+
+	if !IsDigest(reference) {
+		if err := untagManifest(info, reference, digest); err != nil {
+			packages_service.RemoveBlobIfUnreferenced(pb)
+			return "", err
+		}
+	}
+
+	pv, err := info.getOrInsertVersion(reference)
+	if err != nil {
+		packages_service.RemoveBlobIfUnreferenced(pb)
+		return "", err
+	}
+	if lead, err := models.GetLeadPackageFile(pv.ID); err == nil && lead.LowerName == digest {
+		// The manifest was pushed before
+		return digest, nil
+	} else if err != nil && !models.IsErrPackageFileNotExist(err) {
+		return "", err
+	}
+
+	if err := models.InsertPackageFile(&models.PackageFile{
+		VersionID:   pv.ID,
+		BlobID:      pb.ID,
+		Name:        digest,
+		ContentType: mediaType,
+		IsLead:      true,
+	}); err != nil {
+		return "", err
+	}
+	for _, ref := range references {
+		err := models.InsertPackageFile(&models.PackageFile{
+			VersionID:   pv.ID,
+			BlobID:      ref.BlobID,
+			Name:        ref.Name,
+			ContentType: ref.ContentType,
+		})
+		if err != nil && !models.IsErrPackageFileAlreadyExist(err) {
+			return "", err
+		}
+	}
+	return digest, nil
+}
+
+// untagManifest moves the manifest currently tagged with the tag to an untagged version,
+// unless it is the manifest with the given digest
+func untagManifest(info *ImageInfo, tag, digest string) error {
+	pv, err := models.GetPackageVersionByName(info.Owner.ID, models.PackageContainer, info.Name, tag)
+	if err != nil {
+		if models.IsErrPackageNotExist(err) || models.IsErrPackageVersionNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	lead, err := models.GetLeadPackageFile(pv.ID)
+	if err != nil {
+		if models.IsErrPackageFileNotExist(err) {
+			return packages_service.RemovePackageVersion(pv)
+		}
+		return err
+	}
+	if lead.LowerName == digest {
+		return nil
+	}
+
+	// Keep the old manifest pullable by its digest until it is cleaned up
+	if _, err := models.GetPackageVersionByName(info.Owner.ID, models.PackageContainer, info.Name, lead.Name); err == nil {
+		return packages_service.RemovePackageVersion(pv)
+	} else if !models.IsErrPackageVersionNotExist(err) {
+		return err
+	}
+	return models.RenamePackageVersion(pv, lead.Name)
+}
+
+// DeleteManifest removes the tag or all versions of the manifest with the digest
+func DeleteManifest(p *models.Package, reference string) error {
+	var versions []*models.PackageVersion
+	if IsDigest(reference) {
+		var err error
+		versions, err = models.GetContainerVersionsByManifestDigest(p.ID, reference)
+		if err != nil {
+			return err
+		}
+	} else {
+		pv, err := models.GetPackageVersionByName(p.OwnerID, p.Type, p.Name, reference)
+		if err != nil {
+			return err
+		}
+		versions = append(versions, pv)
+	}
+	if len(versions) == 0 {
+		return models.ErrPackageVersionNotExist{PackageID: p.ID, Version: reference}
+	}
+
+	for _, pv := range versions {
+		if err := packages_service.RemovePackageVersion(pv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cleanup removes untagged manifests which are not referenced by a manifest list, uploaded
+// blobs which were not used by a manifest and abandoned uploads older than the given duration
+func Cleanup(ctx context.Context, olderThan time.Duration) error {
+	before := timeutil.TimeStampNow().AddDuration(-olderThan)
+
+	versions, err := models.FindUnreferencedUntaggedContainerVersions(before)
+	if err != nil {
+		return err
+	}
+	for _, pv := range versions {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("while cleaning up untagged manifests")
+		default:
+		}
+		if err := packages_service.RemovePackageVersion(pv); err != nil {
+			return err
+		}
+	}
+
+	files, err := models.FindContainerUploadFilesCreatedBefore(before)
+	if err != nil {
+		return err
+	}
+	for _, pf := range files {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("while cleaning up uploaded blobs")
+		default:
+		}
+		pv, err := models.GetPackageVersionByID(pf.VersionID)
+		if err != nil {
+			return err
+		}
+		if err := packages_service.RemovePackageFile(pv, pf); err != nil {
+			return err
+		}
+	}
+
+	uploads, err := models.FindPackageBlobUploadsUpdatedBefore(before)
+	if err != nil {
+		return err
+	}
+	for _, pbu := range uploads {
+		CancelUpload(pbu)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package packages
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+// ErrFileTooLarge is returned when an uploaded file exceeds the configured maximum size
+var ErrFileTooLarge = errors.New("package file is too large")
+
+// SaveBlob stores the content of the reader as a blob. If a blob with identical content
+// exists already it is returned instead. The returned bool reports whether the blob existed.
+func SaveBlob(r io.Reader) (*models.PackageBlob, bool, error) {
+	tmp, err := ioutil.TempFile("", "gitea-package")
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		_ = tmp.Close()
+		if err := os.Remove(tmp.Name()); err != nil {
+			log.Error("Unable to remove temporary package file %s: %v", tmp.Name(), err)
+		}
+	}()
+
+	if setting.Packages.MaxFileSize > 0 {
+		r = io.LimitReader(r, setting.Packages.MaxFileSize+1)
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(tmp, io.TeeReader(r, h))
+	if err != nil {
+		return nil, false, err
+	}
+	if setting.Packages.MaxFileSize > 0 && size > setting.Packages.MaxFileSize {
+		return nil, false, ErrFileTooLarge
+	}
+
+	pb, exists, err := models.GetOrInsertPackageBlob(&models.PackageBlob{
+		Size:       size,
+		HashSHA256: hex.EncodeToString(h.Sum(nil)),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if exists {
+		return pb, true, nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, false, err
+	}
+	if _, err := storage.Packages.Save(pb.RelativePath(), tmp); err != nil {
+		if _, err := models.DeletePackageBlobIfUnreferenced(pb); err != nil {
+			log.Error("DeletePackageBlobIfUnreferenced [%d]: %v", pb.ID, err)
+		}
+		return nil, false, fmt.Errorf("Save: %v", err)
+	}
+	return pb, false, nil
+}
+
+// PackageInfo describes the package a file is added to
+type PackageInfo struct {
+	Owner   *models.User
+	Creator *models.User
+	Type    models.PackageType
+	Name    string
+	Version string
+}
+
+// AddFileToPackage stores the content of the reader as a file of the package version.
+// The package and the version are created if they do not exist yet.
+func AddFileToPackage(info *PackageInfo, filename string, r io.Reader) (*models.PackageVersion, *models.PackageFile, error) {
+	pb, _, err := SaveBlob(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pv, err := models.GetOrInsertPackageVersion(info.Owner.ID, info.Type, info.Name, info.Version, info.Creator.ID)
+	if err != nil {
+		RemoveBlobIfUnreferenced(pb)
+		return nil, nil, err
+	}
+
+	pf := &models.PackageFile{
+		VersionID: pv.ID,
+		BlobID:    pb.ID,
+		Blob:      pb,
+		Name:      filename,
+	}
+	if err := models.InsertPackageFile(pf); err != nil {
+		RemoveBlobIfUnreferenced(pb)
+		return nil, nil, err
+	}
+	return pv, pf, nil
+}
+
+// OpenPackageFile opens the content of the file and increases the download counter of its version
+func OpenPackageFile(pv *models.PackageVersion, pf *models.PackageFile) (storage.Object, error) {
+	if err := pf.LoadBlob(); err != nil {
+		return nil, err
+	}
+	s, err := storage.Packages.Open(pf.Blob.RelativePath())
+	if err != nil {
+		return nil, err
+	}
+	if err := pv.IncreaseDownloadCount(); err != nil {
+		log.Error("IncreaseDownloadCount [%d]: %v", pv.ID, err)
+	}
+	return s, nil
+}
+
+// RemovePackageVersion deletes the version with all its files
+func RemovePackageVersion(pv *models.PackageVersion) error {
+	blobs, err := models.DeletePackageVersion(pv)
+	if err != nil {
+		return err
+	}
+	removeBlobContents(blobs)
+	return nil
+}
+
+// RemovePackageFile deletes the file from its version
+func RemovePackageFile(pv *models.PackageVersion, pf *models.PackageFile) error {
+	blobs, err := models.DeletePackageFile(pv, pf)
+	if err != nil {
+		return err
+	}
+	removeBlobContents(blobs)
+	return nil
+}
+
+// RemoveBlobIfUnreferenced deletes the blob and its content if no package file references it
+func RemoveBlobIfUnreferenced(pb *models.PackageBlob) {
+	deleted, err := models.DeletePackageBlobIfUnreferenced(pb)
+	if err != nil {
+		log.Error("DeletePackageBlobIfUnreferenced [%d]: %v", pb.ID, err)
+		return
+	}
+	if deleted {
+		removeBlobContents([]*models.PackageBlob{pb})
+	}
+}
+
+func removeBlobContents(blobs []*models.PackageBlob) {
+	for _, pb := range blobs {
+		if err := storage.Packages.Delete(pb.RelativePath()); err != nil {
+			log.Error("Unable to remove package blob %s: %v", pb.RelativePath(), err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+
+	"github.com/unknwon/com"
+)
+
+// autoMergeQueue represents a queue to merge pull requests which are scheduled to be merged automatically
+var autoMergeQueue queue.UniqueQueue
+
+// ScheduleAutoMerge schedules the pull request to be merged by the doer once all required status checks
+// and approvals pass. It returns false if the pull request is ready to be merged right away.
+func ScheduleAutoMerge(doer *models.User, pr *models.PullRequest, style models.MergeStyle, message string) (bool, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return false, err
+	}
+	prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return false, err
+	}
+	if !prUnit.PullRequestsConfig().IsMergeStyleAllowed(style) {
+		return false, models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: style}
+	}
+
+	ready, err := isReadyToAutoMerge(pr)
+	if err != nil || ready {
+		return false, err
+	}
+	if err := models.ScheduleAutoMerge(doer, pr, style, message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isReadyToAutoMerge checks if the pull request is mergeable and all required status checks
+// and approvals pass. Without required status checks all commit statuses of the head commit
+// have to succeed, a head commit without commit statuses has nothing to wait for.
+func isReadyToAutoMerge(pr *models.PullRequest) (bool, error) {
+	if err := pr.LoadIssue(); err != nil {
+		return false, err
+	}
+	if !pr.CanAutoMerge() || pr.IsWorkInProgress() {
+		return false, nil
+	}
+
+	if err := CheckPRReadyToMerge(pr); err != nil {
+		if models.IsErrNotAllowedToMerge(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.EnableStatusCheck {
+		state, err := getPullRequestCommitStatusState(pr, nil)
+		if err != nil {
+			return false, err
+		}
+		if state != "" && !state.IsSuccess() {
+			return false, nil
+		}
+	}
+
+	return models.IssueNoDependenciesLeft(pr.Issue)
+}
+
+// AddToAutoMergeQueue adds the pull request to the queue to be merged if it is scheduled
+func AddToAutoMergeQueue(pr *models.PullRequest) {
+	addToAutoMergeQueue(pr.ID)
+}
+
+// AddToAutoMergeQueueByRepo adds the pull requests with a scheduled merge to the queue
+// whose base or head repository is the repository, e.g. after a commit status was created
+func AddToAutoMergeQueueByRepo(repo *models.Repository) {
+	ids, err := models.GetScheduledAutoMergePullIDsByRepoID(repo.ID)
+	if err != nil {
+		log.Error("GetScheduledAutoMergePullIDsByRepoID[%d]: %v", repo.ID, err)
+		return
+	}
+	for _, id := range ids {
+		addToAutoMergeQueue(id)
+	}
+}
+
+func addToAutoMergeQueue(pullID int64) {
+	if err := autoMergeQueue.Push(strconv.FormatInt(pullID, 10)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding prID %d to the auto merge queue: %v", pullID, err)
+	}
+}
+
+// handleAutoMerge merges the passed pull requests if they are scheduled and ready
+func handleAutoMerge(data ...queue.Data) {
+	for _, datum := range data {
+		id := com.StrTo(datum.(string)).MustInt64()
+		if err := autoMerge(id); err != nil {
+			log.Error("autoMerge[%d]: %v", id, err)
+		}
+	}
+}
+
+func autoMerge(pullID int64) error {
+	scheduled, err := models.GetScheduledAutoMergeByPullID(pullID)
+	if err != nil {
+		if models.IsErrPullAutoMergeNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	pr, err := models.GetPullRequestByID(pullID)
+	if err != nil {
+		return err
+	}
+	if err = pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return models.DeleteScheduledAutoMerge(pr.ID)
+	}
+	if err = pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+
+	if err = scheduled.LoadDoer(); err != nil {
+		if models.IsErrUserNotExist(err) {
+			return models.DeleteScheduledAutoMerge(pr.ID)
+		}
+		return err
+	}
+	perm, err := models.GetUserRepoPermission(pr.BaseRepo, scheduled.Doer)
+	if err != nil {
+		return err
+	}
+	if allowed, err := IsUserAllowedToMerge(pr, perm, scheduled.Doer); err != nil {
+		return err
+	} else if !allowed {
+		log.Trace("Cancel auto merge of PR %d: %s is not allowed to merge anymore", pr.ID, scheduled.Doer.Name)
+		return models.UnscheduleAutoMerge(scheduled.Doer, pr)
+	}
+
+	if ready, err := isReadyToAutoMerge(pr); err != nil || !ready {
+		return err
+	}
+
+	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer baseGitRepo.Close()
+
+	if err = Merge(pr, scheduled.Doer, baseGitRepo, scheduled.MergeStyle, scheduled.Message); err != nil {
+		return err
+	}
+	log.Trace("Pull request merged automatically: %d", pr.ID)
+	return models.DeleteScheduledAutoMerge(pr.ID)
+}
+
+func initAutoMergeQueue() error {
+	autoMergeQueue = queue.CreateUniqueQueue("pr_auto_merge", handleAutoMerge, "").(queue.UniqueQueue)
+	if autoMergeQueue == nil {
+		return fmt.Errorf("Unable to create pr_auto_merge Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(autoMergeQueue.Run)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadyToAutoMerge(t *testing.T) {
+	assert.NoError(t, models.PrepareTestDatabase())
+
+	pr := models.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	assert.NoError(t, pr.LoadHeadRepo())
+	gitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+	sha, err := gitRepo.GetBranchCommitID(pr.HeadBranch)
+	assert.NoError(t, err)
+
+	test := func(expected bool) {
+		ready, err := isReadyToAutoMerge(pr)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ready)
+	}
+
+	// Without commit statuses there is nothing to wait for
+	test(true)
+
+	doer := models.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	for _, state := range []api.CommitStatusState{api.CommitStatusPending, api.CommitStatusSuccess} {
+		assert.NoError(t, models.NewCommitStatus(models.NewCommitStatusOptions{
+			Repo:    pr.HeadRepo,
+			Creator: doer,
+			SHA:     sha,
+			CommitStatus: &models.CommitStatus{
+				State:   state,
+				Context: "ci",
+			},
+		}))
+		test(state.IsSuccess())
+	}
+}
@@ -65,6 +65,9 @@ func checkAndUpdateStatus(pr *models.PullRequest) {
 		if err := pr.UpdateColsIfNotMerged("merge_base", "status", "conflicted_files"); err != nil {
 			log.Error("Update[%d]: %v", pr.ID, err)
 		}
+		if pr.Status == models.PullRequestStatusMergeable {
+			AddToAutoMergeQueue(pr)
+		}
 	}
 }
 
@@ -238,5 +241,5 @@ func Init() error {
 
 	go graceful.GetManager().RunWithShutdownFns(prQueue.Run)
 	go graceful.GetManager().RunWithShutdownContext(InitializePullRequests)
-	return nil
+	return initAutoMergeQueue()
 }
@@ -0,0 +1,222 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"io"
+	"io/ioutil"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// codeOwnersMaxSize is the maximum size of a CODEOWNERS file which is read
+const codeOwnersMaxSize = 3 * 1024 * 1024
+
+// GetCodeOwnerRules returns the rules of the CODEOWNERS file of the branch or nil if there is none
+func GetCodeOwnerRules(repo *models.Repository, gitRepo *git.Repository, branch string) ([]*models.CodeOwnerRule, error) {
+	commit, err := gitRepo.GetBranchCommit(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range models.CodeOwnersFiles {
+		blob, err := commit.GetBlobByPath(path)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		dataRc, err := blob.DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ioutil.ReadAll(io.LimitReader(dataRc, codeOwnersMaxSize))
+		dataRc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		rules, warnings := models.ParseCodeOwners(repo, string(content))
+		for _, warning := range warnings {
+			log.Trace("%s in %s of %s: %s", path, branch, repo.FullName(), warning)
+		}
+		return rules, nil
+	}
+	return nil, nil
+}
+
+// getCodeOwnerRulesOfChangedFiles returns the rule of each file changed by the pull request
+// which has owners, keyed by the path of the file
+func getCodeOwnerRulesOfChangedFiles(pr *models.PullRequest, gitRepo *git.Repository) (map[string]*models.CodeOwnerRule, error) {
+	rules, err := GetCodeOwnerRules(pr.BaseRepo, gitRepo, pr.BaseBranch)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	files, err := gitRepo.GetFilesChangedBetween(git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]*models.CodeOwnerRule, len(files))
+	for _, file := range files {
+		if rule := models.GetCodeOwnerRuleForPath(rules, file); rule != nil && rule.HasOwners() {
+			owned[file] = rule
+		}
+	}
+	return owned, nil
+}
+
+// RequestCodeOwnersReviews requests reviews from the code owners of the files changed by the pull request
+// on behalf of its poster. Owners who already reviewed or were requested are skipped, owning teams are
+// requested as a whole.
+func RequestCodeOwnersReviews(pr *models.PullRequest) error {
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	owned, err := getCodeOwnerRulesOfChangedFiles(pr, gitRepo)
+	if err != nil || len(owned) == 0 {
+		return err
+	}
+
+	reviewers := make(map[int64]*models.User)
+	teams := make(map[int64]*models.Team)
+	for _, rule := range owned {
+		for _, u := range rule.Users {
+			reviewers[u.ID] = u
+		}
+		for _, t := range rule.Teams {
+			teams[t.ID] = t
+		}
+	}
+
+	for _, reviewer := range reviewers {
+		if reviewer.ID == pr.Issue.PosterID || !reviewer.IsActive || reviewer.ProhibitLogin {
+			continue
+		}
+		review, err := models.GetReviewerByIssueIDAndUserID(pr.IssueID, reviewer.ID)
+		if err != nil {
+			return err
+		}
+		if review != nil && review.ID > 0 {
+			continue
+		}
+		perm, err := models.GetUserRepoPermission(pr.BaseRepo, reviewer)
+		if err != nil {
+			return err
+		}
+		if !perm.CanRead(models.UnitTypePullRequests) {
+			continue
+		}
+		if err := issue_service.ReviewRequest(pr.Issue, pr.Issue.Poster, reviewer, true); err != nil {
+			return err
+		}
+	}
+
+	if len(teams) == 0 {
+		return nil
+	}
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		if !team.HasRepository(pr.BaseRepo.ID) || !team.UnitEnabled(models.UnitTypePullRequests) {
+			continue
+		}
+		// the review of any member satisfies the request of the team
+		reviewed := false
+		for _, review := range reviews {
+			if review.Type != models.ReviewTypeApprove && review.Type != models.ReviewTypeReject {
+				continue
+			}
+			if reviewed, err = models.IsTeamMember(team.OrgID, team.ID, review.ReviewerID); err != nil {
+				return err
+			} else if reviewed {
+				break
+			}
+		}
+		if reviewed {
+			continue
+		}
+		if err := issue_service.TeamReviewRequest(pr.Issue, pr.Issue.Poster, team, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsBlockedByCodeOwners returns true if the protected base branch requires approvals of code owners
+// and a file changed by the pull request has not been approved by one of its owners
+func IsBlockedByCodeOwners(pr *models.PullRequest) (bool, error) {
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.BlockOnCodeOwnerReviews {
+		return false, nil
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return false, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return false, err
+	}
+	defer gitRepo.Close()
+
+	owned, err := getCodeOwnerRulesOfChangedFiles(pr, gitRepo)
+	if err != nil || len(owned) == 0 {
+		return false, err
+	}
+
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return false, err
+	}
+	approverIDs := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Type == models.ReviewTypeApprove && !(review.Stale && pr.ProtectedBranch.DismissStaleApprovals) {
+			approverIDs = append(approverIDs, review.ReviewerID)
+		}
+	}
+
+	approved := make(map[*models.CodeOwnerRule]bool)
+	for _, rule := range owned {
+		if _, checked := approved[rule]; checked {
+			continue
+		}
+		approved[rule] = false
+		for _, approverID := range approverIDs {
+			isOwner, err := rule.IsOwner(approverID)
+			if err != nil {
+				return false, err
+			}
+			if isOwner {
+				approved[rule] = true
+				break
+			}
+		}
+		if !approved[rule] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -96,6 +96,12 @@ func IsPullCommitStatusPass(pr *models.PullRequest) (bool, error) {
 
 // GetPullRequestCommitStatusState returns pull request merged commit status state
 func GetPullRequestCommitStatusState(pr *models.PullRequest) (structs.CommitStatusState, error) {
+	return getPullRequestCommitStatusState(pr, pr.ProtectedBranch.StatusCheckContexts)
+}
+
+// getPullRequestCommitStatusState returns the state of the required contexts of the head commit,
+// or the combined state of all its commit statuses if no contexts are required
+func getPullRequestCommitStatusState(pr *models.PullRequest, requiredContexts []string) (structs.CommitStatusState, error) {
 	// Ensure HeadRepo is loaded
 	if err := pr.LoadHeadRepo(); err != nil {
 		return "", errors.Wrap(err, "LoadHeadRepo")
@@ -126,5 +132,5 @@ func GetPullRequestCommitStatusState(pr *models.PullRequest) (structs.CommitStat
 		return "", errors.Wrap(err, "GetLatestCommitStatus")
 	}
 
-	return MergeRequiredContextsCommitStatus(commitStatuses, pr.ProtectedBranch.StatusCheckContexts), nil
+	return MergeRequiredContextsCommitStatus(commitStatuses, requiredContexts), nil
 }
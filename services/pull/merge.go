@@ -598,5 +598,15 @@ func CheckPRReadyToMerge(pr *models.PullRequest) (err error) {
 		}
 	}
 
+	isBlocked, err := IsBlockedByCodeOwners(pr)
+	if err != nil {
+		return err
+	}
+	if isBlocked {
+		return models.ErrNotAllowedToMerge{
+			Reason: "Does not have approval of the code owners",
+		}
+	}
+
 	return nil
 }
@@ -26,6 +26,10 @@ import (
 
 // NewPullRequest creates new pull request with labels for repository.
 func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int64, uuids []string, pr *models.PullRequest, assigneeIDs []int64) error {
+	if err := models.CheckUserBlockedBy(pull.PosterID, repo.OwnerID); err != nil {
+		return err
+	}
+
 	if err := TestPatch(pr); err != nil {
 		return err
 	}
@@ -56,6 +60,10 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 
 	notification.NotifyNewPullRequest(pr)
 
+	if err := RequestCodeOwnersReviews(pr); err != nil {
+		log.Error("RequestCodeOwnersReviews[%d]: %v", pr.ID, err)
+	}
+
 	// add first push codes comment
 	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
 	if err != nil {
@@ -281,6 +289,9 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			if err == nil && comment != nil {
 				notification.NotifyPullRequestPushCommits(doer, pr, comment)
 			}
+			if err := RequestCodeOwnersReviews(pr); err != nil {
+				log.Error("RequestCodeOwnersReviews[%d]: %v", pr.ID, err)
+			}
 		}
 
 		log.Trace("AddTestPullRequestTask [base_repo_id: %d, base_branch: %s]: finding pull requests", repoID, branch)
@@ -16,14 +16,26 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 )
 
-// CreateCodeComment creates a comment on the code line
-func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models.Issue, line int64, content string, treePath string, isReview bool, replyReviewID int64, latestCommitID string) (*models.Comment, error) {
+// CreateCodeComment creates a comment on the code line, or on the lines from startLine to line if startLine is not 0
+func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models.Issue, line, startLine int64, content string, treePath string, isReview bool, replyReviewID int64, latestCommitID string) (*models.Comment, error) {
 
 	var (
 		existsReview bool
 		err          error
 	)
 
+	if startLine == line {
+		startLine = 0
+	}
+	// both lines have to be on the same side and in order
+	if startLine != 0 && (line == 0 || (startLine < 0) != (line < 0) || (line > 0 && startLine > line) || (line < 0 && startLine < line)) {
+		return nil, models.ErrInvalidCommentRange{StartLine: startLine, Line: line}
+	}
+
+	if err = models.CheckUserBlockedFromIssue(doer.ID, issue); err != nil {
+		return nil, err
+	}
+
 	// CreateCodeComment() is used for:
 	// - Single comments
 	// - Comments that are part of a review
@@ -31,8 +43,15 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 
 	if !isReview && replyReviewID != 0 {
 		// It's not part of a review; maybe a reply to a review comment or a single comment.
-		// Check if there are reviews for that line already; if there are, this is a reply
-		if existsReview, err = models.ReviewExists(issue, treePath, line); err != nil {
+		// A reply continues the range of the thread it answers
+		threadStartLine, has, err := models.GetReviewThreadStartLine(replyReviewID, treePath, line)
+		if err != nil {
+			return nil, err
+		} else if has {
+			startLine = threadStartLine
+		}
+		// Check if there are reviews for that range already; if there are, this is a reply
+		if existsReview, err = models.ReviewExists(issue, treePath, line, startLine); err != nil {
 			return nil, err
 		}
 	}
@@ -50,6 +69,7 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 			content,
 			treePath,
 			line,
+			startLine,
 			replyReviewID,
 		)
 		if err != nil {
@@ -86,6 +106,7 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 		content,
 		treePath,
 		line,
+		startLine,
 		review.ID,
 	)
 	if err != nil {
@@ -105,7 +126,7 @@ func CreateCodeComment(doer *models.User, gitRepo *git.Repository, issue *models
 }
 
 // createCodeComment creates a plain code comment at the specified line / path
-func createCodeComment(doer *models.User, repo *models.Repository, issue *models.Issue, content, treePath string, line, reviewID int64) (*models.Comment, error) {
+func createCodeComment(doer *models.User, repo *models.Repository, issue *models.Issue, content, treePath string, line, startLine, reviewID int64) (*models.Comment, error) {
 	var commitID, patch string
 	if err := issue.LoadPullRequest(); err != nil {
 		return nil, fmt.Errorf("GetPullRequestByIssueID: %v", err)
@@ -142,24 +163,36 @@ func createCodeComment(doer *models.User, repo *models.Repository, issue *models
 		if err := git.GetRepoRawDiffForFile(gitRepo, pr.MergeBase, headCommitID, git.RawDiffNormal, treePath, patchBuf); err != nil {
 			return nil, fmt.Errorf("GetRawDiffForLine[%s, %s, %s, %s]: %v", err, gitRepo.Path, pr.MergeBase, headCommitID, treePath)
 		}
-		patch = git.CutDiffAroundLine(patchBuf, int64((&models.Comment{Line: line}).UnsignedLine()), line < 0, setting.UI.CodeCommentLines)
+		c := &models.Comment{Line: line, StartLine: startLine}
+		patch, err = git.CutDiffAroundLines(patchBuf, int64(c.UnsignedStartLine()), int64(c.UnsignedLine()), line < 0, setting.UI.CodeCommentLines)
+		if err != nil {
+			if git.IsErrRangeSpansHunks(err) {
+				return nil, models.ErrInvalidCommentRange{StartLine: startLine, Line: line}
+			}
+			return nil, err
+		}
 	}
 	return models.CreateComment(&models.CreateCommentOptions{
-		Type:      models.CommentTypeCode,
-		Doer:      doer,
-		Repo:      repo,
-		Issue:     issue,
-		Content:   content,
-		LineNum:   line,
-		TreePath:  treePath,
-		CommitSHA: commitID,
-		ReviewID:  reviewID,
-		Patch:     patch,
+		Type:         models.CommentTypeCode,
+		Doer:         doer,
+		Repo:         repo,
+		Issue:        issue,
+		Content:      content,
+		LineNum:      line,
+		StartLineNum: startLine,
+		TreePath:     treePath,
+		CommitSHA:    commitID,
+		ReviewID:     reviewID,
+		Patch:        patch,
 	})
 }
 
 // SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
 func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issue, reviewType models.ReviewType, content, commitID string) (*models.Review, *models.Comment, error) {
+	if err := models.CheckUserBlockedFromIssue(doer.ID, issue); err != nil {
+		return nil, nil, err
+	}
+
 	pr, err := issue.GetPullRequest()
 	if err != nil {
 		return nil, nil, err
@@ -191,5 +224,9 @@ func SubmitReview(doer *models.User, gitRepo *git.Repository, issue *models.Issu
 
 	notification.NotifyPullRequestReview(pr, review, comm)
 
+	if reviewType == models.ReviewTypeApprove {
+		AddToAutoMergeQueue(pr)
+	}
+
 	return review, comm, nil
 }
@@ -0,0 +1,116 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/repofiles"
+)
+
+// IsUserAllowedToApplySuggestions checks whether the user may commit suggestions to the head branch of the pull request
+func IsUserAllowedToApplySuggestions(pr *models.PullRequest, user *models.User) (bool, error) {
+	if user == nil || pr.HasMerged {
+		return false, nil
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		return false, err
+	}
+	if pr.HeadRepo == nil {
+		return false, nil
+	}
+
+	perm, err := models.GetUserRepoPermission(pr.HeadRepo, user)
+	if err != nil {
+		return false, err
+	}
+	if !perm.CanWrite(models.UnitTypeCode) {
+		return false, nil
+	}
+
+	protectedBranch, err := pr.HeadRepo.GetBranchProtection(pr.HeadBranch)
+	if err != nil {
+		return false, err
+	}
+	return protectedBranch == nil || protectedBranch.CanUserPush(user.ID), nil
+}
+
+// ApplySuggestions commits the changes suggested in the given code comments to the head branch
+// of the pull request, crediting their posters as co-authors, and resolves the conversations.
+func ApplySuggestions(pr *models.PullRequest, doer *models.User, comments []*models.Comment, message string) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		return err
+	}
+	if pr.HeadRepo == nil {
+		return models.ErrRepoNotExist{ID: pr.HeadRepoID}
+	}
+
+	suggestions := make([]*repofiles.Suggestion, 0, len(comments))
+	coAuthors := make([]*models.User, 0, len(comments))
+	for _, comment := range comments {
+		if comment.IssueID != pr.IssueID || comment.Type != models.CommentTypeCode {
+			return models.ErrCommentNotExist{ID: comment.ID, IssueID: pr.IssueID}
+		}
+		if err := comment.LoadReview(); err != nil && !models.IsErrReviewNotExist(err) {
+			return err
+		}
+		if comment.Review != nil && comment.Review.Type == models.ReviewTypePending {
+			return models.ErrCommentNotExist{ID: comment.ID, IssueID: pr.IssueID}
+		}
+
+		original := comment.CommentedLines()
+		content, has := markdown.GetSuggestion([]byte(comment.Content))
+		if !has || len(original) == 0 {
+			return models.ErrCommentNotExist{ID: comment.ID, IssueID: pr.IssueID}
+		}
+		if comment.Invalidated || comment.IsResolved() {
+			return models.ErrSuggestionOutdated{TreePath: comment.TreePath, Line: comment.Line}
+		}
+
+		if err := comment.LoadPoster(); err != nil {
+			return err
+		}
+		suggestions = append(suggestions, &repofiles.Suggestion{
+			TreePath: comment.TreePath,
+			Line:     comment.Line - int64(len(original)) + 1,
+			Original: original,
+			Content:  content,
+		})
+		coAuthors = append(coAuthors, comment.Poster)
+	}
+
+	if message == "" {
+		if len(comments) == 1 {
+			message = fmt.Sprintf("Apply suggestion to %s", comments[0].TreePath)
+		} else {
+			message = fmt.Sprintf("Apply %d suggestions from code review", len(comments))
+		}
+	}
+
+	if _, err := repofiles.ApplySuggestions(pr.HeadRepo, doer, &repofiles.ApplySuggestionsOptions{
+		Branch:      pr.HeadBranch,
+		Message:     message,
+		Suggestions: suggestions,
+		CoAuthors:   coAuthors,
+	}); err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		if err := models.MarkConversation(comment, doer, true); err != nil {
+			log.Error("MarkConversation[%d]: %v", comment.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package registration
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// emailDomain returns the lower cased domain of the email address
+func emailDomain(email string) string {
+	n := strings.LastIndex(email, "@")
+	if n <= 0 {
+		return ""
+	}
+	return strings.ToLower(email[n+1:])
+}
+
+// matchDomain returns true if the domain is one of the domains or a subdomain of one of them
+func matchDomain(domain string, domains map[string]bool) bool {
+	for {
+		if domains[domain] {
+			return true
+		}
+		n := strings.Index(domain, ".")
+		if n < 0 {
+			return false
+		}
+		domain = domain[n+1:]
+	}
+}
+
+func toDomainSet(domains []string) map[string]bool {
+	set := make(map[string]bool, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if len(domain) > 0 {
+			set[domain] = true
+		}
+	}
+	return set
+}
+
+// emailDomainChecker rejects registrations with an email domain missing from the allow list or
+// present in the deny list, the deny list also matches the subdomains
+type emailDomainChecker struct {
+	allowed map[string]bool
+	denied  map[string]bool
+}
+
+func newEmailDomainChecker() Checker {
+	if len(setting.Service.EmailDomainWhitelist) == 0 && len(setting.Service.EmailDomainBlocklist) == 0 {
+		return nil
+	}
+	return &emailDomainChecker{
+		allowed: toDomainSet(setting.Service.EmailDomainWhitelist),
+		denied:  toDomainSet(setting.Service.EmailDomainBlocklist),
+	}
+}
+
+func (c *emailDomainChecker) Name() string {
+	return "email domain"
+}
+
+func (c *emailDomainChecker) Check(reg *Registration) (*Result, error) {
+	domain := emailDomain(reg.Email)
+	if len(c.allowed) > 0 && !c.allowed[domain] {
+		return &Result{Verdict: Reject, Reason: fmt.Sprintf("email domain %q is not allowed", domain), Message: "auth.email_domain_blacklisted"}, nil
+	}
+	if matchDomain(domain, c.denied) {
+		return &Result{Verdict: Reject, Reason: fmt.Sprintf("email domain %q is denied", domain), Message: "auth.email_domain_blacklisted"}, nil
+	}
+	return nil, nil
+}
+
+// defaultDisposableEmailDomains lists well-known disposable email providers, the list can be
+// extended with DISPOSABLE_EMAIL_DOMAINS_FILE
+var defaultDisposableEmailDomains = []string{
+	"10minutemail.com",
+	"discard.email",
+	"dispostable.com",
+	"emailondeck.com",
+	"fakeinbox.com",
+	"getairmail.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"guerrillamail.net",
+	"mailcatch.com",
+	"maildrop.cc",
+	"mailinator.com",
+	"mailnesia.com",
+	"mintemail.com",
+	"mohmal.com",
+	"mytemp.email",
+	"sharklasers.com",
+	"spamgourmet.com",
+	"temp-mail.org",
+	"tempail.com",
+	"tempmail.net",
+	"tempr.email",
+	"throwawaymail.com",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// disposableEmailChecker flags or rejects registrations with an email address of a disposable
+// email provider
+type disposableEmailChecker struct {
+	verdict Verdict
+	domains map[string]bool
+}
+
+func newDisposableEmailChecker() (Checker, error) {
+	var verdict Verdict
+	switch setting.Registration.DisposableEmailDomains {
+	case setting.RegistrationActionFlag:
+		verdict = Flag
+	case setting.RegistrationActionReject:
+		verdict = Reject
+	default:
+		return nil, nil
+	}
+
+	domains := toDomainSet(defaultDisposableEmailDomains)
+	if len(setting.Registration.DisposableEmailDomainsFile) > 0 {
+		f, err := os.Open(setting.Registration.DisposableEmailDomainsFile)
+		if err != nil {
+			return nil, fmt.Errorf("open disposable email domains file: %v", err)
+		}
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			if len(line) > 0 && !strings.HasPrefix(line, "#") {
+				domains[line] = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read disposable email domains file: %v", err)
+		}
+	}
+	return &disposableEmailChecker{verdict: verdict, domains: domains}, nil
+}
+
+func (c *disposableEmailChecker) Name() string {
+	return "disposable email"
+}
+
+func (c *disposableEmailChecker) Check(reg *Registration) (*Result, error) {
+	domain := emailDomain(reg.Email)
+	if !matchDomain(domain, c.domains) {
+		return nil, nil
+	}
+	return &Result{Verdict: c.verdict, Reason: fmt.Sprintf("email domain %q is a disposable email provider", domain), Message: "auth.email_domain_disposable"}, nil
+}
+
+// reviewChecker flags all registrations when new accounts have to be reviewed
+type reviewChecker struct{}
+
+func newReviewChecker() Checker {
+	if !setting.Registration.ReviewNewAccounts {
+		return nil
+	}
+	return reviewChecker{}
+}
+
+func (reviewChecker) Name() string {
+	return "review"
+}
+
+func (reviewChecker) Check(reg *Registration) (*Result, error) {
+	return &Result{Verdict: Flag, Reason: "new accounts are reviewed"}, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package registration
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Verdict is the outcome of a registration check
+type Verdict int
+
+// Possible verdicts, ordered from the most to the least permissive
+const (
+	// Accept lets the registration through
+	Accept Verdict = iota
+	// Flag lets the registration through but queues the new account for the review of an
+	// administrator, the account stays in probation until it is reviewed
+	Flag
+	// Reject refuses the registration
+	Reject
+)
+
+// Registration describes an account which is being registered
+type Registration struct {
+	Name  string
+	Email string
+	IP    string
+}
+
+// Result is the result of a registration check
+type Result struct {
+	Verdict Verdict
+	// Reason explains the verdict to administrators
+	Reason string
+	// Message is the locale key of the message shown when the registration is rejected
+	Message string
+}
+
+// Checker inspects registrations
+type Checker interface {
+	Name() string
+	// Check returns the result of the check, a nil result accepts the registration
+	Check(reg *Registration) (*Result, error)
+}
+
+var (
+	checkersLock sync.RWMutex
+	builtin      []Checker
+	checkers     []Checker
+)
+
+// RegisterChecker adds a registration check, checks run in the order they are registered after
+// the built-in checks
+func RegisterChecker(checker Checker) {
+	checkersLock.Lock()
+	defer checkersLock.Unlock()
+	checkers = append(checkers, checker)
+}
+
+// Check runs the registration through the registered checks. The first rejection is returned
+// as is, the reasons of the checks flagging the registration are combined.
+func Check(reg *Registration) (*Result, error) {
+	checkersLock.RLock()
+	defer checkersLock.RUnlock()
+
+	all := make([]Checker, 0, len(builtin)+len(checkers))
+	all = append(append(all, builtin...), checkers...)
+
+	var reasons []string
+	for _, checker := range all {
+		result, err := checker.Check(reg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", checker.Name(), err)
+		}
+		if result == nil {
+			continue
+		}
+		switch result.Verdict {
+		case Reject:
+			log.Info("Registration of %s <%s> from %s rejected by %s: %s", reg.Name, reg.Email, reg.IP, checker.Name(), result.Reason)
+			if len(result.Message) == 0 {
+				result.Message = "auth.sign_up_rejected"
+			}
+			return result, nil
+		case Flag:
+			reasons = append(reasons, result.Reason)
+		}
+	}
+
+	if len(reasons) > 0 {
+		return &Result{Verdict: Flag, Reason: strings.Join(reasons, "; ")}, nil
+	}
+	return &Result{Verdict: Accept}, nil
+}
+
+// Apply queues the newly created account for review if the result flagged its registration
+func Apply(u *models.User, result *Result) error {
+	if result == nil || result.Verdict != Flag {
+		return nil
+	}
+	log.Info("Registration of %s flagged for review: %s", u.Name, result.Reason)
+	return models.FlagUser(u.ID, result.Reason)
+}
+
+// Init registers the built-in checks enabled by the settings
+func Init() error {
+	disposable, err := newDisposableEmailChecker()
+	if err != nil {
+		return err
+	}
+
+	checkersLock.Lock()
+	defer checkersLock.Unlock()
+	builtin = builtin[:0]
+	for _, checker := range []Checker{
+		newEmailDomainChecker(),
+		disposable,
+		newReviewChecker(),
+	} {
+		if checker != nil {
+			builtin = append(builtin, checker)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2020 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package registration
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func check(t *testing.T, email string) *Result {
+	result, err := Check(&Registration{Name: "user", Email: email, IP: "127.0.0.1"})
+	assert.NoError(t, err)
+	return result
+}
+
+func TestCheck_EmailDomain(t *testing.T) {
+	defer func(allowed, denied []string) {
+		setting.Service.EmailDomainWhitelist = allowed
+		setting.Service.EmailDomainBlocklist = denied
+	}(setting.Service.EmailDomainWhitelist, setting.Service.EmailDomainBlocklist)
+
+	setting.Service.EmailDomainWhitelist = nil
+	setting.Service.EmailDomainBlocklist = []string{"Spam.example.com", "example.org"}
+	assert.NoError(t, Init())
+
+	assert.Equal(t, Accept, check(t, "user@example.com").Verdict)
+	assert.Equal(t, Accept, check(t, "user@notspam.example.com").Verdict)
+	for _, email := range []string{"user@spam.example.com", "user@SPAM.example.com", "user@mail.example.org"} {
+		result := check(t, email)
+		assert.Equal(t, Reject, result.Verdict, email)
+		assert.Equal(t, "auth.email_domain_blacklisted", result.Message)
+	}
+
+	setting.Service.EmailDomainWhitelist = []string{"gitea.io"}
+	setting.Service.EmailDomainBlocklist = nil
+	assert.NoError(t, Init())
+	assert.Equal(t, Accept, check(t, "user@gitea.io").Verdict)
+	assert.Equal(t, Reject, check(t, "user@docs.gitea.io").Verdict)
+	assert.Equal(t, Reject, check(t, "user").Verdict)
+}
+
+func TestCheck_Disposable(t *testing.T) {
+	defer func(action, file string) {
+		setting.Registration.DisposableEmailDomains = action
+		setting.Registration.DisposableEmailDomainsFile = file
+	}(setting.Registration.DisposableEmailDomains, setting.Registration.DisposableEmailDomainsFile)
+
+	dir, err := ioutil.TempDir("", "disposable")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "domains.txt")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("# extra domains\nthrowaway.example.com\n\n"), 0644))
+
+	setting.Registration.DisposableEmailDomains = setting.RegistrationActionNone
+	assert.NoError(t, Init())
+	assert.Equal(t, Accept, check(t, "user@mailinator.com").Verdict)
+
+	setting.Registration.DisposableEmailDomains = setting.RegistrationActionFlag
+	setting.Registration.DisposableEmailDomainsFile = file
+	assert.NoError(t, Init())
+	assert.Equal(t, Flag, check(t, "user@mailinator.com").Verdict)
+	assert.Equal(t, Flag, check(t, "user@throwaway.example.com").Verdict)
+	assert.Equal(t, Accept, check(t, "user@example.com").Verdict)
+
+	setting.Registration.DisposableEmailDomains = setting.RegistrationActionReject
+	assert.NoError(t, Init())
+	result := check(t, "user@yopmail.com")
+	assert.Equal(t, Reject, result.Verdict)
+	assert.Equal(t, "auth.email_domain_disposable", result.Message)
+
+	setting.Registration.DisposableEmailDomainsFile = filepath.Join(dir, "missing.txt")
+	assert.Error(t, Init())
+}
+
+type nameChecker struct{}
+
+func (nameChecker) Name() string {
+	return "name"
+}
+
+func (nameChecker) Check(reg *Registration) (*Result, error) {
+	if reg.Name == "spammer" {
+		return &Result{Verdict: Reject, Reason: "spammer"}, nil
+	}
+	return &Result{Verdict: Flag, Reason: "suspicious name"}, nil
+}
+
+func TestCheck_Review(t *testing.T) {
+	defer func(review bool) {
+		setting.Registration.ReviewNewAccounts = review
+		checkers = nil
+	}(setting.Registration.ReviewNewAccounts)
+
+	setting.Registration.ReviewNewAccounts = true
+	assert.NoError(t, Init())
+	RegisterChecker(nameChecker{})
+
+	result := check(t, "user@example.com")
+	assert.Equal(t, Flag, result.Verdict)
+	assert.Equal(t, "new accounts are reviewed; suspicious name", result.Reason)
+
+	result, err := Check(&Registration{Name: "spammer", Email: "spammer@example.com"})
+	assert.NoError(t, err)
+	assert.Equal(t, Reject, result.Verdict)
+	assert.Equal(t, "auth.sign_up_rejected", result.Message)
+}
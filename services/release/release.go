@@ -21,6 +21,10 @@ func createTag(gitRepo *git.Repository, rel *models.Release) error {
 	// Only actual create when publish.
 	if !rel.IsDraft {
 		if !gitRepo.IsTagExist(rel.TagName) {
+			if err := checkProtectedTag(rel.RepoID, rel.TagName, rel.PublisherID); err != nil {
+				return err
+			}
+
 			commit, err := gitRepo.GetCommit(rel.Target)
 			if err != nil {
 				return fmt.Errorf("GetCommit: %v", err)
@@ -64,6 +68,28 @@ func createTag(gitRepo *git.Repository, rel *models.Release) error {
 	return nil
 }
 
+// checkProtectedTag returns ErrProtectedTagName if the user is not allowed to create or delete the tag
+func checkProtectedTag(repoID int64, tagName string, userID int64) error {
+	repo, err := models.GetRepositoryByID(repoID)
+	if err != nil {
+		return err
+	}
+	protectedTags, err := repo.GetProtectedTags()
+	if err != nil {
+		return fmt.Errorf("GetProtectedTags: %v", err)
+	}
+	isAllowed, err := models.IsUserAllowedToControlTag(protectedTags, tagName, userID)
+	if err != nil {
+		return err
+	}
+	if !isAllowed {
+		return models.ErrProtectedTagName{
+			TagName: tagName,
+		}
+	}
+	return nil
+}
+
 // CreateRelease creates a new release of repository.
 func CreateRelease(gitRepo *git.Repository, rel *models.Release, attachmentUUIDs []string) error {
 	isExist, err := models.IsReleaseExist(rel.RepoID, rel.TagName)
@@ -135,6 +161,10 @@ func DeleteReleaseByID(id int64, doer *models.User, delTag bool) error {
 	}
 
 	if delTag {
+		if err := checkProtectedTag(repo.ID, rel.TagName, doer.ID); err != nil {
+			return err
+		}
+
 		if stdout, err := git.NewCommand("tag", "-d", rel.TagName).
 			SetDescription(fmt.Sprintf("DeleteReleaseByID (git tag -d): %d", rel.ID)).
 			RunInDir(repo.RepoPath()); err != nil && !strings.Contains(err.Error(), "not found") {
@@ -0,0 +1,204 @@
+const {AppSubUrl, csrf} = window.config;
+
+export function initWebAuthnAuth() {
+  if ($('.webauthn-prompt').length === 0) {
+    return;
+  }
+  if (typeof window.PublicKeyCredential !== 'function') {
+    // Fallback in case the browser does not support WebAuthn
+    window.location.href = `${AppSubUrl}/user/two_factor`;
+    return;
+  }
+
+  $.getJSON(`${AppSubUrl}/user/webauthn/assertion`).done(async (makeAssertionOptions) => {
+    try {
+      const credential = await navigator.credentials.get({
+        publicKey: decodeAssertionOptions(makeAssertionOptions.publicKey),
+      });
+      await verifyAssertion(`${AppSubUrl}/user/webauthn/assertion`, credential);
+    } catch (err) {
+      webAuthnError(errorType(err));
+    }
+  }).fail(() => {
+    webAuthnError('unknown');
+  });
+}
+
+export function initWebAuthnPasswordless() {
+  const $button = $('#webauthn-passwordless');
+  if ($button.length === 0) {
+    return;
+  }
+  $('#webauthn-error').modal({allowMultiple: false});
+  $button.on('click', async (e) => {
+    e.preventDefault();
+    if (!detectWebAuthnSupport()) {
+      return;
+    }
+    try {
+      const makeAssertionOptions = await $.getJSON(`${AppSubUrl}/user/webauthn/passwordless/assertion`);
+      const credential = await navigator.credentials.get({
+        publicKey: decodeAssertionOptions(makeAssertionOptions.publicKey),
+      });
+      await verifyAssertion(`${AppSubUrl}/user/webauthn/passwordless/assertion`, credential);
+    } catch (err) {
+      webAuthnError(errorType(err));
+    }
+  });
+}
+
+export function initWebAuthnRegister() {
+  if ($('#register-webauthn').length === 0) {
+    return;
+  }
+  $('#register-device').modal({allowMultiple: false});
+  $('#webauthn-error').modal({allowMultiple: false});
+  $('#register-webauthn').on('click', (e) => {
+    e.preventDefault();
+    if (!detectWebAuthnSupport()) {
+      return;
+    }
+    webAuthnRegisterRequest();
+  });
+}
+
+function webAuthnRegisterRequest() {
+  const $nickname = $('#nickname');
+  if ($nickname.val() === '') {
+    webAuthnError('empty');
+    return;
+  }
+  $.post(`${AppSubUrl}/user/settings/security/webauthn/request_register`, {
+    _csrf: csrf,
+    name: $nickname.val(),
+    passwordless: $('#passwordless').is(':checked'),
+  }).done(async (makeCredentialOptions) => {
+    $nickname.closest('div.field').removeClass('error');
+    $('#register-device').modal('show');
+
+    const {publicKey} = makeCredentialOptions;
+    publicKey.challenge = decodeURLEncodedBase64(publicKey.challenge);
+    publicKey.user.id = decodeURLEncodedBase64(publicKey.user.id);
+    if (publicKey.excludeCredentials) {
+      for (const cred of publicKey.excludeCredentials) {
+        cred.id = decodeURLEncodedBase64(cred.id);
+      }
+    }
+
+    try {
+      const credential = await navigator.credentials.create({publicKey});
+      await webauthnRegistered(credential);
+    } catch (err) {
+      $('#register-device').modal('hide');
+      webAuthnError(errorType(err));
+    }
+  }).fail((xhr) => {
+    if (xhr.status === 409) {
+      $nickname.closest('div.field').addClass('error');
+    }
+  });
+}
+
+async function webauthnRegistered(newCredential) {
+  const {attestationObject, clientDataJSON} = newCredential.response;
+  await $.ajax({
+    url: `${AppSubUrl}/user/settings/security/webauthn/register`,
+    type: 'POST',
+    headers: {'X-Csrf-Token': csrf},
+    data: JSON.stringify({
+      id: newCredential.id,
+      rawId: encodeURLEncodedBase64(newCredential.rawId),
+      type: newCredential.type,
+      response: {
+        attestationObject: encodeURLEncodedBase64(attestationObject),
+        clientDataJSON: encodeURLEncodedBase64(clientDataJSON),
+      },
+    }),
+    contentType: 'application/json; charset=utf-8',
+  });
+  window.location.reload();
+}
+
+async function verifyAssertion(url, assertedCredential) {
+  const {authenticatorData, clientDataJSON, signature, userHandle} = assertedCredential.response;
+  let redirect;
+  try {
+    redirect = await $.ajax({
+      url,
+      type: 'POST',
+      headers: {'X-Csrf-Token': csrf},
+      data: JSON.stringify({
+        id: assertedCredential.id,
+        rawId: encodeURLEncodedBase64(assertedCredential.rawId),
+        type: assertedCredential.type,
+        clientExtensionResults: assertedCredential.getClientExtensionResults(),
+        response: {
+          authenticatorData: encodeURLEncodedBase64(authenticatorData),
+          clientDataJSON: encodeURLEncodedBase64(clientDataJSON),
+          signature: encodeURLEncodedBase64(signature),
+          userHandle: userHandle ? encodeURLEncodedBase64(userHandle) : undefined,
+        },
+      }),
+      contentType: 'application/json; charset=utf-8',
+    });
+  } catch (err) {
+    webAuthnError('unable-to-process');
+    return;
+  }
+  window.location.replace(redirect);
+}
+
+function decodeAssertionOptions(publicKey) {
+  publicKey.challenge = decodeURLEncodedBase64(publicKey.challenge);
+  for (const cred of publicKey.allowCredentials || []) {
+    cred.id = decodeURLEncodedBase64(cred.id);
+  }
+  return publicKey;
+}
+
+// Encode an ArrayBuffer into a URLEncoded base64 string.
+function encodeURLEncodedBase64(value) {
+  return btoa(String.fromCharCode(...new Uint8Array(value)))
+    .replace(/\+/g, '-')
+    .replace(/\//g, '_')
+    .replace(/=/g, '');
+}
+
+// Decode a URLEncoded base64 string to a Uint8Array.
+function decodeURLEncodedBase64(value) {
+  return Uint8Array.from(atob(value.replace(/_/g, '/').replace(/-/g, '+')), (c) => c.charCodeAt(0));
+}
+
+function errorType(err) {
+  if (!err || !err.name) {
+    return 'unknown';
+  }
+  switch (err.name) {
+    case 'InvalidStateError':
+      return 'duplicated';
+    case 'SecurityError':
+      return 'insecure';
+    case 'NotAllowedError':
+      return 'timeout';
+    default:
+      return 'unknown';
+  }
+}
+
+function webAuthnError(type) {
+  $('#webauthn-error [data-webauthn-error-msg]').addClass('hide');
+  $(`#webauthn-error [data-webauthn-error-msg="${type}"]`).removeClass('hide');
+  $('#webauthn-error').modal('show');
+}
+
+function detectWebAuthnSupport() {
+  if (!window.isSecureContext) {
+    webAuthnError('insecure');
+    return false;
+  }
+  if (typeof window.PublicKeyCredential !== 'function') {
+    webAuthnError('browser');
+    return false;
+  }
+  return true;
+}
@@ -21,6 +21,7 @@ import attachTribute from './features/tribute.js';
 import createColorPicker from './features/colorpicker.js';
 import createDropzone from './features/dropzone.js';
 import initTableSort from './features/tablesort.js';
+import {initWebAuthnAuth, initWebAuthnPasswordless, initWebAuthnRegister} from './features/webauthn.js';
 import ActivityTopAuthors from './components/ActivityTopAuthors.vue';
 import {initNotificationsTable, initNotificationCount} from './features/notification.js';
 import {createCodeEditor} from './features/codeeditor.js';
@@ -210,6 +211,14 @@ function initRepoStatusChecker() {
               return;
             }
 
+            if (xhr.responseJSON.err) {
+              $('#repo_migrating_progress').hide();
+              $('#repo_migrating_failed_error').text(xhr.responseJSON.err);
+              $('#repo_migrating_failed').show();
+              return;
+            }
+
+            $('#repo_migrating_progress_message').text(xhr.responseJSON.message || '');
             setTimeout(() => {
               initRepoStatusChecker();
             }, 2000);
@@ -1212,21 +1221,51 @@ function initPullRequestReview() {
     .on('mouseleave', function () {
       $(this).closest('tr').removeClass('focus-lines-new focus-lines-old');
     });
+  // the last line a comment was started on, shift-clicking another line on the same side selects the range
+  let lastCommentLine = null;
   $('.add-code-comment').on('click', function (e) {
     if ($(e.target).hasClass('btn-add-single')) return; // https://github.com/go-gitea/gitea/issues/4745
     e.preventDefault();
 
-    const isSplit = $(this).closest('.code-diff').hasClass('code-diff-split');
-    const side = $(this).data('side');
-    const idx = $(this).data('idx');
-    const path = $(this).data('path');
+    let $button = $(this);
+    const side = $button.data('side');
+    const path = $button.data('path');
+    let idx = $button.data('idx');
+    let startIdx = idx;
+    if (e.shiftKey && lastCommentLine && lastCommentLine.path === path && lastCommentLine.side === side && lastCommentLine.idx !== idx) {
+      startIdx = Math.min(idx, lastCommentLine.idx);
+      idx = Math.max(idx, lastCommentLine.idx);
+      // the comment belongs to the last line of the range
+      const $last = $button.closest('.code-diff').find(`.add-code-comment-${side}[data-idx="${idx}"]`).filter(function () {
+        return $(this).data('path') === path;
+      });
+      if ($last.length) $button = $last.first();
+    }
+    lastCommentLine = {path, side, idx: $(this).data('idx')};
+
+    const isSplit = $button.closest('.code-diff').hasClass('code-diff-split');
     const form = $('#pull_review_add_comment').html();
-    const tr = $(this).closest('tr');
+    const tr = $button.closest('tr');
+
+    if (startIdx !== idx) {
+      let $row = tr;
+      for (let i = idx; i >= startIdx && $row.length; i--) {
+        $row.find(`.add-code-comment-${side}`).closest('td').addClass('comment-range');
+        $row = $row.prevAll().filter(function () {
+          return $(this).find(`.add-code-comment-${side}`).length > 0;
+        }).first();
+      }
+    }
 
     const oldLineNum = tr.find('.lines-num-old').data('line-num');
     const newLineNum = tr.find('.lines-num-new').data('line-num');
     const addCommentKey = `${oldLineNum}|${newLineNum}`;
-    if (document.querySelector(`[data-add-comment-key="${addCommentKey}"]`)) return; // don't add same comment box twice
+    const rangeStart = startIdx !== idx ? startIdx : '';
+    const existing = document.querySelector(`[data-add-comment-key="${addCommentKey}"]`);
+    if (existing) { // don't add same comment box twice
+      $(existing).find(`.add-comment-${side} input[name='start_line']`).val(rangeStart);
+      return;
+    }
 
     let ntr = tr.next();
     if (!ntr.hasClass('add-comment')) {
@@ -1256,6 +1295,7 @@ function initPullRequestReview() {
       assingMenuAttributes(commentCloud.find('.menu'));
 
       td.find("input[name='line']").val(idx);
+      td.find("input[name='start_line']").val(rangeStart);
       td.find("input[name='side']").val(side === 'left' ? 'previous' : 'proposed');
       td.find("input[name='path']").val(path);
     }
@@ -1825,7 +1865,7 @@ function initAdmin() {
   // New authentication
   if ($('.admin.new.authentication').length > 0) {
     $('#auth_type').on('change', function () {
-      $('.ldap, .dldap, .smtp, .pam, .oauth2, .has-tls, .search-page-size, .sspi').hide();
+      $('.ldap, .dldap, .smtp, .pam, .oauth2, .has-tls, .search-page-size, .sspi, .saml').hide();
 
       $('.ldap input[required], .binddnrequired input[required], .dldap input[required], .smtp input[required], .pam input[required], .oauth2 input[required], .has-tls input[required], .sspi input[required]').removeAttr('required');
       $('.binddnrequired').removeClass('required');
@@ -1859,6 +1899,9 @@ function initAdmin() {
           $('.sspi').show();
           $('.sspi div.required input').attr('required', 'required');
           break;
+        case '8': // SAML
+          $('.saml').show();
+          break;
       }
       if (authType === '2' || authType === '5') {
         onSecurityProtocolChange();
@@ -2070,130 +2113,6 @@ function initCodeView() {
   });
 }
 
-function initU2FAuth() {
-  if ($('#wait-for-key').length === 0) {
-    return;
-  }
-  u2fApi.ensureSupport()
-    .then(() => {
-      $.getJSON(`${AppSubUrl}/user/u2f/challenge`).done((req) => {
-        u2fApi.sign(req.appId, req.challenge, req.registeredKeys, 30)
-          .then(u2fSigned)
-          .catch((err) => {
-            if (err === undefined) {
-              u2fError(1);
-              return;
-            }
-            u2fError(err.metaData.code);
-          });
-      });
-    }).catch(() => {
-      // Fallback in case browser do not support U2F
-      window.location.href = `${AppSubUrl}/user/two_factor`;
-    });
-}
-function u2fSigned(resp) {
-  $.ajax({
-    url: `${AppSubUrl}/user/u2f/sign`,
-    type: 'POST',
-    headers: {'X-Csrf-Token': csrf},
-    data: JSON.stringify(resp),
-    contentType: 'application/json; charset=utf-8',
-  }).done((res) => {
-    window.location.replace(res);
-  }).fail(() => {
-    u2fError(1);
-  });
-}
-
-function u2fRegistered(resp) {
-  if (checkError(resp)) {
-    return;
-  }
-  $.ajax({
-    url: `${AppSubUrl}/user/settings/security/u2f/register`,
-    type: 'POST',
-    headers: {'X-Csrf-Token': csrf},
-    data: JSON.stringify(resp),
-    contentType: 'application/json; charset=utf-8',
-    success() {
-      reload();
-    },
-    fail() {
-      u2fError(1);
-    }
-  });
-}
-
-function checkError(resp) {
-  if (!('errorCode' in resp)) {
-    return false;
-  }
-  if (resp.errorCode === 0) {
-    return false;
-  }
-  u2fError(resp.errorCode);
-  return true;
-}
-
-function u2fError(errorType) {
-  const u2fErrors = {
-    browser: $('#unsupported-browser'),
-    1: $('#u2f-error-1'),
-    2: $('#u2f-error-2'),
-    3: $('#u2f-error-3'),
-    4: $('#u2f-error-4'),
-    5: $('.u2f-error-5')
-  };
-  u2fErrors[errorType].removeClass('hide');
-
-  Object.keys(u2fErrors).forEach((type) => {
-    if (type !== errorType) {
-      u2fErrors[type].addClass('hide');
-    }
-  });
-  $('#u2f-error').modal('show');
-}
-
-function initU2FRegister() {
-  $('#register-device').modal({allowMultiple: false});
-  $('#u2f-error').modal({allowMultiple: false});
-  $('#register-security-key').on('click', (e) => {
-    e.preventDefault();
-    u2fApi.ensureSupport()
-      .then(u2fRegisterRequest)
-      .catch(() => {
-        u2fError('browser');
-      });
-  });
-}
-
-function u2fRegisterRequest() {
-  $.post(`${AppSubUrl}/user/settings/security/u2f/request_register`, {
-    _csrf: csrf,
-    name: $('#nickname').val()
-  }).done((req) => {
-    $('#nickname').closest('div.field').removeClass('error');
-    $('#register-device').modal('show');
-    if (req.registeredKeys === null) {
-      req.registeredKeys = [];
-    }
-    u2fApi.register(req.appId, req.registerRequests, req.registeredKeys, 30)
-      .then(u2fRegistered)
-      .catch((reason) => {
-        if (reason === undefined) {
-          u2fError(1);
-          return;
-        }
-        u2fError(reason.metaData.code);
-      });
-  }).fail((xhr) => {
-    if (xhr.status === 409) {
-      $('#nickname').closest('div.field').addClass('error');
-    }
-  });
-}
-
 function initWipTitle() {
   $('.title_wip_desc > a').on('click', (e) => {
     e.preventDefault();
@@ -2459,8 +2378,9 @@ $(document).ready(async () => {
   initCtrlEnterSubmit();
   initNavbarContentToggle();
   initTopicbar();
-  initU2FAuth();
-  initU2FRegister();
+  initWebAuthnAuth();
+  initWebAuthnRegister();
+  initWebAuthnPasswordless();
   initIssueList();
   initWipTitle();
   initPullRequestReview();